@@ -17,50 +17,201 @@ limitations under the License.
 package main
 
 import (
-	"log"
+	"flag"
+	"fmt"
+	"os"
 	"time"
 
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/runtime/signals"
 
 	"github.com/crossplaneio/crossplane/pkg/apis"
 	"github.com/crossplaneio/crossplane/pkg/controller"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/health"
+	"github.com/crossplaneio/crossplane/pkg/leader"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
+	"github.com/crossplaneio/crossplane/pkg/selection"
+	"github.com/crossplaneio/crossplane/pkg/tracing"
+	"github.com/crossplaneio/crossplane/pkg/webhook"
 )
 
 func main() {
+	logLevel := flag.String("log-level", "info", "Lowest level of the messages that are logged, i.e. one of debug, info, warn or error")
+	traceExporter := flag.String("trace-exporter", tracing.ExporterNone, "Exporter of reconcile traces, i.e. jaeger, or empty to disable tracing")
+	traceEndpoint := flag.String("trace-endpoint", "http://localhost:14268/api/traces", "HTTP endpoint of the Jaeger or OpenTelemetry collector traces are exported to")
+	traceSampleRate := flag.Float64("trace-sample-rate", 1, "Fraction of reconciles that are traced, between 0 and 1")
+	configPath := flag.String("config", "", "Path of a YAML file that selects the cloud providers and kinds of resources to manage")
+	providers := flag.String("providers", "", "Comma separated cloud providers to manage, i.e. aws, azure or gcp, overriding the config file; all if empty")
+	kinds := flag.String("kinds", "", "Comma separated kinds of resources to manage, i.e. cache, compute, database or storage, overriding the config file; all if empty")
+	metricsAddr := flag.String("metrics-addr", ":8080", "Address the Prometheus metrics endpoint binds to")
+	healthAddr := flag.String("health-addr", ":8081", "Address the liveness and readiness probes bind to")
+	syncPeriod := flag.Duration("sync-period", time.Minute, "Interval at which every resource is reconciled, even if it has not changed")
+	leaderElection := flag.Bool("leader-election", false, "Elect a leader among the replicas of Crossplane, so that only the leader runs the controllers")
+	leaderElectionNamespace := flag.String("leader-election-namespace", os.Getenv("POD_NAMESPACE"), "Namespace of the config map that holds the leader election lock")
+	shutdownGracePeriod := flag.Duration("shutdown-grace-period", 30*time.Second, "How long to wait for reconciles in progress to finish when shutting down")
+	enableWebhooks := flag.Bool("enable-webhooks", false, "Serve the admission webhooks that validate and default resource classes, claims and managed resources; requires --webhook-namespace or the POD_NAMESPACE environment variable")
+	webhookPort := flag.Int("webhook-port", 9443, "Port the admission webhook server listens on")
+	webhookCertDir := flag.String("webhook-cert-dir", "/tmp/crossplane-webhook-certs", "Directory the admission webhook serving certificate is written to")
+	webhookNamespace := flag.String("webhook-namespace", os.Getenv("POD_NAMESPACE"), "Namespace of the admission webhook service and secret")
+	webhookSelector := flag.String("webhook-service-selector", "app=crossplane", "Labels of the pods backing the admission webhook service, e.g. app=crossplane")
+	flag.DurationVar(&requeue.Defaults.BackoffBase, "requeue-backoff-base", requeue.Defaults.BackoffBase, "Delay before retrying a failed reconcile, doubled with each consecutive failure")
+	flag.DurationVar(&requeue.Defaults.BackoffMax, "requeue-backoff-max", requeue.Defaults.BackoffMax, "Longest delay between retries of a failed reconcile")
+	flag.DurationVar(&requeue.Defaults.PollInterval, "requeue-poll-interval", requeue.Defaults.PollInterval, "Interval at which resources are polled while they are being created")
+	flag.BoolVar(&core.DryRun, "dry-run", core.DryRun, "Record the changes that would be made to external resources in the events and conditions of their managed resources, without making them")
+	flag.Parse()
+
+	// Setup the logger shared by Crossplane and controller-runtime
+	l, err := logging.New(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	logging.SetLogger(l)
+	log := logging.Log.WithName("setup")
+
+	// Setup the exporter of reconcile traces
+	stopTracing, err := tracing.Setup(tracing.Options{Exporter: *traceExporter, Endpoint: *traceEndpoint, SampleRate: *traceSampleRate})
+	if err != nil {
+		fatal(log, err, "cannot setup tracing")
+	}
+	defer stopTracing()
+
+	// Select the cloud providers and kinds of resources to manage
+	sel := selection.Selection{}
+	if *configPath != "" {
+		if sel, err = selection.Load(*configPath); err != nil {
+			fatal(log, err, "cannot load config")
+		}
+	}
+	if p := selection.Split(*providers); p != nil {
+		sel.Providers = p
+	}
+	if k := selection.Split(*kinds); k != nil {
+		sel.Kinds = k
+	}
+	if err := sel.Validate(); err != nil {
+		fatal(log, err, "invalid selection")
+	}
+
 	// Get a config to talk to the apiserver
 	cfg, err := config.GetConfig()
 	if err != nil {
-		log.Fatal(err)
+		fatal(log, err, "cannot get config")
 	}
 
-	// Re-sync resources every minutes
-	// TODO: 1 minute could be too aggressive - we will update this in the future.
-	syncPeriod := 1 * time.Minute
-
 	// Create a new Cmd to provide shared dependencies and start components
-	mgr, err := manager.New(cfg, manager.Options{SyncPeriod: &syncPeriod})
+	mgr, err := manager.New(cfg, manager.Options{SyncPeriod: syncPeriod, MetricsBindAddress: *metricsAddr})
 	if err != nil {
-		log.Fatal(err)
+		fatal(log, err, "cannot create manager")
 	}
 
-	log.Printf("Adding schemes")
+	kube, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		fatal(log, err, "cannot create kubernetes client")
+	}
 
-	// Setup Scheme for all resources
-	if err := apis.AddToScheme(mgr.GetScheme()); err != nil {
-		log.Fatal(err)
+	log.Info("adding schemes", "selection", sel.String())
+
+	// Setup Scheme for the selected resources
+	if err := apis.AddToSchemeFor(mgr.GetScheme(), sel); err != nil {
+		fatal(log, err, "cannot add schemes")
+	}
+
+	// Fail fast rather than start controllers that cannot watch their resources
+	if err := selection.VerifyCRDs(kube.Discovery(), mgr.GetScheme()); err != nil {
+		fatal(log, err, "cannot verify CustomResourceDefinitions")
+	}
+
+	log.Info("adding controllers")
+
+	// Setup the selected Controllers
+	if err := controller.AddToManager(mgr, sel); err != nil {
+		fatal(log, err, "cannot add controllers")
 	}
 
-	log.Printf("Adding controllers")
+	// Report the number of claims and resources in each binding phase
+	if err := metrics.AddToManager(mgr); err != nil {
+		fatal(log, err, "cannot add metrics")
+	}
 
-	// Setup all Controllers
-	if err := controller.AddToManager(mgr); err != nil {
-		log.Fatal(err)
+	if *enableWebhooks {
+		log.Info("adding webhooks")
+
+		// The webhook service and serving certificate secret are created in
+		// this namespace, so the webhooks cannot be served without it.
+		if *webhookNamespace == "" {
+			fatal(log, errors.New("webhook namespace is empty; set --webhook-namespace or the POD_NAMESPACE environment variable"), "cannot add webhooks")
+		}
+
+		selector, err := labels.ConvertSelectorToLabelsMap(*webhookSelector)
+		if err != nil {
+			fatal(log, err, "cannot parse webhook service selector")
+		}
+
+		// Setup the admission webhook server
+		if err := webhook.AddToManager(mgr, webhook.Options{
+			Port:      int32(*webhookPort),
+			CertDir:   *webhookCertDir,
+			Namespace: *webhookNamespace,
+			Selector:  selector,
+		}); err != nil {
+			fatal(log, err, "cannot add webhooks")
+		}
 	}
 
-	log.Printf("Starting the manager")
+	elector := leader.NewElector(kube, mgr.GetRecorder("crossplane-leader-election"), leader.Options{
+		Enabled:      *leaderElection,
+		Namespace:    *leaderElectionNamespace,
+		ID:           "crossplane-leader-election",
+		PodNamespace: os.Getenv("POD_NAMESPACE"),
+		PodName:      os.Getenv("POD_NAME"),
+	})
+
+	// Standby replicas are ready to take over; the leader is ready once its
+	// caches have synced
+	synced := &health.CacheSync{}
+	if err := mgr.Add(synced); err != nil {
+		fatal(log, err, "cannot add cache sync check")
+	}
+	checker := health.NewChecker()
+	checker.AddLivenessCheck("ping", health.Ping)
+	checker.AddReadinessCheck("cache", health.Standby(elector.Leading, synced.Check))
+	checker.AddReadinessCheck("providers", health.Standby(elector.Leading, health.Providers(mgr.GetClient())))
+
+	stop := signals.SetupSignalHandler()
+	go func() {
+		if err := checker.Serve(*healthAddr, stop); err != nil {
+			fatal(log, err, "cannot serve health probes")
+		}
+	}()
+
+	log.Info("starting the manager")
+
+	// Start the Cmd once this replica is elected leader
+	err = elector.Run(stop, mgr.Start)
+
+	// Let the reconciles in progress record the state of the cloud operations
+	// they started before exiting
+	if !metrics.WaitForReconciles(*shutdownGracePeriod) {
+		log.Info("reconciles still in progress at shutdown", "gracePeriod", shutdownGracePeriod.String())
+	}
+	if err != nil {
+		stopTracing()
+		fatal(log, err, "cannot run manager")
+	}
+	log.Info("stopped")
+}
 
-	// Start the Cmd
-	log.Fatal(mgr.Start(signals.SetupSignalHandler()))
+// fatal logs the supplied error and exits.
+func fatal(log logr.Logger, err error, msg string) {
+	log.Error(err, msg)
+	os.Exit(1)
 }
@@ -24,12 +24,26 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws"
+	awscachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	awsdatabasev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	awsstoragev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
+	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/azure"
+	azurecachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
+	azurecomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
+	azuredatabasev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/cache"
 	"github.com/crossplaneio/crossplane/pkg/apis/compute"
 	"github.com/crossplaneio/crossplane/pkg/apis/core"
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp"
+	gcpcachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
+	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	gcpdatabasev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/storage"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
@@ -52,3 +66,42 @@ var AddToSchemes runtime.SchemeBuilder
 func AddToScheme(s *runtime.Scheme) error {
 	return AddToSchemes.AddToScheme(s)
 }
+
+// Groups are the API groups of each cloud provider and kind of resource. The
+// API groups of resource claims, which are portable across providers, belong
+// to a kind of resource but to no provider.
+var Groups = []selection.Group{
+	{Provider: selection.ProviderAWS, AddToScheme: awsv1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAWS, Kind: selection.KindCache, AddToScheme: awscachev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAWS, Kind: selection.KindCompute, AddToScheme: awscomputev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAWS, Kind: selection.KindDatabase, AddToScheme: awsdatabasev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAWS, Kind: selection.KindStorage, AddToScheme: awsstoragev1alpha1.SchemeBuilder.AddToScheme},
+
+	{Provider: selection.ProviderAzure, AddToScheme: azurev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAzure, Kind: selection.KindCache, AddToScheme: azurecachev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAzure, Kind: selection.KindCompute, AddToScheme: azurecomputev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderAzure, Kind: selection.KindDatabase, AddToScheme: azuredatabasev1alpha1.SchemeBuilder.AddToScheme},
+
+	{Provider: selection.ProviderGCP, AddToScheme: gcpv1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderGCP, Kind: selection.KindCache, AddToScheme: gcpcachev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderGCP, Kind: selection.KindCompute, AddToScheme: gcpcomputev1alpha1.SchemeBuilder.AddToScheme},
+	{Provider: selection.ProviderGCP, Kind: selection.KindDatabase, AddToScheme: gcpdatabasev1alpha1.SchemeBuilder.AddToScheme},
+
+	{Kind: selection.KindCache, AddToScheme: cache.AddToScheme},
+	{Kind: selection.KindCompute, AddToScheme: compute.AddToScheme},
+	{AddToScheme: core.AddToScheme},
+}
+
+// AddToSchemeFor adds the resources of the selected cloud providers and kinds
+// of resources to the Scheme.
+func AddToSchemeFor(s *runtime.Scheme, sel selection.Selection) error {
+	if err := sel.AddToScheme(s, Groups); err != nil {
+		return err
+	}
+
+	// Database and storage claims share an API group.
+	if sel.Selected("", selection.KindDatabase) || sel.Selected("", selection.KindStorage) {
+		return storage.AddToScheme(s)
+	}
+	return nil
+}
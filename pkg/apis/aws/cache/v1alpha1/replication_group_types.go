@@ -278,6 +278,10 @@ type ReplicationGroupStatus struct {
 	// Groupname of the Replication Group.
 	GroupName string `json:"groupName,omitempty"`
 
+	// FinalSnapshot is the name of the snapshot taken before the Replication
+	// Group was deleted under the Snapshot reclaim policy.
+	FinalSnapshot string `json:"finalSnapshot,omitempty"`
+
 	// TODO(negz): Support PendingModifiedValues?
 	// https://docs.aws.amazon.com/AmazonElastiCache/latest/APIReference/API_ReplicationGroupPendingModifiedValues.html
 }
@@ -398,6 +402,11 @@ func (c *ReplicationGroup) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(c.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this replication group.
+func (c *ReplicationGroup) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &c.Status.ConditionedStatus
+}
+
 // IsAvailable for usage/binding
 func (c *ReplicationGroup) IsAvailable() bool {
 	return c.Status.State == StatusAvailable
@@ -416,3 +425,38 @@ func (c *ReplicationGroup) SetBound(state bool) {
 		c.Status.Phase = corev1alpha1.BindingStateUnbound
 	}
 }
+
+// IsReleased returns true if this resource was released by its resource claim.
+func (c *ReplicationGroup) IsReleased() bool {
+	return c.Status.IsReleased()
+}
+
+// SetReleased marks this resource as released by its resource claim.
+func (c *ReplicationGroup) SetReleased() {
+	c.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this resource is, or was last, bound to.
+func (c *ReplicationGroup) ClaimRef() *v1.ObjectReference {
+	return c.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this resource is bound to.
+func (c *ReplicationGroup) SetClaimRef(ref *v1.ObjectReference) {
+	c.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this resource.
+func (c *ReplicationGroup) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return c.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this resource.
+func (c *ReplicationGroup) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      c.Status.State,
+		Endpoint:   c.Status.Endpoint,
+		ProviderID: c.Status.ProviderID,
+		Message:    c.Status.FailureMessage(),
+	}
+}
@@ -33,10 +33,8 @@ const (
 	// The resource is inaccessible while it is being created.
 	ClusterStatusCreating = "CREATING"
 	ClusterStatusActive   = "ACTIVE"
-
-	// TODO: Deleting and Failed currently not used. Implement usage or remove
-	// ClusterStatusDeleting = "DELETING"
-	// ClusterStatusFailed = "FAILED"
+	ClusterStatusDeleting = "DELETING"
+	ClusterStatusFailed   = "FAILED"
 )
 
 // EKSRegion represents an EKS enabled AWS region.
@@ -232,6 +230,8 @@ type EKSClusterStatus struct {
 	Endpoint string `json:"endpoint,omitempty"`
 	// CloudFormationStackID Stack-id
 	CloudFormationStackID string `json:"cloudformationStackId,omitempty"`
+	// ProviderID is the external ID to identify this resource in the cloud provider
+	ProviderID string `json:"providerID,omitempty"`
 
 	ConnectionSecretRef corev1.LocalObjectReference `json:"connectionSecretRef,omitempty"`
 }
@@ -351,6 +351,11 @@ func (e *EKSCluster) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(e.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this cluster.
+func (e *EKSCluster) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &e.Status.ConditionedStatus
+}
+
 // State returns rds resource state value saved in the status (could be empty)
 func (e *EKSCluster) State() string {
 	return e.Status.State
@@ -376,6 +381,41 @@ func (e *EKSCluster) SetBound(bound bool) {
 	e.Status.SetBound(bound)
 }
 
+// IsReleased returns true if this cluster was released by its resource claim.
+func (e *EKSCluster) IsReleased() bool {
+	return e.Status.IsReleased()
+}
+
+// SetReleased marks this cluster as released by its resource claim.
+func (e *EKSCluster) SetReleased() {
+	e.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this cluster is, or was last, bound to.
+func (e *EKSCluster) ClaimRef() *corev1.ObjectReference {
+	return e.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this cluster is bound to.
+func (e *EKSCluster) SetClaimRef(ref *corev1.ObjectReference) {
+	e.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this cluster.
+func (e *EKSCluster) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return e.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this cluster.
+func (e *EKSCluster) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      e.Status.State,
+		Endpoint:   e.Status.Endpoint,
+		ProviderID: e.Status.ProviderID,
+		Message:    e.Status.FailureMessage(),
+	}
+}
+
 // GetRegionAMI returns the default ami id for a given EKS region
 func GetRegionAMI(region EKSRegion) (string, error) {
 	if val, ok := workerNodeRegionAMI[region]; ok {
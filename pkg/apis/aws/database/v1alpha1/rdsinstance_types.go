@@ -53,6 +53,12 @@ type RDSInstanceSpec struct {
 	//	  that are expected to connect to the database.
 	SecurityGroups []string `json:"securityGroups,omitempty"`
 
+	// ApplyModificationsImmediately specifies whether changes to the class,
+	// size, engine version or security groups of an existing instance are
+	// applied immediately, or during the next maintenance window of the
+	// instance. Applying modifications may cause an outage of the instance.
+	ApplyModificationsImmediately bool `json:"applyModificationsImmediately,omitempty"`
+
 	// Kubernetes object references
 	ClaimRef            *corev1.ObjectReference      `json:"claimRef,omitempty"`
 	ClassRef            *corev1.ObjectReference      `json:"classRef,omitempty"`
@@ -87,6 +93,22 @@ type RDSInstanceStatus struct {
 	ProviderID   string `json:"providerID,omitempty"`   // the external ID to identify this resource in the cloud provider
 	InstanceName string `json:"instanceName,omitempty"` // the generated DB Instance name
 	Endpoint     string `json:"endpoint,omitempty"`     // rds instance endpoint
+
+	// FinalSnapshot is the identifier of the DB snapshot taken before the
+	// instance was deleted under the Snapshot reclaim policy.
+	FinalSnapshot string `json:"finalSnapshot,omitempty"`
+
+	// PendingModifications of the instance that will be applied during its
+	// next maintenance window.
+	PendingModifications *RDSInstanceModifications `json:"pendingModifications,omitempty"`
+}
+
+// RDSInstanceModifications are modifications of an RDS instance that have not
+// been applied yet. Empty fields are not modified.
+type RDSInstanceModifications struct {
+	Class         string `json:"class,omitempty"`
+	Size          int64  `json:"size,omitempty"`
+	EngineVersion string `json:"engineVersion,omitempty"`
 }
 
 // +genclient
@@ -154,6 +176,13 @@ func NewRDSInstanceSpec(properties map[string]string) *RDSInstanceSpec {
 		spec.SubnetGroupName = val
 	}
 
+	val, ok = properties["applyModificationsImmediately"]
+	if ok {
+		if immediately, err := strconv.ParseBool(val); err == nil {
+			spec.ApplyModificationsImmediately = immediately
+		}
+	}
+
 	return spec
 }
 
@@ -180,6 +209,11 @@ func (r *RDSInstance) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(r.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this instance.
+func (r *RDSInstance) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &r.Status.ConditionedStatus
+}
+
 // State returns rds instance state value saved in the status (could be empty)
 func (r *RDSInstance) State() string {
 	return r.Status.State
@@ -204,3 +238,43 @@ func (r *RDSInstance) IsBound() bool {
 func (r *RDSInstance) SetBound(bound bool) {
 	r.Status.SetBound(bound)
 }
+
+// IsReleased returns true if this instance was released by its resource claim.
+func (r *RDSInstance) IsReleased() bool {
+	return r.Status.IsReleased()
+}
+
+// SetReleased marks this instance as released by its resource claim.
+func (r *RDSInstance) SetReleased() {
+	r.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this instance is, or was last, bound to.
+func (r *RDSInstance) ClaimRef() *corev1.ObjectReference {
+	return r.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this instance is bound to.
+func (r *RDSInstance) SetClaimRef(ref *corev1.ObjectReference) {
+	r.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this instance.
+func (r *RDSInstance) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return r.Spec.ReclaimPolicy
+}
+
+// StorageGB returns the storage provisioned for this instance, in GB
+func (r *RDSInstance) StorageGB() int64 {
+	return r.Spec.Size
+}
+
+// StatusSummary returns a summary of the observed state of this instance.
+func (r *RDSInstance) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      r.Status.State,
+		Endpoint:   r.Status.Endpoint,
+		ProviderID: r.Status.ProviderID,
+		Message:    r.Status.FailureMessage(),
+	}
+}
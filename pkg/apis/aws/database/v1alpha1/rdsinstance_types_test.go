@@ -125,6 +125,11 @@ func TestNewRDSInstanceSpec(t *testing.T) {
 	m["subnetGroupName"] = val
 	exp.SubnetGroupName = val
 	g.Expect(NewRDSInstanceSpec(m)).To(Equal(exp))
+
+	val = "true"
+	m["applyModificationsImmediately"] = val
+	exp.ApplyModificationsImmediately = true
+	g.Expect(NewRDSInstanceSpec(m)).To(Equal(exp))
 }
 
 func TestIsAvailable(t *testing.T) {
@@ -85,6 +85,22 @@ func (in *RDSInstanceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RDSInstanceModifications) DeepCopyInto(out *RDSInstanceModifications) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RDSInstanceModifications.
+func (in *RDSInstanceModifications) DeepCopy() *RDSInstanceModifications {
+	if in == nil {
+		return nil
+	}
+	out := new(RDSInstanceModifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RDSInstanceSpec) DeepCopyInto(out *RDSInstanceSpec) {
 	*out = *in
@@ -127,6 +143,11 @@ func (in *RDSInstanceStatus) DeepCopyInto(out *RDSInstanceStatus) {
 	*out = *in
 	in.ConditionedStatus.DeepCopyInto(&out.ConditionedStatus)
 	out.BindingStatusPhase = in.BindingStatusPhase
+	if in.PendingModifications != nil {
+		in, out := &in.PendingModifications, &out.PendingModifications
+		*out = new(RDSInstanceModifications)
+		**out = **in
+	}
 	return
 }
 
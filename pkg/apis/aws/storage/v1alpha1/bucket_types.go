@@ -181,6 +181,11 @@ func (b *S3Bucket) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(b.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this bucket.
+func (b *S3Bucket) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &b.Status.ConditionedStatus
+}
+
 // Endpoint returns the endpoint for the bucket
 func (b *S3Bucket) Endpoint() string {
 	return fmt.Sprintf("https://s3-%s.amazonaws.com", b.Spec.Region)
@@ -200,3 +205,37 @@ func (b *S3Bucket) IsBound() bool {
 func (b *S3Bucket) SetBound(bound bool) {
 	b.Status.SetBound(bound)
 }
+
+// IsReleased returns true if this bucket was released by its resource claim.
+func (b *S3Bucket) IsReleased() bool {
+	return b.Status.IsReleased()
+}
+
+// SetReleased marks this bucket as released by its resource claim.
+func (b *S3Bucket) SetReleased() {
+	b.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this bucket is, or was last, bound to.
+func (b *S3Bucket) ClaimRef() *v1.ObjectReference {
+	return b.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this bucket is bound to.
+func (b *S3Bucket) SetClaimRef(ref *v1.ObjectReference) {
+	b.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this bucket.
+func (b *S3Bucket) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return b.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this bucket.
+func (b *S3Bucket) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		Endpoint:   b.Endpoint(),
+		ProviderID: b.Status.ProviderID,
+		Message:    b.Status.FailureMessage(),
+	}
+}
@@ -248,6 +248,11 @@ func (c *Redis) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(c.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this cache.
+func (c *Redis) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &c.Status.ConditionedStatus
+}
+
 // IsAvailable for usage/binding
 func (c *Redis) IsAvailable() bool {
 	return c.Status.State == ProvisioningStateSucceeded
@@ -266,3 +271,38 @@ func (c *Redis) SetBound(state bool) {
 		c.Status.Phase = corev1alpha1.BindingStateUnbound
 	}
 }
+
+// IsReleased returns true if this resource was released by its resource claim.
+func (c *Redis) IsReleased() bool {
+	return c.Status.IsReleased()
+}
+
+// SetReleased marks this resource as released by its resource claim.
+func (c *Redis) SetReleased() {
+	c.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this resource is, or was last, bound to.
+func (c *Redis) ClaimRef() *v1.ObjectReference {
+	return c.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this resource is bound to.
+func (c *Redis) SetClaimRef(ref *v1.ObjectReference) {
+	c.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this resource.
+func (c *Redis) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return c.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this resource.
+func (c *Redis) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      c.Status.State,
+		Endpoint:   c.Status.Endpoint,
+		ProviderID: c.Status.ProviderID,
+		Message:    c.Status.FailureMessage(),
+	}
+}
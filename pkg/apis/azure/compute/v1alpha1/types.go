@@ -186,6 +186,11 @@ func (a *AKSCluster) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(a.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this cluster.
+func (a *AKSCluster) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &a.Status.ConditionedStatus
+}
+
 // ConnectionSecret returns a secret object for this resource
 func (a *AKSCluster) ConnectionSecret() *corev1.Secret {
 	return &corev1.Secret{
@@ -229,3 +234,38 @@ func (a *AKSCluster) IsBound() bool {
 func (a *AKSCluster) SetBound(bound bool) {
 	a.Status.SetBound(bound)
 }
+
+// IsReleased returns true if this cluster was released by its resource claim.
+func (a *AKSCluster) IsReleased() bool {
+	return a.Status.IsReleased()
+}
+
+// SetReleased marks this cluster as released by its resource claim.
+func (a *AKSCluster) SetReleased() {
+	a.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this cluster is, or was last, bound to.
+func (a *AKSCluster) ClaimRef() *corev1.ObjectReference {
+	return a.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this cluster is bound to.
+func (a *AKSCluster) SetClaimRef(ref *corev1.ObjectReference) {
+	a.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this cluster.
+func (a *AKSCluster) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return a.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this cluster.
+func (a *AKSCluster) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      a.Status.State,
+		Endpoint:   a.Status.Endpoint,
+		ProviderID: a.Status.ProviderID,
+		Message:    a.Status.FailureMessage(),
+	}
+}
@@ -33,6 +33,8 @@ const (
 	OperationCreateServer = "createServer"
 	// OperationCreateFirewallRules is the operation type for creating a firewall rule
 	OperationCreateFirewallRules = "createFirewallRules"
+	// OperationCreateFinalSnapshot is the operation type for restoring a final snapshot of a server before deletion
+	OperationCreateFinalSnapshot = "createFinalSnapshot"
 )
 
 // SQLServer represents a generic Azure SQL server.
@@ -142,6 +144,13 @@ type SQLServerStatus struct {
 
 	// RunningOperationType is the type of the currently running operation
 	RunningOperationType string `json:"runningOperationType,omitempty"`
+
+	// FinalSnapshot is the name of the SQL Server restored from this instance
+	// before it was deleted under the Snapshot reclaim policy. The restored
+	// server is a running, billed server, because the backups of an Azure
+	// server cannot be restored once it is deleted. It is never deleted by
+	// Crossplane.
+	FinalSnapshot string `json:"finalSnapshot,omitempty"`
 }
 
 // PricingTierSpec represents the performance and cost oriented properties of the server
@@ -231,6 +240,11 @@ func (m *MysqlServer) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(m.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this server.
+func (m *MysqlServer) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &m.Status.ConditionedStatus
+}
+
 // IsAvailable for usage/binding
 func (m *MysqlServer) IsAvailable() bool {
 	return m.Status.State == string(mysql.ServerStateReady)
@@ -246,6 +260,46 @@ func (m *MysqlServer) SetBound(bound bool) {
 	m.Status.SetBound(bound)
 }
 
+// IsReleased returns true if this server was released by its resource claim.
+func (m *MysqlServer) IsReleased() bool {
+	return m.Status.IsReleased()
+}
+
+// SetReleased marks this server as released by its resource claim.
+func (m *MysqlServer) SetReleased() {
+	m.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this server is, or was last, bound to.
+func (m *MysqlServer) ClaimRef() *v1.ObjectReference {
+	return m.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this server is bound to.
+func (m *MysqlServer) SetClaimRef(ref *v1.ObjectReference) {
+	m.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this server.
+func (m *MysqlServer) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return m.Spec.ReclaimPolicy
+}
+
+// StorageGB returns the storage provisioned for this server, in GB
+func (m *MysqlServer) StorageGB() int64 {
+	return int64(m.Spec.StorageProfile.StorageGB)
+}
+
+// StatusSummary returns a summary of the observed state of this server.
+func (m *MysqlServer) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      m.Status.State,
+		Endpoint:   m.Status.Endpoint,
+		ProviderID: m.Status.ProviderID,
+		Message:    m.Status.FailureMessage(),
+	}
+}
+
 // GetSpec gets the PostgreSQL server's spec.
 func (p *PostgresqlServer) GetSpec() *SQLServerSpec {
 	return &p.Spec
@@ -282,6 +336,11 @@ func (p *PostgresqlServer) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(p.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this server.
+func (p *PostgresqlServer) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &p.Status.ConditionedStatus
+}
+
 // IsAvailable for usage/binding
 func (p *PostgresqlServer) IsAvailable() bool {
 	return p.Status.State == string(postgresql.ServerStateReady)
@@ -297,6 +356,46 @@ func (p *PostgresqlServer) SetBound(bound bool) {
 	p.Status.SetBound(bound)
 }
 
+// IsReleased returns true if this server was released by its resource claim.
+func (p *PostgresqlServer) IsReleased() bool {
+	return p.Status.IsReleased()
+}
+
+// SetReleased marks this server as released by its resource claim.
+func (p *PostgresqlServer) SetReleased() {
+	p.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this server is, or was last, bound to.
+func (p *PostgresqlServer) ClaimRef() *v1.ObjectReference {
+	return p.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this server is bound to.
+func (p *PostgresqlServer) SetClaimRef(ref *v1.ObjectReference) {
+	p.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this server.
+func (p *PostgresqlServer) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return p.Spec.ReclaimPolicy
+}
+
+// StorageGB returns the storage provisioned for this server, in GB
+func (p *PostgresqlServer) StorageGB() int64 {
+	return int64(p.Spec.StorageProfile.StorageGB)
+}
+
+// StatusSummary returns a summary of the observed state of this server.
+func (p *PostgresqlServer) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      p.Status.State,
+		Endpoint:   p.Status.Endpoint,
+		ProviderID: p.Status.ProviderID,
+		Message:    p.Status.FailureMessage(),
+	}
+}
+
 // ValidMySQLVersionValues returns the valid set of engine version values.
 func ValidMySQLVersionValues() []string {
 	return []string{"5.6", "5.7"}
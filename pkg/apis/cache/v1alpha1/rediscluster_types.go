@@ -30,6 +30,10 @@ type RedisClusterSpec struct {
 	ResourceRef *corev1.ObjectReference `json:"resourceName,omitempty"`
 	Selector    metav1.LabelSelector    `json:"selector,omitempty"`
 
+	// SecretTemplate renames and derives keys of the claim's connection secret
+	// +optional
+	SecretTemplate *corev1alpha1.SecretTemplate `json:"secretTemplate,omitempty"`
+
 	// EngineVersion specifies the desired Redis version.
 	// +kubebuilder:validation:Enum=2.6,2.8,3.2,4.0,5.0
 	EngineVersion string `json:"engineVersion"`
@@ -44,6 +48,8 @@ type RedisClusterSpec struct {
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.bindingPhase"
 // +kubebuilder:printcolumn:name="CLASS",type="string",JSONPath=".spec.classReference.name"
 // +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.engineVersion"
+// +kubebuilder:printcolumn:name="RESOURCE-STATE",type="string",JSONPath=".status.resourceStatus.state"
+// +kubebuilder:printcolumn:name="ENDPOINT",type="string",JSONPath=".status.resourceStatus.endpoint"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type RedisCluster struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -82,6 +88,23 @@ func (c *RedisCluster) ClassRef() *corev1.ObjectReference {
 	return c.Spec.ClassRef
 }
 
+// SetClassRef specifies the resource class used by this resource claim.
+func (c *RedisCluster) SetClassRef(ref *corev1.ObjectReference) {
+	c.Spec.ClassRef = ref
+}
+
+// Selector returns the label selector used to match this claim to an existing
+// resource.
+func (c *RedisCluster) Selector() *metav1.LabelSelector {
+	return &c.Spec.Selector
+}
+
+// SecretTemplate returns the template applied to the connection secret of the
+// resource this claim is bound to when it is copied to the claim's secret.
+func (c *RedisCluster) SecretTemplate() *corev1alpha1.SecretTemplate {
+	return c.Spec.SecretTemplate
+}
+
 // ResourceRef returns the reference to the resource this claim is bound to.
 func (c *RedisCluster) ResourceRef() *corev1.ObjectReference {
 	return c.Spec.ResourceRef
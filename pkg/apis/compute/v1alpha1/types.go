@@ -31,6 +31,10 @@ type KubernetesClusterSpec struct {
 	ResourceRef *corev1.ObjectReference `json:"resourceName,omitempty"`
 	Selector    metav1.LabelSelector    `json:"selector,omitempty"`
 
+	// SecretTemplate renames and derives keys of the claim's connection secret
+	// +optional
+	SecretTemplate *corev1alpha1.SecretTemplate `json:"secretTemplate,omitempty"`
+
 	// cluster properties
 	ClusterVersion string `json:"clusterVersion,omitempty"`
 }
@@ -44,6 +48,8 @@ type KubernetesClusterSpec struct {
 // +kubebuilder:printcolumn:name="CLASS",type="string",JSONPath=".spec.classReference.name"
 // +kubebuilder:printcolumn:name="CLUSTER-CLASS",type="string",JSONPath=".spec.classReference.name"
 // +kubebuilder:printcolumn:name="CLUSTER-REF",type="string",JSONPath=".spec.resourceName.name"
+// +kubebuilder:printcolumn:name="RESOURCE-STATE",type="string",JSONPath=".status.resourceStatus.state"
+// +kubebuilder:printcolumn:name="ENDPOINT",type="string",JSONPath=".status.resourceStatus.endpoint"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type KubernetesCluster struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -94,6 +100,23 @@ func (kc *KubernetesCluster) ClassRef() *corev1.ObjectReference {
 	return kc.Spec.ClassRef
 }
 
+// SetClassRef specifies the resource class used by this resource claim.
+func (kc *KubernetesCluster) SetClassRef(ref *corev1.ObjectReference) {
+	kc.Spec.ClassRef = ref
+}
+
+// Selector returns the label selector used to match this Kubernetes cluster to
+// an existing resource.
+func (kc *KubernetesCluster) Selector() *metav1.LabelSelector {
+	return &kc.Spec.Selector
+}
+
+// SecretTemplate returns the template applied to the connection secret of the
+// resource this claim is bound to when it is copied to the claim's secret.
+func (kc *KubernetesCluster) SecretTemplate() *corev1alpha1.SecretTemplate {
+	return kc.Spec.SecretTemplate
+}
+
 // ResourceRef returns the resource claimed by this Kubernetes cluster.
 func (kc *KubernetesCluster) ResourceRef() *corev1.ObjectReference {
 	return kc.Spec.ResourceRef
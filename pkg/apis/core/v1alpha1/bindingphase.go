@@ -18,18 +18,44 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // BindingState is to identify the current binding status of given resources
 type BindingState int
 
 // MarshalJSON returns a JSON representation of a BindingState.
 func (s BindingState) MarshalJSON() ([]byte, error) {
-	return []byte(s.String()), nil
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON sets a BindingState from its JSON representation.
+func (s *BindingState) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	for state := BindingStateUnbound; state <= BindingStateReleased; state++ {
+		if state.String() == name {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown binding state %q", name)
 }
 
 // Binding states.
 const (
 	BindingStateUnbound BindingState = iota
 	BindingStateBound
+
+	// BindingStateReleased indicates a resource that was bound to a resource
+	// claim that has since been deleted. A released resource retains its
+	// reference to the deleted claim, and is not matched to new claims unless
+	// they explicitly reference it.
+	BindingStateReleased
 )
 
 // BindingStatus defines set of supported operations
@@ -57,3 +83,13 @@ func (b *BindingStatusPhase) SetBound(bound bool) {
 func (b *BindingStatusPhase) IsBound() bool {
 	return b.Phase == BindingStateBound
 }
+
+// SetReleased set binding status to Released
+func (b *BindingStatusPhase) SetReleased() {
+	b.Phase = BindingStateReleased
+}
+
+// IsReleased returns true if status is released
+func (b *BindingStatusPhase) IsReleased() bool {
+	return b.Phase == BindingStateReleased
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestBindingStateJSON(t *testing.T) {
+	cases := map[BindingState]string{
+		BindingStateUnbound:  `{}`,
+		BindingStateBound:    `{"bindingPhase":"Bound"}`,
+		BindingStateReleased: `{"bindingPhase":"Released"}`,
+	}
+
+	for state, want := range cases {
+		t.Run(state.String(), func(t *testing.T) {
+			g := NewGomegaWithT(t)
+
+			b, err := json.Marshal(BindingStatusPhase{Phase: state})
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(string(b)).To(Equal(want))
+
+			got := BindingStatusPhase{}
+			g.Expect(json.Unmarshal(b, &got)).To(Succeed())
+			g.Expect(got.Phase).To(Equal(state))
+		})
+	}
+}
+
+func TestBindingStateUnmarshalJSON(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	got := BindingStatusPhase{}
+	g.Expect(json.Unmarshal([]byte(`{"bindingPhase":"Unbound"}`), &got)).To(Succeed())
+	g.Expect(got.Phase).To(Equal(BindingStateUnbound))
+	g.Expect(json.Unmarshal([]byte(`{"bindingPhase":"Lost"}`), &got)).NotTo(Succeed())
+	g.Expect(json.Unmarshal([]byte(`{"bindingPhase":1}`), &got)).NotTo(Succeed())
+}
@@ -4,9 +4,9 @@ package v1alpha1
 
 import "strconv"
 
-const _BindingState_name = "UnboundBound"
+const _BindingState_name = "UnboundBoundReleased"
 
-var _BindingState_index = [...]uint8{0, 7, 12}
+var _BindingState_index = [...]uint8{0, 7, 12, 20}
 
 func (i BindingState) String() string {
 	if i < 0 || i >= BindingState(len(_BindingState_index)-1) {
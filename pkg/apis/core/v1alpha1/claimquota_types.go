@@ -0,0 +1,90 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClaimQuotaSpec defines the limits on the resource claims of a namespace
+type ClaimQuotaSpec struct {
+	// Claims limits the number of provisioned claims of each kind, keyed by
+	// claim kind, e.g. "mysqlinstance.storage.crossplane.io/v1alpha1"
+	// +optional
+	Claims map[string]int `json:"claims,omitempty"`
+
+	// Classes limits the number of provisioned claims that use each resource
+	// class, keyed by the namespace and name of the class, e.g. "system/standard-mysql"
+	// +optional
+	Classes map[string]int `json:"classes,omitempty"`
+
+	// StorageGB limits the aggregate storage, in GB, of the resources bound to
+	// the claims of the namespace
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StorageGB *int64 `json:"storageGB,omitempty"`
+}
+
+// ClaimQuotaUsage is the observed usage of the limits of a claim quota
+type ClaimQuotaUsage struct {
+	// Claims is the number of provisioned claims of each kind
+	Claims map[string]int `json:"claims,omitempty"`
+
+	// Classes is the number of provisioned claims that use each resource class
+	Classes map[string]int `json:"classes,omitempty"`
+
+	// StorageGB is the aggregate storage, in GB, of the resources bound to the
+	// claims of the namespace
+	StorageGB int64 `json:"storageGB,omitempty"`
+}
+
+// ClaimQuotaStatus is the observed state of a claim quota
+type ClaimQuotaStatus struct {
+	// Used is the usage of the limits of the quota, as observed when a claim
+	// of the namespace was last provisioned
+	Used ClaimQuotaUsage `json:"used,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClaimQuota limits the number of resource claims, and the aggregate storage
+// of their resources, that may be provisioned in its namespace.
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="STORAGE-GB",type="integer",JSONPath=".spec.storageGB"
+// +kubebuilder:printcolumn:name="USED-STORAGE-GB",type="integer",JSONPath=".status.used.storageGB"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+type ClaimQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClaimQuotaSpec   `json:"spec,omitempty"`
+	Status ClaimQuotaStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClaimQuotaList contains a list of ClaimQuota
+type ClaimQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClaimQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClaimQuota{}, &ClaimQuotaList{})
+}
@@ -33,6 +33,11 @@ const (
 	Creating ConditionType = "Creating"
 	// Deleting means that the resource is in the process of being deleted.
 	Deleting ConditionType = "Deleting"
+	// Updating means that the resource is in the process of being updated to match its spec.
+	Updating ConditionType = "Updating"
+	// DryRun means that the resource is reconciled in dry-run mode, and that its external resource
+	// would be changed as described by the reason and message of the condition.
+	DryRun ConditionType = "DryRun"
 	// Failed means that the resource is in a failure state, for example it failed to be created.
 	Failed ConditionType = "Failed"
 	// Ready means that the resource creation has been successful and the resource is ready to
@@ -105,6 +110,15 @@ func (c *ConditionedStatus) IsFailed() bool {
 	return c.IsCondition(Failed)
 }
 
+// FailureMessage returns the message of the failed condition if the status is
+// currently failed, or an empty string otherwise.
+func (c *ConditionedStatus) FailureMessage() string {
+	if !c.IsFailed() {
+		return ""
+	}
+	return c.Condition(Failed).Message
+}
+
 // SetCondition adds/replaces the given condition in the credentials controller status.
 func (c *ConditionedStatus) SetCondition(condition Condition) {
 	current := c.Condition(condition.Type)
@@ -136,6 +150,11 @@ func (c *ConditionedStatus) SetPending() {
 	c.SetCondition(NewCondition(Pending, "", ""))
 }
 
+// SetUpdating set updating as an active condition
+func (c *ConditionedStatus) SetUpdating() {
+	c.SetCondition(NewCondition(Updating, "", ""))
+}
+
 // SetDeleting set creating as an active condition
 func (c *ConditionedStatus) SetDeleting() {
 	c.SetCondition(NewCondition(Deleting, "", ""))
@@ -41,3 +41,16 @@ func TestConditionedStatus_UnsetAllConditions(t *testing.T) {
 	g.Expect(cs.IsFailed()).To(BeFalse())
 	g.Expect(cs.IsReady()).To(BeTrue())
 }
+
+func TestConditionedStatus_FailureMessage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cs := &ConditionedStatus{}
+	g.Expect(cs.FailureMessage()).To(BeEmpty())
+
+	cs.SetFailed("foo", "bar")
+	g.Expect(cs.FailureMessage()).To(Equal("bar"))
+
+	cs.UnsetAllConditions()
+	g.Expect(cs.FailureMessage()).To(BeEmpty())
+}
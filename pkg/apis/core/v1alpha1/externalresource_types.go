@@ -0,0 +1,210 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplaneio/crossplane/pkg/util"
+)
+
+// ExternalProvisioner configures an out-of-process provisioner that speaks
+// the external provisioner protocol
+type ExternalProvisioner struct {
+	// URL of the external provisioner, e.g.
+	// "https://kafka-provisioner.infra.svc:8443". Protocol requests are POSTed
+	// to paths below this URL.
+	URL string `json:"url"`
+
+	// CABundle is a PEM encoded CA bundle used to verify the serving
+	// certificate of the external provisioner. The system roots are used if
+	// it is empty.
+	// +optional
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// TimeoutSeconds limits the duration of each protocol request. Defaults to
+	// 30 seconds.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// ExternalResourceSpec defines the desired state of ExternalResource
+type ExternalResourceSpec struct {
+	// Provisioner of the resource class this resource was provisioned from,
+	// e.g. "kafkatopic.messaging.example.org/v1"
+	Provisioner string `json:"provisioner"`
+
+	// ExternalProvisioner that provisions and deletes this resource
+	ExternalProvisioner ExternalProvisioner `json:"externalProvisioner"`
+
+	// Parameters of the resource class this resource was provisioned from
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Kubernetes object references
+	ClaimRef            *corev1.ObjectReference      `json:"claimRef,omitempty"`
+	ClassRef            *corev1.ObjectReference      `json:"classRef,omitempty"`
+	ConnectionSecretRef *corev1.LocalObjectReference `json:"connectionSecretRef,omitempty"`
+
+	// ReclaimPolicy identifies how to handle the external resource after the deletion of this type
+	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+}
+
+// External resource states reported by external provisioners.
+const (
+	// The resource is being created
+	ExternalResourceStateCreating = "creating"
+	// The resource is ready to be bound and used
+	ExternalResourceStateAvailable = "available"
+	// The resource has failed
+	ExternalResourceStateFailed = "failed"
+)
+
+// ExternalResourceStatus defines the observed state of ExternalResource
+type ExternalResourceStatus struct {
+	ConditionedStatus
+	BindingStatusPhase
+
+	// State of the resource as reported by its external provisioner
+	State string `json:"state,omitempty"`
+
+	// Message describes the state of the resource, as reported by its
+	// external provisioner
+	Message string `json:"message,omitempty"`
+
+	// ProviderID is the ID that identifies the resource in its external
+	// provisioner
+	ProviderID string `json:"providerID,omitempty"`
+
+	// Endpoint used to connect to the resource
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalResource is a resource provisioned by an out-of-process provisioner
+// through the external provisioner protocol.
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="PROVISIONER",type="string",JSONPath=".spec.provisioner"
+// +kubebuilder:printcolumn:name="CLASS",type="string",JSONPath=".spec.classRef.name"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+type ExternalResource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExternalResourceSpec   `json:"spec,omitempty"`
+	Status ExternalResourceStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExternalResourceList contains a list of ExternalResource
+type ExternalResourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ExternalResource `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExternalResource{}, &ExternalResourceList{})
+}
+
+// ConnectionSecretName returns a secret name from the reference
+func (r *ExternalResource) ConnectionSecretName() string {
+	if r.Spec.ConnectionSecretRef == nil {
+		r.Spec.ConnectionSecretRef = &corev1.LocalObjectReference{
+			Name: r.Name,
+		}
+	} else if r.Spec.ConnectionSecretRef.Name == "" {
+		r.Spec.ConnectionSecretRef.Name = r.Name
+	}
+
+	return r.Spec.ConnectionSecretRef.Name
+}
+
+// ObjectReference to this ExternalResource
+func (r *ExternalResource) ObjectReference() *corev1.ObjectReference {
+	return util.ObjectReference(r.ObjectMeta, util.IfEmptyString(r.APIVersion, APIVersion), util.IfEmptyString(r.Kind, ExternalResourceKind))
+}
+
+// OwnerReference to use this resource as an owner
+func (r *ExternalResource) OwnerReference() metav1.OwnerReference {
+	return *util.ObjectToOwnerReference(r.ObjectReference())
+}
+
+// ConditionedStatus returns the conditions of this resource.
+func (r *ExternalResource) ConditionedStatus() *ConditionedStatus {
+	return &r.Status.ConditionedStatus
+}
+
+// IsAvailable for usage/binding
+func (r *ExternalResource) IsAvailable() bool {
+	return r.Status.State == ExternalResourceStateAvailable
+}
+
+// IsBound returns true if this resource is bound to a resource claim.
+func (r *ExternalResource) IsBound() bool {
+	return r.Status.IsBound()
+}
+
+// SetBound specifies whether this resource is bound to a resource claim.
+func (r *ExternalResource) SetBound(bound bool) {
+	r.Status.SetBound(bound)
+}
+
+// IsReleased returns true if this resource was released by its resource claim.
+func (r *ExternalResource) IsReleased() bool {
+	return r.Status.IsReleased()
+}
+
+// SetReleased marks this resource as released by its resource claim.
+func (r *ExternalResource) SetReleased() {
+	r.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this resource is, or was last, bound to.
+func (r *ExternalResource) ClaimRef() *corev1.ObjectReference {
+	return r.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this resource is bound to.
+func (r *ExternalResource) SetClaimRef(ref *corev1.ObjectReference) {
+	r.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this resource.
+func (r *ExternalResource) ReclaimPolicy() ReclaimPolicy {
+	return r.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this resource.
+func (r *ExternalResource) StatusSummary() ResourceStatusSummary {
+	msg := r.Status.FailureMessage()
+	if msg == "" && r.Status.State == ExternalResourceStateFailed {
+		msg = r.Status.Message
+	}
+	return ResourceStatusSummary{
+		State:      r.Status.State,
+		Endpoint:   r.Status.Endpoint,
+		ProviderID: r.Status.ProviderID,
+		Message:    msg,
+	}
+}
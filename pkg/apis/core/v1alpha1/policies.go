@@ -25,4 +25,21 @@ const (
 	// ReclaimRetain means the cloud provider resource backing this custom resource (CR) will be will be left in its current phase upon CR deletion for manual reclamation by the administrator.
 	// The default policy is Retain.
 	ReclaimRetain ReclaimPolicy = "Retain"
+	// ReclaimSnapshot means the cloud provider resource backing this custom resource (CR) will be deleted upon CR deletion, after a final snapshot of it has been taken.
+	// The final snapshot is named by FinalSnapshotName and is never deleted by Crossplane.
+	ReclaimSnapshot ReclaimPolicy = "Snapshot"
 )
+
+// OrDefault returns the policy, or the default Retain policy if no policy is set.
+func (p ReclaimPolicy) OrDefault() ReclaimPolicy {
+	if p == "" {
+		return ReclaimRetain
+	}
+	return p
+}
+
+// FinalSnapshotName returns the deterministic name of the final snapshot taken of the named cloud provider resource
+// before it is deleted under the Snapshot reclaim policy.
+func FinalSnapshotName(name string) string {
+	return name + "-final"
+}
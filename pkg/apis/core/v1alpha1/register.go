@@ -35,6 +35,9 @@ const (
 	Version           = "v1alpha1"
 	ResourceClassKind = "resourceclass"
 	APIVersion        = Group + "/" + Version
+
+	ExternalResourceKind           = "externalresource"
+	ExternalResourceKindAPIVersion = ExternalResourceKind + "." + APIVersion
 )
 
 var (
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +44,42 @@ const (
 	ResourceCredentialsTokenKey = "token"
 )
 
+// AnnotationDefaultClassFor is the annotation used to mark a ResourceClass as
+// the default class for claims that do not reference a class. Its value is a
+// comma separated list of claim kinds, e.g.
+// "mysqlinstance.storage.crossplane.io/v1alpha1".
+const AnnotationDefaultClassFor = "core.crossplane.io/default-class-for"
+
+// AnnotationExternalName is the annotation used to name an existing external
+// resource, e.g. an RDS instance identifier, that a managed resource adopts
+// rather than creating a new external resource.
+const AnnotationExternalName = "core.crossplane.io/external-name"
+
+// ExternalName returns the name of the existing external resource adopted by
+// the supplied managed resource, or an empty string if it adopts none.
+func ExternalName(o metav1.Object) string {
+	return o.GetAnnotations()[AnnotationExternalName]
+}
+
+// AnnotationDryRun is the annotation used to reconcile a managed resource, an
+// ExternalResource or the pool of a resource class in dry-run mode when its
+// value is "true". The changes that would be made to external resources are
+// recorded, but not made.
+const AnnotationDryRun = "core.crossplane.io/dry-run"
+
+// IsDryRun returns true if the supplied object is reconciled in dry-run mode.
+func IsDryRun(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationDryRun] == "true"
+}
+
+// AnnotationSecretHash is the annotation used to record a hash of the data of
+// the resource connection secret a claim secret was copied from.
+const AnnotationSecretHash = "core.crossplane.io/secret-hash"
+
+// LabelPoolClass is the label used to mark an unbound resource as a member of
+// the pool of the resource class named by its value.
+const LabelPoolClass = "core.crossplane.io/pool-class"
+
 // Resource defines a concrete resource that can be provisioned and bound to a resource claim.
 type Resource interface {
 	runtime.Object
@@ -55,6 +93,35 @@ type Resource interface {
 	IsBound() bool
 	// Update bound status of the resource
 	SetBound(bool)
+	// Is resource released by the claim it was bound to
+	IsReleased() bool
+	// Mark the resource as released by the claim it was bound to
+	SetReleased()
+	// Kubernetes object reference to the claim this resource is, or was last, bound to
+	ClaimRef() *corev1.ObjectReference
+	// Sets the reference to the claim this resource is bound to
+	SetClaimRef(*corev1.ObjectReference)
+	// Policy for handling this resource when it is released by its claim
+	ReclaimPolicy() ReclaimPolicy
+	// Summary of the observed state of this resource
+	StatusSummary() ResourceStatusSummary
+}
+
+// StorageResource is implemented by resources that provision storage, whose
+// size counts towards the storage limit of claim quotas.
+type StorageResource interface {
+	Resource
+	// Storage provisioned for this resource, in GB
+	StorageGB() int64
+}
+
+// StorageClaim is implemented by resource claims that may request the storage
+// of the resource they are bound to.
+type StorageClaim interface {
+	ResourceClaim
+	// Storage requested by this claim, in GB, or zero if the storage of the
+	// resource class is requested
+	RequestedStorageGB() int64
 }
 
 // ResourceClaim defines a resource claim that can be provisioned and bound to a concrete resource.
@@ -69,6 +136,12 @@ type ResourceClaim interface {
 	ObjectReference() *corev1.ObjectReference
 	// Gets the reference to the resource class this claim uses
 	ClassRef() *corev1.ObjectReference
+	// Sets the reference to the resource class this claim uses
+	SetClassRef(*corev1.ObjectReference)
+	// Gets the label selector used to match this claim to an existing resource
+	Selector() *metav1.LabelSelector
+	// Gets the template applied to the connection secret copied to this claim
+	SecretTemplate() *SecretTemplate
 	// Gets the reference to the resource that this claim is bound to
 	ResourceRef() *corev1.ObjectReference
 	// Sets the reference to the resource that this claim is bound to
@@ -83,6 +156,7 @@ type ResourceClaim interface {
 // +kubebuilder:printcolumn:name="PROVISIONER",type="string",JSONPath=".provisioner"
 // +kubebuilder:printcolumn:name="PROVIDER-REF",type="string",JSONPath=".providerRef.name"
 // +kubebuilder:printcolumn:name="RECLAIM-POLICY",type="string",JSONPath=".reclaimPolicy"
+// +kubebuilder:printcolumn:name="POOL-SIZE",type="integer",JSONPath=".pool.size"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type ResourceClass struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -110,6 +184,64 @@ type ResourceClass struct {
 	// ResourceInstances of this resource class are created with
 	// +optional
 	ReclaimPolicy ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// Constraints restrict the values that resource claims may request,
+	// keyed by the claim field they apply to, for example "engineVersion". A
+	// claim value that satisfies its constraint overrides the corresponding
+	// class parameter. Claim values without a constraint must match the class
+	// parameter exactly.
+	// +optional
+	Constraints map[string]ParameterConstraint `json:"constraints,omitempty"`
+
+	// Pool configures a pool of unbound resources of this class that are
+	// provisioned ahead of time, so that new claims can be bound to them
+	// without waiting for a resource to be provisioned.
+	// +optional
+	Pool *ResourcePool `json:"pool,omitempty"`
+
+	// ExternalProvisioner configures an out-of-process provisioner that
+	// provisions resources of this class through the external provisioner
+	// protocol. It is used when the provisioner of this class is not one of
+	// the provisioners built into Crossplane.
+	// +optional
+	ExternalProvisioner *ExternalProvisioner `json:"externalProvisioner,omitempty"`
+}
+
+// ResourcePool configures a pool of pre-provisioned, unbound resources
+type ResourcePool struct {
+	// ClaimKind is the kind of resource claim pooled resources are provisioned
+	// for, for example "mysqlinstance.storage.crossplane.io/v1alpha1".
+	ClaimKind string `json:"claimKind"`
+
+	// Size is the number of unbound resources to keep provisioned.
+	// +kubebuilder:validation:Minimum=0
+	Size int `json:"size"`
+}
+
+// ParameterConstraint restricts the values that resource claims may request
+// for a field. A claim value must satisfy every specified restriction.
+type ParameterConstraint struct {
+	// Values is the set of values that claims may request.
+	// +optional
+	Values []string `json:"values,omitempty"`
+
+	// Versions is a set of version ranges, for example "5.7.x", one of which
+	// the requested version must fall within.
+	// +optional
+	Versions []string `json:"versions,omitempty"`
+
+	// Minimum is the smallest numeric value that claims may request.
+	// +optional
+	Minimum *int64 `json:"minimum,omitempty"`
+
+	// Maximum is the largest numeric value that claims may request.
+	// +optional
+	Maximum *int64 `json:"maximum,omitempty"`
+
+	// Pattern is a regular expression that requested values must match in
+	// full.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -126,6 +258,18 @@ func (r *ResourceClass) ObjectReference() *corev1.ObjectReference {
 	return util.ObjectReference(r.ObjectMeta, util.IfEmptyString(r.APIVersion, APIVersion), util.IfEmptyString(r.Kind, ResourceClassKind))
 }
 
+// IsDefaultFor returns true if this resource class is annotated as the default
+// class for the supplied claim kind, e.g.
+// "mysqlinstance.storage.crossplane.io/v1alpha1".
+func (r *ResourceClass) IsDefaultFor(kindAPIVersion string) bool {
+	for _, k := range strings.Split(r.GetAnnotations()[AnnotationDefaultClassFor], ",") {
+		if strings.EqualFold(strings.TrimSpace(k), kindAPIVersion) {
+			return true
+		}
+	}
+	return false
+}
+
 // ResourceClaimStatus represents the status of a resource claim
 type ResourceClaimStatus struct {
 	ConditionedStatus
@@ -139,6 +283,48 @@ type ResourceClaimStatus struct {
 	// CredentialsSecretRef is a local reference to the generated secret containing the credentials
 	// for this resource claim.
 	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecret,omitempty"`
+
+	// ResourceStatus summarizes the observed state of the concrete resource this claim is bound to.
+	// It allows the state of the resource to be observed by users who cannot read the resource itself.
+	ResourceStatus ResourceStatusSummary `json:"resourceStatus,omitempty"`
+}
+
+// SecretTemplate describes how the connection secret of a resource is
+// transformed when it is copied to the secret of the claim bound to it
+type SecretTemplate struct {
+	// Keys renames keys of the resource connection secret. Each entry maps a
+	// key of the resource connection secret to the key it is written to in the
+	// claim secret, for example "endpoint: DB_HOST".
+	// +optional
+	Keys map[string]string `json:"keys,omitempty"`
+
+	// Templates derive additional keys of the claim secret from Go templates
+	// rendered against the values of the resource connection secret, for
+	// example "DATABASE_URL: '{{ mysqlURL . }}'" or
+	// "host: '{{ .endpoint }}:3306'".
+	// +optional
+	Templates map[string]string `json:"templates,omitempty"`
+}
+
+// ResourceStatusSummary summarizes the observed state of a concrete resource
+type ResourceStatusSummary struct {
+	// Kind of the concrete resource
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the concrete resource
+	Name string `json:"name,omitempty"`
+
+	// State of the resource as reported by the cloud provider
+	State string `json:"state,omitempty"`
+
+	// Endpoint used to connect to the resource
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ProviderID is the external ID that identifies the resource in the cloud provider
+	ProviderID string `json:"providerID,omitempty"`
+
+	// Message describes the most recent failure of the resource, if it is currently failed
+	Message string `json:"message,omitempty"`
 }
 
 // ResourceName is the name identifying various resources in a ResourceList.
@@ -174,6 +360,8 @@ type BasicResource struct {
 	state                string
 	phase                BindingStatusPhase
 	objectReference      *corev1.ObjectReference
+	claimReference       *corev1.ObjectReference
+	reclaimPolicy        ReclaimPolicy
 }
 
 // ConnectionSecretName referenced by this resource
@@ -202,6 +390,41 @@ func (br *BasicResource) SetBound(bound bool) {
 	br.phase.SetBound(bound)
 }
 
+// IsReleased returns true if this resource was released by its resource claim.
+func (br *BasicResource) IsReleased() bool {
+	return br.phase.IsReleased()
+}
+
+// SetReleased marks this resource as released by its resource claim.
+func (br *BasicResource) SetReleased() {
+	br.phase.SetReleased()
+}
+
+// ClaimRef returns the resource claim this resource is, or was last, bound to.
+func (br *BasicResource) ClaimRef() *corev1.ObjectReference {
+	return br.claimReference
+}
+
+// SetClaimRef sets the resource claim this resource is bound to.
+func (br *BasicResource) SetClaimRef(ref *corev1.ObjectReference) {
+	br.claimReference = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this resource.
+func (br *BasicResource) ReclaimPolicy() ReclaimPolicy {
+	return br.reclaimPolicy
+}
+
+// SetReclaimPolicy sets the reclaim policy of this resource.
+func (br *BasicResource) SetReclaimPolicy(p ReclaimPolicy) {
+	br.reclaimPolicy = p
+}
+
+// StatusSummary returns a summary of the observed state of this resource.
+func (br *BasicResource) StatusSummary() ResourceStatusSummary {
+	return ResourceStatusSummary{State: br.state, Endpoint: br.endpoint}
+}
+
 // NewBasicResource new instance of base resource
 func NewBasicResource(ref *corev1.ObjectReference, secretName, endpoint, state string) *BasicResource {
 	return &BasicResource{
@@ -88,3 +88,17 @@ func TestStorage(t *testing.T) {
 	g.Expect(c.Delete(ctx, fetched)).NotTo(HaveOccurred())
 	g.Expect(c.Get(ctx, key, fetched)).To(HaveOccurred())
 }
+
+func TestResourceClassIsDefaultFor(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	class := &ResourceClass{}
+	g.Expect(class.IsDefaultFor("mysqlinstance.storage.crossplane.io/v1alpha1")).To(BeFalse())
+
+	class.Annotations = map[string]string{
+		AnnotationDefaultClassFor: "mysqlinstance.storage.crossplane.io/v1alpha1, PostgreSQLInstance.storage.crossplane.io/v1alpha1",
+	}
+	g.Expect(class.IsDefaultFor("mysqlinstance.storage.crossplane.io/v1alpha1")).To(BeTrue())
+	g.Expect(class.IsDefaultFor("postgresqlinstance.storage.crossplane.io/v1alpha1")).To(BeTrue())
+	g.Expect(class.IsDefaultFor("bucket.storage.crossplane.io/v1alpha1")).To(BeFalse())
+}
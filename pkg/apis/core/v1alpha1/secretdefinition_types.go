@@ -0,0 +1,179 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CustomSecretDefinitionSpec defines the schema of the connection secrets of
+// a kind of resource claim
+type CustomSecretDefinitionSpec struct {
+	// Group is the API group of the resource claim kind, e.g. "storage.crossplane.io"
+	Group string `json:"group"`
+
+	// Names identify the resource claim kind within its group
+	Names CustomSecretDefinitionNames `json:"names"`
+
+	// Version is the API version of the resource claim kind, e.g. "v1alpha1"
+	Version string `json:"version"`
+
+	// Validation is the schema connection secrets must satisfy. Each property
+	// of the schema describes a key of the secret.
+	// +optional
+	Validation *apiextensionsv1beta1.CustomResourceValidation `json:"validation,omitempty"`
+}
+
+// CustomSecretDefinitionNames identify the resource claim kind whose
+// connection secrets are defined
+type CustomSecretDefinitionNames struct {
+	// Kind of the resource claim, e.g. "mysqlinstance"
+	Kind string `json:"kind"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CustomSecretDefinition defines the keys that the connection secrets of a
+// kind of resource claim must contain.
+// +k8s:openapi-gen=true
+// +kubebuilder:printcolumn:name="KIND",type="string",JSONPath=".spec.names.kind"
+// +kubebuilder:printcolumn:name="GROUP",type="string",JSONPath=".spec.group"
+// +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.version"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+type CustomSecretDefinition struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CustomSecretDefinitionSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CustomSecretDefinitionList contains a list of CustomSecretDefinition
+type CustomSecretDefinitionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CustomSecretDefinition `json:"items"`
+}
+
+// NewCustomSecretDefinition returns a definition of connection secrets for
+// the supplied kind of resource claim that requires each of the supplied keys.
+func NewCustomSecretDefinition(group, version, kind string, required ...string) *CustomSecretDefinition {
+	props := make(map[string]apiextensionsv1beta1.JSONSchemaProps, len(required))
+	for _, key := range required {
+		props[key] = apiextensionsv1beta1.JSONSchemaProps{Type: "string"}
+	}
+
+	return &CustomSecretDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: kind + "." + group},
+		Spec: CustomSecretDefinitionSpec{
+			Group:   group,
+			Names:   CustomSecretDefinitionNames{Kind: kind},
+			Version: version,
+			Validation: &apiextensionsv1beta1.CustomResourceValidation{
+				OpenAPIV3Schema: &apiextensionsv1beta1.JSONSchemaProps{
+					Properties: props,
+					Required:   required,
+				},
+			},
+		},
+	}
+}
+
+// ClaimKind returns the kind of resource claim whose connection secrets are
+// defined, e.g. "mysqlinstance.storage.crossplane.io/v1alpha1".
+func (s *CustomSecretDefinitionSpec) ClaimKind() string {
+	return strings.ToLower(s.Names.Kind) + "." + s.Group + "/" + s.Version
+}
+
+// Validate returns an error if the supplied secret data does not satisfy this
+// definition. Required keys must be present with a non-empty value, and the
+// values of keys described by the schema must be of the described type and
+// match any described pattern.
+func (s *CustomSecretDefinitionSpec) Validate(data map[string][]byte) error {
+	if s.Validation == nil || s.Validation.OpenAPIV3Schema == nil {
+		return nil
+	}
+	schema := s.Validation.OpenAPIV3Schema
+
+	var missing []string
+	for _, key := range schema.Required {
+		if len(data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("connection secret is missing required keys: %s", strings.Join(missing, ", "))
+	}
+
+	keys := make([]string, 0, len(schema.Properties))
+	for key := range schema.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value, ok := data[key]
+		if !ok {
+			continue
+		}
+		if err := validateSecretValue(schema.Properties[key], string(value)); err != nil {
+			return fmt.Errorf("connection secret key %s is invalid: %s", key, err)
+		}
+	}
+	return nil
+}
+
+func validateSecretValue(props apiextensionsv1beta1.JSONSchemaProps, value string) error {
+	var err error
+	switch props.Type {
+	case "integer":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("value is not of type %s", props.Type)
+	}
+
+	if props.Pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(props.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %s: %s", props.Pattern, err)
+	}
+	if !re.MatchString(value) {
+		return fmt.Errorf("value does not match pattern %s", props.Pattern)
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&CustomSecretDefinition{}, &CustomSecretDefinitionList{})
+}
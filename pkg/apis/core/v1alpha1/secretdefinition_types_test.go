@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+)
+
+func TestCustomSecretDefinitionSpec_ClaimKind(t *testing.T) {
+	g := NewGomegaWithT(t)
+	csd := NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "MySQLInstance")
+	g.Expect(csd.Name).To(Equal("MySQLInstance.storage.crossplane.io"))
+	g.Expect(csd.Spec.ClaimKind()).To(Equal("mysqlinstance.storage.crossplane.io/v1alpha1"))
+}
+
+func TestCustomSecretDefinitionSpec_Validate(t *testing.T) {
+	spec := NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "mysqlinstance", "endpoint", "password").Spec
+	spec.Validation.OpenAPIV3Schema.Properties["port"] = apiextensionsv1beta1.JSONSchemaProps{Type: "integer"}
+	spec.Validation.OpenAPIV3Schema.Properties["endpoint"] = apiextensionsv1beta1.JSONSchemaProps{Type: "string", Pattern: "^[a-z0-9.-]+$"}
+
+	cases := []struct {
+		name    string
+		spec    CustomSecretDefinitionSpec
+		data    map[string][]byte
+		wantErr string
+	}{
+		{"NoSchema", CustomSecretDefinitionSpec{}, nil, ""},
+		{"Valid", spec, map[string][]byte{"endpoint": []byte("db.example.org"), "password": []byte("pass"), "port": []byte("3306")}, ""},
+		{"OptionalKeyMissing", spec, map[string][]byte{"endpoint": []byte("db.example.org"), "password": []byte("pass")}, ""},
+		{"RequiredKeysMissing", spec, map[string][]byte{"port": []byte("3306")}, "connection secret is missing required keys: endpoint, password"},
+		{"RequiredKeyEmpty", spec, map[string][]byte{"endpoint": []byte("db.example.org"), "password": {}}, "connection secret is missing required keys: password"},
+		{"WrongType", spec, map[string][]byte{"endpoint": []byte("db.example.org"), "password": []byte("pass"), "port": []byte("default")}, "connection secret key port is invalid: value is not of type integer"},
+		{"PatternMismatch", spec, map[string][]byte{"endpoint": []byte("DB EXAMPLE"), "password": []byte("pass")}, "connection secret key endpoint is invalid: value does not match pattern ^[a-z0-9.-]+$"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := tc.spec.Validate(tc.data)
+			if tc.wantErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(tc.wantErr))
+		})
+	}
+}
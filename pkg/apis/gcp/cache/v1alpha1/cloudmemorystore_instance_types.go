@@ -223,6 +223,11 @@ func (c *CloudMemorystoreInstance) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(c.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this instance.
+func (c *CloudMemorystoreInstance) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &c.Status.ConditionedStatus
+}
+
 // IsAvailable for usage/binding
 func (c *CloudMemorystoreInstance) IsAvailable() bool {
 	return c.Status.State == StateReady
@@ -241,3 +246,38 @@ func (c *CloudMemorystoreInstance) SetBound(state bool) {
 		c.Status.Phase = corev1alpha1.BindingStateUnbound
 	}
 }
+
+// IsReleased returns true if this resource was released by its resource claim.
+func (c *CloudMemorystoreInstance) IsReleased() bool {
+	return c.Status.IsReleased()
+}
+
+// SetReleased marks this resource as released by its resource claim.
+func (c *CloudMemorystoreInstance) SetReleased() {
+	c.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this resource is, or was last, bound to.
+func (c *CloudMemorystoreInstance) ClaimRef() *v1.ObjectReference {
+	return c.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this resource is bound to.
+func (c *CloudMemorystoreInstance) SetClaimRef(ref *v1.ObjectReference) {
+	c.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this resource.
+func (c *CloudMemorystoreInstance) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return c.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this resource.
+func (c *CloudMemorystoreInstance) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      c.Status.State,
+		Endpoint:   c.Status.Endpoint,
+		ProviderID: c.Status.ProviderID,
+		Message:    c.Status.FailureMessage(),
+	}
+}
@@ -30,6 +30,8 @@ import (
 const (
 	ClusterStateProvisioning = "PROVISIONING"
 	ClusterStateRunning      = "RUNNING"
+	ClusterStateStopping     = "STOPPING"
+	ClusterStateError        = "ERROR"
 )
 
 // Defaults for GKE resources.
@@ -105,6 +107,8 @@ type GKEClusterStatus struct {
 	ClusterName string `json:"clusterName"`
 	Endpoint    string `json:"endpoint"`
 	State       string `json:"state,omitempty"`
+	// ProviderID is the external ID to identify this resource in the cloud provider
+	ProviderID string `json:"providerID,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -164,6 +168,11 @@ func (g *GKECluster) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(g.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this cluster.
+func (g *GKECluster) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &g.Status.ConditionedStatus
+}
+
 // ConnectionSecret returns the connection secret for this GKE cluster.
 func (g *GKECluster) ConnectionSecret() *corev1.Secret {
 	return &corev1.Secret{
@@ -207,3 +216,38 @@ func (g *GKECluster) IsBound() bool {
 func (g *GKECluster) SetBound(bound bool) {
 	g.Status.SetBound(bound)
 }
+
+// IsReleased returns true if this cluster was released by its resource claim.
+func (g *GKECluster) IsReleased() bool {
+	return g.Status.IsReleased()
+}
+
+// SetReleased marks this cluster as released by its resource claim.
+func (g *GKECluster) SetReleased() {
+	g.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this cluster is, or was last, bound to.
+func (g *GKECluster) ClaimRef() *corev1.ObjectReference {
+	return g.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this cluster is bound to.
+func (g *GKECluster) SetClaimRef(ref *corev1.ObjectReference) {
+	g.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this cluster.
+func (g *GKECluster) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return g.Spec.ReclaimPolicy
+}
+
+// StatusSummary returns a summary of the observed state of this cluster.
+func (g *GKECluster) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      g.Status.State,
+		Endpoint:   g.Status.Endpoint,
+		ProviderID: g.Status.ProviderID,
+		Message:    g.Status.FailureMessage(),
+	}
+}
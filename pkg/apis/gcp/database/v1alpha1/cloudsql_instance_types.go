@@ -36,6 +36,9 @@ const (
 
 	// StateFailed  represents a CloudSQL instance has failed in some way
 	StateFailed = "FAILED"
+
+	// StatePendingDelete represents a CloudSQL instance that is in the process of being deleted
+	StatePendingDelete = "PENDING_DELETE"
 )
 
 // CloudSQL version prefixes.
@@ -88,6 +91,21 @@ type CloudsqlInstanceStatus struct {
 
 	// Name of the Cloud SQL instance. This does not include the project ID.
 	InstanceName string `json:"instanceName,omitempty"`
+
+	// FinalSnapshot is the name of the Cloud SQL instance cloned from this
+	// instance before it was deleted under the Snapshot reclaim policy. The
+	// clone is a running, billed instance, because the backups of a Cloud SQL
+	// instance are deleted with it. It is never deleted by Crossplane.
+	FinalSnapshot string `json:"finalSnapshot,omitempty"`
+
+	// Operation is the name of the Cloud SQL operation that is updating the
+	// settings of this instance, if any.
+	Operation string `json:"operation,omitempty"`
+
+	// OperationGeneration is the generation of this instance whose spec the
+	// operation is applying. A failed operation is not retried until the spec
+	// of this instance changes.
+	OperationGeneration int64 `json:"operationGeneration,omitempty"`
 }
 
 // +genclient
@@ -174,6 +192,11 @@ func (c *CloudsqlInstance) OwnerReference() metav1.OwnerReference {
 	return *util.ObjectToOwnerReference(c.ObjectReference())
 }
 
+// ConditionedStatus returns the conditions of this instance.
+func (c *CloudsqlInstance) ConditionedStatus() *corev1alpha1.ConditionedStatus {
+	return &c.Status.ConditionedStatus
+}
+
 // IsAvailable for usage/binding
 func (c *CloudsqlInstance) IsAvailable() bool {
 	return c.Status.State == StateRunnable
@@ -188,3 +211,43 @@ func (c *CloudsqlInstance) IsBound() bool {
 func (c *CloudsqlInstance) SetBound(bound bool) {
 	c.Status.SetBound(bound)
 }
+
+// IsReleased returns true if this instance was released by its resource claim.
+func (c *CloudsqlInstance) IsReleased() bool {
+	return c.Status.IsReleased()
+}
+
+// SetReleased marks this instance as released by its resource claim.
+func (c *CloudsqlInstance) SetReleased() {
+	c.Status.SetReleased()
+}
+
+// ClaimRef returns the resource claim this instance is, or was last, bound to.
+func (c *CloudsqlInstance) ClaimRef() *v1.ObjectReference {
+	return c.Spec.ClaimRef
+}
+
+// SetClaimRef sets the resource claim this instance is bound to.
+func (c *CloudsqlInstance) SetClaimRef(ref *v1.ObjectReference) {
+	c.Spec.ClaimRef = ref
+}
+
+// ReclaimPolicy returns the reclaim policy of this instance.
+func (c *CloudsqlInstance) ReclaimPolicy() corev1alpha1.ReclaimPolicy {
+	return c.Spec.ReclaimPolicy
+}
+
+// StorageGB returns the storage provisioned for this instance, in GB
+func (c *CloudsqlInstance) StorageGB() int64 {
+	return c.Spec.StorageGB
+}
+
+// StatusSummary returns a summary of the observed state of this instance.
+func (c *CloudsqlInstance) StatusSummary() corev1alpha1.ResourceStatusSummary {
+	return corev1alpha1.ResourceStatusSummary{
+		State:      c.Status.State,
+		Endpoint:   c.Status.Endpoint,
+		ProviderID: c.Status.ProviderID,
+		Message:    c.Status.FailureMessage(),
+	}
+}
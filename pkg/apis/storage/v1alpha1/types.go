@@ -30,9 +30,18 @@ type MySQLInstanceSpec struct {
 	ResourceRef *corev1.ObjectReference `json:"resourceName,omitempty"`
 	Selector    metav1.LabelSelector    `json:"selector,omitempty"`
 
+	// SecretTemplate renames and derives keys of the claim's connection secret
+	// +optional
+	SecretTemplate *corev1alpha1.SecretTemplate `json:"secretTemplate,omitempty"`
+
 	// mysql instance properties
 	// +kubebuilder:validation:Enum=5.6,5.7
 	EngineVersion string `json:"engineVersion"`
+
+	// StorageSize is the storage to provision for the instance, in GB. It
+	// overrides the storage size of the resource class if the class allows it.
+	// +optional
+	StorageSize int64 `json:"storageSize,omitempty"`
 }
 
 // +genclient
@@ -43,6 +52,8 @@ type MySQLInstanceSpec struct {
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.bindingPhase"
 // +kubebuilder:printcolumn:name="CLASS",type="string",JSONPath=".spec.classReference.name"
 // +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.engineVersion"
+// +kubebuilder:printcolumn:name="RESOURCE-STATE",type="string",JSONPath=".status.resourceStatus.state"
+// +kubebuilder:printcolumn:name="ENDPOINT",type="string",JSONPath=".status.resourceStatus.endpoint"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type MySQLInstance struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -81,6 +92,23 @@ func (m *MySQLInstance) ClassRef() *corev1.ObjectReference {
 	return m.Spec.ClassRef
 }
 
+// SetClassRef specifies the resource class used by this resource claim.
+func (m *MySQLInstance) SetClassRef(ref *corev1.ObjectReference) {
+	m.Spec.ClassRef = ref
+}
+
+// Selector returns the label selector used to match this resource claim to an
+// existing resource.
+func (m *MySQLInstance) Selector() *metav1.LabelSelector {
+	return &m.Spec.Selector
+}
+
+// SecretTemplate returns the template applied to the connection secret of the
+// resource this claim is bound to when it is copied to the claim's secret.
+func (m *MySQLInstance) SecretTemplate() *corev1alpha1.SecretTemplate {
+	return m.Spec.SecretTemplate
+}
+
 // ResourceRef returns the resource claimed by this resource claim.
 func (m *MySQLInstance) ResourceRef() *corev1.ObjectReference {
 	return m.Spec.ResourceRef
@@ -91,6 +119,12 @@ func (m *MySQLInstance) SetResourceRef(ref *corev1.ObjectReference) {
 	m.Spec.ResourceRef = ref
 }
 
+// RequestedStorageGB returns the storage requested by this resource claim, in
+// GB.
+func (m *MySQLInstance) RequestedStorageGB() int64 {
+	return m.Spec.StorageSize
+}
+
 // PostgreSQLInstanceSpec specifies the configuration of this
 // PostgreSQLInstance.
 type PostgreSQLInstanceSpec struct {
@@ -98,9 +132,18 @@ type PostgreSQLInstanceSpec struct {
 	ResourceRef *corev1.ObjectReference `json:"resourceName,omitempty"`
 	Selector    metav1.LabelSelector    `json:"selector,omitempty"`
 
+	// SecretTemplate renames and derives keys of the claim's connection secret
+	// +optional
+	SecretTemplate *corev1alpha1.SecretTemplate `json:"secretTemplate,omitempty"`
+
 	// postgresql instance properties
 	// +kubebuilder:validation:Enum=9.6
 	EngineVersion string `json:"engineVersion,omitempty"`
+
+	// StorageSize is the storage to provision for the instance, in GB. It
+	// overrides the storage size of the resource class if the class allows it.
+	// +optional
+	StorageSize int64 `json:"storageSize,omitempty"`
 }
 
 // +genclient
@@ -111,6 +154,8 @@ type PostgreSQLInstanceSpec struct {
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.bindingPhase"
 // +kubebuilder:printcolumn:name="CLASS",type="string",JSONPath=".spec.classReference.name"
 // +kubebuilder:printcolumn:name="VERSION",type="string",JSONPath=".spec.engineVersion"
+// +kubebuilder:printcolumn:name="RESOURCE-STATE",type="string",JSONPath=".status.resourceStatus.state"
+// +kubebuilder:printcolumn:name="ENDPOINT",type="string",JSONPath=".status.resourceStatus.endpoint"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type PostgreSQLInstance struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -161,6 +206,23 @@ func (p *PostgreSQLInstance) ClassRef() *corev1.ObjectReference {
 	return p.Spec.ClassRef
 }
 
+// SetClassRef specifies the resource class used by this resource claim.
+func (p *PostgreSQLInstance) SetClassRef(ref *corev1.ObjectReference) {
+	p.Spec.ClassRef = ref
+}
+
+// Selector returns the label selector used to match this resource claim to an
+// existing resource.
+func (p *PostgreSQLInstance) Selector() *metav1.LabelSelector {
+	return &p.Spec.Selector
+}
+
+// SecretTemplate returns the template applied to the connection secret of the
+// resource this claim is bound to when it is copied to the claim's secret.
+func (p *PostgreSQLInstance) SecretTemplate() *corev1alpha1.SecretTemplate {
+	return p.Spec.SecretTemplate
+}
+
 // ResourceRef returns the resource claimed by this resource claim.
 func (p *PostgreSQLInstance) ResourceRef() *corev1.ObjectReference {
 	return p.Spec.ResourceRef
@@ -171,6 +233,12 @@ func (p *PostgreSQLInstance) SetResourceRef(ref *corev1.ObjectReference) {
 	p.Spec.ResourceRef = ref
 }
 
+// RequestedStorageGB returns the storage requested by this resource claim, in
+// GB.
+func (p *PostgreSQLInstance) RequestedStorageGB() int64 {
+	return p.Spec.StorageSize
+}
+
 // LocalPermissionType - Base type for LocalPermissions
 type LocalPermissionType string
 
@@ -200,6 +268,10 @@ type BucketSpec struct {
 	ResourceRef *corev1.ObjectReference `json:"resourceName,omitempty"`
 	Selector    metav1.LabelSelector    `json:"selector,omitempty"`
 
+	// SecretTemplate renames and derives keys of the claim's connection secret
+	// +optional
+	SecretTemplate *corev1alpha1.SecretTemplate `json:"secretTemplate,omitempty"`
+
 	// Bucket properties
 	// +kubebuilder:validation:MaxLength=63
 	// +kubebuilder:validation:MinLength=3
@@ -227,6 +299,8 @@ type BucketSpec struct {
 // +kubebuilder:printcolumn:name="CLASS",type="string",JSONPath=".spec.classReference.name"
 // +kubebuilder:printcolumn:name="PREDEFINED-ACL",type="string",JSONPath=".spec.predefinedACL"
 // +kubebuilder:printcolumn:name="LOCAL-PERMISSION",type="string",JSONPath=".spec.localPermission"
+// +kubebuilder:printcolumn:name="RESOURCE-STATE",type="string",JSONPath=".status.resourceStatus.state"
+// +kubebuilder:printcolumn:name="ENDPOINT",type="string",JSONPath=".status.resourceStatus.endpoint"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 type Bucket struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -265,6 +339,23 @@ func (b *Bucket) ClassRef() *corev1.ObjectReference {
 	return b.Spec.ClassRef
 }
 
+// SetClassRef specifies the resource class used by this resource claim.
+func (b *Bucket) SetClassRef(ref *corev1.ObjectReference) {
+	b.Spec.ClassRef = ref
+}
+
+// Selector returns the label selector used to match this resource claim to an
+// existing resource.
+func (b *Bucket) Selector() *metav1.LabelSelector {
+	return &b.Spec.Selector
+}
+
+// SecretTemplate returns the template applied to the connection secret of the
+// resource this claim is bound to when it is copied to the claim's secret.
+func (b *Bucket) SecretTemplate() *corev1alpha1.SecretTemplate {
+	return b.Spec.SecretTemplate
+}
+
 // ResourceRef returns the resource claimed by this resource claim.
 func (b *Bucket) ResourceRef() *corev1.ObjectReference {
 	return b.Spec.ResourceRef
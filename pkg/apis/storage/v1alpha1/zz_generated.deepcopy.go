@@ -22,6 +22,8 @@ package v1alpha1
 import (
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -99,6 +101,11 @@ func (in *BucketSpec) DeepCopyInto(out *BucketSpec) {
 		**out = **in
 	}
 	in.Selector.DeepCopyInto(&out.Selector)
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = new(corev1alpha1.SecretTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PredefinedACL != nil {
 		in, out := &in.PredefinedACL, &out.PredefinedACL
 		*out = new(PredefinedACL)
@@ -197,6 +204,11 @@ func (in *MySQLInstanceSpec) DeepCopyInto(out *MySQLInstanceSpec) {
 		**out = **in
 	}
 	in.Selector.DeepCopyInto(&out.Selector)
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = new(corev1alpha1.SecretTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -285,6 +297,11 @@ func (in *PostgreSQLInstanceSpec) DeepCopyInto(out *PostgreSQLInstanceSpec) {
 		**out = **in
 	}
 	in.Selector.DeepCopyInto(&out.Selector)
+	if in.SecretTemplate != nil {
+		in, out := &in.SecretTemplate, &out.SecretTemplate
+		*out = new(corev1alpha1.SecretTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
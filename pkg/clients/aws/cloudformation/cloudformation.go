@@ -18,13 +18,18 @@ package cloudformation
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	cf "github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	cfiface "github.com/aws/aws-sdk-go-v2/service/cloudformation/cloudformationiface"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	awsclients "github.com/crossplaneio/crossplane/pkg/clients/aws"
 )
 
+var observer = clients.NewObserver("aws/cloudformation", awsclients.ClassifyError)
+
 // Client interface to perform CloudFormation operations
 type Client interface {
 	CreateStack(stackName *string, templateBody *string, parameters map[string]string) (stackID *string, err error)
@@ -43,6 +48,8 @@ func NewClient(config *aws.Config) Client {
 
 // CreateStack - Creates a stack
 func (c *cloudFormationClient) CreateStack(stackName *string, templateBody *string, parameters map[string]string) (stackID *string, err error) {
+	defer observer.Observe("CreateStack", time.Now(), &err)
+
 	cfParams := make([]cf.Parameter, 0)
 	for k, v := range parameters {
 		if v != "" {
@@ -59,6 +66,8 @@ func (c *cloudFormationClient) CreateStack(stackName *string, templateBody *stri
 
 // GetStack info
 func (c *cloudFormationClient) GetStack(stackID *string) (stack *cf.Stack, err error) {
+	defer observer.Observe("GetStack", time.Now(), &err)
+
 	describeStackResponse, err := c.cloudformation.DescribeStacksRequest(&cf.DescribeStacksInput{StackName: stackID}).Send()
 	if err != nil {
 		return nil, err
@@ -74,17 +83,11 @@ func (c *cloudFormationClient) GetStack(stackID *string) (stack *cf.Stack, err e
 }
 
 // DeleteStack deletes a stack
-func (c *cloudFormationClient) DeleteStack(stackID *string) error {
-	_, err := c.cloudformation.DeleteStackRequest(&cf.DeleteStackInput{StackName: stackID}).Send()
-	return err
-}
+func (c *cloudFormationClient) DeleteStack(stackID *string) (err error) {
+	defer observer.Observe("DeleteStack", time.Now(), &err)
 
-// IsErrorNotFound - not found error
-func IsErrorNotFound(err error) bool {
-	if cloudformationErr, ok := err.(awserr.Error); ok && cloudformationErr.Code() == cf.ErrCodeStackInstanceNotFoundException {
-		return true
-	}
-	return false
+	_, err = c.cloudformation.DeleteStackRequest(&cf.DeleteStackInput{StackName: stackID}).Send()
+	return err
 }
 
 // IsCompletedState validates that operation is complete for a create or update.
@@ -29,9 +29,13 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 
 	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	awsclients "github.com/crossplaneio/crossplane/pkg/clients/aws"
 	cfc "github.com/crossplaneio/crossplane/pkg/clients/aws/cloudformation"
 )
 
+var observer = clients.NewObserver("aws/eks", awsclients.ClassifyError)
+
 const (
 	clusterIDHeader                = "x-k8s-aws-id"
 	v1Prefix                       = "k8s-aws-v1."
@@ -104,7 +108,9 @@ func NewClient(config *aws.Config) Client {
 }
 
 // Create new EKS cluster
-func (e *eksClient) Create(name string, spec awscomputev1alpha1.EKSClusterSpec) (*Cluster, error) {
+func (e *eksClient) Create(name string, spec awscomputev1alpha1.EKSClusterSpec) (cluster *Cluster, err error) {
+	defer observer.Observe("Create", time.Now(), &err)
+
 	input := &eks.CreateClusterInput{
 		Name:    aws.String(name),
 		RoleArn: aws.String(spec.RoleARN),
@@ -172,7 +178,9 @@ func (e *eksClient) CreateWorkerNodes(name string, spec awscomputev1alpha1.EKSCl
 }
 
 // Get an existing EKS cluster
-func (e *eksClient) Get(name string) (*Cluster, error) {
+func (e *eksClient) Get(name string) (cluster *Cluster, err error) {
+	defer observer.Observe("Get", time.Now(), &err)
+
 	input := &eks.DescribeClusterInput{Name: aws.String(name)}
 	output, err := e.eks.DescribeClusterRequest(input).Send()
 	if err != nil {
@@ -203,9 +211,11 @@ func (e *eksClient) GetWorkerNodes(stackID string) (*ClusterWorkers, error) {
 }
 
 // Delete a EKS cluster
-func (e *eksClient) Delete(name string) error {
+func (e *eksClient) Delete(name string) (err error) {
+	defer observer.Observe("Delete", time.Now(), &err)
+
 	input := &eks.DeleteClusterInput{Name: aws.String(name)}
-	_, err := e.eks.DeleteClusterRequest(input).Send()
+	_, err = e.eks.DeleteClusterRequest(input).Send()
 	return err
 }
 
@@ -228,22 +238,6 @@ func (e *eksClient) ConnectionToken(name string) (string, error) {
 	return v1Prefix + base64.RawURLEncoding.EncodeToString([]byte(presignedURLString)), nil
 }
 
-// IsErrorAlreadyExists helper function
-func IsErrorAlreadyExists(err error) bool {
-	return strings.Contains(err.Error(), eks.ErrCodeResourceInUseException)
-}
-
-// IsErrorBadRequest helper function
-func IsErrorBadRequest(err error) bool {
-	return strings.Contains(err.Error(), eks.ErrCodeInvalidParameterException) ||
-		strings.Contains(err.Error(), eks.ErrCodeUnsupportedAvailabilityZoneException)
-}
-
-// IsErrorNotFound helper function
-func IsErrorNotFound(err error) bool {
-	return strings.Contains(err.Error(), eks.ErrCodeResourceNotFoundException)
-}
-
 const (
 	// workerCloudFormationTemplate taken from aws README
 	// https://docs.aws.amazon.com/eks/latest/userguide/launch-workers.html
@@ -26,6 +26,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws"
 )
 
@@ -165,9 +166,20 @@ func NewModifyReplicationGroupInput(g *v1alpha1.ReplicationGroup) *elasticache.M
 }
 
 // NewDeleteReplicationGroupInput returns ElastiCache replication group deletion
-// input suitable for use with the AWS API.
+// input suitable for use with the AWS API. A final snapshot is requested when
+// the supplied replication group uses the Snapshot reclaim policy.
 func NewDeleteReplicationGroupInput(g *v1alpha1.ReplicationGroup) *elasticache.DeleteReplicationGroupInput {
-	return &elasticache.DeleteReplicationGroupInput{ReplicationGroupId: aws.String(NewReplicationGroupID(g), aws.FieldRequired)}
+	i := &elasticache.DeleteReplicationGroupInput{ReplicationGroupId: aws.String(NewReplicationGroupID(g), aws.FieldRequired)}
+	if g.Spec.ReclaimPolicy == corev1alpha1.ReclaimSnapshot {
+		i.FinalSnapshotIdentifier = aws.String(NewFinalSnapshotID(g))
+	}
+	return i
+}
+
+// NewFinalSnapshotID returns the identifier of the snapshot taken before the
+// supplied replication group is deleted.
+func NewFinalSnapshotID(g *v1alpha1.ReplicationGroup) string {
+	return corev1alpha1.FinalSnapshotName(NewReplicationGroupID(g))
 }
 
 // NewDescribeReplicationGroupsInput returns ElastiCache replication group describe
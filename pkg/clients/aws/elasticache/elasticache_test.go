@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws"
 )
 
@@ -297,6 +298,17 @@ func TestNewDeleteReplicationGroupInput(t *testing.T) {
 			group: replicationGroup,
 			want:  &elasticache.DeleteReplicationGroupInput{ReplicationGroupId: aws.String(id, aws.FieldRequired)},
 		},
+		{
+			name: "FinalSnapshot",
+			group: &v1alpha1.ReplicationGroup{
+				ObjectMeta: meta,
+				Spec:       v1alpha1.ReplicationGroupSpec{ReclaimPolicy: corev1alpha1.ReclaimSnapshot},
+			},
+			want: &elasticache.DeleteReplicationGroupInput{
+				ReplicationGroupId:      aws.String(id, aws.FieldRequired),
+				FinalSnapshotIdentifier: aws.String(id + "-final"),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,130 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/pkg/errors"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+)
+
+// errorClasses of AWS error codes that cannot be classified by the conventions
+// of their names, or that break them.
+var errorClasses = map[string]clients.ErrorClass{
+	"BucketAlreadyOwnedByYou": clients.ErrorAlreadyExists,
+	"ResourceInUseException":  clients.ErrorAlreadyExists,
+
+	"ProvisionedThroughputExceededException": clients.ErrorThrottled,
+	"RequestLimitExceeded":                   clients.ErrorThrottled,
+	"SlowDown":                               clients.ErrorThrottled,
+	"TooManyRequestsException":               clients.ErrorThrottled,
+
+	"ClientException":                      clients.ErrorInvalidInput,
+	"InsufficientCapabilitiesException":    clients.ErrorInvalidInput,
+	"UnsupportedAvailabilityZoneException": clients.ErrorInvalidInput,
+	"ValidationError":                      clients.ErrorInvalidInput,
+	"ValidationException":                  clients.ErrorInvalidInput,
+
+	"AccessDenied":                clients.ErrorPermissionDenied,
+	"AccessDeniedException":       clients.ErrorPermissionDenied,
+	"AuthFailure":                 clients.ErrorPermissionDenied,
+	"ExpiredToken":                clients.ErrorPermissionDenied,
+	"ExpiredTokenException":       clients.ErrorPermissionDenied,
+	"InvalidAccessKeyId":          clients.ErrorPermissionDenied,
+	"InvalidClientTokenId":        clients.ErrorPermissionDenied,
+	"OptInRequired":               clients.ErrorPermissionDenied,
+	"SignatureDoesNotMatch":       clients.ErrorPermissionDenied,
+	"UnauthorizedOperation":       clients.ErrorPermissionDenied,
+	"UnrecognizedClientException": clients.ErrorPermissionDenied,
+
+	"TooManyBuckets": clients.ErrorQuotaExceeded,
+
+	"InsufficientDBInstanceCapacity": clients.ErrorTransient,
+	"InternalError":                  clients.ErrorTransient,
+	"InternalFailure":                clients.ErrorTransient,
+	"RequestError":                   clients.ErrorTransient,
+	"RequestTimeout":                 clients.ErrorTransient,
+	"RequestTimeoutException":        clients.ErrorTransient,
+	"ServerException":                clients.ErrorTransient,
+	"ServiceFailure":                 clients.ErrorTransient,
+	"ServiceUnavailable":             clients.ErrorTransient,
+	"ServiceUnavailableException":    clients.ErrorTransient,
+	"Unavailable":                    clients.ErrorTransient,
+}
+
+// ClassifyError returns the class of the supplied error returned by the AWS
+// SDK. Errors are classified by their AWS error code, falling back to the HTTP
+// status code of the failed request.
+func ClassifyError(err error) clients.ErrorClass {
+	awsErr, ok := errors.Cause(err).(awserr.Error)
+	if !ok {
+		return clients.ErrorUnknown
+	}
+	if c := classifyCode(awsErr.Code()); c != clients.ErrorUnknown {
+		return c
+	}
+	if rf, ok := awsErr.(awserr.RequestFailure); ok {
+		return clients.ClassifyStatusCode(rf.StatusCode())
+	}
+	return clients.ErrorUnknown
+}
+
+// classifyCode returns the class of the supplied AWS error code. Codes that
+// are not explicitly classified are classified by the conventions of their
+// names, e.g. DBInstanceNotFound or EntityAlreadyExists.
+func classifyCode(code string) clients.ErrorClass {
+	if c, ok := errorClasses[code]; ok {
+		return c
+	}
+	switch {
+	case strings.Contains(code, "NotFound") || strings.HasPrefix(code, "NoSuch"):
+		return clients.ErrorNotFound
+	case strings.Contains(code, "AlreadyExists"):
+		return clients.ErrorAlreadyExists
+	case strings.Contains(code, "Throttl"):
+		return clients.ErrorThrottled
+	case strings.Contains(code, "QuotaExceeded") || strings.Contains(code, "LimitExceeded"):
+		return clients.ErrorQuotaExceeded
+	case strings.HasPrefix(code, "Invalid") && strings.Contains(code, "State"):
+		// e.g. InvalidDBInstanceState, raised while the resource is busy
+		return clients.ErrorTransient
+	case strings.HasPrefix(code, "Invalid") || strings.HasPrefix(code, "Malformed"):
+		return clients.ErrorInvalidInput
+	}
+	return clients.ErrorUnknown
+}
+
+// IsErrorNotFound returns true if the supplied error indicates the requested
+// AWS resource does not exist.
+func IsErrorNotFound(err error) bool {
+	return ClassifyError(err) == clients.ErrorNotFound
+}
+
+// IsErrorAlreadyExists returns true if the supplied error indicates the AWS
+// resource to be created already exists.
+func IsErrorAlreadyExists(err error) bool {
+	return ClassifyError(err) == clients.ErrorAlreadyExists
+}
+
+// IsErrorBadRequest returns true if the supplied error indicates the request
+// to the AWS API was invalid.
+func IsErrorBadRequest(err error) bool {
+	return ClassifyError(err) == clients.ErrorInvalidInput
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+)
+
+func TestClassifyError(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		err      error
+		expected clients.ErrorClass
+	}{
+		{nil, clients.ErrorUnknown},
+		{fmt.Errorf("DBInstanceNotFound"), clients.ErrorUnknown},
+		{awserr.New("DBInstanceNotFound", "", nil), clients.ErrorNotFound},
+		{awserr.New("NoSuchBucket", "", nil), clients.ErrorNotFound},
+		{awserr.New("EntityAlreadyExists", "", nil), clients.ErrorAlreadyExists},
+		{awserr.New("ResourceInUseException", "", nil), clients.ErrorAlreadyExists},
+		{awserr.New("Throttling", "", nil), clients.ErrorThrottled},
+		{awserr.New("RequestLimitExceeded", "", nil), clients.ErrorThrottled},
+		{awserr.New("InvalidParameterException", "", nil), clients.ErrorInvalidInput},
+		{awserr.New("InvalidClientTokenId", "", nil), clients.ErrorPermissionDenied},
+		{awserr.New("AccessDenied", "", nil), clients.ErrorPermissionDenied},
+		{awserr.New("InstanceQuotaExceeded", "", nil), clients.ErrorQuotaExceeded},
+		{awserr.New("ResourceLimitExceededException", "", nil), clients.ErrorQuotaExceeded},
+		{awserr.New("InvalidDBInstanceState", "", nil), clients.ErrorTransient},
+		{awserr.New("ServiceUnavailableException", "", nil), clients.ErrorTransient},
+		{awserr.New("SomethingUnexpected", "", nil), clients.ErrorUnknown},
+		{awserr.NewRequestFailure(awserr.New("SomethingUnexpected", "", nil), http.StatusForbidden, ""), clients.ErrorPermissionDenied},
+		{errors.Wrap(awserr.New("DBInstanceNotFound", "", nil), "wrapped"), clients.ErrorNotFound},
+	}
+
+	for _, tt := range cases {
+		g.Expect(ClassifyError(tt.err)).To(Equal(tt.expected), "error %v", tt.err)
+	}
+
+	g.Expect(IsErrorNotFound(awserr.New("NoSuchEntity", "", nil))).To(BeTrue())
+	g.Expect(IsErrorAlreadyExists(awserr.New("DBInstanceAlreadyExists", "", nil))).To(BeTrue())
+	g.Expect(IsErrorBadRequest(awserr.New("ValidationError", "", nil))).To(BeTrue())
+}
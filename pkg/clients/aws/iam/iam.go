@@ -2,14 +2,19 @@ package iam
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
-	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/iamiface"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	awsclients "github.com/crossplaneio/crossplane/pkg/clients/aws"
 )
 
+var observer = clients.NewObserver("aws/iam", awsclients.ClassifyError)
+
 const (
 	policyArn = "arn:aws:iam::%s:policy/%s"
 )
@@ -35,13 +40,15 @@ func NewClient(config *aws.Config) Client {
 }
 
 // CreateUser - Creates an IAM User, a policy, binds user to policy and returns an access key and policy version for the user.
-func (c *iamClient) CreateUser(username string) (*iam.AccessKey, error) {
-	err := c.createUser(username)
+func (c *iamClient) CreateUser(username string) (key *iam.AccessKey, err error) {
+	defer observer.Observe("CreateUser", time.Now(), &err)
+
+	err = c.createUser(username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user, %s", err)
 	}
 
-	key, err := c.createAccessKey(username)
+	key, err = c.createAccessKey(username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create access key, %s", err)
 	}
@@ -50,7 +57,9 @@ func (c *iamClient) CreateUser(username string) (*iam.AccessKey, error) {
 }
 
 // CreatePolicyAndAttach - Creates the IAM policy and attaches it to the username
-func (c *iamClient) CreatePolicyAndAttach(username string, policyName string, policyDocument string) (string, error) {
+func (c *iamClient) CreatePolicyAndAttach(username string, policyName string, policyDocument string) (version string, err error) {
+	defer observer.Observe("CreatePolicyAndAttach", time.Now(), &err)
+
 	currentVersion, err := c.createPolicy(username, policyDocument)
 	if err != nil {
 		return "", fmt.Errorf("failed to create policy, %s", err)
@@ -65,7 +74,9 @@ func (c *iamClient) CreatePolicyAndAttach(username string, policyName string, po
 }
 
 // GetPolicyVersion get the policy document for the IAM user
-func (c *iamClient) GetPolicyVersion(username string) (string, error) {
+func (c *iamClient) GetPolicyVersion(username string) (version string, err error) {
+	defer observer.Observe("GetPolicyVersion", time.Now(), &err)
+
 	policyARN, err := c.getPolicyARN(username)
 	if err != nil {
 		return "", err
@@ -83,7 +94,9 @@ func (c *iamClient) GetPolicyVersion(username string) (string, error) {
 }
 
 // UpdatePolicy - updates the policy document for the IAM user and return current policy version
-func (c *iamClient) UpdatePolicy(policyName string, policyDocument string) (string, error) {
+func (c *iamClient) UpdatePolicy(policyName string, policyDocument string) (version string, err error) {
+	defer observer.Observe("UpdatePolicy", time.Now(), &err)
+
 	policyARN, err := c.getPolicyARN(policyName)
 	if err != nil {
 		return "", err
@@ -114,26 +127,30 @@ func (c *iamClient) UpdatePolicy(policyName string, policyDocument string) (stri
 }
 
 // DeletePolicyAndDetach delete the policy of PolicyName and detach it from the username provided
-func (c *iamClient) DeletePolicyAndDetach(username string, policyName string) error {
+func (c *iamClient) DeletePolicyAndDetach(username string, policyName string) (err error) {
+	defer observer.Observe("DeletePolicyAndDetach", time.Now(), &err)
+
 	policyARN, err := c.getPolicyARN(username)
 	if err != nil {
 		return err
 	}
 
 	_, err = c.iam.DetachUserPolicyRequest(&iam.DetachUserPolicyInput{PolicyArn: aws.String(policyARN), UserName: aws.String(username)}).Send()
-	if err != nil && !isErrorNotFound(err) {
+	if err != nil && !awsclients.IsErrorNotFound(err) {
 		return err
 	}
 
 	_, err = c.iam.DeletePolicyRequest(&iam.DeletePolicyInput{PolicyArn: aws.String(policyARN)}).Send()
-	if err != nil && !isErrorNotFound(err) {
+	if err != nil && !awsclients.IsErrorNotFound(err) {
 		return err
 	}
 	return nil
 }
 
 // DeleteUser Policy and IAM User
-func (c *iamClient) DeleteUser(username string) error {
+func (c *iamClient) DeleteUser(username string) (err error) {
+	defer observer.Observe("DeleteUser", time.Now(), &err)
+
 	keys, err := c.iam.ListAccessKeysRequest(&iam.ListAccessKeysInput{UserName: aws.String(username)}).Send()
 	if err != nil {
 		return err
@@ -147,7 +164,7 @@ func (c *iamClient) DeleteUser(username string) error {
 	}
 
 	_, err = c.iam.DeleteUserRequest(&iam.DeleteUserInput{UserName: aws.String(username)}).Send()
-	if err != nil && !isErrorNotFound(err) {
+	if err != nil && !awsclients.IsErrorNotFound(err) {
 		return err
 	}
 
@@ -183,7 +200,7 @@ func (c *iamClient) getPolicyARN(policyName string) (string, error) {
 
 func (c *iamClient) createUser(username string) error {
 	_, err := c.iam.CreateUserRequest(&iam.CreateUserInput{UserName: aws.String(username)}).Send()
-	if err != nil && isErrorAlreadyExists(err) {
+	if err != nil && awsclients.IsErrorAlreadyExists(err) {
 		return nil
 	}
 	return err
@@ -201,7 +218,7 @@ func (c *iamClient) createAccessKey(username string) (*iam.AccessKey, error) {
 func (c *iamClient) createPolicy(policyName string, policyDocument string) (string, error) {
 	response, err := c.iam.CreatePolicyRequest(&iam.CreatePolicyInput{PolicyName: aws.String(policyName), PolicyDocument: aws.String(policyDocument)}).Send()
 	if err != nil {
-		if isErrorAlreadyExists(err) {
+		if awsclients.IsErrorAlreadyExists(err) {
 			return c.UpdatePolicy(policyName, policyDocument)
 		}
 		return "", err
@@ -218,20 +235,6 @@ func (c *iamClient) attachPolicyToUser(policyName string, username string) error
 	return err
 }
 
-func isErrorAlreadyExists(err error) bool {
-	if iamErr, ok := err.(awserr.Error); ok && iamErr.Code() == iam.ErrCodeEntityAlreadyExistsException {
-		return true
-	}
-	return false
-}
-
-func isErrorNotFound(err error) bool {
-	if iamErr, ok := err.(awserr.Error); ok && iamErr.Code() == iam.ErrCodeNoSuchEntityException {
-		return true
-	}
-	return false
-}
-
 // PolicyDocument is the structure of IAM policy document
 type PolicyDocument struct {
 	Version   string
@@ -17,28 +17,42 @@ limitations under the License.
 package fake
 
 import (
+	"context"
+
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/rds"
 )
 
 // MockRDSClient for testing.
 type MockRDSClient struct {
-	MockGetInstance    func(string) (*rds.Instance, error)
-	MockCreateInstance func(string, string, *v1alpha1.RDSInstanceSpec) (*rds.Instance, error)
-	MockDeleteInstance func(name string) (*rds.Instance, error)
+	MockGetInstance    func(context.Context, string) (*rds.Instance, error)
+	MockCreateInstance func(context.Context, string, string, *v1alpha1.RDSInstanceSpec) (*rds.Instance, error)
+	MockModifyInstance func(context.Context, *v1alpha1.RDSInstanceSpec, *rds.Instance) (*rds.Instance, error)
+	MockResetPassword  func(ctx context.Context, name, password string) (*rds.Instance, error)
+	MockDeleteInstance func(ctx context.Context, name, finalSnapshot string) (*rds.Instance, error)
 }
 
 // GetInstance finds RDS Instance by name
-func (m *MockRDSClient) GetInstance(name string) (*rds.Instance, error) {
-	return m.MockGetInstance(name)
+func (m *MockRDSClient) GetInstance(ctx context.Context, name string) (*rds.Instance, error) {
+	return m.MockGetInstance(ctx, name)
 }
 
 // CreateInstance creates RDS Instance with provided Specification
-func (m *MockRDSClient) CreateInstance(name, password string, spec *v1alpha1.RDSInstanceSpec) (*rds.Instance, error) {
-	return m.MockCreateInstance(name, password, spec)
+func (m *MockRDSClient) CreateInstance(ctx context.Context, name, password string, spec *v1alpha1.RDSInstanceSpec) (*rds.Instance, error) {
+	return m.MockCreateInstance(ctx, name, password, spec)
+}
+
+// ModifyInstance modifies RDS Instance to match the provided Specification
+func (m *MockRDSClient) ModifyInstance(ctx context.Context, spec *v1alpha1.RDSInstanceSpec, observed *rds.Instance) (*rds.Instance, error) {
+	return m.MockModifyInstance(ctx, spec, observed)
+}
+
+// ResetPassword changes the master user password of RDS Instance
+func (m *MockRDSClient) ResetPassword(ctx context.Context, name, password string) (*rds.Instance, error) {
+	return m.MockResetPassword(ctx, name, password)
 }
 
 // DeleteInstance deletes RDS Instance
-func (m *MockRDSClient) DeleteInstance(name string) (*rds.Instance, error) {
-	return m.MockDeleteInstance(name)
+func (m *MockRDSClient) DeleteInstance(ctx context.Context, name, finalSnapshot string) (*rds.Instance, error) {
+	return m.MockDeleteInstance(ctx, name, finalSnapshot)
 }
@@ -17,7 +17,9 @@ limitations under the License.
 package rds
 
 import (
+	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -25,14 +27,35 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/rds/rdsiface"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	awsclients "github.com/crossplaneio/crossplane/pkg/clients/aws"
 )
 
+var observer = clients.NewObserver("aws/rds", awsclients.ClassifyError)
+
 // Instance crossplane representation of the to AWS DBInstance
 type Instance struct {
-	Name     string
-	ARN      string
-	Status   string
-	Endpoint string
+	Name           string
+	ARN            string
+	Status         string
+	Endpoint       string
+	MasterUsername string
+	Class          string
+	Size           int64
+	EngineVersion  string
+	SecurityGroups []string
+
+	// Pending modifications that will be applied during the next
+	// maintenance window of the instance, if any.
+	Pending *PendingModifications
+}
+
+// PendingModifications of an AWS DBInstance. Empty fields are not pending
+// modification.
+type PendingModifications struct {
+	Class         string
+	Size          int64
+	EngineVersion string
 }
 
 // NewInstance returns new Instance structure
@@ -42,19 +65,44 @@ func NewInstance(instance *rds.DBInstance) *Instance {
 		endpoint = aws.StringValue(instance.Endpoint.Address)
 	}
 
-	return &Instance{
-		Name:     aws.StringValue(instance.DBInstanceIdentifier),
-		ARN:      aws.StringValue(instance.DBInstanceArn),
-		Status:   aws.StringValue(instance.DBInstanceStatus),
-		Endpoint: endpoint,
+	sgs := make([]string, 0, len(instance.VpcSecurityGroups))
+	for _, sg := range instance.VpcSecurityGroups {
+		sgs = append(sgs, aws.StringValue(sg.VpcSecurityGroupId))
 	}
+
+	i := &Instance{
+		Name:           aws.StringValue(instance.DBInstanceIdentifier),
+		ARN:            aws.StringValue(instance.DBInstanceArn),
+		Status:         aws.StringValue(instance.DBInstanceStatus),
+		Endpoint:       endpoint,
+		MasterUsername: aws.StringValue(instance.MasterUsername),
+		Class:          aws.StringValue(instance.DBInstanceClass),
+		Size:           aws.Int64Value(instance.AllocatedStorage),
+		EngineVersion:  aws.StringValue(instance.EngineVersion),
+		SecurityGroups: sgs,
+	}
+
+	if pv := instance.PendingModifiedValues; pv != nil {
+		p := &PendingModifications{
+			Class:         aws.StringValue(pv.DBInstanceClass),
+			Size:          aws.Int64Value(pv.AllocatedStorage),
+			EngineVersion: aws.StringValue(pv.EngineVersion),
+		}
+		if *p != (PendingModifications{}) {
+			i.Pending = p
+		}
+	}
+
+	return i
 }
 
 // Client defines RDS RDSClient operations
 type Client interface {
-	CreateInstance(string, string, *v1alpha1.RDSInstanceSpec) (*Instance, error)
-	GetInstance(name string) (*Instance, error)
-	DeleteInstance(name string) (*Instance, error)
+	CreateInstance(ctx context.Context, name, password string, spec *v1alpha1.RDSInstanceSpec) (*Instance, error)
+	GetInstance(ctx context.Context, name string) (*Instance, error)
+	ModifyInstance(ctx context.Context, spec *v1alpha1.RDSInstanceSpec, observed *Instance) (*Instance, error)
+	ResetPassword(ctx context.Context, name, password string) (*Instance, error)
+	DeleteInstance(ctx context.Context, name, finalSnapshot string) (*Instance, error)
 }
 
 type rdsClient struct {
@@ -67,10 +115,15 @@ func NewClient(config *aws.Config) Client {
 }
 
 // CreateInstance creates RDS Instance with provided Specification
-func (r *rdsClient) CreateInstance(name, password string, spec *v1alpha1.RDSInstanceSpec) (*Instance, error) {
+func (r *rdsClient) CreateInstance(ctx context.Context, name, password string, spec *v1alpha1.RDSInstanceSpec) (instance *Instance, err error) {
+	ctx, done := observer.Start(ctx, "CreateInstance")
+	defer done(&err)
+
 	input := CreateDBInstanceInput(name, password, spec)
 
-	output, err := r.rds.CreateDBInstanceRequest(input).Send()
+	req := r.rds.CreateDBInstanceRequest(input)
+	req.SetContext(ctx)
+	output, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
@@ -78,9 +131,14 @@ func (r *rdsClient) CreateInstance(name, password string, spec *v1alpha1.RDSInst
 }
 
 // GetInstance finds RDS Instance by name
-func (r *rdsClient) GetInstance(name string) (*Instance, error) {
+func (r *rdsClient) GetInstance(ctx context.Context, name string) (instance *Instance, err error) {
+	ctx, done := observer.Start(ctx, "GetInstance")
+	defer done(&err)
+
 	input := rds.DescribeDBInstancesInput{DBInstanceIdentifier: &name}
-	output, err := r.rds.DescribeDBInstancesRequest(&input).Send()
+	req := r.rds.DescribeDBInstancesRequest(&input)
+	req.SetContext(ctx)
+	output, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
@@ -93,29 +151,61 @@ func (r *rdsClient) GetInstance(name string) (*Instance, error) {
 	return NewInstance(&output.DBInstances[0]), nil
 }
 
-// DeleteInstance deletes RDS Instance
-func (r *rdsClient) DeleteInstance(name string) (*Instance, error) {
-	input := rds.DeleteDBInstanceInput{
-		DBInstanceIdentifier: &name,
-		SkipFinalSnapshot:    aws.Bool(true),
-	}
-	output, err := r.rds.DeleteDBInstanceRequest(&input).Send()
+// ModifyInstance modifies the fields of the supplied observed RDS Instance
+// that differ from the supplied specification.
+func (r *rdsClient) ModifyInstance(ctx context.Context, spec *v1alpha1.RDSInstanceSpec, observed *Instance) (instance *Instance, err error) {
+	ctx, done := observer.Start(ctx, "ModifyInstance")
+	defer done(&err)
+
+	req := r.rds.ModifyDBInstanceRequest(ModifyDBInstanceInput(spec, observed))
+	req.SetContext(ctx)
+	output, err := req.Send()
 	if err != nil {
 		return nil, err
 	}
-
 	return NewInstance(output.DBInstance), nil
 }
 
-// IsErrorAlreadyExists returns true if the supplied error indicates a cluster
-// does already exists.
-func IsErrorAlreadyExists(err error) bool {
-	return strings.Contains(err.Error(), rds.ErrCodeDBClusterAlreadyExistsFault)
+// ResetPassword immediately changes the master user password of RDS Instance
+func (r *rdsClient) ResetPassword(ctx context.Context, name, password string) (instance *Instance, err error) {
+	ctx, done := observer.Start(ctx, "ResetPassword")
+	defer done(&err)
+
+	input := rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(name),
+		MasterUserPassword:   aws.String(password),
+		ApplyImmediately:     aws.Bool(true),
+	}
+	req := r.rds.ModifyDBInstanceRequest(&input)
+	req.SetContext(ctx)
+	output, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return NewInstance(output.DBInstance), nil
 }
 
-// IsErrorNotFound helper function to test for ErrCodeDBInstanceNotFoundFault error
-func IsErrorNotFound(err error) bool {
-	return strings.Contains(err.Error(), rds.ErrCodeDBInstanceNotFoundFault)
+// DeleteInstance deletes RDS Instance. A final DB snapshot with the supplied
+// identifier is taken before the instance is deleted, unless it is empty.
+func (r *rdsClient) DeleteInstance(ctx context.Context, name, finalSnapshot string) (instance *Instance, err error) {
+	ctx, done := observer.Start(ctx, "DeleteInstance")
+	defer done(&err)
+
+	input := rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier: &name,
+		SkipFinalSnapshot:    aws.Bool(finalSnapshot == ""),
+	}
+	if finalSnapshot != "" {
+		input.FinalDBSnapshotIdentifier = aws.String(finalSnapshot)
+	}
+	req := r.rds.DeleteDBInstanceRequest(&input)
+	req.SetContext(ctx)
+	output, err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInstance(output.DBInstance), nil
 }
 
 // CreateDBInstanceInput from RDSInstanceSpec
@@ -134,3 +224,118 @@ func CreateDBInstanceInput(name, password string, spec *v1alpha1.RDSInstanceSpec
 		DBSubnetGroupName:     aws.String(spec.SubnetGroupName),
 	}
 }
+
+// ModifyDBInstanceInput from the fields of RDSInstanceSpec that differ from the
+// observed Instance. Modifications are applied during the next maintenance
+// window of the instance unless the spec asks for them to be applied
+// immediately.
+func ModifyDBInstanceInput(spec *v1alpha1.RDSInstanceSpec, observed *Instance) *rds.ModifyDBInstanceInput {
+	input := &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(observed.Name),
+		ApplyImmediately:     aws.Bool(spec.ApplyModificationsImmediately),
+	}
+	if classNeedsUpdate(spec, observed) {
+		input.DBInstanceClass = aws.String(spec.Class)
+	}
+	if sizeNeedsUpdate(spec, observed) {
+		input.AllocatedStorage = aws.Int64(spec.Size)
+	}
+	if engineVersionNeedsUpdate(spec, observed) {
+		input.EngineVersion = aws.String(spec.EngineVersion)
+		// RDS rejects upgrades to another major version unless they are
+		// explicitly allowed.
+		if observed.EngineVersion != "" && majorVersion(spec.Engine, spec.EngineVersion) != majorVersion(spec.Engine, observed.EngineVersion) {
+			input.AllowMajorVersionUpgrade = aws.Bool(true)
+		}
+	}
+	if securityGroupsNeedUpdate(spec, observed) {
+		input.VpcSecurityGroupIds = spec.SecurityGroups
+	}
+	return input
+}
+
+// NeedsUpdate returns true if the supplied RDSInstanceSpec differs from the
+// observed Instance. Modifications that are pending until the next maintenance
+// window of the instance are considered to be applied already.
+func NeedsUpdate(spec *v1alpha1.RDSInstanceSpec, observed *Instance) bool {
+	return classNeedsUpdate(spec, observed) ||
+		sizeNeedsUpdate(spec, observed) ||
+		engineVersionNeedsUpdate(spec, observed) ||
+		securityGroupsNeedUpdate(spec, observed)
+}
+
+func classNeedsUpdate(spec *v1alpha1.RDSInstanceSpec, observed *Instance) bool {
+	class := observed.Class
+	if p := observed.Pending; p != nil && p.Class != "" {
+		class = p.Class
+	}
+	return spec.Class != class
+}
+
+func sizeNeedsUpdate(spec *v1alpha1.RDSInstanceSpec, observed *Instance) bool {
+	size := observed.Size
+	if p := observed.Pending; p != nil && p.Size != 0 {
+		size = p.Size
+	}
+	return spec.Size != size
+}
+
+// IsStorageShrink returns true if the supplied RDSInstanceSpec asks for less
+// storage than is allocated to the observed Instance. RDS cannot shrink the
+// storage of an instance.
+func IsStorageShrink(spec *v1alpha1.RDSInstanceSpec, observed *Instance) bool {
+	size := observed.Size
+	if p := observed.Pending; p != nil && p.Size != 0 {
+		size = p.Size
+	}
+	return spec.Size < size
+}
+
+// majorVersion returns the major version of the supplied engine version, e.g.
+// 5.7 for MySQL 5.7.22, 9.6 for PostgreSQL 9.6.11 and 10 for PostgreSQL 10.6.
+func majorVersion(engine, version string) string {
+	parts := strings.Split(version, ".")
+	if n, err := strconv.Atoi(parts[0]); err == nil && n >= 10 && strings.HasPrefix(engine, "postgres") {
+		return parts[0]
+	}
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+func engineVersionNeedsUpdate(spec *v1alpha1.RDSInstanceSpec, observed *Instance) bool {
+	// AWS will choose and return a default version if we don't specify one.
+	if spec.EngineVersion == "" {
+		return false
+	}
+	version := observed.EngineVersion
+	if p := observed.Pending; p != nil && p.EngineVersion != "" {
+		version = p.EngineVersion
+	}
+	// A major version such as 5.7 matches any of its minor versions, which
+	// AWS may upgrade automatically.
+	return version != spec.EngineVersion && !strings.HasPrefix(version, spec.EngineVersion+".")
+}
+
+func securityGroupsNeedUpdate(spec *v1alpha1.RDSInstanceSpec, observed *Instance) bool {
+	// AWS will use the default security group of the VPC if we don't specify
+	// any.
+	if len(spec.SecurityGroups) == 0 {
+		return false
+	}
+	if len(spec.SecurityGroups) != len(observed.SecurityGroups) {
+		return true
+	}
+
+	sgs := map[string]bool{}
+	for _, sg := range observed.SecurityGroups {
+		sgs[sg] = true
+	}
+	for _, sg := range spec.SecurityGroups {
+		if !sgs[sg] {
+			return true
+		}
+	}
+	return false
+}
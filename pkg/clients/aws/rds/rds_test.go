@@ -0,0 +1,297 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/go-test/deep"
+
+	"github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+)
+
+const (
+	name          = "coolInstance"
+	class         = "db.t2.small"
+	size          = int64(20)
+	engineVersion = "5.7"
+	securityGroup = "sg-cool"
+)
+
+var spec = &v1alpha1.RDSInstanceSpec{
+	Class:          class,
+	Size:           size,
+	EngineVersion:  engineVersion,
+	SecurityGroups: []string{securityGroup},
+}
+
+func instance(m ...func(*Instance)) *Instance {
+	i := &Instance{
+		Name:           name,
+		Class:          class,
+		Size:           size,
+		EngineVersion:  engineVersion + ".22",
+		SecurityGroups: []string{securityGroup},
+	}
+	for _, mod := range m {
+		mod(i)
+	}
+	return i
+}
+
+func TestNewInstance(t *testing.T) {
+	cases := []struct {
+		name string
+		db   *rds.DBInstance
+		want *Instance
+	}{
+		{
+			name: "NoPendingModifications",
+			db: &rds.DBInstance{
+				DBInstanceIdentifier:  aws.String(name),
+				DBInstanceClass:       aws.String(class),
+				AllocatedStorage:      aws.Int64(size),
+				EngineVersion:         aws.String(engineVersion + ".22"),
+				VpcSecurityGroups:     []rds.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String(securityGroup)}},
+				PendingModifiedValues: &rds.PendingModifiedValues{},
+			},
+			want: instance(),
+		},
+		{
+			name: "PendingModifications",
+			db: &rds.DBInstance{
+				DBInstanceIdentifier:  aws.String(name),
+				DBInstanceClass:       aws.String(class),
+				AllocatedStorage:      aws.Int64(size),
+				EngineVersion:         aws.String(engineVersion + ".22"),
+				VpcSecurityGroups:     []rds.VpcSecurityGroupMembership{{VpcSecurityGroupId: aws.String(securityGroup)}},
+				PendingModifiedValues: &rds.PendingModifiedValues{AllocatedStorage: aws.Int64(size * 2)},
+			},
+			want: instance(func(i *Instance) { i.Pending = &PendingModifications{Size: size * 2} }),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NewInstance(tc.db)
+			if diff := deep.Equal(tc.want, got); diff != nil {
+				t.Errorf("NewInstance(...): want != got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNeedsUpdate(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     *v1alpha1.RDSInstanceSpec
+		observed *Instance
+		want     bool
+	}{
+		{
+			name:     "NeedsNoUpdate",
+			spec:     spec,
+			observed: instance(),
+			want:     false,
+		},
+		{
+			name:     "NeedsNewClass",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.Class = "db.t2.micro" }),
+			want:     true,
+		},
+		{
+			name:     "NeedsNewSize",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.Size = size / 2 }),
+			want:     true,
+		},
+		{
+			name:     "NeedsNewEngineVersion",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.EngineVersion = "5.6.40" }),
+			want:     true,
+		},
+		{
+			name:     "NeedsNewSecurityGroups",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.SecurityGroups = []string{"sg-uncool"} }),
+			want:     true,
+		},
+		{
+			// Modifications that are pending until the next maintenance
+			// window must not be requested again.
+			name: "NeedsNoUpdatePendingModifications",
+			spec: spec,
+			observed: instance(func(i *Instance) {
+				i.Class = "db.t2.micro"
+				i.Size = size / 2
+				i.Pending = &PendingModifications{Class: class, Size: size}
+			}),
+			want: false,
+		},
+		{
+			// AWS chooses an engine version and security group if we don't
+			// specify them.
+			name: "NeedsNoUpdateDefaultsAutoPopulated",
+			spec: &v1alpha1.RDSInstanceSpec{Class: class, Size: size},
+			observed: instance(func(i *Instance) {
+				i.EngineVersion = "5.6.40"
+				i.SecurityGroups = []string{"sg-default"}
+			}),
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := NeedsUpdate(tc.spec, tc.observed)
+			if got != tc.want {
+				t.Errorf("NeedsUpdate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestIsStorageShrink(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     *v1alpha1.RDSInstanceSpec
+		observed *Instance
+		want     bool
+	}{
+		{
+			name:     "Grow",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.Size = size / 2 }),
+			want:     false,
+		},
+		{
+			name:     "Shrink",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.Size = size * 2 }),
+			want:     true,
+		},
+		{
+			name: "ShrinkPending",
+			spec: spec,
+			observed: instance(func(i *Instance) {
+				i.Pending = &PendingModifications{Size: size * 2}
+			}),
+			want: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := IsStorageShrink(tc.spec, tc.observed)
+			if got != tc.want {
+				t.Errorf("IsStorageShrink(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestModifyDBInstanceInput(t *testing.T) {
+	cases := []struct {
+		name     string
+		spec     *v1alpha1.RDSInstanceSpec
+		observed *Instance
+		want     *rds.ModifyDBInstanceInput
+	}{
+		{
+			name:     "ModifySize",
+			spec:     spec,
+			observed: instance(func(i *Instance) { i.Size = size / 2 }),
+			want: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: aws.String(name),
+				ApplyImmediately:     aws.Bool(false),
+				AllocatedStorage:     aws.Int64(size),
+			},
+		},
+		{
+			name:     "ModifyMinorEngineVersion",
+			spec:     &v1alpha1.RDSInstanceSpec{Engine: "mysql", Class: class, Size: size, EngineVersion: "5.7.23"},
+			observed: instance(),
+			want: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: aws.String(name),
+				ApplyImmediately:     aws.Bool(false),
+				EngineVersion:        aws.String("5.7.23"),
+			},
+		},
+		{
+			name:     "ModifyMajorEngineVersion",
+			spec:     &v1alpha1.RDSInstanceSpec{Engine: "mysql", Class: class, Size: size, EngineVersion: "8.0"},
+			observed: instance(),
+			want: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:     aws.String(name),
+				ApplyImmediately:         aws.Bool(false),
+				EngineVersion:            aws.String("8.0"),
+				AllowMajorVersionUpgrade: aws.Bool(true),
+			},
+		},
+		{
+			name:     "ModifyMajorPostgresEngineVersion",
+			spec:     &v1alpha1.RDSInstanceSpec{Engine: "postgres", Class: class, Size: size, EngineVersion: "11.1"},
+			observed: instance(func(i *Instance) { i.EngineVersion = "10.6" }),
+			want: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier:     aws.String(name),
+				ApplyImmediately:         aws.Bool(false),
+				EngineVersion:            aws.String("11.1"),
+				AllowMajorVersionUpgrade: aws.Bool(true),
+			},
+		},
+		{
+			name:     "ModifyMinorPostgresEngineVersion",
+			spec:     &v1alpha1.RDSInstanceSpec{Engine: "postgres", Class: class, Size: size, EngineVersion: "10.6"},
+			observed: instance(func(i *Instance) { i.EngineVersion = "10.5" }),
+			want: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: aws.String(name),
+				ApplyImmediately:     aws.Bool(false),
+				EngineVersion:        aws.String("10.6"),
+			},
+		},
+		{
+			name: "ModifyAllImmediately",
+			spec: func() *v1alpha1.RDSInstanceSpec {
+				s := spec.DeepCopy()
+				s.ApplyModificationsImmediately = true
+				return s
+			}(),
+			observed: &Instance{Name: name},
+			want: &rds.ModifyDBInstanceInput{
+				DBInstanceIdentifier: aws.String(name),
+				ApplyImmediately:     aws.Bool(true),
+				DBInstanceClass:      aws.String(class),
+				AllocatedStorage:     aws.Int64(size),
+				EngineVersion:        aws.String(engineVersion),
+				VpcSecurityGroupIds:  []string{securityGroup},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ModifyDBInstanceInput(tc.spec, tc.observed)
+			if diff := deep.Equal(tc.want, got); diff != nil {
+				t.Errorf("ModifyDBInstanceInput(...): want != got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package s3
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/awserr"
@@ -12,10 +13,14 @@ import (
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
 	storage "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	awsclients "github.com/crossplaneio/crossplane/pkg/clients/aws"
 	iamc "github.com/crossplaneio/crossplane/pkg/clients/aws/iam"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
+var observer = clients.NewObserver("aws/s3", awsclients.ClassifyError)
+
 const (
 	bucketUser           = "crossplane-bucket-%s"
 	bucketObjectARN      = "arn:aws:s3:::%s"
@@ -46,9 +51,11 @@ func NewClient(config *aws.Config) Service {
 
 // CreateOrUpdateBucket creates or updates the supplied S3 bucket with provided
 // specification, and returns access keys with permissions of localPermission
-func (c *Client) CreateOrUpdateBucket(spec *v1alpha1.S3BucketSpec) error {
+func (c *Client) CreateOrUpdateBucket(spec *v1alpha1.S3BucketSpec) (err error) {
+	defer observer.Observe("CreateOrUpdateBucket", time.Now(), &err)
+
 	input := CreateBucketInput(spec)
-	_, err := c.s3.CreateBucketRequest(input).Send()
+	_, err = c.s3.CreateBucketRequest(input).Send()
 	if err != nil {
 		if isErrorAlreadyExists(err) {
 			return c.UpdateBucketACL(spec)
@@ -64,7 +71,9 @@ type Bucket struct {
 }
 
 // GetBucketInfo returns the status of key bucket settings including user's policy version for permission status
-func (c *Client) GetBucketInfo(username string, spec *v1alpha1.S3BucketSpec) (*Bucket, error) {
+func (c *Client) GetBucketInfo(username string, spec *v1alpha1.S3BucketSpec) (info *Bucket, err error) {
+	defer observer.Observe("GetBucketInfo", time.Now(), &err)
+
 	bucket := Bucket{}
 	bucketVersioning, err := c.s3.GetBucketVersioningRequest(&s3.GetBucketVersioningInput{Bucket: aws.String(spec.Name)}).Send()
 	if err != nil {
@@ -100,8 +109,9 @@ func (c *Client) CreateUser(username string, spec *v1alpha1.S3BucketSpec) (*iam.
 }
 
 // UpdateBucketACL - Updated CannedACL on Bucket
-func (c *Client) UpdateBucketACL(spec *v1alpha1.S3BucketSpec) error {
-	var err error
+func (c *Client) UpdateBucketACL(spec *v1alpha1.S3BucketSpec) (err error) {
+	defer observer.Observe("UpdateBucketACL", time.Now(), &err)
+
 	if spec.CannedACL != nil {
 		input := &s3.PutBucketAclInput{
 			ACL:    *spec.CannedACL,
@@ -114,14 +124,16 @@ func (c *Client) UpdateBucketACL(spec *v1alpha1.S3BucketSpec) error {
 }
 
 // UpdateVersioning configuration for Bucket
-func (c *Client) UpdateVersioning(spec *v1alpha1.S3BucketSpec) error {
+func (c *Client) UpdateVersioning(spec *v1alpha1.S3BucketSpec) (err error) {
+	defer observer.Observe("UpdateVersioning", time.Now(), &err)
+
 	versioningStatus := s3.BucketVersioningStatusSuspended
 	if spec.Versioning {
 		versioningStatus = s3.BucketVersioningStatusEnabled
 	}
 
 	input := &s3.PutBucketVersioningInput{Bucket: &spec.Name, VersioningConfiguration: &s3.VersioningConfiguration{Status: versioningStatus}}
-	_, err := c.s3.PutBucketVersioningRequest(input).Send()
+	_, err = c.s3.PutBucketVersioningRequest(input).Send()
 	if err != nil {
 		return err
 	}
@@ -142,12 +154,14 @@ func (c *Client) UpdatePolicyDocument(username string, spec *v1alpha1.S3BucketSp
 }
 
 // DeleteBucket deletes s3 bucket, and related IAM
-func (c *Client) DeleteBucket(bucket *v1alpha1.S3Bucket) error {
+func (c *Client) DeleteBucket(bucket *v1alpha1.S3Bucket) (err error) {
+	defer observer.Observe("DeleteBucket", time.Now(), &err)
+
 	input := &s3.DeleteBucketInput{
 		Bucket: &bucket.Spec.Name,
 	}
-	_, err := c.s3.DeleteBucketRequest(input).Send()
-	if err != nil && !isErrorNotFound(err) {
+	_, err = c.s3.DeleteBucketRequest(input).Send()
+	if err != nil && !awsclients.IsErrorNotFound(err) {
 		return err
 	}
 
@@ -171,14 +185,6 @@ func isErrorAlreadyExists(err error) bool {
 	return false
 }
 
-// isErrorNotFound helper function to test for ErrCodeNoSuchEntityException error
-func isErrorNotFound(err error) bool {
-	if bucketErr, ok := err.(awserr.Error); ok && bucketErr.Code() == s3.ErrCodeNoSuchBucket {
-		return true
-	}
-	return false
-}
-
 // CreateBucketInput returns a CreateBucketInput from the supplied S3BucketSpec.
 func CreateBucketInput(spec *v1alpha1.S3BucketSpec) *s3.CreateBucketInput {
 	bucketInput := &s3.CreateBucketInput{
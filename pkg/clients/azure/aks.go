@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
 	"github.com/Azure/go-autorest/autorest/to"
@@ -27,6 +28,7 @@ import (
 
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
 )
 
 const (
@@ -37,6 +39,8 @@ const (
 	maxClusterNameLen = 31
 )
 
+var aksObserver = clients.NewObserver("azure/aks", ClassifyError)
+
 // AKSSetupClient is a type that implements all of the AKS setup interface
 type AKSSetupClient struct {
 	AKSClusterAPI
@@ -106,12 +110,17 @@ func NewAKSClusterClient(provider *v1alpha1.Provider, clientset kubernetes.Inter
 }
 
 // Get returns the AKS cluster details for the given instance
-func (c *AKSClusterClient) Get(ctx context.Context, instance computev1alpha1.AKSCluster) (containerservice.ManagedCluster, error) {
+func (c *AKSClusterClient) Get(ctx context.Context, instance computev1alpha1.AKSCluster) (_ containerservice.ManagedCluster, err error) {
+	ctx, done := aksObserver.Start(ctx, "Get")
+	defer done(&err)
 	return c.ManagedClustersClient.Get(ctx, instance.Spec.ResourceGroupName, instance.Status.ClusterName)
 }
 
 // CreateOrUpdateBegin begins the create/update operation for a AKS Cluster with the given properties
-func (c *AKSClusterClient) CreateOrUpdateBegin(ctx context.Context, instance computev1alpha1.AKSCluster, clusterName, appID, spSecret string) ([]byte, error) {
+func (c *AKSClusterClient) CreateOrUpdateBegin(ctx context.Context, instance computev1alpha1.AKSCluster, clusterName, appID, spSecret string) (_ []byte, err error) {
+	ctx, done := aksObserver.Start(ctx, "CreateOrUpdateBegin")
+	defer done(&err)
+
 	spec := instance.Spec
 
 	enableRBAC := !spec.DisableRBAC
@@ -158,6 +167,8 @@ func (c *AKSClusterClient) CreateOrUpdateBegin(ctx context.Context, instance com
 
 // CreateOrUpdateEnd checks to see if the given create/update operation is completed and if any error has occurred.
 func (c *AKSClusterClient) CreateOrUpdateEnd(op []byte) (done bool, err error) {
+	defer aksObserver.Observe("CreateOrUpdateEnd", time.Now(), &err)
+
 	// unmarshal the given create complete data into a future object
 	future := &containerservice.ManagedClustersCreateOrUpdateFuture{}
 	if err = future.UnmarshalJSON(op); err != nil {
@@ -179,12 +190,16 @@ func (c *AKSClusterClient) CreateOrUpdateEnd(op []byte) (done bool, err error) {
 }
 
 // Delete begins the deletion operator for the given AKS cluster instance
-func (c *AKSClusterClient) Delete(ctx context.Context, instance computev1alpha1.AKSCluster) (containerservice.ManagedClustersDeleteFuture, error) {
+func (c *AKSClusterClient) Delete(ctx context.Context, instance computev1alpha1.AKSCluster) (_ containerservice.ManagedClustersDeleteFuture, err error) {
+	ctx, done := aksObserver.Start(ctx, "Delete")
+	defer done(&err)
 	return c.ManagedClustersClient.Delete(ctx, instance.Spec.ResourceGroupName, instance.Status.ClusterName)
 }
 
 // ListClusterAdminCredentials will return the admin credentials used to connect to the given AKS cluster
-func (c *AKSClusterClient) ListClusterAdminCredentials(ctx context.Context, instance computev1alpha1.AKSCluster) (containerservice.CredentialResults, error) {
+func (c *AKSClusterClient) ListClusterAdminCredentials(ctx context.Context, instance computev1alpha1.AKSCluster) (_ containerservice.CredentialResults, err error) {
+	ctx, done := aksObserver.Start(ctx, "ListClusterAdminCredentials")
+	defer done(&err)
 	return c.ManagedClustersClient.ListClusterAdminCredentials(ctx, instance.Spec.ResourceGroupName, instance.Status.ClusterName)
 }
 
@@ -20,7 +20,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2018-05-01/resources"
 	"github.com/Azure/go-autorest/autorest"
@@ -122,21 +121,6 @@ func ValidateClient(client *Client) error {
 	return err
 }
 
-// IsNotFound returns a value indicating whether the given error represents that the resource was not found.
-func IsNotFound(err error) bool {
-	detailedError, ok := err.(autorest.DetailedError)
-	if !ok {
-		return false
-	}
-
-	statusCode, ok := detailedError.StatusCode.(int)
-	if !ok {
-		return false
-	}
-
-	return statusCode == http.StatusNotFound
-}
-
 // ToStringPtr converts the supplied string for use with the Azure Go SDK.
 func ToStringPtr(s string, o ...FieldOption) *string {
 	for _, fo := range o {
@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest"
+	azurerest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+)
+
+// ClassifyError returns the class of the supplied error returned by the Azure
+// SDK. Errors are classified by the code of the Azure service error, falling
+// back to the HTTP status code of the failed request.
+func ClassifyError(err error) clients.ErrorClass {
+	detailedError, ok := errors.Cause(err).(autorest.DetailedError)
+	if !ok {
+		return clients.ErrorUnknown
+	}
+
+	if requestError, ok := detailedError.Original.(*azurerest.RequestError); ok && requestError.ServiceError != nil {
+		if c := classifyCode(requestError.ServiceError.Code); c != clients.ErrorUnknown {
+			return c
+		}
+	}
+
+	statusCode, ok := detailedError.StatusCode.(int)
+	if !ok {
+		return clients.ErrorUnknown
+	}
+	return clients.ClassifyStatusCode(statusCode)
+}
+
+// classifyCode returns the class of the supplied Azure service error code,
+// e.g. ResourceGroupNotFound or AuthorizationFailed.
+func classifyCode(code string) clients.ErrorClass {
+	switch {
+	case strings.Contains(code, "NotFound"):
+		return clients.ErrorNotFound
+	case strings.Contains(code, "AlreadyExists"):
+		return clients.ErrorAlreadyExists
+	case strings.Contains(code, "Throttl") || code == "TooManyRequests":
+		return clients.ErrorThrottled
+	case strings.Contains(code, "QuotaExceeded"):
+		return clients.ErrorQuotaExceeded
+	case strings.Contains(code, "Authorization") || strings.Contains(code, "Authentication"):
+		return clients.ErrorPermissionDenied
+	case strings.HasPrefix(code, "Invalid") || code == "BadRequest":
+		return clients.ErrorInvalidInput
+	case code == "InternalServerError" || code == "ServiceUnavailable":
+		return clients.ErrorTransient
+	}
+	return clients.ErrorUnknown
+}
+
+// IsNotFound returns a value indicating whether the given error represents that the resource was not found.
+func IsNotFound(err error) bool {
+	return ClassifyError(err) == clients.ErrorNotFound
+}
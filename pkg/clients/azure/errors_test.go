@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	azurerest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+)
+
+func TestClassifyError(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	serviceError := func(code string, status int) error {
+		return autorest.DetailedError{
+			StatusCode: status,
+			Original:   &azurerest.RequestError{ServiceError: &azurerest.ServiceError{Code: code}},
+		}
+	}
+
+	cases := []struct {
+		err      error
+		expected clients.ErrorClass
+	}{
+		{nil, clients.ErrorUnknown},
+		{fmt.Errorf("test-error"), clients.ErrorUnknown},
+		{autorest.DetailedError{}, clients.ErrorUnknown},
+		{autorest.DetailedError{StatusCode: http.StatusNotFound}, clients.ErrorNotFound},
+		{autorest.DetailedError{StatusCode: http.StatusTooManyRequests}, clients.ErrorThrottled},
+		{serviceError("ResourceGroupNotFound", http.StatusNotFound), clients.ErrorNotFound},
+		{serviceError("AuthorizationFailed", http.StatusForbidden), clients.ErrorPermissionDenied},
+		{serviceError("QuotaExceeded", http.StatusConflict), clients.ErrorQuotaExceeded},
+		{serviceError("InvalidParameter", http.StatusBadRequest), clients.ErrorInvalidInput},
+		{serviceError("SomethingUnexpected", http.StatusServiceUnavailable), clients.ErrorTransient},
+		{errors.Wrap(autorest.DetailedError{StatusCode: http.StatusConflict}, "wrapped"), clients.ErrorAlreadyExists},
+	}
+
+	for _, tt := range cases {
+		g.Expect(ClassifyError(tt.err)).To(gomega.Equal(tt.expected), "error %v", tt.err)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cases := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{autorest.DetailedError{}, false},
+		{autorest.DetailedError{StatusCode: http.StatusNotFound}, true},
+	}
+
+	for _, tt := range cases {
+		actual := IsNotFound(tt.err)
+		g.Expect(actual).To(gomega.Equal(tt.expected))
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
@@ -39,6 +40,8 @@ const (
 	urlSaltDataLen     = 3
 )
 
+var graphObserver = clients.NewObserver("azure/graph", ClassifyError)
+
 //---------------------------------------------------------------------------------------------------------------------
 // Azure Application API interfaces and clients
 
@@ -82,7 +85,10 @@ func NewApplicationClient(provider *v1alpha1.Provider, clientset kubernetes.Inte
 }
 
 // CreateApplication creates a new AD application with the given parameters
-func (c *ApplicationClient) CreateApplication(ctx context.Context, appParams ApplicationParameters) (*graphrbac.Application, error) {
+func (c *ApplicationClient) CreateApplication(ctx context.Context, appParams ApplicationParameters) (_ *graphrbac.Application, err error) {
+	ctx, done := graphObserver.Start(ctx, "CreateApplication")
+	defer done(&err)
+
 	if appParams.ObjectID != "" {
 		// the caller has already created the app, fetch and return it
 		app, err := c.ApplicationsClient.Get(ctx, appParams.ObjectID)
@@ -127,8 +133,11 @@ func (c *ApplicationClient) CreateApplication(ctx context.Context, appParams App
 }
 
 // DeleteApplication will delete the given AD application
-func (c *ApplicationClient) DeleteApplication(ctx context.Context, appObjectID string) error {
-	_, err := c.ApplicationsClient.Delete(ctx, appObjectID)
+func (c *ApplicationClient) DeleteApplication(ctx context.Context, appObjectID string) (err error) {
+	ctx, done := graphObserver.Start(ctx, "DeleteApplication")
+	defer done(&err)
+
+	_, err = c.ApplicationsClient.Delete(ctx, appObjectID)
 	return err
 }
 
@@ -166,7 +175,10 @@ func NewServicePrincipalClient(provider *v1alpha1.Provider, clientset kubernetes
 }
 
 // CreateServicePrincipal creates a new service principal linked to the given AD application
-func (c *ServicePrincipalClient) CreateServicePrincipal(ctx context.Context, spID, appID string) (*graphrbac.ServicePrincipal, error) {
+func (c *ServicePrincipalClient) CreateServicePrincipal(ctx context.Context, spID, appID string) (_ *graphrbac.ServicePrincipal, err error) {
+	ctx, done := graphObserver.Start(ctx, "CreateServicePrincipal")
+	defer done(&err)
+
 	if spID != "" {
 		// the caller has already created the service principal, fetch and return it
 		sp, err := c.ServicePrincipalsClient.Get(ctx, spID)
@@ -187,8 +199,11 @@ func (c *ServicePrincipalClient) CreateServicePrincipal(ctx context.Context, spI
 }
 
 // DeleteServicePrincipal will delete the given service principal
-func (c *ServicePrincipalClient) DeleteServicePrincipal(ctx context.Context, spID string) error {
-	_, err := c.ServicePrincipalsClient.Delete(ctx, spID)
+func (c *ServicePrincipalClient) DeleteServicePrincipal(ctx context.Context, spID string) (err error) {
+	ctx, done := graphObserver.Start(ctx, "DeleteServicePrincipal")
+	defer done(&err)
+
+	_, err = c.ServicePrincipalsClient.Delete(ctx, spID)
 	return err
 }
 
@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
 	"github.com/crossplaneio/crossplane/pkg/clients/azure"
 )
 
@@ -64,7 +65,45 @@ func NewClient(ctx context.Context, credentials []byte) (Client, error) {
 		return nil, errors.Wrap(err, "cannot add to Azure client user agent")
 	}
 
-	return client, nil
+	return &observedClient{ClientAPI: client}, nil
+}
+
+var observer = clients.NewObserver("azure/redis", azure.ClassifyError)
+
+// observedClient records metrics about, traces and logs the requests made by the
+// methods of the upstream client that are used by Crossplane.
+type observedClient struct {
+	redisapi.ClientAPI
+}
+
+func (c *observedClient) Create(ctx context.Context, resourceGroupName string, name string, parameters redismgmt.CreateParameters) (result redismgmt.CreateFuture, err error) {
+	ctx, done := observer.Start(ctx, "Create")
+	defer done(&err)
+	return c.ClientAPI.Create(ctx, resourceGroupName, name, parameters)
+}
+
+func (c *observedClient) Delete(ctx context.Context, resourceGroupName string, name string) (result redismgmt.DeleteFuture, err error) {
+	ctx, done := observer.Start(ctx, "Delete")
+	defer done(&err)
+	return c.ClientAPI.Delete(ctx, resourceGroupName, name)
+}
+
+func (c *observedClient) Get(ctx context.Context, resourceGroupName string, name string) (result redismgmt.ResourceType, err error) {
+	ctx, done := observer.Start(ctx, "Get")
+	defer done(&err)
+	return c.ClientAPI.Get(ctx, resourceGroupName, name)
+}
+
+func (c *observedClient) ListKeys(ctx context.Context, resourceGroupName string, name string) (result redismgmt.AccessKeys, err error) {
+	ctx, done := observer.Start(ctx, "ListKeys")
+	defer done(&err)
+	return c.ClientAPI.ListKeys(ctx, resourceGroupName, name)
+}
+
+func (c *observedClient) Update(ctx context.Context, resourceGroupName string, name string, parameters redismgmt.UpdateParameters) (result redismgmt.ResourceType, err error) {
+	ctx, done := observer.Start(ctx, "Update")
+	defer done(&err)
+	return c.ClientAPI.Update(ctx, resourceGroupName, name, parameters)
 }
 
 // NewResourceName returns a resource name used to identify a Redis resource in
@@ -20,22 +20,30 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/azure-sdk-for-go/services/postgresql/mgmt/2017-12-01/postgresql"
 	azurerest "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/date"
 	"github.com/Azure/go-autorest/autorest/to"
 	"k8s.io/client-go/kubernetes"
 
 	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
 )
 
 const (
 	backupRetentionDaysDefault = int32(7)
 )
 
+var (
+	mySQLObserver      = clients.NewObserver("azure/mysql", ClassifyError)
+	postgreSQLObserver = clients.NewObserver("azure/postgresql", ClassifyError)
+)
+
 var (
 	skuShortTiers = map[mysql.SkuTier]string{
 		mysql.Basic:           "B",
@@ -56,6 +64,7 @@ type SQLServerAPI interface {
 	GetServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (*SQLServer, error)
 	CreateServerBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) ([]byte, error)
 	CreateServerEnd(createOp []byte) (bool, error)
+	CreateFinalSnapshotBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, name, sourceID string, restorePoint time.Time) ([]byte, error)
 	DeleteServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error)
 	GetFirewallRule(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) (err error)
 	CreateFirewallRulesBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) ([]byte, error)
@@ -93,7 +102,10 @@ func NewMySQLServerClient(provider *v1alpha1.Provider, clientset kubernetes.Inte
 }
 
 // GetServer retrieves the requested MySQL Server
-func (c *MySQLServerClient) GetServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (*SQLServer, error) {
+func (c *MySQLServerClient) GetServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (_ *SQLServer, err error) {
+	ctx, done := mySQLObserver.Start(ctx, "GetServer")
+	defer done(&err)
+
 	server, err := c.ServersClient.Get(ctx, instance.GetSpec().ResourceGroupName, instance.GetName())
 	if err != nil {
 		return nil, err
@@ -114,7 +126,10 @@ func (c *MySQLServerClient) GetServer(ctx context.Context, instance azuredbv1alp
 
 // CreateServerBegin begins the create operation for a MySQL Server with the
 // given properties.
-func (c *MySQLServerClient) CreateServerBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) ([]byte, error) {
+func (c *MySQLServerClient) CreateServerBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) (_ []byte, err error) {
+	ctx, done := mySQLObserver.Start(ctx, "CreateServerBegin")
+	defer done(&err)
+
 	spec := instance.GetSpec()
 
 	// initialize all the parameters that specify how to configure the server during creation
@@ -168,6 +183,8 @@ func (c *MySQLServerClient) CreateServerBegin(ctx context.Context, instance azur
 // CreateServerEnd checks to see if the given create operation is completed and
 // if any error has occurred.
 func (c *MySQLServerClient) CreateServerEnd(createOp []byte) (done bool, err error) {
+	defer mySQLObserver.Observe("CreateServerEnd", time.Now(), &err)
+
 	// unmarshal the given create complete data into a future object
 	createFuture := &mysql.ServersCreateFuture{}
 	if err = createFuture.UnmarshalJSON(createOp); err != nil {
@@ -188,20 +205,71 @@ func (c *MySQLServerClient) CreateServerEnd(createOp []byte) (done bool, err err
 	return true, nil
 }
 
+// CreateFinalSnapshotBegin begins the point in time restore of the given MySQL Server
+// at the given restore point to a new server with the given name. The completion
+// of the returned operation can be checked with CreateServerEnd.
+func (c *MySQLServerClient) CreateFinalSnapshotBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, name, sourceID string, restorePoint time.Time) (_ []byte, err error) {
+	ctx, done := mySQLObserver.Start(ctx, "CreateFinalSnapshotBegin")
+	defer done(&err)
+
+	spec := instance.GetSpec()
+
+	skuName, err := SQLServerSkuName(spec.PricingTier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server SKU name: %+v", err)
+	}
+	capacity := int32(spec.PricingTier.VCores)
+	createParams := mysql.ServerForCreate{
+		Sku: &mysql.Sku{
+			Name:     &skuName,
+			Tier:     mysql.SkuTier(spec.PricingTier.Tier),
+			Capacity: &capacity,
+			Family:   &spec.PricingTier.Family,
+		},
+		Properties: &mysql.ServerPropertiesForRestore{
+			SourceServerID:     &sourceID,
+			RestorePointInTime: &date.Time{Time: restorePoint},
+			CreateMode:         mysql.CreateModePointInTimeRestore,
+		},
+		Location: &spec.Location,
+	}
+
+	createFuture, err := c.Create(ctx, spec.ResourceGroupName, name, createParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// serialize the create operation
+	createFutureJSON, err := createFuture.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return createFutureJSON, nil
+}
+
 // DeleteServer deletes the given MySQLServer resource
-func (c *MySQLServerClient) DeleteServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error) {
+func (c *MySQLServerClient) DeleteServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (_ azurerest.Future, err error) {
+	ctx, done := mySQLObserver.Start(ctx, "DeleteServer")
+	defer done(&err)
+
 	result, err := c.ServersClient.Delete(ctx, instance.GetSpec().ResourceGroupName, instance.GetName())
 	return result.Future, err
 }
 
 // GetFirewallRule gets the given firewall rule
-func (c *MySQLServerClient) GetFirewallRule(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) error {
-	_, err := c.FirewallRulesClient.Get(ctx, instance.GetSpec().ResourceGroupName, instance.GetName(), firewallRuleName)
+func (c *MySQLServerClient) GetFirewallRule(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) (err error) {
+	ctx, done := mySQLObserver.Start(ctx, "GetFirewallRule")
+	defer done(&err)
+
+	_, err = c.FirewallRulesClient.Get(ctx, instance.GetSpec().ResourceGroupName, instance.GetName(), firewallRuleName)
 	return err
 }
 
 // CreateFirewallRulesBegin begins the create operation for a firewall rule
-func (c *MySQLServerClient) CreateFirewallRulesBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) ([]byte, error) {
+func (c *MySQLServerClient) CreateFirewallRulesBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) (_ []byte, err error) {
+	ctx, done := mySQLObserver.Start(ctx, "CreateFirewallRulesBegin")
+	defer done(&err)
 
 	createParams := mysql.FirewallRule{
 		Name: to.StringPtr(firewallRuleName),
@@ -230,6 +298,8 @@ func (c *MySQLServerClient) CreateFirewallRulesBegin(ctx context.Context, instan
 
 // CreateFirewallRulesEnd checks to see if the given create operation is completed and if any error has occurred.
 func (c *MySQLServerClient) CreateFirewallRulesEnd(createOp []byte) (done bool, err error) {
+	defer mySQLObserver.Observe("CreateFirewallRulesEnd", time.Now(), &err)
+
 	// unmarshal the given create complete data into a future object
 	createFuture := &mysql.FirewallRulesCreateOrUpdateFuture{}
 	if err = createFuture.UnmarshalJSON(createOp); err != nil {
@@ -278,7 +348,10 @@ func NewPostgreSQLServerClient(provider *v1alpha1.Provider, clientset kubernetes
 }
 
 // GetServer retrieves the requested PostgreSQL Server
-func (c *PostgreSQLServerClient) GetServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (*SQLServer, error) {
+func (c *PostgreSQLServerClient) GetServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (_ *SQLServer, err error) {
+	ctx, done := postgreSQLObserver.Start(ctx, "GetServer")
+	defer done(&err)
+
 	server, err := c.ServersClient.Get(ctx, instance.GetSpec().ResourceGroupName, instance.GetName())
 	if err != nil {
 		return nil, err
@@ -298,7 +371,10 @@ func (c *PostgreSQLServerClient) GetServer(ctx context.Context, instance azuredb
 }
 
 // CreateServerBegin begins the create operation for a PostgreSQL Server with the given properties
-func (c *PostgreSQLServerClient) CreateServerBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) ([]byte, error) {
+func (c *PostgreSQLServerClient) CreateServerBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) (_ []byte, err error) {
+	ctx, done := postgreSQLObserver.Start(ctx, "CreateServerBegin")
+	defer done(&err)
+
 	spec := instance.GetSpec()
 
 	// initialize all the parameters that specify how to configure the server during creation
@@ -351,6 +427,8 @@ func (c *PostgreSQLServerClient) CreateServerBegin(ctx context.Context, instance
 
 // CreateServerEnd checks to see if the given create operation is completed and if any error has occurred.
 func (c *PostgreSQLServerClient) CreateServerEnd(createOp []byte) (done bool, err error) {
+	defer postgreSQLObserver.Observe("CreateServerEnd", time.Now(), &err)
+
 	// unmarshal the given create complete data into a future object
 	createFuture := &postgresql.ServersCreateFuture{}
 	if err = createFuture.UnmarshalJSON(createOp); err != nil {
@@ -371,20 +449,71 @@ func (c *PostgreSQLServerClient) CreateServerEnd(createOp []byte) (done bool, er
 	return true, nil
 }
 
+// CreateFinalSnapshotBegin begins the point in time restore of the given PostgreSQL Server
+// at the given restore point to a new server with the given name. The completion
+// of the returned operation can be checked with CreateServerEnd.
+func (c *PostgreSQLServerClient) CreateFinalSnapshotBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, name, sourceID string, restorePoint time.Time) (_ []byte, err error) {
+	ctx, done := postgreSQLObserver.Start(ctx, "CreateFinalSnapshotBegin")
+	defer done(&err)
+
+	spec := instance.GetSpec()
+
+	skuName, err := SQLServerSkuName(spec.PricingTier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server SKU name: %+v", err)
+	}
+	capacity := int32(spec.PricingTier.VCores)
+	createParams := postgresql.ServerForCreate{
+		Sku: &postgresql.Sku{
+			Name:     &skuName,
+			Tier:     postgresql.SkuTier(spec.PricingTier.Tier),
+			Capacity: &capacity,
+			Family:   &spec.PricingTier.Family,
+		},
+		Properties: &postgresql.ServerPropertiesForRestore{
+			SourceServerID:     &sourceID,
+			RestorePointInTime: &date.Time{Time: restorePoint},
+			CreateMode:         postgresql.CreateModePointInTimeRestore,
+		},
+		Location: &spec.Location,
+	}
+
+	createFuture, err := c.Create(ctx, spec.ResourceGroupName, name, createParams)
+	if err != nil {
+		return nil, err
+	}
+
+	// serialize the create operation
+	createFutureJSON, err := createFuture.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return createFutureJSON, nil
+}
+
 // DeleteServer deletes the given PostgreSQL resource
-func (c *PostgreSQLServerClient) DeleteServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error) {
+func (c *PostgreSQLServerClient) DeleteServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (_ azurerest.Future, err error) {
+	ctx, done := postgreSQLObserver.Start(ctx, "DeleteServer")
+	defer done(&err)
+
 	result, err := c.ServersClient.Delete(ctx, instance.GetSpec().ResourceGroupName, instance.GetName())
 	return result.Future, err
 }
 
 // GetFirewallRule gets the given firewall rule
-func (c *PostgreSQLServerClient) GetFirewallRule(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) error {
-	_, err := c.FirewallRulesClient.Get(ctx, instance.GetSpec().ResourceGroupName, instance.GetName(), firewallRuleName)
+func (c *PostgreSQLServerClient) GetFirewallRule(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) (err error) {
+	ctx, done := postgreSQLObserver.Start(ctx, "GetFirewallRule")
+	defer done(&err)
+
+	_, err = c.FirewallRulesClient.Get(ctx, instance.GetSpec().ResourceGroupName, instance.GetName(), firewallRuleName)
 	return err
 }
 
 // CreateFirewallRulesBegin begins the create operation for a firewall rule
-func (c *PostgreSQLServerClient) CreateFirewallRulesBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) ([]byte, error) {
+func (c *PostgreSQLServerClient) CreateFirewallRulesBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) (_ []byte, err error) {
+	ctx, done := postgreSQLObserver.Start(ctx, "CreateFirewallRulesBegin")
+	defer done(&err)
 
 	createParams := postgresql.FirewallRule{
 		Name: to.StringPtr(firewallRuleName),
@@ -413,6 +542,8 @@ func (c *PostgreSQLServerClient) CreateFirewallRulesBegin(ctx context.Context, i
 
 // CreateFirewallRulesEnd checks to see if the given create operation is completed and if any error has occurred.
 func (c *PostgreSQLServerClient) CreateFirewallRulesEnd(createOp []byte) (done bool, err error) {
+	defer postgreSQLObserver.Observe("CreateFirewallRulesEnd", time.Now(), &err)
+
 	// unmarshal the given create complete data into a future object
 	createFuture := &postgresql.FirewallRulesCreateOrUpdateFuture{}
 	if err = createFuture.UnmarshalJSON(createOp); err != nil {
@@ -0,0 +1,148 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients contains functionality shared by the cloud provider clients.
+// The clients of each cloud provider classify the errors returned by their
+// SDKs into the error classes defined here, so that controllers can report and
+// retry failures the same way regardless of the cloud provider.
+package clients
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// An ErrorClass describes why a cloud provider API request failed.
+type ErrorClass string
+
+// Error classes.
+const (
+	// ErrorUnknown errors could not be classified.
+	ErrorUnknown ErrorClass = "Unknown"
+
+	// ErrorNotFound errors indicate the requested resource does not exist.
+	ErrorNotFound ErrorClass = "NotFound"
+
+	// ErrorAlreadyExists errors indicate the resource to be created exists.
+	ErrorAlreadyExists ErrorClass = "AlreadyExists"
+
+	// ErrorThrottled errors indicate the request was rate limited.
+	ErrorThrottled ErrorClass = "Throttled"
+
+	// ErrorInvalidInput errors indicate the request was malformed or invalid,
+	// for example because of an unsupported parameter value.
+	ErrorInvalidInput ErrorClass = "InvalidInput"
+
+	// ErrorPermissionDenied errors indicate the credentials of the provider
+	// are invalid or do not allow the request.
+	ErrorPermissionDenied ErrorClass = "PermissionDenied"
+
+	// ErrorQuotaExceeded errors indicate the request would exceed a quota or
+	// limit of the cloud provider account.
+	ErrorQuotaExceeded ErrorClass = "QuotaExceeded"
+
+	// ErrorTransient errors indicate a temporary failure of the cloud
+	// provider, such as an internal error or an unavailable service.
+	ErrorTransient ErrorClass = "Transient"
+)
+
+// Retryable returns false if retrying a request that failed with this class of
+// error will not succeed until the request is changed.
+func (c ErrorClass) Retryable() bool {
+	return c != ErrorInvalidInput
+}
+
+// ClassifyStatusCode returns the class of errors returned with the supplied
+// HTTP status code.
+func ClassifyStatusCode(code int) ErrorClass {
+	switch {
+	case code == http.StatusNotFound:
+		return ErrorNotFound
+	case code == http.StatusConflict:
+		return ErrorAlreadyExists
+	case code == http.StatusTooManyRequests:
+		return ErrorThrottled
+	case code == http.StatusBadRequest:
+		return ErrorInvalidInput
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrorPermissionDenied
+	case code >= http.StatusInternalServerError:
+		return ErrorTransient
+	}
+	return ErrorUnknown
+}
+
+// A Classifier returns the class of an error returned by a cloud provider SDK.
+type Classifier func(error) ErrorClass
+
+// An Error is an error returned by a cloud provider API, along with its class.
+type Error struct {
+	Class ErrorClass
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the classified error.
+func (e *Error) Cause() error {
+	return e.Err
+}
+
+// Terminal returns true if the error is not retryable.
+func (e *Error) Terminal() bool {
+	return !e.Class.Retryable()
+}
+
+// Classify returns the supplied error as an *Error of the class returned by the
+// supplied classifier. Errors that are already classified are returned as is.
+func Classify(err error, classify Classifier) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*Error); ok {
+		return err
+	}
+	return &Error{Class: classify(err), Err: err}
+}
+
+// ClassOf returns the class of the supplied error, or ErrorUnknown if neither
+// it nor any error it wraps has been classified.
+func ClassOf(err error) ErrorClass {
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			return e.Class
+		}
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = c.Cause()
+	}
+	return ErrorUnknown
+}
+
+// Reason qualifies the supplied condition reason with the class of the
+// supplied error, e.g. "Failed to create resource: PermissionDenied", so that
+// failures of the same operation can be told apart. The reason is returned as
+// is if the error has not been classified.
+func Reason(reason string, err error) string {
+	if c := ClassOf(err); c != ErrorUnknown {
+		return fmt.Sprintf("%s: %s", reason, c)
+	}
+	return reason
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestClassifyStatusCode(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := map[int]ErrorClass{
+		http.StatusOK:                  ErrorUnknown,
+		http.StatusNotFound:            ErrorNotFound,
+		http.StatusConflict:            ErrorAlreadyExists,
+		http.StatusTooManyRequests:     ErrorThrottled,
+		http.StatusBadRequest:          ErrorInvalidInput,
+		http.StatusUnauthorized:        ErrorPermissionDenied,
+		http.StatusForbidden:           ErrorPermissionDenied,
+		http.StatusInternalServerError: ErrorTransient,
+		http.StatusServiceUnavailable:  ErrorTransient,
+	}
+
+	for code, expected := range cases {
+		g.Expect(ClassifyStatusCode(code)).To(Equal(expected), "status code %d", code)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	throttled := func(error) ErrorClass { return ErrorThrottled }
+	invalid := func(error) ErrorClass { return ErrorInvalidInput }
+
+	g.Expect(Classify(nil, throttled)).To(BeNil())
+	g.Expect(ClassOf(nil)).To(Equal(ErrorUnknown))
+	g.Expect(ClassOf(fmt.Errorf("test-error"))).To(Equal(ErrorUnknown))
+
+	// test: classified errors keep their message and class
+	err := Classify(fmt.Errorf("test-error"), throttled)
+	g.Expect(err).To(MatchError("test-error"))
+	g.Expect(ClassOf(err)).To(Equal(ErrorThrottled))
+	g.Expect(ClassOf(errors.Wrap(err, "wrapped"))).To(Equal(ErrorThrottled))
+	g.Expect(err.(*Error).Terminal()).To(BeFalse())
+
+	// test: classified errors are not classified again
+	g.Expect(Classify(err, invalid)).To(Equal(err))
+
+	// test: invalid input is not retryable
+	err = Classify(fmt.Errorf("test-error"), invalid)
+	g.Expect(err.(*Error).Terminal()).To(BeTrue())
+	g.Expect(err.(*Error).Cause()).To(MatchError("test-error"))
+}
+
+func TestReason(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Reason("Failed to create resource", fmt.Errorf("test-error"))).To(Equal("Failed to create resource"))
+	g.Expect(Reason("Failed to create resource", &Error{Class: ErrorUnknown, Err: fmt.Errorf("test-error")})).To(Equal("Failed to create resource"))
+	g.Expect(Reason("Failed to create resource", &Error{Class: ErrorPermissionDenied, Err: fmt.Errorf("test-error")})).To(Equal("Failed to create resource: PermissionDenied"))
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package external implements the client side of the external provisioner
+// protocol, through which out-of-process provisioners provision, observe,
+// bind and delete resources on behalf of resource claims.
+//
+// Each operation is a JSON encoded Request POSTed to a path below the URL of
+// the provisioner. Provision and find are answered with a JSON encoded
+// Response. A provisioner signals failure with a non-2xx status code and an
+// optional JSON encoded ErrorResponse. Resources are identified by the
+// namespace and name of their ExternalResource, and every operation must be
+// idempotent.
+package external
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+// Paths of the operations of the protocol, relative to the provisioner URL.
+const (
+	PathProvision = "/provision"
+	PathFind      = "/find"
+	PathBind      = "/bind"
+	PathDelete    = "/delete"
+)
+
+// DefaultTimeout of protocol requests to provisioners that do not configure
+// a timeout.
+const DefaultTimeout = 30 * time.Second
+
+// Request identifies the resource an operation applies to.
+type Request struct {
+	// CorrelationID identifies the reconcile that made the request. It is
+	// logged by Crossplane, and may be logged by provisioners so that their
+	// activity can be correlated with it.
+	CorrelationID string `json:"correlationID,omitempty"`
+
+	// Namespace and name of the ExternalResource
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Provisioner of the resource class, e.g. "kafkatopic.messaging.example.org/v1"
+	Provisioner string `json:"provisioner"`
+
+	// Parameters of the resource class
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// ClaimRef references the claim the resource was provisioned for
+	ClaimRef *corev1.ObjectReference `json:"claimRef,omitempty"`
+
+	// ReclaimPolicy of the resource. Provisioners that can snapshot resources
+	// should do so before deleting a resource with the Snapshot policy.
+	ReclaimPolicy corev1alpha1.ReclaimPolicy `json:"reclaimPolicy,omitempty"`
+
+	// Bound is whether the resource is bound to a claim. Only set by bind.
+	Bound bool `json:"bound,omitempty"`
+}
+
+// Response reports the observed state of a resource.
+type Response struct {
+	// State of the resource: creating, available or failed
+	State string `json:"state"`
+
+	// Message describes the state of the resource, e.g. why it failed
+	Message string `json:"message,omitempty"`
+
+	// ProviderID identifies the resource in the provisioner
+	ProviderID string `json:"providerID,omitempty"`
+
+	// Endpoint used to connect to the resource
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ConnectionSecret is the data of the connection secret of the resource.
+	// Values are base64 encoded, as in Kubernetes secrets.
+	ConnectionSecret map[string][]byte `json:"connectionSecret,omitempty"`
+}
+
+// ErrorResponse describes why a provisioner failed an operation.
+type ErrorResponse struct {
+	Message string `json:"message"`
+}
+
+// Error returned by a provisioner.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("external provisioner returned %d: %s", e.StatusCode, e.Message)
+}
+
+// IsErrorNotFound returns true if the supplied error indicates the resource
+// is not known to the provisioner.
+func IsErrorNotFound(err error) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// Client speaks the external provisioner protocol.
+type Client interface {
+	Provision(*Request) (*Response, error)
+	Find(*Request) (*Response, error)
+	SetBindStatus(*Request) error
+	Delete(*Request) error
+}
+
+type httpClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewClient returns a client of the supplied external provisioner.
+func NewClient(p *corev1alpha1.ExternalProvisioner) (Client, error) {
+	if p.URL == "" {
+		return nil, fmt.Errorf("external provisioner URL may not be empty")
+	}
+
+	timeout := DefaultTimeout
+	if p.TimeoutSeconds > 0 {
+		timeout = time.Duration(p.TimeoutSeconds) * time.Second
+	}
+
+	transport := &http.Transport{Proxy: http.ProxyFromEnvironment}
+	if len(p.CABundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(p.CABundle) {
+			return nil, fmt.Errorf("cannot parse external provisioner CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &httpClient{
+		url:    strings.TrimSuffix(p.URL, "/"),
+		client: &http.Client{Timeout: timeout, Transport: transport},
+	}, nil
+}
+
+// A ClientCache returns clients of external provisioners. The client of a
+// provisioner, and thus its connections, is reused until the configuration of
+// the provisioner changes.
+type ClientCache struct {
+	mu      sync.Mutex
+	clients map[string]Client
+}
+
+// NewClientCache returns an empty ClientCache.
+func NewClientCache() *ClientCache {
+	return &ClientCache{clients: map[string]Client{}}
+}
+
+// Get returns the cached client of the supplied external provisioner, or
+// creates one if no client of its configuration is cached.
+func (c *ClientCache) Get(p *corev1alpha1.ExternalProvisioner) (Client, error) {
+	key := fmt.Sprintf("%s|%d|%s", p.URL, p.TimeoutSeconds, p.CABundle)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[key]; ok {
+		return client, nil
+	}
+	client, err := NewClient(p)
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = client
+	return client, nil
+}
+
+// Provision the requested resource.
+func (c *httpClient) Provision(req *Request) (*Response, error) {
+	rsp := &Response{}
+	return rsp, c.post(PathProvision, req, rsp)
+}
+
+// Find the requested resource and report its state.
+func (c *httpClient) Find(req *Request) (*Response, error) {
+	rsp := &Response{}
+	return rsp, c.post(PathFind, req, rsp)
+}
+
+// SetBindStatus notifies the provisioner that the requested resource was
+// bound to, or unbound from, its claim.
+func (c *httpClient) SetBindStatus(req *Request) error {
+	return c.post(PathBind, req, nil)
+}
+
+// Delete the requested resource.
+func (c *httpClient) Delete(req *Request) error {
+	return c.post(PathDelete, req, nil)
+}
+
+func (c *httpClient) post(path string, req *Request, rsp *Response) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	r, err := c.client.Post(c.url+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		e := &ErrorResponse{}
+		if err := json.Unmarshal(b, e); err != nil || e.Message == "" {
+			e.Message = strings.TrimSpace(string(b))
+		}
+		return &Error{StatusCode: r.StatusCode, Message: e.Message}
+	}
+
+	if rsp == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, rsp); err != nil {
+		return fmt.Errorf("cannot decode external provisioner response: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+func TestNewClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	_, err := NewClient(&corev1alpha1.ExternalProvisioner{})
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = NewClient(&corev1alpha1.ExternalProvisioner{URL: "https://example.org", CABundle: []byte("not-a-certificate")})
+	g.Expect(err).To(HaveOccurred())
+
+	c, err := NewClient(&corev1alpha1.ExternalProvisioner{URL: "https://example.org/", TimeoutSeconds: 5})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.(*httpClient).url).To(Equal("https://example.org"))
+	g.Expect(c.(*httpClient).client.Timeout.Seconds()).To(Equal(float64(5)))
+}
+
+func TestClientCache(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cache := NewClientCache()
+
+	_, err := cache.Get(&corev1alpha1.ExternalProvisioner{})
+	g.Expect(err).To(HaveOccurred())
+
+	// test: the client of an unchanged provisioner is reused
+	c, err := cache.Get(&corev1alpha1.ExternalProvisioner{URL: "https://example.org"})
+	g.Expect(err).NotTo(HaveOccurred())
+	got, err := cache.Get(&corev1alpha1.ExternalProvisioner{URL: "https://example.org"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).To(BeIdenticalTo(c))
+
+	// test: a new client is created when the provisioner changes
+	got, err = cache.Get(&corev1alpha1.ExternalProvisioner{URL: "https://example.org", TimeoutSeconds: 5})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).NotTo(BeIdenticalTo(c))
+	g.Expect(got.(*httpClient).client.Timeout.Seconds()).To(Equal(float64(5)))
+}
+
+func TestClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var path string
+	var got Request
+	status := http.StatusOK
+	body := `{"state":"available","endpoint":"kafka.example.org:9092","connectionSecret":{"username":"Ym9i"}}`
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		g.Expect(r.Method).To(Equal(http.MethodPost))
+		g.Expect(json.NewDecoder(r.Body).Decode(&got)).To(Succeed())
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer s.Close()
+
+	c, err := NewClient(&corev1alpha1.ExternalProvisioner{URL: s.URL + "/kafka"})
+	g.Expect(err).NotTo(HaveOccurred())
+	req := &Request{Namespace: "default", Name: "kafkatopic-123", Provisioner: "kafkatopic.messaging.example.org/v1"}
+
+	// test: provision returns the reported state
+	rsp, err := c.Provision(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(path).To(Equal("/kafka" + PathProvision))
+	g.Expect(got).To(Equal(*req))
+	g.Expect(rsp.State).To(Equal(corev1alpha1.ExternalResourceStateAvailable))
+	g.Expect(rsp.Endpoint).To(Equal("kafka.example.org:9092"))
+	g.Expect(rsp.ConnectionSecret).To(Equal(map[string][]byte{"username": []byte("bob")}))
+
+	// test: find returns the reported state
+	rsp, err = c.Find(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(path).To(Equal("/kafka" + PathFind))
+	g.Expect(rsp.State).To(Equal(corev1alpha1.ExternalResourceStateAvailable))
+
+	// test: bind sends the binding status
+	req.Bound = true
+	g.Expect(c.SetBindStatus(req)).To(Succeed())
+	g.Expect(path).To(Equal("/kafka" + PathBind))
+	g.Expect(got.Bound).To(BeTrue())
+
+	// test: delete
+	g.Expect(c.Delete(req)).To(Succeed())
+	g.Expect(path).To(Equal("/kafka" + PathDelete))
+
+	// test: an error response is returned as an error
+	status, body = http.StatusInternalServerError, `{"message":"broker unavailable"}`
+	_, err = c.Find(req)
+	g.Expect(err).To(Equal(&Error{StatusCode: http.StatusInternalServerError, Message: "broker unavailable"}))
+	g.Expect(IsErrorNotFound(err)).To(BeFalse())
+
+	// test: an error response without a JSON body
+	status, body = http.StatusNotFound, "not found\n"
+	err = c.Delete(req)
+	g.Expect(err).To(Equal(&Error{StatusCode: http.StatusNotFound, Message: "not found"}))
+	g.Expect(IsErrorNotFound(err)).To(BeTrue())
+
+	// test: an invalid response
+	status, body = http.StatusOK, "{"
+	_, err = c.Find(req)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/crossplaneio/crossplane/pkg/clients/external"
+)
+
+// MockClient for testing.
+type MockClient struct {
+	MockProvision     func(*external.Request) (*external.Response, error)
+	MockFind          func(*external.Request) (*external.Response, error)
+	MockSetBindStatus func(*external.Request) error
+	MockDelete        func(*external.Request) error
+}
+
+// Provision the requested resource
+func (m *MockClient) Provision(req *external.Request) (*external.Response, error) {
+	return m.MockProvision(req)
+}
+
+// Find the requested resource
+func (m *MockClient) Find(req *external.Request) (*external.Response, error) {
+	return m.MockFind(req)
+}
+
+// SetBindStatus of the requested resource
+func (m *MockClient) SetBindStatus(req *external.Request) error {
+	return m.MockSetBindStatus(req)
+}
+
+// Delete the requested resource
+func (m *MockClient) Delete(req *external.Request) error {
+	return m.MockDelete(req)
+}
@@ -28,6 +28,8 @@ import (
 	"google.golang.org/genproto/protobuf/field_mask"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	"github.com/crossplaneio/crossplane/pkg/clients/gcp"
 )
 
 // NamePrefix is the prefix for all created CloudMemorystore instances.
@@ -45,7 +47,43 @@ type Client interface {
 // NewClient returns a new CloudMemorystore Client. Credentials must be passed
 // as JSON encoded data.
 func NewClient(ctx context.Context, credentials []byte) (Client, error) {
-	return redisv1.NewCloudRedisClient(ctx, option.WithCredentialsJSON(credentials))
+	c, err := redisv1.NewCloudRedisClient(ctx, option.WithCredentialsJSON(credentials))
+	if err != nil {
+		return nil, err
+	}
+	return &observedClient{client: c}, nil
+}
+
+var observer = clients.NewObserver("gcp/cloudmemorystore", gcp.ClassifyError)
+
+// observedClient records metrics about, traces and logs the requests made by a
+// Client.
+type observedClient struct {
+	client Client
+}
+
+func (c *observedClient) CreateInstance(ctx context.Context, req *redisv1pb.CreateInstanceRequest, opts ...gax.CallOption) (op *redisv1.CreateInstanceOperation, err error) {
+	ctx, done := observer.Start(ctx, "CreateInstance")
+	defer done(&err)
+	return c.client.CreateInstance(ctx, req, opts...)
+}
+
+func (c *observedClient) UpdateInstance(ctx context.Context, req *redisv1pb.UpdateInstanceRequest, opts ...gax.CallOption) (op *redisv1.UpdateInstanceOperation, err error) {
+	ctx, done := observer.Start(ctx, "UpdateInstance")
+	defer done(&err)
+	return c.client.UpdateInstance(ctx, req, opts...)
+}
+
+func (c *observedClient) DeleteInstance(ctx context.Context, req *redisv1pb.DeleteInstanceRequest, opts ...gax.CallOption) (op *redisv1.DeleteInstanceOperation, err error) {
+	ctx, done := observer.Start(ctx, "DeleteInstance")
+	defer done(&err)
+	return c.client.DeleteInstance(ctx, req, opts...)
+}
+
+func (c *observedClient) GetInstance(ctx context.Context, req *redisv1pb.GetInstanceRequest, opts ...gax.CallOption) (i *redisv1pb.Instance, err error) {
+	ctx, done := observer.Start(ctx, "GetInstance")
+	defer done(&err)
+	return c.client.GetInstance(ctx, req, opts...)
 }
 
 // An InstanceID represents a CloudMemorystore instance in the GCP API.
@@ -17,8 +17,8 @@ limitations under the License.
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"time"
 
 	sqladmin "google.golang.org/api/sqladmin/v1beta4"
@@ -28,16 +28,21 @@ import (
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	dbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
 )
 
+var cloudSQLObserver = clients.NewObserver("gcp/cloudsql", ClassifyError)
+
 // CloudSQLAPI provides an interface for operations on CloudSQL instances
 type CloudSQLAPI interface {
-	GetInstance(project string, instance string) (*sqladmin.DatabaseInstance, error)
-	CreateInstance(project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error)
-	DeleteInstance(project string, instance string) (*sqladmin.Operation, error)
-	ListUsers(project string, instance string) (*sqladmin.UsersListResponse, error)
-	UpdateUser(project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error)
-	GetOperation(project string, operationID string) (*sqladmin.Operation, error)
+	GetInstance(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error)
+	CreateInstance(ctx context.Context, project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error)
+	PatchInstance(ctx context.Context, project string, instance string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error)
+	DeleteInstance(ctx context.Context, project string, instance string) (*sqladmin.Operation, error)
+	CloneInstance(ctx context.Context, project string, instance string, destination string) (*sqladmin.Operation, error)
+	ListUsers(ctx context.Context, project string, instance string) (*sqladmin.UsersListResponse, error)
+	UpdateUser(ctx context.Context, project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error)
+	GetOperation(ctx context.Context, project string, operationID string) (*sqladmin.Operation, error)
 }
 
 // CloudSQLClient implements the CloudSQLAPI interface for real CloudSQL instances
@@ -61,33 +66,60 @@ func NewCloudSQLClient(clientset kubernetes.Interface, namespace string, secretK
 }
 
 // GetInstance retrieves details for the requested CloudSQL instance
-func (c *CloudSQLClient) GetInstance(project string, instance string) (*sqladmin.DatabaseInstance, error) {
-	return c.Instances.Get(project, instance).Do()
+func (c *CloudSQLClient) GetInstance(ctx context.Context, project string, instance string) (i *sqladmin.DatabaseInstance, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "GetInstance")
+	defer done(&err)
+	return c.Instances.Get(project, instance).Context(ctx).Do()
 }
 
 // CreateInstance creates the given CloudSQL instance
-func (c *CloudSQLClient) CreateInstance(project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
-	return c.Instances.Insert(project, databaseinstance).Do()
+func (c *CloudSQLClient) CreateInstance(ctx context.Context, project string, databaseinstance *sqladmin.DatabaseInstance) (op *sqladmin.Operation, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "CreateInstance")
+	defer done(&err)
+	return c.Instances.Insert(project, databaseinstance).Context(ctx).Do()
+}
+
+// PatchInstance updates the given CloudSQL instance with the fields that are set in the supplied instance
+func (c *CloudSQLClient) PatchInstance(ctx context.Context, project string, instance string, databaseinstance *sqladmin.DatabaseInstance) (op *sqladmin.Operation, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "PatchInstance")
+	defer done(&err)
+	return c.Instances.Patch(project, instance, databaseinstance).Context(ctx).Do()
 }
 
 // DeleteInstance deletes the given CloudSQL instance
-func (c *CloudSQLClient) DeleteInstance(project string, instance string) (*sqladmin.Operation, error) {
-	return c.Instances.Delete(project, instance).Do()
+func (c *CloudSQLClient) DeleteInstance(ctx context.Context, project string, instance string) (op *sqladmin.Operation, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "DeleteInstance")
+	defer done(&err)
+	return c.Instances.Delete(project, instance).Context(ctx).Do()
+}
+
+// CloneInstance clones the given CloudSQL instance to a new instance with the destination name
+func (c *CloudSQLClient) CloneInstance(ctx context.Context, project string, instance string, destination string) (op *sqladmin.Operation, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "CloneInstance")
+	defer done(&err)
+	req := &sqladmin.InstancesCloneRequest{CloneContext: &sqladmin.CloneContext{DestinationInstanceName: destination}}
+	return c.Instances.Clone(project, instance, req).Context(ctx).Do()
 }
 
 // ListUsers lists all the users for the given CloudSQL instance
-func (c *CloudSQLClient) ListUsers(project string, instance string) (*sqladmin.UsersListResponse, error) {
-	return c.Users.List(project, instance).Do()
+func (c *CloudSQLClient) ListUsers(ctx context.Context, project string, instance string) (users *sqladmin.UsersListResponse, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "ListUsers")
+	defer done(&err)
+	return c.Users.List(project, instance).Context(ctx).Do()
 }
 
 // UpdateUser updates the given user for the given CloudSQL instance
-func (c *CloudSQLClient) UpdateUser(project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error) {
-	return c.Users.Update(project, instance, name, user).Do()
+func (c *CloudSQLClient) UpdateUser(ctx context.Context, project string, instance string, name string, user *sqladmin.User) (op *sqladmin.Operation, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "UpdateUser")
+	defer done(&err)
+	return c.Users.Update(project, instance, name, user).Context(ctx).Do()
 }
 
 // GetOperation retrieves the latest status for the given operation
-func (c *CloudSQLClient) GetOperation(project string, operationID string) (*sqladmin.Operation, error) {
-	return c.Operations.Get(project, operationID).Do()
+func (c *CloudSQLClient) GetOperation(ctx context.Context, project string, operationID string) (op *sqladmin.Operation, err error) {
+	ctx, done := cloudSQLObserver.Start(ctx, "GetOperation")
+	defer done(&err)
+	return c.Operations.Get(project, operationID).Context(ctx).Do()
 }
 
 // CloudSQLAPIFactory defines an interface for creating instances of the CloudSQLAPI interface.
@@ -113,7 +145,7 @@ func (c *CloudSQLClientFactory) CreateAPIInstance(clientset kubernetes.Interface
 
 // WaitUntilOperationCompletes waits until the supplied operation is complete,
 // returning an error if it does not complete within the supplied duration.
-func WaitUntilOperationCompletes(operationID string, provider *gcpv1alpha1.Provider,
+func WaitUntilOperationCompletes(ctx context.Context, operationID string, provider *gcpv1alpha1.Provider,
 	cloudSQLClient CloudSQLAPI, waitTime time.Duration) (*sqladmin.Operation, error) {
 
 	var err error
@@ -121,9 +153,9 @@ func WaitUntilOperationCompletes(operationID string, provider *gcpv1alpha1.Provi
 
 	maxRetries := 50
 	for i := 0; i <= maxRetries; i++ {
-		op, err = cloudSQLClient.GetOperation(provider.Spec.ProjectID, operationID)
+		op, err = cloudSQLClient.GetOperation(ctx, provider.Spec.ProjectID, operationID)
 		if err != nil {
-			log.Printf("failed to get cloud sql operation %s, waiting %v: %+v", operationID, waitTime, err)
+			logger.Info("cannot get cloud sql operation, retrying", "operation", operationID, "wait", waitTime.String(), "error", err.Error())
 		} else if IsOperationComplete(op) {
 			// the operation has completed, simply return it
 			return op, nil
@@ -145,6 +177,49 @@ func IsOperationSuccessful(op *sqladmin.Operation) bool {
 	return op.Error == nil || len(op.Error.Errors) == 0
 }
 
+// OperationErrorMessage returns the message of the first error of the supplied
+// operation.
+func OperationErrorMessage(op *sqladmin.Operation) string {
+	if op.Error == nil || len(op.Error.Errors) == 0 {
+		return ""
+	}
+	return op.Error.Errors[0].Message
+}
+
+// CloudSQLInstanceNeedsUpdate returns true if the settings of the supplied
+// CloudSQL instance differ from the supplied spec. It considers only settings
+// that can be patched without recreating the instance. Storage settings that
+// are not specified are chosen by GCP and never need an update, and neither
+// does a disk that is larger than specified, e.g. because it grew
+// automatically: disks cannot shrink.
+func CloudSQLInstanceNeedsUpdate(spec *dbv1alpha1.CloudsqlInstanceSpec, instance *sqladmin.DatabaseInstance) bool {
+	p := CloudSQLInstancePatch(spec, instance).Settings
+	return p.Tier != "" || p.DataDiskType != "" || p.DataDiskSizeGb != 0
+}
+
+// CloudSQLInstancePatch returns a patch that updates the settings of the
+// supplied CloudSQL instance that differ from the supplied spec.
+func CloudSQLInstancePatch(spec *dbv1alpha1.CloudsqlInstanceSpec, instance *sqladmin.DatabaseInstance) *sqladmin.DatabaseInstance {
+	s := instance.Settings
+	if s == nil {
+		s = &sqladmin.Settings{}
+	}
+
+	// The settings version guards against patching settings that were
+	// changed since the instance was observed.
+	p := &sqladmin.Settings{SettingsVersion: s.SettingsVersion}
+	if spec.Tier != s.Tier {
+		p.Tier = spec.Tier
+	}
+	if spec.StorageType != "" && spec.StorageType != s.DataDiskType {
+		p.DataDiskType = spec.StorageType
+	}
+	if spec.StorageGB > s.DataDiskSizeGb {
+		p.DataDiskSizeGb = spec.StorageGB
+	}
+	return &sqladmin.DatabaseInstance{Settings: p}
+}
+
 // CloudSQLConditionType converts the given CloudSQL state string into a corresponding condition type
 func CloudSQLConditionType(state string) corev1alpha1.ConditionType {
 	switch state {
@@ -0,0 +1,64 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	sqladmin "google.golang.org/api/sqladmin/v1beta4"
+
+	dbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+)
+
+func TestCloudSQLInstancePatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	spec := &dbv1alpha1.CloudsqlInstanceSpec{Tier: "db-n1-standard-1", StorageType: "PD_SSD", StorageGB: 10}
+	instance := &sqladmin.DatabaseInstance{Settings: &sqladmin.Settings{
+		Tier:            "db-n1-standard-1",
+		DataDiskType:    "PD_SSD",
+		DataDiskSizeGb:  10,
+		SettingsVersion: 7,
+	}}
+
+	// the instance matches its spec
+	g.Expect(CloudSQLInstanceNeedsUpdate(spec, instance)).To(BeFalse())
+
+	// storage settings chosen by GCP are not patched
+	g.Expect(CloudSQLInstanceNeedsUpdate(&dbv1alpha1.CloudsqlInstanceSpec{Tier: "db-n1-standard-1"}, instance)).To(BeFalse())
+
+	// only the settings that differ from the spec are patched
+	spec.Tier = "db-n1-standard-2"
+	spec.StorageGB = 20
+	g.Expect(CloudSQLInstanceNeedsUpdate(spec, instance)).To(BeTrue())
+	g.Expect(CloudSQLInstancePatch(spec, instance)).To(Equal(&sqladmin.DatabaseInstance{Settings: &sqladmin.Settings{
+		Tier:            "db-n1-standard-2",
+		DataDiskSizeGb:  20,
+		SettingsVersion: 7,
+	}}))
+
+	// a disk that grew automatically beyond its spec is not shrunk
+	spec = &dbv1alpha1.CloudsqlInstanceSpec{Tier: "db-n1-standard-1", StorageType: "PD_SSD", StorageGB: 10}
+	instance.Settings.DataDiskSizeGb = 15
+	g.Expect(CloudSQLInstanceNeedsUpdate(spec, instance)).To(BeFalse())
+	spec.StorageGB = 20
+	g.Expect(CloudSQLInstancePatch(spec, instance)).To(Equal(&sqladmin.DatabaseInstance{Settings: &sqladmin.Settings{
+		DataDiskSizeGb:  20,
+		SettingsVersion: 7,
+	}}))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+)
+
+// errorReasons of Google API errors whose class cannot be determined by their
+// HTTP status code alone.
+var errorReasons = map[string]clients.ErrorClass{
+	"rateLimitExceeded":       clients.ErrorThrottled,
+	"userRateLimitExceeded":   clients.ErrorThrottled,
+	"quotaExceeded":           clients.ErrorQuotaExceeded,
+	"accessNotConfigured":     clients.ErrorPermissionDenied,
+	"forbidden":               clients.ErrorPermissionDenied,
+	"insufficientPermissions": clients.ErrorPermissionDenied,
+}
+
+// errorCodes of gRPC errors returned by Google Cloud client libraries.
+var errorCodes = map[codes.Code]clients.ErrorClass{
+	codes.NotFound:           clients.ErrorNotFound,
+	codes.AlreadyExists:      clients.ErrorAlreadyExists,
+	codes.ResourceExhausted:  clients.ErrorQuotaExceeded,
+	codes.InvalidArgument:    clients.ErrorInvalidInput,
+	codes.OutOfRange:         clients.ErrorInvalidInput,
+	codes.PermissionDenied:   clients.ErrorPermissionDenied,
+	codes.Unauthenticated:    clients.ErrorPermissionDenied,
+	codes.Aborted:            clients.ErrorTransient,
+	codes.DeadlineExceeded:   clients.ErrorTransient,
+	codes.FailedPrecondition: clients.ErrorTransient,
+	codes.Internal:           clients.ErrorTransient,
+	codes.Unavailable:        clients.ErrorTransient,
+}
+
+// ClassifyError returns the class of the supplied error returned by a Google
+// API or Google Cloud client library.
+func ClassifyError(err error) clients.ErrorClass {
+	err = errors.Cause(err)
+	if err == nil {
+		return clients.ErrorUnknown
+	}
+
+	if googleapiErr, ok := err.(*googleapi.Error); ok {
+		for _, item := range googleapiErr.Errors {
+			if c, ok := errorReasons[item.Reason]; ok {
+				return c
+			}
+		}
+		return clients.ClassifyStatusCode(googleapiErr.Code)
+	}
+
+	if s, ok := status.FromError(err); ok {
+		if c, ok := errorCodes[s.Code()]; ok {
+			return c
+		}
+	}
+
+	return clients.ErrorUnknown
+}
+
+// IsErrorNotFound gets a value indicating whether the given error represents a "not found" response from the Google API
+func IsErrorNotFound(err error) bool {
+	return ClassifyError(err) == clients.ErrorNotFound
+}
+
+// IsErrorAlreadyExists gets a value indicating whether the given error represents a "conflict" response from the Google API
+func IsErrorAlreadyExists(err error) bool {
+	return ClassifyError(err) == clients.ErrorAlreadyExists
+}
+
+// IsErrorBadRequest gets a value indicating whether the given error represents a "bad request" response from the Google API
+func IsErrorBadRequest(err error) bool {
+	return ClassifyError(err) == clients.ErrorInvalidInput
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossplaneio/crossplane/pkg/clients"
+)
+
+func TestClassifyError(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		err      error
+		expected clients.ErrorClass
+	}{
+		{nil, clients.ErrorUnknown},
+		{fmt.Errorf("test-error"), clients.ErrorUnknown},
+		{&googleapi.Error{Code: http.StatusNotFound}, clients.ErrorNotFound},
+		{&googleapi.Error{Code: http.StatusConflict}, clients.ErrorAlreadyExists},
+		{&googleapi.Error{Code: http.StatusBadRequest}, clients.ErrorInvalidInput},
+		{&googleapi.Error{Code: http.StatusForbidden}, clients.ErrorPermissionDenied},
+		{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, clients.ErrorThrottled},
+		{&googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, clients.ErrorQuotaExceeded},
+		{&googleapi.Error{Code: http.StatusServiceUnavailable}, clients.ErrorTransient},
+		{errors.Wrap(&googleapi.Error{Code: http.StatusNotFound}, "wrapped"), clients.ErrorNotFound},
+		{status.Error(codes.NotFound, "test-error"), clients.ErrorNotFound},
+		{status.Error(codes.InvalidArgument, "test-error"), clients.ErrorInvalidInput},
+		{status.Error(codes.Unavailable, "test-error"), clients.ErrorTransient},
+		{status.Error(codes.Unknown, "test-error"), clients.ErrorUnknown},
+	}
+
+	for _, tt := range cases {
+		g.Expect(ClassifyError(tt.err)).To(Equal(tt.expected), "error %v", tt.err)
+	}
+
+	g.Expect(IsErrorNotFound(&googleapi.Error{Code: http.StatusNotFound})).To(BeTrue())
+	g.Expect(IsErrorAlreadyExists(&googleapi.Error{Code: http.StatusConflict})).To(BeTrue())
+	g.Expect(IsErrorBadRequest(&googleapi.Error{Code: http.StatusBadRequest})).To(BeTrue())
+}
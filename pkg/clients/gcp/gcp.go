@@ -19,23 +19,24 @@ package gcp
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/cloudresourcemanager/v1"
-	"google.golang.org/api/googleapi"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 // DefaultScope is the default scope to use for a GCP client
 const DefaultScope = cloudresourcemanager.CloudPlatformScope
 
+var logger = logging.Log.WithName("gcp")
+
 // GetGoogleClient returns a client object that can be used to interact with the Google API
 func GetGoogleClient(clientset kubernetes.Interface, namespace string, secretKey v1.SecretKeySelector,
 	scopes ...string) (*http.Client, error) {
@@ -54,12 +55,12 @@ func GetGoogleClient(clientset kubernetes.Interface, namespace string, secretKey
 
 	// 2) try the default Google client
 	if hc == nil {
-		log.Printf("failed to get google client from secret %s, will try default client: %+v", secretKey.Name, err)
+		logger.Info("cannot get google client from secret, trying the default client", "secret", secretKey.Name, "error", err.Error())
 		hc, err = google.DefaultClient(context.Background(), scopes...)
 		if err != nil {
-			log.Printf("failed to get default google client: %+v", err)
+			logger.Error(err, "cannot get default google client")
 		} else {
-			log.Printf("default google client created")
+			logger.V(logging.Debug).Info("default google client created")
 		}
 	}
 
@@ -70,33 +71,6 @@ func GetGoogleClient(clientset kubernetes.Interface, namespace string, secretKey
 	return nil, fmt.Errorf("failed to get google client: %+v", err)
 }
 
-// IsErrorNotFound gets a value indicating whether the given error represents a "not found" response from the Google API
-func IsErrorNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	googleapiErr, ok := err.(*googleapi.Error)
-	return ok && googleapiErr.Code == http.StatusNotFound
-}
-
-// IsErrorAlreadyExists gets a value indicating whether the given error represents a "conflict" response from the Google API
-func IsErrorAlreadyExists(err error) bool {
-	if err == nil {
-		return false
-	}
-	googleapiErr, ok := err.(*googleapi.Error)
-	return ok && googleapiErr.Code == http.StatusConflict
-}
-
-// IsErrorBadRequest gets a value indicating whether the given error represents a "bad request" response from the Google API
-func IsErrorBadRequest(err error) bool {
-	if err == nil {
-		return false
-	}
-	googleapiErr, ok := err.(*googleapi.Error)
-	return ok && googleapiErr.Code == http.StatusBadRequest
-}
-
 // ProviderCredentials return google credentials based on the provider's credentials secret data
 func ProviderCredentials(client kubernetes.Interface, p *gcpv1alpha1.Provider, scopes ...string) (*google.Credentials, error) {
 	// retrieve provider secret data
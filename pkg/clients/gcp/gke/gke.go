@@ -18,12 +18,14 @@ package gke
 
 import (
 	"context"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"google.golang.org/api/container/v1"
 
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp"
 )
 
@@ -32,6 +34,8 @@ const (
 	DefaultScope = container.CloudPlatformScope
 )
 
+var observer = clients.NewObserver("gcp/gke", gcp.ClassifyError)
+
 // Client interface to perform cluster operations
 type Client interface {
 	CreateCluster(string, computev1alpha1.GKEClusterSpec) (*container.Cluster, error)
@@ -59,7 +63,9 @@ func NewClusterClient(creds *google.Credentials) (*ClusterClient, error) {
 }
 
 // CreateCluster creates a new GKE cluster.
-func (c *ClusterClient) CreateCluster(name string, spec computev1alpha1.GKEClusterSpec) (*container.Cluster, error) {
+func (c *ClusterClient) CreateCluster(name string, spec computev1alpha1.GKEClusterSpec) (cluster *container.Cluster, err error) {
+	defer observer.Observe("CreateCluster", time.Now(), &err)
+
 	zone := spec.Zone
 
 	cl := &container.Cluster{
@@ -86,13 +92,17 @@ func (c *ClusterClient) CreateCluster(name string, spec computev1alpha1.GKEClust
 }
 
 // GetCluster retrieve GKE Cluster based on provided zone and name
-func (c *ClusterClient) GetCluster(zone, name string) (*container.Cluster, error) {
+func (c *ClusterClient) GetCluster(zone, name string) (cluster *container.Cluster, err error) {
+	defer observer.Observe("GetCluster", time.Now(), &err)
+
 	return c.client.Projects.Zones.Clusters.Get(c.creds.ProjectID, zone, name).Do()
 }
 
 // DeleteCluster in the given zone with the given name
-func (c *ClusterClient) DeleteCluster(zone, name string) error {
-	_, err := c.client.Projects.Zones.Clusters.Delete(c.creds.ProjectID, zone, name).Do()
+func (c *ClusterClient) DeleteCluster(zone, name string) (err error) {
+	defer observer.Observe("DeleteCluster", time.Now(), &err)
+
+	_, err = c.client.Projects.Zones.Clusters.Delete(c.creds.ProjectID, zone, name).Do()
 	if err != nil {
 		if gcp.IsErrorNotFound(err) {
 			return nil
@@ -103,7 +113,9 @@ func (c *ClusterClient) DeleteCluster(zone, name string) error {
 }
 
 // DefaultKubernetesVersion is the default Kubernetes Cluster version supported by GKE for given project/zone
-func (c *ClusterClient) DefaultKubernetesVersion(zone string) (string, error) {
+func (c *ClusterClient) DefaultKubernetesVersion(zone string) (version string, err error) {
+	defer observer.Observe("DefaultKubernetesVersion", time.Now(), &err)
+
 	sc, err := c.client.Projects.Zones.GetServerconfig(c.creds.ProjectID, zone).Fields("validMasterVersions").Do()
 	if err != nil {
 		return "", err
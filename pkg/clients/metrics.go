@@ -0,0 +1,84 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/tracing"
+)
+
+// An Observer records the latency and errors of the cloud provider API
+// requests made by the methods of a client.
+type Observer struct {
+	client   string
+	classify Classifier
+}
+
+// NewObserver returns an Observer for the named client, e.g. "aws/rds", that
+// counts errors by the class returned by the supplied classifier.
+func NewObserver(client string, classify Classifier) *Observer {
+	return &Observer{client: client, classify: classify}
+}
+
+// Observe records a call of the named client method that started at the
+// supplied time. The error returned by the method is read through the supplied
+// pointer when Observe is called, so it is intended to be deferred by methods
+// with a named error result:
+//
+//	defer observer.Observe("CreateInstance", time.Now(), &err)
+func (o *Observer) Observe(method string, start time.Time, err *error) {
+	class := ""
+	if *err != nil {
+		class = string(o.classify(*err))
+	}
+	metrics.ObserveCloudAPIRequest(o.client, method, start, class)
+}
+
+// Start starts a span of a call of the named client method that is a child
+// of the span of the supplied context. It returns a context for the request
+// the method makes, and a function that ends the span and records the call
+// like Observe. The call is also logged at debug level with the logger of the
+// reconcile the supplied context was created for, so that the request may be
+// correlated with the reconcile that made it. The returned function is
+// intended to be deferred by methods with a named error result:
+//
+//	ctx, done := observer.Start(ctx, "CreateInstance")
+//	defer done(&err)
+func (o *Observer) Start(ctx context.Context, method string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, o.client+"."+method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("crossplane.client", o.client), attribute.String("crossplane.method", method)))
+
+	return ctx, func(err *error) {
+		o.Observe(method, start, err)
+		tracing.End(span, *err)
+
+		log := logging.FromContext(ctx).V(logging.Debug)
+		if *err != nil {
+			log.Info("cloud API request failed", "client", o.client, "method", method, "duration", time.Since(start).String(), "error", (*err).Error())
+			return
+		}
+		log.Info("cloud API request", "client", o.client, "method", method, "duration", time.Since(start).String())
+	}
+}
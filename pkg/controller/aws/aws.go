@@ -24,28 +24,23 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/controller/aws/provider"
 	"github.com/crossplaneio/crossplane/pkg/controller/aws/rds"
 	"github.com/crossplaneio/crossplane/pkg/controller/aws/s3"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
-	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
-	AddToManagerFuncs = append(AddToManagerFuncs,
-		cache.Add,
-		compute.Add,
-		rds.Add,
-		provider.Add,
-		s3.Add,
+	Controllers = append(Controllers,
+		selection.Controller{Provider: selection.ProviderAWS, Add: provider.Add},
+		selection.Controller{Provider: selection.ProviderAWS, Kind: selection.KindCache, Add: cache.Add},
+		selection.Controller{Provider: selection.ProviderAWS, Kind: selection.KindCompute, Add: compute.Add},
+		selection.Controller{Provider: selection.ProviderAWS, Kind: selection.KindDatabase, Add: rds.Add},
+		selection.Controller{Provider: selection.ProviderAWS, Kind: selection.KindStorage, Add: s3.Add},
 	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
-
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
-			return err
-		}
-	}
-	return nil
+// Controllers are the controllers of AWS resources
+var Controllers []selection.Controller
+
+// AddToManager adds the selected Controllers to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
+	return sel.AddToManager(m, Controllers)
 }
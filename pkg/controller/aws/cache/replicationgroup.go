@@ -18,154 +18,114 @@ package cache
 
 import (
 	"context"
-	"log"
-	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/elasticache"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 const (
 	controllerName = "replicationgroup.cache.aws.crossplane.io"
-	finalizerName  = "finalizer." + controllerName
 
-	reasonFetchingClient   = "failed to fetch AWS Replication Group client"
-	reasonCreatingResource = "failed to create AWS Replication Group"
-	reasonDeletingResource = "failed to delete AWS Replication Group"
-	reasonSyncingResource  = "failed to sync AWS Replication Group"
-	reasonSyncingSecret    = "failed to sync AWS Replication Group connection secret" // nolint:gas,gosec
-
-	reconcileTimeout = 1 * time.Minute
+	errNotReplicationGroup = "managed resource is not an AWS Replication Group"
 
 	// Note this is the length of the generated random byte slice before base64
 	// encoding, which adds ~33% overhead. ElastiCache allows auth tokens to be
 	maxAuthTokenData = 32
 )
 
-// A creator can create resources in an external store - e.g. the AWS API.
-type creator interface {
-	// Create the supplied resource in the external store. Returns true if the
-	// resource requires further reconciliation.
-	Create(ctx context.Context, r *v1alpha1.ReplicationGroup) (requeue bool)
+// Add creates a new ReplicationGroup Controller and adds it to the
+// Manager with default RBAC. The Manager will set fields on the Controller and
+// start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	c := &connecter{kube: mgr.GetClient(), newClient: elasticache.NewClient}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &v1alpha1.ReplicationGroup{} }, c,
+		aws.ClassifyError, corev1alpha1.ReclaimDelete, corev1alpha1.ReclaimSnapshot)
 }
 
-// A syncer can sync resources with an external store - e.g. the AWS API.
-type syncer interface {
-	// Sync the supplied resource with the external store. Returns true if the
-	// resource requires further reconciliation.
-	Sync(ctx context.Context, r *v1alpha1.ReplicationGroup) (requeue bool)
+// connecter returns ElastiCache clients authenticated using credentials read
+// from the Crossplane Provider referenced by a ReplicationGroup.
+type connecter struct {
+	kube      client.Client
+	newClient func(creds []byte, region string) (elasticache.Client, error)
 }
 
-// A deleter can delete resources from an external store - e.g. the AWS API.
-type deleter interface {
-	// Delete the supplied resource from the external store. Returns true if the
-	// resource requires further reconciliation.
-	Delete(ctx context.Context, r *v1alpha1.ReplicationGroup) (requeue bool)
-}
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	g, ok := mg.(*v1alpha1.ReplicationGroup)
+	if !ok {
+		return nil, errors.New(errNotReplicationGroup)
+	}
 
-// A keyer can read the primary access key for the supplied resource.
-type keyer interface {
-	// Key returns the primary access key for the supplied resource.
-	Key() (key string)
-}
+	p := &awsv1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: g.GetNamespace(), Name: g.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
+	}
 
-// A createsyncdeletekeyer an create, sync, and delete resources in an external
-// store - e.g. the AWS API. It can also return keys (i.e. credentials) for
-// resources.
-type createsyncdeletekeyer interface {
-	creator
-	syncer
-	deleter
-	keyer
-}
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
+	}
+
+	s := &corev1.Secret{}
+	n = types.NamespacedName{Namespace: p.Namespace, Name: p.Spec.Secret.Name}
+	if err := c.kube.Get(ctx, n, s); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider secret %s", n)
+	}
 
-// elastiCache is a createsyncdeleter using the AWS Replication Group API.
-type elastiCache struct {
-	client    elasticache.Client
-	authToken string
+	client, err := c.newClient(s.Data[p.Spec.Secret.Key], p.Spec.Region)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create new AWS Replication Group client")
+	}
+	return &external{client: client}, nil
 }
 
-func (e *elastiCache) Create(ctx context.Context, g *v1alpha1.ReplicationGroup) bool {
+// external manages ElastiCache replication groups using the AWS API.
+type external struct {
+	client elasticache.Client
+}
 
-	// Our create request will fail if auth is enabled but transit encryption is
-	// not. We don't check for the latter here because it's less surprising to
-	// submit the request as the operator intended and let the resource
-	// transition to failed with an explanatory message from AWS explaining that
-	// transit encryption is required.
-	if g.Spec.AuthEnabled {
-		at, err := util.GeneratePassword(maxAuthTokenData)
-		if err != nil {
-			g.Status.SetFailed(reasonCreatingResource, err.Error())
-			return true
-		}
-		e.authToken = at
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	g, ok := mg.(*v1alpha1.ReplicationGroup)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotReplicationGroup)
 	}
 
-	req := e.client.CreateReplicationGroupRequest(elasticache.NewCreateReplicationGroupInput(g, e.authToken))
-	req.SetContext(ctx)
-	if _, err := req.Send(); err != nil {
-		g.Status.SetFailed(reasonCreatingResource, err.Error())
-		return true
+	// The group is unnamed. Assume it has not been created in AWS.
+	if g.Status.GroupName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	g.Status.GroupName = elasticache.NewReplicationGroupID(g)
-	g.Status.UnsetAllConditions()
-	g.Status.SetCreating()
-	util.AddFinalizer(&g.ObjectMeta, finalizerName)
-
-	return true
-}
-
-// TODO(negz): This method's cyclomatic complexity is a little high. Consider
-// refactoring to reduce said complexity if you touch it.
-// nolint:gocyclo
-func (e *elastiCache) Sync(ctx context.Context, g *v1alpha1.ReplicationGroup) bool {
 	drg := e.client.DescribeReplicationGroupsRequest(elasticache.NewDescribeReplicationGroupsInput(g))
 	drg.SetContext(ctx)
 	rsp, err := drg.Send()
+	if aws.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
 	if err != nil {
-		g.Status.SetFailed(reasonSyncingResource, err.Error())
-		return true
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot describe replication group %s", g.Status.GroupName)
 	}
 	// DescribeReplicationGroups can return one or many replication groups. We
 	// ask for one group by name, so we should get either a single element list
-	//  or an error.
+	// or an error.
 	replicationGroup := rsp.ReplicationGroups[0]
 
 	g.Status.State = aws.StringValue(replicationGroup.Status)
-	g.Status.UnsetAllConditions()
-
-	switch g.Status.State {
-	case v1alpha1.StatusAvailable:
-		g.Status.SetReady()
-	case v1alpha1.StatusCreating:
-		g.Status.SetCreating()
-		return true
-	case v1alpha1.StatusDeleting:
-		g.Status.SetDeleting()
-		return false
-	default:
-		// TODO(negz): Don't requeue in this scenario? The instance could be
+	if g.Status.State != v1alpha1.StatusAvailable {
+		// TODO(negz): Don't poll in this scenario? The instance could be
 		// modifying, snapshotting, etc. It seems instances go into modifying by
 		// themselves shortly after creation, seemingly as part of the creation
 		// process?
-		return true
+		return core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
 	}
 
 	if replicationGroup.ConfigurationEndpoint != nil {
@@ -176,27 +136,26 @@ func (e *elastiCache) Sync(ctx context.Context, g *v1alpha1.ReplicationGroup) bo
 	g.Status.ClusterEnabled = aws.BoolValue(replicationGroup.ClusterEnabled)
 	g.Status.MemberClusters = replicationGroup.MemberClusters
 
-	ccsNeedsUpdate, err := e.cacheClustersNeedUpdate(ctx, g)
+	ccsNeedUpdate, err := e.cacheClustersNeedUpdate(ctx, g)
 	if err != nil {
-		g.Status.SetFailed(reasonSyncingResource, err.Error())
-		return true
+		return core.ExternalObservation{}, err
 	}
 
-	if !ccsNeedsUpdate && !elasticache.ReplicationGroupNeedsUpdate(g, replicationGroup) {
-		return false
-	}
+	o := core.ExternalObservation{
+		ResourceExists:   true,
+		ResourceReady:    true,
+		ResourceUpToDate: !ccsNeedUpdate && !elasticache.ReplicationGroupNeedsUpdate(g, replicationGroup),
 
-	mrg := e.client.ModifyReplicationGroupRequest(elasticache.NewModifyReplicationGroupInput(g))
-	mrg.SetContext(ctx)
-	if _, err := mrg.Send(); err != nil {
-		g.Status.SetFailed(reasonSyncingResource, err.Error())
-		return true
+		// TODO(negz): Include the ports here too?
+		ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(g.Status.Endpoint)},
 	}
-
-	return false
+	if !o.ResourceUpToDate {
+		o.Diff = elasticache.NewModifyReplicationGroupInput(g).String()
+	}
+	return o, nil
 }
 
-func (e *elastiCache) cacheClustersNeedUpdate(ctx context.Context, g *v1alpha1.ReplicationGroup) (bool, error) {
+func (e *external) cacheClustersNeedUpdate(ctx context.Context, g *v1alpha1.ReplicationGroup) (bool, error) {
 	for _, cc := range g.Status.MemberClusters {
 		dcc := e.client.DescribeCacheClustersRequest(elasticache.NewDescribeCacheClustersInput(cc))
 		dcc.SetContext(ctx)
@@ -216,149 +175,81 @@ func (e *elastiCache) cacheClustersNeedUpdate(ctx context.Context, g *v1alpha1.R
 	return false, nil
 }
 
-func (e *elastiCache) Delete(ctx context.Context, g *v1alpha1.ReplicationGroup) bool {
-	if g.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		req := e.client.DeleteReplicationGroupRequest(elasticache.NewDeleteReplicationGroupInput(g))
-		req.SetContext(ctx)
-		if _, err := req.Send(); err != nil {
-			g.Status.SetFailed(reasonDeletingResource, err.Error())
-			return true
-		}
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	g, ok := mg.(*v1alpha1.ReplicationGroup)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotReplicationGroup)
 	}
-	g.Status.SetDeleting()
-	util.RemoveFinalizer(&g.ObjectMeta, finalizerName)
-	return false
-}
-
-func (e *elastiCache) Key() string {
-	return e.authToken
-}
 
-// A connecter returns a createsyncdeletekeyer that can create, sync, and delete
-// AWS Replication Group resources with an external store - for example the AWS API.
-type connecter interface {
-	Connect(context.Context, *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error)
-}
-
-// providerConnecter is a connecter that returns a createsyncdeleter
-// authenticated using credentials read from a Crossplane Provider resource.
-type providerConnecter struct {
-	kube      client.Client
-	newClient func(creds []byte, region string) (elasticache.Client, error)
-}
-
-// Connect returns a createsyncdeletekeyer backed by the AWS API. AWS
-// credentials are read from the Crossplane Provider referenced by the supplied
-// ReplicationGroup.
-func (c *providerConnecter) Connect(ctx context.Context, g *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-	p := &awsv1alpha1.Provider{}
-	n := types.NamespacedName{Namespace: g.GetNamespace(), Name: g.Spec.ProviderRef.Name}
-	if err := c.kube.Get(ctx, n, p); err != nil {
-		return nil, errors.Wrapf(err, "cannot get provider %s", n)
-	}
-
-	if !p.IsValid() {
-		return nil, errors.Errorf("provider %s is not ready", n)
-	}
-
-	s := &corev1.Secret{}
-	n = types.NamespacedName{Namespace: p.Namespace, Name: p.Spec.Secret.Name}
-	if err := c.kube.Get(ctx, n, s); err != nil {
-		return nil, errors.Wrapf(err, "cannot get provider secret %s", n)
+	// Our create request will fail if auth is enabled but transit encryption is
+	// not. We don't check for the latter here because it's less surprising to
+	// submit the request as the operator intended and let the resource
+	// transition to failed with an explanatory message from AWS explaining that
+	// transit encryption is required.
+	var authToken string
+	if g.Spec.AuthEnabled {
+		at, err := util.GeneratePassword(maxAuthTokenData)
+		if err != nil {
+			return core.ExternalCreation{}, errors.Wrap(err, "cannot generate auth token")
+		}
+		authToken = at
 	}
 
-	client, err := c.newClient(s.Data[p.Spec.Secret.Key], p.Spec.Region)
-	return &elastiCache{client: client}, errors.Wrap(err, "cannot create new AWS Replication Group client")
-}
-
-// Reconciler reconciles ReplicationGroups read from the Kubernetes API
-// with an external store, typically the AWS API.
-type Reconciler struct {
-	connecter
-	kube client.Client
-}
+	id := elasticache.NewReplicationGroupID(g)
+	req := e.client.CreateReplicationGroupRequest(elasticache.NewCreateReplicationGroupInput(g, authToken))
+	req.SetContext(ctx)
+	_, err := req.Send()
 
-// Add creates a new ReplicationGroup Controller and adds it to the
-// Manager with default RBAC. The Manager will set fields on the Controller and
-// start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
-	r := &Reconciler{
-		connecter: &providerConnecter{kube: mgr.GetClient(), newClient: elasticache.NewClient},
-		kube:      mgr.GetClient(),
+	// The group was created by an earlier reconcile whose status update was
+	// lost. The auth token it was created with cannot be recovered.
+	if aws.IsErrorAlreadyExists(err) {
+		g.Status.GroupName = id
+		return core.ExternalCreation{}, nil
 	}
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
-		return errors.Wrap(err, "cannot create Kubernetes controller")
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create replication group %s", id)
 	}
 
-	return c.Watch(&source.Kind{Type: &v1alpha1.ReplicationGroup{}}, &handler.EnqueueRequestForObject{})
-}
-
-// Reconcile Google AWS Replication Group resources with the AWS API.
-func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", v1alpha1.ReplicationGroupKindAPIVersion, req)
-
-	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
-	defer cancel()
-
-	rd := &v1alpha1.ReplicationGroup{}
-	if err := r.kube.Get(ctx, req.NamespacedName, rd); err != nil {
-		if kerrors.IsNotFound(err) {
-			return reconcile.Result{Requeue: false}, nil
-		}
-		return reconcile.Result{Requeue: false}, errors.Wrapf(err, "cannot get resource %s", req.NamespacedName)
+	g.Status.GroupName = id
+	if authToken == "" {
+		return core.ExternalCreation{}, nil
 	}
+	return core.ExternalCreation{ConnectionDetails: core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(authToken),
+	}}, nil
+}
 
-	client, err := r.Connect(ctx, rd)
-	if err != nil {
-		rd.Status.SetFailed(reasonFetchingClient, err.Error())
-		return reconcile.Result{Requeue: true}, errors.Wrapf(r.kube.Update(ctx, rd), "cannot update resource %s", req.NamespacedName)
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	g, ok := mg.(*v1alpha1.ReplicationGroup)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotReplicationGroup)
 	}
 
-	// The resource has been deleted from the API server. Delete from AWS.
-	if rd.DeletionTimestamp != nil {
-		return reconcile.Result{Requeue: client.Delete(ctx, rd)}, errors.Wrapf(r.kube.Update(ctx, rd), "cannot update resource %s", req.NamespacedName)
-	}
+	mrg := e.client.ModifyReplicationGroupRequest(elasticache.NewModifyReplicationGroupInput(g))
+	mrg.SetContext(ctx)
+	_, err := mrg.Send()
+	return core.ExternalUpdate{}, errors.Wrapf(err, "cannot modify replication group %s", g.Status.GroupName)
+}
 
-	// The group is unnamed. Assume it has not been created in AWS.
-	if rd.Status.GroupName == "" {
-		return reconcile.Result{Requeue: client.Create(ctx, rd)}, errors.Wrapf(r.kube.Update(ctx, rd), "cannot update resource %s", req.NamespacedName)
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	g, ok := mg.(*v1alpha1.ReplicationGroup)
+	if !ok {
+		return errors.New(errNotReplicationGroup)
 	}
 
-	if err := r.upsertSecret(ctx, connectionSecret(rd, client.Key())); err != nil {
-		rd.Status.SetFailed(reasonSyncingSecret, err.Error())
-		return reconcile.Result{Requeue: true}, errors.Wrapf(r.kube.Update(ctx, rd), "cannot update resource %s", req.NamespacedName)
+	// we already started the deletion of the group, nothing more to do
+	if g.Status.State == v1alpha1.StatusDeleting {
+		return nil
 	}
 
-	// The resource exists in the API server and AWS. Sync it.
-	return reconcile.Result{Requeue: client.Sync(ctx, rd)}, errors.Wrapf(r.kube.Update(ctx, rd), "cannot update resource %s", req.NamespacedName)
-}
-
-func (r *Reconciler) upsertSecret(ctx context.Context, s *corev1.Secret) error {
-	n := types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}
-	if err := r.kube.Get(ctx, n, &corev1.Secret{}); err != nil {
-		if kerrors.IsNotFound(err) {
-			return errors.Wrapf(r.kube.Create(ctx, s), "cannot create secret %s", n)
-		}
-		return errors.Wrapf(err, "cannot get secret %s", n)
+	req := e.client.DeleteReplicationGroupRequest(elasticache.NewDeleteReplicationGroupInput(g))
+	req.SetContext(ctx)
+	if _, err := req.Send(); err != nil && !aws.IsErrorNotFound(err) {
+		return errors.Wrapf(err, "cannot delete replication group %s", g.Status.GroupName)
 	}
-	return errors.Wrapf(r.kube.Update(ctx, s), "cannot update secret %s", n)
-}
-
-func connectionSecret(g *v1alpha1.ReplicationGroup, password string) *corev1.Secret {
-	s := &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            g.ConnectionSecretName(),
-			Namespace:       g.Namespace,
-			OwnerReferences: []metav1.OwnerReference{g.OwnerReference()},
-		},
 
-		// TODO(negz): Include the ports here too?
-		Data: map[string][]byte{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(g.Status.Endpoint)},
-	}
-	if password != "" {
-		s.Data[corev1alpha1.ResourceCredentialsSecretPasswordKey] = []byte(password)
+	if g.Spec.ReclaimPolicy == corev1alpha1.ReclaimSnapshot {
+		g.Status.FinalSnapshot = elasticache.NewFinalSnapshotID(g)
 	}
-
-	return s
+	return nil
 }
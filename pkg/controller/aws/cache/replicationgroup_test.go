@@ -20,9 +20,9 @@ import (
 	"context"
 	"net/http"
 	"testing"
-	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	"github.com/aws/aws-sdk-go-v2/service/elasticache"
 	"github.com/go-test/deep"
 	"github.com/pkg/errors"
@@ -33,13 +33,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	elasticacheclient "github.com/crossplaneio/crossplane/pkg/clients/aws/elasticache"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/elasticache/fake"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -50,7 +50,6 @@ const (
 	id        = elasticacheclient.NamePrefix + "-efdd8494195d7940" // FNV-64a hash of uid
 
 	cacheNodeType            = "n1.super.cool"
-	authToken                = "coolToken"
 	autoFailoverEnabled      = true
 	cacheParameterGroupName  = "coolParamGroup"
 	engineVersion            = "5.0.0"
@@ -100,18 +99,10 @@ var (
 
 type replicationGroupModifier func(*v1alpha1.ReplicationGroup)
 
-func withConditions(c ...corev1alpha1.Condition) replicationGroupModifier {
-	return func(r *v1alpha1.ReplicationGroup) { r.Status.ConditionedStatus.Conditions = c }
-}
-
 func withState(s string) replicationGroupModifier {
 	return func(r *v1alpha1.ReplicationGroup) { r.Status.State = s }
 }
 
-func withFinalizers(f ...string) replicationGroupModifier {
-	return func(r *v1alpha1.ReplicationGroup) { r.ObjectMeta.Finalizers = f }
-}
-
 func withReclaimPolicy(p corev1alpha1.ReclaimPolicy) replicationGroupModifier {
 	return func(r *v1alpha1.ReplicationGroup) { r.Spec.ReclaimPolicy = p }
 }
@@ -128,10 +119,6 @@ func withPort(p int) replicationGroupModifier {
 	return func(r *v1alpha1.ReplicationGroup) { r.Status.Port = p }
 }
 
-func withDeletionTimestamp(t time.Time) replicationGroupModifier {
-	return func(r *v1alpha1.ReplicationGroup) { r.ObjectMeta.DeletionTimestamp = &metav1.Time{Time: t} }
-}
-
 func withAuth() replicationGroupModifier {
 	return func(r *v1alpha1.ReplicationGroup) { r.Spec.AuthEnabled = true }
 }
@@ -140,6 +127,10 @@ func withMemberClusters(members []string) replicationGroupModifier {
 	return func(r *v1alpha1.ReplicationGroup) { r.Status.MemberClusters = members }
 }
 
+func withFinalSnapshot(s string) replicationGroupModifier {
+	return func(r *v1alpha1.ReplicationGroup) { r.Status.FinalSnapshot = s }
+}
+
 func replicationGroup(rm ...replicationGroupModifier) *v1alpha1.ReplicationGroup {
 	r := &v1alpha1.ReplicationGroup{
 		ObjectMeta: meta,
@@ -164,477 +155,337 @@ func replicationGroup(rm ...replicationGroupModifier) *v1alpha1.ReplicationGroup
 	return r
 }
 
-// Test that our Reconciler implementation satisfies the Reconciler interface.
-var _ reconcile.Reconciler = &Reconciler{}
+// availableGroup returns a DescribeReplicationGroups client method that
+// returns an available replication group, modified by the supplied function.
+func availableGroup(m ...func(*elasticache.ReplicationGroup)) func(*elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
+	return func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
+		rg := elasticache.ReplicationGroup{
+			Status:                 aws.String(v1alpha1.StatusAvailable),
+			MemberClusters:         []string{cacheClusterID},
+			AutomaticFailover:      elasticache.AutomaticFailoverStatusEnabled,
+			CacheNodeType:          aws.String(cacheNodeType),
+			SnapshotRetentionLimit: aws.Int64(snapshotRetentionLimit),
+			SnapshotWindow:         aws.String(snapshotWindow),
+			ConfigurationEndpoint:  &elasticache.Endpoint{Address: aws.String(host), Port: aws.Int64(port)},
+		}
+		for _, fn := range m {
+			fn(&rg)
+		}
+		return elasticache.DescribeReplicationGroupsRequest{
+			Request: &aws.Request{
+				HTTPRequest: &http.Request{},
+				Data:        &elasticache.DescribeReplicationGroupsOutput{ReplicationGroups: []elasticache.ReplicationGroup{rg}},
+			},
+		}
+	}
+}
 
-func TestCreate(t *testing.T) {
+// cacheCluster returns a DescribeCacheClusters client method that returns a
+// cache cluster with the supplied maintenance window.
+func cacheCluster(maintenanceWindow string) func(*elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
+	return func(_ *elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
+		return elasticache.DescribeCacheClustersRequest{
+			Request: &aws.Request{
+				HTTPRequest: &http.Request{},
+				Data: &elasticache.DescribeCacheClustersOutput{
+					CacheClusters: []elasticache.CacheCluster{{
+						EngineVersion:              aws.String(engineVersion),
+						PreferredMaintenanceWindow: aws.String(maintenanceWindow),
+					}},
+				},
+			},
+		}
+	}
+}
+
+// Test that our connecter and external implementations satisfy their
+// interfaces.
+var _ core.ExternalConnecter = &connecter{}
+var _ core.ExternalClient = &external{}
+
+func TestConnect(t *testing.T) {
 	cases := []struct {
-		name        string
-		csdk        createsyncdeletekeyer
-		r           *v1alpha1.ReplicationGroup
-		want        *v1alpha1.ReplicationGroup
-		wantRequeue bool
+		name    string
+		conn    *connecter
+		i       *v1alpha1.ReplicationGroup
+		want    core.ExternalClient
+		wantErr error
 	}{
 		{
-			name: "SuccessfulCreate",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockCreateReplicationGroupRequest: func(_ *elasticache.CreateReplicationGroupInput) elasticache.CreateReplicationGroupRequest {
-					return elasticache.CreateReplicationGroupRequest{
-						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.CreateReplicationGroupOutput{}},
+			name: "SuccessfulConnect",
+			conn: &connecter{
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					switch key {
+					case client.ObjectKey{Namespace: namespace, Name: providerName}:
+						*obj.(*awsv1alpha1.Provider) = provider
+					case client.ObjectKey{Namespace: namespace, Name: providerSecretName}:
+						*obj.(*corev1.Secret) = providerSecret
 					}
-				},
-			}},
-			r: replicationGroup(withAuth()),
-			want: replicationGroup(
-				withAuth(),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-				withFinalizers(finalizerName),
-				withGroupName(id),
-			),
-			wantRequeue: true,
+					return nil
+				}},
+				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
+			},
+			i:    replicationGroup(),
+			want: &external{client: &fake.MockClient{}},
 		},
 		{
-			name: "FailedCreate",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockCreateReplicationGroupRequest: func(_ *elasticache.CreateReplicationGroupInput) elasticache.CreateReplicationGroupRequest {
-					return elasticache.CreateReplicationGroupRequest{
-						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
+			name: "FailedToGetProvider",
+			conn: &connecter{
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					return kerrors.NewNotFound(schema.GroupResource{}, providerName)
+				}},
+				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
+			},
+			i:       replicationGroup(),
+			wantErr: errors.WithStack(errors.Errorf("cannot get provider %s/%s:  \"%s\" not found", namespace, providerName, providerName)),
+		},
+		{
+			name: "FailedToAssertProviderIsValid",
+			conn: &connecter{
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					// This provider does not have condition ready, and thus is
+					// deemed invalid.
+					*obj.(*awsv1alpha1.Provider) = awsv1alpha1.Provider{
+						ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: providerName},
+						Spec: awsv1alpha1.ProviderSpec{
+							Secret: corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: providerSecretName},
+								Key:                  providerSecretKey,
+							},
+						},
 					}
-				},
-			}},
-			r: replicationGroup(),
-			want: replicationGroup(withConditions(
-				corev1alpha1.Condition{
-					Type:    corev1alpha1.Failed,
-					Status:  corev1.ConditionTrue,
-					Reason:  reasonCreatingResource,
-					Message: errorBoom.Error(),
-				},
-			)),
-			wantRequeue: true,
+					return nil
+				}},
+				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
+			},
+			i:       replicationGroup(),
+			wantErr: errors.Errorf("provider %s/%s is not ready", namespace, providerName),
+		},
+		{
+			name: "FailedToGetProviderSecret",
+			conn: &connecter{
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					switch key {
+					case client.ObjectKey{Namespace: namespace, Name: providerName}:
+						*obj.(*awsv1alpha1.Provider) = provider
+					case client.ObjectKey{Namespace: namespace, Name: providerSecretName}:
+						return kerrors.NewNotFound(schema.GroupResource{}, providerSecretName)
+					}
+					return nil
+				}},
+				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
+			},
+			i:       replicationGroup(),
+			wantErr: errors.WithStack(errors.Errorf("cannot get provider secret %s/%s:  \"%s\" not found", namespace, providerSecretName, providerSecretName)),
+		},
+		{
+			name: "FailedToCreateElastiCacheClient",
+			conn: &connecter{
+				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
+					switch key {
+					case client.ObjectKey{Namespace: namespace, Name: providerName}:
+						*obj.(*awsv1alpha1.Provider) = provider
+					case client.ObjectKey{Namespace: namespace, Name: providerSecretName}:
+						*obj.(*corev1.Secret) = providerSecret
+					}
+					return nil
+				}},
+				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return nil, errorBoom },
+			},
+			i:       replicationGroup(),
+			wantErr: errors.Wrap(errorBoom, "cannot create new AWS Replication Group client"),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotRequeue := tc.csdk.Create(ctx, tc.r)
+			got, gotErr := tc.conn.Connect(ctx, tc.i)
 
-			if gotRequeue != tc.wantRequeue {
-				t.Errorf("tc.csdk.Create(...): want: %t got: %t", tc.wantRequeue, gotRequeue)
+			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
+				t.Errorf("tc.conn.Connect(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := deep.Equal(tc.want, tc.r); diff != nil {
-				t.Errorf("r: want != got:\n%s", diff)
+			if diff := deep.Equal(tc.want, got); diff != nil {
+				t.Errorf("tc.conn.Connect(...): want != got:\n%s", diff)
 			}
 		})
 	}
 }
 
-func TestSync(t *testing.T) {
+func TestObserve(t *testing.T) {
 	cases := []struct {
-		name        string
-		csdk        createsyncdeletekeyer
-		r           *v1alpha1.ReplicationGroup
-		want        *v1alpha1.ReplicationGroup
-		wantRequeue bool
+		name    string
+		e       *external
+		r       *v1alpha1.ReplicationGroup
+		want    *v1alpha1.ReplicationGroup
+		wantObs core.ExternalObservation
+		wantErr error
 	}{
 		{
-			name: "SuccessfulSyncWhileGroupCreating",
-			csdk: &elastiCache{client: &fake.MockClient{
+			name:    "GroupNotCreated",
+			e:       &external{client: &fake.MockClient{}},
+			r:       replicationGroup(),
+			want:    replicationGroup(),
+			wantObs: core.ExternalObservation{ResourceExists: false},
+		},
+		{
+			name: "GroupNotFound",
+			e: &external{client: &fake.MockClient{
 				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
 					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{Status: aws.String(v1alpha1.StatusCreating)}},
-							},
-						},
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: awserr.New("ReplicationGroupNotFoundFault", "", nil)},
 					}
 				},
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonCreatingResource,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			want: replicationGroup(
-				withState(v1alpha1.StatusCreating),
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionFalse,
-						Reason:  reasonCreatingResource,
-						Message: errorBoom.Error(),
-					},
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue},
-				),
-			),
-			wantRequeue: true,
+			r:       replicationGroup(withGroupName(id)),
+			want:    replicationGroup(withGroupName(id)),
+			wantObs: core.ExternalObservation{ResourceExists: false},
 		},
 		{
-			name: "SuccessfulSyncWhileGroupDeleting",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{Status: aws.String(v1alpha1.StatusDeleting)}},
-							},
-						},
-					}
-				},
+			name: "GroupCreating",
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(func(rg *elasticache.ReplicationGroup) {
+					rg.Status = aws.String(v1alpha1.StatusCreating)
+				}),
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			want: replicationGroup(
-				withGroupName(name),
-				withState(v1alpha1.StatusDeleting),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
+			r:       replicationGroup(withGroupName(id)),
+			want:    replicationGroup(withGroupName(id), withState(v1alpha1.StatusCreating)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 		},
 		{
-			name: "SuccessfulSyncWhileGroupModifying",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{Status: aws.String(v1alpha1.StatusModifying)}},
-							},
-						},
-					}
-				},
+			name: "GroupModifying",
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(func(rg *elasticache.ReplicationGroup) {
+					rg.Status = aws.String(v1alpha1.StatusModifying)
+				}),
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue}),
-			),
-			want: replicationGroup(
-				withState(v1alpha1.StatusModifying),
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionFalse}),
-			),
-			wantRequeue: true,
+			r:       replicationGroup(withGroupName(id)),
+			want:    replicationGroup(withGroupName(id), withState(v1alpha1.StatusModifying)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 		},
 		{
-			name: "SuccessfulSyncWhileGroupAvailableAndDoesNotNeedUpdate",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{
-									Status:                 aws.String(v1alpha1.StatusAvailable),
-									MemberClusters:         []string{cacheClusterID},
-									AutomaticFailover:      elasticache.AutomaticFailoverStatusEnabled,
-									CacheNodeType:          aws.String(cacheNodeType),
-									SnapshotRetentionLimit: aws.Int64(snapshotRetentionLimit),
-									SnapshotWindow:         aws.String(snapshotWindow),
-									ConfigurationEndpoint:  &elasticache.Endpoint{Address: aws.String(host), Port: aws.Int64(port)},
-								}},
-							},
-						},
-					}
-				},
-				MockDescribeCacheClustersRequest: func(_ *elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
-					return elasticache.DescribeCacheClustersRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeCacheClustersOutput{
-								CacheClusters: []elasticache.CacheCluster{{
-									EngineVersion:              aws.String(engineVersion),
-									PreferredMaintenanceWindow: aws.String(maintenanceWindow),
-								}},
-							},
-						},
-					}
-				},
+			name: "GroupDeleting",
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(func(rg *elasticache.ReplicationGroup) {
+					rg.Status = aws.String(v1alpha1.StatusDeleting)
+				}),
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
+			r:       replicationGroup(withGroupName(id)),
+			want:    replicationGroup(withGroupName(id), withState(v1alpha1.StatusDeleting)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		{
+			name: "GroupAvailableAndDoesNotNeedUpdate",
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(),
+				MockDescribeCacheClustersRequest:     cacheCluster(maintenanceWindow),
+			}},
+			r: replicationGroup(withGroupName(id)),
 			want: replicationGroup(
+				withGroupName(id),
 				withState(v1alpha1.StatusAvailable),
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionFalse},
-					corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue},
-				),
-				withPort(port),
 				withEndpoint(host),
+				withPort(port),
 				withMemberClusters([]string{cacheClusterID}),
 			),
-			wantRequeue: false,
+			wantObs: core.ExternalObservation{
+				ResourceExists:    true,
+				ResourceReady:     true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host)},
+			},
 		},
 		{
-			name: "SuccessfulSyncWhileGroupAvailableAndNeedsUpdate",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{
-									Status:                 aws.String(v1alpha1.StatusAvailable),
-									MemberClusters:         []string{cacheClusterID},
-									AutomaticFailover:      elasticache.AutomaticFailoverStatusDisabled, // This field needs updating.
-									CacheNodeType:          aws.String(cacheNodeType),
-									SnapshotRetentionLimit: aws.Int64(snapshotRetentionLimit),
-									SnapshotWindow:         aws.String(snapshotWindow),
-									ConfigurationEndpoint:  &elasticache.Endpoint{Address: aws.String(host), Port: aws.Int64(port)},
-								}},
-							},
-						},
-					}
-				},
-				MockDescribeCacheClustersRequest: func(_ *elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
-					return elasticache.DescribeCacheClustersRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeCacheClustersOutput{
-								CacheClusters: []elasticache.CacheCluster{{
-									EngineVersion:              aws.String(engineVersion),
-									PreferredMaintenanceWindow: aws.String(maintenanceWindow),
-								}},
-							},
-						},
-					}
-				},
-				MockModifyReplicationGroupRequest: func(_ *elasticache.ModifyReplicationGroupInput) elasticache.ModifyReplicationGroupRequest {
-					return elasticache.ModifyReplicationGroupRequest{
-						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.ModifyReplicationGroupOutput{}},
-					}
-				},
+			name: "GroupAvailableAndNeedsUpdate",
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(func(rg *elasticache.ReplicationGroup) {
+					rg.AutomaticFailover = elasticache.AutomaticFailoverStatusDisabled
+				}),
+				MockDescribeCacheClustersRequest: cacheCluster(maintenanceWindow),
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
+			r: replicationGroup(withGroupName(id)),
 			want: replicationGroup(
+				withGroupName(id),
 				withState(v1alpha1.StatusAvailable),
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionFalse},
-					corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue},
-				),
-				withPort(port),
 				withEndpoint(host),
+				withPort(port),
 				withMemberClusters([]string{cacheClusterID}),
 			),
-			wantRequeue: false,
+			wantObs: core.ExternalObservation{
+				ResourceExists:    true,
+				ResourceReady:     true,
+				ResourceUpToDate:  false,
+				Diff:              elasticacheclient.NewModifyReplicationGroupInput(replicationGroup(withGroupName(id))).String(),
+				ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host)},
+			},
 		},
 		{
-			name: "SuccessfulSyncWhileGroupAvailableAndCacheClustersNeedUpdate",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{
-									Status:                 aws.String(v1alpha1.StatusAvailable),
-									MemberClusters:         []string{cacheClusterID},
-									AutomaticFailover:      elasticache.AutomaticFailoverStatusEnabled,
-									CacheNodeType:          aws.String(cacheNodeType),
-									SnapshotRetentionLimit: aws.Int64(snapshotRetentionLimit),
-									SnapshotWindow:         aws.String(snapshotWindow),
-									ConfigurationEndpoint:  &elasticache.Endpoint{Address: aws.String(host), Port: aws.Int64(port)},
-								}},
-							},
-						},
-					}
-				},
-				MockDescribeCacheClustersRequest: func(_ *elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
-					return elasticache.DescribeCacheClustersRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeCacheClustersOutput{
-								CacheClusters: []elasticache.CacheCluster{{
-									EngineVersion:              aws.String(engineVersion),
-									PreferredMaintenanceWindow: aws.String("never!"), // This field needs to be updated.
-								}},
-							},
-						},
-					}
-				},
-				MockModifyReplicationGroupRequest: func(_ *elasticache.ModifyReplicationGroupInput) elasticache.ModifyReplicationGroupRequest {
-					return elasticache.ModifyReplicationGroupRequest{
-						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.ModifyReplicationGroupOutput{}},
-					}
-				},
+			name: "GroupAvailableAndCacheClusterNeedsUpdate",
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(),
+				MockDescribeCacheClustersRequest:     cacheCluster("never!"),
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
+			r: replicationGroup(withGroupName(id)),
 			want: replicationGroup(
+				withGroupName(id),
 				withState(v1alpha1.StatusAvailable),
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionFalse},
-					corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue},
-				),
-				withPort(port),
 				withEndpoint(host),
+				withPort(port),
 				withMemberClusters([]string{cacheClusterID}),
 			),
-			wantRequeue: false,
+			wantObs: core.ExternalObservation{
+				ResourceExists:    true,
+				ResourceReady:     true,
+				ResourceUpToDate:  false,
+				Diff:              elasticacheclient.NewModifyReplicationGroupInput(replicationGroup(withGroupName(id))).String(),
+				ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host)},
+			},
 		},
 		{
 			name: "FailedDescribeReplicationGroups",
-			csdk: &elastiCache{client: &fake.MockClient{
+			e: &external{client: &fake.MockClient{
 				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
 					return elasticache.DescribeReplicationGroupsRequest{
 						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
 					}
 				},
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
-			want: replicationGroup(
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue},
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonSyncingResource,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			wantRequeue: true,
+			r:       replicationGroup(withGroupName(id)),
+			want:    replicationGroup(withGroupName(id)),
+			wantErr: errors.Wrapf(errorBoom, "cannot describe replication group %s", id),
 		},
 		{
 			name: "FailedDescribeCacheClusters",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{
-									Status:         aws.String(v1alpha1.StatusAvailable),
-									MemberClusters: []string{cacheClusterID},
-								}},
-							},
-						},
-					}
-				},
-				MockDescribeCacheClustersRequest: func(_ *elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
-					return elasticache.DescribeCacheClustersRequest{
-						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
-					}
-				},
-			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
-			want: replicationGroup(
-				withState(v1alpha1.StatusAvailable),
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionFalse},
-					corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue},
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonSyncingResource,
-						Message: errors.Wrapf(errorBoom, "cannot describe cache cluster %s", cacheClusterID).Error(),
-					},
-				),
-				withMemberClusters([]string{cacheClusterID}),
-			),
-			wantRequeue: true,
-		},
-		{
-			name: "FailedModifyReplicationGroup",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDescribeReplicationGroupsRequest: func(_ *elasticache.DescribeReplicationGroupsInput) elasticache.DescribeReplicationGroupsRequest {
-					return elasticache.DescribeReplicationGroupsRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeReplicationGroupsOutput{
-								ReplicationGroups: []elasticache.ReplicationGroup{{
-									Status:                 aws.String(v1alpha1.StatusAvailable),
-									MemberClusters:         []string{cacheClusterID},
-									AutomaticFailover:      elasticache.AutomaticFailoverStatusEnabled,
-									CacheNodeType:          aws.String(cacheNodeType),
-									SnapshotRetentionLimit: aws.Int64(snapshotRetentionLimit),
-									SnapshotWindow:         aws.String(snapshotWindow),
-									ConfigurationEndpoint:  &elasticache.Endpoint{Address: aws.String(host), Port: aws.Int64(port)},
-								}},
-							},
-						},
-					}
-				},
+			e: &external{client: &fake.MockClient{
+				MockDescribeReplicationGroupsRequest: availableGroup(),
 				MockDescribeCacheClustersRequest: func(_ *elasticache.DescribeCacheClustersInput) elasticache.DescribeCacheClustersRequest {
 					return elasticache.DescribeCacheClustersRequest{
-						Request: &aws.Request{
-							HTTPRequest: &http.Request{},
-							Data: &elasticache.DescribeCacheClustersOutput{
-								CacheClusters: []elasticache.CacheCluster{{
-									EngineVersion:              aws.String(engineVersion),
-									PreferredMaintenanceWindow: aws.String("never!"), // This field needs to be updated.
-								}},
-							},
-						},
-					}
-				},
-				MockModifyReplicationGroupRequest: func(_ *elasticache.ModifyReplicationGroupInput) elasticache.ModifyReplicationGroupRequest {
-					return elasticache.ModifyReplicationGroupRequest{
 						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
 					}
 				},
 			}},
-			r: replicationGroup(
-				withGroupName(name),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
+			r: replicationGroup(withGroupName(id)),
 			want: replicationGroup(
+				withGroupName(id),
 				withState(v1alpha1.StatusAvailable),
-				withGroupName(name),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionFalse},
-					corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue},
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonSyncingResource,
-						Message: errorBoom.Error(),
-					},
-				),
-				withPort(port),
 				withEndpoint(host),
+				withPort(port),
 				withMemberClusters([]string{cacheClusterID}),
 			),
-			wantRequeue: true,
+			wantErr: errors.Wrapf(errorBoom, "cannot describe cache cluster %s", cacheClusterID),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotRequeue := tc.csdk.Sync(ctx, tc.r)
+			gotObs, gotErr := tc.e.Observe(ctx, tc.r)
+
+			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
+				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
+			}
 
-			if gotRequeue != tc.wantRequeue {
-				t.Errorf("tc.csd.Sync(...): want: %t got: %t", tc.wantRequeue, gotRequeue)
+			if diff := deep.Equal(tc.wantObs, gotObs); diff != nil {
+				t.Errorf("tc.e.Observe(...): want != got:\n%s", diff)
 			}
 
 			if diff := deep.Equal(tc.want, tc.r); diff != nil {
@@ -644,555 +495,209 @@ func TestSync(t *testing.T) {
 	}
 }
 
-func TestDelete(t *testing.T) {
+func TestCreate(t *testing.T) {
 	cases := []struct {
-		name        string
-		csdk        createsyncdeletekeyer
-		r           *v1alpha1.ReplicationGroup
-		want        *v1alpha1.ReplicationGroup
-		wantRequeue bool
+		name          string
+		e             *external
+		r             *v1alpha1.ReplicationGroup
+		want          *v1alpha1.ReplicationGroup
+		wantAuthToken bool
+		wantErr       error
 	}{
 		{
-			name: "ReclaimRetainSuccessfulDelete",
-			csdk: &elastiCache{},
-			r:    replicationGroup(withFinalizers(finalizerName), withReclaimPolicy(corev1alpha1.ReclaimRetain)),
-			want: replicationGroup(
-				withReclaimPolicy(corev1alpha1.ReclaimRetain),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
+			name: "SuccessfulCreate",
+			e: &external{client: &fake.MockClient{
+				MockCreateReplicationGroupRequest: func(_ *elasticache.CreateReplicationGroupInput) elasticache.CreateReplicationGroupRequest {
+					return elasticache.CreateReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.CreateReplicationGroupOutput{}},
+					}
+				},
+			}},
+			r:    replicationGroup(),
+			want: replicationGroup(withGroupName(id)),
 		},
 		{
-			name: "ReclaimDeleteSuccessfulDelete",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDeleteReplicationGroupRequest: func(_ *elasticache.DeleteReplicationGroupInput) elasticache.DeleteReplicationGroupRequest {
-					return elasticache.DeleteReplicationGroupRequest{
-						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.DeleteReplicationGroupOutput{}},
+			name: "SuccessfulCreateWithAuth",
+			e: &external{client: &fake.MockClient{
+				MockCreateReplicationGroupRequest: func(i *elasticache.CreateReplicationGroupInput) elasticache.CreateReplicationGroupRequest {
+					if aws.StringValue(i.AuthToken) == "" {
+						t.Errorf("i.AuthToken: want non-empty auth token")
+					}
+					return elasticache.CreateReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.CreateReplicationGroupOutput{}},
 					}
 				},
 			}},
-			r: replicationGroup(withFinalizers(finalizerName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
-			want: replicationGroup(
-				withReclaimPolicy(corev1alpha1.ReclaimDelete),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
+			r:             replicationGroup(withAuth()),
+			want:          replicationGroup(withAuth(), withGroupName(id)),
+			wantAuthToken: true,
 		},
 		{
-			name: "ReclaimDeleteFailedDelete",
-			csdk: &elastiCache{client: &fake.MockClient{
-				MockDeleteReplicationGroupRequest: func(_ *elasticache.DeleteReplicationGroupInput) elasticache.DeleteReplicationGroupRequest {
-					return elasticache.DeleteReplicationGroupRequest{
+			name: "GroupAlreadyExists",
+			e: &external{client: &fake.MockClient{
+				MockCreateReplicationGroupRequest: func(_ *elasticache.CreateReplicationGroupInput) elasticache.CreateReplicationGroupRequest {
+					return elasticache.CreateReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: awserr.New("ReplicationGroupAlreadyExistsFault", "", nil)},
+					}
+				},
+			}},
+			r:    replicationGroup(withAuth()),
+			want: replicationGroup(withAuth(), withGroupName(id)),
+		},
+		{
+			name: "FailedCreate",
+			e: &external{client: &fake.MockClient{
+				MockCreateReplicationGroupRequest: func(_ *elasticache.CreateReplicationGroupInput) elasticache.CreateReplicationGroupRequest {
+					return elasticache.CreateReplicationGroupRequest{
 						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
 					}
 				},
 			}},
-			r: replicationGroup(withFinalizers(finalizerName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
-			want: replicationGroup(
-				withFinalizers(finalizerName),
-				withReclaimPolicy(corev1alpha1.ReclaimDelete),
-				withConditions(
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonDeletingResource,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			wantRequeue: true,
+			r:       replicationGroup(),
+			want:    replicationGroup(),
+			wantErr: errors.Wrapf(errorBoom, "cannot create replication group %s", id),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotRequeue := tc.csdk.Delete(ctx, tc.r)
+			got, gotErr := tc.e.Create(ctx, tc.r)
 
-			if gotRequeue != tc.wantRequeue {
-				t.Errorf("tc.csd.Delete(...): want: %t got: %t", tc.wantRequeue, gotRequeue)
+			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
+				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := deep.Equal(tc.want, tc.r); diff != nil {
-				t.Errorf("r: want != got:\n%s", diff)
+			gotAuthToken := len(got.ConnectionDetails[corev1alpha1.ResourceCredentialsSecretPasswordKey]) > 0
+			if gotAuthToken != tc.wantAuthToken {
+				t.Errorf("tc.e.Create(...): want auth token: %t got: %t", tc.wantAuthToken, gotAuthToken)
 			}
-		})
-	}
-}
-func TestKey(t *testing.T) {
-	cases := []struct {
-		name string
-		csdk createsyncdeletekeyer
-		want string
-	}{
-		{
-			name: "AuthTokenSet",
-			csdk: &elastiCache{authToken: authToken},
-			want: authToken,
-		},
-		{
-			name: "AuthTokenUnset",
-			csdk: &elastiCache{},
-			want: "",
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := tc.csdk.Key()
 
-			if got != tc.want {
-				t.Errorf("tc.csd.Key(...): want: %s got: %s", tc.want, got)
+			if diff := deep.Equal(tc.want, tc.r); diff != nil {
+				t.Errorf("r: want != got:\n%s", diff)
 			}
 		})
 	}
 }
 
-func TestConnect(t *testing.T) {
+func TestUpdate(t *testing.T) {
 	cases := []struct {
 		name    string
-		conn    connecter
-		i       *v1alpha1.ReplicationGroup
-		want    createsyncdeletekeyer
+		e       *external
+		r       *v1alpha1.ReplicationGroup
 		wantErr error
 	}{
 		{
-			name: "SuccessfulConnect",
-			conn: &providerConnecter{
-				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-					switch key {
-					case client.ObjectKey{Namespace: namespace, Name: providerName}:
-						*obj.(*awsv1alpha1.Provider) = provider
-					case client.ObjectKey{Namespace: namespace, Name: providerSecretName}:
-						*obj.(*corev1.Secret) = providerSecret
+			name: "SuccessfulUpdate",
+			e: &external{client: &fake.MockClient{
+				MockModifyReplicationGroupRequest: func(i *elasticache.ModifyReplicationGroupInput) elasticache.ModifyReplicationGroupRequest {
+					if aws.StringValue(i.CacheNodeType) != cacheNodeType {
+						t.Errorf("i.CacheNodeType: want %s, got %s", cacheNodeType, aws.StringValue(i.CacheNodeType))
 					}
-					return nil
-				}},
-				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
-			},
-			i:    replicationGroup(),
-			want: &elastiCache{client: &fake.MockClient{}},
-		},
-		{
-			name: "FailedToGetProvider",
-			conn: &providerConnecter{
-				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-					return kerrors.NewNotFound(schema.GroupResource{}, providerName)
-				}},
-				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
-			},
-			i:       replicationGroup(),
-			wantErr: errors.WithStack(errors.Errorf("cannot get provider %s/%s:  \"%s\" not found", namespace, providerName, providerName)),
-		},
-		{
-			name: "FailedToAssertProviderIsValid",
-			conn: &providerConnecter{
-				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-					// This provider does not have condition ready, and thus is
-					// deemed invalid.
-					*obj.(*awsv1alpha1.Provider) = awsv1alpha1.Provider{
-						ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: providerName},
-						Spec: awsv1alpha1.ProviderSpec{
-							Secret: corev1.SecretKeySelector{
-								LocalObjectReference: corev1.LocalObjectReference{Name: providerSecretName},
-								Key:                  providerSecretKey,
-							},
-						},
-					}
-					return nil
-				}},
-				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
-			},
-			i:       replicationGroup(),
-			wantErr: errors.Errorf("provider %s/%s is not ready", namespace, providerName),
-		},
-		{
-			name: "FailedToGetProviderSecret",
-			conn: &providerConnecter{
-				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-					switch key {
-					case client.ObjectKey{Namespace: namespace, Name: providerName}:
-						*obj.(*awsv1alpha1.Provider) = provider
-					case client.ObjectKey{Namespace: namespace, Name: providerSecretName}:
-						return kerrors.NewNotFound(schema.GroupResource{}, providerSecretName)
+					return elasticache.ModifyReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.ModifyReplicationGroupOutput{}},
 					}
-					return nil
-				}},
-				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return &fake.MockClient{}, nil },
-			},
-			i:       replicationGroup(),
-			wantErr: errors.WithStack(errors.Errorf("cannot get provider secret %s/%s:  \"%s\" not found", namespace, providerSecretName, providerSecretName)),
+				},
+			}},
+			r: replicationGroup(withGroupName(id)),
 		},
 		{
-			name: "FailedToCreateElastiCacheClient",
-			conn: &providerConnecter{
-				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-					switch key {
-					case client.ObjectKey{Namespace: namespace, Name: providerName}:
-						*obj.(*awsv1alpha1.Provider) = provider
-					case client.ObjectKey{Namespace: namespace, Name: providerSecretName}:
-						*obj.(*corev1.Secret) = providerSecret
+			name: "FailedUpdate",
+			e: &external{client: &fake.MockClient{
+				MockModifyReplicationGroupRequest: func(_ *elasticache.ModifyReplicationGroupInput) elasticache.ModifyReplicationGroupRequest {
+					return elasticache.ModifyReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
 					}
-					return nil
-				}},
-				newClient: func(_ []byte, _ string) (elasticacheclient.Client, error) { return nil, errorBoom },
-			},
-			i:       replicationGroup(),
-			want:    &elastiCache{},
-			wantErr: errors.Wrap(errorBoom, "cannot create new AWS Replication Group client"),
+				},
+			}},
+			r:       replicationGroup(withGroupName(id)),
+			wantErr: errors.Wrapf(errorBoom, "cannot modify replication group %s", id),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got, gotErr := tc.conn.Connect(ctx, tc.i)
+			_, gotErr := tc.e.Update(ctx, tc.r)
 
 			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
-				t.Errorf("tc.conn.Connect(...): want error != got error:\n%s", diff)
-			}
-
-			if diff := deep.Equal(tc.want, got); diff != nil {
-				t.Errorf("tc.conn.Connect(...): want != got:\n%s", diff)
+				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
 			}
 		})
 	}
 }
 
-type mockConnector struct {
-	MockConnect func(ctx context.Context, i *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error)
-}
-
-func (c *mockConnector) Connect(ctx context.Context, i *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-	return c.MockConnect(ctx, i)
-}
-
-type mockCSDK struct {
-	MockCreate func(ctx context.Context, g *v1alpha1.ReplicationGroup) bool
-	MockSync   func(ctx context.Context, g *v1alpha1.ReplicationGroup) bool
-	MockDelete func(ctx context.Context, g *v1alpha1.ReplicationGroup) bool
-	MockKey    func() string
-}
-
-func (csdk *mockCSDK) Create(ctx context.Context, g *v1alpha1.ReplicationGroup) bool {
-	return csdk.MockCreate(ctx, g)
-}
-
-func (csdk *mockCSDK) Sync(ctx context.Context, g *v1alpha1.ReplicationGroup) bool {
-	return csdk.MockSync(ctx, g)
-}
-
-func (csdk *mockCSDK) Delete(ctx context.Context, g *v1alpha1.ReplicationGroup) bool {
-	return csdk.MockDelete(ctx, g)
-}
-
-func (csdk *mockCSDK) Key() string {
-	return csdk.MockKey()
-}
+func TestDelete(t *testing.T) {
+	deleted := func(_ *elasticache.DeleteReplicationGroupInput) elasticache.DeleteReplicationGroupRequest {
+		return elasticache.DeleteReplicationGroupRequest{
+			Request: &aws.Request{HTTPRequest: &http.Request{}, Data: &elasticache.DeleteReplicationGroupOutput{}},
+		}
+	}
 
-func TestReconcile(t *testing.T) {
 	cases := []struct {
 		name    string
-		rec     *Reconciler
-		req     reconcile.Request
-		want    reconcile.Result
+		e       *external
+		r       *v1alpha1.ReplicationGroup
+		want    *v1alpha1.ReplicationGroup
 		wantErr error
 	}{
 		{
-			name: "SuccessfulDelete",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return &mockCSDK{MockDelete: func(_ context.Context, _ *v1alpha1.ReplicationGroup) bool { return false }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup(withGroupName(name), withDeletionTimestamp(time.Now())))
-						return nil
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: nil,
-		},
-		{
-			name: "SuccessfulCreate",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return &mockCSDK{MockCreate: func(_ context.Context, _ *v1alpha1.ReplicationGroup) bool { return true }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup())
-						return nil
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
-		},
-		{
-			name: "SuccessfulSync",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return &mockCSDK{
-						MockSync: func(_ context.Context, _ *v1alpha1.ReplicationGroup) bool { return false },
-						MockKey:  func() string { return "" },
-					}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case client.ObjectKey{Namespace: namespace, Name: name}:
-							*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup(withGroupName(name), withEndpoint(host)))
-						case client.ObjectKey{Namespace: namespace, Name: connectionSecretName}:
-							return kerrors.NewNotFound(schema.GroupResource{}, connectionSecretName)
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
-					MockCreate: func(_ context.Context, _ runtime.Object) error { return nil },
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: nil,
-		},
-		{
-			name: "FailedToGetNonexistentResource",
-			rec: &Reconciler{
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						return kerrors.NewNotFound(schema.GroupResource{}, name)
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: nil,
-		},
-		{
-			name: "FailedToGetExtantResource",
-			rec: &Reconciler{
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						return errorBoom
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: errors.Wrapf(errorBoom, "cannot get resource %s/%s", namespace, name),
+			name: "ReclaimDeleteSuccessfulDelete",
+			e:    &external{client: &fake.MockClient{MockDeleteReplicationGroupRequest: deleted}},
+			r:    replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+			want: replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
 		},
 		{
-			name: "FailedToConnect",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return nil, errorBoom
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup())
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						want := replicationGroup(withConditions(
-							corev1alpha1.Condition{
-								Type:    corev1alpha1.Failed,
-								Status:  corev1.ConditionTrue,
-								Reason:  reasonFetchingClient,
-								Message: errorBoom.Error(),
-							},
-						))
-						got := obj.(*v1alpha1.ReplicationGroup)
-						if diff := deep.Equal(want, got); diff != nil {
-							t.Errorf("kube.Update(...): want != got:\n%s", diff)
-						}
-						return nil
-					},
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			name: "ReclaimSnapshotSuccessfulDelete",
+			e:    &external{client: &fake.MockClient{MockDeleteReplicationGroupRequest: deleted}},
+			r:    replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimSnapshot)),
+			want: replicationGroup(
+				withGroupName(id),
+				withReclaimPolicy(corev1alpha1.ReclaimSnapshot),
+				withFinalSnapshot(id+"-final"),
+			),
 		},
 		{
-			name: "FailedToGetConnectionSecret",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return &mockCSDK{MockKey: func() string { return "" }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case types.NamespacedName{Namespace: namespace, Name: connectionSecretName}:
-							return errorBoom
-						case types.NamespacedName{Namespace: namespace, Name: name}:
-							*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup(withGroupName(name)))
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						want := replicationGroup(
-							withGroupName(name),
-							withConditions(
-								corev1alpha1.Condition{
-									Type:    corev1alpha1.Failed,
-									Status:  corev1.ConditionTrue,
-									Reason:  reasonSyncingSecret,
-									Message: errors.Wrapf(errorBoom, "cannot get secret %s/%s", namespace, connectionSecretName).Error(),
-								},
-							))
-						got := obj.(*v1alpha1.ReplicationGroup)
-						if diff := deep.Equal(want, got); diff != nil {
-							t.Errorf("kube.Update(...): want != got:\n%s", diff)
-						}
-						return nil
-					},
-				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			name: "GroupAlreadyDeleting",
+			e:    &external{client: &fake.MockClient{}},
+			r:    replicationGroup(withGroupName(id), withState(v1alpha1.StatusDeleting)),
+			want: replicationGroup(withGroupName(id), withState(v1alpha1.StatusDeleting)),
 		},
 		{
-			name: "FailedToCreateConnectionSecret",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return &mockCSDK{MockKey: func() string { return "" }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case types.NamespacedName{Namespace: namespace, Name: connectionSecretName}:
-							return kerrors.NewNotFound(schema.GroupResource{}, connectionSecretName)
-						case types.NamespacedName{Namespace: namespace, Name: name}:
-							*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup(withGroupName(name)))
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						want := replicationGroup(
-							withGroupName(name),
-							withConditions(
-								corev1alpha1.Condition{
-									Type:    corev1alpha1.Failed,
-									Status:  corev1.ConditionTrue,
-									Reason:  reasonSyncingSecret,
-									Message: errors.Wrapf(errorBoom, "cannot create secret %s/%s", namespace, connectionSecretName).Error(),
-								},
-							))
-						got := obj.(*v1alpha1.ReplicationGroup)
-						if diff := deep.Equal(want, got); diff != nil {
-							t.Errorf("kube.Update(...): want != got:\n%s", diff)
-						}
-						return nil
-					},
-					MockCreate: func(_ context.Context, obj runtime.Object) error { return errorBoom },
+			name: "GroupNotFound",
+			e: &external{client: &fake.MockClient{
+				MockDeleteReplicationGroupRequest: func(_ *elasticache.DeleteReplicationGroupInput) elasticache.DeleteReplicationGroupRequest {
+					return elasticache.DeleteReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: awserr.New("ReplicationGroupNotFoundFault", "", nil)},
+					}
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}},
+			r:    replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+			want: replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
 		},
 		{
-			name: "FailedToUpdateConnectionSecret",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.ReplicationGroup) (createsyncdeletekeyer, error) {
-					return &mockCSDK{MockKey: func() string { return "" }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case types.NamespacedName{Namespace: namespace, Name: connectionSecretName}:
-							return nil
-						case types.NamespacedName{Namespace: namespace, Name: name}:
-							*obj.(*v1alpha1.ReplicationGroup) = *(replicationGroup(withGroupName(name)))
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						switch got := obj.(type) {
-						case *corev1.Secret:
-							return errorBoom
-						case *v1alpha1.ReplicationGroup:
-							want := replicationGroup(
-								withGroupName(name),
-								withConditions(
-									corev1alpha1.Condition{
-										Type:    corev1alpha1.Failed,
-										Status:  corev1.ConditionTrue,
-										Reason:  reasonSyncingSecret,
-										Message: errors.Wrapf(errorBoom, "cannot update secret %s/%s", namespace, connectionSecretName).Error(),
-									},
-								))
-							if diff := deep.Equal(want, got); diff != nil {
-								t.Errorf("kube.Update(...): want != got:\n%s", diff)
-							}
-						}
-						return nil
-					},
+			name: "FailedDelete",
+			e: &external{client: &fake.MockClient{
+				MockDeleteReplicationGroupRequest: func(_ *elasticache.DeleteReplicationGroupInput) elasticache.DeleteReplicationGroupRequest {
+					return elasticache.DeleteReplicationGroupRequest{
+						Request: &aws.Request{HTTPRequest: &http.Request{}, Error: errorBoom},
+					}
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}},
+			r:       replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+			want:    replicationGroup(withGroupName(id), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+			wantErr: errors.Wrapf(errorBoom, "cannot delete replication group %s", id),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotResult, gotErr := tc.rec.Reconcile(tc.req)
+			gotErr := tc.e.Delete(ctx, tc.r)
 
 			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
-				t.Errorf("tc.rec.Reconcile(...): want error != got error:\n%s", diff)
+				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := deep.Equal(tc.want, gotResult); diff != nil {
-				t.Errorf("tc.rec.Reconcile(...): want != got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestConnectionSecret(t *testing.T) {
-	cases := []struct {
-		name     string
-		r        *v1alpha1.ReplicationGroup
-		password string
-		want     *corev1.Secret
-	}{
-		{
-			name:     "Successful",
-			r:        replicationGroup(withEndpoint(host)),
-			password: authToken,
-			want: &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      connectionSecretName,
-					Namespace: namespace,
-					OwnerReferences: []metav1.OwnerReference{{
-						APIVersion: v1alpha1.APIVersion,
-						Kind:       v1alpha1.ReplicationGroupKind,
-						Name:       name,
-						UID:        uid,
-					}},
-				},
-				Data: map[string][]byte{
-					corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host),
-					corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(authToken),
-				},
-			},
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := connectionSecret(tc.r, tc.password)
-			if diff := deep.Equal(tc.want, got); diff != nil {
-				t.Errorf("connectionSecret(...): want != got:\n%s", diff)
+			if diff := deep.Equal(tc.want, tc.r); diff != nil {
+				t.Errorf("r: want != got:\n%s", diff)
 			}
 		})
 	}
@@ -20,392 +20,348 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"log"
+	"reflect"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
-	awsClient "github.com/crossplaneio/crossplane/pkg/clients/aws"
+	awsclient "github.com/crossplaneio/crossplane/pkg/clients/aws"
 	cloudformationclient "github.com/crossplaneio/crossplane/pkg/clients/aws/cloudformation"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/eks"
-	"github.com/crossplaneio/crossplane/pkg/util"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
 	controllerName    = "eks.compute.aws.crossplane.io"
-	finalizer         = "finalizer." + controllerName
 	clusterNamePrefix = "eks-"
 
-	errorClusterClient   = "Failed to create cluster client"
-	errorCreateCluster   = "Failed to create new cluster"
-	errorSyncCluster     = "Failed to sync cluster state"
-	errorDeleteCluster   = "Failed to delete cluster"
 	eksAuthConfigMapName = "aws-auth"
 	eksAuthMapRolesKey   = "mapRoles"
 	eksAuthMapUsersKey   = "mapUsers"
-)
 
-var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	errNotEKSCluster = "managed resource is not an EKS cluster"
 )
 
-// Add creates a new Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
-// and Start it when the Manager is Started.
+// Add creates a new EKSCluster controller and adds it to the Manager. The
+// Manager will set fields on the controller and start it when the Manager is
+// started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	c := &connecter{
+		kube:       mgr.GetClient(),
+		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+		newClient:  eks.NewClient,
+	}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &awscomputev1alpha1.EKSCluster{} }, c,
+		awsclient.ClassifyError, corev1alpha1.ReclaimDelete)
 }
 
-// Reconciler reconciles a Provider object
-type Reconciler struct {
-	client.Client
-	scheme     *runtime.Scheme
+// connecter returns EKS clients authenticated using the credentials of the
+// AWS Provider referenced by an EKSCluster.
+type connecter struct {
+	kube       client.Client
 	kubeclient kubernetes.Interface
-	recorder   record.EventRecorder
-
-	connect func(*awscomputev1alpha1.EKSCluster) (eks.Client, error)
-	create  func(*awscomputev1alpha1.EKSCluster, eks.Client) (reconcile.Result, error)
-	sync    func(*awscomputev1alpha1.EKSCluster, eks.Client) (reconcile.Result, error)
-	delete  func(*awscomputev1alpha1.EKSCluster, eks.Client) (reconcile.Result, error)
-	secret  func(*eks.Cluster, *awscomputev1alpha1.EKSCluster, eks.Client) error
-	awsauth func(*eks.Cluster, *awscomputev1alpha1.EKSCluster, eks.Client, string) error
-}
-
-// newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	r := &Reconciler{
-		Client:     mgr.GetClient(),
-		scheme:     mgr.GetScheme(),
-		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
-		recorder:   mgr.GetRecorder(controllerName),
-	}
-	r.connect = r._connect
-	r.create = r._create
-	r.sync = r._sync
-	r.delete = r._delete
-	r.secret = r._secret
-	r.awsauth = r._awsauth
-	return r
+	newClient  func(*aws.Config) eks.Client
 }
 
-// add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
-	}
-
-	// Watch for changes to Provider
-	err = c.Watch(&source.Kind{Type: &awscomputev1alpha1.EKSCluster{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(*awscomputev1alpha1.EKSCluster)
+	if !ok {
+		return nil, errors.New(errNotEKSCluster)
 	}
 
-	return nil
-}
-
-// fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *awscomputev1alpha1.EKSCluster, reason, msg string) (reconcile.Result, error) {
-	instance.Status.SetFailed(reason, msg)
-	return resultRequeue, r.Update(context.TODO(), instance)
-}
-
-func (r *Reconciler) _connect(instance *awscomputev1alpha1.EKSCluster) (eks.Client, error) {
-	// Fetch Provider
 	p := &awsv1alpha1.Provider{}
-	providerNamespacedName := types.NamespacedName{
-		Namespace: instance.Namespace,
-		Name:      instance.Spec.ProviderRef.Name,
-	}
-	err := r.Get(ctx, providerNamespacedName, p)
-	if err != nil {
-		return nil, err
+	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
 	}
 
-	// Check provider status
 	if !p.IsValid() {
-		return nil, fmt.Errorf("provider status is invalid")
+		return nil, errors.Errorf("provider %s is not ready", n)
 	}
 
-	// Get Provider's AWS Config
-	config, err := awsClient.Config(r.kubeclient, p)
+	config, err := awsclient.Config(c.kubeclient, p)
 	if err != nil {
-		return nil, err
+		return nil, errors.Wrapf(err, "cannot get AWS config of provider %s", n)
 	}
 
 	// Connection Region must be with Spec.Region
-	if string(instance.Spec.Region) != config.Region {
-		config.Region = string(instance.Spec.Region)
-	}
+	config.Region = string(i.Spec.Region)
 
-	// Create new EKS Client
-	return eks.NewClient(config), nil
+	return &external{client: c.newClient(config), newClientset: newClientset}, nil
 }
 
-func (r *Reconciler) _create(instance *awscomputev1alpha1.EKSCluster, client eks.Client) (reconcile.Result, error) {
-	clusterName := fmt.Sprintf("%s%s", clusterNamePrefix, instance.UID)
-
-	// Create Master
-	_, err := client.Create(clusterName, instance.Spec)
-	if err != nil && !eks.IsErrorAlreadyExists(err) {
-		if eks.IsErrorBadRequest(err) {
-			instance.Status.SetFailed(errorCreateCluster, err.Error())
-			// do not requeue on bad requests
-			return result, r.Update(ctx, instance)
-		}
-		return r.fail(instance, errorCreateCluster, err.Error())
-	}
-
-	// Update status
-	instance.Status.State = awscomputev1alpha1.ClusterStatusCreating
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetCreating()
-	instance.Status.ClusterName = clusterName
+// newClientset returns a clientset for the supplied remote cluster config.
+func newClientset(c *rest.Config) (kubernetes.Interface, error) {
+	return kubernetes.NewForConfig(c)
+}
 
-	return resultRequeue, r.Update(ctx, instance)
+// external manages EKS clusters and their worker nodes using the EKS and
+// CloudFormation APIs.
+type external struct {
+	client       eks.Client
+	newClientset func(*rest.Config) (kubernetes.Interface, error)
 }
 
-// generateAWSAuthConfigMap generates the configmap for configure auth
-func generateAWSAuthConfigMap(instance *awscomputev1alpha1.EKSCluster, workerARN string) (*v1.ConfigMap, error) {
-	data := map[string]string{}
-	defaultRole := awscomputev1alpha1.MapRole{
-		RoleARN:  workerARN,
-		Username: "system:node:{{EC2PrivateDNSName}}",
-		Groups:   []string{"system:bootstrappers", "system:nodes"},
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(*awscomputev1alpha1.EKSCluster)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotEKSCluster)
 	}
 
-	// Serialize mapRoles
-	roles := make([]awscomputev1alpha1.MapRole, len(instance.Spec.MapRoles))
-	copy(roles, instance.Spec.MapRoles)
-	roles = append(roles, defaultRole)
+	// The cluster is unnamed. Assume it has not been created in AWS.
+	if i.Status.ClusterName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
 
-	rolesMarshalled, err := yaml.Marshal(roles)
+	cluster, err := e.client.Get(i.Status.ClusterName)
+	if awsclient.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
 	if err != nil {
-		return nil, err
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get EKS cluster %s", i.Status.ClusterName)
 	}
+	i.Status.ProviderID = cluster.ARN
 
-	data[eksAuthMapRolesKey] = string(rolesMarshalled)
+	if cluster.Status == awscomputev1alpha1.ClusterStatusFailed {
+		i.Status.State = cluster.Status
+		return core.ExternalObservation{}, requeue.Terminal(errors.Errorf("EKS cluster %s is in failed state", i.Status.ClusterName))
+	}
 
-	// Serialize mapUsers
-	if len(instance.Spec.MapUsers) > 0 {
-		usersMarshalled, err := yaml.Marshal(instance.Spec.MapUsers)
-		if err != nil {
-			return nil, err
-		}
-		data[eksAuthMapUsersKey] = string(usersMarshalled)
+	o := core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}
+	if cluster.Status != awscomputev1alpha1.ClusterStatusActive {
+		i.Status.State = cluster.Status
+		return o, nil
 	}
 
-	name := eksAuthConfigMapName
-	namespace := "kube-system"
-	cm := v1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
-		},
-		Data: data,
+	// The cluster is not considered active until its worker nodes exist and
+	// are allowed to join it.
+	i.Status.State = awscomputev1alpha1.ClusterStatusCreating
+
+	// The worker nodes are created by Update.
+	if i.Status.CloudFormationStackID == "" {
+		o.ResourceUpToDate = false
+		return o, nil
 	}
 
-	return &cm, nil
-}
+	workers, err := e.client.GetWorkerNodes(i.Status.CloudFormationStackID)
+	if err != nil {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get worker nodes of EKS cluster %s", i.Status.ClusterName)
+	}
+	if !cloudformationclient.IsCompletedState(workers.WorkersStatus) {
+		return o, nil
+	}
 
-// _awsauth generates an aws-auth configmap and pushes it to the remote eks cluster to configure auth
-func (r *Reconciler) _awsauth(cluster *eks.Cluster, instance *awscomputev1alpha1.EKSCluster, client eks.Client, workerARN string) error {
-	cm, err := generateAWSAuthConfigMap(instance, workerARN)
+	clientset, token, err := e.connectCluster(cluster, i)
 	if err != nil {
-		return err
+		return core.ExternalObservation{}, err
 	}
 
-	// Sync aws-auth to remote eks cluster to configure it's auth.
-	token, err := client.ConnectionToken(instance.Status.ClusterName)
+	// The aws-auth configmap is pushed by Update.
+	want, err := generateAWSAuthConfigMap(i, workers.WorkerARN)
 	if err != nil {
-		return err
+		return core.ExternalObservation{}, errors.Wrap(err, "cannot generate aws-auth configmap")
+	}
+	got, err := clientset.CoreV1().ConfigMaps(want.Namespace).Get(want.Name, metav1.GetOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get aws-auth configmap of EKS cluster %s", i.Status.ClusterName)
+	}
+	if err != nil || !reflect.DeepEqual(got.Data, want.Data) {
+		o.ResourceUpToDate = false
+		return o, nil
 	}
 
-	// Client to eks cluster
+	// Avoid double base64 encoding on secret
 	caData, err := base64.StdEncoding.DecodeString(cluster.CA)
 	if err != nil {
-		return err
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot decode CA data of EKS cluster %s", i.Status.ClusterName)
 	}
 
-	c := rest.Config{
-		Host: cluster.Endpoint,
-		TLSClientConfig: rest.TLSClientConfig{
-			CAData: caData,
-		},
-		BearerToken: token,
+	i.Status.State = awscomputev1alpha1.ClusterStatusActive
+	i.Status.Endpoint = cluster.Endpoint
+	i.Status.ConnectionSecretRef = v1.LocalObjectReference{Name: i.ConnectionSecretName()}
+
+	o.ResourceReady = true
+	o.ConnectionDetails = core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(cluster.Endpoint),
+		corev1alpha1.ResourceCredentialsSecretCAKey:       caData,
+		corev1alpha1.ResourceCredentialsTokenKey:          []byte(token),
 	}
+	return o, nil
+}
 
-	clientset, err := kubernetes.NewForConfig(&c)
-	if err != nil {
-		return err
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(*awscomputev1alpha1.EKSCluster)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotEKSCluster)
 	}
 
-	// Create or update aws-auth configmap on eks cluster
-	_, err = clientset.CoreV1().ConfigMaps(cm.Namespace).Create(cm)
-	if err != nil {
-		if apierrors.IsAlreadyExists(err) {
-			_, err = clientset.CoreV1().ConfigMaps(cm.Namespace).Update(cm)
-		}
+	name := fmt.Sprintf("%s%s", clusterNamePrefix, i.UID)
+	if _, err := e.client.Create(name, i.Spec); err != nil && !awsclient.IsErrorAlreadyExists(err) {
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create EKS cluster %s", name)
 	}
 
-	return err
+	i.Status.State = awscomputev1alpha1.ClusterStatusCreating
+	i.Status.ClusterName = name
+	return core.ExternalCreation{}, nil
 }
 
-func (r *Reconciler) _sync(instance *awscomputev1alpha1.EKSCluster, client eks.Client) (reconcile.Result, error) {
-	cluster, err := client.Get(instance.Status.ClusterName)
-	if err != nil {
-		return r.fail(instance, errorSyncCluster, err.Error())
-	}
-
-	if cluster.Status != awscomputev1alpha1.ClusterStatusActive {
-		return resultRequeue, nil
+// Update creates the worker nodes of an active EKS cluster, then configures
+// the cluster to allow them to join it.
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	i, ok := mg.(*awscomputev1alpha1.EKSCluster)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotEKSCluster)
 	}
 
-	// Create workers
-	if instance.Status.CloudFormationStackID == "" {
-		clusterWorkers, err := client.CreateWorkerNodes(instance.Status.ClusterName, instance.Spec)
+	if i.Status.CloudFormationStackID == "" {
+		workers, err := e.client.CreateWorkerNodes(i.Status.ClusterName, i.Spec)
 		if err != nil {
-			return r.fail(instance, errorSyncCluster, err.Error())
+			return core.ExternalUpdate{}, errors.Wrapf(err, "cannot create worker nodes of EKS cluster %s", i.Status.ClusterName)
 		}
-		instance.Status.CloudFormationStackID = clusterWorkers.WorkerStackID
-		return resultRequeue, r.Update(ctx, instance)
+		i.Status.CloudFormationStackID = workers.WorkerStackID
+		return core.ExternalUpdate{}, nil
 	}
 
-	clusterWorker, err := client.GetWorkerNodes(instance.Status.CloudFormationStackID)
+	cluster, err := e.client.Get(i.Status.ClusterName)
 	if err != nil {
-		return r.fail(instance, errorSyncCluster, err.Error())
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot get EKS cluster %s", i.Status.ClusterName)
 	}
+	workers, err := e.client.GetWorkerNodes(i.Status.CloudFormationStackID)
+	if err != nil {
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot get worker nodes of EKS cluster %s", i.Status.ClusterName)
+	}
+
+	return core.ExternalUpdate{}, errors.Wrapf(e.awsauth(cluster, i, workers.WorkerARN),
+		"cannot set auth map on EKS cluster %s", i.Status.ClusterName)
+}
 
-	if !cloudformationclient.IsCompletedState(clusterWorker.WorkersStatus) {
-		return resultRequeue, r.Update(ctx, instance)
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(*awscomputev1alpha1.EKSCluster)
+	if !ok {
+		return errors.New(errNotEKSCluster)
 	}
 
-	if err := r.awsauth(cluster, instance, client, clusterWorker.WorkerARN); err != nil {
-		return r.fail(instance, errorSyncCluster, fmt.Sprintf("failed to set auth map on eks: %s", err.Error()))
+	if i.Status.State == awscomputev1alpha1.ClusterStatusDeleting {
+		return nil
 	}
 
-	if err := r.secret(cluster, instance, client); err != nil {
-		return r.fail(instance, errorSyncCluster, err.Error())
+	var deleteErrors []string
+	if err := e.client.Delete(i.Status.ClusterName); err != nil && !awsclient.IsErrorNotFound(err) {
+		deleteErrors = append(deleteErrors, fmt.Sprintf("Master Delete Error: %s", err.Error()))
 	}
 
-	// update resource status
-	instance.Status.Endpoint = cluster.Endpoint
-	instance.Status.State = awscomputev1alpha1.ClusterStatusActive
-	instance.Status.SetReady()
+	if i.Status.CloudFormationStackID != "" {
+		if err := e.client.DeleteWorkerNodes(i.Status.CloudFormationStackID); err != nil && !awsclient.IsErrorNotFound(err) {
+			deleteErrors = append(deleteErrors, fmt.Sprintf("Worker Delete Error: %s", err.Error()))
+		}
+	}
 
-	return result, r.Update(ctx, instance)
+	if len(deleteErrors) > 0 {
+		return errors.Errorf("cannot delete EKS cluster %s: %s", i.Status.ClusterName, strings.Join(deleteErrors, ", "))
+	}
+	return nil
 }
 
-func (r *Reconciler) _secret(cluster *eks.Cluster, instance *awscomputev1alpha1.EKSCluster, client eks.Client) error {
-	token, err := client.ConnectionToken(instance.Status.ClusterName)
+// connectCluster returns a clientset for the supplied EKS cluster, along with
+// the bearer token it authenticates with.
+func (e *external) connectCluster(cluster *eks.Cluster, i *awscomputev1alpha1.EKSCluster) (kubernetes.Interface, string, error) {
+	token, err := e.client.ConnectionToken(i.Status.ClusterName)
 	if err != nil {
-		return err
+		return nil, "", errors.Wrapf(err, "cannot get connection token of EKS cluster %s", i.Status.ClusterName)
 	}
 
-	// Avoid double base64 encoding on secret
+	// Client to eks cluster
 	caData, err := base64.StdEncoding.DecodeString(cluster.CA)
 	if err != nil {
-		return err
+		return nil, "", errors.Wrapf(err, "cannot decode CA data of EKS cluster %s", i.Status.ClusterName)
 	}
 
-	secret := instance.ConnectionSecret()
-	data := make(map[string][]byte)
-	data[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(cluster.Endpoint)
-	data[corev1alpha1.ResourceCredentialsSecretCAKey] = caData
-	data[corev1alpha1.ResourceCredentialsTokenKey] = []byte(token)
-	secret.Data = data
-
-	// create connection secret
-	if _, err := util.ApplySecret(r.kubeclient, secret); err != nil {
-		return err
+	c := &rest.Config{
+		Host: cluster.Endpoint,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: caData,
+		},
+		BearerToken: token,
 	}
 
-	// Set secret reference
-	instance.SetConnectionSecretReference(secret)
-
-	return nil
+	clientset, err := e.newClientset(c)
+	return clientset, token, errors.Wrapf(err, "cannot create client for EKS cluster %s", i.Status.ClusterName)
 }
 
-// _delete check reclaim policy and if needed delete the eks cluster resource
-func (r *Reconciler) _delete(instance *awscomputev1alpha1.EKSCluster, client eks.Client) (reconcile.Result, error) {
-	if instance.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		var deleteErrors []string
-		if err := client.Delete(instance.Status.ClusterName); err != nil && !eks.IsErrorNotFound(err) {
-			deleteErrors = append(deleteErrors, fmt.Sprintf("Master Delete Error: %s", err.Error()))
-		}
-
-		if instance.Status.CloudFormationStackID != "" {
-			if err := client.DeleteWorkerNodes(instance.Status.CloudFormationStackID); err != nil && !cloudformationclient.IsErrorNotFound(err) {
-				deleteErrors = append(deleteErrors, fmt.Sprintf("Worker Delete Error: %s", err.Error()))
-			}
-		}
+// awsauth generates an aws-auth configmap and pushes it to the remote eks
+// cluster to configure auth.
+func (e *external) awsauth(cluster *eks.Cluster, i *awscomputev1alpha1.EKSCluster, workerARN string) error {
+	cm, err := generateAWSAuthConfigMap(i, workerARN)
+	if err != nil {
+		return err
+	}
 
-		if len(deleteErrors) > 0 {
-			return r.fail(instance, errorDeleteCluster, strings.Join(deleteErrors, ", "))
-		}
+	clientset, _, err := e.connectCluster(cluster, i)
+	if err != nil {
+		return err
 	}
 
-	util.RemoveFinalizer(&instance.ObjectMeta, finalizer)
-	instance.Status.SetDeleting()
-	return result, r.Update(ctx, instance)
+	// Create or update aws-auth configmap on eks cluster
+	_, err = clientset.CoreV1().ConfigMaps(cm.Namespace).Create(cm)
+	if kerrors.IsAlreadyExists(err) {
+		_, err = clientset.CoreV1().ConfigMaps(cm.Namespace).Update(cm)
+	}
+	return err
 }
 
-// Reconcile reads that state of the cluster for a Provider object and makes changes based on the state read
-// and what is in the Provider.Spec
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", awscomputev1alpha1.EKSClusterKindAPIVersion, request)
-	// Fetch the Provider instance
-	instance := &awscomputev1alpha1.EKSCluster{}
-	err := r.Get(ctx, request.NamespacedName, instance)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		return reconcile.Result{}, err
+// generateAWSAuthConfigMap generates the configmap for configure auth
+func generateAWSAuthConfigMap(instance *awscomputev1alpha1.EKSCluster, workerARN string) (*v1.ConfigMap, error) {
+	data := map[string]string{}
+	defaultRole := awscomputev1alpha1.MapRole{
+		RoleARN:  workerARN,
+		Username: "system:node:{{EC2PrivateDNSName}}",
+		Groups:   []string{"system:bootstrappers", "system:nodes"},
 	}
 
-	// Create EKS Client
-	eksClient, err := r.connect(instance)
+	// Serialize mapRoles
+	roles := make([]awscomputev1alpha1.MapRole, len(instance.Spec.MapRoles))
+	copy(roles, instance.Spec.MapRoles)
+	roles = append(roles, defaultRole)
+
+	rolesMarshalled, err := yaml.Marshal(roles)
 	if err != nil {
-		return r.fail(instance, errorClusterClient, err.Error())
+		return nil, err
 	}
 
-	// Add finalizer
-	util.AddFinalizer(&instance.ObjectMeta, finalizer)
+	data[eksAuthMapRolesKey] = string(rolesMarshalled)
 
-	// Check for deletion
-	if instance.DeletionTimestamp != nil {
-		return r.delete(instance, eksClient)
+	// Serialize mapUsers
+	if len(instance.Spec.MapUsers) > 0 {
+		usersMarshalled, err := yaml.Marshal(instance.Spec.MapUsers)
+		if err != nil {
+			return nil, err
+		}
+		data[eksAuthMapUsersKey] = string(usersMarshalled)
 	}
 
-	// Create cluster instance
-	if instance.Status.ClusterName == "" {
-		return r.create(instance, eksClient)
+	name := eksAuthConfigMapName
+	namespace := "kube-system"
+	cm := v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Data: data,
 	}
 
-	// Sync cluster instance status with cluster status
-	return r.sync(instance, eksClient)
+	return &cm, nil
 }
@@ -17,48 +17,51 @@ limitations under the License.
 package compute
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
 	"github.com/ghodss/yaml"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
 	. "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
-	. "k8s.io/client-go/testing"
-	. "sigs.k8s.io/controller-runtime/pkg/client/fake"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"k8s.io/client-go/rest"
+	kubefake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
-	"github.com/crossplaneio/crossplane/pkg/apis/aws"
+	apisaws "github.com/crossplaneio/crossplane/pkg/apis/aws"
 	. "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	awsclient "github.com/crossplaneio/crossplane/pkg/clients/aws"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/eks"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/eks/fake"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
 	namespace    = "default"
 	providerName = "test-provider"
 	clusterName  = "test-cluster"
+	workerARN    = "test-worker-arn"
 )
 
 var (
-	key = types.NamespacedName{
-		Namespace: namespace,
-		Name:      clusterName,
-	}
-	request = reconcile.Request{
-		NamespacedName: key,
-	}
+	ctx = context.Background()
+
+	caData = []byte("test-ca")
 )
 
 func init() {
-	_ = aws.AddToScheme(scheme.Scheme)
+	_ = apisaws.AddToScheme(scheme.Scheme)
 }
 
 func testCluster() *EKSCluster {
@@ -66,6 +69,7 @@ func testCluster() *EKSCluster {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      clusterName,
 			Namespace: namespace,
+			UID:       types.UID("test-uid"),
 		},
 		Spec: EKSClusterSpec{
 			ProviderRef: corev1.LocalObjectReference{
@@ -76,13 +80,22 @@ func testCluster() *EKSCluster {
 	}
 }
 
-// assertResource a helper function to check on cluster and its status
-func assertResource(g *GomegaWithT, r *Reconciler, s corev1alpha1.ConditionedStatus) *EKSCluster {
-	rc := &EKSCluster{}
-	err := r.Get(ctx, key, rc)
-	g.Expect(err).To(BeNil())
-	g.Expect(rc.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(s))
-	return rc
+func testActiveCluster() *eks.Cluster {
+	return &eks.Cluster{
+		ARN:      "test-arn",
+		Status:   ClusterStatusActive,
+		Endpoint: "test-ep",
+		CA:       base64.StdEncoding.EncodeToString(caData),
+	}
+}
+
+// testExternal returns an external client whose remote cluster clientset is
+// the supplied fake.
+func testExternal(client eks.Client, clientset kubernetes.Interface) *external {
+	return &external{
+		client:       client,
+		newClientset: func(*rest.Config) (kubernetes.Interface, error) { return clientset, nil },
+	}
 }
 
 func TestGenerateEksAuth(t *testing.T) {
@@ -139,480 +152,271 @@ func TestGenerateEksAuth(t *testing.T) {
 	g.Expect(outputUsers).To(Equal(expectUsers))
 }
 
-func TestCreate(t *testing.T) {
+func TestConnect(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	test := func(cluster *EKSCluster, client eks.Client, expectedResult reconcile.Result, expectedStatus corev1alpha1.ConditionedStatus) *EKSCluster {
-		r := &Reconciler{
-			Client:     NewFakeClient(cluster),
-			kubeclient: NewSimpleClientset(),
-		}
-
-		rs, err := r._create(cluster, client)
-		g.Expect(rs).To(Equal(expectedResult))
-		g.Expect(err).To(BeNil())
-		return assertResource(g, r, expectedStatus)
-	}
-
-	// new cluster
-	cluster := testCluster()
-	cluster.ObjectMeta.UID = types.UID("test-uid")
-
-	client := &fake.MockEKSClient{
-		MockCreate: func(string, EKSClusterSpec) (*eks.Cluster, error) { return nil, nil },
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetCreating()
-
-	reconciledCluster := test(cluster, client, resultRequeue, expectedStatus)
-
-	g.Expect(reconciledCluster.Status.ClusterName).To(Equal(fmt.Sprintf("%s%s", clusterNamePrefix, cluster.UID)))
-	g.Expect(reconciledCluster.State()).To(Equal(ClusterStatusCreating))
-
-	// cluster create error - bad request
-	cluster = testCluster()
-	cluster.ObjectMeta.UID = types.UID("test-uid")
-	client.MockCreate = func(string, EKSClusterSpec) (*eks.Cluster, error) {
-		return nil, fmt.Errorf("InvalidParameterException")
+	p := &awsv1alpha1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerName, Namespace: namespace}}
+	c := &connecter{
+		kube:       kubefake.NewFakeClient(p),
+		kubeclient: NewSimpleClientset(),
+		newClient:  func(*aws.Config) eks.Client { return &fake.MockEKSClient{} },
 	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateCluster, "InvalidParameterException")
 
-	reconciledCluster = test(cluster, client, result, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(BeEmpty())
-	g.Expect(reconciledCluster.Status.ClusterName).To(BeEmpty())
-	g.Expect(reconciledCluster.State()).To(BeEmpty())
-	g.Expect(reconciledCluster.Status.CloudFormationStackID).To(BeEmpty())
+	// provider status is not ready
+	ext, err := c.Connect(ctx, testCluster())
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(MatchError(fmt.Sprintf("provider %s/%s is not ready", namespace, providerName)))
 
-	// cluster create error - other
-	cluster = testCluster()
-	cluster.ObjectMeta.UID = types.UID("test-uid")
-	testError := "test-create-error"
-	client.MockCreate = func(string, EKSClusterSpec) (*eks.Cluster, error) {
-		return nil, fmt.Errorf(testError)
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateCluster, testError)
-
-	reconciledCluster = test(cluster, client, resultRequeue, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(BeEmpty())
-	g.Expect(reconciledCluster.Status.ClusterName).To(BeEmpty())
-	g.Expect(reconciledCluster.State()).To(BeEmpty())
-	g.Expect(reconciledCluster.Status.CloudFormationStackID).To(BeEmpty())
+	// provider is not found
+	c.kube = kubefake.NewFakeClient()
+	ext, err = c.Connect(ctx, testCluster())
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(HaveOccurred())
 }
 
-func TestSync(t *testing.T) {
+func TestObserve(t *testing.T) {
 	g := NewGomegaWithT(t)
-	fakeStackID := "fake-stack-id"
-
-	test := func(tc *EKSCluster, cl *fake.MockEKSClient, sec func(*eks.Cluster, *EKSCluster, eks.Client) error, auth func(*eks.Cluster, *EKSCluster, eks.Client, string) error,
-		rslt reconcile.Result, exp corev1alpha1.ConditionedStatus) *EKSCluster {
-		r := &Reconciler{
-			Client:     NewFakeClient(tc),
-			kubeclient: NewSimpleClientset(),
-			secret:     sec,
-			awsauth:    auth,
-		}
-
-		rs, err := r._sync(tc, cl)
-		g.Expect(rs).To(Equal(rslt))
-		g.Expect(err).NotTo(HaveOccurred())
-		return assertResource(g, r, exp)
-	}
 
-	fakeWorkerARN := "fake-worker-arn"
-	mockClusterWorker := eks.ClusterWorkers{
-		WorkerStackID: fakeStackID,
-		WorkerARN:     fakeWorkerARN,
-	}
+	// cluster has not been created
+	e := testExternal(&fake.MockEKSClient{}, NewSimpleClientset())
+	o, err := e.Observe(ctx, testCluster())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeFalse())
 
-	// error retrieving the cluster
-	testError := "test-cluster-retriever-error"
-	cl := &fake.MockEKSClient{
+	// cluster does not exist
+	client := &fake.MockEKSClient{
 		MockGet: func(string) (*eks.Cluster, error) {
-			return nil, fmt.Errorf(testError)
+			return nil, awserr.New("ResourceNotFoundException", "not found", nil)
 		},
-		MockCreateWorkerNodes: func(string, EKSClusterSpec) (*eks.ClusterWorkers, error) { return &mockClusterWorker, nil },
-	}
-
-	cl.MockGetWorkerNodes = func(string) (*eks.ClusterWorkers, error) {
-		return &eks.ClusterWorkers{
-			WorkersStatus: cloudformation.StackStatusCreateInProgress,
-			WorkerReason:  "",
-			WorkerStackID: fakeStackID}, nil
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncCluster, testError)
-	tc := testCluster()
-	test(tc, cl, nil, nil, resultRequeue, expectedStatus)
-
-	// cluster is not ready
-	cl.MockGet = func(string) (*eks.Cluster, error) {
-		return &eks.Cluster{
-			Status: ClusterStatusCreating,
-		}, nil
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	tc = testCluster()
-	test(tc, cl, nil, nil, resultRequeue, expectedStatus)
-
-	// cluster is ready, but lets create workers that error
-	testError = "test-create-wait-on-workers"
-	cl.MockGet = func(string) (*eks.Cluster, error) {
-		return &eks.Cluster{
-			Status: ClusterStatusActive,
-		}, nil
-	}
-
-	testError = "fake-error-worker-nodes"
-	cl.MockCreateWorkerNodes = func(string, EKSClusterSpec) (*eks.ClusterWorkers, error) {
-		return nil, fmt.Errorf(testError)
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncCluster, testError)
-	tc = testCluster()
-	reconciledCluster := test(tc, cl, nil, nil, resultRequeue, expectedStatus)
-	g.Expect(reconciledCluster.Status.CloudFormationStackID).To(BeEmpty())
-
-	// cluster is ready, lets create workers
-	testError = "test-create-wait-on-workers"
-	cl.MockGet = func(string) (*eks.Cluster, error) {
-		return &eks.Cluster{
-			Status: ClusterStatusActive,
-		}, nil
-	}
-
-	cl.MockCreateWorkerNodes = func(string, EKSClusterSpec) (*eks.ClusterWorkers, error) {
-		return &eks.ClusterWorkers{WorkerStackID: fakeStackID}, nil
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	tc = testCluster()
-	reconciledCluster = test(tc, cl, nil, nil, resultRequeue, expectedStatus)
-	g.Expect(reconciledCluster.Status.CloudFormationStackID).To(Equal(fakeStackID))
-
-	// cluster is ready, but auth sync failed
-	testError = "test-create-auth-config-error"
-	cl.MockGetWorkerNodes = func(string) (*eks.ClusterWorkers, error) {
-		return &eks.ClusterWorkers{
-			WorkersStatus: cloudformation.StackStatusCreateComplete,
-			WorkerReason:  "",
-			WorkerStackID: fakeStackID,
-			WorkerARN:     fakeWorkerARN,
-		}, nil
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncCluster, fmt.Sprintf("failed to set auth map on eks: %s", testError))
-	tc = testCluster()
-	tc.Status.CloudFormationStackID = fakeStackID
-	auth := func(*eks.Cluster, *EKSCluster, eks.Client, string) error {
-		return fmt.Errorf(testError)
-
-	}
-	test(tc, cl, nil, auth, resultRequeue, expectedStatus)
-
-	// cluster is ready, but secret failed
-	testError = "test-create-secret-error"
-	cl.MockGetWorkerNodes = func(string) (*eks.ClusterWorkers, error) {
-		return &eks.ClusterWorkers{
-			WorkersStatus: cloudformation.StackStatusCreateComplete,
-			WorkerReason:  "",
-			WorkerStackID: fakeStackID,
-			WorkerARN:     fakeWorkerARN,
-		}, nil
 	}
+	e = testExternal(client, NewSimpleClientset())
+	cluster := testCluster()
+	cluster.Status.ClusterName = clusterName
+	o, err = e.Observe(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeFalse())
+
+	// cluster cannot be retrieved
+	testError := "test-get-error"
+	client.MockGet = func(string) (*eks.Cluster, error) { return nil, fmt.Errorf(testError) }
+	_, err = e.Observe(ctx, cluster)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get EKS cluster %s: %s", clusterName, testError)))
+
+	// cluster is in failed state
+	client.MockGet = func(string) (*eks.Cluster, error) { return &eks.Cluster{Status: ClusterStatusFailed}, nil }
+	_, err = e.Observe(ctx, cluster)
+	g.Expect(requeue.IsTerminal(err)).To(BeTrue())
+
+	// cluster master is being created
+	client.MockGet = func(string) (*eks.Cluster, error) { return &eks.Cluster{Status: ClusterStatusCreating}, nil }
+	o, err = e.Observe(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
+	g.Expect(cluster.State()).To(Equal(ClusterStatusCreating))
+
+	// cluster master is active, worker nodes need to be created
+	client.MockGet = func(string) (*eks.Cluster, error) { return testActiveCluster(), nil }
+	o, err = e.Observe(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ResourceUpToDate).To(BeFalse())
+	g.Expect(cluster.State()).To(Equal(ClusterStatusCreating))
 
-	auth = func(*eks.Cluster, *EKSCluster, eks.Client, string) error {
-		return nil
+	// worker nodes are being created
+	cluster.Status.CloudFormationStackID = "test-stack-id"
+	client.MockGetWorkerNodes = func(string) (*eks.ClusterWorkers, error) {
+		return &eks.ClusterWorkers{WorkersStatus: cloudformation.StackStatusCreateInProgress}, nil
 	}
+	o, err = e.Observe(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
 
-	fSec := func(*eks.Cluster, *EKSCluster, eks.Client) error {
-		return fmt.Errorf(testError)
+	// worker nodes are created, aws-auth configmap has not been pushed
+	client.MockGetWorkerNodes = func(string) (*eks.ClusterWorkers, error) {
+		return &eks.ClusterWorkers{WorkersStatus: cloudformation.StackStatusCreateComplete, WorkerARN: workerARN}, nil
 	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncCluster, testError)
-	tc = testCluster()
-	tc.Status.CloudFormationStackID = fakeStackID
-	test(tc, cl, fSec, auth, resultRequeue, expectedStatus)
+	client.MockConnectionToken = func(string) (string, error) { return "test-token", nil }
+	o, err = e.Observe(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ResourceUpToDate).To(BeFalse())
 
 	// cluster is ready
-	fSec = func(*eks.Cluster, *EKSCluster, eks.Client) error {
-		return nil
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	tc = testCluster()
-	tc.Status.CloudFormationStackID = fakeStackID
-	test(tc, cl, fSec, auth, result, expectedStatus)
+	cm, _ := generateAWSAuthConfigMap(cluster, workerARN)
+	e = testExternal(client, NewSimpleClientset(cm))
+	o, err = e.Observe(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceReady).To(BeTrue())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
+	g.Expect(o.ConnectionDetails).To(Equal(core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("test-ep"),
+		corev1alpha1.ResourceCredentialsSecretCAKey:       caData,
+		corev1alpha1.ResourceCredentialsTokenKey:          []byte("test-token"),
+	}))
+	g.Expect(cluster.State()).To(Equal(ClusterStatusActive))
+	g.Expect(cluster.Status.Endpoint).To(Equal("test-ep"))
+	g.Expect(cluster.Status.ConnectionSecretRef.Name).To(Equal(clusterName))
+	g.Expect(cluster.StatusSummary().ProviderID).To(Equal("test-arn"))
+
+	// connection token cannot be retrieved
+	testError = "test-token-error"
+	client.MockConnectionToken = func(string) (string, error) { return "", fmt.Errorf(testError) }
+	_, err = e.Observe(ctx, cluster)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get connection token of EKS cluster %s: %s", clusterName, testError)))
 }
 
-func TestSecret(t *testing.T) {
+func TestCreate(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tc := testCluster()
-
-	// connection token error
-	testError := "test-connection-token-error"
+	// new cluster
 	client := &fake.MockEKSClient{
-		MockConnectionToken: func(string) (string, error) {
-			return "", fmt.Errorf(testError)
-		},
-	}
-	clusterCA := []byte("test-ca")
-	cluster := &eks.Cluster{
-		Status:   ClusterStatusActive,
-		Endpoint: "test-ep",
-		CA:       base64.StdEncoding.EncodeToString(clusterCA),
-	}
-
-	kc := NewSimpleClientset()
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: kc,
+		MockCreate: func(string, EKSClusterSpec) (*eks.Cluster, error) { return nil, nil },
 	}
-
-	err := r._secret(cluster, tc, client)
-	g.Expect(err).To(And(HaveOccurred(), MatchError(testError)))
-
-	// test success
-	client.MockConnectionToken = func(string) (string, error) { return "test-token", nil }
-	err = r._secret(cluster, tc, client)
-
-	g.Expect(err).NotTo(HaveOccurred())
-	// validate secret
-	secret, err := kc.CoreV1().Secrets(tc.Namespace).Get(tc.Name, metav1.GetOptions{})
+	e := testExternal(client, NewSimpleClientset())
+	cluster := testCluster()
+	_, err := e.Create(ctx, cluster)
 	g.Expect(err).NotTo(HaveOccurred())
-	data := make(map[string][]byte)
-	data[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(cluster.Endpoint)
-	data[corev1alpha1.ResourceCredentialsSecretCAKey] = clusterCA
-	data[corev1alpha1.ResourceCredentialsTokenKey] = []byte("test-token")
-	secret.Data = data
-	expSec := tc.ConnectionSecret()
-	expSec.Data = data
-	g.Expect(secret).To(Equal(expSec))
-
-	// test update secret error
-	testError = "test-update-secret-error"
-	kc.PrependReactor("get", "secrets", func(Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, fmt.Errorf(testError)
-	})
-
-	err = r._secret(cluster, tc, client)
-	g.Expect(err).To(And(HaveOccurred(), MatchError(testError)))
-}
-
-func TestDelete(t *testing.T) {
-	g := NewGomegaWithT(t)
+	g.Expect(cluster.Status.ClusterName).To(Equal(fmt.Sprintf("%s%s", clusterNamePrefix, cluster.UID)))
+	g.Expect(cluster.State()).To(Equal(ClusterStatusCreating))
 
-	test := func(cluster *EKSCluster, client eks.Client, expectedResult reconcile.Result, expectedStatus corev1alpha1.ConditionedStatus) *EKSCluster {
-		r := &Reconciler{
-			Client:     NewFakeClient(cluster),
-			kubeclient: NewSimpleClientset(),
-		}
-
-		rs, err := r._delete(cluster, client)
-		g.Expect(rs).To(Equal(expectedResult))
-		g.Expect(err).To(BeNil())
-		return assertResource(g, r, expectedStatus)
+	// cluster was created by an earlier reconcile
+	client.MockCreate = func(string, EKSClusterSpec) (*eks.Cluster, error) {
+		return nil, awserr.New("ResourceInUseException", "test-in-use", nil)
 	}
+	cluster = testCluster()
+	_, err = e.Create(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Status.ClusterName).NotTo(BeEmpty())
 
-	// reclaim - delete
-	cluster := testCluster()
-	cluster.Finalizers = []string{finalizer}
-	cluster.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	cluster.Status.CloudFormationStackID = "fake-stack-id"
-	cluster.Status.SetReady()
-
-	client := &fake.MockEKSClient{}
-	client.MockDelete = func(string) error { return nil }
-	client.MockDeleteWorkerNodes = func(string) error { return nil }
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.SetDeleting()
-
-	reconciledCluster := test(cluster, client, result, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(BeEmpty())
-
-	// reclaim - retain
-	cluster.Spec.ReclaimPolicy = corev1alpha1.ReclaimRetain
-	cluster.Status.RemoveAllConditions()
-	cluster.Status.SetReady()
-	cluster.Finalizers = []string{finalizer}
-	client.MockDelete = nil // should not be called
-
-	reconciledCluster = test(cluster, client, result, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(BeEmpty())
-
-	// reclaim - delete, delete error
-	cluster.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	cluster.Status.RemoveAllConditions()
-	cluster.Status.SetReady()
-	cluster.Finalizers = []string{finalizer}
-	testError := "test-delete-error"
-	client.MockDelete = func(string) error { return fmt.Errorf(testError) }
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.SetFailed(errorDeleteCluster, fmt.Sprintf("Master Delete Error: %s", testError))
-
-	reconciledCluster = test(cluster, client, resultRequeue, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(ContainElement(finalizer))
-
-	// reclaim - delete, delete error with worker
-	cluster.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	cluster.Status.RemoveAllConditions()
-	cluster.Status.SetReady()
-	cluster.Finalizers = []string{finalizer}
-	testErrorWorker := "test-delete-error-worker"
-	client.MockDelete = func(string) error { return nil }
-	client.MockDeleteWorkerNodes = func(string) error { return fmt.Errorf(testErrorWorker) }
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.SetFailed(errorDeleteCluster, fmt.Sprintf("Worker Delete Error: %s", testErrorWorker))
-
-	reconciledCluster = test(cluster, client, resultRequeue, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(ContainElement(finalizer))
-
-	// reclaim - delete, delete error in cluster and cluster workers
-	cluster.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	cluster.Status.RemoveAllConditions()
-	cluster.Status.SetReady()
-	cluster.Finalizers = []string{finalizer}
-	client.MockDelete = func(string) error { return nil }
-	client.MockDelete = func(string) error { return fmt.Errorf(testError) }
-	client.MockDeleteWorkerNodes = func(string) error { return fmt.Errorf(testErrorWorker) }
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.SetFailed(errorDeleteCluster, fmt.Sprintf("Master Delete Error: %s, Worker Delete Error: %s", testError, testErrorWorker))
-
-	reconciledCluster = test(cluster, client, resultRequeue, expectedStatus)
-	g.Expect(reconciledCluster.Finalizers).To(ContainElement(finalizer))
-}
-
-func TestReconcileObjectNotFound(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	r := &Reconciler{
-		Client: NewFakeClient(),
-	}
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
+	// cluster create error
+	badRequest := awserr.New("InvalidParameterException", "test-bad-request", nil)
+	client.MockCreate = func(string, EKSClusterSpec) (*eks.Cluster, error) { return nil, badRequest }
+	cluster = testCluster()
+	_, err = e.Create(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(awsclient.IsErrorBadRequest(err)).To(BeTrue())
+	g.Expect(cluster.Status.ClusterName).To(BeEmpty())
+	g.Expect(cluster.State()).To(BeEmpty())
+
+	// cluster create other error
+	client.MockCreate = func(string, EKSClusterSpec) (*eks.Cluster, error) { return nil, fmt.Errorf("test-create-error") }
+	cluster = testCluster()
+	_, err = e.Create(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(awsclient.IsErrorBadRequest(err)).To(BeFalse())
+	g.Expect(cluster.Status.ClusterName).To(BeEmpty())
 }
 
-func TestReconcileClientError(t *testing.T) {
+func TestUpdate(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	testError := "test-client-error"
-
-	called := false
-
-	r := &Reconciler{
-		Client:     NewFakeClient(testCluster()),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*EKSCluster) (eks.Client, error) {
-			called = true
-			return nil, fmt.Errorf(testError)
+	// worker nodes are created
+	client := &fake.MockEKSClient{
+		MockCreateWorkerNodes: func(string, EKSClusterSpec) (*eks.ClusterWorkers, error) {
+			return &eks.ClusterWorkers{WorkerStackID: "test-stack-id"}, nil
 		},
 	}
+	clientset := NewSimpleClientset()
+	e := testExternal(client, clientset)
+	cluster := testCluster()
+	cluster.Status.ClusterName = clusterName
+	_, err := e.Update(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Status.CloudFormationStackID).To(Equal("test-stack-id"))
 
-	// expected to have a failed condition
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorClusterClient, testError)
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-
-	assertResource(g, r, expectedStatus)
-}
-
-func TestReconcileDelete(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	// test objects
-	tc := testCluster()
-	dt := metav1.Now()
-	tc.DeletionTimestamp = &dt
-
-	called := false
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*EKSCluster) (eks.Client, error) {
-			return nil, nil
-		},
-		delete: func(*EKSCluster, eks.Client) (reconcile.Result, error) {
-			called = true
-			return result, nil
-		},
+	// aws-auth configmap is pushed
+	client.MockGet = func(string) (*eks.Cluster, error) { return testActiveCluster(), nil }
+	client.MockGetWorkerNodes = func(string) (*eks.ClusterWorkers, error) {
+		return &eks.ClusterWorkers{WorkersStatus: cloudformation.StackStatusCreateComplete, WorkerARN: workerARN}, nil
 	}
+	client.MockConnectionToken = func(string) (string, error) { return "test-token", nil }
+	_, err = e.Update(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	cm, err := clientset.CoreV1().ConfigMaps("kube-system").Get(eksAuthConfigMapName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data).To(HaveKey(eksAuthMapRolesKey))
 
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, corev1alpha1.ConditionedStatus{})
-}
-
-func TestReconcileCreate(t *testing.T) {
-	g := NewGomegaWithT(t)
+	// aws-auth configmap is updated
+	cluster.Spec.MapUsers = []MapUser{{UserARN: "test-user-arn", Username: "test-user"}}
+	_, err = e.Update(ctx, cluster)
+	g.Expect(err).NotTo(HaveOccurred())
+	cm, err = clientset.CoreV1().ConfigMaps("kube-system").Get(eksAuthConfigMapName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cm.Data).To(HaveKey(eksAuthMapUsersKey))
 
-	called := false
+	// aws-auth configmap cannot be pushed
+	client.MockConnectionToken = func(string) (string, error) { return "", fmt.Errorf("test-token-error") }
+	_, err = e.Update(ctx, cluster)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(HavePrefix(fmt.Sprintf("cannot set auth map on EKS cluster %s: ", clusterName)))
 
-	r := &Reconciler{
-		Client:     NewFakeClient(testCluster()),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*EKSCluster) (eks.Client, error) {
-			return nil, nil
-		},
-		create: func(*EKSCluster, eks.Client) (reconcile.Result, error) {
-			called = true
-			return resultRequeue, nil
-		},
+	// worker nodes cannot be created
+	testError := "test-create-workers-error"
+	client.MockCreateWorkerNodes = func(string, EKSClusterSpec) (*eks.ClusterWorkers, error) {
+		return nil, fmt.Errorf(testError)
 	}
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-
-	assertResource(g, r, corev1alpha1.ConditionedStatus{})
+	cluster = testCluster()
+	cluster.Status.ClusterName = clusterName
+	_, err = e.Update(ctx, cluster)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot create worker nodes of EKS cluster %s: %s", clusterName, testError)))
+	g.Expect(cluster.Status.CloudFormationStackID).To(BeEmpty())
 }
 
-func TestReconcileSync(t *testing.T) {
+func TestDelete(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	called := false
-
-	tc := testCluster()
-	tc.Status.ClusterName = "test-status- cluster-name"
-	tc.Finalizers = []string{finalizer}
+	cluster := testCluster()
+	cluster.Status.ClusterName = clusterName
+	cluster.Status.CloudFormationStackID = "test-stack-id"
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*EKSCluster) (eks.Client, error) {
-			return nil, nil
+	// cluster and worker nodes are deleted
+	deleteCalled := false
+	deleteWorkersCalled := false
+	client := &fake.MockEKSClient{
+		MockDelete: func(string) error {
+			deleteCalled = true
+			return nil
 		},
-		sync: func(*EKSCluster, eks.Client) (reconcile.Result, error) {
-			called = true
-			return resultRequeue, nil
+		MockDeleteWorkerNodes: func(string) error {
+			deleteWorkersCalled = true
+			return nil
 		},
 	}
+	e := testExternal(client, NewSimpleClientset())
+	g.Expect(e.Delete(ctx, cluster)).To(Succeed())
+	g.Expect(deleteCalled).To(BeTrue())
+	g.Expect(deleteWorkersCalled).To(BeTrue())
+
+	// cluster is already being deleted
+	deleteCalled = false
+	cluster.Status.State = ClusterStatusDeleting
+	g.Expect(e.Delete(ctx, cluster)).To(Succeed())
+	g.Expect(deleteCalled).To(BeFalse())
+
+	// cluster and worker nodes are already gone
+	cluster.Status.State = ClusterStatusActive
+	notFound := awserr.New("ResourceNotFoundException", "not found", nil)
+	client.MockDelete = func(string) error { return notFound }
+	client.MockDeleteWorkerNodes = func(string) error { return notFound }
+	g.Expect(e.Delete(ctx, cluster)).To(Succeed())
+
+	// cluster and worker nodes cannot be deleted
+	client.MockDelete = func(string) error { return fmt.Errorf("test-delete-error") }
+	client.MockDeleteWorkerNodes = func(string) error { return fmt.Errorf("test-delete-workers-error") }
+	g.Expect(e.Delete(ctx, cluster)).To(MatchError(fmt.Sprintf(
+		"cannot delete EKS cluster %s: Master Delete Error: test-delete-error, Worker Delete Error: test-delete-workers-error", clusterName)))
+
+	// only the cluster cannot be deleted
+	client.MockDeleteWorkerNodes = func(string) error { return nil }
+	g.Expect(e.Delete(ctx, cluster)).To(MatchError(fmt.Sprintf(
+		"cannot delete EKS cluster %s: Master Delete Error: test-delete-error", clusterName)))
 
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-
-	rc := assertResource(g, r, corev1alpha1.ConditionedStatus{})
-	g.Expect(rc.Finalizers).To(HaveLen(1))
-	g.Expect(rc.Finalizers).To(ContainElement(finalizer))
+	// only the worker nodes cannot be deleted
+	client.MockDelete = func(string) error { return nil }
+	client.MockDeleteWorkerNodes = func(string) error { return fmt.Errorf("test-delete-workers-error") }
+	g.Expect(e.Delete(ctx, cluster)).To(MatchError(fmt.Sprintf(
+		"cannot delete EKS cluster %s: Worker Delete Error: test-delete-workers-error", clusterName)))
 }
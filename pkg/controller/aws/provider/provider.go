@@ -18,10 +18,10 @@ package provider
 
 import (
 	"context"
-	"log"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/go-ini/ini"
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -35,6 +35,9 @@ import (
 
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	awsclient "github.com/crossplaneio/crossplane/pkg/clients/aws"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
@@ -45,9 +48,7 @@ const (
 )
 
 var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	result = reconcile.Result{}
 )
 
 // Add creates a new Provider Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -62,6 +63,8 @@ type Reconciler struct {
 	scheme     *runtime.Scheme
 	kubeclient kubernetes.Interface
 	recorder   record.EventRecorder
+	requeue    *requeue.Policy
+	log        logr.Logger
 
 	validate func(*aws.Config) error
 }
@@ -73,6 +76,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		scheme:     mgr.GetScheme(),
 		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
 		recorder:   mgr.GetRecorder(controllerName),
+		requeue:    requeue.NewPolicy(controllerName, requeue.Defaults),
+		log:        logging.Log.WithName(controllerName),
 	}
 	r.validate = r._validate
 	return r
@@ -81,7 +86,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -99,7 +104,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 func (r *Reconciler) fail(instance *awsv1alpha1.Provider, reason, msg string) (reconcile.Result, error) {
 	instance.Status.UnsetAllConditions()
 	instance.Status.SetFailed(reason, msg)
-	return resultRequeue, r.Update(context.TODO(), instance)
+	return r.requeue.Failed(instance, nil), r.Update(context.TODO(), instance)
 }
 
 func (r *Reconciler) _validate(config *aws.Config) error {
@@ -112,7 +117,7 @@ func (r *Reconciler) _validate(config *aws.Config) error {
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=aws.crossplane.io,resources=provider,verbs=get;list;watch;create;update;patch;delete
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", awsv1alpha1.ProviderKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), r.log, request)
 	// Fetch the Provider instance
 	instance := &awsv1alpha1.Provider{}
 	err := r.Get(ctx, request.NamespacedName, instance)
@@ -120,12 +125,20 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.requeue.Forget(request.NamespacedName)
 			return result, nil
 		}
 		// Error reading the object - requeue the request.
 		return result, err
 	}
 
+	logging.Reconciling(ctx, instance)
+
+	// Wait for a failed provider to back off before reconciling it again
+	if d := r.requeue.Wait(instance); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
 	// Fetch Provider Secret
 	data, err := util.SecretData(r.kubeclient, instance.Namespace, instance.Spec.Secret)
 	if err != nil {
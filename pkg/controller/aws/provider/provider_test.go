@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -36,6 +37,7 @@ import (
 
 	apisaws "github.com/crossplaneio/crossplane/pkg/apis/aws"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -47,6 +49,7 @@ const (
 )
 
 var (
+	ctx = context.Background()
 	key = types.NamespacedName{
 		Namespace: namespace,
 		Name:      providerName,
@@ -54,6 +57,8 @@ var (
 	request = reconcile.Request{
 		NamespacedName: key,
 	}
+
+	resultFailed = reconcile.Result{RequeueAfter: requeue.Defaults.BackoffBase}
 )
 
 func init() {
@@ -115,7 +120,7 @@ func TestReconcileSecretNotFound(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	rp := &Provider{}
@@ -141,7 +146,7 @@ func TestReconcileSecretKeyNotFound(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	rp := &Provider{}
@@ -192,7 +197,7 @@ func TestReconcileInvalidCredentialsFormat(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	// assert provider status
@@ -218,7 +223,7 @@ func TestReconcileValidationFailed(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	// assert provider status
@@ -19,280 +19,266 @@ package rds
 import (
 	"context"
 	"fmt"
-	"log"
 
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	databasev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
-	"github.com/crossplaneio/crossplane/pkg/clients/aws"
+	awsclient "github.com/crossplaneio/crossplane/pkg/clients/aws"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/rds"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 const (
 	controllerName = "rds.aws.crossplane.io"
-	finalizer      = "finalizer." + controllerName
 
-	errorResourceClient = "Failed to create rds client"
-	errorCreateResource = "Failed to crate resource"
-	errorSyncResource   = "Failed to sync resource state"
-	errorDeleteResource = "Failed to delete resource"
-)
+	passwordDataLen = 20
 
-var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	errNotRDSInstance = "managed resource is not an RDS instance"
 )
 
-// Add creates a new Instance Controller and adds it to the Manager with default RBAC.
-// The Manager will set fields on the Controller and Start it when the Manager is Started.
+// Add creates a new RDSInstance controller and adds it to the Manager. The
+// Manager will set fields on the controller and start it when the Manager is
+// started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	c := &connecter{
+		kube:       mgr.GetClient(),
+		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+		newClient:  rds.NewClient,
+	}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &databasev1alpha1.RDSInstance{} }, c,
+		awsclient.ClassifyError, corev1alpha1.ReclaimDelete, corev1alpha1.ReclaimSnapshot)
 }
 
-// Reconciler reconciles a Instance object
-type Reconciler struct {
-	client.Client
-	scheme     *runtime.Scheme
+// connecter returns RDS clients authenticated using the credentials of the
+// AWS Provider referenced by an RDSInstance.
+type connecter struct {
+	kube       client.Client
 	kubeclient kubernetes.Interface
-	recorder   record.EventRecorder
-
-	connect func(*databasev1alpha1.RDSInstance) (rds.Client, error)
-	create  func(*databasev1alpha1.RDSInstance, rds.Client) (reconcile.Result, error)
-	sync    func(*databasev1alpha1.RDSInstance, rds.Client) (reconcile.Result, error)
-	delete  func(*databasev1alpha1.RDSInstance, rds.Client) (reconcile.Result, error)
+	newClient  func(*aws.Config) rds.Client
 }
 
-// newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	r := &Reconciler{
-		Client:     mgr.GetClient(),
-		scheme:     mgr.GetScheme(),
-		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
-		recorder:   mgr.GetRecorder(controllerName),
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(*databasev1alpha1.RDSInstance)
+	if !ok {
+		return nil, errors.New(errNotRDSInstance)
 	}
-	r.connect = r._connect
-	r.create = r._create
-	r.sync = r._sync
-	r.delete = r._delete
-	return r
-}
 
-// add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New("instance-controller", mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
+	p := &awsv1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
 	}
 
-	// Watch for changes to Instance
-	err = c.Watch(&source.Kind{Type: &databasev1alpha1.RDSInstance{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
 	}
 
-	// Watch for changes to Instance Secret
-	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
-		IsController: true,
-		OwnerType:    &databasev1alpha1.RDSInstance{},
-	})
+	config, err := awsclient.Config(c.kubeclient, p)
 	if err != nil {
-		return err
+		return nil, errors.Wrapf(err, "cannot get AWS config of provider %s", n)
 	}
 
-	return nil
+	return &external{kube: c.kube, client: c.newClient(config)}, nil
 }
 
-// fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *databasev1alpha1.RDSInstance, reason, msg string) (reconcile.Result, error) {
-	instance.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
-	return reconcile.Result{Requeue: true}, r.Update(context.TODO(), instance)
+// external manages RDS instances using the RDS API.
+type external struct {
+	kube   client.Client
+	client rds.Client
 }
 
-// connectionSecret return secret object for this resource
-func connectionSecret(instance *databasev1alpha1.RDSInstance, password string) *corev1.Secret {
-	if instance.APIVersion == "" {
-		instance.APIVersion = "database.aws.crossplane.io/v1alpha1"
-	}
-	if instance.Kind == "" {
-		instance.Kind = "RDSInstance"
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(*databasev1alpha1.RDSInstance)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotRDSInstance)
 	}
 
-	return &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            instance.ConnectionSecretName(),
-			Namespace:       instance.Namespace,
-			OwnerReferences: []metav1.OwnerReference{instance.OwnerReference()},
-		},
+	// Adopt the existing instance named by the managed resource, if any.
+	if i.Status.InstanceName == "" {
+		i.Status.InstanceName = corev1alpha1.ExternalName(i)
+	}
 
-		Data: map[string][]byte{
-			corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(instance.Spec.MasterUsername),
-			corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
-		},
+	// The instance is unnamed. Assume it has not been created in AWS.
+	if i.Status.InstanceName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
-}
 
-func (r *Reconciler) _connect(instance *databasev1alpha1.RDSInstance) (rds.Client, error) {
-	// Fetch AWS Provider
-	p := &awsv1alpha1.Provider{}
-	providerNamespacedName := types.NamespacedName{
-		Namespace: instance.Namespace,
-		Name:      instance.Spec.ProviderRef.Name,
+	db, err := e.client.GetInstance(ctx, i.Status.InstanceName)
+	if awsclient.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
-	err := r.Get(ctx, providerNamespacedName, p)
 	if err != nil {
-		return nil, err
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get RDS instance %s", i.Status.InstanceName)
 	}
 
-	// Check provider status
-	if !p.IsValid() {
-		return nil, fmt.Errorf("provider is not ready")
+	i.Status.State = db.Status
+	if db.Status == string(databasev1alpha1.RDSInstanceStateFailed) {
+		return core.ExternalObservation{}, requeue.Terminal(errors.Errorf("RDS instance %s is in failed state", i.Status.InstanceName))
 	}
 
-	// Get Provider's AWS Config
-	config, err := aws.Config(r.kubeclient, p)
-	if err != nil {
-		return nil, err
+	i.Status.PendingModifications = pendingModifications(db)
+
+	// Instances can only be modified while they are available.
+	ready := db.Status == string(databasev1alpha1.RDSInstanceStateAvailable)
+	o := core.ExternalObservation{
+		ResourceExists:   true,
+		ResourceReady:    ready,
+		ResourceUpToDate: !ready || !rds.NeedsUpdate(&i.Spec, db),
 	}
 
-	// Create new RDS RDSClient
-	return rds.NewClient(config), nil
-}
+	// The master password of an adopted instance is unknown until Update
+	// resets it.
+	if ready {
+		published, err := core.ConnectionDetailPublished(ctx, e.kube, i, corev1alpha1.ResourceCredentialsSecretPasswordKey)
+		if err != nil {
+			return core.ExternalObservation{}, err
+		}
+		o.ResourceUpToDate = o.ResourceUpToDate && published
 
-func (r *Reconciler) _create(instance *databasev1alpha1.RDSInstance, client rds.Client) (reconcile.Result, error) {
-	resourceName := fmt.Sprintf("%s-%s", instance.Spec.Engine, instance.UID)
+		switch {
+		case !published:
+			o.Diff = fmt.Sprintf("master password of RDS instance %s would be reset", i.Status.InstanceName)
+		case !o.ResourceUpToDate:
+			o.Diff = rds.ModifyDBInstanceInput(&i.Spec, db).String()
+		}
+	}
+	if db.Endpoint != "" {
+		i.Status.Endpoint = db.Endpoint
+		i.Status.ProviderID = db.ARN
+		o.ConnectionDetails = core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(db.Endpoint)}
+	}
+	return o, nil
+}
 
-	// generate new password
-	password, err := util.GeneratePassword(20)
-	if err != nil {
-		return r.fail(instance, errorCreateResource, err.Error())
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(*databasev1alpha1.RDSInstance)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotRDSInstance)
 	}
 
-	_, err = util.ApplySecret(r.kubeclient, connectionSecret(instance, password))
+	name := fmt.Sprintf("%s-%s", i.Spec.Engine, i.UID)
+	password, err := util.GeneratePassword(passwordDataLen)
 	if err != nil {
-		return r.fail(instance, errorCreateResource, err.Error())
+		return core.ExternalCreation{}, errors.Wrap(err, "cannot generate password")
 	}
 
-	// Create DB Instance
-	_, err = client.CreateInstance(resourceName, password, &instance.Spec)
-	if err != nil && !rds.IsErrorAlreadyExists(err) {
-		return r.fail(instance, errorCreateResource, err.Error())
+	if _, err := e.client.CreateInstance(ctx, name, password, &i.Spec); err != nil {
+		if !awsclient.IsErrorAlreadyExists(err) {
+			return core.ExternalCreation{}, errors.Wrapf(err, "cannot create RDS instance %s", name)
+		}
+		// The instance was created by an earlier reconcile, whose password
+		// must not be overwritten.
+		i.Status.InstanceName = name
+		return core.ExternalCreation{}, nil
 	}
 
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetCreating()
-	instance.Status.InstanceName = resourceName
-
-	util.AddFinalizer(&instance.ObjectMeta, finalizer)
-
-	return resultRequeue, r.Update(ctx, instance)
+	i.Status.InstanceName = name
+	return core.ExternalCreation{ConnectionDetails: core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(i.Spec.MasterUsername),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
+	}}, nil
 }
 
-func (r *Reconciler) _sync(instance *databasev1alpha1.RDSInstance, client rds.Client) (reconcile.Result, error) {
-	// Search for the RDS instance in AWS
-	db, err := client.GetInstance(instance.Status.InstanceName)
-	if err != nil {
-		return r.fail(instance, errorSyncResource, err.Error())
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	i, ok := mg.(*databasev1alpha1.RDSInstance)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotRDSInstance)
 	}
 
-	instance.Status.State = db.Status
-
-	instance.Status.UnsetAllConditions()
-	switch db.Status {
-	case string(databasev1alpha1.RDSInstanceStateCreating):
-		instance.Status.SetCreating()
-		return resultRequeue, r.Update(ctx, instance)
-	case string(databasev1alpha1.RDSInstanceStateFailed):
-		instance.Status.SetFailed(errorSyncResource, "resource is in failed state")
-		return result, r.Update(ctx, instance)
-	case string(databasev1alpha1.RDSInstanceStateAvailable):
-		instance.Status.SetReady()
-	default:
-		return r.fail(instance, errorSyncResource, fmt.Sprintf("unexpected resource status: %s", db.Status))
+	published, err := core.ConnectionDetailPublished(ctx, e.kube, i, corev1alpha1.ResourceCredentialsSecretPasswordKey)
+	if err != nil {
+		return core.ExternalUpdate{}, err
+	}
+	if !published {
+		return e.resetPassword(ctx, i)
 	}
 
-	// Retrieve connection secret that was created during resource create phase
-	connSecret, err := r.kubeclient.CoreV1().Secrets(instance.Namespace).Get(instance.ConnectionSecretName(), metav1.GetOptions{})
+	db, err := e.client.GetInstance(ctx, i.Status.InstanceName)
 	if err != nil {
-		return r.fail(instance, errorSyncResource, err.Error())
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot get RDS instance %s", i.Status.InstanceName)
 	}
 
-	// Save resource endpoint
-	instance.Status.Endpoint = db.Endpoint
-	instance.Status.ProviderID = db.ARN
+	// RDS cannot shrink storage, so retrying is pointless until the size in
+	// the spec changes.
+	if rds.IsStorageShrink(&i.Spec, db) {
+		return core.ExternalUpdate{}, requeue.Terminal(errors.Errorf("cannot shrink storage of RDS instance %s to %d GB", i.Status.InstanceName, i.Spec.Size))
+	}
 
-	// Update resource secret
-	connSecret.Data[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(db.Endpoint)
-	_, err = util.ApplySecret(r.kubeclient, connSecret)
+	db, err = e.client.ModifyInstance(ctx, &i.Spec, db)
 	if err != nil {
-		return r.fail(instance, errorSyncResource, err.Error())
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot modify RDS instance %s", i.Status.InstanceName)
 	}
 
-	return result, r.Update(ctx, instance)
+	i.Status.State = db.Status
+	i.Status.PendingModifications = pendingModifications(db)
+	return core.ExternalUpdate{}, nil
 }
 
-func (r *Reconciler) _delete(instance *databasev1alpha1.RDSInstance, client rds.Client) (reconcile.Result, error) {
-	if instance.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		if _, err := client.DeleteInstance(instance.Status.InstanceName); err != nil && !rds.IsErrorNotFound(err) {
-			return r.fail(instance, errorDeleteResource, err.Error())
-		}
+// resetPassword generates a new master password for the supplied instance,
+// e.g. because the instance was adopted and its password is unknown.
+func (e *external) resetPassword(ctx context.Context, i *databasev1alpha1.RDSInstance) (core.ExternalUpdate, error) {
+	password, err := util.GeneratePassword(passwordDataLen)
+	if err != nil {
+		return core.ExternalUpdate{}, errors.Wrap(err, "cannot generate password")
+	}
+
+	db, err := e.client.ResetPassword(ctx, i.Status.InstanceName, password)
+	if err != nil {
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot reset master password of RDS instance %s", i.Status.InstanceName)
 	}
 
-	instance.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Deleting, "", ""))
-	util.RemoveFinalizer(&instance.ObjectMeta, finalizer)
-	return result, r.Update(ctx, instance)
+	i.Status.State = db.Status
+	return core.ExternalUpdate{ConnectionDetails: core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(db.MasterUsername),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
+	}}, nil
 }
 
-// Reconcile reads that state of the cluster for a Instance object and makes changes based on the state read
-// and what is in the Instance.Spec
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", databasev1alpha1.RDSInstanceKindAPIVersion, request)
-	// Fetch the CRD instance
-	instance := &databasev1alpha1.RDSInstance{}
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(*databasev1alpha1.RDSInstance)
+	if !ok {
+		return errors.New(errNotRDSInstance)
+	}
 
-	err := r.Get(ctx, request.NamespacedName, instance)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		log.Printf("failed to get object at start of reconcile loop: %+v", err)
-		return reconcile.Result{}, err
+	if i.Status.State == string(databasev1alpha1.RDSInstanceStateDeleting) {
+		return nil
 	}
 
-	rdsClient, err := r.connect(instance)
+	snapshot := ""
+	if i.Spec.ReclaimPolicy == corev1alpha1.ReclaimSnapshot {
+		snapshot = corev1alpha1.FinalSnapshotName(i.Status.InstanceName)
+	}
+	_, err := e.client.DeleteInstance(ctx, i.Status.InstanceName, snapshot)
+	if awsclient.IsErrorNotFound(err) {
+		// No final snapshot was taken of an instance that was already gone.
+		return nil
+	}
 	if err != nil {
-		return r.fail(instance, errorResourceClient, err.Error())
+		return errors.Wrapf(err, "cannot delete RDS instance %s", i.Status.InstanceName)
 	}
 
-	// Check for deletion
-	if instance.DeletionTimestamp != nil {
-		return r.delete(instance, rdsClient)
-	}
+	i.Status.FinalSnapshot = snapshot
+	return nil
+}
 
-	// Create cluster instance
-	if instance.Status.InstanceName == "" {
-		return r.create(instance, rdsClient)
+// pendingModifications returns the modifications of the supplied instance that
+// will be applied during its next maintenance window, if any.
+func pendingModifications(db *rds.Instance) *databasev1alpha1.RDSInstanceModifications {
+	if db.Pending == nil {
+		return nil
+	}
+	return &databasev1alpha1.RDSInstanceModifications{
+		Class:         db.Pending.Class,
+		Size:          db.Pending.Size,
+		EngineVersion: db.Pending.EngineVersion,
 	}
-
-	// Sync cluster instance status with cluster status
-	return r.sync(instance, rdsClient)
 }
@@ -17,25 +17,27 @@ limitations under the License.
 package rds
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	. "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
-	. "k8s.io/client-go/testing"
 	. "sigs.k8s.io/controller-runtime/pkg/client/fake"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/crossplaneio/crossplane/pkg/apis/aws"
+	apisaws "github.com/crossplaneio/crossplane/pkg/apis/aws"
 	. "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/rds"
 	. "github.com/crossplaneio/crossplane/pkg/clients/aws/rds/fake"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -49,18 +51,10 @@ const (
 	size           = int64(10)
 )
 
-var (
-	key = types.NamespacedName{
-		Namespace: namespace,
-		Name:      instanceName,
-	}
-	request = reconcile.Request{
-		NamespacedName: key,
-	}
-)
+var ctx = context.Background()
 
 func init() {
-	if err := aws.AddToScheme(scheme.Scheme); err != nil {
+	if err := apisaws.AddToScheme(scheme.Scheme); err != nil {
 		panic(err)
 	}
 }
@@ -79,6 +73,7 @@ func testResource() *RDSInstance {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      instanceName,
 			Namespace: namespace,
+			UID:       "test-uid",
 		},
 		Spec: RDSInstanceSpec{
 			MasterUsername: masterUserName,
@@ -89,390 +84,357 @@ func testResource() *RDSInstance {
 	}
 }
 
-// assertResource a helper function to check on cluster and its status
-func assertResource(g *GomegaWithT, r *Reconciler, s corev1alpha1.ConditionedStatus) *RDSInstance {
-	resource := &RDSInstance{}
-	err := r.Get(ctx, key, resource)
-	g.Expect(err).To(BeNil())
-	g.Expect(resource.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(s))
-	return resource
-}
-
-func TestSyncClusterError(t *testing.T) {
+func TestConnect(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	assert := func(instance *RDSInstance, client rds.Client, expectedResult reconcile.Result, expectedStatus corev1alpha1.ConditionedStatus) {
-		r := &Reconciler{
-			Client:     NewFakeClient(instance),
-			kubeclient: NewSimpleClientset(),
-		}
-
-		rs, err := r._sync(instance, client)
-
-		g.Expect(err).NotTo(HaveOccurred())
-		g.Expect(rs).To(Equal(expectedResult))
-		assertResource(g, r, expectedStatus)
-	}
-
-	// get error
-	testError := "test-resource-retrieve-error"
-	cl := &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			return nil, fmt.Errorf(testError)
-		},
-	}
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, testError)
-	assert(testResource(), cl, resultRequeue, expectedStatus)
-
-	// instance is not ready
-	cl = &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			return &rds.Instance{
-				Status: string(RDSInstanceStateCreating),
-			}, nil
-		},
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetCreating()
-	assert(testResource(), cl, resultRequeue, expectedStatus)
-
-	// instance in failed state
-	cl = &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			return &rds.Instance{
-				Status: string(RDSInstanceStateFailed),
-			}, nil
-		},
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, "resource is in failed state")
-	assert(testResource(), cl, result, expectedStatus)
-
-	// instance is in deleting state
-	cl = &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			return &rds.Instance{
-				Status: string(RDSInstanceStateDeleting),
-			}, nil
-		},
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, fmt.Sprintf("unexpected resource status: %s", RDSInstanceStateDeleting))
-	assert(testResource(), cl, resultRequeue, expectedStatus)
+	tp := testProvider()
+	tp.Spec.Secret.Name = "test-secret"
+	tr := testResource()
+	tr.Spec.ProviderRef.Name = providerName
 
-	// failed to retrieve instance secret
-	cl = &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			return &rds.Instance{
-				Status: string(RDSInstanceStateAvailable),
-			}, nil
-		},
+	c := &connecter{
+		kube:       NewFakeClient(tp),
+		kubeclient: NewSimpleClientset(),
+		newClient:  func(*aws.Config) rds.Client { return &MockRDSClient{} },
 	}
 
-	tr := testResource()
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.SetFailed(errorSyncResource, fmt.Sprintf("secrets \"%s\" not found", tr.Name))
-	assert(tr, cl, resultRequeue, expectedStatus)
+	// provider status is not ready
+	ext, err := c.Connect(ctx, tr)
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(MatchError(fmt.Sprintf("provider %s/%s is not ready", namespace, providerName)))
 
+	// error getting aws config - secret is not found
+	tp.Status.SetReady()
+	c.kube = NewFakeClient(tp)
+	ext, err = c.Connect(ctx, tr)
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(HaveOccurred())
+
+	// provider is not found
+	c.kube = NewFakeClient()
+	ext, err = c.Connect(ctx, tr)
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(HaveOccurred())
 }
 
-func TestSyncClusterUpdateSecretFailure(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	tr := testResource()
-	ts := connectionSecret(tr, "testPassword")
-
-	testError := "test-error-create-secret"
-	kc := NewSimpleClientset(ts)
-	kc.PrependReactor("update", "secrets", func(Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, fmt.Errorf(testError)
-	})
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: kc,
+func testSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: namespace},
+		Data:       map[string][]byte{corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password")},
 	}
+}
 
-	called := false
-	cl := &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			called = true
-			return &rds.Instance{
-				Status: string(RDSInstanceStateAvailable),
-			}, nil
-		},
+func availableInstance() *rds.Instance {
+	return &rds.Instance{
+		Status:   string(RDSInstanceStateAvailable),
+		Endpoint: "test-endpoint",
+		ARN:      "test-arn",
+		Class:    class,
+		Size:     size,
 	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.SetFailed(errorSyncResource, testError)
-
-	rs, err := r._sync(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
 }
 
-func TestSyncCluster(t *testing.T) {
+func TestObserve(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tr := testResource()
-	ts := connectionSecret(tr, "testPassword")
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: NewSimpleClientset(ts),
-	}
-
-	called := false
-	cl := &MockRDSClient{
-		MockGetInstance: func(s string) (instance *rds.Instance, e error) {
-			called = true
-			return &rds.Instance{
-				Status: string(RDSInstanceStateAvailable),
-			}, nil
-		},
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-
-	rs, err := r._sync(tr, cl)
-	g.Expect(rs).To(Equal(result))
+	// instance has not been created
+	e := &external{kube: NewFakeClient(testSecret()), client: &MockRDSClient{}}
+	o, err := e.Observe(ctx, testResource())
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	rr := assertResource(g, r, expectedStatus)
-	g.Expect(rr.Status.State).To(Equal(string(RDSInstanceStateAvailable)))
-}
-
-func TestDelete(t *testing.T) {
-	g := NewGomegaWithT(t)
+	g.Expect(o.ResourceExists).To(BeFalse())
 
+	// instance cannot be retrieved
+	testError := "test-resource-retrieve-error"
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return nil, fmt.Errorf(testError)
+	}}
 	tr := testResource()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: NewSimpleClientset(),
-	}
-
-	cl := &MockRDSClient{}
-
-	// test delete w/ reclaim policy
-	tr.Spec.ReclaimPolicy = corev1alpha1.ReclaimRetain
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetDeleting()
-
-	rs, err := r._delete(tr, cl)
-	g.Expect(rs).To(Equal(result))
+	tr.Status.InstanceName = instanceName
+	_, err = e.Observe(ctx, tr)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get RDS instance %s: %s", instanceName, testError)))
+
+	// instance does not exist
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return nil, awserr.New("DBInstanceNotFound", "not found", nil)
+	}}
+	o, err = e.Observe(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	assertResource(g, r, expectedStatus)
+	g.Expect(o.ResourceExists).To(BeFalse())
 
-	// test delete w/ delete policy
-	tr.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	called := false
-	cl.MockDeleteInstance = func(name string) (instance *rds.Instance, e error) {
-		called = true
-		return nil, nil
-	}
-
-	rs, err = r._delete(tr, cl)
-	g.Expect(rs).To(Equal(result))
+	// instance is being created
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return &rds.Instance{Status: string(RDSInstanceStateCreating)}, nil
+	}}
+	o, err = e.Observe(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-
-	// test delete w/ delete policy and delete error
-	testError := "test-delete-error"
-	called = false
-	cl.MockDeleteInstance = func(name string) (instance *rds.Instance, e error) {
-		called = true
-		return nil, fmt.Errorf(testError)
-	}
-	expectedStatus.SetFailed(errorDeleteResource, testError)
-
-	rs, err = r._delete(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ConnectionDetails).To(BeEmpty())
+	g.Expect(tr.Status.State).To(Equal(string(RDSInstanceStateCreating)))
+
+	// instance is in failed state
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return &rds.Instance{Status: string(RDSInstanceStateFailed)}, nil
+	}}
+	_, err = e.Observe(ctx, tr)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(requeue.IsTerminal(err)).To(BeTrue())
+
+	// instance is being deleted
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return &rds.Instance{Status: string(RDSInstanceStateDeleting)}, nil
+	}}
+	o, err = e.Observe(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
+	g.Expect(tr.Status.State).To(Equal(string(RDSInstanceStateDeleting)))
+
+	// instance is available
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return availableInstance(), nil
+	}}
+	o, err = e.Observe(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(o.ResourceReady).To(BeTrue())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
+	g.Expect(o.ConnectionDetails).To(HaveKeyWithValue(corev1alpha1.ResourceCredentialsSecretEndpointKey, []byte("test-endpoint")))
+	g.Expect(tr.Status.Endpoint).To(Equal("test-endpoint"))
+	g.Expect(tr.Status.ProviderID).To(Equal("test-arn"))
+	g.Expect(tr.Status.PendingModifications).To(BeNil())
+
+	// instance is available, but its size differs from the spec
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		db := availableInstance()
+		db.Size = size / 2
+		return db, nil
+	}}
+	o, err = e.Observe(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(BeFalse())
+
+	// instance is available, and its new size is pending
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		db := availableInstance()
+		db.Size = size / 2
+		db.Pending = &rds.PendingModifications{Size: size}
+		return db, nil
+	}}
+	o, err = e.Observe(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
+	g.Expect(tr.Status.PendingModifications).To(Equal(&RDSInstanceModifications{Size: size}))
+
+	// instance was adopted, and its master password is unknown
+	e.kube = NewFakeClient()
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return availableInstance(), nil
+	}}
+	o, err = e.Observe(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(BeFalse())
+
+	// instance named by the external name annotation is adopted
+	var observed string
+	e.client = &MockRDSClient{MockGetInstance: func(_ context.Context, name string) (*rds.Instance, error) {
+		observed = name
+		return availableInstance(), nil
+	}}
+	adopted := testResource()
+	adopted.SetAnnotations(map[string]string{corev1alpha1.AnnotationExternalName: "existing-instance"})
+	o, err = e.Observe(ctx, adopted)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(observed).To(Equal("existing-instance"))
+	g.Expect(adopted.Status.InstanceName).To(Equal("existing-instance"))
+
+	// instance is being modified
+	e.kube = NewFakeClient(testSecret())
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		db := availableInstance()
+		db.Status = "modifying"
+		db.Size = size / 2
+		return db, nil
+	}}
+	o, err = e.Observe(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
 }
 
 func TestCreate(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tr := testResource()
-
-	tk := NewSimpleClientset()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: tk,
-	}
-
-	called := false
-	cl := &MockRDSClient{
-		MockCreateInstance: func(s string, s2 string, spec *RDSInstanceSpec) (instance *rds.Instance, e error) {
-			called = true
+	// instance is created
+	var name, password string
+	e := &external{client: &MockRDSClient{
+		MockCreateInstance: func(_ context.Context, n, p string, _ *RDSInstanceSpec) (*rds.Instance, error) {
+			name, password = n, p
 			return nil, nil
 		},
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetCreating()
-
-	rs, err := r._create(testResource(), cl)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-	// assertSecret
-	g.Expect(tk.Actions()).To(HaveLen(2))
-	g.Expect(tk.Actions()[0].GetVerb()).To(Equal("get"))
-	g.Expect(tk.Actions()[1].GetVerb()).To(Equal("create"))
-	s, err := tk.CoreV1().Secrets(tr.Namespace).Get(tr.Name, metav1.GetOptions{})
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(s).NotTo(BeNil())
-}
-
-func TestCreateFail(t *testing.T) {
-	g := NewGomegaWithT(t)
+	}}
 	tr := testResource()
-	tk := NewSimpleClientset()
-	cl := &MockRDSClient{}
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: tk,
-	}
-
-	// test apply secret error
-	testError := "test-get-secret-error"
-	tk.PrependReactor("get", "secrets", func(action Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, fmt.Errorf(testError)
-	})
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateResource, testError)
-
-	rs, err := r._create(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	c, err := e.Create(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	assertResource(g, r, expectedStatus)
-
-	// test create resource error
+	g.Expect(name).To(Equal("mysql-test-uid"))
+	g.Expect(tr.Status.InstanceName).To(Equal(name))
+	g.Expect(c.ConnectionDetails).To(Equal(core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(masterUserName),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
+	}))
+
+	// instance was created by an earlier reconcile
+	e.client = &MockRDSClient{MockCreateInstance: func(context.Context, string, string, *RDSInstanceSpec) (*rds.Instance, error) {
+		return nil, awserr.New("DBInstanceAlreadyExists", "already exists", nil)
+	}}
 	tr = testResource()
-	r.kubeclient = NewSimpleClientset()
-	called := false
-	testError = "test-create-error"
-	cl.MockCreateInstance = func(s string, s2 string, spec *RDSInstanceSpec) (instance *rds.Instance, e error) {
-		called = true
-		return nil, fmt.Errorf(testError)
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateResource, testError)
-
-	rs, err = r._create(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	c, err = e.Create(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	g.Expect(tr.Status.InstanceName).To(Equal("mysql-test-uid"))
+	g.Expect(c.ConnectionDetails).To(BeEmpty())
+
+	// instance cannot be created
+	testError := "test-create-error"
+	e.client = &MockRDSClient{MockCreateInstance: func(context.Context, string, string, *RDSInstanceSpec) (*rds.Instance, error) {
+		return nil, fmt.Errorf(testError)
+	}}
+	tr = testResource()
+	_, err = e.Create(ctx, tr)
+	g.Expect(err).To(MatchError("cannot create RDS instance mysql-test-uid: " + testError))
+	g.Expect(tr.Status.InstanceName).To(BeEmpty())
 }
 
-func TestConnect(t *testing.T) {
+func TestUpdate(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tp := testProvider()
 	tr := testResource()
+	tr.Status.InstanceName = instanceName
+
+	// instance is modified
+	var observed *rds.Instance
+	e := &external{kube: NewFakeClient(testSecret()), client: &MockRDSClient{
+		MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+			db := availableInstance()
+			db.Size = size / 2
+			return db, nil
+		},
+		MockModifyInstance: func(_ context.Context, _ *RDSInstanceSpec, o *rds.Instance) (*rds.Instance, error) {
+			observed = o
+			db := availableInstance()
+			db.Size = size / 2
+			db.Pending = &rds.PendingModifications{Size: size}
+			return db, nil
+		},
+	}}
+	_, err := e.Update(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(observed.Size).To(Equal(size / 2))
+	g.Expect(tr.Status.PendingModifications).To(Equal(&RDSInstanceModifications{Size: size}))
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tp),
-		kubeclient: NewSimpleClientset(),
+	// instance cannot be retrieved
+	testError := "test-retrieve-error"
+	e.client = &MockRDSClient{MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+		return nil, fmt.Errorf(testError)
+	}}
+	_, err = e.Update(ctx, tr)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get RDS instance %s: %s", instanceName, testError)))
+
+	// instance cannot be modified
+	testError = "test-modify-error"
+	e.client = &MockRDSClient{
+		MockGetInstance: func(context.Context, string) (*rds.Instance, error) { return availableInstance(), nil },
+		MockModifyInstance: func(context.Context, *RDSInstanceSpec, *rds.Instance) (*rds.Instance, error) {
+			return nil, fmt.Errorf(testError)
+		},
 	}
-
-	// provider status is not ready
-	c, err := r._connect(tr)
-	g.Expect(c).To(BeNil())
-	g.Expect(err).To(And(Not(BeNil()), MatchError("provider is not ready")))
-
-	// error getting aws config - secret is not found
-	tp.Status.SetReady()
-	r.Client = NewFakeClient(tp)
-	c, err = r._connect(tr)
-	g.Expect(c).To(BeNil())
-	g.Expect(err).To(Not(BeNil()))
+	_, err = e.Update(ctx, tr)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot modify RDS instance %s: %s", instanceName, testError)))
+
+	// instance storage cannot be shrunk
+	e.client = &MockRDSClient{
+		MockGetInstance: func(context.Context, string) (*rds.Instance, error) {
+			db := availableInstance()
+			db.Size = size * 2
+			return db, nil
+		},
+		MockModifyInstance: func(context.Context, *RDSInstanceSpec, *rds.Instance) (*rds.Instance, error) {
+			t.Errorf("storage must not be shrunk")
+			return nil, nil
+		},
+	}
+	_, err = e.Update(ctx, tr)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot shrink storage of RDS instance %s to %d GB", instanceName, size)))
+	g.Expect(requeue.IsTerminal(err)).To(BeTrue())
+
+	// master password of an adopted instance is reset
+	var password string
+	e.kube = NewFakeClient()
+	e.client = &MockRDSClient{MockResetPassword: func(_ context.Context, _, p string) (*rds.Instance, error) {
+		password = p
+		db := availableInstance()
+		db.MasterUsername = "adopted-user"
+		return db, nil
+	}}
+	u, err := e.Update(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(password).NotTo(BeEmpty())
+	g.Expect(u.ConnectionDetails).To(Equal(core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte("adopted-user"),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
+	}))
 }
 
-func TestReconcile(t *testing.T) {
+func TestDelete(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	tr := testResource()
+	tr.Status.InstanceName = instanceName
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: NewSimpleClientset(),
-	}
-
-	// test connect error
+	// test delete w/ delete policy
+	tr.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
 	called := false
-	testError := "test-connect-error"
-	r.connect = func(instance *RDSInstance) (client rds.Client, e error) {
+	snapshot := "unset"
+	cl := &MockRDSClient{MockDeleteInstance: func(_ context.Context, _, finalSnapshot string) (*rds.Instance, error) {
 		called = true
-		return nil, fmt.Errorf(testError)
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorResourceClient, testError)
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-
-	// test delete
-	r.connect = func(instance *RDSInstance) (client rds.Client, e error) {
-		t := metav1.Now()
-		instance.DeletionTimestamp = &t
+		snapshot = finalSnapshot
 		return nil, nil
-	}
-	called = false
-	r.delete = func(instance *RDSInstance, client rds.Client) (i reconcile.Result, e error) {
-		called = true
-		return result, nil
-	}
-	r.Reconcile(request)
+	}}
+	e := &external{client: cl}
+	g.Expect(e.Delete(ctx, tr)).To(Succeed())
 	g.Expect(called).To(BeTrue())
-
-	// test create
-	r.connect = func(instance *RDSInstance) (client rds.Client, e error) {
-		return nil, nil
+	g.Expect(snapshot).To(BeEmpty())
+
+	// test delete w/ snapshot policy
+	tr.Spec.ReclaimPolicy = corev1alpha1.ReclaimSnapshot
+	g.Expect(e.Delete(ctx, tr)).To(Succeed())
+	g.Expect(snapshot).To(Equal("test-instance-final"))
+	g.Expect(tr.Status.FinalSnapshot).To(Equal("test-instance-final"))
+
+	// test delete w/ snapshot policy of an instance that is already gone
+	tr.Status.FinalSnapshot = ""
+	cl.MockDeleteInstance = func(context.Context, string, string) (*rds.Instance, error) {
+		return nil, awserr.New("DBInstanceNotFound", "not found", nil)
 	}
-	called = false
-	r.delete = r._delete
-	r.create = func(instance *RDSInstance, client rds.Client) (i reconcile.Result, e error) {
+	g.Expect(e.Delete(ctx, tr)).To(Succeed())
+	g.Expect(tr.Status.FinalSnapshot).To(BeEmpty())
+	cl.MockDeleteInstance = func(_ context.Context, _, finalSnapshot string) (*rds.Instance, error) {
 		called = true
-		return result, nil
-	}
-	r.Reconcile(request)
-	g.Expect(called).To(BeTrue())
-
-	// test sync
-	r.connect = func(instance *RDSInstance) (client rds.Client, e error) {
-		instance.Status.InstanceName = "foo"
 		return nil, nil
 	}
+
+	// test delete of an instance that is already being deleted
 	called = false
-	r.create = r._create
-	r.sync = func(instance *RDSInstance, client rds.Client) (i reconcile.Result, e error) {
-		called = true
-		return result, nil
-	}
-	r.Reconcile(request)
-	g.Expect(called).To(BeTrue())
+	tr.Status.State = string(RDSInstanceStateDeleting)
+	g.Expect(e.Delete(ctx, tr)).To(Succeed())
+	g.Expect(called).To(BeFalse())
 
+	// test delete error
+	tr.Status.State = string(RDSInstanceStateAvailable)
+	testError := "test-delete-error"
+	cl.MockDeleteInstance = func(context.Context, string, string) (*rds.Instance, error) {
+		return nil, fmt.Errorf(testError)
+	}
+	g.Expect(e.Delete(ctx, tr)).To(MatchError(fmt.Sprintf("cannot delete RDS instance %s: %s", instanceName, testError)))
 }
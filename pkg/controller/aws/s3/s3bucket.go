@@ -18,293 +18,198 @@ package s3
 
 import (
 	"context"
-	"fmt"
-	"log"
 
-	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	bucketv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
-	"github.com/crossplaneio/crossplane/pkg/clients/aws"
+	awsclient "github.com/crossplaneio/crossplane/pkg/clients/aws"
 	"github.com/crossplaneio/crossplane/pkg/clients/aws/s3"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 const (
 	controllerName = "s3bucket.aws.crossplane.io"
-	finalizer      = "finalizer." + controllerName
 
-	errorResourceClient = "Failed to create s3 client"
-	errorCreateResource = "Failed to create resource"
-	errorSyncResource   = "Failed to sync resource state"
-	errorDeleteResource = "Failed to delete resource"
+	errNotS3Bucket = "managed resource is not an S3 bucket"
 )
 
-var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
-)
-
-// Add creates a new Instance Controller and adds it to the Manager with default RBAC.
-// The Manager will set fields on the Controller and Start it when the Manager is Started.
+// Add creates a new S3Bucket controller and adds it to the Manager. The
+// Manager will set fields on the controller and start it when the Manager is
+// started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	c := &connecter{
+		kube:       mgr.GetClient(),
+		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+		newClient:  s3.NewClient,
+	}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &bucketv1alpha1.S3Bucket{} }, c,
+		awsclient.ClassifyError, corev1alpha1.ReclaimDelete)
 }
 
-// Reconciler reconciles a S3Bucket object
-type Reconciler struct {
-	client.Client
-	scheme     *runtime.Scheme
+// connecter returns S3 clients authenticated using the credentials of the AWS
+// Provider referenced by an S3Bucket.
+type connecter struct {
+	kube       client.Client
 	kubeclient kubernetes.Interface
-	recorder   record.EventRecorder
-
-	connect func(*bucketv1alpha1.S3Bucket) (s3.Service, error)
-	create  func(*bucketv1alpha1.S3Bucket, s3.Service) (reconcile.Result, error)
-	sync    func(*bucketv1alpha1.S3Bucket, s3.Service) (reconcile.Result, error)
-	delete  func(*bucketv1alpha1.S3Bucket, s3.Service) (reconcile.Result, error)
+	newClient  func(*aws.Config) s3.Service
 }
 
-// newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	r := &Reconciler{
-		Client:     mgr.GetClient(),
-		scheme:     mgr.GetScheme(),
-		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
-		recorder:   mgr.GetRecorder(controllerName),
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	b, ok := mg.(*bucketv1alpha1.S3Bucket)
+	if !ok {
+		return nil, errors.New(errNotS3Bucket)
 	}
-	r.connect = r._connect
-	r.create = r._create
-	r.delete = r._delete
-	r.sync = r._sync
-	return r
-}
 
-// add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
+	p := &awsv1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: b.GetNamespace(), Name: b.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
 	}
 
-	// Watch for changes to Instance
-	err = c.Watch(&source.Kind{Type: &bucketv1alpha1.S3Bucket{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
 	}
 
-	// Watch for changes to Instance Secret
-	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
-		IsController: true,
-		OwnerType:    &bucketv1alpha1.S3Bucket{},
-	})
+	config, err := awsclient.Config(c.kubeclient, p)
 	if err != nil {
-		return err
+		return nil, errors.Wrapf(err, "cannot get AWS config of provider %s", n)
 	}
 
-	return nil
-}
+	// Bucket Region and client region must match.
+	config.Region = b.Spec.Region
 
-// fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(bucket *bucketv1alpha1.S3Bucket, reason, msg string) (reconcile.Result, error) {
-	bucket.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
-	return reconcile.Result{Requeue: true}, r.Update(context.TODO(), bucket)
+	return &external{client: c.newClient(config)}, nil
 }
 
-// connectionSecret return secret object for this resource
-func connectionSecret(bucket *bucketv1alpha1.S3Bucket, accessKey *iam.AccessKey) *corev1.Secret {
-	return &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            bucket.ConnectionSecretName(),
-			Namespace:       bucket.Namespace,
-			OwnerReferences: []metav1.OwnerReference{bucket.OwnerReference()},
-		},
-
-		Data: map[string][]byte{
-			corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(util.StringValue(accessKey.AccessKeyId)),
-			corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(util.StringValue(accessKey.SecretAccessKey)),
-			corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(bucket.Endpoint()),
-		},
-	}
+// external manages S3 buckets and their IAM users using the S3 and IAM APIs.
+type external struct {
+	client s3.Service
 }
 
-func (r *Reconciler) _connect(instance *bucketv1alpha1.S3Bucket) (s3.Service, error) {
-	// Fetch AWS Provider
-	p := &awsv1alpha1.Provider{}
-	providerNamespacedName := types.NamespacedName{
-		Namespace: instance.Namespace,
-		Name:      instance.Spec.ProviderRef.Name,
-	}
-	err := r.Get(ctx, providerNamespacedName, p)
-	if err != nil {
-		return nil, err
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	b, ok := mg.(*bucketv1alpha1.S3Bucket)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotS3Bucket)
 	}
 
-	// Check provider status
-	if !p.IsValid() {
-		return nil, fmt.Errorf("provider is not ready")
+	// The bucket's user policy version is recorded only once the bucket and
+	// its IAM user have both been created.
+	if b.Status.IAMUsername == "" || b.Status.LastUserPolicyVersion == 0 {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	// Get Provider's AWS Config
-	config, err := aws.Config(r.kubeclient, p)
+	info, err := e.client.GetBucketInfo(b.Status.IAMUsername, &b.Spec)
+	if awsclient.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
 	if err != nil {
-		return nil, err
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get S3 bucket %s", b.Spec.Name)
 	}
 
-	// Bucket Region and client region must match.
-	config.Region = instance.Spec.Region
+	// Eventually consistent, so we check if this version is newer than our
+	// stored version.
+	// TODO: Detect if the bucket CannedACL has changed, possibly by managing
+	// grants list directly.
+	changed, err := b.HasPolicyChanged(info.UserPolicyVersion)
+	if err != nil {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot compare user policy version of S3 bucket %s", b.Spec.Name)
+	}
 
-	// Create new S3 S3Client
-	return s3.NewClient(config), nil
+	return core.ExternalObservation{
+		ResourceExists:    true,
+		ResourceReady:     true,
+		ResourceUpToDate:  info.Versioning == b.Spec.Versioning && !changed,
+		ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(b.Endpoint())},
+	}, nil
 }
 
-func (r *Reconciler) _create(bucket *bucketv1alpha1.S3Bucket, client s3.Service) (reconcile.Result, error) {
-	bucket.Status.UnsetAllConditions()
-	util.AddFinalizer(&bucket.ObjectMeta, finalizer)
-	err := client.CreateOrUpdateBucket(&bucket.Spec)
-	if err != nil {
-		return r.fail(bucket, errorCreateResource, err.Error())
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	b, ok := mg.(*bucketv1alpha1.S3Bucket)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotS3Bucket)
 	}
 
-	// Set username for iam user
-	if bucket.Status.IAMUsername == "" {
-		bucket.Status.IAMUsername = s3.GenerateBucketUsername(&bucket.Spec)
+	if err := e.client.CreateOrUpdateBucket(&b.Spec); err != nil {
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create S3 bucket %s", b.Spec.Name)
 	}
 
-	// Get access keys for iam user
-	accessKeys, currentVersion, err := client.CreateUser(bucket.Status.IAMUsername, &bucket.Spec)
-	if err != nil {
-		return r.fail(bucket, errorCreateResource, err.Error())
+	// Set username for iam user. The username is generated only once so that
+	// retries do not create additional IAM users.
+	if b.Status.IAMUsername == "" {
+		b.Status.IAMUsername = s3.GenerateBucketUsername(&b.Spec)
 	}
 
-	// Set user policy version in status so we can detect policy drift
-	err = bucket.SetUserPolicyVersion(currentVersion)
+	keys, version, err := e.client.CreateUser(b.Status.IAMUsername, &b.Spec)
 	if err != nil {
-		return r.fail(bucket, errorCreateResource, err.Error())
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create IAM user %s", b.Status.IAMUsername)
 	}
 
-	// Set access keys into a secret for local access creds to s3 bucket
-	secret := connectionSecret(bucket, accessKeys)
-	secret.OwnerReferences = append(secret.OwnerReferences, bucket.OwnerReference())
-	bucket.Status.ConnectionSecretRef = corev1.LocalObjectReference{Name: secret.Name}
-
-	_, err = util.ApplySecret(r.kubeclient, secret)
-	if err != nil {
-		return r.fail(bucket, errorCreateResource, err.Error())
+	// Set user policy version in status so we can detect policy drift.
+	if err := b.SetUserPolicyVersion(version); err != nil {
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot set user policy version of S3 bucket %s", b.Spec.Name)
 	}
 
-	// No longer creating, we're ready!
-	bucket.Status.UnsetCondition(corev1alpha1.Creating)
-	bucket.Status.SetReady()
-	return result, r.Update(ctx, bucket)
+	b.Status.ConnectionSecretRef = corev1.LocalObjectReference{Name: b.ConnectionSecretName()}
+	return core.ExternalCreation{ConnectionDetails: core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(util.StringValue(keys.AccessKeyId)),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(util.StringValue(keys.SecretAccessKey)),
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(b.Endpoint()),
+	}}, nil
 }
 
-func (r *Reconciler) _sync(bucket *bucketv1alpha1.S3Bucket, client s3.Service) (reconcile.Result, error) {
-	if bucket.Status.IAMUsername == "" {
-		return r.fail(bucket, errorSyncResource, "username not set, .Status.IAMUsername")
-	}
-	bucketInfo, err := client.GetBucketInfo(bucket.Status.IAMUsername, &bucket.Spec)
-	if err != nil {
-		return r.fail(bucket, errorSyncResource, err.Error())
-	}
-
-	if bucketInfo.Versioning != bucket.Spec.Versioning {
-		err := client.UpdateVersioning(&bucket.Spec)
-		if err != nil {
-			return r.fail(bucket, errorSyncResource, err.Error())
-		}
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	b, ok := mg.(*bucketv1alpha1.S3Bucket)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotS3Bucket)
 	}
 
-	// TODO: Detect if the bucket CannedACL has changed, possibly by managing grants list directly.
-	err = client.UpdateBucketACL(&bucket.Spec)
+	info, err := e.client.GetBucketInfo(b.Status.IAMUsername, &b.Spec)
 	if err != nil {
-		return r.fail(bucket, errorSyncResource, err.Error())
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot get S3 bucket %s", b.Spec.Name)
 	}
 
-	// Eventually consistent, so we check if this version is newer than our stored version.
-	changed, err := bucket.HasPolicyChanged(bucketInfo.UserPolicyVersion)
-	if err != nil {
-		return r.fail(bucket, errorSyncResource, err.Error())
-	}
-	if changed {
-		currentVersion, err := client.UpdatePolicyDocument(bucket.Status.IAMUsername, &bucket.Spec)
-		if err != nil {
-			return r.fail(bucket, errorSyncResource, err.Error())
-		}
-		err = bucket.SetUserPolicyVersion(currentVersion)
-		if err != nil {
-			return r.fail(bucket, errorSyncResource, err.Error())
+	if info.Versioning != b.Spec.Versioning {
+		if err := e.client.UpdateVersioning(&b.Spec); err != nil {
+			return core.ExternalUpdate{}, errors.Wrapf(err, "cannot update versioning of S3 bucket %s", b.Spec.Name)
 		}
 	}
 
-	// Sync completed, so lets unset failed conditions.
-	bucket.Status.UnsetCondition(corev1alpha1.Failed)
-
-	return result, r.Update(ctx, bucket)
-}
-
-func (r *Reconciler) _delete(bucket *bucketv1alpha1.S3Bucket, client s3.Service) (reconcile.Result, error) {
-	if bucket.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		if err := client.DeleteBucket(bucket); err != nil {
-			return r.fail(bucket, errorDeleteResource, err.Error())
-		}
+	if err := e.client.UpdateBucketACL(&b.Spec); err != nil {
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot update ACL of S3 bucket %s", b.Spec.Name)
 	}
 
-	bucket.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Deleting, "", ""))
-	util.RemoveFinalizer(&bucket.ObjectMeta, finalizer)
-	return result, r.Update(ctx, bucket)
-}
-
-// Reconcile reads that state of the bucket for an Instance object and makes changes based on the state read
-// and what is in the Instance.Spec
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", bucketv1alpha1.S3BucketKindAPIVersion, request)
-
-	// Fetch the CRD instance
-	bucket := &bucketv1alpha1.S3Bucket{}
-
-	err := r.Get(ctx, request.NamespacedName, bucket)
+	changed, err := b.HasPolicyChanged(info.UserPolicyVersion)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return result, nil
-		}
-		// Error reading the object - requeue the request.
-		log.Printf("failed to get object at start of reconcile loop: %+v", err)
-		return result, err
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot compare user policy version of S3 bucket %s", b.Spec.Name)
+	}
+	if !changed {
+		return core.ExternalUpdate{}, nil
 	}
 
-	s3Client, err := r.connect(bucket)
+	version, err := e.client.UpdatePolicyDocument(b.Status.IAMUsername, &b.Spec)
 	if err != nil {
-		return r.fail(bucket, errorResourceClient, err.Error())
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot update policy of IAM user %s", b.Status.IAMUsername)
 	}
+	return core.ExternalUpdate{}, errors.Wrapf(b.SetUserPolicyVersion(version), "cannot set user policy version of S3 bucket %s", b.Spec.Name)
+}
 
-	// Check for deletion
-	if bucket.DeletionTimestamp != nil {
-		return r.delete(bucket, s3Client)
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	b, ok := mg.(*bucketv1alpha1.S3Bucket)
+	if !ok {
+		return errors.New(errNotS3Bucket)
 	}
 
-	// Create s3 bucket
-	if bucket.Status.IAMUsername == "" {
-		return r.create(bucket, s3Client)
+	if err := e.client.DeleteBucket(b); err != nil && !awsclient.IsErrorNotFound(err) {
+		return errors.Wrapf(err, "cannot delete S3 bucket %s", b.Spec.Name)
 	}
-
-	// Update the bucket if it's no longer there.
-	return r.sync(bucket, s3Client)
+	return nil
 }
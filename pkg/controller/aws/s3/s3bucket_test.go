@@ -17,27 +17,28 @@ limitations under the License.
 package s3
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	. "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
-	. "k8s.io/client-go/testing"
 	. "sigs.k8s.io/controller-runtime/pkg/client/fake"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
-	"github.com/crossplaneio/crossplane/pkg/apis/aws"
+	apisaws "github.com/crossplaneio/crossplane/pkg/apis/aws"
 	. "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
 	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
 	client "github.com/crossplaneio/crossplane/pkg/clients/aws/s3"
 	. "github.com/crossplaneio/crossplane/pkg/clients/aws/s3/fake"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
@@ -47,18 +48,10 @@ const (
 	bucketName   = "test-bucket"
 )
 
-var (
-	key = types.NamespacedName{
-		Namespace: namespace,
-		Name:      bucketName,
-	}
-	request = reconcile.Request{
-		NamespacedName: key,
-	}
-)
+var ctx = context.Background()
 
 func init() {
-	if err := aws.AddToScheme(scheme.Scheme); err != nil {
+	if err := apisaws.AddToScheme(scheme.Scheme); err != nil {
 		panic(err)
 	}
 }
@@ -81,214 +74,112 @@ func testResource() *S3Bucket {
 			Namespace: namespace,
 		},
 		Spec: S3BucketSpec{
+			Name:            bucketName,
 			LocalPermission: &perm,
+			ProviderRef:     corev1.LocalObjectReference{Name: providerName},
 		},
 		Status: S3BucketStatus{
-			IAMUsername: testIAMUsername,
+			IAMUsername:           testIAMUsername,
+			LastUserPolicyVersion: 1,
+			LastLocalPermission:   perm,
 		},
 	}
 }
 
-// assertResource a helper function to check on cluster and its status
-func assertResource(g *GomegaWithT, r *Reconciler, s corev1alpha1.ConditionedStatus) *S3Bucket {
-	resource := &S3Bucket{}
-	err := r.Get(ctx, key, resource)
-	g.Expect(err).To(BeNil())
-	g.Expect(resource.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(s))
-	return resource
-}
-
-func TestSyncBucketError(t *testing.T) {
+func TestConnect(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	assert := func(instance *S3Bucket, client client.Service, expectedResult reconcile.Result, expectedStatus corev1alpha1.ConditionedStatus) {
-		r := &Reconciler{
-			Client:     NewFakeClient(instance),
-			kubeclient: NewSimpleClientset(),
-		}
-
-		rs, err := r._sync(instance, client)
-
-		g.Expect(err).NotTo(HaveOccurred())
-		g.Expect(rs).To(Equal(expectedResult))
-		assertResource(g, r, expectedStatus)
-	}
-
-	// error iam username not set
-	testError := "username not set, .Status.IAMUsername"
-	cl := &MockS3Client{}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, testError)
-	noUserResource := testResource()
-	noUserResource.Status.IAMUsername = ""
-	assert(noUserResource, cl, resultRequeue, expectedStatus)
-
-	// error get bucket info
-	testError = "mock get bucket info err"
-	cl.MockGetBucketInfo = func(username string, spec *S3BucketSpec) (*client.Bucket, error) {
-		return nil, fmt.Errorf(testError)
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, testError)
-	assert(testResource(), cl, resultRequeue, expectedStatus)
-
-	//update versioning error
-	cl.MockGetBucketInfo = func(username string, spec *S3BucketSpec) (*client.Bucket, error) {
-		return &client.Bucket{Versioning: true, UserPolicyVersion: "v1"}, nil
-	}
-
-	testError = "bucket-versioning-update-error"
-	cl.MockUpdateVersioning = func(spec *S3BucketSpec) error {
-		return fmt.Errorf(testError)
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, testError)
-	assert(testResource(), cl, resultRequeue, expectedStatus)
-
-	// update bucket acl error
-	cl.MockGetBucketInfo = func(username string, spec *S3BucketSpec) (*client.Bucket, error) {
-		return &client.Bucket{Versioning: false, UserPolicyVersion: "v1"}, nil
-	}
-
-	testError = "bucket-acl-update-error"
-	cl.MockUpdateBucketACL = func(spec *S3BucketSpec) error {
-		return fmt.Errorf(testError)
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, testError)
-	assert(testResource(), cl, resultRequeue, expectedStatus)
-
-	cl.MockUpdateBucketACL = func(spec *S3BucketSpec) error {
-		return nil
-	}
-
-	// Update policy error
-	perm := storagev1alpha1.WriteOnlyPermission
-	bucketWithPolicyChanges := testResource()
-	bucketWithPolicyChanges.Spec.LocalPermission = &perm
-	bucketWithPolicyChanges.Status.LastUserPolicyVersion = 1
-	bucketWithPolicyChanges.Status.LastLocalPermission = storagev1alpha1.ReadOnlyPermission
-
-	testError = "policy-update-err"
-	cl.MockUpdatePolicyDocument = func(username string, spec *S3BucketSpec) (string, error) {
-		return "", fmt.Errorf(testError)
-	}
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncResource, testError)
-	assert(testResource(), cl, resultRequeue, expectedStatus)
-}
-
-func TestSyncCluster(t *testing.T) {
-	g := NewGomegaWithT(t)
+	tp := testProvider()
 	tr := testResource()
-	tr.Status.LastUserPolicyVersion = 1
-	tr.Status.LastLocalPermission = storagev1alpha1.ReadOnlyPermission
-	tr.Status.SetFailed("test", "test-msg")
+	tr.Spec.Region = "us-west-2"
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
+	var region string
+	c := &connecter{
+		kube:       NewFakeClient(tp),
 		kubeclient: NewSimpleClientset(),
-	}
-	//
-	updateBucketACLCalled := false
-	getBucketInfoCalled := false
-	cl := &MockS3Client{
-		MockUpdateBucketACL: func(spec *S3BucketSpec) error {
-			updateBucketACLCalled = true
-			return nil
-		},
-		MockGetBucketInfo: func(username string, spec *S3BucketSpec) (*client.Bucket, error) {
-			getBucketInfoCalled = true
-			return &client.Bucket{Versioning: false, UserPolicyVersion: "v1"}, nil
+		newClient: func(config *aws.Config) client.Service {
+			region = config.Region
+			return &MockS3Client{}
 		},
 	}
 
-	// Successful Sync should clear failed setatus.
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed("test", "test-msg")
-	expectedStatus.UnsetCondition(corev1alpha1.Failed)
-	rs, err := r._sync(tr, cl)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(updateBucketACLCalled).To(BeTrue())
-	g.Expect(getBucketInfoCalled).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	// provider status is not ready
+	ext, err := c.Connect(ctx, tr)
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(MatchError(fmt.Sprintf("provider %s/%s is not ready", namespace, providerName)))
+
+	// error getting aws config - secret is not found
+	tp.Status.SetReady()
+	c.kube = NewFakeClient(tp)
+	ext, err = c.Connect(ctx, tr)
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(region).To(BeEmpty())
 }
 
-func TestDelete(t *testing.T) {
+func TestObserve(t *testing.T) {
 	g := NewGomegaWithT(t)
 
+	// bucket has not been created
+	e := &external{client: &MockS3Client{}}
 	tr := testResource()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: NewSimpleClientset(),
-	}
-
-	cl := &MockS3Client{}
-
-	// test delete w/ reclaim policy
-	tr.Spec.ReclaimPolicy = corev1alpha1.ReclaimRetain
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetDeleting()
-
-	rs, err := r._delete(tr, cl)
-	g.Expect(rs).To(Equal(result))
+	tr.Status.IAMUsername = ""
+	o, err := e.Observe(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	assertResource(g, r, expectedStatus)
+	g.Expect(o.ResourceExists).To(BeFalse())
 
-	// test delete w/ delete policy
-	tr.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	called := false
-	cl.MockDelete = func(bucket *S3Bucket) error {
-		called = true
-		return nil
-	}
+	// bucket info cannot be retrieved
+	testError := "mock get bucket info err"
+	e.client = &MockS3Client{MockGetBucketInfo: func(string, *S3BucketSpec) (*client.Bucket, error) {
+		return nil, fmt.Errorf(testError)
+	}}
+	_, err = e.Observe(ctx, testResource())
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get S3 bucket %s: %s", bucketName, testError)))
+
+	// bucket does not exist
+	e.client = &MockS3Client{MockGetBucketInfo: func(string, *S3BucketSpec) (*client.Bucket, error) {
+		return nil, awserr.New("NoSuchBucket", "not found", nil)
+	}}
+	o, err = e.Observe(ctx, testResource())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeFalse())
 
-	rs, err = r._delete(tr, cl)
-	g.Expect(rs).To(Equal(result))
+	// bucket is up to date
+	e.client = &MockS3Client{MockGetBucketInfo: func(string, *S3BucketSpec) (*client.Bucket, error) {
+		return &client.Bucket{Versioning: false, UserPolicyVersion: "v1"}, nil
+	}}
+	o, err = e.Observe(ctx, testResource())
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(o.ResourceReady).To(BeTrue())
+	g.Expect(o.ResourceUpToDate).To(BeTrue())
+	g.Expect(o.ConnectionDetails).To(HaveKey(corev1alpha1.ResourceCredentialsSecretEndpointKey))
 
-	// test delete w/ delete policy error
-	testError := "test-delete-error"
-	called = false
-	cl.MockDelete = func(bucket *S3Bucket) error {
-		called = true
-		return fmt.Errorf(testError)
-	}
-	expectedStatus.SetFailed(errorDeleteResource, testError)
+	// bucket versioning has drifted
+	tr = testResource()
+	tr.Spec.Versioning = true
+	o, err = e.Observe(ctx, tr)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(BeFalse())
 
-	rs, err = r._delete(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	// bucket user policy has a newer version
+	e.client = &MockS3Client{MockGetBucketInfo: func(string, *S3BucketSpec) (*client.Bucket, error) {
+		return &client.Bucket{Versioning: false, UserPolicyVersion: "v2"}, nil
+	}}
+	o, err = e.Observe(ctx, testResource())
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	g.Expect(o.ResourceUpToDate).To(BeFalse())
 }
 
 func TestCreate(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tr := testResource()
-
-	tk := NewSimpleClientset()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: tk,
-	}
-
 	createOrUpdateBucketCalled := false
-	createUserCalled := false
 	cl := &MockS3Client{
 		MockCreateUser: func(username string, spec *S3BucketSpec) (*iam.AccessKey, string, error) {
-			createUserCalled = true
 			fakeKey := &iam.AccessKey{
 				AccessKeyId:     util.String("fake-string"),
-				SecretAccessKey: util.String(""),
+				SecretAccessKey: util.String("fake-secret"),
 			}
 			return fakeKey, "v2", nil
 		},
@@ -297,201 +188,135 @@ func TestCreate(t *testing.T) {
 			return nil
 		},
 	}
+	e := &external{client: cl}
 
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-
-	resource := testResource()
-	rs, err := r._create(resource, cl)
-	g.Expect(rs).To(Equal(result))
+	// bucket and user are created
+	tr := testResource()
+	tr.Status = S3BucketStatus{}
+	c, err := e.Create(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(createOrUpdateBucketCalled).To(BeTrue())
-	g.Expect(createUserCalled).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-	g.Expect(resource.Status.LastUserPolicyVersion).To(Equal(2))
-
-	// assertSecret
-	g.Expect(tk.Actions()).To(HaveLen(2))
-	g.Expect(tk.Actions()[0].GetVerb()).To(Equal("get"))
-	g.Expect(tk.Actions()[1].GetVerb()).To(Equal("create"))
-	s, err := tk.CoreV1().Secrets(tr.Namespace).Get(tr.Name, metav1.GetOptions{})
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(s).NotTo(BeNil())
+	g.Expect(tr.Status.IAMUsername).NotTo(BeEmpty())
+	g.Expect(tr.Status.LastUserPolicyVersion).To(Equal(2))
+	g.Expect(tr.Status.ConnectionSecretRef.Name).To(Equal(bucketName))
+	g.Expect(c.ConnectionDetails).To(Equal(core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte("fake-string"),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("fake-secret"),
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(tr.Endpoint()),
+	}))
+
+	// user cannot be created
+	testError := "test-create-user-error"
+	cl.MockCreateUser = func(string, *S3BucketSpec) (*iam.AccessKey, string, error) {
+		return nil, "", fmt.Errorf(testError)
+	}
+	tr = testResource()
+	tr.Status = S3BucketStatus{IAMUsername: "test-username"}
+	_, err = e.Create(ctx, tr)
+	g.Expect(err).To(MatchError("cannot create IAM user test-username: " + testError))
+	g.Expect(tr.Status.IAMUsername).To(Equal("test-username"))
+
+	// bucket cannot be created
+	testError = "test-create-bucket-error"
+	cl.MockCreateOrUpdateBucket = func(*S3BucketSpec) error { return fmt.Errorf(testError) }
+	_, err = e.Create(ctx, testResource())
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot create S3 bucket %s: %s", bucketName, testError)))
 }
 
-func TestCreateFail(t *testing.T) {
+func TestUpdate(t *testing.T) {
 	g := NewGomegaWithT(t)
-	tr := testResource()
-	tk := NewSimpleClientset()
+
+	updateVersioningCalled := false
+	updateBucketACLCalled := false
+	updatePolicyCalled := false
 	cl := &MockS3Client{
-		MockCreateUser: func(username string, spec *S3BucketSpec) (*iam.AccessKey, string, error) {
-			fakeKey := &iam.AccessKey{
-				AccessKeyId:     util.String("fake-string"),
-				SecretAccessKey: util.String(""),
-			}
-			return fakeKey, "v2", nil
+		MockGetBucketInfo: func(string, *S3BucketSpec) (*client.Bucket, error) {
+			return &client.Bucket{Versioning: false, UserPolicyVersion: "v1"}, nil
 		},
-		MockCreateOrUpdateBucket: func(spec *S3BucketSpec) error {
+		MockUpdateVersioning: func(*S3BucketSpec) error {
+			updateVersioningCalled = true
 			return nil
 		},
+		MockUpdateBucketACL: func(*S3BucketSpec) error {
+			updateBucketACLCalled = true
+			return nil
+		},
+		MockUpdatePolicyDocument: func(string, *S3BucketSpec) (string, error) {
+			updatePolicyCalled = true
+			return "v3", nil
+		},
 	}
+	e := &external{client: cl}
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: tk,
-	}
-
-	// test apply secret error
-	testError := "test-get-secret-error"
-	tk.PrependReactor("get", "secrets", func(action Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, fmt.Errorf(testError)
-	})
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateResource, testError)
-
-	rs, err := r._create(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	// only versioning has drifted
+	tr := testResource()
+	tr.Spec.Versioning = true
+	_, err := e.Update(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	assertResource(g, r, expectedStatus)
+	g.Expect(updateVersioningCalled).To(BeTrue())
+	g.Expect(updateBucketACLCalled).To(BeTrue())
+	g.Expect(updatePolicyCalled).To(BeFalse())
 
-	// test create resource error
+	// local permission has changed
 	tr = testResource()
-	r.kubeclient = NewSimpleClientset()
-	testError = "test-create-user--error"
-	called := false
-	cl.MockCreateUser = func(username string, spec *S3BucketSpec) (*iam.AccessKey, string, error) {
-		called = true
-		return nil, "", fmt.Errorf(testError)
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateResource, testError)
-
-	rs, err = r._create(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	perm := storagev1alpha1.ReadWritePermission
+	tr.Spec.LocalPermission = &perm
+	_, err = e.Update(ctx, tr)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-
-	// test create bucket error
-	cl.MockCreateUser = func(username string, spec *S3BucketSpec) (*iam.AccessKey, string, error) {
-		fakeKey := &iam.AccessKey{
-			AccessKeyId:     util.String("fake-string"),
-			SecretAccessKey: util.String(""),
-		}
-		return fakeKey, "v2", nil
-	}
-
+	g.Expect(updatePolicyCalled).To(BeTrue())
+	g.Expect(tr.Status.LastUserPolicyVersion).To(Equal(3))
+	g.Expect(tr.Status.LastLocalPermission).To(Equal(perm))
+
+	// acl cannot be updated
+	testError := "test-update-acl-error"
+	cl.MockUpdateBucketACL = func(*S3BucketSpec) error { return fmt.Errorf(testError) }
+	_, err = e.Update(ctx, testResource())
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot update ACL of S3 bucket %s: %s", bucketName, testError)))
+	cl.MockUpdateBucketACL = func(*S3BucketSpec) error { return nil }
+
+	// versioning cannot be updated
+	testError = "test-update-versioning-error"
+	cl.MockUpdateVersioning = func(*S3BucketSpec) error { return fmt.Errorf(testError) }
 	tr = testResource()
-	r.kubeclient = NewSimpleClientset()
-	testError = "test-create-bucket--error"
-	called = false
-	cl.MockCreateOrUpdateBucket = func(spec *S3BucketSpec) error {
-		called = true
-		return fmt.Errorf(testError)
-	}
-
-	expectedStatus = corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorCreateResource, testError)
-
-	rs, err = r._create(tr, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-}
-
-func TestConnect(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	tp := testProvider()
-	tr := testResource()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tp),
-		kubeclient: NewSimpleClientset(),
-	}
-
-	// provider status is not ready
-	c, err := r._connect(tr)
-	g.Expect(c).To(BeNil())
-	g.Expect(err).To(And(Not(BeNil()), MatchError("provider is not ready")))
+	tr.Spec.Versioning = true
+	_, err = e.Update(ctx, tr)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot update versioning of S3 bucket %s: %s", bucketName, testError)))
 
-	// error getting aws config - secret is not found
-	tp.Status.SetReady()
-	r.Client = NewFakeClient(tp)
-	c, err = r._connect(tr)
-	g.Expect(c).To(BeNil())
-	g.Expect(err).To(Not(BeNil()))
+	// policy cannot be updated
+	testError = "test-update-policy-error"
+	cl.MockUpdatePolicyDocument = func(string, *S3BucketSpec) (string, error) { return "", fmt.Errorf(testError) }
+	tr = testResource()
+	tr.Spec.LocalPermission = &perm
+	_, err = e.Update(ctx, tr)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot update policy of IAM user %s: %s", tr.Status.IAMUsername, testError)))
+
+	// bucket info cannot be retrieved
+	testError = "test-get-bucket-info-error"
+	cl.MockGetBucketInfo = func(string, *S3BucketSpec) (*client.Bucket, error) { return nil, fmt.Errorf(testError) }
+	_, err = e.Update(ctx, testResource())
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get S3 bucket %s: %s", bucketName, testError)))
 }
 
-func TestReconcile(t *testing.T) {
+func TestDelete(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tr := testResource()
-	tr.Status.IAMUsername = ""
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tr),
-		kubeclient: NewSimpleClientset(),
-	}
-
-	// test connect error
 	called := false
-	testError := "test-connect-error"
-	r.connect = func(*S3Bucket) (client.Service, error) {
+	cl := &MockS3Client{MockDelete: func(*S3Bucket) error {
 		called = true
-		return nil, fmt.Errorf(testError)
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorResourceClient, testError)
+		return nil
+	}}
+	e := &external{client: cl}
 
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).NotTo(HaveOccurred())
+	// bucket is deleted
+	g.Expect(e.Delete(ctx, testResource())).To(Succeed())
 	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
 
-	// test delete
-	r.connect = func(instance *S3Bucket) (client client.Service, e error) {
-		t := metav1.Now()
-		instance.DeletionTimestamp = &t
-		return nil, nil
-	}
-	called = false
-	r.delete = func(instance *S3Bucket, client client.Service) (i reconcile.Result, e error) {
-		called = true
-		return result, nil
-	}
-	r.Reconcile(request)
-	g.Expect(called).To(BeTrue())
+	// bucket or user is already gone
+	cl.MockDelete = func(*S3Bucket) error { return awserr.New("NoSuchEntity", "not found", nil) }
+	g.Expect(e.Delete(ctx, testResource())).To(Succeed())
 
-	// test create
-	r.connect = func(instance *S3Bucket) (client client.Service, e error) {
-		return nil, nil
-	}
-	called = false
-	r.delete = r._delete
-	r.create = func(instance *S3Bucket, client client.Service) (i reconcile.Result, e error) {
-		called = true
-		return result, nil
-	}
-	r.Reconcile(request)
-	g.Expect(called).To(BeTrue())
-
-	// test sync
-	r.connect = func(instance *S3Bucket) (client client.Service, e error) {
-		instance.Status.IAMUsername = "foo-user"
-		return nil, nil
-	}
-	called = false
-	r.create = r._create
-	r.sync = func(instance *S3Bucket, client client.Service) (i reconcile.Result, e error) {
-		called = true
-		return result, nil
-	}
-	r.Reconcile(request)
-	g.Expect(called).To(BeTrue())
+	// bucket cannot be deleted
+	testError := "test-delete-error"
+	cl.MockDelete = func(*S3Bucket) error { return fmt.Errorf(testError) }
+	g.Expect(e.Delete(ctx, testResource())).To(MatchError(fmt.Sprintf("cannot delete S3 bucket %s: %s", bucketName, testError)))
 }
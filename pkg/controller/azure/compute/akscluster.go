@@ -19,59 +19,41 @@ package compute
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
 	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	azureclients "github.com/crossplaneio/crossplane/pkg/clients/azure"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 const (
 	controllerName = "aks.compute.azure.crossplane.io"
-	finalizer      = "finalizer." + controllerName
 	spSecretFmt    = "%s-service-principal"
 	spSecretKey    = "clientSecret"
 	adAppNameFmt   = "%s-crossplane-aks-app"
 
-	errorClusterClient   = "Failed to create AKS cluster client"
-	errorCreatingCluster = "failed to create cluster"
-	errorSyncingCluster  = "Failed to sync cluster state"
-	errorDeletingCluster = "Failed to delete cluster"
-)
+	// Provisioning states of AKS clusters.
+	stateSucceeded = "Succeeded"
+	stateFailed    = "Failed"
+	stateDeleting  = "Deleting"
 
-var (
-	_             reconcile.Reconciler = &Reconciler{}
-	ctx                                = context.Background()
-	result                             = reconcile.Result{}
-	resultRequeue                      = reconcile.Result{Requeue: true}
+	errNotAKSCluster = "managed resource is not an AKS cluster"
 )
 
-// Reconciler reconciles a AKSCluster object
-type Reconciler struct {
-	client.Client
-	clientset          kubernetes.Interface
-	aksSetupAPIFactory azureclients.AKSSetupAPIFactory
-	scheme             *runtime.Scheme
-}
-
 // AddAKSCluster creates a new AKSCluster Controller and adds it to the Manager with default RBAC.
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func AddAKSCluster(mgr manager.Manager) error {
@@ -80,310 +62,227 @@ func AddAKSCluster(mgr manager.Manager) error {
 		return fmt.Errorf("failed to create clientset: %+v", err)
 	}
 
-	r := newAKSClusterReconciler(mgr, &azureclients.AKSSetupClientFactory{}, clientset)
-	return AddAKSClusterReconciler(mgr, r)
+	return addAKSCluster(mgr, &azureclients.AKSSetupClientFactory{}, clientset)
 }
 
-// newAKSClusterReconciler returns a new reconcile.Reconciler
-func newAKSClusterReconciler(mgr manager.Manager, aksSetupAPIFactory azureclients.AKSSetupAPIFactory,
-	clientset kubernetes.Interface) *Reconciler {
-
-	return &Reconciler{
-		Client:             mgr.GetClient(),
+// addAKSCluster adds a new AKSCluster controller that uses the supplied
+// factory to create AKS setup clients to mgr.
+func addAKSCluster(mgr manager.Manager, factory azureclients.AKSSetupAPIFactory, clientset kubernetes.Interface) error {
+	c := &connecter{
+		kube:               mgr.GetClient(),
 		clientset:          clientset,
-		aksSetupAPIFactory: aksSetupAPIFactory,
-		scheme:             mgr.GetScheme(),
+		aksSetupAPIFactory: factory,
 	}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &computev1alpha1.AKSCluster{} }, c,
+		azureclients.ClassifyError, corev1alpha1.ReclaimDelete)
 }
 
-// AddAKSClusterReconciler adds a new Controller to mgr with r as the reconcile.Reconciler
-func AddAKSClusterReconciler(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New("AKSCluster-controller", mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
+// connecter returns AKS setup clients authenticated using the credentials of
+// the Azure Provider referenced by an AKSCluster.
+type connecter struct {
+	kube               client.Client
+	clientset          kubernetes.Interface
+	aksSetupAPIFactory azureclients.AKSSetupAPIFactory
+}
+
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(*computev1alpha1.AKSCluster)
+	if !ok {
+		return nil, errors.New(errNotAKSCluster)
+	}
+
+	p := &azurev1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
 	}
 
-	// Watch for changes to AKSCluster
-	err = c.Watch(&source.Kind{Type: &computev1alpha1.AKSCluster{}}, &handler.EnqueueRequestForObject{})
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
+	}
+
+	ac, err := c.aksSetupAPIFactory.CreateSetupClient(p, c.clientset)
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "cannot create AKS setup client")
 	}
 
-	return nil
+	return &external{client: ac, clientset: c.clientset}, nil
 }
 
-// Reconcile reads that state of the cluster for a AKSCluster object and makes changes based on the state read
-// and what is in its spec.
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", computev1alpha1.AKSClusterKindAPIVersion, request)
-	// Fetch the CRD instance
-	instance := &computev1alpha1.AKSCluster{}
-	err := r.Get(ctx, request.NamespacedName, instance)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		return reconcile.Result{}, err
+// external manages AKS clusters, along with the AD application and service
+// principal they use for Azure APIs.
+type external struct {
+	client    *azureclients.AKSSetupClient
+	clientset kubernetes.Interface
+}
+
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(*computev1alpha1.AKSCluster)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotAKSCluster)
 	}
 
-	// Create AKS Client
-	aksClient, err := r.connect(instance)
-	if err != nil {
-		return r.fail(instance, errorClusterClient, err.Error())
+	// The cluster is unnamed. Assume it has not been created in Azure.
+	if i.Status.ClusterName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	// Check for deletion
-	if instance.DeletionTimestamp != nil {
-		if instance.Status.Condition(corev1alpha1.Deleting) == nil {
-			// we haven't started the deletion of the AKS cluster yet, do it now
-			log.Printf("AKS cluster %s has been deleted, running finalizer now", instance.Name)
-			return r.delete(instance, aksClient)
+	if i.Status.RunningOperation != "" {
+		// there is a running operation on the instance, wait for it to complete
+		done, err := e.client.AKSClusterAPI.CreateOrUpdateEnd([]byte(i.Status.RunningOperation))
+		if !done {
+			// not done yet, check again on the next reconcile
+			logging.FromContext(ctx).V(logging.Debug).Info("waiting on create of aks cluster")
+			return core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, errors.Wrapf(err, "cannot get create operation of AKS cluster %s", i.Status.ClusterName)
 		}
-		// we already started the deletion of the AKS cluster, nothing more to do
-		return result, nil
-	}
 
-	// Add finalizer
-	if !util.HasFinalizer(&instance.ObjectMeta, finalizer) {
-		util.AddFinalizer(&instance.ObjectMeta, finalizer)
-		if err := r.Update(ctx, instance); err != nil {
-			return resultRequeue, err
+		// the operation is done, clear out the running operation on the CRD status
+		i.Status.RunningOperation = ""
+		if err != nil {
+			return core.ExternalObservation{}, errors.Wrapf(err, "failure result returned from create operation for AKS cluster %s", i.Status.ClusterName)
 		}
 	}
 
-	if instance.Status.RunningOperation != "" {
-		// there is a running operation on the instance, wait for it to complete
-		return r.waitForCompletion(instance, aksClient)
+	cluster, err := e.client.AKSClusterAPI.Get(ctx, *i)
+	if azureclients.IsNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get AKS cluster %s", i.Status.ClusterName)
 	}
 
-	// Create cluster instance
-	if !r.created(instance) {
-		return r.create(instance, aksClient)
+	if cluster.ID != nil {
+		i.Status.ProviderID = *cluster.ID
+	}
+	if cluster.ManagedClusterProperties != nil && cluster.ProvisioningState != nil {
+		i.Status.State = *cluster.ProvisioningState
+	}
+	if cluster.ManagedClusterProperties != nil && cluster.Fqdn != nil {
+		i.Status.Endpoint = *cluster.Fqdn
 	}
 
-	// Sync cluster instance status with cluster status
-	return r.sync(instance, aksClient)
-}
+	if i.Status.State == stateFailed {
+		return core.ExternalObservation{}, requeue.Terminal(errors.Errorf("AKS cluster %s is in failed state", i.Status.ClusterName))
+	}
 
-func (r *Reconciler) connect(instance *computev1alpha1.AKSCluster) (*azureclients.AKSSetupClient, error) {
-	// Fetch Provider
-	p := &azurev1alpha1.Provider{}
-	providerNamespacedName := types.NamespacedName{
-		Namespace: instance.Namespace,
-		Name:      instance.Spec.ProviderRef.Name,
+	o := core.ExternalObservation{
+		ResourceExists:   true,
+		ResourceReady:    i.Status.State == stateSucceeded,
+		ResourceUpToDate: true,
 	}
-	err := r.Get(ctx, providerNamespacedName, p)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get provider: %+v", err)
+	if !o.ResourceReady {
+		return o, nil
 	}
 
-	// Check provider status
-	if !p.IsValid() {
-		return nil, fmt.Errorf("provider status is invalid: %+v", p)
+	cd, err := e.connectionDetails(ctx, i)
+	if err != nil {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get connection details of AKS cluster %s", i.Status.ClusterName)
 	}
-
-	return r.aksSetupAPIFactory.CreateSetupClient(p, r.clientset)
+	o.ConnectionDetails = cd
+	return o, nil
 }
 
-// TODO(negz): This method's cyclomatic complexity is a little high. Consider
-// refactoring to reduce said complexity if you touch it.
-// nolint:gocyclo
-func (r *Reconciler) create(instance *computev1alpha1.AKSCluster, aksClient *azureclients.AKSSetupClient) (reconcile.Result, error) {
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(*computev1alpha1.AKSCluster)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotAKSCluster)
+	}
+
 	// create or fetch the secret for the AD application and its service principal the cluster will use for Azure APIs
-	spSecret, err := r.servicePrincipalSecret(instance)
+	spSecret, err := e.servicePrincipalSecret(i)
 	if err != nil {
-		return r.fail(instance, errorCreatingCluster, fmt.Sprintf("failed to get service principal secret for AKS cluster %s: %+v", instance.Name, err))
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot get service principal secret for AKS cluster %s", i.Name)
 	}
 
 	// create the AD application that the cluster will use for the Azure APIs
 	appParams := azureclients.ApplicationParameters{
-		Name:          fmt.Sprintf(adAppNameFmt, instance.Name),
-		DNSNamePrefix: instance.Spec.DNSNamePrefix,
-		Location:      instance.Spec.Location,
-		ObjectID:      instance.Status.ApplicationObjectID,
+		Name:          fmt.Sprintf(adAppNameFmt, i.Name),
+		DNSNamePrefix: i.Spec.DNSNamePrefix,
+		Location:      i.Spec.Location,
+		ObjectID:      i.Status.ApplicationObjectID,
 		ClientSecret:  spSecret,
 	}
-	log.Printf("starting create of app for AKS cluster %s", instance.Name)
-	app, err := aksClient.ApplicationAPI.CreateApplication(ctx, appParams)
+	logging.FromContext(ctx).Info("starting create of app for aks cluster")
+	app, err := e.client.ApplicationAPI.CreateApplication(ctx, appParams)
 	if err != nil {
-		return r.fail(instance, errorCreatingCluster, fmt.Sprintf("failed to create app for AKS cluster %s: %+v", instance.Name, err))
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create app for AKS cluster %s", i.Name)
 	}
 
-	if instance.Status.ApplicationObjectID == "" {
-		// save the application object ID on the CRD status now
-		instance.Status.ApplicationObjectID = *app.ObjectID
-		// TODO: retry this CRD update upon conflict
-		r.Update(ctx, instance) // nolint:errcheck
+	// save the application object ID on the CRD status now. It is persisted
+	// even if a later step of the create fails.
+	if i.Status.ApplicationObjectID == "" {
+		i.Status.ApplicationObjectID = *app.ObjectID
 	}
 
 	// create the service principal for the AD application
-	log.Printf("starting create of service principal for AKS cluster %s", instance.Name)
-	sp, err := aksClient.ServicePrincipalAPI.CreateServicePrincipal(ctx, instance.Status.ServicePrincipalID, *app.AppID)
+	logging.FromContext(ctx).Info("starting create of service principal for aks cluster")
+	sp, err := e.client.ServicePrincipalAPI.CreateServicePrincipal(ctx, i.Status.ServicePrincipalID, *app.AppID)
 	if err != nil {
-		return r.fail(instance, errorCreatingCluster, fmt.Sprintf("failed to create service principal for AKS cluster %s: %+v", instance.Name, err))
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create service principal for AKS cluster %s", i.Name)
 	}
 
-	if instance.Status.ServicePrincipalID == "" {
-		// save the service principal ID on the CRD status now
-		instance.Status.ServicePrincipalID = *sp.ObjectID
-		// TODO: retry this CRD update upon conflict
-		r.Update(ctx, instance) // nolint:errcheck
+	if i.Status.ServicePrincipalID == "" {
+		i.Status.ServicePrincipalID = *sp.ObjectID
 	}
 
 	// start the creation of the AKS cluster
-	log.Printf("starting create of AKS cluster %s", instance.Name)
-	clusterName := azureclients.SanitizeClusterName(instance.Name)
-	createOp, err := aksClient.AKSClusterAPI.CreateOrUpdateBegin(ctx, *instance, clusterName, *app.AppID, spSecret)
+	logging.FromContext(ctx).Info("starting create of aks cluster")
+	clusterName := azureclients.SanitizeClusterName(i.Name)
+	createOp, err := e.client.AKSClusterAPI.CreateOrUpdateBegin(ctx, *i, clusterName, *app.AppID, spSecret)
 	if err != nil {
-		return r.fail(instance, errorCreatingCluster, fmt.Sprintf("failed to start create operation for AKS cluster %s: %+v", instance.Name, err))
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot start create operation for AKS cluster %s", i.Name)
 	}
 
-	log.Printf("started create of AKS cluster %s, operation: %s", instance.Name, string(createOp))
+	logging.FromContext(ctx).Info("started create of aks cluster", "operation", string(createOp))
 
 	// save the create operation to the CRD status
-	instance.Status.RunningOperation = string(createOp)
-
-	// set the creating/provisioning state to the CRD status
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetCreating()
-	instance.Status.ClusterName = clusterName
-
-	// wait until the important status fields we just set have become committed/consistent
-	updateWaitErr := wait.ExponentialBackoff(util.DefaultUpdateRetry, func() (done bool, err error) {
-		if err := r.Update(ctx, instance); err != nil {
-			return false, nil
-		}
-
-		// the update went through, let's do a get to verify the fields are committed/consistent
-		fetchedInstance := &computev1alpha1.AKSCluster{}
-		if err := r.Get(ctx, types.NamespacedName{Name: instance.Name, Namespace: instance.Namespace}, fetchedInstance); err != nil {
-			return false, nil
-		}
-
-		if fetchedInstance.Status.RunningOperation != "" && fetchedInstance.Status.ClusterName != "" {
-			// both the running operation field and the cluster name field have been committed, we can stop retrying
-			return true, nil
-		}
-
-		// the instance hasn't reached consistency yet, retry
-		log.Printf("AKS cluster %s hasn't reached consistency yet, retrying", instance.Name)
-		return false, nil
-	})
-
-	return resultRequeue, updateWaitErr
-}
-
-func (r *Reconciler) waitForCompletion(instance *computev1alpha1.AKSCluster, aksClient *azureclients.AKSSetupClient) (reconcile.Result, error) {
-	// check if the operation is done yet and if there was any error
-	done, err := aksClient.AKSClusterAPI.CreateOrUpdateEnd([]byte(instance.Status.RunningOperation))
-	if !done {
-		// not done yet, check again on the next reconcile
-		log.Printf("waiting on create of AKS cluster %s, err: %+v", instance.Name, err)
-		return resultRequeue, err
-	}
-
-	// the operation is done, clear out the running operation on the CRD status
-	instance.Status.RunningOperation = ""
-
-	if err != nil {
-		// the operation completed, but there was an error
-		return r.fail(instance, errorCreatingCluster, fmt.Sprintf("failure result returned from create operation for AKS cluster %s: %+v", instance.Name, err))
-	}
-
-	log.Printf("AKS cluster %s successfully created", instance.Name)
-	return resultRequeue, r.Update(ctx, instance)
+	i.Status.RunningOperation = string(createOp)
+	i.Status.ClusterName = clusterName
+	return core.ExternalCreation{}, nil
 }
 
-func (r *Reconciler) created(instance *computev1alpha1.AKSCluster) bool {
-	return instance.Status.ClusterName != ""
+// Update does nothing, because AKS clusters are never out of date.
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	return core.ExternalUpdate{}, nil
 }
 
-func (r *Reconciler) sync(instance *computev1alpha1.AKSCluster, aksClient *azureclients.AKSSetupClient) (reconcile.Result, error) {
-	cluster, err := aksClient.AKSClusterAPI.Get(ctx, *instance)
-	if err != nil {
-		return r.fail(instance, errorSyncingCluster, err.Error())
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(*computev1alpha1.AKSCluster)
+	if !ok {
+		return errors.New(errNotAKSCluster)
 	}
 
-	secret, err := r.connectionSecret(instance, aksClient)
-	if err != nil {
-		return r.fail(instance, errorSyncingCluster, err.Error())
+	// we already started the deletion of the AKS cluster, nothing more to do
+	if i.Status.State == stateDeleting {
+		return nil
 	}
 
-	if _, err := util.ApplySecret(r.clientset, secret); err != nil {
-		return r.fail(instance, errorSyncingCluster, err.Error())
+	logging.FromContext(ctx).Info("deleting aks cluster")
+	if _, err := e.client.AKSClusterAPI.Delete(ctx, *i); err != nil && !azureclients.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete AKS cluster %s", i.Name)
 	}
 
-	// update resource status
-	if cluster.ID != nil {
-		instance.Status.ProviderID = *cluster.ID
-	}
-	if cluster.ProvisioningState != nil {
-		instance.Status.State = *cluster.ProvisioningState
-	}
-	if cluster.Fqdn != nil {
-		instance.Status.Endpoint = *cluster.Fqdn
+	logging.FromContext(ctx).Info("deleting service principal for aks cluster")
+	err := e.client.ServicePrincipalAPI.DeleteServicePrincipal(ctx, i.Status.ServicePrincipalID)
+	if err != nil && !azureclients.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete service principal for AKS cluster %s", i.Name)
 	}
 
-	if !instance.Status.IsReady() {
-		instance.Status.UnsetAllConditions()
-		instance.Status.SetReady()
+	logging.FromContext(ctx).Info("deleting app for aks cluster")
+	err = e.client.ApplicationAPI.DeleteApplication(ctx, i.Status.ApplicationObjectID)
+	if err != nil && !azureclients.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot delete app for AKS cluster %s", i.Name)
 	}
 
-	return result, r.Update(ctx, instance)
-}
-
-// delete performs a deletion of the AKS cluster if needed
-func (r *Reconciler) delete(instance *computev1alpha1.AKSCluster, aksClient *azureclients.AKSSetupClient) (reconcile.Result, error) {
-	if instance.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		// delete the AKS cluster
-		log.Printf("deleting AKS cluster %s", instance.Name)
-		deleteFuture, err := aksClient.AKSClusterAPI.Delete(ctx, *instance)
-		if err != nil && !azureclients.IsNotFound(err) {
-			return r.fail(instance, errorDeletingCluster, fmt.Sprintf("failed to delete AKS cluster %s: %+v", instance.Name, err))
-		}
-		deleteFutureJSON, _ := deleteFuture.MarshalJSON()
-		log.Printf("started delete of AKS cluster %s, operation: %s", instance.Name, string(deleteFutureJSON))
-
-		// delete the service principal
-		log.Printf("deleting service principal for AKS cluster %s", instance.Name)
-		err = aksClient.ServicePrincipalAPI.DeleteServicePrincipal(ctx, instance.Status.ServicePrincipalID)
-		if err != nil && !azureclients.IsNotFound(err) {
-			return r.fail(instance, errorDeletingCluster, fmt.Sprintf("failed to service principal: %+v", err))
-		}
-
-		// delete the AD application
-		log.Printf("deleting app for AKS cluster %s", instance.Name)
-		err = aksClient.ApplicationAPI.DeleteApplication(ctx, instance.Status.ApplicationObjectID)
-		if err != nil && !azureclients.IsNotFound(err) {
-			return r.fail(instance, errorDeletingCluster, fmt.Sprintf("failed to AD application: %+v", err))
-		}
-
-		log.Printf("all resources deleted for AKS cluster %s", instance.Name)
-	}
-
-	util.RemoveFinalizer(&instance.ObjectMeta, finalizer)
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetDeleting()
-	return result, r.Update(ctx, instance)
-}
-
-// fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *computev1alpha1.AKSCluster, reason, msg string) (reconcile.Result, error) {
-	log.Printf("%s: %s", reason, msg)
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetFailed(reason, msg)
-	return resultRequeue, r.Update(ctx, instance)
+	i.Status.State = stateDeleting
+	return nil
 }
 
-func (r *Reconciler) servicePrincipalSecret(instance *computev1alpha1.AKSCluster) (string, error) {
+func (e *external) servicePrincipalSecret(instance *computev1alpha1.AKSCluster) (string, error) {
 	// check to see if the secret already exists
 	secretName := fmt.Sprintf(spSecretFmt, instance.Name)
 	selector := v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: secretName}, Key: spSecretKey}
-	spSecretValue, err := util.SecretData(r.clientset, instance.Namespace, selector)
+	spSecretValue, err := util.SecretData(e.clientset, instance.Namespace, selector)
 	if err == nil {
 		return string(spSecretValue), nil
 	}
@@ -404,15 +303,15 @@ func (r *Reconciler) servicePrincipalSecret(instance *computev1alpha1.AKSCluster
 		Data: map[string][]byte{spSecretKey: []byte(newSPSecretValue.String())},
 	}
 
-	if _, err := r.clientset.CoreV1().Secrets(instance.Namespace).Create(spSecret); err != nil {
+	if _, err := e.clientset.CoreV1().Secrets(instance.Namespace).Create(spSecret); err != nil {
 		return "", fmt.Errorf("failed to create service principal secret: %+v", err)
 	}
 
 	return newSPSecretValue.String(), nil
 }
 
-func (r *Reconciler) connectionSecret(instance *computev1alpha1.AKSCluster, client *azureclients.AKSSetupClient) (*v1.Secret, error) {
-	creds, err := client.ListClusterAdminCredentials(ctx, *instance)
+func (e *external) connectionDetails(ctx context.Context, instance *computev1alpha1.AKSCluster) (core.ConnectionDetails, error) {
+	creds, err := e.client.ListClusterAdminCredentials(ctx, *instance)
 	if err != nil {
 		return nil, err
 	}
@@ -443,12 +342,10 @@ func (r *Reconciler) connectionSecret(instance *computev1alpha1.AKSCluster, clie
 		return nil, fmt.Errorf("auth-info configuration is not found: %s", kctx.AuthInfo)
 	}
 
-	secret := instance.ConnectionSecret()
-	secret.Data = map[string][]byte{
+	return core.ConnectionDetails{
 		corev1alpha1.ResourceCredentialsSecretEndpointKey:   []byte(cluster.Server),
 		corev1alpha1.ResourceCredentialsSecretCAKey:         cluster.CertificateAuthorityData,
 		corev1alpha1.ResourceCredentialsSecretClientCertKey: auth.ClientCertificateData,
 		corev1alpha1.ResourceCredentialsSecretClientKeyKey:  auth.ClientKeyData,
-	}
-	return secret, nil
+	}, nil
 }
@@ -19,21 +19,24 @@ package compute
 import (
 	"context"
 	"log"
+	"net/http"
+	"sync/atomic"
 	"testing"
 
 	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2018-03-31/containerservice"
 	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
@@ -136,6 +139,10 @@ func TestReconcile(t *testing.T) {
 	mockAKSSetupClient := &mockAKSSetupClient{}
 	mockAKSSetupClientFactory := &mockAKSSetupClientFactory{mockClient: mockAKSSetupClient}
 
+	// the create operation is not done until created is set, and the cluster
+	// is not found once deleted is set
+	var created, deleted int32
+
 	// setup all the mocked functions for the AKS setup client
 	mockAKSSetupClient.MockCreateApplication = func(ctx context.Context, appParams azureclients.ApplicationParameters) (*graphrbac.Application, error) {
 		return &graphrbac.Application{
@@ -151,7 +158,13 @@ func TestReconcile(t *testing.T) {
 	mockAKSSetupClient.MockCreateOrUpdateBegin = func(ctx context.Context, instance computev1alpha1.AKSCluster, clusterName, appID, spSecret string) ([]byte, error) {
 		return []byte("mocked marshalled create future"), nil
 	}
+	mockAKSSetupClient.MockCreateOrUpdateEnd = func(op []byte) (bool, error) {
+		return atomic.LoadInt32(&created) == 1, nil
+	}
 	mockAKSSetupClient.MockGet = func(ctx context.Context, instance computev1alpha1.AKSCluster) (containerservice.ManagedCluster, error) {
+		if atomic.LoadInt32(&deleted) == 1 {
+			return containerservice.ManagedCluster{}, autorest.DetailedError{StatusCode: http.StatusNotFound}
+		}
 		return containerservice.ManagedCluster{
 			ID: to.StringPtr("fcb4e97a-c3ea-4466-9b02-e728d8e6764f"),
 			ManagedClusterProperties: &containerservice.ManagedClusterProperties{
@@ -160,21 +173,22 @@ func TestReconcile(t *testing.T) {
 			},
 		}, nil
 	}
+	mockAKSSetupClient.MockDelete = func(ctx context.Context, instance computev1alpha1.AKSCluster) (containerservice.ManagedClustersDeleteFuture, error) {
+		atomic.StoreInt32(&deleted, 1)
+		return containerservice.ManagedClustersDeleteFuture{}, nil
+	}
 	mockAKSSetupClient.MockListClusterAdminCredentials = func(ctx context.Context, instance computev1alpha1.AKSCluster) (containerservice.CredentialResults, error) {
 		return containerservice.CredentialResults{
 			Kubeconfigs: &[]containerservice.CredentialResult{{Value: &kubecfg}},
 		}, nil
 	}
 
-	// Setup the Manager and Controller.  Wrap the Controller Reconcile function so it writes each request to a
-	// channel when it is finished.
+	// Setup the Manager and Controller.
 	mgr, err := manager.New(cfg, manager.Options{})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	c := mgr.GetClient()
 
-	r := newAKSClusterReconciler(mgr, mockAKSSetupClientFactory, clientset)
-	recFn, requests := SetupTestReconcile(r)
-	g.Expect(AddAKSClusterReconciler(mgr, recFn)).NotTo(gomega.HaveOccurred())
+	g.Expect(addAKSCluster(mgr, mockAKSSetupClientFactory, clientset)).NotTo(gomega.HaveOccurred())
 	defer close(StartTestManager(mgr, g))
 
 	// create the provider object and defer its cleanup
@@ -189,13 +203,10 @@ func TestReconcile(t *testing.T) {
 	instance := testInstance(provider)
 	err = c.Create(ctx, instance)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
-	defer cleanupAKSCluster(g, c, requests, instance)
-
-	// first reconcile loop should start the create operation
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
+	defer cleanupAKSCluster(g, c, instance)
 
-	// after the first reconcile, the create operation should be saved on the running operation field,
-	// and the following should be set:
+	// the first reconcile should start the create operation and save it on
+	// the running operation field, along with the following:
 	// 1) cluster name
 	// 2) application object ID
 	// 3) service principal ID
@@ -214,38 +225,21 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 	}
+	g.Eventually(func() string { return aksCluster(c).Status.RunningOperation }, timeout).Should(gomega.Equal(expectedStatus.RunningOperation))
 	assertAKSClusterStatus(g, c, expectedStatus)
 
 	// the service principal secret (note this is not the connection secret) should have been created
-	spSecret, err := r.clientset.CoreV1().Secrets(namespace).Get("test-compute-instance-service-principal", metav1.GetOptions{})
+	spSecret, err := clientset.CoreV1().Secrets(namespace).Get("test-compute-instance-service-principal", metav1.GetOptions{})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	spSecretValue, ok := spSecret.Data[spSecretKey]
 	g.Expect(ok).To(gomega.BeTrue())
 	g.Expect(spSecretValue).ToNot(gomega.BeEmpty())
 
-	// second reconcile should finish the create operation and clear out the running operation field
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-	expectedStatus = computev1alpha1.AKSClusterStatus{
-		RunningOperation:    "",
-		ClusterName:         instanceName,
-		ApplicationObjectID: "182f8c4a-ad89-4b25-b947-d4026ab183a1",
-		ServicePrincipalID:  "da804153-3faa-4c73-9fcb-0961387a31f9",
-		ConditionedStatus: corev1alpha1.ConditionedStatus{
-			Conditions: []corev1alpha1.Condition{
-				{
-					Type:   corev1alpha1.Creating,
-					Status: v1.ConditionTrue,
-				},
-			},
-		},
-	}
-	assertAKSClusterStatus(g, c, expectedStatus)
-
-	// third reconcile should find the AKS cluster instance from Azure and update the full status of the CRD
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-
-	// verify that the CRD status was updated with details about the external AKS cluster and that the
-	// CRD conditions show the transition from creating to running
+	// once the create operation is done, the running operation field should
+	// be cleared and the status updated with details about the external AKS
+	// cluster. The conditions should show the transition from creating to
+	// ready.
+	atomic.StoreInt32(&created, 1)
 	expectedStatus = computev1alpha1.AKSClusterStatus{
 		ClusterName:         instanceName,
 		State:               "Succeeded",
@@ -266,29 +260,28 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 	}
+	g.Eventually(func() bool { return aksCluster(c).Status.IsReady() }, timeout).Should(gomega.BeTrue())
 	assertAKSClusterStatus(g, c, expectedStatus)
 
-	// wait for the connection information to be stored in a secret, then verify it
-	var connectionSecret *v1.Secret
-	for {
-		if connectionSecret, err = r.clientset.CoreV1().Secrets(namespace).Get(instanceName, metav1.GetOptions{}); err == nil {
-			if string(connectionSecret.Data[corev1alpha1.ResourceCredentialsSecretEndpointKey]) != "" {
-				break
-			}
-		}
-	}
-	assertConnectionSecret(g, c, connectionSecret)
+	// the connection information should have been stored in a secret
+	connectionSecret := &v1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: instanceName}, connectionSecret)).To(gomega.Succeed())
+	assertConnectionSecret(g, connectionSecret)
 
 	// verify that a finalizer was added to the CRD
-	c.Get(ctx, expectedRequest.NamespacedName, instance)
-	g.Expect(len(instance.Finalizers)).To(gomega.Equal(1))
-	g.Expect(instance.Finalizers[0]).To(gomega.Equal(finalizer))
+	g.Expect(aksCluster(c).Finalizers).To(gomega.Equal([]string{"finalizer." + controllerName}))
 
 	// test deletion of the instance
-	cleanupAKSCluster(g, c, requests, instance)
+	cleanupAKSCluster(g, c, instance)
+}
+
+func aksCluster(c client.Client) *computev1alpha1.AKSCluster {
+	instance := &computev1alpha1.AKSCluster{}
+	c.Get(ctx, expectedRequest.NamespacedName, instance)
+	return instance
 }
 
-func cleanupAKSCluster(g *gomega.GomegaWithT, c client.Client, requests chan reconcile.Request, instance *computev1alpha1.AKSCluster) {
+func cleanupAKSCluster(g *gomega.GomegaWithT, c client.Client, instance *computev1alpha1.AKSCluster) {
 	deletedInstance := &computev1alpha1.AKSCluster{}
 	if err := c.Get(ctx, expectedRequest.NamespacedName, deletedInstance); errors.IsNotFound(err) {
 		// instance has already been deleted, bail out
@@ -299,20 +292,6 @@ func cleanupAKSCluster(g *gomega.GomegaWithT, c client.Client, requests chan rec
 	err := c.Delete(ctx, instance)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
-	// wait for the deletion timestamp to be set
-	err = wait.ExponentialBackoff(test.DefaultRetry, func() (done bool, err error) {
-		deletedInstance := &computev1alpha1.AKSCluster{}
-		c.Get(ctx, expectedRequest.NamespacedName, deletedInstance)
-		if deletedInstance.DeletionTimestamp != nil {
-			return true, nil
-		}
-		return false, nil
-	})
-	g.Expect(err).NotTo(gomega.HaveOccurred())
-
-	// wait for the reconcile to happen that handles the CRD deletion
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-
 	// wait for the finalizer to run and the instance to be deleted for good
 	err = wait.ExponentialBackoff(test.DefaultRetry, func() (done bool, err error) {
 		deletedInstance := &computev1alpha1.AKSCluster{}
@@ -342,10 +321,7 @@ func assertAKSClusterStatus(g *gomega.GomegaWithT, c client.Client, expectedStat
 	corev1alpha1.AssertConditions(g, expectedStatus.Conditions, instance.Status.ConditionedStatus)
 }
 
-func assertConnectionSecret(g *gomega.GomegaWithT, c client.Client, connectionSecret *v1.Secret) {
-	instance := &computev1alpha1.AKSCluster{}
-	err := c.Get(ctx, expectedRequest.NamespacedName, instance)
-	g.Expect(err).NotTo(gomega.HaveOccurred())
+func assertConnectionSecret(g *gomega.GomegaWithT, connectionSecret *v1.Secret) {
 	g.Expect(connectionSecret.Data).Should(gomega.Equal(map[string][]byte{
 		corev1alpha1.ResourceCredentialsSecretEndpointKey:   []byte(clientEndpoint),
 		corev1alpha1.ResourceCredentialsSecretCAKey:         []byte(clientCAdata),
@@ -17,6 +17,7 @@ limitations under the License.
 package compute
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"testing"
@@ -36,6 +37,7 @@ import (
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
 	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -77,6 +79,7 @@ preferences: {}
 `
 
 var (
+	ctx             = context.Background()
 	cfg             *rest.Config
 	expectedRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: instanceName, Namespace: namespace}}
 
@@ -92,24 +95,15 @@ var (
 func TestMain(m *testing.M) {
 	azure.AddToScheme(scheme.Scheme)
 
+	// poll resources that are being created or deleted without delay
+	requeue.Defaults.PollInterval = 100 * time.Millisecond
+
 	t := test.NewEnv(namespace, test.CRDs())
 	cfg = t.Start()
 	t.StopAndExit(m.Run())
 }
 
-// SetupTestReconcile returns a reconcile.Reconcile implementation that delegates to inner and
-// writes the request to requests after Reconcile is finished.
-func SetupTestReconcile(inner reconcile.Reconciler) (reconcile.Reconciler, chan reconcile.Request) {
-	requests := make(chan reconcile.Request)
-	fn := reconcile.Func(func(req reconcile.Request) (reconcile.Result, error) {
-		result, err := inner.Reconcile(req)
-		requests <- req
-		return result, err
-	})
-	return fn, requests
-}
-
-// StartTestManager adds recFn
+// StartTestManager starts the supplied manager
 func StartTestManager(mgr manager.Manager, g *gomega.GomegaWithT) chan struct{} {
 	stop := make(chan struct{})
 	go func() {
@@ -23,28 +23,23 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/controller/azure/compute"
 	"github.com/crossplaneio/crossplane/pkg/controller/azure/database"
 	"github.com/crossplaneio/crossplane/pkg/controller/azure/provider"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
-	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
-	AddToManagerFuncs = append(AddToManagerFuncs,
-		provider.Add,
-		cache.Add,
-		database.AddMysqlServer,
-		database.AddPostgreSQLServer,
-		compute.AddAKSCluster,
+	Controllers = append(Controllers,
+		selection.Controller{Provider: selection.ProviderAzure, Add: provider.Add},
+		selection.Controller{Provider: selection.ProviderAzure, Kind: selection.KindCache, Add: cache.Add},
+		selection.Controller{Provider: selection.ProviderAzure, Kind: selection.KindDatabase, Add: database.AddMysqlServer},
+		selection.Controller{Provider: selection.ProviderAzure, Kind: selection.KindDatabase, Add: database.AddPostgreSQLServer},
+		selection.Controller{Provider: selection.ProviderAzure, Kind: selection.KindCompute, Add: compute.AddAKSCluster},
 	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
-
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
-			return err
-		}
-	}
-	return nil
+// Controllers are the controllers of Azure resources
+var Controllers []selection.Controller
+
+// AddToManager adds the selected Controllers to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
+	return sel.AddToManager(m, Controllers)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package database
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -32,6 +33,8 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/apis/azure"
 	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
 	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -45,6 +48,7 @@ const (
 )
 
 var (
+	ctx             = context.Background()
 	cfg             *rest.Config
 	expectedRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: instanceName, Namespace: namespace}}
 )
@@ -52,24 +56,15 @@ var (
 func TestMain(m *testing.M) {
 	azure.AddToScheme(scheme.Scheme)
 
+	// poll resources that are being created or deleted without delay
+	requeue.Defaults.PollInterval = 100 * time.Millisecond
+
 	t := test.NewEnv(namespace, test.CRDs())
 	cfg = t.Start()
 	t.StopAndExit(m.Run())
 }
 
-// SetupTestReconcile returns a reconcile.Reconcile implementation that delegates to inner and
-// writes the request to requests after Reconcile is finished.
-func SetupTestReconcile(inner reconcile.Reconciler) (reconcile.Reconciler, chan reconcile.Request) {
-	requests := make(chan reconcile.Request)
-	fn := reconcile.Func(func(req reconcile.Request) (reconcile.Result, error) {
-		result, err := inner.Reconcile(req)
-		requests <- req
-		return result, err
-	})
-	return fn, requests
-}
-
-// StartTestManager adds recFn
+// StartTestManager starts the supplied manager
 func StartTestManager(mgr manager.Manager, g *gomega.GomegaWithT) chan struct{} {
 	stop := make(chan struct{})
 	go func() {
@@ -109,6 +104,7 @@ func testInstance(p *azurev1alpha1.Provider) *azuredbv1alpha1.MysqlServer {
 	return &azuredbv1alpha1.MysqlServer{
 		ObjectMeta: metav1.ObjectMeta{Name: instanceName, Namespace: namespace},
 		Spec: azuredbv1alpha1.SQLServerSpec{
+			ReclaimPolicy:  corev1alpha1.ReclaimDelete,
 			ProviderRef:    corev1.LocalObjectReference{Name: p.Name},
 			AdminLoginName: "myadmin",
 			PricingTier: azuredbv1alpha1.PricingTierSpec{
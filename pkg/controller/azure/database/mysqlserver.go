@@ -18,23 +18,18 @@ package database
 
 import (
 	"fmt"
-	"log"
 
-	"k8s.io/apimachinery/pkg/api/errors"
-	apitypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	azureclients "github.com/crossplaneio/crossplane/pkg/clients/azure"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 const (
-	mysqlFinalizer = "finalizer.mysqlservers.database.azure.crossplane.io"
+	mysqlControllerName = "mysqlservers.database.azure.crossplane.io"
 )
 
 // AddMysqlServer creates a new MysqlServer Controller and adds it to the Manager with default RBAC.
@@ -45,77 +40,17 @@ func AddMysqlServer(mgr manager.Manager) error {
 		return fmt.Errorf("failed to create clientset: %+v", err)
 	}
 
-	r := newMysqlServerReconciler(mgr, &azureclients.MySQLServerClientFactory{}, clientset)
-	return addMysqlServerReconciler(mgr, r)
+	return addMysqlServer(mgr, &azureclients.MySQLServerClientFactory{}, clientset)
 }
 
-// newMysqlServerReconciler returns a new reconcile.Reconciler
-func newMysqlServerReconciler(mgr manager.Manager, sqlServerAPIFactory azureclients.SQLServerAPIFactory,
-	clientset kubernetes.Interface) *MySQLReconciler {
-
-	r := &MySQLReconciler{}
-	r.SQLReconciler = &SQLReconciler{
-		Client:              mgr.GetClient(),
+// addMysqlServer adds a new MysqlServer controller that uses the supplied factory to
+// create SQL server clients to mgr.
+func addMysqlServer(mgr manager.Manager, factory azureclients.SQLServerAPIFactory, clientset kubernetes.Interface) error {
+	c := &connecter{
+		kube:                mgr.GetClient(),
 		clientset:           clientset,
-		sqlServerAPIFactory: sqlServerAPIFactory,
-		findInstance:        r.findMySQLInstance,
-		scheme:              mgr.GetScheme(),
-		finalizer:           mysqlFinalizer,
-	}
-
-	return r
-}
-
-// addMysqlServerReconciler adds a new Controller to mgr with r as the reconcile.Reconciler
-func addMysqlServerReconciler(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New("MysqlServer-controller", mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
-	}
-
-	// Watch for changes to MysqlServer
-	err = c.Watch(&source.Kind{Type: &azuredbv1alpha1.MysqlServer{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// MySQLReconciler reconciles a MysqlServer object
-type MySQLReconciler struct {
-	*SQLReconciler
-}
-
-// Reconcile reads that state of the cluster for a MysqlServer object and makes changes based on the state read
-// and what is in the MysqlServer.Spec
-func (r *MySQLReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", azuredbv1alpha1.MysqlServerKindAPIVersion, request)
-	instance := &azuredbv1alpha1.MysqlServer{}
-
-	// Fetch the MysqlServer instance
-	err := r.Get(ctx, request.NamespacedName, instance)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		log.Printf("failed to get object at start of reconcile loop: %+v", err)
-		return reconcile.Result{}, err
+		sqlServerAPIFactory: factory,
 	}
-
-	return r.SQLReconciler.handleReconcile(instance)
-}
-
-func (r *MySQLReconciler) findMySQLInstance(instance azuredbv1alpha1.SQLServer) (azuredbv1alpha1.SQLServer, error) {
-	fetchedInstance := &azuredbv1alpha1.MysqlServer{}
-	namespacedName := apitypes.NamespacedName{Name: instance.GetName(), Namespace: instance.GetNamespace()}
-	if err := r.Get(ctx, namespacedName, fetchedInstance); err != nil {
-		return nil, err
-	}
-
-	return fetchedInstance, nil
+	return core.AddManaged(mgr, mysqlControllerName, func() core.Managed { return &azuredbv1alpha1.MysqlServer{} }, c,
+		azureclients.ClassifyError, corev1alpha1.ReclaimDelete, corev1alpha1.ReclaimSnapshot)
 }
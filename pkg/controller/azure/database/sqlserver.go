@@ -19,22 +19,19 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
-
-	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	apitypes "k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
 	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	azureclients "github.com/crossplaneio/crossplane/pkg/clients/azure"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/logging"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
@@ -42,391 +39,258 @@ const (
 	passwordDataLen  = 20
 	firewallRuleName = "crossplane-sql-firewall-rule"
 
-	errorFetchingAzureProvider   = "failed to fetch Azure Provider"
-	errorCreatingClient          = "Failed to create Azure client"
-	errorFetchingInstance        = "failed to fetch instance"
-	errorDeletingInstance        = "failed to delete instance"
-	errorCreatingInstance        = "failed to create instance"
-	errorCreatingPassword        = "failed to create password"
-	errorSettingConnectionSecret = "failed to set connection secret"
-	conditionStateChanged        = "instance state changed"
-)
+	// States of SQL servers.
+	stateReady    = "Ready"
+	stateDropping = "Dropping"
 
-var (
-	ctx = context.TODO()
+	errNotSQLServer = "managed resource is not a SQL server"
 )
 
-// SQLReconciler reconciles SQL resource specs with Azure.
-type SQLReconciler struct {
-	client.Client
+// connecter returns SQL server clients authenticated using the credentials
+// of the Azure Provider referenced by a SQL server.
+type connecter struct {
+	kube                client.Client
 	clientset           kubernetes.Interface
 	sqlServerAPIFactory azureclients.SQLServerAPIFactory
-	findInstance        func(instance azuredbv1alpha1.SQLServer) (azuredbv1alpha1.SQLServer, error)
-	scheme              *runtime.Scheme
-	finalizer           string
 }
 
-// TODO(negz): This method's cyclomatic complexity is very high. Consider
-// refactoring it if you touch it.
-// nolint:gocyclo
-func (r *SQLReconciler) handleReconcile(instance azuredbv1alpha1.SQLServer) (reconcile.Result, error) {
-
-	// look up the provider information for this instance
-	provider := &azurev1alpha1.Provider{}
-	providerNamespacedName := apitypes.NamespacedName{
-		Namespace: instance.GetNamespace(),
-		Name:      instance.GetSpec().ProviderRef.Name,
-	}
-	if err := r.Get(ctx, providerNamespacedName, provider); err != nil {
-		return r.fail(instance, errorFetchingAzureProvider, fmt.Sprintf("failed to get provider %+v: %+v", providerNamespacedName, err))
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(azuredbv1alpha1.SQLServer)
+	if !ok {
+		return nil, errors.New(errNotSQLServer)
 	}
 
-	// create a SQL Server client to perform management operations in Azure with
-	sqlServersClient, err := r.sqlServerAPIFactory.CreateAPIInstance(provider, r.clientset)
-	if err != nil {
-		return r.fail(instance, errorCreatingClient, fmt.Sprintf("failed to create SQL Server client for instance %s: %+v", instance.GetName(), err))
+	p := &azurev1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.GetSpec().ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
 	}
 
-	// check for CRD deletion and handle it if needed
-	if instance.GetDeletionTimestamp() != nil {
-		if instance.GetStatus().Condition(corev1alpha1.Deleting) == nil {
-			// we haven't started the deletion of the SQL Server resource yet, do it now
-			log.Printf("sql server instance %s has been deleted, running finalizer now", instance.GetName())
-			return r.handleDeletion(sqlServersClient, instance)
-		}
-		// we already started the deletion of the SQL Server resource, nothing more to do
-		return reconcile.Result{}, nil
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
 	}
 
-	// Add finalizer to the CRD if it doesn't already exist
-	if !util.HasFinalizer(instance, r.finalizer) {
-		util.AddFinalizer(instance, r.finalizer)
-		if err := r.Update(ctx, instance); err != nil {
-			log.Printf("failed to add finalizer to instance %s: %+v", instance.GetName(), err)
-			return reconcile.Result{}, err
-		}
+	sc, err := c.sqlServerAPIFactory.CreateAPIInstance(p, c.clientset)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create SQL server client")
 	}
 
-	if instance.GetStatus().RunningOperation != "" {
-		// there is a running operation on the instance, wait for it to complete
-		return r.handleRunningOperation(sqlServersClient, instance)
-	}
+	return &external{client: sc, now: time.Now}, nil
+}
 
-	// Get latest SQL Server instance from Azure to check the latest status
-	server, err := sqlServersClient.GetServer(ctx, instance)
-	if err != nil {
-		if !azureclients.IsNotFound(err) {
-			return r.fail(instance, errorFetchingInstance, fmt.Sprintf("failed to get SQL Server instance %s: %+v", instance.GetName(), err))
-		}
+// external manages Azure SQL servers and their firewall rules. MySQL and
+// PostgreSQL servers are both managed using the generic SQL server API.
+type external struct {
+	client azureclients.SQLServerAPI
+	now    func() time.Time
+}
 
-		// the given sql server instance does not exist, create it now
-		return r.handleCreation(sqlServersClient, instance)
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(azuredbv1alpha1.SQLServer)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotSQLServer)
 	}
+	status := i.GetStatus()
 
-	if err := sqlServersClient.GetFirewallRule(ctx, instance, firewallRuleName); err != nil {
-		if !azureclients.IsNotFound(err) {
-			return r.fail(instance, errorFetchingInstance, fmt.Sprintf("failed to get firewall rule for SQL Server instance %s: %+v", instance.GetName(), err))
+	// The final snapshot operation is the concern of Delete.
+	if status.RunningOperation != "" && status.RunningOperationType != azuredbv1alpha1.OperationCreateFinalSnapshot {
+		// there is a running operation on the instance, wait for it to complete
+		done, err := e.endRunningOperation(i)
+		if !done {
+			// not done yet, check again on the next reconcile
+			logging.FromContext(ctx).V(logging.Debug).Info("waiting on operation for sql server instance", "operationType", status.RunningOperationType)
+			return core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, errors.Wrapf(err, "cannot get %s operation of SQL server %s", status.RunningOperationType, i.GetName())
 		}
 
-		return r.handleFirewallRuleCreation(sqlServersClient, instance)
+		// the operation is done, clear out the running operation on the CRD status
+		logging.FromContext(ctx).Info("finished operation for sql server instance", "operationType", status.RunningOperationType)
+		opType := status.RunningOperationType
+		status.RunningOperation = ""
+		status.RunningOperationType = ""
+		if err != nil {
+			return core.ExternalObservation{}, errors.Wrapf(err, "failure result returned from %s operation for SQL server %s", opType, i.GetName())
+		}
 	}
 
-	// SQL Server instance exists, update the CRD status now with its latest status
-	stateChanged := instance.GetStatus().State != server.State
-	conditionType := azureclients.SQLServerConditionType(server.State)
-	if err := r.updateStatus(instance, azureclients.SQLServerStatusMessage(instance.GetName(), server.State), server); err != nil {
-		// updating the CRD status failed, return the error and try the next reconcile loop
-		log.Printf("failed to update status of instance %s: %+v", instance.GetName(), err)
-		return reconcile.Result{}, err
+	server, err := e.client.GetServer(ctx, i)
+	if azureclients.IsNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get SQL server %s", i.GetName())
 	}
 
-	if stateChanged {
-		// the state of the instance has changed, let's set a corresponding condition on the CRD and then
-		// requeue another reconciliation attempt
-		if conditionType == corev1alpha1.Ready {
-			// when we hit the running condition, clear out all old conditions first
-			instance.GetStatus().UnsetAllConditions()
-		}
+	status.Message = azureclients.SQLServerStatusMessage(i.GetName(), server.State)
+	status.State = server.State
+	status.ProviderID = server.ID
+	status.Endpoint = server.FQDN
 
-		conditionMessage := fmt.Sprintf("SQL Server instance %s is in the %s state", instance.GetName(), conditionType)
-		log.Print(conditionMessage)
-		instance.GetStatus().SetCondition(corev1alpha1.NewCondition(conditionType, conditionStateChanged, conditionMessage))
-		return reconcile.Result{Requeue: true}, r.Update(ctx, instance)
+	// the firewall rule is created by Update
+	upToDate := true
+	if err := e.client.GetFirewallRule(ctx, i, firewallRuleName); err != nil {
+		if !azureclients.IsNotFound(err) {
+			return core.ExternalObservation{}, errors.Wrapf(err, "cannot get firewall rule of SQL server %s", i.GetName())
+		}
+		upToDate = false
 	}
 
-	if conditionType != corev1alpha1.Ready {
-		// the instance isn't running still, requeue another reconciliation attempt
-		return reconcile.Result{Requeue: true}, nil
+	o := core.ExternalObservation{
+		ResourceExists:   true,
+		ResourceReady:    server.State == stateReady,
+		ResourceUpToDate: upToDate,
 	}
-
-	// ensure all the connection information is set on the secret
-	if err := r.createOrUpdateConnectionSecret(instance, ""); err != nil {
-		return r.fail(instance, errorSettingConnectionSecret, fmt.Sprintf("failed to set connection secret for SQL Server instance %s: %+v", instance.GetName(), err))
+	if o.ResourceReady {
+		o.ConnectionDetails = connectionDetails(i)
+		o.ConnectionDetails[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(status.Endpoint)
 	}
-
-	return reconcile.Result{}, nil
+	return o, nil
 }
 
-// handle the creation of the given SQL Server instance
-func (r *SQLReconciler) handleCreation(sqlServersClient azureclients.SQLServerAPI, instance azuredbv1alpha1.SQLServer) (reconcile.Result, error) {
-	ctx := context.Background()
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(azuredbv1alpha1.SQLServer)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotSQLServer)
+	}
 
 	// generate a password for the admin user
 	adminPassword, err := util.GeneratePassword(passwordDataLen)
 	if err != nil {
-		return r.fail(instance, errorCreatingPassword, fmt.Sprintf("failed to create password for SQL Server instance %s: %+v", instance.GetName(), err))
-	}
-
-	// save the password to the connection info secret, we'll update the secret later with the
-	// server FQDN once we have that
-	if err := r.createOrUpdateConnectionSecret(instance, adminPassword); err != nil {
-		return r.fail(instance, errorSettingConnectionSecret, fmt.Sprintf("failed to set connection secret for SQL Server instance %s: %+v", instance.GetName(), err))
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create password for SQL server %s", i.GetName())
 	}
 
 	// make the API call to start the create server operation
-	log.Printf("starting create of SQL Server instance %s", instance.GetName())
-	createOp, err := sqlServersClient.CreateServerBegin(ctx, instance, adminPassword)
+	logging.FromContext(ctx).Info("starting create of sql server instance")
+	createOp, err := e.client.CreateServerBegin(ctx, i, adminPassword)
 	if err != nil {
-		return r.fail(instance, errorCreatingInstance, fmt.Sprintf("failed to start create operation for SQL Server instance %s: %+v", instance.GetName(), err))
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot start create operation for SQL server %s", i.GetName())
 	}
 
-	log.Printf("started create of SQL Server instance %s, operation: %s", instance.GetName(), string(createOp))
+	logging.FromContext(ctx).Info("started create of sql server instance", "operation", string(createOp))
 
 	// save the create operation to the CRD status
-	status := instance.GetStatus()
-	status.SetCreating()
+	status := i.GetStatus()
 	status.RunningOperation = string(createOp)
 	status.RunningOperationType = azuredbv1alpha1.OperationCreateServer
 
-	// wait until the important status fields we just set have become committed/consistent
-	updateWaitErr := wait.ExponentialBackoff(util.DefaultUpdateRetry, func() (done bool, err error) {
-		if err := r.Update(ctx, instance); err != nil {
-			return false, nil
-		}
-
-		// the update went through, let's do a get to verify the fields are committed/consistent
-		fetchedInstance, err := r.findInstance(instance)
-		if err != nil {
-			return false, nil
-		}
-
-		if fetchedInstance.GetStatus().RunningOperation != "" {
-			// the running operation field has been committed, we can stop retrying
-			return true, nil
-		}
-
-		// the instance hasn't reached consistency yet, retry
-		log.Printf("SQL Server instance %s hasn't reached consistency yet, retrying", instance.GetName())
-		return false, nil
-	})
-
-	return reconcile.Result{Requeue: true}, updateWaitErr
+	cd := connectionDetails(i)
+	cd[corev1alpha1.ResourceCredentialsSecretPasswordKey] = []byte(adminPassword)
+	return core.ExternalCreation{ConnectionDetails: cd}, nil
 }
 
-// handle the deletion of the given SQL Server instance
-func (r *SQLReconciler) handleDeletion(sqlServersClient azureclients.SQLServerAPI, instance azuredbv1alpha1.SQLServer) (reconcile.Result, error) {
-	ctx := context.Background()
-
-	// first get the latest status of the SQL Server resource that needs to be deleted
-	_, err := sqlServersClient.GetServer(ctx, instance)
-	if err != nil {
-		if !azureclients.IsNotFound(err) {
-			return r.fail(instance, errorFetchingInstance, fmt.Sprintf("failed to get SQL Server instance %s for deletion: %+v", instance.GetName(), err))
-		}
-
-		// SQL Server instance doesn't exist, it's already deleted
-		log.Printf("SQL Server instance %s does not exist, it must be already deleted", instance.GetName())
-		return r.markAsDeleting(instance)
+// Update creates the firewall rule of the SQL server, which is the only part
+// of it that can be out of date.
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	i, ok := mg.(azuredbv1alpha1.SQLServer)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotSQLServer)
 	}
 
-	// attempt to delete the SQL Server instance now
-	deleteFuture, err := sqlServersClient.DeleteServer(ctx, instance)
-	if err != nil {
-		return r.fail(instance, errorDeletingInstance, fmt.Sprintf("failed to start delete operation for SQL Server instance %s: %+v", instance.GetName(), err))
-	}
-
-	deleteFutureJSON, _ := deleteFuture.MarshalJSON()
-	log.Printf("started delete of SQL Server instance %s, operation: %s", instance.GetName(), string(deleteFutureJSON))
-	return r.markAsDeleting(instance)
-}
-
-func (r *SQLReconciler) markAsDeleting(instance azuredbv1alpha1.SQLServer) (reconcile.Result, error) {
-	ctx := context.Background()
-	instance.GetStatus().SetCondition(corev1alpha1.NewCondition(corev1alpha1.Deleting, "", ""))
-	util.RemoveFinalizer(instance, r.finalizer)
-	return reconcile.Result{}, r.Update(ctx, instance)
-}
-
-func (r *SQLReconciler) handleFirewallRuleCreation(sqlServersClient azureclients.SQLServerAPI, instance azuredbv1alpha1.SQLServer) (reconcile.Result, error) {
-	ctx := context.Background()
-
-	log.Printf("starting create of firewall rules for SQL Server instance %s", instance.GetName())
-	createOp, err := sqlServersClient.CreateFirewallRulesBegin(ctx, instance, firewallRuleName)
+	logging.FromContext(ctx).Info("starting create of firewall rules for sql server instance")
+	createOp, err := e.client.CreateFirewallRulesBegin(ctx, i, firewallRuleName)
 	if err != nil {
-		return r.fail(instance, errorCreatingInstance, fmt.Sprintf("failed to start create firewall rules operation for SQL Server instance %s: %+v", instance.GetName(), err))
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot start create firewall rules operation for SQL server %s", i.GetName())
 	}
 
-	log.Printf("started create of firewall rules for SQL Server instance %s, operation: %s", instance.GetName(), string(createOp))
+	logging.FromContext(ctx).Info("started create of firewall rules for sql server instance", "operation", string(createOp))
 
 	// save the create operation to the CRD status
-	status := instance.GetStatus()
+	status := i.GetStatus()
 	status.RunningOperation = string(createOp)
 	status.RunningOperationType = azuredbv1alpha1.OperationCreateFirewallRules
-
-	return reconcile.Result{Requeue: true}, r.Update(ctx, instance)
+	return core.ExternalUpdate{}, nil
 }
 
-// handle a running operation for the given SQL Server instance
-func (r *SQLReconciler) handleRunningOperation(sqlServersClient azureclients.SQLServerAPI, instance azuredbv1alpha1.SQLServer) (reconcile.Result, error) {
-	ctx := context.Background()
-
-	var done bool
-	var err error
-	opType := instance.GetStatus().RunningOperationType
-
-	// check if the operation is done yet and if there was any error
-	switch opType {
-	case azuredbv1alpha1.OperationCreateServer:
-		done, err = sqlServersClient.CreateServerEnd([]byte(instance.GetStatus().RunningOperation))
-	case azuredbv1alpha1.OperationCreateFirewallRules:
-		done, err = sqlServersClient.CreateFirewallRulesEnd([]byte(instance.GetStatus().RunningOperation))
-	default:
-		return r.fail(instance, errorCreatingInstance,
-			fmt.Sprintf("unknown running operation type for SQL Server instance %s: %s", instance.GetName(), opType))
+// Delete the SQL server. Azure backups do not outlive their server, so the
+// final snapshot of a server is a point in time restore of it to a new server
+// that must complete before the server is deleted.
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(azuredbv1alpha1.SQLServer)
+	if !ok {
+		return errors.New(errNotSQLServer)
 	}
+	status := i.GetStatus()
 
-	if !done {
-		// not done yet, check again on the next reconcile
-		log.Printf("waiting on create operation type %s for SQL Server instance %s, err: %+v",
-			instance.GetStatus().RunningOperationType, instance.GetName(), err)
-		return reconcile.Result{Requeue: true}, err
+	// we already started the deletion of the SQL server, nothing more to do
+	if status.State == stateDropping {
+		return nil
 	}
 
-	// the operation is done, clear out the running operation on the CRD status
-	status := instance.GetStatus()
-	status.RunningOperation = ""
-	status.RunningOperationType = ""
-
-	if err != nil {
-		// the operation completed, but there was an error
-		return r.fail(instance, errorCreatingInstance, fmt.Sprintf("failure result returned from create operation for SQL Server instance %s: %+v", instance.GetName(), err))
-	}
-
-	log.Printf("successfully finished operation type %s for SQL Server instance %s", opType, instance.GetName())
-	return reconcile.Result{Requeue: true}, r.Update(ctx, instance)
-}
-
-// fail - helper function to set fail condition with reason and message
-func (r *SQLReconciler) fail(instance azuredbv1alpha1.SQLServer, reason, msg string) (reconcile.Result, error) {
-	ctx := context.Background()
-
-	log.Printf("instance %s failed: '%s': %s", instance.GetName(), reason, msg)
-	instance.GetStatus().SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
-	return reconcile.Result{Requeue: true}, r.Update(ctx, instance)
-}
-
-func (r *SQLReconciler) updateStatus(instance azuredbv1alpha1.SQLServer, message string, server *azureclients.SQLServer) error {
-	ctx := context.Background()
-
-	oldStatus := instance.GetStatus()
-	status := &azuredbv1alpha1.SQLServerStatus{
-		ConditionedStatus:    oldStatus.ConditionedStatus,
-		BindingStatusPhase:   oldStatus.BindingStatusPhase,
-		Message:              message,
-		State:                server.State,
-		ProviderID:           server.ID,
-		Endpoint:             server.FQDN,
-		RunningOperation:     oldStatus.RunningOperation,
-		RunningOperationType: oldStatus.RunningOperationType,
+	if i.GetSpec().ReclaimPolicy == corev1alpha1.ReclaimSnapshot && status.FinalSnapshot == "" {
+		done, err := e.finalSnapshot(ctx, i)
+		if !done || err != nil {
+			return err
+		}
 	}
-	instance.SetStatus(status)
 
-	if err := r.Update(ctx, instance); err != nil {
-		return fmt.Errorf("failed to update status of CRD instance %s: %+v", instance.GetName(), err)
+	deleteFuture, err := e.client.DeleteServer(ctx, i)
+	if err != nil && !azureclients.IsNotFound(err) {
+		return errors.Wrapf(err, "cannot start delete operation for SQL server %s", i.GetName())
 	}
 
+	deleteFutureJSON, _ := deleteFuture.MarshalJSON()
+	logging.FromContext(ctx).Info("started delete of sql server instance", "operation", string(deleteFutureJSON))
+	status.State = stateDropping
 	return nil
 }
 
-func (r *SQLReconciler) createOrUpdateConnectionSecret(instance azuredbv1alpha1.SQLServer, password string) error {
-	// first check if secret already exists
-	secretName := instance.ConnectionSecretName()
-	secretExists := false
-	connectionSecret, err := r.clientset.CoreV1().Secrets(instance.GetNamespace()).Get(secretName, metav1.GetOptions{})
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			return fmt.Errorf("failed to get connection secret %s for instance %s: %+v", secretName, instance.GetName(), err)
-		}
-		// secret doesn't exist yet, create it from scratch
-		connectionSecret = &v1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:            secretName,
-				Namespace:       instance.GetNamespace(),
-				OwnerReferences: []metav1.OwnerReference{instance.OwnerReference()},
-			},
+// finalSnapshot starts the final snapshot of the supplied SQL server, or
+// returns true once the started final snapshot has completed.
+func (e *external) finalSnapshot(ctx context.Context, i azuredbv1alpha1.SQLServer) (bool, error) {
+	status := i.GetStatus()
+	snapshot := corev1alpha1.FinalSnapshotName(i.GetName())
+
+	if status.RunningOperationType != azuredbv1alpha1.OperationCreateFinalSnapshot {
+		server, err := e.client.GetServer(ctx, i)
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot get SQL server %s", i.GetName())
 		}
-	} else {
-		// secret already exists, we'll update the missing information if that hasn't already been done
-		secretExists = true
-		if isConnectionSecretCompleted(connectionSecret) {
-			// the connection secret is already filled out completely, we shouldn't overwrite it
-			return nil
+
+		logging.FromContext(ctx).Info("starting final snapshot of sql server instance", "snapshot", snapshot)
+		createOp, err := e.client.CreateFinalSnapshotBegin(ctx, i, snapshot, server.ID, e.now())
+		if err != nil {
+			return false, errors.Wrapf(err, "cannot start final snapshot operation for SQL server %s", i.GetName())
 		}
 
-		// reuse the password that has already been set
-		password = string(connectionSecret.Data[corev1alpha1.ResourceCredentialsSecretPasswordKey])
+		status.RunningOperation = string(createOp)
+		status.RunningOperationType = azuredbv1alpha1.OperationCreateFinalSnapshot
+		return false, nil
 	}
 
-	// fill in all of the connection details on the secret's data
-	connectionSecret.Data = map[string][]byte{
-		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(fmt.Sprintf("%s@%s", instance.GetSpec().AdminLoginName, instance.GetName())),
-		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
-	}
-	if instance.GetStatus().Endpoint != "" {
-		connectionSecret.Data[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(instance.GetStatus().Endpoint)
+	done, err := e.client.CreateServerEnd([]byte(status.RunningOperation))
+	if !done {
+		// not done yet, check again on the next reconcile
+		logging.FromContext(ctx).V(logging.Debug).Info("waiting on final snapshot of sql server instance", "snapshot", snapshot)
+		return false, errors.Wrapf(err, "cannot get final snapshot operation of SQL server %s", i.GetName())
 	}
 
-	if secretExists {
-		if _, err := r.clientset.CoreV1().Secrets(instance.GetNamespace()).Update(connectionSecret); err != nil {
-			return fmt.Errorf("failed to update connection secret %s: %+v", connectionSecret.Name, err)
-		}
-		log.Printf("updated connection secret %s for user '%s'", connectionSecret.Name, instance.GetSpec().AdminLoginName)
-	} else {
-		if _, err := r.clientset.CoreV1().Secrets(instance.GetNamespace()).Create(connectionSecret); err != nil {
-			return fmt.Errorf("failed to create connection secret %s: %+v", connectionSecret.Name, err)
-		}
-		log.Printf("created connection secret %s for user '%s'", connectionSecret.Name, instance.GetSpec().AdminLoginName)
+	status.RunningOperation = ""
+	status.RunningOperationType = ""
+	if err != nil {
+		return false, errors.Wrapf(err, "failure result returned from final snapshot operation for SQL server %s", i.GetName())
 	}
 
-	return nil
+	logging.FromContext(ctx).Info("took final snapshot of sql server instance", "snapshot", snapshot)
+	status.FinalSnapshot = snapshot
+	return true, nil
 }
 
-func isConnectionSecretCompleted(connectionSecret *v1.Secret) bool {
-	if connectionSecret == nil {
-		return false
-	}
-
-	if !isSecretDataKeySet(corev1alpha1.ResourceCredentialsSecretEndpointKey, connectionSecret.Data) {
-		return false
-	}
-
-	if !isSecretDataKeySet(corev1alpha1.ResourceCredentialsSecretUserKey, connectionSecret.Data) {
-		return false
-	}
-
-	if !isSecretDataKeySet(corev1alpha1.ResourceCredentialsSecretPasswordKey, connectionSecret.Data) {
-		return false
+// endRunningOperation returns true if the running create server or create
+// firewall rules operation of the supplied SQL server is done.
+func (e *external) endRunningOperation(i azuredbv1alpha1.SQLServer) (bool, error) {
+	status := i.GetStatus()
+	switch status.RunningOperationType {
+	case azuredbv1alpha1.OperationCreateServer:
+		return e.client.CreateServerEnd([]byte(status.RunningOperation))
+	case azuredbv1alpha1.OperationCreateFirewallRules:
+		return e.client.CreateFirewallRulesEnd([]byte(status.RunningOperation))
 	}
-
-	return true
+	return true, errors.Errorf("unknown running operation type %s", status.RunningOperationType)
 }
 
-func isSecretDataKeySet(key string, data map[string][]byte) bool {
-	if data == nil {
-		return false
+// connectionDetails returns the connection details of the supplied SQL server
+// that are known without observing it.
+func connectionDetails(i azuredbv1alpha1.SQLServer) core.ConnectionDetails {
+	return core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey: []byte(fmt.Sprintf("%s@%s", i.GetSpec().AdminLoginName, i.GetName())),
 	}
-
-	// the key has been set if it exists and its value is not an empty string
-	val, ok := data[key]
-	return ok && string(val) != ""
 }
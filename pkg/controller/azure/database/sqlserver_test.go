@@ -20,7 +20,9 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/services/mysql/mgmt/2017-12-01/mysql"
 	"github.com/Azure/go-autorest/autorest"
@@ -28,13 +30,12 @@ import (
 	"github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
@@ -47,6 +48,7 @@ type mockSQLServerClient struct {
 	MockGetServer                func(ctx context.Context, instance azuredbv1alpha1.SQLServer) (*azureclients.SQLServer, error)
 	MockCreateServerBegin        func(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) ([]byte, error)
 	MockCreateServerEnd          func(createOp []byte) (bool, error)
+	MockCreateFinalSnapshotBegin func(ctx context.Context, instance azuredbv1alpha1.SQLServer, name, sourceID string, restorePoint time.Time) ([]byte, error)
 	MockDeleteServer             func(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error)
 	MockGetFirewallRule          func(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) error
 	MockCreateFirewallRulesBegin func(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) ([]byte, error)
@@ -74,6 +76,13 @@ func (m *mockSQLServerClient) CreateServerEnd(createOp []byte) (bool, error) {
 	return true, nil
 }
 
+func (m *mockSQLServerClient) CreateFinalSnapshotBegin(ctx context.Context, instance azuredbv1alpha1.SQLServer, name, sourceID string, restorePoint time.Time) ([]byte, error) {
+	if m.MockCreateFinalSnapshotBegin != nil {
+		return m.MockCreateFinalSnapshotBegin(ctx, instance, name, sourceID, restorePoint)
+	}
+	return nil, nil
+}
+
 func (m *mockSQLServerClient) DeleteServer(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error) {
 	if m.MockDeleteServer != nil {
 		return m.MockDeleteServer(ctx, instance)
@@ -120,14 +129,14 @@ func TestReconcile(t *testing.T) {
 	sqlServerClient := &mockSQLServerClient{}
 	sqlServerClientFactory := &mockSQLServerClientFactory{mockClient: sqlServerClient}
 
-	getCallCount := 0
+	// the create operations are not done until their flags are set, and the
+	// server is not found until it is created and once it is deleted
+	var serverCreated, firewallCreated, deleted int32
+
 	sqlServerClient.MockGetServer = func(ctx context.Context, instance azuredbv1alpha1.SQLServer) (*azureclients.SQLServer, error) {
-		getCallCount++
-		if getCallCount <= 1 {
-			// first GET should return not found, which will cause the reconcile loop to try to create the instance
+		if atomic.LoadInt32(&serverCreated) == 0 || atomic.LoadInt32(&deleted) == 1 {
 			return nil, autorest.DetailedError{StatusCode: http.StatusNotFound}
 		}
-		// subsequent GET calls should return the created instance
 		instanceName := instance.GetName()
 		return &azureclients.SQLServer{
 			State: string(mysql.ServerStateReady),
@@ -138,12 +147,11 @@ func TestReconcile(t *testing.T) {
 	sqlServerClient.MockCreateServerBegin = func(ctx context.Context, instance azuredbv1alpha1.SQLServer, adminPassword string) ([]byte, error) {
 		return []byte("mocked marshalled create future"), nil
 	}
-
-	getFirewallCallCount := 0
+	sqlServerClient.MockCreateServerEnd = func(createOp []byte) (bool, error) {
+		return atomic.LoadInt32(&serverCreated) == 1, nil
+	}
 	sqlServerClient.MockGetFirewallRule = func(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) error {
-		getFirewallCallCount++
-		if getFirewallCallCount <= 1 {
-			// first GET should return not found, which will cause the reconcile loop to try to create the firewall rule
+		if atomic.LoadInt32(&firewallCreated) == 0 {
 			return autorest.DetailedError{StatusCode: http.StatusNotFound}
 		}
 		return nil
@@ -151,20 +159,26 @@ func TestReconcile(t *testing.T) {
 	sqlServerClient.MockCreateFirewallRulesBegin = func(ctx context.Context, instance azuredbv1alpha1.SQLServer, firewallRuleName string) ([]byte, error) {
 		return []byte("mocked marshalled firewall create future"), nil
 	}
+	sqlServerClient.MockCreateFirewallRulesEnd = func(createOp []byte) (bool, error) {
+		return atomic.LoadInt32(&firewallCreated) == 1, nil
+	}
+	sqlServerClient.MockDeleteServer = func(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error) {
+		atomic.StoreInt32(&deleted, 1)
+		return azurerest.Future{}, nil
+	}
 
-	// Setup the Manager and Controller.  Wrap the Controller Reconcile function so it writes each request to a
-	// channel when it is finished.
+	// Setup the Manager and Controller.
 	mgr, err := manager.New(cfg, manager.Options{})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	c := mgr.GetClient()
 
-	r := newMysqlServerReconciler(mgr, sqlServerClientFactory, clientset)
-	recFn, requests := SetupTestReconcile(r)
-	g.Expect(addMysqlServerReconciler(mgr, recFn)).NotTo(gomega.HaveOccurred())
+	g.Expect(addMysqlServer(mgr, sqlServerClientFactory, clientset)).NotTo(gomega.HaveOccurred())
 	defer close(StartTestManager(mgr, g))
 
 	// create the provider object and defer its cleanup
 	provider := testProvider(testSecret([]byte("testdata")))
+	provider.Status.UnsetAllConditions()
+	provider.Status.SetReady()
 	err = c.Create(ctx, provider)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	defer c.Delete(ctx, provider)
@@ -173,45 +187,34 @@ func TestReconcile(t *testing.T) {
 	instance := testInstance(provider)
 	err = c.Create(ctx, instance)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
-	defer cleanupSQLServer(g, c, requests, instance)
-
-	// 1st reconcile loop should start the create operation
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
+	defer cleanupSQLServer(g, c, instance)
 
-	// after the first reconcile, the create operation should be saved on the running operation field
+	// the create operation should be saved on the running operation field
 	expectedStatus := azuredbv1alpha1.SQLServerStatus{
 		RunningOperation:     "mocked marshalled create future",
 		RunningOperationType: azuredbv1alpha1.OperationCreateServer,
+		ConditionedStatus: corev1alpha1.ConditionedStatus{
+			Conditions: []corev1alpha1.Condition{
+				{
+					Type:   corev1alpha1.Creating,
+					Status: v1.ConditionTrue,
+				},
+			},
+		},
 	}
+	g.Eventually(func() string { return mysqlServer(c).Status.RunningOperation }, timeout).Should(gomega.Equal(expectedStatus.RunningOperation))
 	assertSQLServerStatus(g, c, expectedStatus)
 
-	// 2nd reconcile should finish the create server operation and clear out the running operation field
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-	expectedStatus = azuredbv1alpha1.SQLServerStatus{
-		RunningOperation: "",
-	}
-	assertSQLServerStatus(g, c, expectedStatus)
-
-	// 3rd reconcile should see that there is no firewall rule yet and try to create it
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-	expectedStatus = azuredbv1alpha1.SQLServerStatus{
-		RunningOperation:     "mocked marshalled firewall create future",
-		RunningOperationType: azuredbv1alpha1.OperationCreateFirewallRules,
-	}
-	assertSQLServerStatus(g, c, expectedStatus)
-
-	// 4th reconcile should finish the create firewall operation and clear out the running operation field
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-	expectedStatus = azuredbv1alpha1.SQLServerStatus{
-		RunningOperation: "",
-	}
-	assertSQLServerStatus(g, c, expectedStatus)
-
-	// 5th reconcile should find the SQL Server instance from Azure and update the full status of the CRD
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
+	// once the create server operation is done, there is no firewall rule yet
+	// and its create operation should be saved on the running operation field
+	atomic.StoreInt32(&serverCreated, 1)
+	g.Eventually(func() string { return mysqlServer(c).Status.RunningOperationType }, timeout).Should(gomega.Equal(azuredbv1alpha1.OperationCreateFirewallRules))
+	g.Expect(mysqlServer(c).Status.RunningOperation).To(gomega.Equal("mocked marshalled firewall create future"))
 
-	// verify that the CRD status was updated with details about the external SQL Server and that the
-	// CRD conditions show the transition from creating to running
+	// once the create firewall operation is done, the CRD status should be
+	// updated with details about the external SQL Server and the CRD
+	// conditions should show the transition from creating to ready
+	atomic.StoreInt32(&firewallCreated, 1)
 	expectedStatus = azuredbv1alpha1.SQLServerStatus{
 		Message:    "SQL Server instance test-db-instance is ready",
 		State:      "Ready",
@@ -220,37 +223,90 @@ func TestReconcile(t *testing.T) {
 		ConditionedStatus: corev1alpha1.ConditionedStatus{
 			Conditions: []corev1alpha1.Condition{
 				{
-					Type:    corev1alpha1.Ready,
-					Status:  v1.ConditionTrue,
-					Reason:  conditionStateChanged,
-					Message: "SQL Server instance test-db-instance is in the Ready state",
+					Type:   corev1alpha1.Creating,
+					Status: v1.ConditionFalse,
+				},
+				{
+					Type:   corev1alpha1.Ready,
+					Status: v1.ConditionTrue,
 				},
 			},
 		},
 	}
+	g.Eventually(func() bool { return mysqlServer(c).Status.IsReady() }, timeout).Should(gomega.BeTrue())
 	assertSQLServerStatus(g, c, expectedStatus)
 
-	// wait for the connection information to be stored in a secret, then verify it
-	var connectionSecret *v1.Secret
-	for {
-		if connectionSecret, err = r.clientset.CoreV1().Secrets(namespace).Get(instanceName, metav1.GetOptions{}); err == nil {
-			if string(connectionSecret.Data[corev1alpha1.ResourceCredentialsSecretEndpointKey]) != "" {
-				break
-			}
-		}
-	}
+	// the connection information should have been stored in a secret
+	connectionSecret := &v1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: instanceName}, connectionSecret)).To(gomega.Succeed())
 	assertConnectionSecret(g, c, connectionSecret)
 
 	// verify that a finalizer was added to the CRD
-	c.Get(ctx, expectedRequest.NamespacedName, instance)
-	g.Expect(len(instance.Finalizers)).To(gomega.Equal(1))
-	g.Expect(instance.Finalizers[0]).To(gomega.Equal(mysqlFinalizer))
+	g.Expect(mysqlServer(c).Finalizers).To(gomega.Equal([]string{"finalizer." + mysqlControllerName}))
 
 	// test deletion of the instance
-	cleanupSQLServer(g, c, requests, instance)
+	cleanupSQLServer(g, c, instance)
 }
 
-func cleanupSQLServer(g *gomega.GomegaWithT, c client.Client, requests chan reconcile.Request, instance *azuredbv1alpha1.MysqlServer) {
+func TestDeleteFinalSnapshot(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	snapshotDone := false
+	deleted := false
+	now := time.Date(2019, 5, 1, 12, 0, 0, 0, time.UTC)
+	sqlServerClient := &mockSQLServerClient{
+		MockGetServer: func(ctx context.Context, instance azuredbv1alpha1.SQLServer) (*azureclients.SQLServer, error) {
+			return &azureclients.SQLServer{ID: "test-azure-id", State: string(mysql.ServerStateReady)}, nil
+		},
+		MockCreateFinalSnapshotBegin: func(ctx context.Context, instance azuredbv1alpha1.SQLServer, name, sourceID string, restorePoint time.Time) ([]byte, error) {
+			g.Expect(name).To(gomega.Equal(corev1alpha1.FinalSnapshotName(instanceName)))
+			g.Expect(sourceID).To(gomega.Equal("test-azure-id"))
+			g.Expect(restorePoint).To(gomega.Equal(now))
+			return []byte("mocked marshalled snapshot future"), nil
+		},
+		MockCreateServerEnd: func(createOp []byte) (bool, error) {
+			return snapshotDone, nil
+		},
+		MockDeleteServer: func(ctx context.Context, instance azuredbv1alpha1.SQLServer) (azurerest.Future, error) {
+			deleted = true
+			return azurerest.Future{}, nil
+		},
+	}
+	e := &external{client: sqlServerClient, now: func() time.Time { return now }}
+
+	instance := testInstance(testProvider(testSecret([]byte("testdata"))))
+	instance.Spec.ReclaimPolicy = corev1alpha1.ReclaimSnapshot
+
+	// the final snapshot is started
+	g.Expect(e.Delete(ctx, instance)).To(gomega.Succeed())
+	g.Expect(instance.Status.RunningOperation).To(gomega.Equal("mocked marshalled snapshot future"))
+	g.Expect(instance.Status.RunningOperationType).To(gomega.Equal(azuredbv1alpha1.OperationCreateFinalSnapshot))
+	g.Expect(deleted).To(gomega.BeFalse())
+
+	// the final snapshot is running, and is left to the delete operation
+	o, err := e.Observe(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(o.ResourceExists).To(gomega.BeTrue())
+	g.Expect(instance.Status.RunningOperationType).To(gomega.Equal(azuredbv1alpha1.OperationCreateFinalSnapshot))
+	g.Expect(e.Delete(ctx, instance)).To(gomega.Succeed())
+	g.Expect(deleted).To(gomega.BeFalse())
+
+	// the final snapshot is done, and the server is deleted
+	snapshotDone = true
+	g.Expect(e.Delete(ctx, instance)).To(gomega.Succeed())
+	g.Expect(instance.Status.FinalSnapshot).To(gomega.Equal(corev1alpha1.FinalSnapshotName(instanceName)))
+	g.Expect(instance.Status.RunningOperation).To(gomega.BeEmpty())
+	g.Expect(instance.Status.State).To(gomega.Equal(stateDropping))
+	g.Expect(deleted).To(gomega.BeTrue())
+}
+
+func mysqlServer(c client.Client) *azuredbv1alpha1.MysqlServer {
+	instance := &azuredbv1alpha1.MysqlServer{}
+	c.Get(ctx, expectedRequest.NamespacedName, instance)
+	return instance
+}
+
+func cleanupSQLServer(g *gomega.GomegaWithT, c client.Client, instance *azuredbv1alpha1.MysqlServer) {
 	deletedInstance := &azuredbv1alpha1.MysqlServer{}
 	if err := c.Get(ctx, expectedRequest.NamespacedName, deletedInstance); errors.IsNotFound(err) {
 		// instance has already been deleted, bail out
@@ -261,20 +317,6 @@ func cleanupSQLServer(g *gomega.GomegaWithT, c client.Client, requests chan reco
 	err := c.Delete(ctx, instance)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
-	// wait for the deletion timestamp to be set
-	err = wait.ExponentialBackoff(test.DefaultRetry, func() (done bool, err error) {
-		deletedInstance := &azuredbv1alpha1.MysqlServer{}
-		c.Get(ctx, expectedRequest.NamespacedName, deletedInstance)
-		if deletedInstance.DeletionTimestamp != nil {
-			return true, nil
-		}
-		return false, nil
-	})
-	g.Expect(err).NotTo(gomega.HaveOccurred())
-
-	// wait for the reconcile to happen that handles the CRD deletion
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-
 	// wait for the finalizer to run and the instance to be deleted for good
 	err = wait.ExponentialBackoff(test.DefaultRetry, func() (done bool, err error) {
 		deletedInstance := &azuredbv1alpha1.MysqlServer{}
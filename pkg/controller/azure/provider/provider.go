@@ -18,8 +18,8 @@ package provider
 
 import (
 	"context"
-	"log"
 
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -33,6 +33,9 @@ import (
 
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
 	azureclient "github.com/crossplaneio/crossplane/pkg/clients/azure"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -42,9 +45,7 @@ const (
 )
 
 var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	result = reconcile.Result{}
 )
 
 // Add creates a new Provider Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -59,6 +60,8 @@ type Reconciler struct {
 	scheme     *runtime.Scheme
 	kubeclient kubernetes.Interface
 	recorder   record.EventRecorder
+	requeue    *requeue.Policy
+	log        logr.Logger
 
 	validate func(*azureclient.Client) error
 }
@@ -70,6 +73,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		scheme:     mgr.GetScheme(),
 		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
 		recorder:   mgr.GetRecorder(controllerName),
+		requeue:    requeue.NewPolicy(controllerName, requeue.Defaults),
+		log:        logging.Log.WithName(controllerName),
 	}
 	r.validate = r._validate
 	return r
@@ -78,7 +83,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -96,7 +101,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 func (r *Reconciler) fail(instance *v1alpha1.Provider, reason, msg string) (reconcile.Result, error) {
 	instance.Status.UnsetAllConditions()
 	instance.Status.SetFailed(reason, msg)
-	return resultRequeue, r.Update(context.TODO(), instance)
+	return r.requeue.Failed(instance, nil), r.Update(context.TODO(), instance)
 }
 
 func (r *Reconciler) _validate(client *azureclient.Client) error {
@@ -106,8 +111,7 @@ func (r *Reconciler) _validate(client *azureclient.Client) error {
 // Reconcile reads that state of the cluster for a Provider object and makes changes based on the state read
 // and what is in the Provider.Spec
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", v1alpha1.ProviderKindAPIVersion, request)
-	ctx := context.TODO()
+	ctx := logging.NewContext(context.Background(), r.log, request)
 
 	// Fetch the Provider instance
 	instance := &v1alpha1.Provider{}
@@ -116,12 +120,20 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.requeue.Forget(request.NamespacedName)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
 		return reconcile.Result{}, err
 	}
 
+	logging.Reconciling(ctx, instance)
+
+	// Wait for a failed provider to back off before reconciling it again
+	if d := r.requeue.Wait(instance); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
 	// Retrieve azure client from the given provider config
 	azureClient, err := azureclient.NewClient(instance, r.kubeclient)
 	if err != nil {
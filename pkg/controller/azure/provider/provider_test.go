@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -34,6 +35,7 @@ import (
 
 	"github.com/crossplaneio/crossplane/pkg/apis/azure"
 	azureclient "github.com/crossplaneio/crossplane/pkg/clients/azure"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -56,6 +58,7 @@ const (
 )
 
 var (
+	ctx = context.Background()
 	key = types.NamespacedName{
 		Namespace: namespace,
 		Name:      providerName,
@@ -63,6 +66,8 @@ var (
 	request = reconcile.Request{
 		NamespacedName: key,
 	}
+
+	resultFailed = reconcile.Result{RequeueAfter: requeue.Defaults.BackoffBase}
 )
 
 func init() {
@@ -119,7 +124,7 @@ func TestReconcileSecretNotFound(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	rp := &Provider{}
@@ -145,7 +150,7 @@ func TestReconcileSecretKeyNotFound(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	rp := &Provider{}
@@ -196,7 +201,7 @@ func TestReconcileValidationFailed(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	// assert provider status
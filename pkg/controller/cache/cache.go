@@ -20,21 +20,19 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/crossplaneio/crossplane/pkg/controller/cache/redis"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
-	AddToManagerFuncs = append(AddToManagerFuncs, redis.AddCluster)
+	Controllers = append(Controllers,
+		selection.Controller{Kind: selection.KindCache, Add: redis.AddCluster},
+	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
-
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
-			return err
-		}
-	}
-	return nil
+// Controllers are the controllers of cache resource claims
+var Controllers []selection.Controller
+
+// AddToManager adds the selected Controllers to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
+	return sel.AddToManager(m, Controllers)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package redis
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -30,23 +31,30 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
 	cachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/cache/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 // ReplicationGroupHandler dynamically provisions ReplicationGroup resources given a resource class.
 type ReplicationGroupHandler struct{}
 
 // Find a ReplicationGroup resource.
-func (h *ReplicationGroupHandler) Find(n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *ReplicationGroupHandler) Find(ctx context.Context, n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	i := &v1alpha1.ReplicationGroup{}
 	err := c.Get(ctx, n, i)
 	return i, errors.Wrapf(err, "cannot find replication group %s", n)
 }
 
+// Match an existing, unbound ReplicationGroup resource to the supplied claim.
+func (h *ReplicationGroupHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	res, err := corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &v1alpha1.ReplicationGroupList{})
+	return res, errors.Wrap(err, "cannot match replication group")
+}
+
 // Provision a new ReplicationGroup resource.
-func (h *ReplicationGroupHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (h *ReplicationGroupHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	spec := v1alpha1.NewReplicationGroupSpec(class.Parameters)
 
-	if err := resolveAWSClassInstanceValues(spec, claim); err != nil {
+	if err := resolveAWSClassInstanceValues(corecontroller.NewConstraintPolicy(class), spec, claim); err != nil {
 		return nil, errors.Wrap(err, "cannot resolve Azure class instance values")
 	}
 
@@ -73,7 +81,7 @@ func (h *ReplicationGroupHandler) Provision(class *corev1alpha1.ResourceClass, c
 
 // SetBindStatus marks the supplied ReplicationGroup resource as bound or unbound in the
 // Kubernetes API.
-func (h *ReplicationGroupHandler) SetBindStatus(n types.NamespacedName, c client.Client, bound bool) error {
+func (h *ReplicationGroupHandler) SetBindStatus(ctx context.Context, n types.NamespacedName, c client.Client, bound bool) error {
 	i := &v1alpha1.ReplicationGroup{}
 	if err := c.Get(ctx, n, i); err != nil {
 		if kerrors.IsNotFound(err) && !bound {
@@ -85,12 +93,21 @@ func (h *ReplicationGroupHandler) SetBindStatus(n types.NamespacedName, c client
 	return errors.Wrapf(c.Update(ctx, i), "cannot update instance %s", n)
 }
 
-func resolveAWSClassInstanceValues(spec *v1alpha1.ReplicationGroupSpec, claim corev1alpha1.ResourceClaim) error {
+func resolveAWSClassInstanceValues(policy *corecontroller.ConstraintPolicy, spec *v1alpha1.ReplicationGroupSpec, claim corev1alpha1.ResourceClaim) error {
 	rc, ok := claim.(*cachev1alpha1.RedisCluster)
 	if !ok {
 		return errors.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
 	}
 
+	if policy.Constrained(EngineVersionField) && rc.Spec.EngineVersion != "" {
+		// The class allows a range of versions. A claim version within that
+		// range overrides the class version.
+		if err := policy.Check(EngineVersionField, rc.Spec.EngineVersion); err != nil {
+			return errors.Wrap(err, "cannot resolve class claim values")
+		}
+		spec.EngineVersion = ""
+	}
+
 	var err error
 	switch {
 	case spec.EngineVersion == "" && rc.Spec.EngineVersion == "":
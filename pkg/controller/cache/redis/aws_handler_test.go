@@ -27,6 +27,7 @@ import (
 	cachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/cache/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 const claimversion99 = "9.9"
@@ -38,6 +39,7 @@ var (
 func TestResolveAWSClassValues(t *testing.T) {
 	cases := []struct {
 		name    string
+		policy  *corecontroller.ConstraintPolicy
 		class   *v1alpha1.ReplicationGroupSpec
 		claim   corev1alpha1.ResourceClaim
 		want    *v1alpha1.ReplicationGroupSpec
@@ -85,6 +87,26 @@ func TestResolveAWSClassValues(t *testing.T) {
 			want:    &v1alpha1.ReplicationGroupSpec{EngineVersion: awsClassVersion32},
 			wantErr: errors.WithStack(errors.Errorf("cannot resolve class claim values: class version %s is not a patch of claim version %s", awsClassVersion32, claimVersion40)),
 		},
+		{
+			name: "ClassConstrainedClaimAllowed",
+			policy: corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{Constraints: map[string]corev1alpha1.ParameterConstraint{
+				EngineVersionField: {Versions: []string{"3.x", "4.x"}},
+			}}),
+			class:   &v1alpha1.ReplicationGroupSpec{EngineVersion: awsClassVersion32},
+			claim:   &cachev1alpha1.RedisCluster{Spec: cachev1alpha1.RedisClusterSpec{EngineVersion: claimVersion40}},
+			want:    &v1alpha1.ReplicationGroupSpec{EngineVersion: string(v1alpha1.LatestSupportedPatchVersion[claimVersion40])},
+			wantErr: nil,
+		},
+		{
+			name: "ClassConstrainedClaimDisallowed",
+			policy: corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{Constraints: map[string]corev1alpha1.ParameterConstraint{
+				EngineVersionField: {Versions: []string{"3.x"}},
+			}}),
+			class:   &v1alpha1.ReplicationGroupSpec{EngineVersion: awsClassVersion32},
+			claim:   &cachev1alpha1.RedisCluster{Spec: cachev1alpha1.RedisClusterSpec{EngineVersion: claimVersion40}},
+			want:    &v1alpha1.ReplicationGroupSpec{EngineVersion: awsClassVersion32},
+			wantErr: errors.WithStack(errors.Errorf("cannot resolve class claim values: claim value [%s] for %s is not allowed by the resource class: version must be within one of [3.x]", claimVersion40, EngineVersionField)),
+		},
 		{
 			name:    "NotARedisCache",
 			class:   &v1alpha1.ReplicationGroupSpec{},
@@ -96,7 +118,7 @@ func TestResolveAWSClassValues(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotErr := resolveAWSClassInstanceValues(tc.class, tc.claim)
+			gotErr := resolveAWSClassInstanceValues(tc.policy, tc.class, tc.claim)
 			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
 				t.Errorf("want error != got error:\n%s", diff)
 			}
@@ -17,6 +17,7 @@ limitations under the License.
 package redis
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
 	cachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/cache/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 // TODO(negz): Name this something that doesn't stutter? redis.RedisHandler is
@@ -38,17 +40,23 @@ import (
 type RedisHandler struct{} // nolint:golint
 
 // Find a Redis resource.
-func (h *RedisHandler) Find(n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *RedisHandler) Find(ctx context.Context, n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	i := &v1alpha1.Redis{}
 	err := c.Get(ctx, n, i)
 	return i, errors.Wrapf(err, "cannot find Azure Redis Cache %s", n)
 }
 
+// Match an existing, unbound Redis resource to the supplied claim.
+func (h *RedisHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	res, err := corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &v1alpha1.RedisList{})
+	return res, errors.Wrap(err, "cannot match Redis resource")
+}
+
 // Provision a new Redis resource.
-func (h *RedisHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (h *RedisHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	spec := v1alpha1.NewRedisSpec(class.Parameters)
 
-	if err := resolveAzureClassValues(claim); err != nil {
+	if err := resolveAzureClassValues(corecontroller.NewConstraintPolicy(class), claim); err != nil {
 		return nil, errors.Wrap(err, "cannot resolve Azure class instance values")
 	}
 
@@ -75,7 +83,7 @@ func (h *RedisHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1
 
 // SetBindStatus marks the supplied Redis resource as bound or unbound in the
 // Kubernetes API.
-func (h *RedisHandler) SetBindStatus(n types.NamespacedName, c client.Client, bound bool) error {
+func (h *RedisHandler) SetBindStatus(ctx context.Context, n types.NamespacedName, c client.Client, bound bool) error {
 	i := &v1alpha1.Redis{}
 	if err := c.Get(ctx, n, i); err != nil {
 		if kerrors.IsNotFound(err) && !bound {
@@ -87,7 +95,7 @@ func (h *RedisHandler) SetBindStatus(n types.NamespacedName, c client.Client, bo
 	return errors.Wrapf(c.Update(ctx, i), "cannot update instance %s", n)
 }
 
-func resolveAzureClassValues(claim corev1alpha1.ResourceClaim) error {
+func resolveAzureClassValues(policy *corecontroller.ConstraintPolicy, claim corev1alpha1.ResourceClaim) error {
 	rc, ok := claim.(*cachev1alpha1.RedisCluster)
 	if !ok {
 		return errors.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
@@ -97,6 +105,10 @@ func resolveAzureClassValues(claim corev1alpha1.ResourceClaim) error {
 		return nil
 	}
 
+	if err := policy.Check(EngineVersionField, rc.Spec.EngineVersion); err != nil {
+		return err
+	}
+
 	// EngineVersion is currently the only option we expose at the claim level,
 	// and Azure only supports Redis 3.2.
 	if rc.Spec.EngineVersion != v1alpha1.SupportedRedisVersion {
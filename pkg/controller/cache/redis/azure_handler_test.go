@@ -57,7 +57,7 @@ func TestResolveAzureClassValues(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := resolveAzureClassValues(tc.claim)
+			got := resolveAzureClassValues(nil, tc.claim)
 			if diff := deep.Equal(tc.want, got); diff != nil {
 				t.Errorf("want != got:\n%s", diff)
 			}
@@ -17,6 +17,7 @@ limitations under the License.
 package redis
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -38,17 +39,23 @@ import (
 type CloudMemorystoreInstanceHandler struct{}
 
 // Find a CloudMemorystoreInstance instance.
-func (h *CloudMemorystoreInstanceHandler) Find(n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *CloudMemorystoreInstanceHandler) Find(ctx context.Context, n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	i := &gcpcachev1alpha1.CloudMemorystoreInstance{}
 	err := c.Get(ctx, n, i)
 	return i, errors.Wrapf(err, "cannot find Cloud Memorystore instance %s", n)
 }
 
+// Match an existing, unbound CloudMemorystoreInstance instance to the supplied claim.
+func (h *CloudMemorystoreInstanceHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	res, err := corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &gcpcachev1alpha1.CloudMemorystoreInstanceList{})
+	return res, errors.Wrap(err, "cannot match Cloud Memorystore instance")
+}
+
 // Provision a new CloudMemorystoreInstance resource.
-func (h *CloudMemorystoreInstanceHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (h *CloudMemorystoreInstanceHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	spec := gcpcachev1alpha1.NewCloudMemorystoreInstanceSpec(class.Parameters)
 
-	if err := resolveGCPClassInstanceValues(spec, claim); err != nil {
+	if err := resolveGCPClassInstanceValues(corecontroller.NewConstraintPolicy(class), spec, claim); err != nil {
 		return nil, errors.Wrap(err, "cannot resolve GCP class instance values")
 	}
 
@@ -75,7 +82,7 @@ func (h *CloudMemorystoreInstanceHandler) Provision(class *corev1alpha1.Resource
 
 // SetBindStatus marks the supplied CloudMemorystoreInstance as bound or unbound
 // in the Kubernetes API.
-func (h *CloudMemorystoreInstanceHandler) SetBindStatus(n types.NamespacedName, c client.Client, bound bool) error {
+func (h *CloudMemorystoreInstanceHandler) SetBindStatus(ctx context.Context, n types.NamespacedName, c client.Client, bound bool) error {
 	i := &gcpcachev1alpha1.CloudMemorystoreInstance{}
 	if err := c.Get(ctx, n, i); err != nil {
 		if kerrors.IsNotFound(err) && !bound {
@@ -87,12 +94,21 @@ func (h *CloudMemorystoreInstanceHandler) SetBindStatus(n types.NamespacedName,
 	return errors.Wrapf(c.Update(ctx, i), "cannot update instance %s", n)
 }
 
-func resolveGCPClassInstanceValues(spec *gcpcachev1alpha1.CloudMemorystoreInstanceSpec, claim corev1alpha1.ResourceClaim) error {
+func resolveGCPClassInstanceValues(policy *corecontroller.ConstraintPolicy, spec *gcpcachev1alpha1.CloudMemorystoreInstanceSpec, claim corev1alpha1.ResourceClaim) error {
 	rc, ok := claim.(*cachev1alpha1.RedisCluster)
 	if !ok {
 		return errors.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
 	}
 
+	if policy.Constrained(EngineVersionField) && rc.Spec.EngineVersion != "" {
+		// The class allows a range of versions. A claim version within that
+		// range overrides the class version.
+		if err := policy.Check(EngineVersionField, rc.Spec.EngineVersion); err != nil {
+			return errors.Wrap(err, "cannot resolve class claim values")
+		}
+		spec.RedisVersion = ""
+	}
+
 	var err error
 	spec.RedisVersion, err = corecontroller.ResolveClassClaimValues(spec.RedisVersion, toGCPFormat(rc.Spec.EngineVersion))
 	return errors.Wrap(err, "cannot resolve class claim values")
@@ -27,6 +27,7 @@ import (
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpcachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 const (
@@ -40,6 +41,7 @@ const (
 func TestResolveGCPClassInstanceValues(t *testing.T) {
 	cases := []struct {
 		name    string
+		policy  *corecontroller.ConstraintPolicy
 		class   *gcpcachev1alpha1.CloudMemorystoreInstanceSpec
 		claim   corev1alpha1.ResourceClaim
 		want    *gcpcachev1alpha1.CloudMemorystoreInstanceSpec
@@ -76,6 +78,16 @@ func TestResolveGCPClassInstanceValues(t *testing.T) {
 			want:    &gcpcachev1alpha1.CloudMemorystoreInstanceSpec{},
 			wantErr: errors.WithStack(errors.Errorf("cannot resolve class claim values: claim value [%s] does not match the one defined in the resource class [%s]", gcpClassVersion40, gcpClassVersion32)),
 		},
+		{
+			name: "ClassConstrainedClaimAllowed",
+			policy: corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{Constraints: map[string]corev1alpha1.ParameterConstraint{
+				EngineVersionField: {Values: []string{claimVersion32, claimVersion40}},
+			}}),
+			class:   &gcpcachev1alpha1.CloudMemorystoreInstanceSpec{RedisVersion: gcpClassVersion32},
+			claim:   &cachev1alpha1.RedisCluster{Spec: cachev1alpha1.RedisClusterSpec{EngineVersion: claimVersion40}},
+			want:    &gcpcachev1alpha1.CloudMemorystoreInstanceSpec{RedisVersion: gcpClassVersion40},
+			wantErr: nil,
+		},
 		{
 			name:    "NotARedisCache",
 			class:   &gcpcachev1alpha1.CloudMemorystoreInstanceSpec{},
@@ -87,7 +99,7 @@ func TestResolveGCPClassInstanceValues(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotErr := resolveGCPClassInstanceValues(tc.class, tc.claim)
+			gotErr := resolveGCPClassInstanceValues(tc.policy, tc.class, tc.claim)
 			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
 				t.Errorf("want error != got error:\n %+v", diff)
 			}
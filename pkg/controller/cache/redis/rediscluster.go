@@ -17,7 +17,7 @@ limitations under the License.
 package redis
 
 import (
-	"log"
+	"context"
 
 	"github.com/pkg/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,13 +27,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	awscachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	azurecachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
 	cachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/cache/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpcachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
 	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
 )
 
 const (
 	controllerName = "redisclusters.cache.crossplane.io"
 	finalizerName  = "finalizer." + controllerName
+	poolName       = "pool." + controllerName
 )
 
 // Reconciler is the reconciler for RedisCluster objects
@@ -45,26 +52,40 @@ type Reconciler struct {
 // The Manager will set fields on the Controller and Start it when the Manager
 // is Started.
 func AddCluster(mgr manager.Manager) error {
+	corecontroller.SecretDefinitions.SetDefault(secretDefinition)
+
 	r := &Reconciler{corecontroller.NewReconciler(mgr, controllerName, finalizerName, handlers)}
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return errors.Wrap(err, "cannot create controller")
 	}
-	return errors.Wrap(c.Watch(&source.Kind{Type: &cachev1alpha1.RedisCluster{}}, &handler.EnqueueRequestForObject{}), "cannot watch for RedisClusters")
+	if err := c.Watch(&source.Kind{Type: &cachev1alpha1.RedisCluster{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return errors.Wrap(err, "cannot watch for RedisClusters")
+	}
+	err = corecontroller.WatchResources(mgr, c, cachev1alpha1.RedisClusterKind, handlers,
+		&awscachev1alpha1.ReplicationGroup{}, &azurecachev1alpha1.Redis{}, &gcpcachev1alpha1.CloudMemorystoreInstance{}, &corev1alpha1.ExternalResource{})
+	if err != nil {
+		return errors.Wrap(err, "cannot watch for resources bound to RedisClusters")
+	}
+	err = corecontroller.AddPool(mgr, poolName, cachev1alpha1.RedisClusterKindAPIVersion,
+		func() corev1alpha1.ResourceClaim { return &cachev1alpha1.RedisCluster{} }, handlers,
+		&awscachev1alpha1.ReplicationGroup{}, &azurecachev1alpha1.Redis{}, &gcpcachev1alpha1.CloudMemorystoreInstance{})
+	return errors.Wrap(err, "cannot create RedisCluster pool controller")
 }
 
 // Reconcile the desired with the actual state of a RedisCluster.
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", cachev1alpha1.RedisClusterKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), r.Log, request)
 
 	c := &cachev1alpha1.RedisCluster{}
 	if err := r.Get(ctx, request.NamespacedName, c); err != nil {
 		if kerrors.IsNotFound(err) {
-			return corecontroller.Result, nil
+			return r.HandleGetClaimError(request.NamespacedName, err)
 		}
 		return corecontroller.Result, errors.Wrap(err, "cannot get RedisCluster")
 	}
 
-	result, err := r.DoReconcile(c)
+	logging.Reconciling(ctx, c)
+	result, err := r.DoReconcile(ctx, c)
 	return result, errors.Wrapf(err, "cannot reconcile %s/%s", c.GetNamespace(), c.GetName())
 }
@@ -17,21 +17,27 @@ limitations under the License.
 package redis
 
 import (
-	"context"
-
 	awscachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
 	azurecachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
+	cachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/cache/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpcachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
 	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
-var (
-	ctx = context.Background()
+// EngineVersionField is the claim field resource classes may constrain to
+// allow claims to request a range of Redis versions.
+const EngineVersionField = "engineVersion"
 
+var (
 	// map of supported resource handlers
 	handlers = map[string]corecontroller.ResourceHandler{
 		awscachev1alpha1.ReplicationGroupKindAPIVersion:         &ReplicationGroupHandler{},
 		azurecachev1alpha1.RedisKindAPIVersion:                  &RedisHandler{},
 		gcpcachev1alpha1.CloudMemorystoreInstanceKindAPIVersion: &CloudMemorystoreInstanceHandler{},
 	}
+
+	// built-in definition of connection secrets, used unless overridden by a CustomSecretDefinition
+	secretDefinition = corev1alpha1.NewCustomSecretDefinition(cachev1alpha1.Group, cachev1alpha1.Version, cachev1alpha1.RedisClusterKind,
+		corev1alpha1.ResourceCredentialsSecretEndpointKey)
 )
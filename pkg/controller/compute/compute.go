@@ -22,25 +22,21 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/controller/compute/kubernetes"
 	"github.com/crossplaneio/crossplane/pkg/controller/compute/scheduler"
 	"github.com/crossplaneio/crossplane/pkg/controller/compute/workload"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
-	AddToManagerFuncs = append(AddToManagerFuncs,
-		kubernetes.Add,
-		scheduler.Add,
-		workload.Add,
+	Controllers = append(Controllers,
+		selection.Controller{Kind: selection.KindCompute, Add: kubernetes.Add},
+		selection.Controller{Kind: selection.KindCompute, Add: scheduler.Add},
+		selection.Controller{Kind: selection.KindCompute, Add: workload.Add},
 	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
-
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
-			return err
-		}
-	}
-	return nil
+// Controllers are the controllers of compute resource claims
+var Controllers []selection.Controller
+
+// AddToManager adds the selected Controllers to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
+	return sel.AddToManager(m, Controllers)
 }
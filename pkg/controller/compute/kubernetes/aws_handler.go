@@ -17,6 +17,7 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -26,23 +27,35 @@ import (
 
 	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 // AWSClusterHandler AWS EKS handler handles Kubernetes cluster functionality
 type AWSClusterHandler struct{}
 
 // Find EKSCluster resource
-func (r *AWSClusterHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (r *AWSClusterHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	instance := &awscomputev1alpha1.EKSCluster{}
 	err := c.Get(ctx, name, instance)
 	return instance, err
 }
 
+// Match an existing, unbound EKSCluster resource to the supplied claim.
+func (r *AWSClusterHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &awscomputev1alpha1.EKSClusterList{})
+}
+
 // Provision a new EKSCluster
-func (r *AWSClusterHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (r *AWSClusterHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	// construct EKSCluster Spec from class definition
 	resourceInstance := awscomputev1alpha1.NewEKSClusterSpec(class.Parameters)
 
+	version, err := resolveClusterVersion(corecontroller.NewConstraintPolicy(class), resourceInstance.ClusterVersion, claim)
+	if err != nil {
+		return nil, err
+	}
+	resourceInstance.ClusterVersion = version
+
 	// assign provider reference and reclaim policy from the resource class
 	resourceInstance.ProviderRef = class.ProviderRef
 	resourceInstance.ReclaimPolicy = class.ReclaimPolicy
@@ -61,13 +74,13 @@ func (r *AWSClusterHandler) Provision(class *corev1alpha1.ResourceClass, claim c
 		Spec: *resourceInstance,
 	}
 
-	err := c.Create(ctx, cluster)
+	err = c.Create(ctx, cluster)
 	return cluster, err
 }
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (r AWSClusterHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (r AWSClusterHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	instance := &awscomputev1alpha1.EKSCluster{}
 	err := c.Get(ctx, name, instance)
 	if err != nil {
@@ -0,0 +1,62 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
+)
+
+func TestAWSClusterHandlerProvision(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+	h := &AWSClusterHandler{}
+	class := constrainedClass(map[string]string{"clusterVersion": "1.11"})
+	class.Namespace = "default"
+	claim := &computev1alpha1.KubernetesCluster{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-claim", UID: types.UID("test-uid")},
+	}
+
+	// the claim does not request a version, so the class version is used
+	c := fake.NewFakeClient()
+	res, err := h.Provision(ctx, class, claim, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res.(*awscomputev1alpha1.EKSCluster).Spec.ClusterVersion).To(Equal("1.11"))
+
+	// the claim requests a version allowed by the class
+	claim.Spec.ClusterVersion = "1.12.5"
+	c = fake.NewFakeClient()
+	res, err = h.Provision(ctx, class, claim, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	created := &awscomputev1alpha1.EKSCluster{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: "default", Name: "eks-test-uid"}, created)).To(Succeed())
+	g.Expect(created.Spec.ClusterVersion).To(Equal("1.12.5"))
+
+	// the claim requests a version the class does not allow
+	claim.Spec.ClusterVersion = "1.10"
+	c = fake.NewFakeClient()
+	_, err = h.Provision(ctx, class, claim, c)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -26,23 +27,35 @@ import (
 
 	azurecomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 // AKSClusterHandler handles Kubernetes cluster functionality
 type AKSClusterHandler struct{}
 
 // Find AKSCluster resource
-func (r *AKSClusterHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (r *AKSClusterHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	instance := &azurecomputev1alpha1.AKSCluster{}
 	err := c.Get(ctx, name, instance)
 	return instance, err
 }
 
+// Match an existing, unbound AKSCluster resource to the supplied claim.
+func (r *AKSClusterHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &azurecomputev1alpha1.AKSClusterList{})
+}
+
 // Provision a new AKSCluster
-func (r *AKSClusterHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (r *AKSClusterHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	// construct AKSCluster Spec from class definition
 	resourceInstance := azurecomputev1alpha1.NewAKSClusterSpec(class.Parameters)
 
+	version, err := resolveClusterVersion(corecontroller.NewConstraintPolicy(class), resourceInstance.Version, claim)
+	if err != nil {
+		return nil, err
+	}
+	resourceInstance.Version = version
+
 	// assign provider reference and reclaim policy from the resource class
 	resourceInstance.ProviderRef = class.ProviderRef
 	resourceInstance.ReclaimPolicy = class.ReclaimPolicy
@@ -61,13 +74,13 @@ func (r *AKSClusterHandler) Provision(class *corev1alpha1.ResourceClass, claim c
 		Spec: *resourceInstance,
 	}
 
-	err := c.Create(ctx, cluster)
+	err = c.Create(ctx, cluster)
 	return cluster, err
 }
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (r AKSClusterHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (r AKSClusterHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	instance := &azurecomputev1alpha1.AKSCluster{}
 	err := c.Get(ctx, name, instance)
 	if err != nil {
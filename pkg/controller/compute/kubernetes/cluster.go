@@ -18,7 +18,8 @@ package kubernetes
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"reflect"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -29,18 +30,24 @@ import (
 	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
 	azurecomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
 	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
 )
 
 const (
 	controllerName = "kubernetes.compute.crossplane.io"
 	finalizer      = "finalizer." + controllerName
+	poolName       = "pool." + controllerName
+
+	// ClusterVersionField is the claim field resource classes may constrain to
+	// allow claims to request a range of Kubernetes versions.
+	ClusterVersionField = "clusterVersion"
 )
 
 var (
-	ctx = context.Background()
-
 	// map of supported resource handlers
 	handlers = map[string]corecontroller.ResourceHandler{
 		gcpcomputev1alpha1.GKEClusterKindAPIVersion:   &GKEClusterHandler{},
@@ -57,7 +64,14 @@ type Reconciler struct {
 // Add creates a new KubernetesCluster Controller and adds it to the Manager with default RBAC.
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	if err := add(mgr, newReconciler(mgr)); err != nil {
+		return err
+	}
+
+	// Keep the pools of resource classes that pool resources for KubernetesClusters filled
+	return corecontroller.AddPool(mgr, poolName, computev1alpha1.KubernetesInstanceKindAPIVersion,
+		func() corev1alpha1.ResourceClaim { return &computev1alpha1.KubernetesCluster{} }, handlers,
+		&awscomputev1alpha1.EKSCluster{}, &azurecomputev1alpha1.AKSCluster{}, &gcpcomputev1alpha1.GKECluster{})
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -71,7 +85,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -82,19 +96,32 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	return nil
+	// Watch for changes to the resources KubernetesClusters are bound to, and their secrets
+	return corecontroller.WatchResources(mgr, c, computev1alpha1.KubernetesInstanceKind, handlers,
+		&awscomputev1alpha1.EKSCluster{}, &azurecomputev1alpha1.AKSCluster{}, &gcpcomputev1alpha1.GKECluster{}, &corev1alpha1.ExternalResource{})
 }
 
 // Reconcile reads that state of the cluster for a Instance object and makes changes based on the state read
 // and what is in the Instance.Spec
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", computev1alpha1.KubernetesInstanceKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), r.Log, request)
 
 	// fetch the CRD instance
 	instance := &computev1alpha1.KubernetesCluster{}
 	if err := r.Get(ctx, request.NamespacedName, instance); err != nil {
-		return corecontroller.HandleGetClaimError(err)
+		return r.HandleGetClaimError(request.NamespacedName, err)
 	}
 
-	return r.DoReconcile(instance)
+	logging.Reconciling(ctx, instance)
+	return r.DoReconcile(ctx, instance)
+}
+
+// resolveClusterVersion resolves the Kubernetes version requested by the
+// supplied claim against the version specified by its resource class.
+func resolveClusterVersion(policy *corecontroller.ConstraintPolicy, classValue string, claim corev1alpha1.ResourceClaim) (string, error) {
+	kc, ok := claim.(*computev1alpha1.KubernetesCluster)
+	if !ok {
+		return "", fmt.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
+	}
+	return policy.Resolve(ClusterVersionField, classValue, kc.Spec.ClusterVersion)
 }
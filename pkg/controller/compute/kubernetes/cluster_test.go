@@ -0,0 +1,119 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubernetes
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
+)
+
+func init() {
+	if err := awscomputev1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+	if err := gcpcomputev1alpha1.SchemeBuilder.AddToScheme(scheme.Scheme); err != nil {
+		panic(err)
+	}
+}
+
+// constrainedClass returns a resource class that allows claims to request
+// any 1.11 or 1.12 Kubernetes version.
+func constrainedClass(params map[string]string) *corev1alpha1.ResourceClass {
+	return &corev1alpha1.ResourceClass{
+		Parameters: params,
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			ClusterVersionField: {Versions: []string{"1.11.x", "1.12.x"}},
+		},
+	}
+}
+
+func TestResolveClusterVersion(t *testing.T) {
+	cases := []struct {
+		name       string
+		policy     *corecontroller.ConstraintPolicy
+		classValue string
+		claim      corev1alpha1.ResourceClaim
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "UnconstrainedClassSetClaimUnset",
+			classValue: "1.11",
+			claim:      &computev1alpha1.KubernetesCluster{},
+			want:       "1.11",
+		},
+		{
+			name:  "UnconstrainedClassUnsetClaimSet",
+			claim: &computev1alpha1.KubernetesCluster{Spec: computev1alpha1.KubernetesClusterSpec{ClusterVersion: "1.12"}},
+			want:  "1.12",
+		},
+		{
+			name:       "UnconstrainedConflict",
+			classValue: "1.11",
+			claim:      &computev1alpha1.KubernetesCluster{Spec: computev1alpha1.KubernetesClusterSpec{ClusterVersion: "1.12"}},
+			wantErr:    true,
+		},
+		{
+			name:       "ConstrainedClaimAllowed",
+			policy:     corecontroller.NewConstraintPolicy(constrainedClass(nil)),
+			classValue: "1.11",
+			claim:      &computev1alpha1.KubernetesCluster{Spec: computev1alpha1.KubernetesClusterSpec{ClusterVersion: "1.12.5"}},
+			want:       "1.12.5",
+		},
+		{
+			name:       "ConstrainedClaimUnset",
+			policy:     corecontroller.NewConstraintPolicy(constrainedClass(nil)),
+			classValue: "1.11",
+			claim:      &computev1alpha1.KubernetesCluster{},
+			want:       "1.11",
+		},
+		{
+			name:       "ConstrainedClaimNotAllowed",
+			policy:     corecontroller.NewConstraintPolicy(constrainedClass(nil)),
+			classValue: "1.11",
+			claim:      &computev1alpha1.KubernetesCluster{Spec: computev1alpha1.KubernetesClusterSpec{ClusterVersion: "1.10"}},
+			wantErr:    true,
+		},
+		{
+			name:    "UnexpectedClaim",
+			claim:   &storagev1alpha1.MySQLInstance{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			got, err := resolveClusterVersion(tc.policy, tc.classValue, tc.claim)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
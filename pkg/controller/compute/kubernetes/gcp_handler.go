@@ -17,6 +17,7 @@ limitations under the License.
 package kubernetes
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -26,23 +27,35 @@ import (
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 // A GKEClusterHandler handles Kubernetes cluster functionality
 type GKEClusterHandler struct{}
 
 // Find GKECluster resource
-func (r *GKEClusterHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (r *GKEClusterHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	instance := &gcpcomputev1alpha1.GKECluster{}
 	err := c.Get(ctx, name, instance)
 	return instance, err
 }
 
+// Match an existing, unbound GKECluster resource to the supplied claim.
+func (r *GKEClusterHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &gcpcomputev1alpha1.GKEClusterList{})
+}
+
 // Provision a new GKECluster
-func (r *GKEClusterHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (r *GKEClusterHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	// construct GKECluster Spec from class definition
 	resourceInstance := gcpcomputev1alpha1.NewGKEClusterSpec(class.Parameters)
 
+	version, err := resolveClusterVersion(corecontroller.NewConstraintPolicy(class), resourceInstance.ClusterVersion, claim)
+	if err != nil {
+		return nil, err
+	}
+	resourceInstance.ClusterVersion = version
+
 	// assign provider reference and reclaim policy from the resource class
 	resourceInstance.ProviderRef = class.ProviderRef
 	resourceInstance.ReclaimPolicy = class.ReclaimPolicy
@@ -61,13 +74,13 @@ func (r *GKEClusterHandler) Provision(class *corev1alpha1.ResourceClass, claim c
 		Spec: *resourceInstance,
 	}
 
-	err := c.Create(ctx, cluster)
+	err = c.Create(ctx, cluster)
 	return cluster, err
 }
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (r GKEClusterHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (r GKEClusterHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	instance := &gcpcomputev1alpha1.GKECluster{}
 	err := c.Get(ctx, name, instance)
 	if err != nil {
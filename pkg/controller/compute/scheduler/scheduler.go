@@ -18,7 +18,6 @@ package scheduler
 
 import (
 	"context"
-	"log"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +33,9 @@ import (
 
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -43,9 +45,8 @@ const (
 )
 
 var (
-	ctx           = context.Background()
-	resultDone    = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	log        = logging.Log.WithName(controllerName)
+	resultDone = reconcile.Result{}
 )
 
 // Add creates a new Instance Controller and adds it to the Manager with default RBAC.
@@ -59,9 +60,10 @@ type Reconciler struct {
 	client.Client
 	scheme           *runtime.Scheme
 	recorder         record.EventRecorder
+	requeue          *requeue.Policy
 	lastClusterIndex uint64
 
-	schedule func(*computev1alpha1.Workload) (reconcile.Result, error)
+	schedule func(context.Context, *computev1alpha1.Workload) (reconcile.Result, error)
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -70,6 +72,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		Client:   mgr.GetClient(),
 		scheme:   mgr.GetScheme(),
 		recorder: mgr.GetRecorder(controllerName),
+		requeue:  requeue.NewPolicy(controllerName, requeue.Defaults),
 	}
 	r.schedule = r._schedule
 	return r
@@ -87,7 +90,7 @@ func CreatePredicate(event event.CreateEvent) bool {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -97,24 +100,24 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 }
 
 // fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *computev1alpha1.Workload, reason, msg string) (reconcile.Result, error) {
-	log.Printf("%s: %s", reason, msg)
+func (r *Reconciler) fail(ctx context.Context, instance *computev1alpha1.Workload, reason, msg string) (reconcile.Result, error) {
+	log.Info(msg, "workload", instance.Namespace+"/"+instance.Name, "reason", reason)
 	instance.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
-	return resultRequeue, r.Status().Update(ctx, instance)
+	return r.requeue.Failed(instance, nil), r.Status().Update(ctx, instance)
 }
 
 // _schedule assigns Workload to a matching cluster. If the workload matches more than one cluster use
 // round-robin to select next cluster
-func (r *Reconciler) _schedule(instance *computev1alpha1.Workload) (reconcile.Result, error) {
+func (r *Reconciler) _schedule(ctx context.Context, instance *computev1alpha1.Workload) (reconcile.Result, error) {
 
 	clusters := &computev1alpha1.KubernetesClusterList{}
 
-	if err := r.List(context.Background(), client.MatchingLabels(instance.Spec.ClusterSelector), clusters); err != nil {
+	if err := r.List(ctx, client.MatchingLabels(instance.Spec.ClusterSelector), clusters); err != nil {
 		return resultDone, err
 	}
 
 	if len(clusters.Items) == 0 {
-		return r.fail(instance, errorUnschedulable, "Cannot match to any existing cluster")
+		return r.fail(ctx, instance, errorUnschedulable, "Cannot match to any existing cluster")
 	}
 
 	// round-robin cluster index selection
@@ -131,7 +134,7 @@ func (r *Reconciler) _schedule(instance *computev1alpha1.Workload) (reconcile.Re
 // Reconcile reads that state of the cluster for a Instance object and makes changes based on the state read
 // and what is in the Instance.Spec
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", computev1alpha1.WorkloadKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), log, request)
 	// fetch the CRD instance
 	instance := &computev1alpha1.Workload{}
 
@@ -140,13 +143,21 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.requeue.Forget(request.NamespacedName)
 			return resultDone, nil
 		}
 		return resultDone, err
 	}
 
+	logging.Reconciling(ctx, instance)
+
+	// Wait for a failed workload to back off before reconciling it again
+	if d := r.requeue.Wait(instance); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
 	if instance.Status.Cluster == nil {
-		return r.schedule(instance)
+		return r.schedule(ctx, instance)
 	}
 
 	return resultDone, nil
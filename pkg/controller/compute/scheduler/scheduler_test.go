@@ -17,6 +17,7 @@ limitations under the License
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/apis/compute"
 	. "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -41,6 +43,7 @@ const (
 )
 
 var (
+	ctx = context.Background()
 	key = types.NamespacedName{
 		Namespace: namespace,
 		Name:      workloadName,
@@ -48,6 +51,8 @@ var (
 	request = reconcile.Request{
 		NamespacedName: key,
 	}
+
+	resultFailed = reconcile.Result{RequeueAfter: requeue.Defaults.BackoffBase}
 )
 
 func init() {
@@ -85,7 +90,7 @@ func TestReconcile(t *testing.T) {
 	}
 
 	// successful scheduling
-	r.schedule = func(workload *Workload) (result reconcile.Result, e error) {
+	r.schedule = func(_ context.Context, workload *Workload) (result reconcile.Result, e error) {
 		return resultDone, nil
 	}
 	rs, err := r.Reconcile(request)
@@ -93,15 +98,15 @@ func TestReconcile(t *testing.T) {
 	g.Expect(rs).Should(Equal(resultDone))
 
 	// requeueing scheduling
-	r.schedule = func(workload *Workload) (result reconcile.Result, e error) {
-		return resultRequeue, nil
+	r.schedule = func(_ context.Context, workload *Workload) (result reconcile.Result, e error) {
+		return resultFailed, nil
 	}
 	rs, err = r.Reconcile(request)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	g.Expect(rs).Should(Equal(resultRequeue))
+	g.Expect(rs).Should(Equal(resultFailed))
 
 	// schedule error
-	r.schedule = func(workload *Workload) (result reconcile.Result, e error) {
+	r.schedule = func(_ context.Context, workload *Workload) (result reconcile.Result, e error) {
 		return resultDone, fmt.Errorf("test-error")
 	}
 	_, err = r.Reconcile(request)
@@ -128,9 +133,9 @@ func TestScheduleNoClusters(t *testing.T) {
 		Client: NewFakeClient(wl),
 	}
 
-	rs, err := r._schedule(wl)
+	rs, err := r._schedule(ctx, wl)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	g.Expect(rs).Should(Equal(resultRequeue))
+	g.Expect(rs).Should(Equal(resultFailed))
 	g.Expect(wl.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expStatus))
 }
 
@@ -146,7 +151,7 @@ func TestScheduleSingleClusterNoSelector(t *testing.T) {
 		Client: NewFakeClient(wl, cl),
 	}
 
-	rs, err := r._schedule(wl)
+	rs, err := r._schedule(ctx, wl)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(rs).Should(Equal(resultDone))
 	g.Expect(wl.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expStatus))
@@ -165,14 +170,14 @@ func TestScheduleRoundRobin(t *testing.T) {
 		Client: NewFakeClient(wl, clA, clB),
 	}
 
-	rs, err := r._schedule(wl)
+	rs, err := r._schedule(ctx, wl)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(rs).Should(Equal(resultDone))
 	g.Expect(wl.Status.Cluster).Should(Equal(clA.ObjectReference()))
 
 	// repeat scheduling and assert workload is scheduled on a different cluster
 	wl.Status.Cluster = nil
-	rs, err = r._schedule(wl)
+	rs, err = r._schedule(ctx, wl)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(rs).Should(Equal(resultDone))
 	g.Expect(wl.Status.Cluster).Should(Equal(clB.ObjectReference()))
@@ -204,13 +209,13 @@ func TestScheduleSelector(t *testing.T) {
 		Client: NewFakeClient(wl, clA, clB),
 	}
 
-	rs, err := r._schedule(wl)
+	rs, err := r._schedule(ctx, wl)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(rs).Should(Equal(resultDone))
 	g.Expect(wl.Status.Cluster).Should(Equal(clA.ObjectReference()))
 
 	wl.Status.Cluster = nil
-	rs, err = r._schedule(wl)
+	rs, err = r._schedule(ctx, wl)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(rs).Should(Equal(resultDone))
 	g.Expect(wl.Status.Cluster).Should(Equal(clA.ObjectReference()))
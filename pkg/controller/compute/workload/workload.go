@@ -19,7 +19,6 @@ package workload
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/url"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -41,6 +40,9 @@ import (
 
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
@@ -57,9 +59,8 @@ const (
 )
 
 var (
-	ctx           = context.Background()
-	resultDone    = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	log        = logging.Log.WithName(controllerName)
+	resultDone = reconcile.Result{}
 )
 
 // Add creates a new Instance Controller and adds it to the Manager with default RBAC.
@@ -74,11 +75,12 @@ type Reconciler struct {
 	scheme     *runtime.Scheme
 	kubeclient kubernetes.Interface
 	recorder   record.EventRecorder
+	requeue    *requeue.Policy
 
-	connect func(*computev1alpha1.Workload) (kubernetes.Interface, error)
-	create  func(*computev1alpha1.Workload, kubernetes.Interface) (reconcile.Result, error)
-	sync    func(*computev1alpha1.Workload, kubernetes.Interface) (reconcile.Result, error)
-	delete  func(*computev1alpha1.Workload, kubernetes.Interface) (reconcile.Result, error)
+	connect func(context.Context, *computev1alpha1.Workload) (kubernetes.Interface, error)
+	create  func(context.Context, *computev1alpha1.Workload, kubernetes.Interface) (reconcile.Result, error)
+	sync    func(context.Context, *computev1alpha1.Workload, kubernetes.Interface) (reconcile.Result, error)
+	delete  func(context.Context, *computev1alpha1.Workload, kubernetes.Interface) (reconcile.Result, error)
 
 	propagateDeployment func(kubernetes.Interface, *appsv1.Deployment, string, string) (*appsv1.Deployment, error)
 	propagateService    func(kubernetes.Interface, *corev1.Service, string, string) (*corev1.Service, error)
@@ -91,6 +93,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		scheme:              mgr.GetScheme(),
 		kubeclient:          kubernetes.NewForConfigOrDie(mgr.GetConfig()),
 		recorder:            mgr.GetRecorder(controllerName),
+		requeue:             requeue.NewPolicy(controllerName, requeue.Defaults),
 		propagateDeployment: propagateDeployment,
 		propagateService:    propagateService,
 	}
@@ -114,7 +117,7 @@ func CreatePredicate(event event.CreateEvent) bool {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -129,14 +132,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 }
 
 // fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *computev1alpha1.Workload, reason, msg string) (reconcile.Result, error) {
-	log.Printf("%s: %s", reason, msg)
+func (r *Reconciler) fail(ctx context.Context, instance *computev1alpha1.Workload, reason, msg string) (reconcile.Result, error) {
+	log.Info(msg, "workload", instance.Namespace+"/"+instance.Name, "reason", reason)
 	instance.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
-	return resultRequeue, r.Status().Update(ctx, instance)
+	return r.requeue.Failed(instance, nil), r.Status().Update(ctx, instance)
 }
 
 // _connect establish connection to the target cluster
-func (r *Reconciler) _connect(instance *computev1alpha1.Workload) (kubernetes.Interface, error) {
+func (r *Reconciler) _connect(ctx context.Context, instance *computev1alpha1.Workload) (kubernetes.Interface, error) {
 	ref := instance.Status.Cluster
 	if ref == nil {
 		return nil, fmt.Errorf("workload is not scheduled")
@@ -267,7 +270,7 @@ func propagateService(k kubernetes.Interface, s *corev1.Service, ns, uid string)
 }
 
 // _create workload
-func (r *Reconciler) _create(instance *computev1alpha1.Workload, client kubernetes.Interface) (reconcile.Result, error) {
+func (r *Reconciler) _create(ctx context.Context, instance *computev1alpha1.Workload, client kubernetes.Interface) (reconcile.Result, error) {
 	instance.Status.SetCreating()
 	util.AddFinalizer(&instance.ObjectMeta, finalizer)
 
@@ -276,7 +279,7 @@ func (r *Reconciler) _create(instance *computev1alpha1.Workload, client kubernet
 
 	_, err := client.CoreV1().Namespaces().Create(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: targetNamespace}})
 	if err != nil && !errors.IsAlreadyExists(err) {
-		return r.fail(instance, errorCreating, err.Error())
+		return r.fail(ctx, instance, errorCreating, err.Error())
 	}
 
 	uid := string(instance.UID)
@@ -287,7 +290,7 @@ func (r *Reconciler) _create(instance *computev1alpha1.Workload, client kubernet
 		secretName := util.IfEmptyString(resource.SecretName, resource.Name)
 		sec, err := r.kubeclient.CoreV1().Secrets(instance.Namespace).Get(secretName, metav1.GetOptions{})
 		if err != nil {
-			return r.fail(instance, errorCreating, err.Error())
+			return r.fail(ctx, instance, errorCreating, err.Error())
 		}
 
 		// create secret
@@ -298,21 +301,21 @@ func (r *Reconciler) _create(instance *computev1alpha1.Workload, client kubernet
 		addWorkloadReferenceLabel(&sec.ObjectMeta, uid)
 		_, err = util.ApplySecret(client, sec)
 		if err != nil {
-			return r.fail(instance, errorCreating, err.Error())
+			return r.fail(ctx, instance, errorCreating, err.Error())
 		}
 	}
 
 	// propagate deployment
 	d, err := r.propagateDeployment(client, instance.Spec.TargetDeployment, targetNamespace, uid)
 	if err != nil {
-		return r.fail(instance, errorCreating, err.Error())
+		return r.fail(ctx, instance, errorCreating, err.Error())
 	}
 	instance.Status.Deployment = util.ObjectReference(d.ObjectMeta, d.APIVersion, d.Kind)
 
 	// propagate service
 	s, err := r.propagateService(client, instance.Spec.TargetService, targetNamespace, uid)
 	if err != nil {
-		return r.fail(instance, errorCreating, err.Error())
+		return r.fail(ctx, instance, errorCreating, err.Error())
 	}
 	instance.Status.Service = util.ObjectReference(s.ObjectMeta, s.APIVersion, s.Kind)
 
@@ -323,20 +326,20 @@ func (r *Reconciler) _create(instance *computev1alpha1.Workload, client kubernet
 }
 
 // _sync Workload status
-func (r *Reconciler) _sync(instance *computev1alpha1.Workload, client kubernetes.Interface) (reconcile.Result, error) {
+func (r *Reconciler) _sync(ctx context.Context, instance *computev1alpha1.Workload, client kubernetes.Interface) (reconcile.Result, error) {
 	ns := instance.Spec.TargetNamespace
 
 	s := instance.Spec.TargetService
 	ss, err := client.CoreV1().Services(ns).Get(s.Name, metav1.GetOptions{})
 	if err != nil {
-		return r.fail(instance, errorSynchronizing, err.Error())
+		return r.fail(ctx, instance, errorSynchronizing, err.Error())
 	}
 	instance.Status.ServiceStatus = ss.Status
 
 	d := instance.Spec.TargetDeployment
 	dd, err := client.AppsV1().Deployments(ns).Get(d.Name, metav1.GetOptions{})
 	if err != nil {
-		return r.fail(instance, errorSynchronizing, err.Error())
+		return r.fail(ctx, instance, errorSynchronizing, err.Error())
 	}
 	instance.Status.DeploymentStatus = dd.Status
 
@@ -347,24 +350,24 @@ func (r *Reconciler) _sync(instance *computev1alpha1.Workload, client kubernetes
 		return resultDone, r.Status().Update(ctx, instance)
 	}
 
-	return resultRequeue, r.Status().Update(ctx, instance)
+	return r.requeue.Pending(requeue.Key(instance)), r.Status().Update(ctx, instance)
 }
 
 // _delete workload
-func (r *Reconciler) _delete(instance *computev1alpha1.Workload, client kubernetes.Interface) (reconcile.Result, error) {
+func (r *Reconciler) _delete(ctx context.Context, instance *computev1alpha1.Workload, client kubernetes.Interface) (reconcile.Result, error) {
 	ns := instance.Spec.TargetNamespace
 
 	// delete service
 	if s := instance.Status.Service; s != nil {
 		if err := client.CoreV1().Services(s.Namespace).Delete(s.Name, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-			return r.fail(instance, errorDeleting, err.Error())
+			return r.fail(ctx, instance, errorDeleting, err.Error())
 		}
 	}
 
 	// delete deployment
 	if d := instance.Status.Deployment; d != nil {
 		if err := client.AppsV1().Deployments(d.Namespace).Delete(d.Name, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-			return r.fail(instance, errorDeleting, err.Error())
+			return r.fail(ctx, instance, errorDeleting, err.Error())
 		}
 	}
 
@@ -372,7 +375,7 @@ func (r *Reconciler) _delete(instance *computev1alpha1.Workload, client kubernet
 	for _, resource := range instance.Spec.Resources {
 		secretName := util.IfEmptyString(resource.SecretName, resource.Name)
 		if err := client.CoreV1().Secrets(ns).Delete(secretName, &metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-			return r.fail(instance, errorDeleting, err.Error())
+			return r.fail(ctx, instance, errorDeleting, err.Error())
 		}
 	}
 
@@ -384,7 +387,7 @@ func (r *Reconciler) _delete(instance *computev1alpha1.Workload, client kubernet
 // Reconcile reads that state of the cluster for a Instance object and makes changes based on the state read
 // and what is in the Instance.Spec
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", computev1alpha1.WorkloadKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), log, request)
 	// fetch the CRD instance
 	instance := &computev1alpha1.Workload{}
 
@@ -393,29 +396,37 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.requeue.Forget(request.NamespacedName)
 			return resultDone, nil
 		}
 		return resultDone, err
 	}
 
+	logging.Reconciling(ctx, instance)
+
 	if instance.Status.Cluster == nil {
 		return resultDone, nil
 	}
 
+	// Wait for a failed workload to back off before reconciling it again
+	if d := r.requeue.Wait(instance); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
 	// target cluster client
-	targetClient, err := r.connect(instance)
+	targetClient, err := r.connect(ctx, instance)
 	if err != nil {
-		return r.fail(instance, errorClusterClient, err.Error())
+		return r.fail(ctx, instance, errorClusterClient, err.Error())
 	}
 
 	// Check for deletion
 	if instance.DeletionTimestamp != nil && instance.Status.Condition(corev1alpha1.Deleting) == nil {
-		return r.delete(instance, targetClient)
+		return r.delete(ctx, instance, targetClient)
 	}
 
 	if instance.Status.State == "" {
-		return r.create(instance, targetClient)
+		return r.create(ctx, instance, targetClient)
 	}
 
-	return r.sync(instance, targetClient)
+	return r.sync(ctx, instance, targetClient)
 }
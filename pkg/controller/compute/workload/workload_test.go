@@ -17,6 +17,7 @@ limitations under the License.
 package workload
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -39,6 +40,7 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/apis/compute"
 	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -47,6 +49,7 @@ const (
 )
 
 var (
+	ctx = context.Background()
 	key = types.NamespacedName{
 		Namespace: namespace,
 		Name:      name,
@@ -58,6 +61,8 @@ var (
 		Name:      name,
 		Namespace: namespace,
 	}
+
+	resultFailed = reconcile.Result{RequeueAfter: requeue.Defaults.BackoffBase}
 )
 
 func init() {
@@ -146,13 +151,13 @@ func TestReconcileClientError(t *testing.T) {
 	expCondition.SetFailed(errorClusterClient, testError)
 	r := &Reconciler{
 		Client: fake.NewFakeClient(w),
-		connect: func(*computev1alpha1.Workload) (i kubernetes.Interface, e error) {
+		connect: func(context.Context, *computev1alpha1.Workload) (i kubernetes.Interface, e error) {
 			return nil, fmt.Errorf(testError)
 		},
 	}
 	rs, err := r.Reconcile(request)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	g.Expect(rs).Should(Equal(resultRequeue))
+	g.Expect(rs).Should(Equal(resultFailed))
 	g.Expect(r.Get(ctx, key, w)).ShouldNot(HaveOccurred())
 	g.Expect(w.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expCondition))
 }
@@ -166,8 +171,8 @@ func TestReconcileDelete(t *testing.T) {
 	w.Status.Cluster = &corev1.ObjectReference{}
 	r := &Reconciler{
 		Client:  fake.NewFakeClient(w),
-		connect: func(*computev1alpha1.Workload) (i kubernetes.Interface, e error) { return nil, nil },
-		delete: func(workload *computev1alpha1.Workload, i kubernetes.Interface) (result reconcile.Result, e error) {
+		connect: func(context.Context, *computev1alpha1.Workload) (i kubernetes.Interface, e error) { return nil, nil },
+		delete: func(_ context.Context, workload *computev1alpha1.Workload, i kubernetes.Interface) (result reconcile.Result, e error) {
 			return resultDone, nil
 		},
 	}
@@ -183,8 +188,8 @@ func TestReconcileCreate(t *testing.T) {
 	w.Status.Cluster = &corev1.ObjectReference{}
 	r := &Reconciler{
 		Client:  fake.NewFakeClient(w),
-		connect: func(*computev1alpha1.Workload) (i kubernetes.Interface, e error) { return nil, nil },
-		create: func(workload *computev1alpha1.Workload, i kubernetes.Interface) (result reconcile.Result, e error) {
+		connect: func(context.Context, *computev1alpha1.Workload) (i kubernetes.Interface, e error) { return nil, nil },
+		create: func(_ context.Context, workload *computev1alpha1.Workload, i kubernetes.Interface) (result reconcile.Result, e error) {
 			return resultDone, nil
 		},
 	}
@@ -201,8 +206,8 @@ func TestReconcileSync(t *testing.T) {
 	w.Status.State = computev1alpha1.WorkloadStateRunning
 	r := &Reconciler{
 		Client:  fake.NewFakeClient(w),
-		connect: func(*computev1alpha1.Workload) (i kubernetes.Interface, e error) { return nil, nil },
-		sync: func(workload *computev1alpha1.Workload, i kubernetes.Interface) (result reconcile.Result, e error) {
+		connect: func(context.Context, *computev1alpha1.Workload) (i kubernetes.Interface, e error) { return nil, nil },
+		sync: func(_ context.Context, workload *computev1alpha1.Workload, i kubernetes.Interface) (result reconcile.Result, e error) {
 			return resultDone, nil
 		},
 	}
@@ -219,7 +224,7 @@ func TestConnectNotScheduled(t *testing.T) {
 		Client: fake.NewFakeClient(w),
 	}
 
-	_, err := r._connect(w)
+	_, err := r._connect(ctx, w)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err).Should(MatchError("workload is not scheduled"))
 }
@@ -234,7 +239,7 @@ func TestConnectNoCluster(t *testing.T) {
 		Client: fake.NewFakeClient(w),
 	}
 
-	_, err := r._connect(w)
+	_, err := r._connect(ctx, w)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err).Should(MatchError("kubernetesclusters.compute.crossplane.io \"test\" not found"))
 }
@@ -250,7 +255,7 @@ func TestConnectNoClusterSecret(t *testing.T) {
 	}
 	w.Status.Cluster = c.ObjectReference()
 
-	_, err := r._connect(w)
+	_, err := r._connect(ctx, w)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err).Should(MatchError("secrets \"test\" not found"))
 }
@@ -270,7 +275,7 @@ func TestConnectNoClusterSecretHostValue(t *testing.T) {
 	g.Expect(r.Client.Get(ctx, key, c)).ShouldNot(HaveOccurred())
 	w.Status.Cluster = c.ObjectReference()
 
-	_, err := r._connect(w)
+	_, err := r._connect(ctx, w)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err).Should(MatchError("kubernetes cluster endpoint/host is not found"))
 }
@@ -292,7 +297,7 @@ func TestConnectInvalidSecretHostValue(t *testing.T) {
 	g.Expect(r.Client.Get(ctx, key, c)).ShouldNot(HaveOccurred())
 	w.Status.Cluster = c.ObjectReference()
 
-	_, err := r._connect(w)
+	_, err := r._connect(ctx, w)
 	g.Expect(err).Should(HaveOccurred())
 	g.Expect(err).Should(MatchError("cannot parse Kubernetes endpoint as URL: parse foo :bar: first path segment in URL cannot contain colon"))
 }
@@ -314,7 +319,7 @@ func TestConnect(t *testing.T) {
 	g.Expect(r.Client.Get(ctx, key, c)).ShouldNot(HaveOccurred())
 	w.Status.Cluster = c.ObjectReference()
 
-	k, err := r._connect(w)
+	k, err := r._connect(ctx, w)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(k).ShouldNot(BeNil())
 }
@@ -530,7 +535,7 @@ func Test_create(t *testing.T) {
 	expStatus := tw.Status.ConditionedStatus
 	expStatus.SetCreating()
 
-	rs, err := r._create(tw, client)
+	rs, err := r._create(ctx, tw, client)
 	g.Expect(err).ShouldNot(HaveOccurred())
 	g.Expect(rs).Should(Equal(resultDone))
 	g.Expect(tw.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expStatus))
@@ -555,9 +560,9 @@ func Test_create_Failures(t *testing.T) {
 	r := &Reconciler{
 		Client: fake.NewFakeClient(tw),
 	}
-	rs, err := r._create(tw, client)
+	rs, err := r._create(ctx, tw, client)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	g.Expect(rs).Should(Equal(resultRequeue))
+	g.Expect(rs).Should(Equal(resultFailed))
 	g.Expect(tw.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expStatus))
 	client.ReactionChain = client.ReactionChain[:0]
 
@@ -572,9 +577,9 @@ func Test_create_Failures(t *testing.T) {
 
 	expStatus.SetFailed(errorCreating, testError)
 
-	rs, err = r._create(tw, client)
+	rs, err = r._create(ctx, tw, client)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	g.Expect(rs).Should(Equal(resultRequeue))
+	g.Expect(rs).Should(Equal(resultFailed))
 	g.Expect(tw.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expStatus))
 
 	// Service propagation failure
@@ -587,8 +592,8 @@ func Test_create_Failures(t *testing.T) {
 	}
 	expStatus.SetFailed(errorCreating, testError)
 
-	rs, err = r._create(tw, client)
+	rs, err = r._create(ctx, tw, client)
 	g.Expect(err).ShouldNot(HaveOccurred())
-	g.Expect(rs).Should(Equal(resultRequeue))
+	g.Expect(rs).Should(Equal(resultFailed))
 	g.Expect(tw.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(expStatus))
 }
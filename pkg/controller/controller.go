@@ -23,8 +23,10 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/controller/azure"
 	"github.com/crossplaneio/crossplane/pkg/controller/cache"
 	"github.com/crossplaneio/crossplane/pkg/controller/compute"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/controller/gcp"
 	"github.com/crossplaneio/crossplane/pkg/controller/storage"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
@@ -34,18 +36,22 @@ func init() {
 		azure.AddToManager,
 		cache.AddToManager,
 		compute.AddToManager,
+		func(m manager.Manager, _ selection.Selection) error { return core.AddExternalResources(m) },
+		func(m manager.Manager, _ selection.Selection) error { return core.AddSecretDefinitions(m) },
 		gcp.AddToManager,
 		storage.AddToManager,
 	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
+// AddToManagerFuncs is a list of functions to add the selected Controllers to
+// the Manager
+var AddToManagerFuncs []func(manager.Manager, selection.Selection) error
 
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
+// AddToManager adds the Controllers of the selected cloud providers and kinds
+// of resources to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
 	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
+		if err := f(m, sel); err != nil {
 			return err
 		}
 	}
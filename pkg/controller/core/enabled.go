@@ -0,0 +1,47 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// enabledHandlers returns the supplied handlers whose kinds of resources are
+// registered with the supplied scheme. The API groups of cloud providers and
+// kinds of resources that are not selected are not registered, so claims of
+// classes that use their provisioners are ignored as if no handler existed.
+func enabledHandlers(s *runtime.Scheme, handlers map[string]ResourceHandler) map[string]ResourceHandler {
+	enabled := make(map[string]ResourceHandler, len(handlers))
+	for provisioner, h := range handlers {
+		if _, err := resourceKind(s, provisioner); err == nil {
+			enabled[provisioner] = h
+		}
+	}
+	return enabled
+}
+
+// enabledResources returns the supplied resources whose types are registered
+// with the supplied scheme.
+func enabledResources(s *runtime.Scheme, resources []runtime.Object) []runtime.Object {
+	enabled := make([]runtime.Object, 0, len(resources))
+	for _, r := range resources {
+		if _, _, err := s.ObjectKinds(r); err == nil {
+			enabled = append(enabled, r)
+		}
+	}
+	return enabled
+}
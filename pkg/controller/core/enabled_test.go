@@ -0,0 +1,55 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+type unregisteredResource struct{ corev1.Secret }
+
+func TestEnabledHandlers(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	h := &MockResourceHandler{}
+	handlers := map[string]ResourceHandler{
+		"resourceclass.core.crossplane.io/v1alpha1":       h,
+		"rdsinstance.database.aws.crossplane.io/v1alpha1": h,
+	}
+
+	// test: handlers of kinds that are not registered with the scheme are ignored
+	g.Expect(enabledHandlers(scheme.Scheme, handlers)).To(Equal(map[string]ResourceHandler{
+		"resourceclass.core.crossplane.io/v1alpha1": h,
+	}))
+}
+
+func TestEnabledResources(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	class := &corev1alpha1.ResourceClass{}
+	resources := []runtime.Object{class, &unregisteredResource{}}
+
+	// test: resources whose types are not registered with the scheme are ignored
+	g.Expect(enabledResources(scheme.Scheme, resources)).To(Equal([]runtime.Object{class}))
+}
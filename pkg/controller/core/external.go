@@ -0,0 +1,416 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients/external"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
+	"github.com/crossplaneio/crossplane/pkg/util"
+)
+
+const (
+	externalResourceControllerName = "externalresources.core.crossplane.io"
+	externalResourceFinalizer      = "finalizer." + externalResourceControllerName
+
+	errorExternalProvisionerClient = "Failed to create external provisioner client"
+	errorExternalProvision         = "Failed to provision external resource"
+	errorExternalSync              = "Failed to sync external resource state"
+	errorExternalDelete            = "Failed to delete external resource"
+
+	planExternalProvision = "Would provision external resource"
+	planExternalDelete    = "Would delete external resource"
+)
+
+// externalClients are shared by the ExternalResource handler and controller,
+// so that the connections to each external provisioner are reused.
+var externalClients = external.NewClientCache()
+
+// ExternalResourceHandler handles the resources of resource classes that
+// configure an external provisioner. Resources are represented by
+// ExternalResources, which are provisioned and deleted by the external
+// provisioner at the request of the ExternalResource controller.
+type ExternalResourceHandler struct {
+	connect func(*corev1alpha1.ExternalProvisioner) (external.Client, error)
+	dryRun  bool
+}
+
+// NewExternalResourceHandler returns a handler of the resources of external
+// provisioners.
+func NewExternalResourceHandler() *ExternalResourceHandler {
+	return &ExternalResourceHandler{connect: externalClients.Get, dryRun: DryRun}
+}
+
+// Find ExternalResource
+func (h *ExternalResourceHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+	res := &corev1alpha1.ExternalResource{}
+	err := c.Get(ctx, name, res)
+	return res, err
+}
+
+// Match an existing, unbound ExternalResource of the provisioner of the
+// supplied class to the supplied claim.
+func (h *ExternalResourceHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return MatchUnboundResource(ctx, &provisionerClient{Client: c, provisioner: class.Provisioner}, class.Namespace, claim, &corev1alpha1.ExternalResourceList{})
+}
+
+// provisionerClient lists only the ExternalResources of a provisioner.
+type provisionerClient struct {
+	client.Client
+	provisioner string
+}
+
+func (c *provisionerClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	if err := c.Client.List(ctx, opts, list); err != nil {
+		return err
+	}
+	l, ok := list.(*corev1alpha1.ExternalResourceList)
+	if !ok {
+		return nil
+	}
+	items := l.Items[:0]
+	for _, res := range l.Items {
+		if strings.EqualFold(res.Spec.Provisioner, c.provisioner) {
+			items = append(items, res)
+		}
+	}
+	l.Items = items
+	return nil
+}
+
+// Provision creates a new ExternalResource, which is provisioned by the
+// external provisioner of the supplied class.
+func (h *ExternalResourceHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	if class.ExternalProvisioner == nil {
+		return nil, fmt.Errorf("resource class %s/%s does not configure an external provisioner", class.Namespace, class.Name)
+	}
+
+	res := &corev1alpha1.ExternalResource{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: corev1alpha1.APIVersion,
+			Kind:       corev1alpha1.ExternalResourceKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       class.Namespace,
+			Name:            externalResourceName(class.Provisioner, claim.GetUID()),
+			OwnerReferences: []metav1.OwnerReference{claim.OwnerReference()},
+		},
+		Spec: corev1alpha1.ExternalResourceSpec{
+			Provisioner:         class.Provisioner,
+			ExternalProvisioner: *class.ExternalProvisioner.DeepCopy(),
+			Parameters:          class.Parameters,
+			ClassRef:            class.ObjectReference(),
+			ClaimRef:            claim.ObjectReference(),
+			ReclaimPolicy:       class.ReclaimPolicy,
+		},
+	}
+
+	err := c.Create(ctx, res)
+	return res, err
+}
+
+// SetBindStatus updates the binding phase of the ExternalResource and
+// notifies its external provisioner, unless the ExternalResource is reconciled
+// in dry-run mode.
+func (h *ExternalResourceHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
+	res := &corev1alpha1.ExternalResource{}
+	if err := c.Get(ctx, name, res); err != nil {
+		if errors.IsNotFound(err) && !bound {
+			return nil
+		}
+		return err
+	}
+
+	if !h.dryRun && !corev1alpha1.IsDryRun(res) {
+		p, err := h.connect(&res.Spec.ExternalProvisioner)
+		if err != nil {
+			return err
+		}
+		req := externalRequest(ctx, res)
+		req.Bound = bound
+		if err := p.SetBindStatus(req); err != nil {
+			return err
+		}
+	}
+
+	res.Status.SetBound(bound)
+	return c.Update(ctx, res)
+}
+
+// externalResourceName returns the name of the ExternalResource provisioned
+// for the supplied claim UID, prefixed with the kind of the supplied
+// provisioner, e.g. "kafkatopic-<uid>".
+func externalResourceName(provisioner string, uid types.UID) string {
+	kind := strings.ToLower(strings.SplitN(provisioner, ".", 2)[0])
+	return fmt.Sprintf("%s-%s", kind, uid)
+}
+
+// isExternalResourceRef returns true if the supplied reference refers to an
+// ExternalResource.
+func isExternalResourceRef(ref *corev1.ObjectReference) bool {
+	return ref != nil && ref.APIVersion == corev1alpha1.APIVersion && strings.EqualFold(ref.Kind, corev1alpha1.ExternalResourceKind)
+}
+
+// externalRequest returns a protocol request for the supplied resource, made
+// by the reconcile the supplied context was created for.
+func externalRequest(ctx context.Context, res *corev1alpha1.ExternalResource) *external.Request {
+	return &external.Request{
+		CorrelationID: logging.CorrelationID(ctx),
+		Namespace:     res.Namespace,
+		Name:          res.Name,
+		Provisioner:   res.Spec.Provisioner,
+		Parameters:    res.Spec.Parameters,
+		ClaimRef:      res.Spec.ClaimRef,
+		ReclaimPolicy: res.Spec.ReclaimPolicy,
+	}
+}
+
+// ExternalResourceReconciler asks external provisioners to provision and
+// delete ExternalResources, and keeps their status and connection secrets in
+// sync with the state reported by their provisioner.
+type ExternalResourceReconciler struct {
+	client.Client
+	kubeclient kubernetes.Interface
+	recorder   record.EventRecorder
+	requeue    *requeue.Policy
+	log        logr.Logger
+	dryRun     bool
+
+	connect func(*corev1alpha1.ExternalProvisioner) (external.Client, error)
+	create  func(context.Context, *corev1alpha1.ExternalResource, external.Client) (reconcile.Result, error)
+	sync    func(context.Context, *corev1alpha1.ExternalResource, external.Client) (reconcile.Result, error)
+	delete  func(context.Context, *corev1alpha1.ExternalResource, external.Client) (reconcile.Result, error)
+}
+
+// NewExternalResourceReconciler returns a new ExternalResourceReconciler.
+func NewExternalResourceReconciler(mgr manager.Manager) *ExternalResourceReconciler {
+	r := &ExternalResourceReconciler{
+		Client:     mgr.GetClient(),
+		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+		recorder:   mgr.GetRecorder(externalResourceControllerName),
+		requeue:    requeue.NewPolicy(externalResourceControllerName, requeue.Defaults),
+		log:        logging.Log.WithName(externalResourceControllerName),
+		dryRun:     DryRun,
+		connect:    externalClients.Get,
+	}
+	r.create = r._create
+	r.sync = r._sync
+	r.delete = r._delete
+	return r
+}
+
+// AddExternalResources creates a new ExternalResource controller and adds it
+// to the manager.
+func AddExternalResources(mgr manager.Manager) error {
+	c, err := controller.New(externalResourceControllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(externalResourceControllerName, NewExternalResourceReconciler(mgr))})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Kind{Type: &corev1alpha1.ExternalResource{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &corev1alpha1.ExternalResource{},
+	})
+}
+
+// Reconcile the requested ExternalResource with the state reported by its
+// external provisioner.
+func (r *ExternalResourceReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := logging.NewContext(context.Background(), r.log, request)
+
+	res := &corev1alpha1.ExternalResource{}
+	if err := r.Get(ctx, request.NamespacedName, res); err != nil {
+		if errors.IsNotFound(err) {
+			r.requeue.Forget(request.NamespacedName)
+			return Result, nil
+		}
+		return Result, err
+	}
+
+	logging.Reconciling(ctx, res)
+
+	if d := r.requeue.Wait(res); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
+	p, err := r.connect(&res.Spec.ExternalProvisioner)
+	if err != nil {
+		return r.fail(ctx, res, errorExternalProvisionerClient, err.Error())
+	}
+
+	if res.DeletionTimestamp != nil {
+		return r.delete(ctx, res, p)
+	}
+
+	if !util.HasFinalizer(&res.ObjectMeta, externalResourceFinalizer) {
+		return r.create(ctx, res, p)
+	}
+
+	return r.sync(ctx, res, p)
+}
+
+// _create asks the external provisioner to provision the resource.
+func (r *ExternalResourceReconciler) _create(ctx context.Context, res *corev1alpha1.ExternalResource, p external.Client) (reconcile.Result, error) {
+	if r.isDryRun(res) {
+		return r.plan(ctx, res, planExternalProvision, "external resource would be provisioned")
+	}
+
+	rsp, err := p.Provision(externalRequest(ctx, res))
+	if err != nil {
+		return r.fail(ctx, res, errorExternalProvision, err.Error())
+	}
+
+	util.AddFinalizer(&res.ObjectMeta, externalResourceFinalizer)
+	setExternalStatus(res, rsp)
+	res.Status.UnsetAllConditions()
+	res.Status.SetCreating()
+	return r.requeue.Pending(requeue.Key(res)), r.Update(ctx, res)
+}
+
+// _sync the status and connection secret of the resource with the state
+// reported by the external provisioner.
+func (r *ExternalResourceReconciler) _sync(ctx context.Context, res *corev1alpha1.ExternalResource, p external.Client) (reconcile.Result, error) {
+	rsp, err := p.Find(externalRequest(ctx, res))
+	if err != nil {
+		return r.fail(ctx, res, errorExternalSync, err.Error())
+	}
+	setExternalStatus(res, rsp)
+
+	res.Status.UnsetAllConditions()
+	switch rsp.State {
+	case corev1alpha1.ExternalResourceStateCreating:
+		res.Status.SetCreating()
+		return r.requeue.Pending(requeue.Key(res)), r.Update(ctx, res)
+	case corev1alpha1.ExternalResourceStateFailed:
+		res.Status.SetFailed(errorExternalSync, rsp.Message)
+		return Result, r.Update(ctx, res)
+	case corev1alpha1.ExternalResourceStateAvailable:
+		metrics.ObserveReady(res, &res.Status.ConditionedStatus)
+		res.Status.SetReady()
+		r.requeue.Forget(requeue.Key(res))
+	default:
+		return r.fail(ctx, res, errorExternalSync, fmt.Sprintf("unexpected resource state: %s", rsp.State))
+	}
+
+	if _, err := util.ApplySecret(r.kubeclient, externalConnectionSecret(res, rsp)); err != nil {
+		return r.fail(ctx, res, errorExternalSync, err.Error())
+	}
+
+	return Result, r.Update(ctx, res)
+}
+
+// _delete asks the external provisioner to delete the resource, unless it is
+// to be retained. Resources are retained in dry-run mode.
+func (r *ExternalResourceReconciler) _delete(ctx context.Context, res *corev1alpha1.ExternalResource, p external.Client) (reconcile.Result, error) {
+	if res.Spec.ReclaimPolicy != corev1alpha1.ReclaimRetain && util.HasFinalizer(&res.ObjectMeta, externalResourceFinalizer) {
+		if r.isDryRun(res) {
+			msg := "external resource would be deleted, and is retained"
+			logging.FromContext(ctx).Info(msg, "reason", planExternalDelete)
+			logging.RecordEvent(ctx, r.recorder, res, corev1.EventTypeNormal, planExternalDelete, msg)
+		} else if err := p.Delete(externalRequest(ctx, res)); err != nil && !external.IsErrorNotFound(err) {
+			return r.fail(ctx, res, errorExternalDelete, err.Error())
+		}
+	}
+
+	res.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Deleting, "", ""))
+	util.RemoveFinalizer(&res.ObjectMeta, externalResourceFinalizer)
+	return Result, r.Update(ctx, res)
+}
+
+// isDryRun returns true if the supplied resource is reconciled in dry-run
+// mode, i.e. its external provisioner must not be asked to change it.
+func (r *ExternalResourceReconciler) isDryRun(res *corev1alpha1.ExternalResource) bool {
+	return r.dryRun || corev1alpha1.IsDryRun(res)
+}
+
+// plan records the change that would be made to the supplied resource in
+// dry-run mode, without asking its external provisioner to make it. An event
+// is recorded only when the planned change differs from the last one.
+func (r *ExternalResourceReconciler) plan(ctx context.Context, res *corev1alpha1.ExternalResource, reason, message string) (reconcile.Result, error) {
+	c := corev1alpha1.NewCondition(corev1alpha1.DryRun, reason, message)
+	if current := res.Status.Condition(corev1alpha1.DryRun); current == nil || !current.Equal(c) {
+		logging.FromContext(ctx).Info(message, "reason", reason)
+		logging.RecordEvent(ctx, r.recorder, res, corev1.EventTypeNormal, reason, message)
+		res.Status.SetCondition(c)
+	}
+	r.requeue.Forget(requeue.Key(res))
+	return Result, r.Update(ctx, res)
+}
+
+// fail - helper function to set fail condition with reason and message
+func (r *ExternalResourceReconciler) fail(ctx context.Context, res *corev1alpha1.ExternalResource, reason, msg string) (reconcile.Result, error) {
+	logging.RecordEvent(ctx, r.recorder, res, corev1.EventTypeWarning, reason, msg)
+	res.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
+	return r.requeue.Failed(res, nil), r.Update(ctx, res)
+}
+
+// setExternalStatus records the state reported by an external provisioner in
+// the status of the supplied resource.
+func setExternalStatus(res *corev1alpha1.ExternalResource, rsp *external.Response) {
+	res.Status.State = rsp.State
+	res.Status.Message = rsp.Message
+	res.Status.ProviderID = rsp.ProviderID
+	res.Status.Endpoint = rsp.Endpoint
+}
+
+// externalConnectionSecret returns the connection secret of the supplied
+// resource, containing the connection details reported by its external
+// provisioner.
+func externalConnectionSecret(res *corev1alpha1.ExternalResource, rsp *external.Response) *corev1.Secret {
+	data := map[string][]byte{}
+	for k, v := range rsp.ConnectionSecret {
+		data[k] = v
+	}
+	if _, ok := data[corev1alpha1.ResourceCredentialsSecretEndpointKey]; !ok && rsp.Endpoint != "" {
+		data[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(rsp.Endpoint)
+	}
+
+	ref := metav1.NewControllerRef(res, corev1alpha1.SchemeGroupVersion.WithKind("ExternalResource"))
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       res.Namespace,
+			Name:            res.ConnectionSecretName(),
+			OwnerReferences: []metav1.OwnerReference{*ref},
+		},
+		Data: data,
+	}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients/external"
+	externalfake "github.com/crossplaneio/crossplane/pkg/clients/external/fake"
+	"github.com/crossplaneio/crossplane/pkg/util"
+)
+
+const externalProvisioner = "kafkatopic.messaging.example.org/v1"
+
+func testExternalClass() *corev1alpha1.ResourceClass {
+	return &corev1alpha1.ResourceClass{
+		ObjectMeta:          metav1.ObjectMeta{Namespace: "system", Name: "kafka"},
+		Provisioner:         externalProvisioner,
+		Parameters:          map[string]string{"partitions": "3"},
+		ReclaimPolicy:       corev1alpha1.ReclaimDelete,
+		ExternalProvisioner: &corev1alpha1.ExternalProvisioner{URL: "https://kafka-provisioner.infra.svc"},
+	}
+}
+
+func testExternalResource() *corev1alpha1.ExternalResource {
+	return &corev1alpha1.ExternalResource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: corev1alpha1.ExternalResourceSpec{
+			Provisioner:         externalProvisioner,
+			ExternalProvisioner: corev1alpha1.ExternalProvisioner{URL: "https://kafka-provisioner.infra.svc"},
+			ReclaimPolicy:       corev1alpha1.ReclaimDelete,
+		},
+	}
+}
+
+func TestGetHandlerExternal(t *testing.T) {
+	g := NewGomegaWithT(t)
+	mc := &MockClient{}
+	r := Reconciler{Client: mc, handlers: map[string]ResourceHandler{}}
+	claim := testClaim()
+	claim.Spec.ClassRef = &corev1.ObjectReference{Namespace: "system", Name: "kafka"}
+
+	// test: the class configures an external provisioner
+	mc.MockGet = func(args ...interface{}) error {
+		testExternalClass().DeepCopyInto(args[2].(*corev1alpha1.ResourceClass))
+		return nil
+	}
+	h, err := r._getHandler(ctx, claim)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(h).To(BeAssignableToTypeOf(&ExternalResourceHandler{}))
+
+	// test: the claim is bound to an external resource, the class is not needed
+	claim.ClaimStatus().Provisioner = externalProvisioner
+	claim.SetResourceRef((&corev1alpha1.ExternalResource{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}).ObjectReference())
+	mc.MockGet = nil
+	h, err = r._getHandler(ctx, claim)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(h).To(BeAssignableToTypeOf(&ExternalResourceHandler{}))
+}
+
+func TestExternalResourceHandlerProvision(t *testing.T) {
+	g := NewGomegaWithT(t)
+	h := NewExternalResourceHandler()
+	claim := testClaim()
+	claim.SetUID("123")
+
+	var got *corev1alpha1.ExternalResource
+	c := &MockClient{MockCreate: func(args ...interface{}) error {
+		got = args[1].(*corev1alpha1.ExternalResource)
+		return nil
+	}}
+
+	// test: the class does not configure an external provisioner
+	class := testExternalClass()
+	class.ExternalProvisioner = nil
+	_, err := h.Provision(ctx, class, claim, c)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(got).To(BeNil())
+
+	// test: an external resource is created for the claim
+	res, err := h.Provision(ctx, testExternalClass(), claim, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(got).NotTo(BeNil())
+	g.Expect(got.Namespace).To(Equal("system"))
+	g.Expect(got.Name).To(Equal("kafkatopic-123"))
+	g.Expect(res.ObjectReference().Name).To(Equal(got.Name))
+	g.Expect(got.Spec.Provisioner).To(Equal(externalProvisioner))
+	g.Expect(got.Spec.ExternalProvisioner.URL).To(Equal("https://kafka-provisioner.infra.svc"))
+	g.Expect(got.Spec.Parameters).To(Equal(map[string]string{"partitions": "3"}))
+	g.Expect(got.Spec.ReclaimPolicy).To(Equal(corev1alpha1.ReclaimDelete))
+	g.Expect(got.Spec.ClaimRef).To(Equal(claim.ObjectReference()))
+	g.Expect(got.OwnerReferences).To(Equal([]metav1.OwnerReference{claim.OwnerReference()}))
+}
+
+func TestExternalResourceHandlerMatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+	h := NewExternalResourceHandler()
+	claim := testClaim()
+	claim.Spec.Selector = metav1.LabelSelector{MatchLabels: map[string]string{"team": "data"}}
+
+	other := testExternalResource()
+	other.Namespace = "system"
+	other.Name = "other"
+	other.Labels = map[string]string{"team": "data"}
+	other.Spec.Provisioner = "onpremdb.database.example.org/v1"
+	other.Status.State = corev1alpha1.ExternalResourceStateAvailable
+
+	// test: resources of other provisioners are not matched
+	c := fake.NewFakeClient(other)
+	res, err := h.Match(ctx, testExternalClass(), claim, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).To(BeNil())
+
+	// test: resources of the provisioner of the class are matched
+	matching := other.DeepCopy()
+	matching.Name = "matching"
+	matching.Spec.Provisioner = externalProvisioner
+	c = fake.NewFakeClient(other, matching)
+	res, err = h.Match(ctx, testExternalClass(), claim, c)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(res).NotTo(BeNil())
+	g.Expect(res.ObjectReference().Name).To(Equal("matching"))
+}
+
+func TestExternalResourceHandlerSetBindStatus(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+
+	var got *external.Request
+	p := &externalfake.MockClient{
+		MockSetBindStatus: func(req *external.Request) error {
+			got = req
+			return nil
+		},
+	}
+	h := &ExternalResourceHandler{connect: func(*corev1alpha1.ExternalProvisioner) (external.Client, error) { return p, nil }}
+
+	// test: unbinding a resource that does not exist
+	g.Expect(h.SetBindStatus(ctx, nn, fake.NewFakeClient(), false)).To(Succeed())
+
+	// test: binding a resource notifies the provisioner
+	var updated *corev1alpha1.ExternalResource
+	c := &MockClient{
+		MockGet: func(args ...interface{}) error {
+			testExternalResource().DeepCopyInto(args[2].(*corev1alpha1.ExternalResource))
+			return nil
+		},
+		MockUpdate: func(args ...interface{}) error {
+			updated = args[1].(*corev1alpha1.ExternalResource)
+			return nil
+		},
+	}
+	g.Expect(h.SetBindStatus(ctx, nn, c, true)).To(Succeed())
+	g.Expect(got.Name).To(Equal(name))
+	g.Expect(got.Bound).To(BeTrue())
+	g.Expect(updated).NotTo(BeNil())
+	g.Expect(updated.IsBound()).To(BeTrue())
+
+	// test: the provisioner fails to bind the resource
+	updated = nil
+	p.MockSetBindStatus = func(*external.Request) error { return fmt.Errorf("test-error") }
+	g.Expect(h.SetBindStatus(ctx, nn, c, false)).NotTo(Succeed())
+	g.Expect(updated).To(BeNil())
+
+	// test: the provisioner is not notified of resources in dry-run mode
+	c.MockGet = func(args ...interface{}) error {
+		res := testExternalResource()
+		res.SetAnnotations(map[string]string{corev1alpha1.AnnotationDryRun: "true"})
+		res.DeepCopyInto(args[2].(*corev1alpha1.ExternalResource))
+		return nil
+	}
+	g.Expect(h.SetBindStatus(ctx, nn, c, true)).To(Succeed())
+	g.Expect(updated).NotTo(BeNil())
+	g.Expect(updated.IsBound()).To(BeTrue())
+}
+
+func TestExternalResourceReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	p := &externalfake.MockClient{}
+	kc := kubefake.NewSimpleClientset()
+	c := fake.NewFakeClient(testExternalResource())
+	r := &ExternalResourceReconciler{
+		Client:     c,
+		kubeclient: kc,
+		recorder:   &MockRecorder{},
+		connect:    func(*corev1alpha1.ExternalProvisioner) (external.Client, error) { return p, nil },
+	}
+	r.create = r._create
+	r.sync = r._sync
+	r.delete = r._delete
+	res := &corev1alpha1.ExternalResource{}
+
+	// test: the provisioner fails to provision the resource
+	p.MockProvision = func(*external.Request) (*external.Response, error) { return nil, fmt.Errorf("test-error") }
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeTrue())
+	g.Expect(util.HasFinalizer(res, externalResourceFinalizer)).To(BeFalse())
+
+	// test: the resource is provisioned
+	var provisioned *external.Request
+	p.MockProvision = func(req *external.Request) (*external.Response, error) {
+		provisioned = req
+		return &external.Response{State: corev1alpha1.ExternalResourceStateCreating}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(provisioned.CorrelationID).NotTo(BeEmpty())
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.State).To(Equal(corev1alpha1.ExternalResourceStateCreating))
+	g.Expect(res.Status.IsCondition(corev1alpha1.Creating)).To(BeTrue())
+	g.Expect(util.HasFinalizer(res, externalResourceFinalizer)).To(BeTrue())
+
+	// test: the resource has failed
+	p.MockFind = func(*external.Request) (*external.Response, error) {
+		return &external.Response{State: corev1alpha1.ExternalResourceStateFailed, Message: "no brokers"}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.StatusSummary().Message).To(Equal("no brokers"))
+	g.Expect(res.IsAvailable()).To(BeFalse())
+
+	// test: the resource is available and its connection secret is written
+	p.MockFind = func(*external.Request) (*external.Response, error) {
+		return &external.Response{
+			State:            corev1alpha1.ExternalResourceStateAvailable,
+			Endpoint:         "kafka.example.org:9092",
+			ConnectionSecret: map[string][]byte{"topic": []byte("events")},
+		}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.IsAvailable()).To(BeTrue())
+	g.Expect(res.Status.IsReady()).To(BeTrue())
+	secret, err := kc.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(secret.Data).To(Equal(map[string][]byte{
+		"topic": []byte("events"),
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("kafka.example.org:9092"),
+	}))
+
+	// test: the resource is deleted by its provisioner
+	var deleted *external.Request
+	p.MockDelete = func(req *external.Request) error {
+		deleted = req
+		return &external.Error{StatusCode: 404}
+	}
+	now := metav1.Now()
+	res.DeletionTimestamp = &now
+	rs, err = r._delete(ctx, res, p)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(deleted.Name).To(Equal(name))
+	g.Expect(util.HasFinalizer(res, externalResourceFinalizer)).To(BeFalse())
+
+	// test: retained resources are not deleted by their provisioner
+	res = testExternalResource()
+	res.Spec.ReclaimPolicy = corev1alpha1.ReclaimRetain
+	util.AddFinalizer(res, externalResourceFinalizer)
+	r.Client = fake.NewFakeClient(res.DeepCopy())
+	p.MockDelete = func(*external.Request) error { return fmt.Errorf("should not be called") }
+	rs, err = r._delete(ctx, res, p)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(util.HasFinalizer(res, externalResourceFinalizer)).To(BeFalse())
+}
+
+func TestExternalResourceReconcileDryRun(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	p := &externalfake.MockClient{
+		MockProvision: func(*external.Request) (*external.Response, error) {
+			return nil, fmt.Errorf("provision should not be called")
+		},
+		MockDelete: func(*external.Request) error { return fmt.Errorf("delete should not be called") },
+	}
+	annotated := testExternalResource()
+	annotated.SetAnnotations(map[string]string{corev1alpha1.AnnotationDryRun: "true"})
+	c := fake.NewFakeClient(annotated)
+	r := &ExternalResourceReconciler{
+		Client:     c,
+		kubeclient: kubefake.NewSimpleClientset(),
+		recorder:   &MockRecorder{},
+		connect:    func(*corev1alpha1.ExternalProvisioner) (external.Client, error) { return p, nil },
+	}
+	r.create = r._create
+	r.sync = r._sync
+	r.delete = r._delete
+
+	// test: the provisioning of an annotated resource is planned
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	res := &corev1alpha1.ExternalResource{}
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.Condition(corev1alpha1.DryRun).Reason).To(Equal(planExternalProvision))
+	g.Expect(util.HasFinalizer(res, externalResourceFinalizer)).To(BeFalse())
+
+	// test: a resource deleted in global dry-run mode is retained
+	r.dryRun = true
+	res = testExternalResource()
+	util.AddFinalizer(res, externalResourceFinalizer)
+	now := metav1.Now()
+	res.DeletionTimestamp = &now
+	r.Client = fake.NewFakeClient(res.DeepCopy())
+	rs, err = r._delete(ctx, res, p)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(util.HasFinalizer(res, externalResourceFinalizer)).To(BeFalse())
+}
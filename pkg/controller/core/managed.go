@@ -0,0 +1,607 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
+	"github.com/crossplaneio/crossplane/pkg/tracing"
+	"github.com/crossplaneio/crossplane/pkg/util"
+)
+
+const (
+	managedReconcileTimeout = 1 * time.Minute
+
+	errorManagedConnect = "Failed to connect to provider"
+	errorManagedObserve = "Failed to observe external resource"
+	errorManagedCreate  = "Failed to create external resource"
+	errorManagedAdopt   = "Failed to adopt external resource"
+	errorManagedUpdate  = "Failed to update external resource"
+	errorManagedDelete  = "Failed to delete external resource"
+	errorManagedPublish = "Failed to publish connection secret" // nolint:gas,gosec
+	errorManagedReclaim = "Unsupported reclaim policy"
+
+	planManagedCreate = "Would create external resource"
+	planManagedUpdate = "Would update external resource"
+	planManagedDelete = "Would delete external resource"
+)
+
+// A Managed resource is a resource whose external resource, for example an
+// RDS instance or a GKE cluster, is managed by a ManagedReconciler.
+type Managed interface {
+	corev1alpha1.Resource
+	metav1.Object
+
+	// Gets an owner reference that points to this resource
+	OwnerReference() metav1.OwnerReference
+	// The conditions of this resource
+	ConditionedStatus() *corev1alpha1.ConditionedStatus
+}
+
+// ConnectionDetails are the details required to connect to an external
+// resource, keyed by the keys of its connection secret, e.g.
+// corev1alpha1.ResourceCredentialsSecretEndpointKey.
+type ConnectionDetails map[string][]byte
+
+// An ExternalObservation is the observed state of an external resource.
+type ExternalObservation struct {
+	// ResourceExists is false if the external resource does not exist and
+	// must be created.
+	ResourceExists bool
+
+	// ResourceReady is true if the external resource is available for use.
+	ResourceReady bool
+
+	// ResourceUpToDate is false if the external resource differs from the
+	// spec of its managed resource and must be updated. External resources
+	// that cannot be updated yet, for example because they are still being
+	// created, are up to date.
+	ResourceUpToDate bool
+
+	// ResourceUpdating is true if an earlier update of the external resource
+	// is still in progress.
+	ResourceUpdating bool
+
+	// Diff describes how an external resource that is not up to date would
+	// be updated, e.g. the modify request that would be sent to the cloud
+	// provider. It is recorded instead of updating the external resource in
+	// dry-run mode.
+	Diff string
+
+	// ConnectionDetails observed from the external resource, if any. They
+	// are merged into the existing connection secret.
+	ConnectionDetails ConnectionDetails
+}
+
+// An ExternalCreation is the result of the creation of an external resource.
+type ExternalCreation struct {
+	// ConnectionDetails that are only known when the external resource is
+	// created, such as a generated password.
+	ConnectionDetails ConnectionDetails
+}
+
+// An ExternalUpdate is the result of the update of an external resource.
+type ExternalUpdate struct {
+	// ConnectionDetails that changed as a result of the update, if any.
+	ConnectionDetails ConnectionDetails
+}
+
+// An ExternalClient manages the external resources of managed resources.
+// Methods may update the status of the supplied managed resource, which is
+// persisted by the ManagedReconciler.
+type ExternalClient interface {
+	// Observe the external resource of the supplied managed resource. An
+	// external resource that is in a state it cannot recover from should
+	// return an error marked with requeue.Terminal.
+	Observe(ctx context.Context, mg Managed) (ExternalObservation, error)
+
+	// Create the external resource of the supplied managed resource. The
+	// managed resource must record enough of the created resource in its
+	// status, typically its name, to observe it later.
+	Create(ctx context.Context, mg Managed) (ExternalCreation, error)
+
+	// Update the external resource of the supplied managed resource to match
+	// its spec.
+	Update(ctx context.Context, mg Managed) (ExternalUpdate, error)
+
+	// Delete the external resource of the supplied managed resource, taking
+	// a final snapshot first if its reclaim policy is ReclaimSnapshot. Delete
+	// is called until the external resource no longer exists, so it must
+	// tolerate external resources that are already being deleted.
+	Delete(ctx context.Context, mg Managed) error
+}
+
+// An ExternalConnecter returns an ExternalClient for the external resource of
+// a managed resource, typically authenticated using the credentials of the
+// provider the managed resource references.
+type ExternalConnecter interface {
+	Connect(ctx context.Context, mg Managed) (ExternalClient, error)
+}
+
+// DryRun reconciles every managed resource, ExternalResource and resource pool
+// in dry-run mode, as if it had the corev1alpha1.AnnotationDryRun annotation.
+var DryRun = false
+
+// ManagedReconciler reconciles managed resources with their external
+// resources. It creates external resources that do not exist, updates those
+// that differ from their managed resource, publishes their connection details
+// to the connection secret of their managed resource, and deletes them when
+// their managed resource is deleted, depending on its reclaim policy.
+type ManagedReconciler struct {
+	client.Client
+	recorder   record.EventRecorder
+	requeue    *requeue.Policy
+	log        logr.Logger
+	scheme     *runtime.Scheme
+	finalizer  string
+	newManaged func() Managed
+	external   ExternalConnecter
+	classify   clients.Classifier
+	deletes    map[corev1alpha1.ReclaimPolicy]bool
+	dryRun     bool
+}
+
+// NewManagedReconciler returns a ManagedReconciler for the kind of managed
+// resource returned by newManaged. Its external resources are managed by the
+// ExternalClients of the supplied connecter, and errors returned by them are
+// classified by the supplied classifier. External resources are only deleted
+// if the reclaim policy of their managed resource is one of the supplied
+// policies; they are retained otherwise.
+func NewManagedReconciler(mgr manager.Manager, controllerName string, newManaged func() Managed, c ExternalConnecter, classify clients.Classifier, deletes ...corev1alpha1.ReclaimPolicy) *ManagedReconciler {
+	r := &ManagedReconciler{
+		Client:     mgr.GetClient(),
+		recorder:   mgr.GetRecorder(controllerName),
+		requeue:    requeue.NewPolicy(controllerName, requeue.Defaults),
+		log:        logging.Log.WithName(controllerName),
+		scheme:     mgr.GetScheme(),
+		finalizer:  "finalizer." + controllerName,
+		newManaged: newManaged,
+		external:   c,
+		classify:   classify,
+		deletes:    map[corev1alpha1.ReclaimPolicy]bool{},
+		dryRun:     DryRun,
+	}
+	for _, p := range deletes {
+		r.deletes[p] = true
+	}
+	return r
+}
+
+// AddManaged creates a new controller for the kind of managed resource
+// returned by newManaged and adds it to the manager. The arguments are those
+// of NewManagedReconciler. Managed resources are reconciled when they or their
+// connection secrets change.
+func AddManaged(mgr manager.Manager, controllerName string, newManaged func() Managed, c ExternalConnecter, classify clients.Classifier, deletes ...corev1alpha1.ReclaimPolicy) error {
+	r := NewManagedReconciler(mgr, controllerName, newManaged, c, classify, deletes...)
+	ctrl, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
+	if err != nil {
+		return errors.Wrap(err, "cannot create Kubernetes controller")
+	}
+
+	if err := ctrl.Watch(&source.Kind{Type: newManaged()}, &handler.EnqueueRequestForObject{}); err != nil {
+		return errors.Wrap(err, "cannot watch managed resources")
+	}
+
+	return errors.Wrap(ctrl.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{OwnerType: newManaged()}), "cannot watch connection secrets")
+}
+
+// Reconcile the requested managed resource with its external resource.
+func (r *ManagedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx, cancel := context.WithTimeout(logging.NewContext(context.Background(), r.log, request), managedReconcileTimeout)
+	defer cancel()
+
+	mg := r.newManaged()
+	if err := r.Get(ctx, request.NamespacedName, mg); err != nil {
+		if kerrors.IsNotFound(err) {
+			r.requeue.Forget(request.NamespacedName)
+			return Result, nil
+		}
+		return Result, errors.Wrapf(err, "cannot get managed resource %s", request.NamespacedName)
+	}
+
+	logging.Reconciling(ctx, mg)
+
+	ctx, span := tracing.StartSpan(ctx, "core.ManagedReconciler.Reconcile", mg)
+	defer span.End()
+
+	// Wait for a failed resource to back off before reconciling it again
+	if d := r.requeue.Wait(mg); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
+	if mg.GetDeletionTimestamp() != nil {
+		if !util.HasFinalizer(mg, r.finalizer) {
+			return Result, nil
+		}
+		if p := mg.ReclaimPolicy().OrDefault(); !r.deletes[p] {
+			// The external resource is retained, so there is no need to
+			// connect to the provider, which may already be gone. A final
+			// snapshot that cannot be taken must not block the deletion.
+			if p == corev1alpha1.ReclaimSnapshot {
+				msg := fmt.Sprintf("reclaim policy %s is not supported by this kind of managed resource, the external resource is retained", p)
+				logging.RecordEvent(ctx, r.recorder, mg, corev1.EventTypeWarning, errorManagedReclaim, msg)
+			}
+			return r.finalize(ctx, mg)
+		}
+	}
+
+	// A kind of managed resource that cannot take a final snapshot must not
+	// silently retain or delete its external resource instead. Classes and
+	// resources with this policy are rejected by the admission webhooks, which
+	// may be disabled.
+	if p := mg.ReclaimPolicy(); p == corev1alpha1.ReclaimSnapshot && !r.deletes[p] {
+		err := errors.Errorf("reclaim policy %s is not supported by this kind of managed resource", p)
+		return r.fail(ctx, mg, errorManagedReclaim, requeue.Terminal(err))
+	}
+
+	// Two managed resources must never adopt, and later delete, the same
+	// external resource. A managed resource has adopted its external resource
+	// once it has a finalizer.
+	if n := corev1alpha1.ExternalName(mg); n != "" && mg.GetDeletionTimestamp() == nil && !util.HasFinalizer(mg, r.finalizer) {
+		if err := r.adoptable(ctx, mg, n); err != nil {
+			return r.fail(ctx, mg, errorManagedAdopt, err)
+		}
+	}
+
+	ext, err := r.external.Connect(ctx, mg)
+	if err != nil {
+		return r.fail(ctx, mg, errorManagedConnect, err)
+	}
+
+	o, err := ext.Observe(ctx, mg)
+	if err != nil {
+		return r.fail(ctx, mg, errorManagedObserve, err)
+	}
+
+	if mg.GetDeletionTimestamp() != nil {
+		return r.delete(ctx, mg, ext, o)
+	}
+
+	if !o.ResourceExists {
+		return r.create(ctx, mg, ext)
+	}
+
+	return r.sync(ctx, mg, ext, o)
+}
+
+// create the external resource of the supplied managed resource.
+func (r *ManagedReconciler) create(ctx context.Context, mg Managed, ext ExternalClient) (reconcile.Result, error) {
+	ctx, span := tracing.StartSpan(ctx, "core.ManagedReconciler.create", mg)
+	defer span.End()
+
+	// A managed resource that adopts an existing external resource must never
+	// create another one in its place.
+	if n := corev1alpha1.ExternalName(mg); n != "" {
+		err := errors.Errorf("external resource %s does not exist, or cannot be adopted by this kind of managed resource", n)
+		return r.fail(ctx, mg, errorManagedAdopt, requeue.Terminal(err))
+	}
+
+	if r.isDryRun(mg) {
+		return r.plan(ctx, mg, planManagedCreate, "external resource would be created")
+	}
+
+	// Persist the finalizer before the external resource is created, so that
+	// the external resource cannot be orphaned by deleting the managed one.
+	if !util.HasFinalizer(mg, r.finalizer) {
+		util.AddFinalizer(mg, r.finalizer)
+		if err := r.Update(ctx, mg); err != nil {
+			return Result, errors.Wrapf(err, "cannot add finalizer to managed resource %s", requeue.Key(mg))
+		}
+	}
+
+	c, err := ext.Create(ctx, mg)
+	if err != nil {
+		return r.fail(ctx, mg, errorManagedCreate, err)
+	}
+
+	if err := r.publish(ctx, mg, c.ConnectionDetails); err != nil {
+		return r.fail(ctx, mg, errorManagedPublish, err)
+	}
+
+	logging.FromContext(ctx).Info("created external resource")
+	mg.ConditionedStatus().UnsetAllConditions()
+	mg.ConditionedStatus().SetCreating()
+
+	// later reconciles of the resource are not part of the trace that created it
+	tracing.Forget(mg)
+
+	return r.requeue.Pending(requeue.Key(mg)), r.Update(ctx, mg)
+}
+
+// sync the existing external resource of the supplied managed resource.
+func (r *ManagedReconciler) sync(ctx context.Context, mg Managed, ext ExternalClient, o ExternalObservation) (reconcile.Result, error) {
+	// Adopted external resources were never created by this reconciler, so
+	// their finalizer is added, and persisted below, once they are observed.
+	util.AddFinalizer(mg, r.finalizer)
+
+	if err := r.publish(ctx, mg, o.ConnectionDetails); err != nil {
+		return r.fail(ctx, mg, errorManagedPublish, err)
+	}
+
+	updating := o.ResourceUpdating
+	if !o.ResourceUpToDate {
+		if r.isDryRun(mg) {
+			diff := o.Diff
+			if diff == "" {
+				diff = "external resource would be updated"
+			}
+			return r.plan(ctx, mg, planManagedUpdate, diff)
+		}
+		u, err := ext.Update(ctx, mg)
+		if err != nil {
+			return r.fail(ctx, mg, errorManagedUpdate, err)
+		}
+		if err := r.publish(ctx, mg, u.ConnectionDetails); err != nil {
+			return r.fail(ctx, mg, errorManagedPublish, err)
+		}
+		logging.FromContext(ctx).Info("updated external resource")
+		updating = true
+	}
+
+	s := mg.ConditionedStatus()
+	if !o.ResourceReady {
+		// Resources that have been ready before are unavailable, e.g. because
+		// they are being modified, rather than being created.
+		switch {
+		case s.Condition(corev1alpha1.Ready) == nil:
+			activate(s, corev1alpha1.Creating)
+		case updating:
+			activate(s, corev1alpha1.Updating)
+		default:
+			activate(s)
+		}
+		return r.requeue.Pending(requeue.Key(mg)), r.Update(ctx, mg)
+	}
+
+	if updating {
+		// poll the resource until the update is complete
+		metrics.ObserveReady(mg, s)
+		activate(s, corev1alpha1.Ready, corev1alpha1.Updating)
+		return r.requeue.Pending(requeue.Key(mg)), r.Update(ctx, mg)
+	}
+
+	if !active(s, corev1alpha1.Ready) {
+		metrics.ObserveReady(mg, s)
+		activate(s, corev1alpha1.Ready)
+	}
+
+	r.requeue.Forget(requeue.Key(mg))
+	return Result, r.Update(ctx, mg)
+}
+
+// delete the external resource of the supplied managed resource, if it still
+// exists, and finalize the managed resource once it does not.
+func (r *ManagedReconciler) delete(ctx context.Context, mg Managed, ext ExternalClient, o ExternalObservation) (reconcile.Result, error) {
+	if !o.ResourceExists {
+		return r.finalize(ctx, mg)
+	}
+
+	// The external resource is retained in dry-run mode, rather than keeping
+	// the managed resource from being deleted until dry-run mode is disabled.
+	if r.isDryRun(mg) {
+		msg := "external resource would be deleted, and is retained"
+		logging.FromContext(ctx).Info(msg, "reason", planManagedDelete)
+		logging.RecordEvent(ctx, r.recorder, mg, corev1.EventTypeNormal, planManagedDelete, msg)
+		return r.finalize(ctx, mg)
+	}
+
+	if err := ext.Delete(ctx, mg); err != nil {
+		return r.fail(ctx, mg, errorManagedDelete, err)
+	}
+
+	activate(mg.ConditionedStatus(), corev1alpha1.Deleting)
+
+	// poll the resource until it has been deleted
+	return r.requeue.Pending(requeue.Key(mg)), r.Update(ctx, mg)
+}
+
+// finalize the supplied managed resource, allowing it to be deleted.
+func (r *ManagedReconciler) finalize(ctx context.Context, mg Managed) (reconcile.Result, error) {
+	logging.FromContext(ctx).Info("finalizing managed resource", "reclaimPolicy", string(mg.ReclaimPolicy().OrDefault()))
+	mg.ConditionedStatus().UnsetAllConditions()
+	mg.ConditionedStatus().SetDeleting()
+	util.RemoveFinalizer(mg, r.finalizer)
+	r.requeue.Forget(requeue.Key(mg))
+	return Result, r.Update(ctx, mg)
+}
+
+// isDryRun returns true if the supplied managed resource is reconciled in
+// dry-run mode, i.e. its external resource must not be changed.
+func (r *ManagedReconciler) isDryRun(mg Managed) bool {
+	return r.dryRun || corev1alpha1.IsDryRun(mg)
+}
+
+// plan records the change that would be made to the external resource of the
+// supplied managed resource in dry-run mode, without making it. An event is
+// recorded only when the planned change differs from the last one.
+func (r *ManagedReconciler) plan(ctx context.Context, mg Managed, reason, message string) (reconcile.Result, error) {
+	s := mg.ConditionedStatus()
+	c := corev1alpha1.NewCondition(corev1alpha1.DryRun, reason, message)
+	if current := s.Condition(corev1alpha1.DryRun); current == nil || !current.Equal(c) {
+		logging.FromContext(ctx).Info(message, "reason", reason)
+		logging.RecordEvent(ctx, r.recorder, mg, corev1.EventTypeNormal, reason, message)
+		s.SetCondition(c)
+	}
+	r.requeue.Forget(requeue.Key(mg))
+	return Result, r.Update(ctx, mg)
+}
+
+// fail sets the failed condition of the supplied managed resource and records
+// a warning event. Failures are retried with backoff unless they are terminal.
+func (r *ManagedReconciler) fail(ctx context.Context, mg Managed, reason string, err error) (reconcile.Result, error) {
+	err = clients.Classify(err, r.classify)
+	reason = clients.Reason(reason, err)
+	logging.FromContext(ctx).Info(err.Error(), "reason", reason)
+	logging.RecordEvent(ctx, r.recorder, mg, corev1.EventTypeWarning, reason, err.Error())
+	mg.ConditionedStatus().SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, err.Error()))
+	return r.requeue.Failed(mg, err), r.Update(ctx, mg)
+}
+
+// publish the supplied connection details to the connection secret of the
+// supplied managed resource. Details are merged into the existing secret, so
+// that details only known when the external resource was created, such as
+// its password, are not lost.
+func (r *ManagedReconciler) publish(ctx context.Context, mg Managed, cd ConnectionDetails) error {
+	if len(cd) == 0 {
+		return nil
+	}
+
+	n := types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.ConnectionSecretName()}
+	s := &corev1.Secret{}
+	if err := r.Get(ctx, n, s); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "cannot get secret %s", n)
+		}
+		s = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            n.Name,
+				Namespace:       n.Namespace,
+				OwnerReferences: []metav1.OwnerReference{mg.OwnerReference()},
+			},
+			Data: map[string][]byte(cd),
+		}
+		return errors.Wrapf(r.Create(ctx, s), "cannot create secret %s", n)
+	}
+
+	changed := false
+	if s.Data == nil {
+		s.Data = map[string][]byte{}
+	}
+	for k, v := range cd {
+		if string(s.Data[k]) != string(v) {
+			s.Data[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return errors.Wrapf(r.Update(ctx, s), "cannot update secret %s", n)
+}
+
+// adoptable returns an error marked with requeue.Terminal if another managed
+// resource of the same kind adopted the supplied external resource, or was
+// created before the supplied managed resource and so adopts it first.
+func (r *ManagedReconciler) adoptable(ctx context.Context, mg Managed, name string) error {
+	gvk, err := apiutil.GVKForObject(mg, r.scheme)
+	if err != nil {
+		return errors.Wrap(err, "cannot get kind of managed resource")
+	}
+	list, err := r.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return errors.Wrapf(err, "cannot get list of kind %s", gvk.Kind)
+	}
+	if err := r.List(ctx, &client.ListOptions{}, list); err != nil {
+		return errors.Wrapf(err, "cannot list managed resources of kind %s", gvk.Kind)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return errors.Wrapf(err, "cannot list managed resources of kind %s", gvk.Kind)
+	}
+
+	for _, item := range items {
+		other, ok := item.(Managed)
+		if !ok || requeue.Key(other) == requeue.Key(mg) || corev1alpha1.ExternalName(other) != name {
+			continue
+		}
+		if util.HasFinalizer(other, r.finalizer) || adoptsFirst(other, mg) {
+			return requeue.Terminal(errors.Errorf("external resource %s is adopted by managed resource %s", name, requeue.Key(other)))
+		}
+	}
+	return nil
+}
+
+// adoptsFirst returns true if managed resource a adopts an external resource
+// before managed resource b, i.e. if it was created first. Managed resources
+// created in the same second are ordered by namespace and name.
+func adoptsFirst(a, b Managed) bool {
+	ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !ta.Equal(&tb) {
+		return ta.Before(&tb)
+	}
+	return requeue.Key(a).String() < requeue.Key(b).String()
+}
+
+// ConnectionDetailPublished returns true if the connection secret of the
+// supplied managed resource holds a value for the supplied key. External
+// clients use it to tell whether details that can only be generated, such as
+// the password of an adopted external resource, were published.
+func ConnectionDetailPublished(ctx context.Context, kube client.Client, mg Managed, key string) (bool, error) {
+	s := &corev1.Secret{}
+	n := types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.ConnectionSecretName()}
+	if err := kube.Get(ctx, n, s); err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "cannot get connection secret %s", n)
+	}
+	return len(s.Data[key]) > 0, nil
+}
+
+// active returns true if the supplied conditions, and only those conditions,
+// are true.
+func active(s *corev1alpha1.ConditionedStatus, cts ...corev1alpha1.ConditionType) bool {
+	want := map[corev1alpha1.ConditionType]bool{}
+	for _, t := range cts {
+		want[t] = true
+	}
+	for _, c := range s.Conditions {
+		if (c.Status == corev1.ConditionTrue) != want[c.Type] {
+			return false
+		}
+		delete(want, c.Type)
+	}
+	return len(want) == 0
+}
+
+// activate makes the supplied conditions, and only those conditions, true.
+// The status is left untouched if this is already the case, so that polling
+// an unchanged external resource does not update its managed resource and in
+// turn trigger another reconcile.
+func activate(s *corev1alpha1.ConditionedStatus, cts ...corev1alpha1.ConditionType) {
+	if active(s, cts...) {
+		return
+	}
+	s.UnsetAllConditions()
+	for _, t := range cts {
+		s.SetCondition(corev1alpha1.NewCondition(t, "", ""))
+	}
+}
@@ -0,0 +1,714 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
+	"github.com/crossplaneio/crossplane/pkg/util"
+)
+
+const testManagedFinalizer = "finalizer.managed.core.crossplane.io"
+
+type MockExternalClient struct {
+	MockObserve func(context.Context, Managed) (ExternalObservation, error)
+	MockCreate  func(context.Context, Managed) (ExternalCreation, error)
+	MockUpdate  func(context.Context, Managed) (ExternalUpdate, error)
+	MockDelete  func(context.Context, Managed) error
+}
+
+func (m *MockExternalClient) Observe(ctx context.Context, mg Managed) (ExternalObservation, error) {
+	return m.MockObserve(ctx, mg)
+}
+
+func (m *MockExternalClient) Create(ctx context.Context, mg Managed) (ExternalCreation, error) {
+	return m.MockCreate(ctx, mg)
+}
+
+func (m *MockExternalClient) Update(ctx context.Context, mg Managed) (ExternalUpdate, error) {
+	return m.MockUpdate(ctx, mg)
+}
+
+func (m *MockExternalClient) Delete(ctx context.Context, mg Managed) error {
+	return m.MockDelete(ctx, mg)
+}
+
+type MockExternalConnecter struct {
+	MockConnect func(context.Context, Managed) (ExternalClient, error)
+}
+
+func (m *MockExternalConnecter) Connect(ctx context.Context, mg Managed) (ExternalClient, error) {
+	return m.MockConnect(ctx, mg)
+}
+
+// secretErrorClient fails the configured operations on secrets.
+type secretErrorClient struct {
+	client.Client
+	get, create, update error
+}
+
+func (c *secretErrorClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if _, ok := obj.(*corev1.Secret); ok && c.get != nil {
+		return c.get
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *secretErrorClient) Create(ctx context.Context, obj runtime.Object) error {
+	if _, ok := obj.(*corev1.Secret); ok && c.create != nil {
+		return c.create
+	}
+	return c.Client.Create(ctx, obj)
+}
+
+func (c *secretErrorClient) Update(ctx context.Context, obj runtime.Object) error {
+	if _, ok := obj.(*corev1.Secret); ok && c.update != nil {
+		return c.update
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func testManagedReconciler(c client.Client, ext ExternalClient) *ManagedReconciler {
+	return &ManagedReconciler{
+		Client:     c,
+		recorder:   &MockRecorder{},
+		requeue:    requeue.NewPolicy("managed.core.crossplane.io", requeue.Defaults),
+		scheme:     scheme.Scheme,
+		finalizer:  testManagedFinalizer,
+		newManaged: func() Managed { return &corev1alpha1.ExternalResource{} },
+		external: &MockExternalConnecter{MockConnect: func(context.Context, Managed) (ExternalClient, error) {
+			return ext, nil
+		}},
+		classify: func(error) clients.ErrorClass { return clients.ErrorUnknown },
+		deletes:  map[corev1alpha1.ReclaimPolicy]bool{corev1alpha1.ReclaimDelete: true},
+	}
+}
+
+func TestManagedReconcileCreate(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	c := fake.NewFakeClient(testExternalResource())
+	ext := &MockExternalClient{MockObserve: func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: false}, nil
+	}}
+	r := testManagedReconciler(c, ext)
+	res := &corev1alpha1.ExternalResource{}
+
+	// test: the external resource cannot be created
+	ext.MockCreate = func(context.Context, Managed) (ExternalCreation, error) {
+		return ExternalCreation{}, fmt.Errorf("test-create-error")
+	}
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeTrue())
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Reason).To(Equal(errorManagedCreate))
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeTrue())
+
+	// test: the external resource is created and its password published
+	r.requeue.Forget(nn)
+	ext.MockCreate = func(_ context.Context, mg Managed) (ExternalCreation, error) {
+		mg.(*corev1alpha1.ExternalResource).Status.ProviderID = "test-id"
+		return ExternalCreation{ConnectionDetails: ConnectionDetails{
+			corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password"),
+		}}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.ProviderID).To(Equal("test-id"))
+	g.Expect(res.Status.IsCondition(corev1alpha1.Creating)).To(BeTrue())
+	g.Expect(res.Status.IsFailed()).To(BeFalse())
+	secret := &corev1.Secret{}
+	g.Expect(c.Get(ctx, nn, secret)).To(Succeed())
+	g.Expect(secret.OwnerReferences).To(Equal([]metav1.OwnerReference{res.OwnerReference()}))
+	g.Expect(secret.Data).To(Equal(map[string][]byte{
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password"),
+	}))
+}
+
+func TestManagedReconcileGet(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	// test: the managed resource does not exist
+	r := testManagedReconciler(fake.NewFakeClient(), nil)
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+
+	// test: the managed resource cannot be retrieved
+	r.Client = &MockClient{MockGet: func(...interface{}) error { return fmt.Errorf("test-get-error") }}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get managed resource %s: test-get-error", nn)))
+	g.Expect(rs).To(Equal(Result))
+}
+
+func TestManagedReconcilePublish(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	existing := testExternalResource()
+	details := ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("test-endpoint")}
+	ext := &MockExternalClient{MockObserve: func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true, ResourceUpToDate: true, ConnectionDetails: details}, nil
+	}}
+
+	cases := []struct {
+		name   string
+		client *secretErrorClient
+	}{
+		{name: "FailedToGetConnectionSecret", client: &secretErrorClient{get: fmt.Errorf("test-get-error")}},
+		{name: "FailedToCreateConnectionSecret", client: &secretErrorClient{create: fmt.Errorf("test-create-error")}},
+		{name: "FailedToUpdateConnectionSecret", client: &secretErrorClient{update: fmt.Errorf("test-update-error")}},
+	}
+	for _, tc := range cases {
+		objs := []runtime.Object{existing.DeepCopy()}
+		if tc.client.update != nil {
+			objs = append(objs, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: existing.ConnectionSecretName()}})
+		}
+		tc.client.Client = fake.NewFakeClient(objs...)
+		r := testManagedReconciler(tc.client, ext)
+
+		rs, err := r.Reconcile(request)
+		g.Expect(err).NotTo(HaveOccurred(), tc.name)
+		g.Expect(rs).To(Equal(resultFailed), tc.name)
+		res := &corev1alpha1.ExternalResource{}
+		g.Expect(tc.client.Get(ctx, nn, res)).To(Succeed(), tc.name)
+		g.Expect(res.Status.Condition(corev1alpha1.Failed).Reason).To(Equal(errorManagedPublish), tc.name)
+	}
+
+	// test: details are merged into an existing connection secret
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: existing.ConnectionSecretName()},
+		Data:       map[string][]byte{corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password")},
+	}
+	c := fake.NewFakeClient(existing.DeepCopy(), secret)
+	r := testManagedReconciler(c, ext)
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	secret = &corev1.Secret{}
+	g.Expect(c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: existing.ConnectionSecretName()}, secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(map[string][]byte{
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("test-endpoint"),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password"),
+	}))
+}
+
+func TestManagedReconcileSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	existing := testExternalResource()
+	util.AddFinalizer(existing, testManagedFinalizer)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Data:       map[string][]byte{corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password")},
+	}
+	c := fake.NewFakeClient(existing, secret)
+	ext := &MockExternalClient{}
+	r := testManagedReconciler(c, ext)
+	res := &corev1alpha1.ExternalResource{}
+
+	// test: the external resource cannot be observed
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{}, requeue.Terminal(fmt.Errorf("test-failed-state"))
+	}
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeTrue())
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Message).To(Equal("test-failed-state"))
+
+	// test: the external resource is still being created
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Creating)).To(BeTrue())
+	g.Expect(res.Status.IsFailed()).To(BeFalse())
+
+	// test: polling a resource that is still being created leaves its
+	// conditions untouched
+	since := metav1.NewTime(time.Now().Add(-time.Minute))
+	for i := range res.Status.Conditions {
+		res.Status.Conditions[i].LastTransitionTime = since
+	}
+	g.Expect(c.Update(ctx, res)).To(Succeed())
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.Condition(corev1alpha1.Creating).LastTransitionTime.Unix()).To(Equal(since.Unix()))
+
+	// test: the external resource is ready and its endpoint is published
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{
+			ResourceExists:    true,
+			ResourceReady:     true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("test-endpoint")},
+		}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsReady()).To(BeTrue())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Creating)).To(BeFalse())
+	g.Expect(c.Get(ctx, nn, secret)).To(Succeed())
+	g.Expect(secret.Data).To(Equal(map[string][]byte{
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password"),
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte("test-endpoint"),
+	}))
+
+	// test: the external resource is out of date and is updated
+	updated := false
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true}, nil
+	}
+	ext.MockUpdate = func(context.Context, Managed) (ExternalUpdate, error) {
+		updated = true
+		return ExternalUpdate{}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(updated).To(BeTrue())
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsReady()).To(BeTrue())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Updating)).To(BeTrue())
+
+	// test: the external resource is still being updated
+	updated = false
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true, ResourceUpToDate: true, ResourceUpdating: true}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(updated).To(BeFalse())
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsReady()).To(BeTrue())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Updating)).To(BeTrue())
+
+	// test: the update of the external resource is complete
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true, ResourceUpToDate: true}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsReady()).To(BeTrue())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Updating)).To(BeFalse())
+
+	// test: the external resource is out of date again
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true}, nil
+	}
+
+	// test: the external resource cannot be updated
+	ext.MockUpdate = func(context.Context, Managed) (ExternalUpdate, error) {
+		return ExternalUpdate{}, fmt.Errorf("test-update-error")
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeTrue())
+
+	// test: a failed resource is not reconciled until it has backed off
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs.RequeueAfter).To(BeNumerically(">", 0))
+
+	// test: a ready resource that is being modified is no longer ready
+	r.requeue.Forget(nn)
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsReady()).To(BeFalse())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Creating)).To(BeFalse())
+}
+
+func TestManagedReconcileAdopt(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	existing := testExternalResource()
+	existing.SetAnnotations(map[string]string{corev1alpha1.AnnotationExternalName: "test-external-name"})
+	c := fake.NewFakeClient(existing)
+	created := false
+	ext := &MockExternalClient{MockCreate: func(context.Context, Managed) (ExternalCreation, error) {
+		created = true
+		return ExternalCreation{}, nil
+	}}
+	r := testManagedReconciler(c, ext)
+	res := &corev1alpha1.ExternalResource{}
+
+	// test: the external resource to adopt does not exist, and is not created
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: false}, nil
+	}
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(created).To(BeFalse())
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeTrue())
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Reason).To(Equal(errorManagedAdopt))
+
+	// test: the adopted external resource exists, and the finalizer is added
+	ext.MockObserve = func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true, ResourceUpToDate: true}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(created).To(BeFalse())
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsReady()).To(BeTrue())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeTrue())
+}
+
+func TestManagedReconcileDryRun(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	existing := testExternalResource()
+	existing.SetAnnotations(map[string]string{corev1alpha1.AnnotationDryRun: "true"})
+	c := fake.NewFakeClient(existing)
+	o := ExternalObservation{ResourceExists: false}
+	ext := &MockExternalClient{
+		MockObserve: func(context.Context, Managed) (ExternalObservation, error) { return o, nil },
+		MockCreate: func(context.Context, Managed) (ExternalCreation, error) {
+			return ExternalCreation{}, fmt.Errorf("create should not be called")
+		},
+		MockUpdate: func(context.Context, Managed) (ExternalUpdate, error) {
+			return ExternalUpdate{}, fmt.Errorf("update should not be called")
+		},
+		MockDelete: func(context.Context, Managed) error { return fmt.Errorf("delete should not be called") },
+	}
+	r := testManagedReconciler(c, ext)
+	res := &corev1alpha1.ExternalResource{}
+
+	// test: the external resource would be created
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeFalse())
+	g.Expect(res.Status.Condition(corev1alpha1.DryRun).Reason).To(Equal(planManagedCreate))
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeFalse())
+
+	// test: the external resource would be updated as described by its diff
+	o = ExternalObservation{ResourceExists: true, ResourceReady: true, Diff: "test-diff"}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeFalse())
+	g.Expect(res.Status.Condition(corev1alpha1.DryRun).Reason).To(Equal(planManagedUpdate))
+	g.Expect(res.Status.Condition(corev1alpha1.DryRun).Message).To(Equal("test-diff"))
+
+	// test: the external resource would be deleted, and is retained while the
+	// managed resource is finalized
+	now := metav1.Now()
+	res.DeletionTimestamp = &now
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeTrue())
+	g.Expect(c.Update(ctx, res)).To(Succeed())
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	res = &corev1alpha1.ExternalResource{}
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeFalse())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Deleting)).To(BeTrue())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeFalse())
+
+	// test: every resource is reconciled in dry-run mode when it is enabled
+	// globally
+	c = fake.NewFakeClient(testExternalResource())
+	o = ExternalObservation{ResourceExists: false}
+	r = testManagedReconciler(c, ext)
+	r.dryRun = true
+	res = &corev1alpha1.ExternalResource{}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.Condition(corev1alpha1.DryRun).Reason).To(Equal(planManagedCreate))
+}
+
+func TestManagedReconcileAdoptConflict(t *testing.T) {
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+	ext := &MockExternalClient{MockObserve: func(context.Context, Managed) (ExternalObservation, error) {
+		return ExternalObservation{ResourceExists: true, ResourceReady: true, ResourceUpToDate: true}, nil
+	}}
+
+	adopter := func(name, externalName string, finalizers ...string) *corev1alpha1.ExternalResource {
+		res := testExternalResource()
+		res.SetName(name)
+		res.SetAnnotations(map[string]string{corev1alpha1.AnnotationExternalName: externalName})
+		res.SetFinalizers(finalizers)
+		return res
+	}
+
+	cases := []struct {
+		name      string
+		other     *corev1alpha1.ExternalResource
+		wantError bool
+	}{
+		{
+			name:      "AdoptedByOther",
+			other:     adopter("z-resource", "test-external-name", testManagedFinalizer),
+			wantError: true,
+		},
+		{
+			name:      "CreatedBeforeOther",
+			other:     adopter("z-resource", "test-external-name"),
+			wantError: false,
+		},
+		{
+			name:      "CreatedAfterOther",
+			other:     adopter("a-resource", "test-external-name"),
+			wantError: true,
+		},
+		{
+			name:      "OtherExternalName",
+			other:     adopter("a-resource", "other-external-name", testManagedFinalizer),
+			wantError: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			c := fake.NewFakeClient(adopter(name, "test-external-name"), tc.other)
+			r := testManagedReconciler(c, ext)
+
+			rs, err := r.Reconcile(request)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(rs).To(Equal(Result))
+
+			res := &corev1alpha1.ExternalResource{}
+			g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+			g.Expect(res.Status.IsFailed()).To(Equal(tc.wantError))
+			g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(Equal(!tc.wantError))
+			if tc.wantError {
+				g.Expect(res.Status.Condition(corev1alpha1.Failed).Reason).To(Equal(errorManagedAdopt))
+			}
+		})
+	}
+}
+
+func TestManagedReconcileDelete(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	deleting := func(p corev1alpha1.ReclaimPolicy) *corev1alpha1.ExternalResource {
+		res := testExternalResource()
+		res.Spec.ReclaimPolicy = p
+		now := metav1.Now()
+		res.DeletionTimestamp = &now
+		util.AddFinalizer(res, testManagedFinalizer)
+		return res
+	}
+
+	// test: the external resource of a retained resource is not deleted
+	c := fake.NewFakeClient(deleting(corev1alpha1.ReclaimRetain))
+	r := testManagedReconciler(c, nil)
+	r.external = &MockExternalConnecter{MockConnect: func(context.Context, Managed) (ExternalClient, error) {
+		return nil, fmt.Errorf("connect should not be called")
+	}}
+	res := &corev1alpha1.ExternalResource{}
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeFalse())
+
+	// test: the external resource of a resource without a reclaim policy is retained
+	c = fake.NewFakeClient(deleting(""))
+	r = testManagedReconciler(c, nil)
+	r.deletes[""] = true
+	r.external = &MockExternalConnecter{MockConnect: func(context.Context, Managed) (ExternalClient, error) {
+		return nil, fmt.Errorf("connect should not be called")
+	}}
+	res = &corev1alpha1.ExternalResource{}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeFalse())
+
+	// test: the external resource is deleted
+	exists := true
+	deleted := 0
+	ext := &MockExternalClient{
+		MockObserve: func(context.Context, Managed) (ExternalObservation, error) {
+			return ExternalObservation{ResourceExists: exists}, nil
+		},
+		MockDelete: func(context.Context, Managed) error {
+			deleted++
+			return nil
+		},
+	}
+	c = fake.NewFakeClient(deleting(corev1alpha1.ReclaimDelete))
+	r = testManagedReconciler(c, ext)
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultPending))
+	g.Expect(deleted).To(Equal(1))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsCondition(corev1alpha1.Deleting)).To(BeTrue())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeTrue())
+
+	// test: the external resource cannot be deleted
+	ext.MockDelete = func(context.Context, Managed) error { return fmt.Errorf("test-delete-error") }
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.IsFailed()).To(BeTrue())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeTrue())
+
+	// test: the resource is finalized once the external resource is gone
+	r.requeue.Forget(nn)
+	exists = false
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	res = &corev1alpha1.ExternalResource{}
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(util.HasFinalizer(res, testManagedFinalizer)).To(BeFalse())
+}
+
+func TestManagedReconcileUnsupportedReclaimPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+	request := reconcile.Request{NamespacedName: nn}
+
+	snapshot := testExternalResource()
+	snapshot.Spec.ReclaimPolicy = corev1alpha1.ReclaimSnapshot
+	c := fake.NewFakeClient(snapshot)
+	r := testManagedReconciler(c, nil)
+	r.external = &MockExternalConnecter{MockConnect: func(context.Context, Managed) (ExternalClient, error) {
+		return nil, fmt.Errorf("connect should not be called")
+	}}
+
+	// test: a resource whose kind cannot take a final snapshot fails terminally
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	res := &corev1alpha1.ExternalResource{}
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Reason).To(Equal(errorManagedReclaim))
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Message).To(Equal("reclaim policy Snapshot is not supported by this kind of managed resource"))
+
+	// test: a deleted resource whose kind cannot take a final snapshot is
+	// finalized without connecting to its provider, retaining its external
+	// resource with a warning
+	now := metav1.Now()
+	deleted := res.DeepCopy()
+	deleted.DeletionTimestamp = &now
+	util.AddFinalizer(deleted, testManagedFinalizer)
+	c = fake.NewFakeClient(deleted)
+	r.Client = c
+	recorder := &eventRecorder{}
+	r.recorder = recorder
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	deleted = &corev1alpha1.ExternalResource{}
+	g.Expect(c.Get(ctx, nn, deleted)).To(Succeed())
+	g.Expect(util.HasFinalizer(deleted, testManagedFinalizer)).To(BeFalse())
+	g.Expect(recorder.reasons).To(Equal([]string{errorManagedReclaim}))
+
+	// test: a resource whose kind can take a final snapshot is reconciled
+	c = fake.NewFakeClient(res)
+	r.Client = c
+	r.deletes[corev1alpha1.ReclaimSnapshot] = true
+	r.external = &MockExternalConnecter{MockConnect: func(context.Context, Managed) (ExternalClient, error) {
+		return nil, fmt.Errorf("test-connect-error")
+	}}
+	_, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Message).To(Equal("test-connect-error"))
+}
+
+func TestManagedReconcileConnectFailure(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nn := types.NamespacedName{Namespace: namespace, Name: name}
+
+	c := fake.NewFakeClient(testExternalResource())
+	r := testManagedReconciler(c, nil)
+	r.external = &MockExternalConnecter{MockConnect: func(context.Context, Managed) (ExternalClient, error) {
+		return nil, fmt.Errorf("test-connect-error")
+	}}
+
+	// test: the provider of the resource cannot be connected to
+	rs, err := r.Reconcile(reconcile.Request{NamespacedName: nn})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	res := &corev1alpha1.ExternalResource{}
+	g.Expect(c.Get(ctx, nn, res)).To(Succeed())
+	g.Expect(res.Status.Condition(corev1alpha1.Failed).Message).To(Equal("test-connect-error"))
+
+	// test: the resource does not exist
+	rs, err = r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: "missing"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+// A ConstraintPolicy evaluates the values requested by a resource claim against
+// the constraints declared by its resource class. A nil ConstraintPolicy
+// constrains nothing.
+type ConstraintPolicy struct {
+	constraints map[string]corev1alpha1.ParameterConstraint
+}
+
+// NewConstraintPolicy returns the ConstraintPolicy declared by the supplied
+// resource class.
+func NewConstraintPolicy(class *corev1alpha1.ResourceClass) *ConstraintPolicy {
+	return &ConstraintPolicy{constraints: class.Constraints}
+}
+
+// Constrained returns true if the policy constrains the supplied claim field.
+func (p *ConstraintPolicy) Constrained(field string) bool {
+	if p == nil {
+		return false
+	}
+	_, ok := p.constraints[field]
+	return ok
+}
+
+// Check returns an error if the supplied claim value does not satisfy the
+// constraint on the supplied claim field. Empty values and values of
+// unconstrained fields always satisfy the policy.
+func (p *ConstraintPolicy) Check(field, claimValue string) error {
+	if claimValue == "" || !p.Constrained(field) {
+		return nil
+	}
+	if err := CheckConstraint(p.constraints[field], claimValue); err != nil {
+		return fmt.Errorf("claim value [%s] for %s is not allowed by the resource class: %s", claimValue, field, err)
+	}
+	return nil
+}
+
+// Resolve the class value and claim value of the supplied claim field. The claim
+// value of a constrained field overrides the class value if it satisfies the
+// constraint. Values of unconstrained fields are resolved by
+// ResolveClassClaimValues.
+func (p *ConstraintPolicy) Resolve(field, classValue, claimValue string) (string, error) {
+	if !p.Constrained(field) {
+		return ResolveClassClaimValues(classValue, claimValue)
+	}
+	if claimValue == "" {
+		return classValue, nil
+	}
+	if err := p.Check(field, claimValue); err != nil {
+		return "", err
+	}
+	return claimValue, nil
+}
+
+// ResolveInt resolves the class value and claim value of the supplied numeric
+// claim field like Resolve. Zero values are not set.
+func (p *ConstraintPolicy) ResolveInt(field string, classValue, claimValue int64) (int64, error) {
+	v, err := p.Resolve(field, formatInt(classValue), formatInt(claimValue))
+	if err != nil || v == "" {
+		return 0, err
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+// formatInt formats the supplied numeric value, or returns an empty string if
+// it is zero.
+func formatInt(v int64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatInt(v, 10)
+}
+
+// CheckConstraint returns an error if the supplied value does not satisfy every
+// restriction of the supplied constraint.
+func CheckConstraint(c corev1alpha1.ParameterConstraint, value string) error {
+	if len(c.Values) > 0 && !containsString(c.Values, value) {
+		return fmt.Errorf("value must be one of [%s]", strings.Join(c.Values, ", "))
+	}
+
+	if len(c.Versions) > 0 && !matchesAnyVersionRange(c.Versions, value) {
+		return fmt.Errorf("version must be within one of [%s]", strings.Join(c.Versions, ", "))
+	}
+
+	if c.Minimum != nil || c.Maximum != nil {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("value must be an integer")
+		}
+		if c.Minimum != nil && n < *c.Minimum {
+			return fmt.Errorf("value must be at least %d", *c.Minimum)
+		}
+		if c.Maximum != nil && n > *c.Maximum {
+			return fmt.Errorf("value must be at most %d", *c.Maximum)
+		}
+	}
+
+	if c.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + c.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("invalid pattern %s: %s", c.Pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("value must match pattern %s", c.Pattern)
+		}
+	}
+
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyVersionRange(ranges []string, version string) bool {
+	for _, r := range ranges {
+		if matchesVersionRange(r, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesVersionRange returns true if the supplied dot separated version falls
+// within the supplied range. An "x" or "*" component of the range matches any
+// remaining components of the version, for example "5.7.x" matches "5.7" and
+// "5.7.23" but not "5.6".
+func matchesVersionRange(r, version string) bool {
+	rc := strings.Split(r, ".")
+	vc := strings.Split(version, ".")
+	for i := range rc {
+		if rc[i] == "x" || rc[i] == "X" || rc[i] == "*" {
+			return true
+		}
+		if i >= len(vc) || rc[i] != vc[i] {
+			return false
+		}
+	}
+	return len(rc) == len(vc)
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+func int64Ptr(i int64) *int64 { return &i }
+
+func TestCheckConstraint(t *testing.T) {
+	cases := []struct {
+		name       string
+		constraint corev1alpha1.ParameterConstraint
+		value      string
+		wantErr    bool
+	}{
+		{"NoRestrictions", corev1alpha1.ParameterConstraint{}, "anything", false},
+		{"ValueAllowed", corev1alpha1.ParameterConstraint{Values: []string{"5.6", "5.7"}}, "5.7", false},
+		{"ValueNotAllowed", corev1alpha1.ParameterConstraint{Values: []string{"5.6", "5.7"}}, "8.0", true},
+		{"VersionWithinRange", corev1alpha1.ParameterConstraint{Versions: []string{"5.7.x"}}, "5.7.23", false},
+		{"VersionWithinShortRange", corev1alpha1.ParameterConstraint{Versions: []string{"5.x"}}, "5.7", false},
+		{"VersionMatchesRangePrefix", corev1alpha1.ParameterConstraint{Versions: []string{"5.7.x"}}, "5.7", false},
+		{"VersionOutsideRange", corev1alpha1.ParameterConstraint{Versions: []string{"5.7.x"}}, "5.6", true},
+		{"VersionExact", corev1alpha1.ParameterConstraint{Versions: []string{"9.6"}}, "9.6", false},
+		{"VersionLongerThanExact", corev1alpha1.ParameterConstraint{Versions: []string{"9.6"}}, "9.6.1", true},
+		{"WithinMinimumAndMaximum", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20), Maximum: int64Ptr(100)}, "50", false},
+		{"AtMinimum", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20), Maximum: int64Ptr(100)}, "20", false},
+		{"AtMaximum", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20), Maximum: int64Ptr(100)}, "100", false},
+		{"JustBelowMinimum", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20), Maximum: int64Ptr(100)}, "19", true},
+		{"JustAboveMaximum", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20), Maximum: int64Ptr(100)}, "101", true},
+		{"BelowMinimum", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20)}, "10", true},
+		{"AboveMaximum", corev1alpha1.ParameterConstraint{Maximum: int64Ptr(100)}, "200", true},
+		{"NotAnInteger", corev1alpha1.ParameterConstraint{Minimum: int64Ptr(20)}, "big", true},
+		{"PatternMatches", corev1alpha1.ParameterConstraint{Pattern: "team-[a-z]+"}, "team-data", false},
+		{"PatternPartiallyMatches", corev1alpha1.ParameterConstraint{Pattern: "team-[a-z]+"}, "my-team-data", true},
+		{"InvalidPattern", corev1alpha1.ParameterConstraint{Pattern: "team-["}, "team-data", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := CheckConstraint(tc.constraint, tc.value)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).NotTo(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestConstraintPolicyResolve(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	policy := NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			"engineVersion": {Versions: []string{"5.6.x", "5.7.x"}},
+		},
+	})
+
+	// constrained field, claim value allowed: claim value overrides class value
+	v, err := policy.Resolve("engineVersion", "5.6", "5.7")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(v).To(Equal("5.7"))
+
+	// constrained field, claim value not set: class value is used
+	v, err = policy.Resolve("engineVersion", "5.6", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(v).To(Equal("5.6"))
+
+	// constrained field, claim value not allowed
+	_, err = policy.Resolve("engineVersion", "5.6", "8.0")
+	g.Expect(err).To(MatchError("claim value [8.0] for engineVersion is not allowed by the resource class: version must be within one of [5.6.x, 5.7.x]"))
+
+	// unconstrained field: values must match exactly
+	_, err = policy.Resolve("storageSize", "20", "30")
+	g.Expect(err).To(HaveOccurred())
+
+	// nil policy: values must match exactly
+	var nilPolicy *ConstraintPolicy
+	g.Expect(nilPolicy.Constrained("engineVersion")).To(BeFalse())
+	v, err = nilPolicy.Resolve("engineVersion", "", "5.7")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(v).To(Equal("5.7"))
+}
+
+func TestConstraintPolicyResolveInt(t *testing.T) {
+	policy := NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			"storageSize": {Minimum: int64Ptr(20), Maximum: int64Ptr(100)},
+		},
+	})
+
+	cases := []struct {
+		name       string
+		policy     *ConstraintPolicy
+		classValue int64
+		claimValue int64
+		want       int64
+		wantErr    bool
+	}{
+		{"ClaimValueAtMinimum", policy, 50, 20, 20, false},
+		{"ClaimValueAtMaximum", policy, 50, 100, 100, false},
+		{"ClaimValueJustBelowMinimum", policy, 50, 19, 0, true},
+		{"ClaimValueJustAboveMaximum", policy, 50, 101, 0, true},
+		{"ClaimValueNotSet", policy, 50, 0, 50, false},
+		{"NoValueSet", policy, 0, 0, 0, false},
+		{"UnconstrainedDifferentValues", nil, 50, 20, 0, true},
+		{"UnconstrainedSameValue", nil, 50, 50, 50, false},
+		{"UnconstrainedClassValueNotSet", nil, 0, 20, 20, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			got, err := tc.policy.ResolveInt("storageSize", tc.classValue, tc.claimValue)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(got).To(Equal(tc.want))
+		})
+	}
+}
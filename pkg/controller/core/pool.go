@@ -0,0 +1,525 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+)
+
+const (
+	errorListingPooledResources     = "Failed to list pooled resources"
+	errorProvisioningPooledResource = "Failed to provision pooled resource"
+	errorDeletingPooledResource     = "Failed to delete pooled resource"
+	errorShrinkingPool              = "Failed to shrink pool"
+
+	planPoolProvision = "Would provision pooled resource"
+	planPoolDelete    = "Would delete pooled resource"
+
+	// poolExpectationsTimeout is how long a pooled resource that was created
+	// is expected to appear in the cache of the pool controller.
+	poolExpectationsTimeout = 5 * time.Minute
+)
+
+// PoolReconciler keeps the pools of resource classes that pool resources for
+// a kind of resource claim filled with unbound resources.
+type PoolReconciler struct {
+	client.Client
+	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
+	claimKind string
+	newClaim  func() corev1alpha1.ResourceClaim
+	handlers  map[string]ResourceHandler
+	log       logr.Logger
+	expected  *poolExpectations
+	dryRun    bool
+
+	pooled func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error)
+}
+
+// NewPoolReconciler returns a PoolReconciler for resource classes that pool
+// resources for claims of the supplied kind. Pooled resources are provisioned
+// by the supplied handlers on behalf of placeholder claims returned by
+// newClaim. Handlers whose kinds of resources are not registered with the
+// scheme of the manager are ignored.
+func NewPoolReconciler(mgr manager.Manager, controllerName, claimKind string, newClaim func() corev1alpha1.ResourceClaim, handlers map[string]ResourceHandler) *PoolReconciler {
+	r := &PoolReconciler{
+		Client:    mgr.GetClient(),
+		scheme:    mgr.GetScheme(),
+		recorder:  mgr.GetRecorder(controllerName),
+		claimKind: claimKind,
+		newClaim:  newClaim,
+		handlers:  enabledHandlers(mgr.GetScheme(), handlers),
+		log:       logging.Log.WithName(controllerName),
+		expected:  newPoolExpectations(),
+		dryRun:    DryRun,
+	}
+	r.pooled = func(ctx context.Context, class *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return PooledResources(ctx, r.Client, r.scheme, class)
+	}
+	return r
+}
+
+// AddPool creates a new pool controller for claims of the supplied kind and
+// adds it to the manager. The pool is refilled whenever a resource class or
+// a pooled resource of one of the supplied types changes. Types that are not
+// registered with the scheme of the manager are not watched.
+func AddPool(mgr manager.Manager, controllerName, claimKind string, newClaim func() corev1alpha1.ResourceClaim, handlers map[string]ResourceHandler, resources ...runtime.Object) error {
+	c, err := controller.New(controllerName, mgr, controller.Options{
+		Reconciler: metrics.InstrumentReconciler(controllerName, NewPoolReconciler(mgr, controllerName, claimKind, newClaim, handlers)),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := c.Watch(&source.Kind{Type: &corev1alpha1.ResourceClass{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+
+	for _, res := range enabledResources(mgr.GetScheme(), resources) {
+		if err := c.Watch(&source.Kind{Type: res}, &handler.EnqueueRequestsFromMapFunc{ToRequests: &poolClassMapper{}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reconcile provisions or deletes unbound resources until the pool of the
+// requested resource class contains the configured number of resources.
+// In dry-run mode the resources that would be provisioned or deleted are only
+// recorded in events of the resource class.
+func (r *PoolReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := logging.NewContext(context.Background(), r.log, request)
+
+	class := &corev1alpha1.ResourceClass{}
+	if err := r.Get(ctx, request.NamespacedName, class); err != nil {
+		if errors.IsNotFound(err) {
+			return Result, nil
+		}
+		return Result, err
+	}
+
+	if !isPoolFor(class, r.claimKind) {
+		return Result, nil
+	}
+
+	handler, ok := r.handlers[class.Provisioner]
+	if !ok {
+		// let an external provisioner fill the pool of this class
+		return Result, nil
+	}
+
+	pooled, err := r.pooled(ctx, class)
+	if err != nil {
+		logging.RecordEvent(ctx, r.recorder, class, corev1.EventTypeWarning, errorListingPooledResources, err.Error())
+		return Result, err
+	}
+
+	// The cache may not contain the resources created by an earlier
+	// reconcile yet, so they are counted until they appear in it.
+	key := types.NamespacedName{Namespace: class.Namespace, Name: class.Name}
+	for i := len(pooled) + r.expected.pending(key, pooled); i < class.Pool.Size; i++ {
+		if r.isDryRun(class) {
+			r.plan(ctx, class, planPoolProvision, fmt.Sprintf("%d pooled resources would be provisioned", class.Pool.Size-i))
+			break
+		}
+		logging.FromContext(ctx).Info("provisioning pooled resource", "pooled", i, "size", class.Pool.Size)
+		res, err := handler.Provision(ctx, class, r.poolClaim(class), &poolClient{Client: r.Client, class: class})
+		if err != nil {
+			logging.RecordEvent(ctx, r.recorder, class, corev1.EventTypeWarning, errorProvisioningPooledResource, err.Error())
+			return Result, err
+		}
+		r.expected.expect(key, res)
+	}
+
+	// Deleting a pooled resource that retains its external resource would
+	// orphan the external resource, so only those that delete it are removed.
+	excess := len(pooled) - class.Pool.Size
+	for i := len(pooled) - 1; i >= 0 && excess > 0; i-- {
+		if pooled[i].ReclaimPolicy().OrDefault() == corev1alpha1.ReclaimRetain {
+			msg := fmt.Sprintf("pooled resource %s retains its external resource, and is not deleted to shrink the pool", pooled[i].ObjectReference().Name)
+			logging.RecordEvent(ctx, r.recorder, class, corev1.EventTypeWarning, errorShrinkingPool, msg)
+			continue
+		}
+		if r.isDryRun(class) {
+			r.plan(ctx, class, planPoolDelete, fmt.Sprintf("pooled resource %s would be deleted to shrink the pool", pooled[i].ObjectReference().Name))
+			excess--
+			continue
+		}
+		if err := r.Delete(ctx, pooled[i]); err != nil && !errors.IsNotFound(err) {
+			logging.RecordEvent(ctx, r.recorder, class, corev1.EventTypeWarning, errorDeletingPooledResource, err.Error())
+			return Result, err
+		}
+		excess--
+	}
+
+	return Result, nil
+}
+
+// isDryRun returns true if the pool of the supplied class is reconciled in
+// dry-run mode, i.e. pooled resources must not be provisioned or deleted.
+func (r *PoolReconciler) isDryRun(class *corev1alpha1.ResourceClass) bool {
+	return r.dryRun || corev1alpha1.IsDryRun(class)
+}
+
+// plan records the change that would be made to the pool of the supplied
+// class in dry-run mode, without making it.
+func (r *PoolReconciler) plan(ctx context.Context, class *corev1alpha1.ResourceClass, reason, message string) {
+	logging.FromContext(ctx).Info(message, "reason", reason)
+	logging.RecordEvent(ctx, r.recorder, class, corev1.EventTypeNormal, reason, message)
+}
+
+// poolExpectations tracks the pooled resources a PoolReconciler created that
+// it has not yet observed in its cache, so that a stale cache does not cause
+// the pool of a class to be filled twice.
+type poolExpectations struct {
+	mu      sync.Mutex
+	created map[types.NamespacedName]map[types.NamespacedName]time.Time
+}
+
+func newPoolExpectations() *poolExpectations {
+	return &poolExpectations{created: map[types.NamespacedName]map[types.NamespacedName]time.Time{}}
+}
+
+// expect the supplied resource to appear in the pool of the supplied class.
+func (e *poolExpectations) expect(class types.NamespacedName, res corev1alpha1.Resource) {
+	if res == nil || res.ObjectReference() == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.created[class] == nil {
+		e.created[class] = map[types.NamespacedName]time.Time{}
+	}
+	ref := res.ObjectReference()
+	e.created[class][types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}] = time.Now()
+}
+
+// pending returns the number of resources created for the pool of the
+// supplied class that are not among the supplied pooled resources. Resources
+// that were observed, or that were expected for too long, are forgotten.
+func (e *poolExpectations) pending(class types.NamespacedName, pooled []corev1alpha1.Resource) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	created := e.created[class]
+	for _, res := range pooled {
+		if ref := res.ObjectReference(); ref != nil {
+			delete(created, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name})
+		}
+	}
+	for name, t := range created {
+		if time.Since(t) > poolExpectationsTimeout {
+			delete(created, name)
+		}
+	}
+	if len(created) == 0 {
+		delete(e.created, class)
+	}
+	return len(created)
+}
+
+// poolClaim returns a placeholder claim on whose behalf a pooled resource of
+// the supplied class is provisioned. Each placeholder claim has a unique UID,
+// from which handlers derive the names of the resources they provision.
+func (r *PoolReconciler) poolClaim(class *corev1alpha1.ResourceClass) corev1alpha1.ResourceClaim {
+	claim := r.newClaim()
+	claim.SetNamespace(class.Namespace)
+	claim.SetName(class.Name + "-pool")
+	claim.SetUID(uuid.NewUUID())
+	claim.SetClassRef(class.ObjectReference())
+	return claim
+}
+
+// poolClient creates resources as members of the pool of a resource class,
+// rather than as resources bound to the placeholder claim they were
+// provisioned for.
+type poolClient struct {
+	client.Client
+	class *corev1alpha1.ResourceClass
+}
+
+func (c *poolClient) Create(ctx context.Context, obj runtime.Object) error {
+	if res, ok := obj.(corev1alpha1.Resource); ok {
+		res.SetClaimRef(nil)
+	}
+	if o, err := meta.Accessor(obj); err == nil {
+		o.SetOwnerReferences(nil)
+		l := o.GetLabels()
+		if l == nil {
+			l = map[string]string{}
+		}
+		l[corev1alpha1.LabelPoolClass] = c.class.Name
+		o.SetLabels(l)
+	}
+	return c.Client.Create(ctx, obj)
+}
+
+// dryRunClient does not create the resources it is asked to create. It lets
+// the spec that a handler would provision for a claim be compared with the
+// spec of a pooled resource.
+type dryRunClient struct {
+	client.Client
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// wantedResource returns a function that returns the resource the supplied
+// handler would provision for the supplied claim, without creating it. The
+// resource is only computed once, when it is first needed.
+func wantedResource(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, handler ResourceHandler, c client.Client) func() (corev1alpha1.Resource, error) {
+	var want corev1alpha1.Resource
+	var err error
+	done := false
+	return func() (corev1alpha1.Resource, error) {
+		if !done {
+			want, err = handler.Provision(ctx, class, claim, &dryRunClient{Client: c})
+			done = true
+		}
+		return want, err
+	}
+}
+
+// satisfyingClient lists only the unbound resources that satisfy the resource
+// wanted by a claim, so that handlers do not match resources of e.g. another
+// engine or version than the claim asks for. Bound and released resources
+// cannot be matched by a claim, so they are not listed.
+type satisfyingClient struct {
+	client.Client
+	want func() (corev1alpha1.Resource, error)
+}
+
+func (c *satisfyingClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	if err := c.Client.List(ctx, opts, list); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+
+	resources := make([]corev1alpha1.Resource, 0, len(items))
+	for _, item := range items {
+		res, ok := item.(corev1alpha1.Resource)
+		if !ok {
+			return fmt.Errorf("unexpected resource type: %T", item)
+		}
+		if res.IsBound() || res.IsReleased() {
+			continue
+		}
+		resources = append(resources, res)
+	}
+	if len(resources) == 0 {
+		return meta.SetList(list, nil)
+	}
+	satisfying, err := filterSatisfying(resources, c.want)
+	if err != nil {
+		return err
+	}
+
+	filtered := make([]runtime.Object, 0, len(satisfying))
+	for _, res := range satisfying {
+		filtered = append(filtered, res)
+	}
+	return meta.SetList(list, filtered)
+}
+
+// filterSatisfying returns the supplied resources that satisfy the resource
+// returned by want.
+func filterSatisfying(resources []corev1alpha1.Resource, want func() (corev1alpha1.Resource, error)) ([]corev1alpha1.Resource, error) {
+	w, err := want()
+	if err != nil {
+		return nil, err
+	}
+
+	var satisfying []corev1alpha1.Resource
+	for _, res := range resources {
+		ok, err := satisfies(res, w)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			satisfying = append(satisfying, res)
+		}
+	}
+	return satisfying, nil
+}
+
+// satisfies returns true if the spec of the supplied pooled resource has the
+// values of the supplied wanted resource. Empty values of the wanted resource,
+// e.g. of fields that were defaulted after the pooled resource was created,
+// and its class and claim references are ignored.
+func satisfies(pooled, want corev1alpha1.Resource) (bool, error) {
+	ps, err := resourceSpec(pooled)
+	if err != nil {
+		return false, err
+	}
+	ws, err := resourceSpec(want)
+	if err != nil {
+		return false, err
+	}
+	return subset(ws, ps), nil
+}
+
+// resourceSpec returns the spec of the supplied resource, without its class and
+// claim references. Only the spec is marshalled, so that the status of the
+// resource cannot affect whether it satisfies a claim. Resources without a
+// spec have an empty spec.
+func resourceSpec(res corev1alpha1.Resource) (map[string]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(res))
+	if v.Kind() != reflect.Struct || !v.FieldByName("Spec").IsValid() {
+		return map[string]interface{}{}, nil
+	}
+	j, err := json.Marshal(v.FieldByName("Spec").Interface())
+	if err != nil {
+		return nil, err
+	}
+	spec := map[string]interface{}{}
+	if err := json.Unmarshal(j, &spec); err != nil {
+		return nil, err
+	}
+	delete(spec, "claimRef")
+	delete(spec, "classRef")
+	return spec, nil
+}
+
+// subset returns true if every non-empty value of the supplied JSON value want
+// equals the corresponding value of got.
+func subset(want, got interface{}) bool {
+	switch w := want.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		g, _ := got.(map[string]interface{})
+		for k, v := range w {
+			if !subset(v, g[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		return len(w) == 0 || reflect.DeepEqual(w, got)
+	case string:
+		return w == "" || w == got
+	case float64:
+		return w == 0 || w == got
+	case bool:
+		return !w || w == got
+	}
+	return reflect.DeepEqual(want, got)
+}
+
+// poolClassMapper maps a pooled resource to a reconcile request for the
+// resource class whose pool it belongs, or belonged, to.
+type poolClassMapper struct{}
+
+func (m *poolClassMapper) Map(o handler.MapObject) []reconcile.Request {
+	if o.Meta == nil {
+		return nil
+	}
+	class, ok := o.Meta.GetLabels()[corev1alpha1.LabelPoolClass]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: o.Meta.GetNamespace(), Name: class}}}
+}
+
+// PooledResources returns the unbound resources in the pool of the supplied
+// resource class. The kind of resource to search for is determined by the
+// class provisioner, and must be registered with the supplied scheme.
+func PooledResources(ctx context.Context, c client.Client, scheme *runtime.Scheme, class *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+	list, err := resourceList(scheme, class.Provisioner)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.SelectorFromSet(labels.Set{corev1alpha1.LabelPoolClass: class.Name})
+	if err := c.List(ctx, &client.ListOptions{Namespace: class.Namespace, LabelSelector: selector}, list); err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	var pooled []corev1alpha1.Resource
+	for _, item := range items {
+		res, ok := item.(corev1alpha1.Resource)
+		if !ok {
+			return nil, fmt.Errorf("unexpected resource type: %T", item)
+		}
+		if res.ClaimRef() == nil && !res.IsBound() && !res.IsReleased() {
+			pooled = append(pooled, res)
+		}
+	}
+	return pooled, nil
+}
+
+// resourceList returns an empty list of the kind of resource identified by the
+// supplied provisioner, e.g. "rdsinstance.database.aws.crossplane.io/v1alpha1".
+func resourceList(scheme *runtime.Scheme, provisioner string) (runtime.Object, error) {
+	gvk, err := resourceKind(scheme, provisioner)
+	if err != nil {
+		return nil, err
+	}
+	return scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+}
+
+// resourceKind returns the kind of resource identified by the supplied
+// provisioner, which must be registered with the supplied scheme.
+func resourceKind(scheme *runtime.Scheme, provisioner string) (schema.GroupVersionKind, error) {
+	for gvk := range scheme.AllKnownTypes() {
+		if strings.EqualFold(gvk.Kind+"."+gvk.GroupVersion().String(), provisioner) {
+			return gvk, nil
+		}
+	}
+	return schema.GroupVersionKind{}, fmt.Errorf("unknown resource kind for provisioner %s", provisioner)
+}
+
+// isPoolFor returns true if the supplied resource class pools resources for
+// claims of the supplied kind.
+func isPoolFor(class *corev1alpha1.ResourceClass, claimKind string) bool {
+	return class.Pool != nil && strings.EqualFold(class.Pool.ClaimKind, claimKind)
+}
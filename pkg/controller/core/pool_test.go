@@ -0,0 +1,283 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+const testClaimKindAPIVersion = "testresourceclaim.core.crossplane.io/v1alpha1"
+
+func TestPoolReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "system", Name: "foo"}}
+
+	size := 2
+	mc := &MockClient{}
+	mc.MockGet = func(args ...interface{}) error {
+		class := args[2].(*corev1alpha1.ResourceClass)
+		class.Namespace = "system"
+		class.Name = "foo"
+		class.Provisioner = "test-provisioner"
+		class.Pool = &corev1alpha1.ResourcePool{ClaimKind: testClaimKindAPIVersion, Size: size}
+		return nil
+	}
+
+	h := &MockResourceHandler{}
+	r := &PoolReconciler{
+		Client:    mc,
+		recorder:  &MockRecorder{},
+		claimKind: testClaimKindAPIVersion,
+		newClaim:  func() corev1alpha1.ResourceClaim { return testClaim() },
+		handlers:  map[string]ResourceHandler{"test-provisioner": h},
+		expected:  newPoolExpectations(),
+	}
+
+	warm := corev1alpha1.NewBasicResource(&corev1.ObjectReference{Namespace: "system", Name: "warm"}, "", "", "available")
+	warm.SetReclaimPolicy(corev1alpha1.ReclaimDelete)
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return []corev1alpha1.Resource{warm}, nil
+	}
+
+	// test: pool is missing a resource, one is provisioned for a placeholder claim
+	var claims []corev1alpha1.ResourceClaim
+	created := corev1alpha1.NewBasicResource(&corev1.ObjectReference{Namespace: "system", Name: "created"}, "", "", "")
+	h.MockProvision = func(_ *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+		g.Expect(c).To(BeAssignableToTypeOf(&poolClient{}))
+		claims = append(claims, claim)
+		return created, nil
+	}
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(claims).To(HaveLen(1))
+	g.Expect(claims[0].GetNamespace()).To(Equal("system"))
+	g.Expect(claims[0].GetUID()).NotTo(BeEmpty())
+
+	// test: the created resource is not yet in the cache, and is not provisioned again
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(claims).To(HaveLen(1))
+
+	// test: the created resource was observed, and taken from the pool by a claim
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return []corev1alpha1.Resource{warm, created}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(claims).To(HaveLen(1))
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return []corev1alpha1.Resource{warm}, nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(claims).To(HaveLen(2))
+	r.expected = newPoolExpectations()
+
+	// test: provisioning fails
+	h.MockProvision = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("test-provision-error")
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).To(MatchError("test-provision-error"))
+	g.Expect(rs).To(Equal(Result))
+
+	// test: pool has too many resources, the surplus is deleted
+	size = 0
+	var deleted []interface{}
+	mc.MockDelete = func(args ...interface{}) error {
+		deleted = append(deleted, args[1])
+		return nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(deleted).To(Equal([]interface{}{warm}))
+
+	// test: the surplus retains its external resource, and is not deleted
+	deleted = nil
+	warm.SetReclaimPolicy(corev1alpha1.ReclaimRetain)
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(deleted).To(BeEmpty())
+
+	// test: pool is missing resources in dry-run mode, none are provisioned
+	size = 3
+	r.dryRun = true
+	h.MockProvision = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("provision should not be called")
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+
+	// test: pool has too many resources in dry-run mode, the surplus is not deleted
+	size = 0
+	deleted = nil
+	warm.SetReclaimPolicy(corev1alpha1.ReclaimDelete)
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(deleted).To(BeEmpty())
+	r.dryRun = false
+
+	// test: the class of the pool is annotated for dry-run mode, the surplus is not deleted
+	mc.MockGet = func(args ...interface{}) error {
+		class := args[2].(*corev1alpha1.ResourceClass)
+		class.Namespace = "system"
+		class.Name = "foo"
+		class.Provisioner = "test-provisioner"
+		class.Pool = &corev1alpha1.ResourcePool{ClaimKind: testClaimKindAPIVersion, Size: size}
+		class.SetAnnotations(map[string]string{corev1alpha1.AnnotationDryRun: "true"})
+		return nil
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(deleted).To(BeEmpty())
+
+	// test: class pools resources for another kind of claim
+	r.claimKind = "otherclaim.core.crossplane.io/v1alpha1"
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("pooled should not be called")
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+}
+
+func TestSatisfies(t *testing.T) {
+	g := NewGomegaWithT(t)
+	resource := func(version, size string) *corev1alpha1.ExternalResource {
+		return &corev1alpha1.ExternalResource{Spec: corev1alpha1.ExternalResourceSpec{
+			Provisioner: "test-provisioner",
+			ClaimRef:    &corev1.ObjectReference{Name: "test-claim"},
+			Parameters:  map[string]string{"engineVersion": version, "size": size},
+		}}
+	}
+	pooled := resource("5.6", "20")
+	pooled.Spec.ClaimRef = nil
+	pooled.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
+
+	// test: the pooled resource has every value that is wanted
+	g.Expect(satisfies(pooled, resource("5.6", "20"))).To(BeTrue())
+
+	// test: empty wanted values are ignored
+	g.Expect(satisfies(pooled, resource("", "20"))).To(BeTrue())
+
+	// test: the pooled resource has a different value than is wanted
+	g.Expect(satisfies(pooled, resource("5.7", "20"))).To(BeFalse())
+}
+
+func TestPoolClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var created interface{}
+	mc := &MockClient{MockCreate: func(args ...interface{}) error {
+		created = args[1]
+		return nil
+	}}
+	c := &poolClient{Client: mc, class: &corev1alpha1.ResourceClass{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}}
+
+	obj := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Labels:          map[string]string{"app": "test"},
+		OwnerReferences: []metav1.OwnerReference{testClaim().OwnerReference()},
+	}}
+	g.Expect(c.Create(ctx, obj)).To(Succeed())
+	g.Expect(created).To(Equal(obj))
+	g.Expect(obj.OwnerReferences).To(BeEmpty())
+	g.Expect(obj.Labels).To(Equal(map[string]string{"app": "test", corev1alpha1.LabelPoolClass: "foo"}))
+}
+
+func TestPoolClassMapper(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := &poolClassMapper{}
+
+	pooled := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "system",
+		Labels:    map[string]string{corev1alpha1.LabelPoolClass: "foo"},
+	}}
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "system", Name: "foo"}}}
+	g.Expect(m.Map(handler.MapObject{Meta: pooled, Object: pooled})).To(Equal(want))
+
+	unpooled := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "system"}}
+	g.Expect(m.Map(handler.MapObject{Meta: unpooled, Object: unpooled})).To(BeEmpty())
+}
+
+func TestResourceList(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	list, err := resourceList(scheme.Scheme, "resourceclass.core.crossplane.io/v1alpha1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(list).To(BeAssignableToTypeOf(&corev1alpha1.ResourceClassList{}))
+
+	_, err = resourceList(scheme.Scheme, "unknown.core.crossplane.io/v1alpha1")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestSatisfyingClient(t *testing.T) {
+	g := NewGomegaWithT(t)
+	resource := func(name string, phase corev1alpha1.BindingState) corev1alpha1.ExternalResource {
+		res := corev1alpha1.ExternalResource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: name},
+			Spec: corev1alpha1.ExternalResourceSpec{
+				Provisioner: "test-provisioner",
+				Parameters:  map[string]string{"engineVersion": "5.6"},
+			},
+		}
+		res.Status.Phase = phase
+		return res
+	}
+	free := resource("free", corev1alpha1.BindingStateUnbound)
+	bound := resource("bound", corev1alpha1.BindingStateBound)
+	released := resource("released", corev1alpha1.BindingStateReleased)
+
+	mc := &MockClient{MockList: func(args ...interface{}) error {
+		args[2].(*corev1alpha1.ExternalResourceList).Items = []corev1alpha1.ExternalResource{bound, free, released}
+		return nil
+	}}
+	want := resource("", corev1alpha1.BindingStateUnbound)
+	c := &satisfyingClient{Client: mc, want: func() (corev1alpha1.Resource, error) { return &want, nil }}
+
+	// test: only the free resource is listed next to bound and released ones
+	list := &corev1alpha1.ExternalResourceList{}
+	g.Expect(c.List(ctx, &client.ListOptions{}, list)).To(Succeed())
+	g.Expect(list.Items).To(Equal([]corev1alpha1.ExternalResource{free}))
+
+	// test: nothing is listed if the free resource does not satisfy the claim
+	want.Spec.Parameters = map[string]string{"engineVersion": "5.7"}
+	list = &corev1alpha1.ExternalResourceList{}
+	g.Expect(c.List(ctx, &client.ListOptions{}, list)).To(Succeed())
+	g.Expect(list.Items).To(BeEmpty())
+}
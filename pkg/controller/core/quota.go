@@ -0,0 +1,298 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+// quotaUsage is the usage of claim quotas observed when provisioning a claim.
+type quotaUsage struct {
+	// number of provisioned claims of the same kind as the claim
+	claims int
+	// number of provisioned claims of the same kind that use the claim's class
+	class int
+	// aggregate storage of the resources bound to claims of the namespace, if
+	// any kind of resource that provisions storage is known
+	storageGB      int64
+	storageTracked bool
+}
+
+// quotaExceededError is returned when provisioning a claim would exceed a
+// claim quota of its namespace.
+type quotaExceededError struct {
+	quota   string
+	message string
+}
+
+func (e *quotaExceededError) Error() string {
+	return fmt.Sprintf("claim quota %s exceeded: %s", e.quota, e.message)
+}
+
+// isQuotaExceeded returns true if the supplied error was returned because a
+// claim quota would be exceeded.
+func isQuotaExceeded(err error) bool {
+	_, ok := err.(*quotaExceededError)
+	return ok
+}
+
+// enforceQuotas returns an error if binding an existing resource to, or
+// provisioning a new resource for, the supplied claim would exceed any of the
+// claim quotas of the claim's namespace. The resource would provide the
+// supplied storage, in GB. The usage observed before the claim is bound is
+// recorded in the status of each quota.
+func (r *Reconciler) enforceQuotas(ctx context.Context, claim corev1alpha1.ResourceClaim, requestedGB int64) error {
+	quotas, u, err := r.recordQuotaUsage(ctx, claim, false)
+	if err != nil {
+		return err
+	}
+
+	for i := range quotas {
+		if err := checkQuota(&quotas[i], claimKind(claim), claimClassKey(claim), requestedGB, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshQuotas records the usage observed after the supplied claim was bound
+// or released in the status of each claim quota of the claim's namespace.
+func (r *Reconciler) refreshQuotas(ctx context.Context, claim corev1alpha1.ResourceClaim) error {
+	_, _, err := r.recordQuotaUsage(ctx, claim, isProvisioned(claim))
+	return err
+}
+
+// recordQuotaUsage records the usage of the claim kind and class of the
+// supplied claim in the status of each claim quota of the claim's namespace,
+// counting the claim itself only if requested. The quotas are returned along
+// with the usage of all other claims.
+func (r *Reconciler) recordQuotaUsage(ctx context.Context, claim corev1alpha1.ResourceClaim, self bool) ([]corev1alpha1.ClaimQuota, *quotaUsage, error) {
+	quotas := &corev1alpha1.ClaimQuotaList{}
+	if err := r.List(ctx, &client.ListOptions{Namespace: claim.GetNamespace()}, quotas); err != nil {
+		return nil, nil, err
+	}
+	if len(quotas.Items) == 0 {
+		return nil, nil, nil
+	}
+
+	u, err := r.usage(ctx, claim)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kind := claimKind(claim)
+	key := claimClassKey(claim)
+	observed := *u
+	if self {
+		observed.claims++
+		if key != "" {
+			observed.class++
+		}
+	}
+
+	for i := range quotas.Items {
+		q := &quotas.Items[i]
+		used := q.Status.Used.DeepCopy()
+		recordUsage(used, kind, key, &observed)
+		if !reflect.DeepEqual(used, &q.Status.Used) {
+			q.Status.Used = *used
+			if err := r.Update(ctx, q); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return quotas.Items, u, nil
+}
+
+// _usage counts the provisioned claims of the namespace, other than the
+// supplied claim, that are of the same kind as the supplied claim, and sums the storage of the resources of every
+// known kind that provisions storage that are bound to claims of the
+// namespace, whichever kind of claim they are bound to.
+func (r *Reconciler) _usage(ctx context.Context, claim corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+	u := &quotaUsage{}
+
+	list, err := resourceList(r.scheme, claimKind(claim))
+	if err != nil {
+		return nil, err
+	}
+	if err := r.List(ctx, &client.ListOptions{Namespace: claim.GetNamespace()}, list); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	key := claimClassKey(claim)
+	for _, item := range items {
+		c, ok := item.(corev1alpha1.ResourceClaim)
+		if !ok || c.GetName() == claim.GetName() || !isProvisioned(c) {
+			continue
+		}
+		u.claims++
+		if key != "" && claimClassKey(c) == key {
+			u.class++
+		}
+	}
+
+	for _, gvk := range storageKinds(r.scheme) {
+		u.storageTracked = true
+
+		list, err := r.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err != nil {
+			return nil, err
+		}
+		if err := r.List(ctx, &client.ListOptions{}, list); err != nil {
+			return nil, err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			res, ok := item.(corev1alpha1.StorageResource)
+			if !ok || res.IsReleased() {
+				continue
+			}
+			if ref := res.ClaimRef(); ref != nil && ref.Namespace == claim.GetNamespace() {
+				u.storageGB += res.StorageGB()
+			}
+		}
+	}
+
+	return u, nil
+}
+
+// storageKinds returns the kinds of resource registered with the supplied
+// scheme that provision storage, ordered by kind.
+func storageKinds(scheme *runtime.Scheme) []schema.GroupVersionKind {
+	var kinds []schema.GroupVersionKind
+	for gvk := range scheme.AllKnownTypes() {
+		if strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		obj, err := scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+		if _, ok := obj.(corev1alpha1.StorageResource); ok {
+			kinds = append(kinds, gvk)
+		}
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// recordUsage records the supplied usage of the supplied claim kind and class
+// in the usage reported by a claim quota. The usage of other claim kinds and
+// classes is left as it was last observed, and no class usage is recorded for
+// an empty class.
+func recordUsage(used *corev1alpha1.ClaimQuotaUsage, kind, class string, u *quotaUsage) {
+	if used.Claims == nil {
+		used.Claims = map[string]int{}
+	}
+	used.Claims[kind] = u.claims
+
+	if class != "" {
+		if used.Classes == nil {
+			used.Classes = map[string]int{}
+		}
+		used.Classes[class] = u.class
+	}
+
+	if u.storageTracked {
+		used.StorageGB = u.storageGB
+	}
+}
+
+// checkQuota returns an error if provisioning one more claim of the supplied
+// kind and class, requesting the supplied storage, would exceed the supplied
+// quota given the supplied usage.
+func checkQuota(q *corev1alpha1.ClaimQuota, kind, class string, requestedGB int64, u *quotaUsage) error {
+	if limit, ok := lookupLimit(q.Spec.Claims, kind); ok && u.claims+1 > limit {
+		return &quotaExceededError{quota: q.Name, message: fmt.Sprintf("%d of %d %s claims are in use", u.claims, limit, kind)}
+	}
+
+	if limit, ok := lookupLimit(q.Spec.Classes, class); ok && u.class+1 > limit {
+		return &quotaExceededError{quota: q.Name, message: fmt.Sprintf("%d of %d claims of resource class %s are in use", u.class, limit, class)}
+	}
+
+	if limit := q.Spec.StorageGB; limit != nil && requestedGB > 0 && u.storageGB+requestedGB > *limit {
+		return &quotaExceededError{quota: q.Name, message: fmt.Sprintf("%dGB of %dGB storage is in use, %dGB requested", u.storageGB, *limit, requestedGB)}
+	}
+
+	return nil
+}
+
+// lookupLimit returns the limit keyed by the supplied key, ignoring case.
+func lookupLimit(limits map[string]int, key string) (int, bool) {
+	for k, limit := range limits {
+		if strings.EqualFold(k, key) {
+			return limit, true
+		}
+	}
+	return 0, false
+}
+
+// classKey returns the key of the referenced resource class in claim quotas.
+func classKey(ref *corev1.ObjectReference) string {
+	return ref.Namespace + "/" + ref.Name
+}
+
+// claimClassKey returns the key of the resource class of the supplied claim in
+// claim quotas, or an empty string if the claim references no class.
+func claimClassKey(claim corev1alpha1.ResourceClaim) string {
+	if ref := claim.ClassRef(); ref != nil {
+		return classKey(ref)
+	}
+	return ""
+}
+
+// isProvisioned returns true if the supplied claim references a resource and
+// is not being deleted, and so counts towards claim quotas.
+func isProvisioned(claim corev1alpha1.ResourceClaim) bool {
+	return claim.ResourceRef() != nil && claim.GetDeletionTimestamp() == nil
+}
+
+// requestedStorageGB returns the storage, in GB, requested by the supplied
+// claim, or that resources of the supplied class are provisioned with if the
+// claim does not request storage. Zero is returned if neither configures
+// storage.
+func requestedStorageGB(claim corev1alpha1.ResourceClaim, class *corev1alpha1.ResourceClass) int64 {
+	if sc, ok := claim.(corev1alpha1.StorageClaim); ok && sc.RequestedStorageGB() > 0 {
+		return sc.RequestedStorageGB()
+	}
+	for _, param := range []string{"storageGB", "size"} {
+		if gb, err := strconv.ParseInt(class.Parameters[param], 10, 64); err == nil {
+			return gb
+		}
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+)
+
+func testQuota(spec corev1alpha1.ClaimQuotaSpec) corev1alpha1.ClaimQuota {
+	return corev1alpha1.ClaimQuota{ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: namespace}, Spec: spec}
+}
+
+func TestEnforceQuotas(t *testing.T) {
+	g := NewGomegaWithT(t)
+	class := &corev1alpha1.ResourceClass{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "foo"},
+		Parameters: map[string]string{"storageGB": "20"},
+	}
+	storage := int64(100)
+
+	var quotas []corev1alpha1.ClaimQuota
+	mc := &MockClient{}
+	mc.MockList = func(args ...interface{}) error {
+		args[2].(*corev1alpha1.ClaimQuotaList).Items = quotas
+		return nil
+	}
+	var updated []*corev1alpha1.ClaimQuota
+	mc.MockUpdate = func(args ...interface{}) error {
+		updated = append(updated, args[1].(*corev1alpha1.ClaimQuota))
+		return nil
+	}
+	r := &Reconciler{Client: mc}
+	claim := testClaim()
+	claim.SetClassRef(class.ObjectReference())
+
+	// test: namespace has no quotas, usage is not computed
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return nil, fmt.Errorf("usage should not be computed")
+	}
+	g.Expect(r.enforceQuotas(ctx, claim, requestedStorageGB(claim, class))).To(Succeed())
+
+	// test: quota is not exceeded, its usage is recorded
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return &quotaUsage{claims: 2, class: 1, storageGB: 70, storageTracked: true}, nil
+	}
+	quotas = []corev1alpha1.ClaimQuota{testQuota(corev1alpha1.ClaimQuotaSpec{
+		Claims:    map[string]int{testClaimKindAPIVersion: 3},
+		Classes:   map[string]int{"system/foo": 2},
+		StorageGB: &storage,
+	})}
+	g.Expect(r.enforceQuotas(ctx, claim, requestedStorageGB(claim, class))).To(Succeed())
+	g.Expect(updated).To(HaveLen(1))
+	g.Expect(updated[0].Status.Used).To(Equal(corev1alpha1.ClaimQuotaUsage{
+		Claims:    map[string]int{testClaimKindAPIVersion: 2},
+		Classes:   map[string]int{"system/foo": 1},
+		StorageGB: 70,
+	}))
+
+	// test: usage is unchanged, the quota is not updated
+	quotas = []corev1alpha1.ClaimQuota{*updated[0]}
+	updated = nil
+	g.Expect(r.enforceQuotas(ctx, claim, requestedStorageGB(claim, class))).To(Succeed())
+	g.Expect(updated).To(BeEmpty())
+
+	// test: the storage requested by the class would exceed the quota
+	class.Parameters["storageGB"] = "40"
+	err := r.enforceQuotas(ctx, claim, requestedStorageGB(claim, class))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(isQuotaExceeded(err)).To(BeTrue())
+
+	// test: the usage recorded after the claim is bound counts the claim
+	claim.Spec.ResourceRef = &corev1.ObjectReference{Name: "resource"}
+	updated = nil
+	g.Expect(r.refreshQuotas(ctx, claim)).To(Succeed())
+	g.Expect(updated).To(HaveLen(1))
+	g.Expect(updated[0].Status.Used.Claims).To(HaveKeyWithValue(testClaimKindAPIVersion, 3))
+	g.Expect(updated[0].Status.Used.Classes).To(HaveKeyWithValue("system/foo", 2))
+
+	// test: a claim without a class records no class usage
+	claim.Spec.ClassRef = nil
+	quotas = []corev1alpha1.ClaimQuota{testQuota(corev1alpha1.ClaimQuotaSpec{})}
+	updated = nil
+	g.Expect(r.refreshQuotas(ctx, claim)).To(Succeed())
+	g.Expect(updated).To(HaveLen(1))
+	g.Expect(updated[0].Status.Used.Classes).To(BeEmpty())
+
+	// test: updating the quota fails
+	quotas = []corev1alpha1.ClaimQuota{testQuota(corev1alpha1.ClaimQuotaSpec{})}
+	mc.MockUpdate = func(...interface{}) error { return fmt.Errorf("test-update-error") }
+	err = r.enforceQuotas(ctx, claim, 0)
+	g.Expect(err).To(MatchError("test-update-error"))
+	g.Expect(isQuotaExceeded(err)).To(BeFalse())
+}
+
+func TestUsage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	s := runtime.NewScheme()
+	g.Expect(corev1alpha1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+	g.Expect(storagev1alpha1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+	g.Expect(awsdbv1alpha1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+	g.Expect(gcpdbv1alpha1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+
+	claimRef := func(kind, namespace string) *corev1.ObjectReference {
+		return &corev1.ObjectReference{Kind: kind, Namespace: namespace, Name: "claim"}
+	}
+	rds := func(name string, size int64, ref *corev1.ObjectReference) *awsdbv1alpha1.RDSInstance {
+		return &awsdbv1alpha1.RDSInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: name},
+			Spec:       awsdbv1alpha1.RDSInstanceSpec{Size: size, ClaimRef: ref},
+		}
+	}
+	class := &corev1alpha1.ResourceClass{ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "foo"}}
+	mysql := func(name string, deleting bool) *storagev1alpha1.MySQLInstance {
+		c := &storagev1alpha1.MySQLInstance{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       storagev1alpha1.MySQLInstanceSpec{ClassRef: class.ObjectReference(), ResourceRef: &corev1.ObjectReference{Name: "mysql"}},
+		}
+		if deleting {
+			c.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return c
+	}
+	claim := mysql("claim", false)
+
+	// the reconciler handles no kind of resource that provisions storage, but
+	// the storage of every kind bound to claims of the namespace is summed.
+	// Neither the claim itself nor claims being deleted are counted.
+	r := &Reconciler{
+		Client: fake.NewFakeClientWithScheme(s,
+			claim,
+			mysql("other-claim", false),
+			mysql("deleted-claim", true),
+			rds("mysql", 20, claimRef(storagev1alpha1.MySQLInstanceKind, namespace)),
+			rds("postgres", 30, claimRef(storagev1alpha1.PostgreSQLInstanceKind, namespace)),
+			rds("other", 40, claimRef(storagev1alpha1.PostgreSQLInstanceKind, "other-namespace")),
+			&gcpdbv1alpha1.CloudsqlInstance{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "system", Name: "cloudsql"},
+				Spec:       gcpdbv1alpha1.CloudsqlInstanceSpec{StorageGB: 10, ClaimRef: claimRef(storagev1alpha1.PostgreSQLInstanceKind, namespace)},
+			},
+		),
+		scheme:   s,
+		handlers: map[string]ResourceHandler{},
+	}
+	u, err := r._usage(ctx, claim)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(u).To(Equal(&quotaUsage{claims: 1, class: 1, storageGB: 60, storageTracked: true}))
+}
+
+func TestCheckQuota(t *testing.T) {
+	g := NewGomegaWithT(t)
+	storage := int64(100)
+	q := testQuota(corev1alpha1.ClaimQuotaSpec{
+		Claims:    map[string]int{"MySQLInstance.storage.crossplane.io/v1alpha1": 2},
+		Classes:   map[string]int{"system/foo": 1},
+		StorageGB: &storage,
+	})
+	kind := "mysqlinstance.storage.crossplane.io/v1alpha1"
+
+	// test: within all limits
+	g.Expect(checkQuota(&q, kind, "system/bar", 10, &quotaUsage{claims: 1, storageGB: 90})).To(Succeed())
+
+	// test: claim kind limit is matched ignoring case
+	g.Expect(checkQuota(&q, kind, "system/bar", 0, &quotaUsage{claims: 2})).NotTo(Succeed())
+
+	// test: claims of kinds without a limit are not limited
+	g.Expect(checkQuota(&q, "bucket.storage.crossplane.io/v1alpha1", "system/bar", 0, &quotaUsage{claims: 10})).To(Succeed())
+
+	// test: class limit
+	g.Expect(checkQuota(&q, kind, "system/foo", 0, &quotaUsage{class: 1})).NotTo(Succeed())
+
+	// test: storage limit
+	g.Expect(checkQuota(&q, kind, "system/bar", 11, &quotaUsage{storageGB: 90})).NotTo(Succeed())
+
+	// test: classes that do not provision storage are not limited by storage
+	g.Expect(checkQuota(&q, kind, "system/bar", 0, &quotaUsage{storageGB: 200})).To(Succeed())
+}
+
+func TestRecordUsage(t *testing.T) {
+	g := NewGomegaWithT(t)
+	used := &corev1alpha1.ClaimQuotaUsage{
+		Claims:    map[string]int{"other": 3},
+		StorageGB: 50,
+	}
+
+	recordUsage(used, "kind", "system/foo", &quotaUsage{claims: 1, class: 1})
+	g.Expect(used).To(Equal(&corev1alpha1.ClaimQuotaUsage{
+		Claims:    map[string]int{"other": 3, "kind": 1},
+		Classes:   map[string]int{"system/foo": 1},
+		StorageGB: 50,
+	}))
+
+	recordUsage(used, "kind", "system/foo", &quotaUsage{claims: 2, class: 2, storageTracked: true})
+	g.Expect(used.StorageGB).To(BeZero())
+}
+
+func TestRequestedStorageGB(t *testing.T) {
+	g := NewGomegaWithT(t)
+	claim := testClaim()
+	g.Expect(requestedStorageGB(claim, &corev1alpha1.ResourceClass{Parameters: map[string]string{"storageGB": "20"}})).To(Equal(int64(20)))
+	g.Expect(requestedStorageGB(claim, &corev1alpha1.ResourceClass{Parameters: map[string]string{"size": "30"}})).To(Equal(int64(30)))
+	g.Expect(requestedStorageGB(claim, &corev1alpha1.ResourceClass{Parameters: map[string]string{"tier": "db-n1"}})).To(BeZero())
+
+	storageClaim := &testStorageClaim{testResourceClaim: claim, storageGB: 40}
+	g.Expect(requestedStorageGB(storageClaim, &corev1alpha1.ResourceClass{Parameters: map[string]string{"size": "30"}})).To(Equal(int64(40)))
+	storageClaim.storageGB = 0
+	g.Expect(requestedStorageGB(storageClaim, &corev1alpha1.ResourceClass{Parameters: map[string]string{"size": "30"}})).To(Equal(int64(30)))
+}
+
+// testStorageClaim is a claim that requests storage.
+type testStorageClaim struct {
+	*testResourceClaim
+	storageGB int64
+}
+
+func (c *testStorageClaim) RequestedStorageGB() int64 {
+	return c.storageGB
+}
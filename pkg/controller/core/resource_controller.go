@@ -18,11 +18,16 @@ package core
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"log"
+	"sort"
+	"strings"
 
+	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -33,34 +38,40 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
+	"github.com/crossplaneio/crossplane/pkg/tracing"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 const (
 	errorResourceProvisioning        = "Failed to provision new resource"
+	errorMatchingResource            = "Failed to match existing resource"
 	errorRetrievingHandler           = "Failed to retrieve handler"
 	errorRetrievingResourceClass     = "Failed to retrieve resource class"
 	errorRetrievingResource          = "Failed to retrieve resource"
 	errorRetrievingResourceSecret    = "Failed to retrieve resource secret"
+	errorValidatingResourceSecret    = "Failed to validate resource secret"
+	errorRenderingClaimSecret        = "Failed to render claim secret"
 	errorApplyingResourceSecret      = "Failed to apply resource secret"
 	errorSettingResourceBindStatus   = "Failed to set resource binding status"
 	errorResettingResourceBindStatus = "Failed to reset resource binding status"
+	errorResourceBoundToAnotherClaim = "Resource is bound to another claim"
+	errorEnforcingClaimQuota         = "Failed to enforce claim quota"
+	errorClaimQuotaExceeded          = "Claim quota exceeded"
 	waitResourceIsNotAvailable       = "Waiting for resource to become available"
 )
 
-// Commonly used reconciliation results.
-var (
-	Result        = reconcile.Result{}
-	ResultRequeue = reconcile.Result{Requeue: true}
-)
-
-var ctx = context.Background()
+// Result is the result of a reconcile that does not need to be requeued.
+var Result = reconcile.Result{}
 
-// ResourceHandler defines resource handing functions
+// ResourceHandler defines resource handing functions. The supplied context is
+// that of the reconcile on whose behalf the handler is called.
 type ResourceHandler interface {
-	Provision(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error)
-	Find(types.NamespacedName, client.Client) (corev1alpha1.Resource, error)
-	SetBindStatus(types.NamespacedName, client.Client, bool) error
+	Provision(context.Context, *corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error)
+	Match(context.Context, *corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error)
+	Find(context.Context, types.NamespacedName, client.Client) (corev1alpha1.Resource, error)
+	SetBindStatus(context.Context, types.NamespacedName, client.Client, bool) error
 }
 
 // Reconciler reconciles a resource claim
@@ -71,15 +82,24 @@ type Reconciler struct {
 	recorder      record.EventRecorder
 	finalizerName string
 	handlers      map[string]ResourceHandler
-
-	DoReconcile func(corev1alpha1.ResourceClaim) (reconcile.Result, error)
-	provision   func(corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error)
-	bind        func(corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error)
-	delete      func(corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error)
-	getHandler  func(claim corev1alpha1.ResourceClaim) (ResourceHandler, error)
+	secrets       *SecretDefinitionRegistry
+	requeue       *requeue.Policy
+
+	// Log is the logger of the controller
+	Log logr.Logger
+
+	DoReconcile func(context.Context, corev1alpha1.ResourceClaim) (reconcile.Result, error)
+	provision   func(context.Context, corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error)
+	bind        func(context.Context, corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error)
+	delete      func(context.Context, corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error)
+	getHandler  func(context.Context, corev1alpha1.ResourceClaim) (ResourceHandler, error)
+	pooled      func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error)
+	usage       func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error)
 }
 
-// NewReconciler initializes and returns a new Reconciler instance.
+// NewReconciler initializes and returns a new Reconciler instance. Handlers
+// whose kinds of resources are not registered with the scheme of the manager
+// are ignored.
 func NewReconciler(mgr manager.Manager, controllerName, finalizerName string, handlers map[string]ResourceHandler) *Reconciler {
 	r := &Reconciler{
 		Client:        mgr.GetClient(),
@@ -87,105 +107,205 @@ func NewReconciler(mgr manager.Manager, controllerName, finalizerName string, ha
 		kubeclient:    kubernetes.NewForConfigOrDie(mgr.GetConfig()),
 		recorder:      mgr.GetRecorder(controllerName),
 		finalizerName: finalizerName,
-		handlers:      handlers,
+		handlers:      enabledHandlers(mgr.GetScheme(), handlers),
+		secrets:       SecretDefinitions,
+		requeue:       requeue.NewPolicy(controllerName, requeue.Defaults),
+		Log:           logging.Log.WithName(controllerName),
 	}
 	r.DoReconcile = r._reconcile
 	r.provision = r._provision
 	r.bind = r._bind
 	r.delete = r._delete
 	r.getHandler = r._getHandler
+	r.pooled = func(ctx context.Context, class *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return PooledResources(ctx, r.Client, r.scheme, class)
+	}
+	r.usage = r._usage
 
 	return r
 }
 
 // HandleGetClaimError is a helper function to handle an error that was returned from a GET
-// operation on a concrete claim type.
-func HandleGetClaimError(err error) (reconcile.Result, error) {
+// operation on the concrete claim type with the supplied key. The failures of claims that
+// no longer exist are forgotten.
+func (r *Reconciler) HandleGetClaimError(key types.NamespacedName, err error) (reconcile.Result, error) {
 	if errors.IsNotFound(err) {
 		// Object not found, return.  Created objects are automatically garbage collected.
 		// For additional cleanup logic use finalizers.
+		r.requeue.Forget(key)
 		return Result, nil
 	}
 	return Result, err
 }
 
 // _reconcile runs the main reconcile loop of this controller, given the requested claim
-func (r *Reconciler) _reconcile(claim corev1alpha1.ResourceClaim) (reconcile.Result, error) {
+func (r *Reconciler) _reconcile(ctx context.Context, claim corev1alpha1.ResourceClaim) (reconcile.Result, error) {
+	ctx, span := tracing.StartSpan(ctx, "core.Reconciler.Reconcile", claim)
+	defer span.End()
+
+	// a claim that failed is not reconciled again until it has backed off
+	if d := r.requeue.Wait(claim); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
 	// get the resource handler for this claim
-	handler, err := r.getHandler(claim)
+	handler, err := r.getHandler(ctx, claim)
 	if err != nil {
-		return r.fail(claim, errorRetrievingHandler, err.Error())
+		return r.fail(ctx, claim, errorRetrievingHandler, err.Error())
 	} else if handler == nil {
 		// handler is not found - log this but don't fail, let an external provisioner handle it
-		log.Printf("handler for claim %s is unknown, ignoring reconcile to allow external provisioners to handle it", claim.GetName())
+		logging.WithClaim(r.Log, claim).V(logging.Debug).Info("handler for claim is unknown, ignoring reconcile to allow external provisioners to handle it")
 		return Result, nil
 	}
 
 	// Check for deletion
 	if claim.GetDeletionTimestamp() != nil && claim.ClaimStatus().Condition(corev1alpha1.Deleting) == nil {
-		return r.delete(claim, handler)
+		return r.delete(ctx, claim, handler)
 	}
 
 	// Add finalizer
 	if !util.HasFinalizer(claim, r.finalizerName) {
 		util.AddFinalizer(claim, r.finalizerName)
 		if err := r.Update(ctx, claim); err != nil {
-			return ResultRequeue, err
+			return Result, err
 		}
 	}
 
 	// check if claim reference is set, if not - provision new resource
 	if claim.ResourceRef() == nil {
-		return r.provision(claim, handler)
+		return r.provision(ctx, claim, handler)
 	}
 
 	// bind to the resource
-	return r.bind(claim, handler)
+	return r.bind(ctx, claim, handler)
 }
 
 // _provision based on class and parameters
-func (r *Reconciler) _provision(claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+func (r *Reconciler) _provision(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+	ctx, span := tracing.StartSpan(ctx, "core.Reconciler.provision", claim)
+	defer span.End()
+
 	// initialize the claim
 	claimStatus := claim.ClaimStatus()
 
 	// get the resource class for this claim
-	class, err := r.getResourceClass(claim)
+	class, err := r.getResourceClass(ctx, claim)
 	if err != nil {
-		return r.fail(claim, errorRetrievingResourceClass, err.Error())
+		return r.fail(ctx, claim, errorRetrievingResourceClass, err.Error())
+	}
+
+	// remember the class for claims that use the default resource class
+	if claim.ClassRef() == nil {
+		claim.SetClassRef(class.ObjectReference())
 	}
 
-	// create new resource
-	res, err := handler.Provision(class, claim, r.Client)
+	// binding an existing resource or creating a new one must not exceed a
+	// quota of the namespace
+	if err := r.enforceQuotas(ctx, claim, requestedStorageGB(claim, class)); err != nil {
+		if isQuotaExceeded(err) {
+			return r.fail(ctx, claim, errorClaimQuotaExceeded, err.Error())
+		}
+		return r.fail(ctx, claim, errorEnforcingClaimQuota, err.Error())
+	}
+
+	// existing resources were provisioned before the claim existed, so they
+	// may only be bound if they have the spec that would be provisioned for it
+	want := wantedResource(ctx, class, claim, handler, r.Client)
+
+	// try to statically bind to an existing resource matching the claim's selector
+	res, err := handler.Match(ctx, class, claim, &satisfyingClient{Client: r.Client, want: want})
 	if err != nil {
-		return r.fail(claim, errorResourceProvisioning, err.Error())
+		return r.fail(ctx, claim, errorMatchingResource, err.Error())
+	}
+
+	// otherwise try to bind to an unbound resource from the pool of the class
+	pooled, pool := false, ""
+	if res == nil && isPoolFor(class, claimKind(claim)) {
+		if res, err = r.takePooled(ctx, class, want); err != nil {
+			return r.fail(ctx, claim, errorMatchingResource, err.Error())
+		}
+		pooled = res != nil
+	}
+
+	reserved := res != nil
+	if reserved {
+		// reserve the matched resource so that it is not matched by another claim
+		res.SetClaimRef(claim.ObjectReference())
+		res.SetBound(true)
+		if pooled {
+			pool = adoptPooled(res, claim)
+		}
+		if err := r.Update(ctx, res); err != nil {
+			return r.fail(ctx, claim, errorSettingResourceBindStatus, err.Error())
+		}
+	} else {
+		// nothing matched - create new resource
+		// the controller of the new resource continues the trace of this reconcile
+		res, err = handler.Provision(ctx, class, claim, tracing.NewClient(ctx, r.Client))
+		if err != nil {
+			return r.fail(ctx, claim, errorResourceProvisioning, err.Error())
+		}
 	}
 
-	// set resource reference to the newly created resource
+	// set resource reference to the matched or newly created resource
 	claim.SetResourceRef(res.ObjectReference())
 
 	// set status values
 	claimStatus.Provisioner = class.Provisioner
 	claimStatus.SetCreating()
 
-	// update claim
-	return Result, r.Update(ctx, claim)
+	// update claim, releasing the reserved resource if the claim cannot
+	// reference it so that it can be matched again
+	if err := r.Update(ctx, claim); err != nil {
+		if reserved {
+			if uerr := r.unreserve(ctx, res, claim, pool); uerr != nil {
+				logging.FromContext(ctx).Error(uerr, "cannot release reserved resource", "resource", res.ObjectReference().Name)
+			}
+		}
+		return Result, err
+	}
+	return Result, nil
 }
 
 // _bind the given resource claim to a concrete Resource
-func (r *Reconciler) _bind(claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+func (r *Reconciler) _bind(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
 	// find resource instance
 	resNName := util.NamespaceNameFromObjectRef(claim.ResourceRef())
-	resource, err := handler.Find(resNName, r.Client)
+	resource, err := handler.Find(ctx, resNName, r.Client)
 	if err != nil {
 		// failed to retrieve the resource - requeue
-		return r.fail(claim, errorRetrievingResource, "")
+		return r.fail(ctx, claim, errorRetrievingResource, "")
 	}
 
+	// surface the state of the resource on the claim
+	summary := resourceStatusSummary(resource)
+	claim.ClaimStatus().ResourceStatus = summary
+
 	// check for resource state and requeue if not running
 	if !resource.IsAvailable() {
 		claim.ClaimStatus().UnsetAllConditions()
-		claim.ClaimStatus().SetCondition(corev1alpha1.NewCondition(corev1alpha1.Pending, waitResourceIsNotAvailable, "Resource is not in running state"))
-		return ResultRequeue, r.Update(ctx, claim)
+		claim.ClaimStatus().SetCondition(corev1alpha1.NewCondition(corev1alpha1.Pending, waitResourceIsNotAvailable, resourceNotAvailableMessage(summary)))
+		return r.requeue.Pending(requeue.Key(claim)), r.Update(ctx, claim)
+	}
+
+	// a resource that is bound to another claim cannot be bound to this one
+	if ref := resource.ClaimRef(); ref != nil && !isClaimRef(ref, claim) && resource.IsBound() {
+		return r.fail(ctx, claim, errorResourceBoundToAnotherClaim, fmt.Sprintf("resource is bound to claim %s/%s", ref.Namespace, ref.Name))
+	}
+
+	// reclaiming a released resource, or binding a resource named by the claim,
+	// must not exceed a quota of the namespace
+	if !isClaimRef(resource.ClaimRef(), claim) {
+		var requested int64
+		if sr, ok := resource.(corev1alpha1.StorageResource); ok {
+			requested = sr.StorageGB()
+		}
+		if err := r.enforceQuotas(ctx, claim, requested); err != nil {
+			if isQuotaExceeded(err) {
+				return r.fail(ctx, claim, errorClaimQuotaExceeded, err.Error())
+			}
+			return r.fail(ctx, claim, errorEnforcingClaimQuota, err.Error())
+		}
 	}
 
 	// Object reference to the resource: needed to retrieve resource's namespace to retrieve resource's secret
@@ -194,26 +314,49 @@ func (r *Reconciler) _bind(claim corev1alpha1.ResourceClaim, handler ResourceHan
 	// retrieve resource's secret
 	secret, err := r.kubeclient.CoreV1().Secrets(or.Namespace).Get(resource.ConnectionSecretName(), metav1.GetOptions{})
 	if err != nil {
-		return r.fail(claim, errorRetrievingResourceSecret, err.Error())
+		return r.fail(ctx, claim, errorRetrievingResourceSecret, err.Error())
+	}
+
+	// the secret must contain the keys defined for secrets of this kind of claim
+	if err := r.secrets.Validate(claimKind(claim), secret.Data); err != nil {
+		return r.fail(ctx, claim, errorValidatingResourceSecret, err.Error())
+	}
+
+	// rename and derive keys of the claim secret as requested by the claim
+	data, err := RenderSecret(claim.SecretTemplate(), secret.Data)
+	if err != nil {
+		return r.fail(ctx, claim, errorRenderingClaimSecret, err.Error())
 	}
+	secret.Data = data
 
 	// replace secret metadata with the consuming claim's metadata (same as in service)
 	secret.ObjectMeta = metav1.ObjectMeta{
 		Namespace:       claim.GetNamespace(),
 		Name:            claim.GetName(),
 		OwnerReferences: []metav1.OwnerReference{claim.OwnerReference()},
+		Annotations:     map[string]string{corev1alpha1.AnnotationSecretHash: secretHash(secret.Data)},
 	}
 	if _, err := util.ApplySecret(r.kubeclient, secret); err != nil {
-		return r.fail(claim, errorApplyingResourceSecret, err.Error())
+		return r.fail(ctx, claim, errorApplyingResourceSecret, err.Error())
+	}
+
+	// record this claim on the resource, reclaiming the resource if it was
+	// released by a previous claim
+	if !isClaimRef(resource.ClaimRef(), claim) {
+		resource.SetClaimRef(claim.ObjectReference())
+		if err := r.Update(ctx, resource); err != nil {
+			return r.fail(ctx, claim, errorSettingResourceBindStatus, err.Error())
+		}
 	}
 
 	// update resource binding status
-	if err := handler.SetBindStatus(resNName, r.Client, true); err != nil {
-		return r.fail(claim, errorSettingResourceBindStatus, err.Error())
+	if err := handler.SetBindStatus(ctx, resNName, r.Client, true); err != nil {
+		return r.fail(ctx, claim, errorSettingResourceBindStatus, err.Error())
 	}
 
 	// set claim binding status
 	claimStatus := claim.ClaimStatus()
+	bound := claimStatus.IsBound()
 	claimStatus.SetBound(true)
 
 	// save a local reference to the credentials secret in the claim's status
@@ -225,20 +368,32 @@ func (r *Reconciler) _bind(claim corev1alpha1.ResourceClaim, handler ResourceHan
 		claimStatus.SetReady()
 	}
 
-	return Result, r.Update(ctx, claim)
+	if err := r.Update(ctx, claim); err != nil {
+		return Result, err
+	}
+
+	// the newly bound claim now counts towards the quotas of the namespace
+	if !bound {
+		if err := r.refreshQuotas(ctx, claim); err != nil {
+			logging.FromContext(ctx).Error(err, "cannot refresh claim quotas")
+		}
+	}
+	return Result, nil
 }
 
 // _delete the given resource claim
-func (r *Reconciler) _delete(claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
-	// update resource binding status
-	resNName := util.NamespaceNameFromObjectRef(claim.ResourceRef())
-
-	// TODO: decide how to handle resource binding status update error
+func (r *Reconciler) _delete(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+	// TODO: decide how to handle resource release error
 	// - record an event for the error for now
-	if err := handler.SetBindStatus(resNName, r.Client, false); err != nil {
+	if err := r.release(ctx, claim, handler); err != nil {
 		r.recorder.Event(claim, corev1.EventTypeWarning, errorResettingResourceBindStatus, err.Error())
 	}
 
+	// the released claim no longer counts towards the quotas of the namespace
+	if err := r.refreshQuotas(ctx, claim); err != nil {
+		logging.FromContext(ctx).Error(err, "cannot refresh claim quotas")
+	}
+
 	// update claim status and remove finalizer
 	claimStatus := claim.ClaimStatus()
 	claimStatus.UnsetAllConditions()
@@ -247,14 +402,184 @@ func (r *Reconciler) _delete(claim corev1alpha1.ResourceClaim, handler ResourceH
 	return reconcile.Result{}, r.Update(ctx, claim)
 }
 
+// release the resource bound to the given claim. Resources with the Retain
+// reclaim policy enter the Released phase: they keep their reference to the
+// claim and are no longer owned by it, so that they survive its deletion and
+// may later be reclaimed. All other resources are unbound.
+func (r *Reconciler) release(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) error {
+	if claim.ResourceRef() == nil {
+		// the claim was never bound to a resource
+		return nil
+	}
+
+	res, err := handler.Find(ctx, util.NamespaceNameFromObjectRef(claim.ResourceRef()), r.Client)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if ref := res.ClaimRef(); ref != nil && !isClaimRef(ref, claim) {
+		// the resource has since been reclaimed by another claim
+		return nil
+	}
+
+	if res.ReclaimPolicy().OrDefault() == corev1alpha1.ReclaimRetain {
+		res.SetReleased()
+		if o, err := meta.Accessor(res); err == nil {
+			o.SetOwnerReferences(removeOwnerReference(o.GetOwnerReferences(), claim.GetUID()))
+		}
+	} else {
+		res.SetBound(false)
+		res.SetClaimRef(nil)
+	}
+
+	return r.Update(ctx, res)
+}
+
+// takePooled returns an unbound resource from the pool of the supplied class
+// that satisfies the resource wanted by a claim, preferring resources that are
+// already available. A nil resource is returned if no pooled resource
+// satisfies the claim.
+func (r *Reconciler) takePooled(ctx context.Context, class *corev1alpha1.ResourceClass, want func() (corev1alpha1.Resource, error)) (corev1alpha1.Resource, error) {
+	pooled, err := r.pooled(ctx, class)
+	if err != nil || len(pooled) == 0 {
+		return nil, err
+	}
+
+	satisfying, err := filterSatisfying(pooled, want)
+	if err != nil || len(satisfying) == 0 {
+		return nil, err
+	}
+	for _, res := range satisfying {
+		if res.IsAvailable() {
+			return res, nil
+		}
+	}
+	return satisfying[0], nil
+}
+
+// adoptPooled removes the supplied resource from the pool it belongs to and
+// makes it owned by the supplied claim, like a resource that was provisioned
+// for the claim. The pool controller provisions a replacement. The name of the
+// class of the pool is returned.
+func adoptPooled(res corev1alpha1.Resource, claim corev1alpha1.ResourceClaim) string {
+	o, err := meta.Accessor(res)
+	if err != nil {
+		return ""
+	}
+	l := o.GetLabels()
+	pool := l[corev1alpha1.LabelPoolClass]
+	delete(l, corev1alpha1.LabelPoolClass)
+	o.SetLabels(l)
+	o.SetOwnerReferences(append(o.GetOwnerReferences(), claim.OwnerReference()))
+	return pool
+}
+
+// unreserve undoes the reservation of the supplied resource for a claim that
+// could not be updated to reference it. Resources taken from a pool are
+// returned to the pool of the named class.
+func (r *Reconciler) unreserve(ctx context.Context, res corev1alpha1.Resource, claim corev1alpha1.ResourceClaim, pool string) error {
+	res.SetClaimRef(nil)
+	res.SetBound(false)
+	if pool != "" {
+		returnPooled(res, claim, pool)
+	}
+	return r.Update(ctx, res)
+}
+
+// returnPooled reverts adoptPooled: the supplied resource is added back to the
+// pool of the named class and is no longer owned by the supplied claim.
+func returnPooled(res corev1alpha1.Resource, claim corev1alpha1.ResourceClaim, pool string) {
+	o, err := meta.Accessor(res)
+	if err != nil {
+		return
+	}
+	l := o.GetLabels()
+	if l == nil {
+		l = map[string]string{}
+	}
+	l[corev1alpha1.LabelPoolClass] = pool
+	o.SetLabels(l)
+
+	owner := claim.OwnerReference()
+	refs := make([]metav1.OwnerReference, 0, len(o.GetOwnerReferences()))
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.UID != owner.UID || ref.Kind != owner.Kind || ref.Name != owner.Name {
+			refs = append(refs, ref)
+		}
+	}
+	o.SetOwnerReferences(refs)
+}
+
+// resourceStatusSummary returns a summary of the observed state of the
+// supplied resource, identifying the resource by its kind and name.
+func resourceStatusSummary(res corev1alpha1.Resource) corev1alpha1.ResourceStatusSummary {
+	summary := res.StatusSummary()
+	if ref := res.ObjectReference(); ref != nil {
+		summary.Kind = ref.Kind
+		summary.Name = ref.Name
+	}
+	return summary
+}
+
+// resourceNotAvailableMessage describes why the summarized resource is not
+// yet available to be bound.
+func resourceNotAvailableMessage(s corev1alpha1.ResourceStatusSummary) string {
+	switch {
+	case s.Message != "":
+		return fmt.Sprintf("Resource has failed: %s", s.Message)
+	case s.State != "":
+		return fmt.Sprintf("Resource is in state %s", s.State)
+	}
+	return "Resource is not in running state"
+}
+
+// secretHash returns a hash of the supplied secret data that changes whenever
+// any of its keys or values change.
+func secretHash(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b []byte
+	for _, k := range keys {
+		b = append(b, k...)
+		b = append(b, 0)
+		b = append(b, data[k]...)
+		b = append(b, 0)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// isClaimRef returns true if the supplied reference refers to the supplied claim.
+func isClaimRef(ref *corev1.ObjectReference, claim corev1alpha1.ResourceClaim) bool {
+	return ref != nil && ref.UID == claim.GetUID() && ref.Name == claim.GetName() && ref.Namespace == claim.GetNamespace()
+}
+
+func removeOwnerReference(refs []metav1.OwnerReference, uid types.UID) []metav1.OwnerReference {
+	filtered := make([]metav1.OwnerReference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.UID != uid {
+			filtered = append(filtered, ref)
+		}
+	}
+	return filtered
+}
+
 // fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(claim corev1alpha1.ResourceClaim, reason, msg string) (reconcile.Result, error) {
+func (r *Reconciler) fail(ctx context.Context, claim corev1alpha1.ResourceClaim, reason, msg string) (reconcile.Result, error) {
 	claim.ClaimStatus().SetFailed(reason, msg)
-	return ResultRequeue, r.Update(ctx, claim)
+	return r.requeue.Failed(claim, nil), r.Update(ctx, claim)
 }
 
-func (r *Reconciler) _getHandler(claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
+func (r *Reconciler) _getHandler(ctx context.Context, claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
 	var provisioner string
+	var class *corev1alpha1.ResourceClass
 
 	// first check if the claim already has the provisioner set on the resource status
 	resourceStatus := claim.ClaimStatus()
@@ -262,8 +587,8 @@ func (r *Reconciler) _getHandler(claim corev1alpha1.ResourceClaim) (ResourceHand
 		provisioner = claim.ClaimStatus().Provisioner
 	} else {
 		// try looking up the provisioner through the claim's resource class
-		class, err := r.getResourceClass(claim)
-		if err != nil {
+		var err error
+		if class, err = r.getResourceClass(ctx, claim); err != nil {
 			return nil, err
 		}
 
@@ -276,15 +601,32 @@ func (r *Reconciler) _getHandler(claim corev1alpha1.ResourceClaim) (ResourceHand
 		return handler, nil
 	}
 
+	// resources of classes that configure an external provisioner are
+	// provisioned through the external provisioner protocol
+	if isExternalResourceRef(claim.ResourceRef()) {
+		return NewExternalResourceHandler(), nil
+	}
+	if class == nil {
+		var err error
+		if class, err = r.getResourceClass(ctx, claim); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+	}
+	if class.ExternalProvisioner != nil {
+		return NewExternalResourceHandler(), nil
+	}
+
 	// didn't find a known handler, but there wasn't any error on the way to figuring that out either
 	return nil, nil
 }
 
-func (r *Reconciler) getResourceClass(claim corev1alpha1.ResourceClaim) (*corev1alpha1.ResourceClass, error) {
+func (r *Reconciler) getResourceClass(ctx context.Context, claim corev1alpha1.ResourceClaim) (*corev1alpha1.ResourceClass, error) {
 	classRef := claim.ClassRef()
 	if classRef == nil {
-		// TODO: add support for default resource class, until then - fail
-		return nil, fmt.Errorf("resource claim does not reference a resource class")
+		return r.getDefaultResourceClass(ctx, claim)
 	}
 
 	// retrieve resource class for this claim
@@ -296,6 +638,66 @@ func (r *Reconciler) getResourceClass(claim corev1alpha1.ResourceClaim) (*corev1
 	return class, nil
 }
 
+// getDefaultResourceClass returns the resource class annotated as the default
+// for the kind of the supplied claim.
+func (r *Reconciler) getDefaultResourceClass(ctx context.Context, claim corev1alpha1.ResourceClaim) (*corev1alpha1.ResourceClass, error) {
+	class, err := DefaultResourceClass(ctx, r.Client, claim)
+	if err != nil {
+		return nil, err
+	}
+	if class == nil {
+		return nil, fmt.Errorf("resource claim does not reference a resource class and no default resource class exists for %s", claimKind(claim))
+	}
+	return class, nil
+}
+
+// DefaultResourceClass returns the resource class annotated as the default
+// for the kind of the supplied claim, or nil if there is none. A default class
+// in the claim's namespace takes precedence over default classes in any other
+// namespace.
+func DefaultResourceClass(ctx context.Context, c client.Client, claim corev1alpha1.ResourceClaim) (*corev1alpha1.ResourceClass, error) {
+	classes := &corev1alpha1.ResourceClassList{}
+	if err := c.List(ctx, &client.ListOptions{}, classes); err != nil {
+		return nil, err
+	}
+
+	kind := claimKind(claim)
+
+	var local, global []*corev1alpha1.ResourceClass
+	for i := range classes.Items {
+		class := &classes.Items[i]
+		if !class.IsDefaultFor(kind) {
+			continue
+		}
+		if class.Namespace == claim.GetNamespace() {
+			local = append(local, class)
+			continue
+		}
+		global = append(global, class)
+	}
+
+	switch {
+	case len(local) == 1:
+		return local[0], nil
+	case len(local) > 1:
+		return nil, fmt.Errorf("multiple default resource classes for %s in namespace %s", kind, claim.GetNamespace())
+	case len(global) == 1:
+		return global[0], nil
+	case len(global) > 1:
+		return nil, fmt.Errorf("multiple default resource classes for %s", kind)
+	}
+
+	return nil, nil
+}
+
+// claimKind returns the kind of the supplied claim in the form used to refer
+// to claim kinds from resource classes, e.g.
+// "mysqlinstance.storage.crossplane.io/v1alpha1".
+func claimKind(claim corev1alpha1.ResourceClaim) string {
+	ref := claim.ObjectReference()
+	return strings.ToLower(ref.Kind) + "." + ref.APIVersion
+}
+
 // ResolveClassClaimValues validates claim value against resource class properties.
 // if both values are defined, then the claim value is validated against the resource class value and expected to match
 // TODO: the "matching" process will be further refined once we implement constraint policies at the resource class level
@@ -311,3 +713,42 @@ func ResolveClassClaimValues(classValue, claimValue string) (string, error) {
 	}
 	return claimValue, nil
 }
+
+// MatchUnboundResource returns the first available, unbound resource in the
+// supplied namespace whose labels satisfy the claim's selector. The supplied
+// list determines the kind of resource to search for. A nil resource is
+// returned if the claim does not specify a selector or nothing matches.
+func MatchUnboundResource(ctx context.Context, c client.Client, namespace string, claim corev1alpha1.ResourceClaim, list runtime.Object) (corev1alpha1.Resource, error) {
+	ls := claim.Selector()
+	if ls == nil || (len(ls.MatchLabels) == 0 && len(ls.MatchExpressions) == 0) {
+		// an empty selector matches everything, which is never what the claim wants
+		return nil, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(ls)
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim selector: %s", err)
+	}
+
+	if err := c.List(ctx, &client.ListOptions{Namespace: namespace, LabelSelector: selector}, list); err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		res, ok := item.(corev1alpha1.Resource)
+		if !ok {
+			return nil, fmt.Errorf("unexpected resource type: %T", item)
+		}
+		// released resources must be explicitly reclaimed by referencing them
+		if res.IsAvailable() && !res.IsBound() && !res.IsReleased() {
+			return res, nil
+		}
+	}
+
+	return nil, nil
+}
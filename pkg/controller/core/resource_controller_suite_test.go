@@ -37,6 +37,8 @@ const (
 	name      = "test-resource"
 )
 
+var ctx = context.Background()
+
 func init() {
 	if err := core.AddToScheme(scheme.Scheme); err != nil {
 		panic(err)
@@ -58,6 +60,8 @@ type testResourceClaimSpec struct {
 	ClassRef    *corev1.ObjectReference `json:"classReference,omitempty"`
 	ResourceRef *corev1.ObjectReference `json:"resourceName,omitempty"`
 	Selector    metav1.LabelSelector    `json:"selector,omitempty"`
+
+	SecretTemplate *corev1alpha1.SecretTemplate `json:"secretTemplate,omitempty"`
 }
 
 func testClaim() *testResourceClaim {
@@ -105,6 +109,18 @@ func (t *testResourceClaim) ClassRef() *corev1.ObjectReference {
 	return t.Spec.ClassRef
 }
 
+func (t *testResourceClaim) SetClassRef(ref *corev1.ObjectReference) {
+	t.Spec.ClassRef = ref
+}
+
+func (t *testResourceClaim) Selector() *metav1.LabelSelector {
+	return &t.Spec.Selector
+}
+
+func (t *testResourceClaim) SecretTemplate() *corev1alpha1.SecretTemplate {
+	return t.Spec.SecretTemplate
+}
+
 func (t *testResourceClaim) ResourceRef() *corev1.ObjectReference {
 	return t.Spec.ResourceRef
 }
@@ -121,17 +137,32 @@ type MockClient struct {
 	client.Client
 
 	MockGet    func(...interface{}) error
+	MockList   func(...interface{}) error
+	MockCreate func(...interface{}) error
 	MockUpdate func(...interface{}) error
+	MockDelete func(...interface{}) error
 }
 
 func (mc *MockClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
 	return mc.MockGet(ctx, key, obj)
 }
 
+func (mc *MockClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return mc.MockList(ctx, opts, list)
+}
+
+func (mc *MockClient) Create(ctx context.Context, obj runtime.Object) error {
+	return mc.MockCreate(ctx, obj)
+}
+
 func (mc *MockClient) Update(ctx context.Context, obj runtime.Object) error {
 	return mc.MockUpdate(ctx, obj)
 }
 
+func (mc *MockClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOptionFunc) error {
+	return mc.MockDelete(ctx, obj)
+}
+
 // MockRecorder Kubernetes events recorder
 type MockRecorder struct {
 	record.EventRecorder
@@ -154,18 +185,23 @@ func (mr *MockRecorder) AnnotatedEventf(object runtime.Object, annotations map[s
 
 type MockResourceHandler struct {
 	MockProvision     func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error)
+	MockMatch         func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error)
 	MockFind          func(types.NamespacedName, client.Client) (corev1alpha1.Resource, error)
 	MockSetBindStatus func(types.NamespacedName, client.Client, bool) error
 }
 
-func (mrh *MockResourceHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (mrh *MockResourceHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	return mrh.MockProvision(class, claim, c)
 }
 
-func (mrh *MockResourceHandler) Find(n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (mrh *MockResourceHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return mrh.MockMatch(class, claim, c)
+}
+
+func (mrh *MockResourceHandler) Find(ctx context.Context, n types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	return mrh.MockFind(n, c)
 }
 
-func (mrh *MockResourceHandler) SetBindStatus(n types.NamespacedName, c client.Client, s bool) error {
+func (mrh *MockResourceHandler) SetBindStatus(ctx context.Context, n types.NamespacedName, c client.Client, s bool) error {
 	return mrh.MockSetBindStatus(n, c, s)
 }
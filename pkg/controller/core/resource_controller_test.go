@@ -17,8 +17,10 @@ limitations under the License.
 package core
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
@@ -31,10 +33,14 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 var (
 	handlers = map[string]ResourceHandler{}
+
+	resultFailed  = reconcile.Result{RequeueAfter: requeue.Defaults.BackoffBase}
+	resultPending = reconcile.Result{RequeueAfter: requeue.Defaults.PollInterval}
 )
 
 func TestGetHandler(t *testing.T) {
@@ -43,8 +49,9 @@ func TestGetHandler(t *testing.T) {
 	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
 	claim := testClaim()
 
-	// test: claim has no ResourceClass
-	h, err := r._getHandler(claim)
+	// test: claim has no ResourceClass and there is no default ResourceClass
+	mc.MockList = func(...interface{}) error { return nil }
+	h, err := r._getHandler(ctx, claim)
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(h).To(BeNil())
 
@@ -59,13 +66,13 @@ func TestGetHandler(t *testing.T) {
 		class.Provisioner = "test-provisioner"
 		return nil
 	}
-	h, err = r._getHandler(claim)
+	h, err = r._getHandler(ctx, claim)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(h).To(BeNil())
 
 	// resource class has a known provisioner, it should be returned
 	handlers["test-provisioner"] = &MockResourceHandler{}
-	h, err = r._getHandler(claim)
+	h, err = r._getHandler(ctx, claim)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(h).NotTo(BeNil())
 
@@ -73,7 +80,7 @@ func TestGetHandler(t *testing.T) {
 	// the handler should be returned
 	claim.ClaimStatus().Provisioner = "test-provisioner"
 	mc.MockGet = nil // Get should not be called on this path
-	h, err = r._getHandler(claim)
+	h, err = r._getHandler(ctx, claim)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(h).NotTo(BeNil())
 }
@@ -86,10 +93,11 @@ func TestProvision(t *testing.T) {
 	claim := testClaim()
 
 	// test: without ResourceClass definition - expected to: fail
+	mc.MockList = func(...interface{}) error { return nil }
 	mc.MockUpdate = func(...interface{}) error { return nil }
-	rs, err := r._provision(claim, h)
+	rs, err := r._provision(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorRetrievingResourceClass)
 
 	// test: ResourceClass is not found - expected to: fail
@@ -100,9 +108,9 @@ func TestProvision(t *testing.T) {
 	mc.MockGet = func(...interface{}) error {
 		return fmt.Errorf("not-found")
 	}
-	rs, err = r._provision(claim, h)
+	rs, err = r._provision(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorRetrievingResourceClass)
 
 	// test: ResourceClass has test provisioner, but provisioning failed
@@ -111,14 +119,45 @@ func TestProvision(t *testing.T) {
 		class.Provisioner = "test-provisioner"
 		return nil
 	}
+	h.MockMatch = func(c *corev1alpha1.ResourceClass, sp corev1alpha1.ResourceClaim, cl client.Client) (corev1alpha1.Resource, error) {
+		return nil, nil
+	}
 	h.MockProvision = func(c *corev1alpha1.ResourceClass, sp corev1alpha1.ResourceClaim, cl client.Client) (corev1alpha1.Resource, error) {
 		return nil, fmt.Errorf("test-provisioning-error")
 	}
-	rs, err = r._provision(claim, h)
+	rs, err = r._provision(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorResourceProvisioning)
 
+	// test: provisioning would exceed a claim quota of the namespace
+	mc.MockList = func(args ...interface{}) error {
+		if quotas, ok := args[2].(*corev1alpha1.ClaimQuotaList); ok {
+			quotas.Items = []corev1alpha1.ClaimQuota{{
+				ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: namespace},
+				Spec:       corev1alpha1.ClaimQuotaSpec{Claims: map[string]int{testClaimKindAPIVersion: 1}},
+			}}
+		}
+		return nil
+	}
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return &quotaUsage{claims: 1}, nil
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorClaimQuotaExceeded)
+
+	// test: usage of the claim quota cannot be determined
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return nil, fmt.Errorf("test-usage-error")
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorEnforcingClaimQuota)
+	mc.MockList = func(...interface{}) error { return nil }
+
 	// test: ResourceClass has test provisioner, no provisioning failures
 	mc.MockGet = func(args ...interface{}) error {
 		class := args[2].(*corev1alpha1.ResourceClass)
@@ -128,21 +167,423 @@ func TestProvision(t *testing.T) {
 	h.MockProvision = func(c *corev1alpha1.ResourceClass, sp corev1alpha1.ResourceClaim, cl client.Client) (corev1alpha1.Resource, error) {
 		return &corev1alpha1.BasicResource{}, nil
 	}
-	r.bind = func(corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error) {
+	r.bind = func(context.Context, corev1alpha1.ResourceClaim, ResourceHandler) (reconcile.Result, error) {
 		return Result, nil
 	}
 
-	rs, err = r._provision(claim, h)
+	rs, err = r._provision(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(rs).To(Equal(Result))
 }
 
-func TestBind(t *testing.T) {
+func TestProvisionStaticBinding(t *testing.T) {
+	mc := &MockClient{}
+	mc.MockUpdate = func(...interface{}) error { return nil }
+	mc.MockGet = func(args ...interface{}) error {
+		class := args[2].(*corev1alpha1.ResourceClass)
+		class.Provisioner = "test-provisioner"
+		return nil
+	}
+	mc.MockList = func(...interface{}) error { return nil }
+
+	g := NewGomegaWithT(t)
+	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
+	claim := testClaim()
+	claim.Spec.ClassRef = &corev1.ObjectReference{
+		Name:      "foo",
+		Namespace: "system",
+	}
+	h := &MockResourceHandler{}
+	h.MockProvision = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("provision should not be called")
+	}
+
+	// test: matching existing resources fails
+	h.MockMatch = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("test-match-error")
+	}
+	rs, err := r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorMatchingResource)
+
+	// test: existing resource matched, but it cannot be reserved
+	ref := &corev1.ObjectReference{Name: "test-resource", Namespace: "system"}
+	matched := corev1alpha1.NewBasicResource(ref, "", "", "available")
+	h.MockMatch = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return matched, nil
+	}
+	mc.MockUpdate = func(args ...interface{}) error {
+		if _, ok := args[1].(*corev1alpha1.BasicResource); ok {
+			return fmt.Errorf("test-error-update-resource")
+		}
+		return nil
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorSettingResourceBindStatus)
+
+	// test: existing resource matched and reserved, no new resource is provisioned
+	var reserved corev1alpha1.Resource
+	mc.MockUpdate = func(args ...interface{}) error {
+		if res, ok := args[1].(*corev1alpha1.BasicResource); ok {
+			reserved = res
+		}
+		return nil
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(reserved).To(Equal(matched))
+	g.Expect(matched.IsBound()).To(BeTrue())
+	g.Expect(matched.ClaimRef()).To(Equal(claim.ObjectReference()))
+	g.Expect(claim.ResourceRef()).To(Equal(ref))
+	g.Expect(claim.ClaimStatus().Provisioner).To(Equal("test-provisioner"))
+
+	// test: binding the existing resource would exceed a claim quota of the namespace
+	reserved = nil
+	claim.SetResourceRef(nil)
+	matched.SetBound(false)
+	matched.SetClaimRef(nil)
+	mc.MockList = func(args ...interface{}) error {
+		if quotas, ok := args[2].(*corev1alpha1.ClaimQuotaList); ok {
+			quotas.Items = []corev1alpha1.ClaimQuota{testQuota(corev1alpha1.ClaimQuotaSpec{Claims: map[string]int{testClaimKindAPIVersion: 1}})}
+		}
+		return nil
+	}
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return &quotaUsage{claims: 1}, nil
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorClaimQuotaExceeded)
+	g.Expect(reserved).To(BeNil())
+	g.Expect(matched.IsBound()).To(BeFalse())
+}
+
+func TestProvisionStaticBindingSatisfies(t *testing.T) {
+	g := NewGomegaWithT(t)
+	mc := &MockClient{}
+	mc.MockGet = func(args ...interface{}) error {
+		class := args[2].(*corev1alpha1.ResourceClass)
+		class.Namespace = "system"
+		class.Provisioner = "test-provisioner"
+		return nil
+	}
+	resource := func(name, engineVersion string) corev1alpha1.ExternalResource {
+		res := testExternalResource()
+		res.Namespace = "system"
+		res.Name = name
+		res.Labels = map[string]string{"team": "data"}
+		res.Spec.Parameters = map[string]string{"engineVersion": engineVersion}
+		res.Status.State = corev1alpha1.ExternalResourceStateAvailable
+		return *res
+	}
+	var existing []corev1alpha1.ExternalResource
+	mc.MockList = func(args ...interface{}) error {
+		if l, ok := args[2].(*corev1alpha1.ExternalResourceList); ok {
+			l.Items = append([]corev1alpha1.ExternalResource(nil), existing...)
+		}
+		return nil
+	}
+	var reserved corev1alpha1.Resource
+	mc.MockUpdate = func(args ...interface{}) error {
+		if res, ok := args[1].(*corev1alpha1.ExternalResource); ok {
+			reserved = res
+		}
+		return nil
+	}
+
+	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
+	claim := testClaim()
+	claim.Spec.ClassRef = &corev1.ObjectReference{Name: "foo", Namespace: "system"}
+	claim.Spec.Selector = v1.LabelSelector{MatchLabels: map[string]string{"team": "data"}}
+
+	provisioned := corev1alpha1.NewBasicResource(&corev1.ObjectReference{Name: "provisioned", Namespace: "system"}, "", "", "")
+	h := &MockResourceHandler{
+		MockMatch: func(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+			return MatchUnboundResource(ctx, c, class.Namespace, claim, &corev1alpha1.ExternalResourceList{})
+		},
+		MockProvision: func(_ *corev1alpha1.ResourceClass, _ corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+			if _, ok := c.(*dryRunClient); ok {
+				return &corev1alpha1.ExternalResource{Spec: corev1alpha1.ExternalResourceSpec{Parameters: map[string]string{"engineVersion": "5.7"}}}, nil
+			}
+			return provisioned, nil
+		},
+	}
+
+	// test: a resource with matching labels but the wrong engine version is not bound
+	existing = []corev1alpha1.ExternalResource{resource("wrong-engine", "5.6")}
+	rs, err := r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(reserved).To(BeNil())
+	g.Expect(claim.ResourceRef()).To(Equal(provisioned.ObjectReference()))
+
+	// test: a resource that satisfies the claim is bound in preference to one that does not
+	existing = []corev1alpha1.ExternalResource{resource("wrong-engine", "5.6"), resource("right-engine", "5.7")}
+	claim.SetResourceRef(nil)
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(reserved).NotTo(BeNil())
+	g.Expect(reserved.ObjectReference().Name).To(Equal("right-engine"))
+	g.Expect(reserved.IsBound()).To(BeTrue())
+	g.Expect(claim.ResourceRef().Name).To(Equal("right-engine"))
+
+	// test: the resource that would be provisioned for the claim cannot be determined
+	h.MockProvision = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("test-provision-error")
+	}
+	claim.SetResourceRef(nil)
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorMatchingResource)
+}
+
+func TestProvisionPooled(t *testing.T) {
+	mc := &MockClient{}
+	mc.MockGet = func(args ...interface{}) error {
+		class := args[2].(*corev1alpha1.ResourceClass)
+		class.Provisioner = "test-provisioner"
+		class.Pool = &corev1alpha1.ResourcePool{ClaimKind: "testresourceclaim.core.crossplane.io/v1alpha1", Size: 2}
+		return nil
+	}
+	var reserved corev1alpha1.Resource
+	mc.MockUpdate = func(args ...interface{}) error {
+		if res, ok := args[1].(*corev1alpha1.BasicResource); ok {
+			reserved = res
+		}
+		return nil
+	}
+	mc.MockList = func(...interface{}) error { return nil }
+
+	g := NewGomegaWithT(t)
+	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
+	claim := testClaim()
+	claim.Spec.ClassRef = &corev1.ObjectReference{Name: "foo", Namespace: "system"}
+	h := &MockResourceHandler{}
+	h.MockMatch = func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+		return nil, nil
+	}
+	want := corev1alpha1.NewBasicResource(nil, "", "", "")
+	h.MockProvision = func(_ *corev1alpha1.ResourceClass, _ corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+		if _, ok := c.(*dryRunClient); !ok {
+			return nil, fmt.Errorf("provision should not be called")
+		}
+		return want, nil
+	}
+
+	// test: listing the pool fails
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("test-list-error")
+	}
+	rs, err := r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorMatchingResource)
+
+	// test: an available pooled resource is preferred and reserved
+	pending := corev1alpha1.NewBasicResource(&corev1.ObjectReference{Name: "pending", Namespace: "system"}, "", "", "creating")
+	ref := &corev1.ObjectReference{Name: "available", Namespace: "system"}
+	available := corev1alpha1.NewBasicResource(ref, "", "", "available")
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return []corev1alpha1.Resource{pending, available}, nil
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(reserved).To(Equal(available))
+	g.Expect(available.IsBound()).To(BeTrue())
+	g.Expect(available.ClaimRef()).To(Equal(claim.ObjectReference()))
+	g.Expect(claim.ResourceRef()).To(Equal(ref))
+
+	// test: binding a pooled resource would exceed a claim quota of the namespace
+	reserved = nil
+	claim.SetResourceRef(nil)
+	mc.MockList = func(args ...interface{}) error {
+		if quotas, ok := args[2].(*corev1alpha1.ClaimQuotaList); ok {
+			quotas.Items = []corev1alpha1.ClaimQuota{{
+				ObjectMeta: v1.ObjectMeta{Name: "quota", Namespace: namespace},
+				Spec:       corev1alpha1.ClaimQuotaSpec{Claims: map[string]int{testClaimKindAPIVersion: 1}},
+			}}
+		}
+		return nil
+	}
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return &quotaUsage{claims: 1}, nil
+	}
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorClaimQuotaExceeded)
+	g.Expect(reserved).To(BeNil())
+	g.Expect(claim.ResourceRef()).To(BeNil())
+
+	// test: pooled resources that do not satisfy the claim are not reserved
+	mc.MockList = func(...interface{}) error { return nil }
+	provisioned := corev1alpha1.NewBasicResource(&corev1.ObjectReference{Name: "provisioned", Namespace: "system"}, "", "", "")
+	h.MockProvision = func(_ *corev1alpha1.ResourceClass, _ corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+		if _, ok := c.(*dryRunClient); ok {
+			return &corev1alpha1.ExternalResource{Spec: corev1alpha1.ExternalResourceSpec{Parameters: map[string]string{"engineVersion": "5.7"}}}, nil
+		}
+		return provisioned, nil
+	}
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return []corev1alpha1.Resource{&corev1alpha1.ExternalResource{Spec: corev1alpha1.ExternalResourceSpec{Parameters: map[string]string{"engineVersion": "5.6"}}}}, nil
+	}
+	claim.SetResourceRef(nil)
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(reserved).To(BeNil())
+	g.Expect(claim.ResourceRef()).To(Equal(provisioned.ObjectReference()))
+
+	// test: an empty pool falls back to provisioning a new resource
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) { return nil, nil }
+	claim.SetResourceRef(nil)
+	rs, err = r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(claim.ResourceRef()).To(Equal(provisioned.ObjectReference()))
+
+	// test: a pooled resource is returned to the pool if the claim cannot be
+	// updated to reference it
+	pool := testExternalResource()
+	pool.Namespace = "system"
+	pool.Name = "pooled"
+	pool.Labels = map[string]string{corev1alpha1.LabelPoolClass: "foo"}
+	pool.Spec.Parameters = map[string]string{"engineVersion": "5.7"}
+	pool.Status.State = corev1alpha1.ExternalResourceStateAvailable
+	r.pooled = func(context.Context, *corev1alpha1.ResourceClass) ([]corev1alpha1.Resource, error) {
+		return []corev1alpha1.Resource{pool}, nil
+	}
+	var updates []corev1alpha1.ExternalResource
+	mc.MockUpdate = func(args ...interface{}) error {
+		switch o := args[1].(type) {
+		case *corev1alpha1.ExternalResource:
+			updates = append(updates, *o.DeepCopy())
+		case *testResourceClaim:
+			return fmt.Errorf("test-update-error")
+		}
+		return nil
+	}
+	claim.SetResourceRef(nil)
+	_, err = r._provision(ctx, claim, h)
+	g.Expect(err).To(MatchError("test-update-error"))
+	g.Expect(updates).To(HaveLen(2))
+	g.Expect(updates[0].IsBound()).To(BeTrue())
+	g.Expect(updates[0].Labels).NotTo(HaveKey(corev1alpha1.LabelPoolClass))
+	g.Expect(updates[0].OwnerReferences).To(ConsistOf(claim.OwnerReference()))
+	g.Expect(updates[1].IsBound()).To(BeFalse())
+	g.Expect(updates[1].ClaimRef()).To(BeNil())
+	g.Expect(updates[1].Labels).To(HaveKeyWithValue(corev1alpha1.LabelPoolClass, "foo"))
+	g.Expect(updates[1].OwnerReferences).To(BeEmpty())
+}
+
+func TestGetDefaultResourceClass(t *testing.T) {
+	g := NewGomegaWithT(t)
 	mc := &MockClient{}
+	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
+	claim := testClaim()
+	kind := "testresourceclaim.core.crossplane.io/v1alpha1"
+
+	defaultClass := func(namespace, name, defaultFor string) corev1alpha1.ResourceClass {
+		return corev1alpha1.ResourceClass{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Annotations: map[string]string{corev1alpha1.AnnotationDefaultClassFor: defaultFor},
+			},
+		}
+	}
+	listClasses := func(classes ...corev1alpha1.ResourceClass) func(...interface{}) error {
+		return func(args ...interface{}) error {
+			if l, ok := args[2].(*corev1alpha1.ResourceClassList); ok {
+				l.Items = classes
+			}
+			return nil
+		}
+	}
+
+	// test: listing resource classes fails
+	mc.MockList = func(...interface{}) error { return fmt.Errorf("test-list-error") }
+	class, err := r.getResourceClass(ctx, claim)
+	g.Expect(err).To(MatchError("test-list-error"))
+	g.Expect(class).To(BeNil())
+
+	// test: no class is the default for this claim kind
+	mc.MockList = listClasses(defaultClass(namespace, "other", "mysqlinstance.storage.crossplane.io/v1alpha1"))
+	class, err = r.getResourceClass(ctx, claim)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(class).To(BeNil())
+
+	// test: a default class in another namespace is used as a fallback
+	mc.MockList = listClasses(
+		defaultClass(namespace, "other", "mysqlinstance.storage.crossplane.io/v1alpha1"),
+		defaultClass("system", "global", "bucket.storage.crossplane.io/v1alpha1, "+kind),
+	)
+	class, err = r.getResourceClass(ctx, claim)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(class.Name).To(Equal("global"))
+
+	// test: a default class in the claim's namespace takes precedence
+	mc.MockList = listClasses(
+		defaultClass("system", "global", kind),
+		defaultClass(namespace, "local", kind),
+	)
+	class, err = r.getResourceClass(ctx, claim)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(class.Name).To(Equal("local"))
+
+	// test: multiple default classes in the same scope are ambiguous
+	mc.MockList = listClasses(
+		defaultClass("system", "global", kind),
+		defaultClass("other", "global", kind),
+	)
+	class, err = r.getResourceClass(ctx, claim)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(class).To(BeNil())
+
+	// test: the default class is recorded on the claim when provisioning
+	mc.MockList = listClasses(defaultClass("system", "global", kind))
 	mc.MockUpdate = func(...interface{}) error { return nil }
+	h := &MockResourceHandler{
+		MockMatch: func(*corev1alpha1.ResourceClass, corev1alpha1.ResourceClaim, client.Client) (corev1alpha1.Resource, error) {
+			return corev1alpha1.NewBasicResource(&corev1.ObjectReference{Name: "test-resource", Namespace: "system"}, "", "", "available"), nil
+		},
+	}
+	rs, err := r._provision(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(claim.ClassRef()).NotTo(BeNil())
+	g.Expect(claim.ClassRef().Name).To(Equal("global"))
+	g.Expect(claim.ClassRef().Namespace).To(Equal("system"))
+}
+
+func TestBind(t *testing.T) {
+	var quotas []corev1alpha1.ClaimQuota
+	var updatedQuotas []*corev1alpha1.ClaimQuota
+	mc := &MockClient{}
+	mc.MockList = func(args ...interface{}) error {
+		args[2].(*corev1alpha1.ClaimQuotaList).Items = append([]corev1alpha1.ClaimQuota(nil), quotas...)
+		return nil
+	}
+	mc.MockUpdate = func(args ...interface{}) error {
+		if q, ok := args[1].(*corev1alpha1.ClaimQuota); ok {
+			updatedQuotas = append(updatedQuotas, q)
+		}
+		return nil
+	}
 
 	g := NewGomegaWithT(t)
 	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return &quotaUsage{claims: 1}, nil
+	}
 	claim := testClaim()
 	h := &MockResourceHandler{}
 
@@ -154,9 +595,9 @@ func TestBind(t *testing.T) {
 		Namespace: "foo",
 		Name:      "bar",
 	}
-	rs, err := r._bind(claim, h)
+	rs, err := r._bind(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorRetrievingResource)
 
 	// resource is not available
@@ -164,11 +605,13 @@ func TestBind(t *testing.T) {
 	h.MockFind = func(types.NamespacedName, client.Client) (corev1alpha1.Resource, error) {
 		return br, nil
 	}
-	rs, err = r._bind(claim, h)
+	rs, err = r._bind(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultPending))
 	assertConditionUnset(g, claim, corev1alpha1.Failed, errorRetrievingResource)
 	assertConditionSet(g, claim, corev1alpha1.Pending, waitResourceIsNotAvailable)
+	g.Expect(claim.Status.Condition(corev1alpha1.Pending).Message).To(Equal("Resource is in state not-available"))
+	g.Expect(claim.Status.ResourceStatus).To(Equal(corev1alpha1.ResourceStatusSummary{State: "not-available"}))
 
 	// error retrieving resource secret
 	br = corev1alpha1.NewBasicResource(
@@ -180,11 +623,29 @@ func TestBind(t *testing.T) {
 	mk := fake.NewSimpleClientset(&corev1.Secret{})
 	r.kubeclient = mk
 
-	rs, err = r._bind(claim, h)
+	rs, err = r._bind(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorRetrievingResourceSecret)
 
+	// resource secret does not contain the keys defined for the claim kind
+	r.secrets = NewSecretDefinitionRegistry()
+	r.secrets.SetDefault(corev1alpha1.NewCustomSecretDefinition("core.crossplane.io", "v1alpha1", "testresourceclaim", "password"))
+	r.kubeclient = fake.NewSimpleClientset(&corev1.Secret{ObjectMeta: v1.ObjectMeta{Name: "test-secret", Namespace: "default"}})
+	rs, err = r._bind(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorValidatingResourceSecret)
+	r.secrets = nil
+
+	// claim secret template cannot be rendered
+	claim.Spec.SecretTemplate = &corev1alpha1.SecretTemplate{Keys: map[string]string{"password": "DB_PASSWORD"}}
+	rs, err = r._bind(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorRenderingClaimSecret)
+	claim.Spec.SecretTemplate = nil
+
 	// error applying resource secret
 	sec := &corev1.Secret{
 		ObjectMeta: v1.ObjectMeta{
@@ -197,9 +658,9 @@ func TestBind(t *testing.T) {
 		return true, nil, fmt.Errorf("test-error-create")
 	})
 	r.kubeclient = mk
-	rs, err = r._bind(claim, h)
+	rs, err = r._bind(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorApplyingResourceSecret)
 
 	// failure to set binding status
@@ -208,22 +669,81 @@ func TestBind(t *testing.T) {
 	h.MockSetBindStatus = func(namespacedName types.NamespacedName, i client.Client, b bool) error {
 		return fmt.Errorf("test-error-set-bind-status")
 	}
-	rs, err = r._bind(claim, h)
+	rs, err = r._bind(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorSettingResourceBindStatus)
 
-	// bind
+	// bind, the quota usage is refreshed to count the newly bound claim
 	mk = fake.NewSimpleClientset(sec)
 	r.kubeclient = mk
 	h.MockSetBindStatus = func(namespacedName types.NamespacedName, i client.Client, b bool) error { return nil }
-	rs, err = r._bind(claim, h)
+	quotas = []corev1alpha1.ClaimQuota{testQuota(corev1alpha1.ClaimQuotaSpec{Claims: map[string]int{testClaimKindAPIVersion: 3}})}
+	rs, err = r._bind(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(rs).To(Equal(Result))
+	g.Expect(updatedQuotas).To(HaveLen(1))
+	g.Expect(updatedQuotas[0].Status.Used.Claims).To(HaveKeyWithValue(testClaimKindAPIVersion, 2))
+	quotas, updatedQuotas = nil, nil
 	assertConditionUnset(g, claim, corev1alpha1.Failed, errorSettingResourceBindStatus)
 	assertConditionSet(g, claim, corev1alpha1.Ready, "")
 	g.Expect(claim.Status.CredentialsSecretRef.Name).To(Equal(claim.Name))
 	g.Expect(claim.Status.BindingStatusPhase.Phase).To(Equal(corev1alpha1.BindingStateBound))
+	g.Expect(claim.Status.ResourceStatus).To(Equal(corev1alpha1.ResourceStatusSummary{
+		Name:     "test-resource",
+		State:    "available",
+		Endpoint: "test-endpoint",
+	}))
+	g.Expect(br.ClaimRef()).To(Equal(claim.ObjectReference()))
+	cs, err := mk.CoreV1().Secrets(claim.Namespace).Get(claim.Name, v1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.Annotations).To(HaveKeyWithValue(corev1alpha1.AnnotationSecretHash, secretHash(sec.Data)))
+
+	// resource is bound to another claim
+	other := &corev1.ObjectReference{Namespace: "other", Name: "other-claim", UID: "other-uid"}
+	br.SetClaimRef(other)
+	br.SetBound(true)
+	rs, err = r._bind(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorResourceBoundToAnotherClaim)
+	g.Expect(br.ClaimRef()).To(Equal(other))
+
+	// reclaiming the resource released by another claim would exceed a quota
+	br.SetReleased()
+	quotas = []corev1alpha1.ClaimQuota{testQuota(corev1alpha1.ClaimQuotaSpec{Claims: map[string]int{testClaimKindAPIVersion: 1}})}
+	rs, err = r._bind(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(resultFailed))
+	assertConditionSet(g, claim, corev1alpha1.Failed, errorClaimQuotaExceeded)
+	g.Expect(br.ClaimRef()).To(Equal(other))
+	quotas = nil
+
+	// resource was released by another claim and is reclaimed
+	mk = fake.NewSimpleClientset(sec)
+	r.kubeclient = mk
+	rs, err = r._bind(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	assertConditionSet(g, claim, corev1alpha1.Ready, "")
+	g.Expect(br.ClaimRef()).To(Equal(claim.ObjectReference()))
+}
+
+func TestResourceNotAvailableMessage(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(resourceNotAvailableMessage(corev1alpha1.ResourceStatusSummary{})).To(Equal("Resource is not in running state"))
+	g.Expect(resourceNotAvailableMessage(corev1alpha1.ResourceStatusSummary{State: "creating"})).To(Equal("Resource is in state creating"))
+	g.Expect(resourceNotAvailableMessage(corev1alpha1.ResourceStatusSummary{State: "failed", Message: "quota exceeded"})).To(Equal("Resource has failed: quota exceeded"))
+}
+
+func TestSecretHash(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	data := map[string][]byte{"endpoint": []byte("localhost"), "password": []byte("secret")}
+	g.Expect(secretHash(data)).To(Equal(secretHash(map[string][]byte{"password": []byte("secret"), "endpoint": []byte("localhost")})))
+	g.Expect(secretHash(data)).NotTo(Equal(secretHash(map[string][]byte{"endpoint": []byte("localhost"), "password": []byte("rotated")})))
+	g.Expect(secretHash(data)).NotTo(Equal(secretHash(map[string][]byte{"endpointpassword": []byte("localhostsecret")})))
 }
 
 func TestDelete(t *testing.T) {
@@ -238,20 +758,95 @@ func TestDelete(t *testing.T) {
 		Namespace: "default",
 		Name:      "test-resource",
 	}
-	bindingStatus := true
-	h.MockSetBindStatus = func(namespacedName types.NamespacedName, i client.Client, b bool) error {
-		bindingStatus = b
+	br := corev1alpha1.NewBasicResource(claim.Spec.ResourceRef, "", "", "available")
+	br.SetClaimRef(claim.ObjectReference())
+	br.SetBound(true)
+	br.SetReclaimPolicy(corev1alpha1.ReclaimDelete)
+	h.MockFind = func(types.NamespacedName, client.Client) (corev1alpha1.Resource, error) {
+		return br, nil
+	}
+	mc.MockList = func(args ...interface{}) error {
+		args[2].(*corev1alpha1.ClaimQuotaList).Items = []corev1alpha1.ClaimQuota{
+			testQuota(corev1alpha1.ClaimQuotaSpec{Claims: map[string]int{testClaimKindAPIVersion: 3}}),
+		}
 		return nil
 	}
-	mc.MockUpdate = func(...interface{}) error { return nil }
-	rs, err := r._delete(claim, h)
+	var quota *corev1alpha1.ClaimQuota
+	mc.MockUpdate = func(args ...interface{}) error {
+		if q, ok := args[1].(*corev1alpha1.ClaimQuota); ok {
+			quota = q
+		}
+		return nil
+	}
+	r.usage = func(context.Context, corev1alpha1.ResourceClaim) (*quotaUsage, error) {
+		return &quotaUsage{claims: 1}, nil
+	}
+	claim.DeletionTimestamp = &v1.Time{Time: time.Now()}
+	rs, err := r._delete(ctx, claim, h)
 	g.Expect(err).NotTo(HaveOccurred())
 	g.Expect(rs).To(Equal(Result))
 	c := claim.Status.Condition(corev1alpha1.Failed)
 	g.Expect(c).To(BeNil())
 	assertConditionSet(g, claim, corev1alpha1.Deleting, "")
 	g.Expect(len(claim.Finalizers)).To(Equal(0))
-	g.Expect(bindingStatus).To(BeFalse())
+	g.Expect(br.IsBound()).To(BeFalse())
+	g.Expect(br.IsReleased()).To(BeFalse())
+	g.Expect(br.ClaimRef()).To(BeNil())
+	g.Expect(quota).NotTo(BeNil())
+	g.Expect(quota.Status.Used.Claims).To(HaveKeyWithValue(testClaimKindAPIVersion, 1))
+}
+
+func TestDeleteRetain(t *testing.T) {
+	mc := &MockClient{}
+	mc.MockList = func(...interface{}) error { return nil }
+	mc.MockUpdate = func(...interface{}) error { return nil }
+	g := NewGomegaWithT(t)
+	r := Reconciler{Client: mc, recorder: &MockRecorder{}, handlers: handlers}
+	claim := testClaim()
+	claim.Spec.ResourceRef = &corev1.ObjectReference{
+		Namespace: "default",
+		Name:      "test-resource",
+	}
+	h := &MockResourceHandler{}
+
+	// test: a retained resource is released, keeping its claim reference
+	br := corev1alpha1.NewBasicResource(claim.Spec.ResourceRef, "", "", "available")
+	br.SetClaimRef(claim.ObjectReference())
+	br.SetBound(true)
+	br.SetReclaimPolicy(corev1alpha1.ReclaimRetain)
+	h.MockFind = func(types.NamespacedName, client.Client) (corev1alpha1.Resource, error) {
+		return br, nil
+	}
+	rs, err := r._delete(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	assertConditionSet(g, claim, corev1alpha1.Deleting, "")
+	g.Expect(br.IsReleased()).To(BeTrue())
+	g.Expect(br.IsBound()).To(BeFalse())
+	g.Expect(br.ClaimRef()).To(Equal(claim.ObjectReference()))
+
+	// test: a resource without a reclaim policy is retained
+	br = corev1alpha1.NewBasicResource(claim.Spec.ResourceRef, "", "", "available")
+	br.SetClaimRef(claim.ObjectReference())
+	br.SetBound(true)
+	rs, err = r._delete(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(br.IsReleased()).To(BeTrue())
+	g.Expect(br.IsBound()).To(BeFalse())
+	g.Expect(br.ClaimRef()).To(Equal(claim.ObjectReference()))
+
+	// test: a resource that has been reclaimed by another claim is left alone
+	other := &corev1.ObjectReference{Namespace: "other", Name: "other-claim", UID: "other-uid"}
+	br = corev1alpha1.NewBasicResource(claim.Spec.ResourceRef, "", "", "available")
+	br.SetClaimRef(other)
+	br.SetBound(true)
+	br.SetReclaimPolicy(corev1alpha1.ReclaimRetain)
+	rs, err = r._delete(ctx, claim, h)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(br.IsBound()).To(BeTrue())
+	g.Expect(br.ClaimRef()).To(Equal(other))
 }
 
 func TestReconciler_Reconcile(t *testing.T) {
@@ -265,41 +860,41 @@ func TestReconciler_Reconcile(t *testing.T) {
 	claim := testClaim()
 
 	// 1) getHandler returns an error, failure condition should be set
-	r.getHandler = func(claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
+	r.getHandler = func(_ context.Context, claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
 		return &MockResourceHandler{}, fmt.Errorf("mocked getHandler error")
 	}
 	mc.MockUpdate = func(...interface{}) error {
 		return nil
 	}
-	rs, err := r._reconcile(claim)
+	rs, err := r._reconcile(ctx, claim)
 	g.Expect(err).ToNot(HaveOccurred())
-	g.Expect(rs).To(Equal(ResultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	assertConditionSet(g, claim, corev1alpha1.Failed, errorRetrievingHandler)
 
 	// 2) getHandler does not return an error, but also doesn't find a known handler
 	// this is OK since an external provisioner may handle it instead
 	// we should not requeue
-	r.getHandler = func(claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
+	r.getHandler = func(_ context.Context, claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
 		return nil, nil
 	}
-	rs, err = r._reconcile(claim)
+	rs, err = r._reconcile(ctx, claim)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(rs).To(Equal(Result))
 
 	// 3) reconcile deleted resource
 	// mocked happy delete function
 	deleteCalled := false
-	deleteFunc := func(claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+	deleteFunc := func(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
 		deleteCalled = true
 		return Result, nil
 	}
-	r.getHandler = func(claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
+	r.getHandler = func(_ context.Context, claim corev1alpha1.ResourceClaim) (ResourceHandler, error) {
 		return &MockResourceHandler{}, nil
 	}
 	tm := v1.Now()
 	claim.DeletionTimestamp = &tm
 	r.delete = deleteFunc
-	rs, err = r._reconcile(claim)
+	rs, err = r._reconcile(ctx, claim)
 	g.Expect(rs).To(Equal(Result))
 	g.Expect(err).To(BeNil())
 	g.Expect(deleteCalled).To(BeTrue())
@@ -313,15 +908,15 @@ func TestReconciler_Reconcile(t *testing.T) {
 	}
 	r.delete = nil                // clear out the mocked delete func
 	claim.DeletionTimestamp = nil // clear out the deletion timestamp
-	rs, err = r._reconcile(claim)
-	g.Expect(rs).To(Equal(ResultRequeue))
+	rs, err = r._reconcile(ctx, claim)
+	g.Expect(rs).To(Equal(Result))
 	g.Expect(err).NotTo(BeNil())
 	g.Expect(err.Error()).To(Equal("test-error"))
 
 	// 5) provision path
 	// mocked happy provision function
 	provisionCalled := false
-	provisionFunc := func(claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+	provisionFunc := func(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
 		provisionCalled = true
 		return Result, nil
 	}
@@ -332,7 +927,7 @@ func TestReconciler_Reconcile(t *testing.T) {
 		return nil
 	}
 	r.provision = provisionFunc
-	rs, err = r._reconcile(claim)
+	rs, err = r._reconcile(ctx, claim)
 	g.Expect(rs).To(Equal(Result))
 	g.Expect(err).To(BeNil())
 	g.Expect(provisionCalled).To(BeTrue())
@@ -340,7 +935,7 @@ func TestReconciler_Reconcile(t *testing.T) {
 	// 6) bind path
 	// mocked happy bind function
 	bindCalled := false
-	bindFunc := func(claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
+	bindFunc := func(ctx context.Context, claim corev1alpha1.ResourceClaim, handler ResourceHandler) (reconcile.Result, error) {
 		bindCalled = true
 		return Result, nil
 	}
@@ -351,7 +946,7 @@ func TestReconciler_Reconcile(t *testing.T) {
 	r.provision = nil
 	bindCalled = false
 	r.bind = bindFunc
-	rs, err = r._reconcile(claim)
+	rs, err = r._reconcile(ctx, claim)
 	g.Expect(rs).To(Equal(Result))
 	g.Expect(err).To(BeNil())
 	g.Expect(bindCalled).To(BeTrue())
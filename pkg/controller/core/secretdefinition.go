@@ -0,0 +1,213 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+)
+
+const (
+	secretDefinitionControllerName = "customsecretdefinitions.core.crossplane.io"
+
+	errorConflictingSecretDefinition = "Conflicting secret definition"
+)
+
+// SecretDefinitions holds the definitions of connection secrets that claim
+// reconcilers validate the secrets of bound resources against.
+var SecretDefinitions = NewSecretDefinitionRegistry()
+
+// SecretDefinitionRegistry holds the definitions of the connection secrets of
+// each kind of resource claim. Custom definitions registered from
+// CustomSecretDefinition objects take precedence over the built-in defaults
+// of claim controllers. When several custom definitions target the same kind
+// of claim, the one with the lowest name is used.
+type SecretDefinitionRegistry struct {
+	mu       sync.RWMutex
+	defaults map[string]corev1alpha1.CustomSecretDefinitionSpec
+	custom   map[string]corev1alpha1.CustomSecretDefinitionSpec
+}
+
+// NewSecretDefinitionRegistry returns an empty SecretDefinitionRegistry.
+func NewSecretDefinitionRegistry() *SecretDefinitionRegistry {
+	return &SecretDefinitionRegistry{
+		defaults: map[string]corev1alpha1.CustomSecretDefinitionSpec{},
+		custom:   map[string]corev1alpha1.CustomSecretDefinitionSpec{},
+	}
+}
+
+// SetDefault registers the built-in definition of the connection secrets of a
+// kind of resource claim.
+func (r *SecretDefinitionRegistry) SetDefault(csd *corev1alpha1.CustomSecretDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaults[strings.ToLower(csd.Spec.ClaimKind())] = csd.Spec
+}
+
+// Register the supplied CustomSecretDefinition, replacing any definition
+// previously registered under the same name.
+func (r *SecretDefinitionRegistry) Register(csd *corev1alpha1.CustomSecretDefinition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.custom[csd.Name] = csd.Spec
+}
+
+// Unregister the CustomSecretDefinition with the supplied name.
+func (r *SecretDefinitionRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.custom, name)
+}
+
+// Lookup returns the definition of the connection secrets of the supplied kind
+// of resource claim, e.g. "mysqlinstance.storage.crossplane.io/v1alpha1", or
+// nil if their keys are not defined.
+func (r *SecretDefinitionRegistry) Lookup(claimKind string) *corev1alpha1.CustomSecretDefinitionSpec {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name := r.lookupCustom(claimKind); name != "" {
+		spec := r.custom[name]
+		return &spec
+	}
+	if spec, ok := r.defaults[strings.ToLower(claimKind)]; ok {
+		return &spec
+	}
+	return nil
+}
+
+// Conflict returns the name of the registered CustomSecretDefinition that
+// takes precedence over the definition with the supplied name because it
+// targets the same kind of claim, or an empty string if there is none.
+func (r *SecretDefinitionRegistry) Conflict(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec, ok := r.custom[name]
+	if !ok {
+		return ""
+	}
+	if winner := r.lookupCustom(spec.ClaimKind()); winner != name {
+		return winner
+	}
+	return ""
+}
+
+// lookupCustom returns the lowest name of the custom definitions of the
+// supplied kind of claim, or an empty string if there are none. The caller
+// must hold the read lock.
+func (r *SecretDefinitionRegistry) lookupCustom(claimKind string) string {
+	names := make([]string, 0, len(r.custom))
+	for name, spec := range r.custom {
+		if strings.EqualFold(spec.ClaimKind(), claimKind) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
+// Validate returns an error if the supplied connection secret data does not
+// satisfy the definition of the secrets of the supplied kind of claim. Secrets
+// of claim kinds without a definition are always valid.
+func (r *SecretDefinitionRegistry) Validate(claimKind string, data map[string][]byte) error {
+	spec := r.Lookup(claimKind)
+	if spec == nil {
+		return nil
+	}
+	return spec.Validate(data)
+}
+
+// SecretDefinitionReconciler registers CustomSecretDefinitions with a
+// SecretDefinitionRegistry.
+type SecretDefinitionReconciler struct {
+	client.Client
+	definitions *SecretDefinitionRegistry
+	recorder    record.EventRecorder
+	log         logr.Logger
+}
+
+// AddSecretDefinitions creates a new CustomSecretDefinition controller that
+// registers definitions with SecretDefinitions, and adds it to the manager.
+func AddSecretDefinitions(mgr manager.Manager) error {
+	r := &SecretDefinitionReconciler{
+		Client:      mgr.GetClient(),
+		definitions: SecretDefinitions,
+		recorder:    mgr.GetRecorder(secretDefinitionControllerName),
+		log:         logging.Log.WithName(secretDefinitionControllerName),
+	}
+	c, err := controller.New(secretDefinitionControllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(secretDefinitionControllerName, r)})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(&source.Kind{Type: &corev1alpha1.CustomSecretDefinition{}}, &handler.EnqueueRequestForObject{})
+}
+
+// Reconcile registers the requested CustomSecretDefinition, or unregisters it
+// if it no longer exists. A warning event is recorded for definitions that are
+// ignored because a definition with a lower name targets the same claim kind.
+func (r *SecretDefinitionReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	ctx := logging.NewContext(context.Background(), r.log, request)
+
+	csd := &corev1alpha1.CustomSecretDefinition{}
+	if err := r.Get(ctx, request.NamespacedName, csd); err != nil {
+		if errors.IsNotFound(err) {
+			r.definitions.Unregister(request.Name)
+			return Result, nil
+		}
+		return Result, err
+	}
+
+	logging.Reconciling(ctx, csd)
+
+	if csd.GetDeletionTimestamp() != nil {
+		r.definitions.Unregister(csd.Name)
+		return Result, nil
+	}
+
+	r.definitions.Register(csd)
+	if winner := r.definitions.Conflict(csd.Name); winner != "" {
+		msg := fmt.Sprintf("claim kind %s is already defined by CustomSecretDefinition %s, which takes precedence", csd.Spec.ClaimKind(), winner)
+		logging.FromContext(ctx).Info(msg)
+		logging.RecordEvent(ctx, r.recorder, csd, corev1.EventTypeWarning, errorConflictingSecretDefinition, msg)
+	}
+	return Result, nil
+}
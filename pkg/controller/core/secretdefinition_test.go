@@ -0,0 +1,143 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+const mysqlKindAPIVersion = "mysqlinstance.storage.crossplane.io/v1alpha1"
+
+func TestSecretDefinitionRegistry(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := NewSecretDefinitionRegistry()
+
+	// no definition: every secret is valid
+	g.Expect(r.Lookup(mysqlKindAPIVersion)).To(BeNil())
+	g.Expect(r.Validate(mysqlKindAPIVersion, nil)).To(Succeed())
+
+	// built-in definition
+	r.SetDefault(corev1alpha1.NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "mysqlinstance", "endpoint", "password"))
+	g.Expect(r.Validate(mysqlKindAPIVersion, map[string][]byte{"endpoint": []byte("e"), "password": []byte("p")})).To(Succeed())
+	g.Expect(r.Validate(mysqlKindAPIVersion, map[string][]byte{"endpoint": []byte("e")})).
+		To(MatchError("connection secret is missing required keys: password"))
+
+	// custom definition takes precedence over the built-in definition
+	custom := corev1alpha1.NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "MySQLInstance", "endpoint")
+	r.Register(custom)
+	g.Expect(r.Validate(mysqlKindAPIVersion, map[string][]byte{"endpoint": []byte("e")})).To(Succeed())
+
+	// unregistered custom definition falls back to the built-in definition
+	r.Unregister(custom.Name)
+	g.Expect(r.Validate(mysqlKindAPIVersion, map[string][]byte{"endpoint": []byte("e")})).To(HaveOccurred())
+
+	// nil registry: every secret is valid
+	var nilRegistry *SecretDefinitionRegistry
+	g.Expect(nilRegistry.Validate(mysqlKindAPIVersion, nil)).To(Succeed())
+}
+
+func TestSecretDefinitionRegistryConflict(t *testing.T) {
+	g := NewGomegaWithT(t)
+	r := NewSecretDefinitionRegistry()
+
+	first := corev1alpha1.NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "mysqlinstance", "endpoint")
+	first.Name = "a-mysql"
+	second := corev1alpha1.NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "mysqlinstance", "password")
+	second.Name = "b-mysql"
+
+	// the definition with the lowest name is used regardless of registration order
+	r.Register(second)
+	r.Register(first)
+	for i := 0; i < 10; i++ {
+		g.Expect(r.Lookup(mysqlKindAPIVersion)).To(Equal(&first.Spec))
+	}
+	g.Expect(r.Conflict(first.Name)).To(BeEmpty())
+	g.Expect(r.Conflict(second.Name)).To(Equal(first.Name))
+	g.Expect(r.Conflict("unknown")).To(BeEmpty())
+
+	// the remaining definition is used once the preferred one is unregistered
+	r.Unregister(first.Name)
+	g.Expect(r.Lookup(mysqlKindAPIVersion)).To(Equal(&second.Spec))
+	g.Expect(r.Conflict(second.Name)).To(BeEmpty())
+}
+
+func TestSecretDefinitionReconcile(t *testing.T) {
+	g := NewGomegaWithT(t)
+	csd := corev1alpha1.NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "mysqlinstance", "endpoint")
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: csd.Name}}
+
+	mc := &MockClient{}
+	recorder := &eventRecorder{}
+	r := &SecretDefinitionReconciler{Client: mc, definitions: NewSecretDefinitionRegistry(), recorder: recorder}
+
+	// definition exists and is registered
+	mc.MockGet = func(args ...interface{}) error {
+		*args[2].(*corev1alpha1.CustomSecretDefinition) = *csd
+		return nil
+	}
+	rs, err := r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(r.definitions.Lookup(mysqlKindAPIVersion)).To(Equal(&csd.Spec))
+	g.Expect(recorder.reasons).To(BeEmpty())
+
+	// a conflicting definition with a higher name is registered but ignored
+	conflicting := corev1alpha1.NewCustomSecretDefinition("storage.crossplane.io", "v1alpha1", "mysqlinstance", "password")
+	conflicting.Name = "z-" + csd.Name
+	mc.MockGet = func(args ...interface{}) error {
+		*args[2].(*corev1alpha1.CustomSecretDefinition) = *conflicting
+		return nil
+	}
+	rs, err = r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: conflicting.Name}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(r.definitions.Lookup(mysqlKindAPIVersion)).To(Equal(&csd.Spec))
+	g.Expect(recorder.reasons).To(Equal([]string{errorConflictingSecretDefinition}))
+	r.definitions.Unregister(conflicting.Name)
+
+	// definition no longer exists and is unregistered
+	mc.MockGet = func(...interface{}) error {
+		return kerrors.NewNotFound(schema.GroupResource{}, csd.Name)
+	}
+	rs, err = r.Reconcile(request)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(rs).To(Equal(Result))
+	g.Expect(r.definitions.Lookup(mysqlKindAPIVersion)).To(BeNil())
+}
+
+// eventRecorder records the reasons of the events it is asked to record.
+type eventRecorder struct {
+	MockRecorder
+	reasons []string
+}
+
+func (r *eventRecorder) Event(_ runtime.Object, _, reason, _ string) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func (r *eventRecorder) AnnotatedEventf(_ runtime.Object, _ map[string]string, _, reason, _ string, _ ...interface{}) {
+	r.reasons = append(r.reasons, reason)
+}
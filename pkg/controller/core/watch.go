@@ -0,0 +1,136 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+// WatchResources configures the supplied claim controller to reconcile claims
+// of the supplied kind whenever the concrete resources they are bound to, or
+// the connection secrets of those resources, change. This allows claim secrets
+// to be kept up to date without waiting for the next resync of the claim.
+// Resources and handlers whose types are not registered with the scheme of the
+// manager are ignored. The connection secrets of ExternalResources are always
+// mapped, because any class may configure an external provisioner.
+func WatchResources(mgr manager.Manager, c controller.Controller, claimKind string, handlers map[string]ResourceHandler, resources ...runtime.Object) error {
+	for _, r := range enabledResources(mgr.GetScheme(), resources) {
+		err := c.Watch(&source.Kind{Type: r}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &resourceClaimMapper{claimKind: claimKind},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: &secretClaimMapper{
+			client:   mgr.GetClient(),
+			mapper:   &resourceClaimMapper{claimKind: claimKind},
+			handlers: withExternalResourceHandler(enabledHandlers(mgr.GetScheme(), handlers)),
+		},
+	})
+}
+
+// resourceClaimMapper maps a concrete resource to a reconcile request for the
+// claim of the configured kind it is bound to.
+type resourceClaimMapper struct {
+	claimKind string
+}
+
+func (m *resourceClaimMapper) Map(o handler.MapObject) []reconcile.Request {
+	res, ok := o.Object.(corev1alpha1.Resource)
+	if !ok {
+		return nil
+	}
+	return m.requests(res)
+}
+
+func (m *resourceClaimMapper) requests(res corev1alpha1.Resource) []reconcile.Request {
+	ref := res.ClaimRef()
+	if ref == nil || res.IsReleased() || !strings.EqualFold(ref.Kind, m.claimKind) {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}}}
+}
+
+// secretClaimMapper maps the connection secret of a concrete resource to a
+// reconcile request for the claim of the configured kind that resource is
+// bound to. The concrete resource is identified by the owner references of the
+// secret.
+type secretClaimMapper struct {
+	client   client.Client
+	mapper   *resourceClaimMapper
+	handlers map[string]ResourceHandler
+}
+
+func (m *secretClaimMapper) Map(o handler.MapObject) []reconcile.Request {
+	if _, ok := o.Object.(*corev1.Secret); !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, ref := range o.Meta.GetOwnerReferences() {
+		h := handlerForOwner(m.handlers, ref)
+		if h == nil {
+			continue
+		}
+		res, err := h.Find(context.Background(), types.NamespacedName{Namespace: o.Meta.GetNamespace(), Name: ref.Name}, m.client)
+		if err != nil || res.ConnectionSecretName() != o.Meta.GetName() {
+			continue
+		}
+		requests = append(requests, m.mapper.requests(res)...)
+	}
+	return requests
+}
+
+// withExternalResourceHandler returns the supplied handlers and the handler of
+// ExternalResources.
+func withExternalResourceHandler(handlers map[string]ResourceHandler) map[string]ResourceHandler {
+	h := make(map[string]ResourceHandler, len(handlers)+1)
+	for k, v := range handlers {
+		h[k] = v
+	}
+	h[corev1alpha1.ExternalResourceKindAPIVersion] = NewExternalResourceHandler()
+	return h
+}
+
+// handlerForOwner returns the handler for the kind of resource referenced by
+// the supplied owner reference, or nil if no handler manages that kind.
+func handlerForOwner(handlers map[string]ResourceHandler, ref metav1.OwnerReference) ResourceHandler {
+	kind := ref.Kind + "." + ref.APIVersion
+	for k, h := range handlers {
+		if strings.EqualFold(k, kind) {
+			return h
+		}
+	}
+	return nil
+}
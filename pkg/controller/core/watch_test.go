@@ -0,0 +1,127 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+const testResourceKindAPIVersion = "testresource.core.crossplane.io/v1alpha1"
+
+func boundResource(secretName string, claimRef *corev1.ObjectReference) *corev1alpha1.BasicResource {
+	br := corev1alpha1.NewBasicResource(nil, secretName, "", "available")
+	br.SetClaimRef(claimRef)
+	br.SetBound(true)
+	return br
+}
+
+func TestResourceClaimMapper(t *testing.T) {
+	g := NewGomegaWithT(t)
+	m := &resourceClaimMapper{claimKind: "testresourceclaim"}
+	claimRef := testClaim().ObjectReference()
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+
+	// resource bound to a claim of the mapped kind
+	g.Expect(m.Map(handler.MapObject{Object: boundResource("", claimRef)})).To(Equal(want))
+
+	// resource bound to a claim of another kind
+	other := &corev1.ObjectReference{Kind: "otherclaim", Namespace: namespace, Name: name}
+	g.Expect(m.Map(handler.MapObject{Object: boundResource("", other)})).To(BeEmpty())
+
+	// resource that is not bound to a claim
+	g.Expect(m.Map(handler.MapObject{Object: boundResource("", nil)})).To(BeEmpty())
+
+	// resource that was released by its claim
+	released := boundResource("", claimRef)
+	released.SetReleased()
+	g.Expect(m.Map(handler.MapObject{Object: released})).To(BeEmpty())
+
+	// object that is not a resource
+	g.Expect(m.Map(handler.MapObject{Object: &corev1.Secret{}})).To(BeEmpty())
+}
+
+func TestSecretClaimMapper(t *testing.T) {
+	g := NewGomegaWithT(t)
+	claimRef := testClaim().ObjectReference()
+	want := []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+
+	h := &MockResourceHandler{}
+	m := &secretClaimMapper{
+		mapper:   &resourceClaimMapper{claimKind: "testresourceclaim"},
+		handlers: map[string]ResourceHandler{testResourceKindAPIVersion: h},
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "test-secret",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "core.crossplane.io/v1alpha1",
+				Kind:       "TestResource",
+				Name:       "test-resource",
+			}},
+		},
+	}
+
+	// secret of a resource bound to a claim of the mapped kind
+	var found types.NamespacedName
+	h.MockFind = func(n types.NamespacedName, _ client.Client) (corev1alpha1.Resource, error) {
+		found = n
+		return boundResource("test-secret", claimRef), nil
+	}
+	g.Expect(m.Map(handler.MapObject{Meta: secret, Object: secret})).To(Equal(want))
+	g.Expect(found).To(Equal(types.NamespacedName{Namespace: namespace, Name: "test-resource"}))
+
+	// secret owned by a resource that uses another connection secret
+	h.MockFind = func(types.NamespacedName, client.Client) (corev1alpha1.Resource, error) {
+		return boundResource("other-secret", claimRef), nil
+	}
+	g.Expect(m.Map(handler.MapObject{Meta: secret, Object: secret})).To(BeEmpty())
+
+	// secret owned by a resource that cannot be found
+	h.MockFind = func(types.NamespacedName, client.Client) (corev1alpha1.Resource, error) {
+		return nil, fmt.Errorf("test-error")
+	}
+	g.Expect(m.Map(handler.MapObject{Meta: secret, Object: secret})).To(BeEmpty())
+
+	// secret that is not owned by a known kind of resource
+	secret.OwnerReferences[0].Kind = "OtherResource"
+	g.Expect(m.Map(handler.MapObject{Meta: secret, Object: secret})).To(BeEmpty())
+
+	// secret of an ExternalResource, whose handler is always known
+	m.handlers = withExternalResourceHandler(m.handlers)
+	m.client = &MockClient{MockGet: func(args ...interface{}) error {
+		res := args[2].(*corev1alpha1.ExternalResource)
+		res.Name = args[1].(types.NamespacedName).Name
+		res.Spec.ConnectionSecretRef = &corev1.LocalObjectReference{Name: "test-secret"}
+		res.Spec.ClaimRef = claimRef
+		return nil
+	}}
+	secret.OwnerReferences[0].Kind = "ExternalResource"
+	g.Expect(m.Map(handler.MapObject{Meta: secret, Object: secret})).To(Equal(want))
+}
@@ -18,163 +18,49 @@ package cache
 
 import (
 	"context"
-	"log"
-	"time"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
-	kerrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/clients/gcp"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp/cloudmemorystore"
-	"github.com/crossplaneio/crossplane/pkg/util"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 const (
 	controllerName = "cloudmemorystoreinstances.cache.gcp.crossplane.io"
-	finalizerName  = "finalizer." + controllerName
 
-	reasonFetchingClient   = "failed to fetch CloudMemorystore client"
-	reasonCreatingInstance = "failed to create CloudMemorystore instance"
-	reasonDeletingInstance = "failed to delete CloudMemorystore instance"
-	reasonSyncingInstance  = "failed to sync CloudMemorystore instance"
-	reasonSyncingSecret    = "failed to sync CloudMemorystore connection secret" // nolint:gas,gosec
-
-	reconcileTimeout = 1 * time.Minute
+	errNotInstance = "managed resource is not a CloudMemorystore instance"
 )
 
-// A creator can create instances in an external store - e.g. the GCP API.
-type creator interface {
-	// Create the supplied instance in the external store. Returns true if the
-	// instance requires further reconciliation.
-	Create(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) (requeue bool)
-}
-
-// A syncer can sync instances with an external store - e.g. the GCP API.
-type syncer interface {
-	// Sync the supplied instance with the external store. Returns true if the
-	// instance requires further reconciliation.
-	Sync(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) (requeue bool)
-}
-
-// A deleter can delete instances from an external store - e.g. the GCP API.
-type deleter interface {
-	// Delete the supplied instance from the external store. Returns true if the
-	// instance requires further reconciliation.
-	Delete(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) (requeue bool)
-}
-
-// A createsyncdeleter an create, sync, and delete instances in an external
-// store - e.g. the GCP API.
-type createsyncdeleter interface {
-	creator
-	syncer
-	deleter
-}
-
-// cloudMemorystore is a createsyncdeleter using the GCP CloudMemorystore API.
-type cloudMemorystore struct {
-	client  cloudmemorystore.Client
-	project string
-}
-
-func (c *cloudMemorystore) Create(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool {
-	id := cloudmemorystore.NewInstanceID(c.project, i)
-	if _, err := c.client.CreateInstance(ctx, cloudmemorystore.NewCreateInstanceRequest(id, i)); err != nil {
-		i.Status.SetFailed(reasonCreatingInstance, err.Error())
-		return true
-	}
-
-	i.Status.InstanceName = id.Instance
-	i.Status.UnsetAllConditions()
-	i.Status.SetCreating()
-	util.AddFinalizer(&i.ObjectMeta, finalizerName)
-
-	return true
-}
-
-func (c *cloudMemorystore) Sync(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool {
-	id := cloudmemorystore.NewInstanceID(c.project, i)
-	gcpInstance, err := c.client.GetInstance(ctx, cloudmemorystore.NewGetInstanceRequest(id))
-	if err != nil {
-		i.Status.SetFailed(reasonSyncingInstance, err.Error())
-		return true
-	}
-
-	i.Status.State = gcpInstance.GetState().String()
-	i.Status.UnsetAllConditions()
-
-	switch i.Status.State {
-	case v1alpha1.StateReady:
-		i.Status.SetReady()
-	case v1alpha1.StateCreating:
-		i.Status.SetCreating()
-		return true
-	case v1alpha1.StateDeleting:
-		i.Status.SetDeleting()
-		return false
-	default:
-		// TODO(negz): Don't requeue in this scenario? The instance is probably
-		// in maintenance, updating, or repairing, which can take minutes.
-		return true
-	}
-
-	i.Status.Endpoint = gcpInstance.GetHost()
-	i.Status.Port = int(gcpInstance.GetPort())
-	i.Status.ProviderID = gcpInstance.GetName()
-
-	if !cloudmemorystore.NeedsUpdate(i, gcpInstance) {
-		return false
-	}
-
-	if _, err := c.client.UpdateInstance(ctx, cloudmemorystore.NewUpdateInstanceRequest(id, i)); err != nil {
-		i.Status.SetFailed(reasonSyncingInstance, err.Error())
-		return true
-	}
-
-	return false
-}
-
-func (c *cloudMemorystore) Delete(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool {
-	if i.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		id := cloudmemorystore.NewInstanceID(c.project, i)
-		if _, err := c.client.DeleteInstance(ctx, cloudmemorystore.NewDeleteInstanceRequest(id)); err != nil {
-			i.Status.SetFailed(reasonDeletingInstance, err.Error())
-			return true
-		}
-	}
-	i.Status.SetDeleting()
-	util.RemoveFinalizer(&i.ObjectMeta, finalizerName)
-	return false
-}
-
-// A connecter returns a createsyncdeleter that can create, sync, and delete
-// CloudMemorystore instances with an external store - for example the GCP API.
-type connecter interface {
-	Connect(context.Context, *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error)
+// Add creates a new CloudMemorystoreInstance Controller and adds it to the
+// Manager with default RBAC. The Manager will set fields on the Controller and
+// start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	c := &connecter{kube: mgr.GetClient(), newClient: cloudmemorystore.NewClient}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &v1alpha1.CloudMemorystoreInstance{} }, c,
+		gcp.ClassifyError, corev1alpha1.ReclaimDelete)
 }
 
-// providerConnecter is a connecter that returns a createsyncdeleter
-// authenticated using credentials read from a Crossplane Provider resource.
-type providerConnecter struct {
+// connecter returns CloudMemorystore clients authenticated using credentials
+// read from the Crossplane Provider referenced by a CloudMemorystoreInstance.
+type connecter struct {
 	kube      client.Client
 	newClient func(ctx context.Context, creds []byte) (cloudmemorystore.Client, error)
 }
 
-// Connect returns a createsyncdeleter backed by the GCP API. GCP credentials
-// are read from the Crossplane Provider referenced by the supplied
-// CloudMemorystoreInstance.
-func (c *providerConnecter) Connect(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(*v1alpha1.CloudMemorystoreInstance)
+	if !ok {
+		return nil, errors.New(errNotInstance)
+	}
+
 	p := &gcpv1alpha1.Provider{}
 	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.Spec.ProviderRef.Name}
 	if err := c.kube.Get(ctx, n, p); err != nil {
@@ -192,92 +78,108 @@ func (c *providerConnecter) Connect(ctx context.Context, i *v1alpha1.CloudMemory
 	}
 
 	client, err := c.newClient(ctx, s.Data[p.Spec.Secret.Key])
-	return &cloudMemorystore{client: client, project: p.Spec.ProjectID}, errors.Wrap(err, "cannot create new CloudMemorystore client")
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create new CloudMemorystore client")
+	}
+	return &external{client: client, project: p.Spec.ProjectID}, nil
 }
 
-// Reconciler reconciles CloudMemorystoreInstances read from the Kubernetes API
-// with an external store, typically the GCP API.
-type Reconciler struct {
-	connecter
-	kube client.Client
+// external manages CloudMemorystore instances using the GCP API.
+type external struct {
+	client  cloudmemorystore.Client
+	project string
 }
 
-// Add creates a new CloudMemorystoreInstance Controller and adds it to the
-// Manager with default RBAC. The Manager will set fields on the Controller and
-// start it when the Manager is Started.
-func Add(mgr manager.Manager) error {
-	r := &Reconciler{
-		connecter: &providerConnecter{kube: mgr.GetClient(), newClient: cloudmemorystore.NewClient},
-		kube:      mgr.GetClient(),
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(*v1alpha1.CloudMemorystoreInstance)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotInstance)
+	}
+
+	// Adopt the existing instance named by the managed resource, if any.
+	if i.Status.InstanceName == "" {
+		i.Status.InstanceName = corev1alpha1.ExternalName(i)
+	}
+
+	// The instance is unnamed. Assume it has not been created in GCP.
+	if i.Status.InstanceName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	id := cloudmemorystore.NewInstanceID(e.project, i)
+	gcpInstance, err := e.client.GetInstance(ctx, cloudmemorystore.NewGetInstanceRequest(id))
+	if gcp.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
-		return errors.Wrap(err, "cannot create Kubernetes controller")
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get CloudMemorystore instance %s", id.Name())
 	}
 
-	return c.Watch(&source.Kind{Type: &v1alpha1.CloudMemorystoreInstance{}}, &handler.EnqueueRequestForObject{})
-}
+	i.Status.State = gcpInstance.GetState().String()
+	if i.Status.State != v1alpha1.StateReady {
+		// TODO(negz): Don't poll in this scenario? The instance is probably
+		// in maintenance, updating, or repairing, which can take minutes.
+		return core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
 
-// Reconcile Google CloudMemorystore resources with the GCP API.
-func (r *Reconciler) Reconcile(req reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", v1alpha1.CloudMemorystoreInstanceKindAPIVersion, req)
+	i.Status.Endpoint = gcpInstance.GetHost()
+	i.Status.Port = int(gcpInstance.GetPort())
+	i.Status.ProviderID = gcpInstance.GetName()
 
-	ctx, cancel := context.WithTimeout(context.Background(), reconcileTimeout)
-	defer cancel()
+	o := core.ExternalObservation{
+		ResourceExists:   true,
+		ResourceReady:    true,
+		ResourceUpToDate: !cloudmemorystore.NeedsUpdate(i, gcpInstance),
 
-	i := &v1alpha1.CloudMemorystoreInstance{}
-	if err := r.kube.Get(ctx, req.NamespacedName, i); err != nil {
-		if kerrors.IsNotFound(err) {
-			return reconcile.Result{Requeue: false}, nil
-		}
-		return reconcile.Result{Requeue: false}, errors.Wrapf(err, "cannot get instance %s", req.NamespacedName)
+		// TODO(negz): Include the port here too?
+		ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(i.Status.Endpoint)},
 	}
-
-	client, err := r.Connect(ctx, i)
-	if err != nil {
-		i.Status.SetFailed(reasonFetchingClient, err.Error())
-		return reconcile.Result{Requeue: true}, errors.Wrapf(r.kube.Update(ctx, i), "cannot update instance %s", req.NamespacedName)
+	if !o.ResourceUpToDate {
+		o.Diff = cloudmemorystore.NewUpdateInstanceRequest(id, i).String()
 	}
+	return o, nil
+}
 
-	// The instance has been deleted from the API server. Delete from GCP.
-	if i.DeletionTimestamp != nil {
-		return reconcile.Result{Requeue: client.Delete(ctx, i)}, errors.Wrapf(r.kube.Update(ctx, i), "cannot update instance %s", req.NamespacedName)
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(*v1alpha1.CloudMemorystoreInstance)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotInstance)
 	}
 
-	// The instance is unnamed. Assume it has not been created in GCP.
-	if i.Status.InstanceName == "" {
-		return reconcile.Result{Requeue: client.Create(ctx, i)}, errors.Wrapf(r.kube.Update(ctx, i), "cannot update instance %s", req.NamespacedName)
+	id := cloudmemorystore.NewInstanceID(e.project, i)
+	if _, err := e.client.CreateInstance(ctx, cloudmemorystore.NewCreateInstanceRequest(id, i)); err != nil && !gcp.IsErrorAlreadyExists(err) {
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create CloudMemorystore instance %s", id.Name())
 	}
 
-	if err := r.upsertSecret(ctx, connectionSecret(i)); err != nil {
-		i.Status.SetFailed(reasonSyncingSecret, err.Error())
-		return reconcile.Result{Requeue: true}, errors.Wrapf(r.kube.Update(ctx, i), "cannot update instance %s", req.NamespacedName)
+	i.Status.InstanceName = id.Instance
+	return core.ExternalCreation{}, nil
+}
+
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	i, ok := mg.(*v1alpha1.CloudMemorystoreInstance)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotInstance)
 	}
 
-	// The instance exists in the API server and GCP. Sync it.
-	return reconcile.Result{Requeue: client.Sync(ctx, i)}, errors.Wrapf(r.kube.Update(ctx, i), "cannot update instance %s", req.NamespacedName)
+	id := cloudmemorystore.NewInstanceID(e.project, i)
+	_, err := e.client.UpdateInstance(ctx, cloudmemorystore.NewUpdateInstanceRequest(id, i))
+	return core.ExternalUpdate{}, errors.Wrapf(err, "cannot update CloudMemorystore instance %s", id.Name())
 }
 
-func (r *Reconciler) upsertSecret(ctx context.Context, s *corev1.Secret) error {
-	n := types.NamespacedName{Namespace: s.GetNamespace(), Name: s.GetName()}
-	if err := r.kube.Get(ctx, n, &corev1.Secret{}); err != nil {
-		if kerrors.IsNotFound(err) {
-			return errors.Wrapf(r.kube.Create(ctx, s), "cannot create secret %s", n)
-		}
-		return errors.Wrapf(err, "cannot get secret %s", n)
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(*v1alpha1.CloudMemorystoreInstance)
+	if !ok {
+		return errors.New(errNotInstance)
 	}
-	return errors.Wrapf(r.kube.Update(ctx, s), "cannot update secret %s", n)
-}
 
-func connectionSecret(i *v1alpha1.CloudMemorystoreInstance) *corev1.Secret {
-	return &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            i.ConnectionSecretName(),
-			Namespace:       i.Namespace,
-			OwnerReferences: []metav1.OwnerReference{i.OwnerReference()},
-		},
+	// we already started the deletion of the instance, nothing more to do
+	if i.Status.State == v1alpha1.StateDeleting {
+		return nil
+	}
 
-		// TODO(negz): Include the port here too?
-		Data: map[string][]byte{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(i.Status.Endpoint)},
+	id := cloudmemorystore.NewInstanceID(e.project, i)
+	if _, err := e.client.DeleteInstance(ctx, cloudmemorystore.NewDeleteInstanceRequest(id)); err != nil && !gcp.IsErrorNotFound(err) {
+		return errors.Wrapf(err, "cannot delete CloudMemorystore instance %s", id.Name())
 	}
+	return nil
 }
@@ -19,13 +19,14 @@ package cache
 import (
 	"context"
 	"testing"
-	"time"
 
 	redisv1 "cloud.google.com/go/redis/apiv1"
 	"github.com/go-test/deep"
 	gax "github.com/googleapis/gax-go"
 	"github.com/pkg/errors"
 	redisv1pb "google.golang.org/genproto/googleapis/cloud/redis/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,13 +34,13 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp/cloudmemorystore"
 	fakecloudmemorystore "github.com/crossplaneio/crossplane/pkg/clients/gcp/cloudmemorystore/fake"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -92,18 +93,10 @@ var (
 
 type instanceModifier func(*v1alpha1.CloudMemorystoreInstance)
 
-func withConditions(c ...corev1alpha1.Condition) instanceModifier {
-	return func(i *v1alpha1.CloudMemorystoreInstance) { i.Status.ConditionedStatus.Conditions = c }
-}
-
 func withState(s string) instanceModifier {
 	return func(i *v1alpha1.CloudMemorystoreInstance) { i.Status.State = s }
 }
 
-func withFinalizers(f ...string) instanceModifier {
-	return func(i *v1alpha1.CloudMemorystoreInstance) { i.ObjectMeta.Finalizers = f }
-}
-
 func withReclaimPolicy(p corev1alpha1.ReclaimPolicy) instanceModifier {
 	return func(i *v1alpha1.CloudMemorystoreInstance) { i.Spec.ReclaimPolicy = p }
 }
@@ -112,6 +105,12 @@ func withInstanceName(n string) instanceModifier {
 	return func(i *v1alpha1.CloudMemorystoreInstance) { i.Status.InstanceName = n }
 }
 
+func withExternalName(n string) instanceModifier {
+	return func(i *v1alpha1.CloudMemorystoreInstance) {
+		i.SetAnnotations(map[string]string{corev1alpha1.AnnotationExternalName: n})
+	}
+}
+
 func withProviderID(id string) instanceModifier {
 	return func(i *v1alpha1.CloudMemorystoreInstance) { i.Status.ProviderID = id }
 }
@@ -124,10 +123,6 @@ func withPort(p int) instanceModifier {
 	return func(i *v1alpha1.CloudMemorystoreInstance) { i.Status.Port = p }
 }
 
-func withDeletionTimestamp(t time.Time) instanceModifier {
-	return func(i *v1alpha1.CloudMemorystoreInstance) { i.ObjectMeta.DeletionTimestamp = &metav1.Time{Time: t} }
-}
-
 func instance(im ...instanceModifier) *v1alpha1.CloudMemorystoreInstance {
 	i := &v1alpha1.CloudMemorystoreInstance{
 		ObjectMeta: metav1.ObjectMeta{
@@ -137,6 +132,7 @@ func instance(im ...instanceModifier) *v1alpha1.CloudMemorystoreInstance {
 			Finalizers: []string{},
 		},
 		Spec: v1alpha1.CloudMemorystoreInstanceSpec{
+			Region:              region,
 			MemorySizeGB:        memorySizeGB,
 			RedisConfigs:        redisConfigs,
 			AuthorizedNetwork:   authorizedNetwork,
@@ -157,379 +153,39 @@ func instance(im ...instanceModifier) *v1alpha1.CloudMemorystoreInstance {
 	return i
 }
 
-// Test that our Reconciler implementation satisfies the Reconciler interface.
-var _ reconcile.Reconciler = &Reconciler{}
-
-func TestCreate(t *testing.T) {
-	cases := []struct {
-		name        string
-		csd         createsyncdeleter
-		i           *v1alpha1.CloudMemorystoreInstance
-		want        *v1alpha1.CloudMemorystoreInstance
-		wantRequeue bool
-	}{
-		{
-			name: "SuccessfulCreate",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockCreateInstance: func(_ context.Context, _ *redisv1pb.CreateInstanceRequest, _ ...gax.CallOption) (*redisv1.CreateInstanceOperation, error) {
-					return nil, nil
-				}},
-			},
-			i: instance(),
-			want: instance(
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-				withFinalizers(finalizerName),
-				withInstanceName(instanceName),
-			),
-			wantRequeue: true,
-		},
-		{
-			name: "FailedCreate",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockCreateInstance: func(_ context.Context, _ *redisv1pb.CreateInstanceRequest, _ ...gax.CallOption) (*redisv1.CreateInstanceOperation, error) {
-					return nil, errorBoom
-				},
-			}},
-			i: instance(),
-			want: instance(withConditions(
-				corev1alpha1.Condition{
-					Type:    corev1alpha1.Failed,
-					Status:  corev1.ConditionTrue,
-					Reason:  reasonCreatingInstance,
-					Message: errorBoom.Error(),
-				},
-			)),
-			wantRequeue: true,
-		},
+// gcpInstance returns a CloudMemorystore instance that is ready and matches
+// instance(), modified by the supplied function.
+func gcpInstance(m ...func(*redisv1pb.Instance)) *redisv1pb.Instance {
+	i := &redisv1pb.Instance{
+		Name:         qualifiedName,
+		State:        redisv1pb.Instance_READY,
+		MemorySizeGb: memorySizeGB,
+		RedisConfigs: redisConfigs,
+		Host:         host,
+		Port:         port,
 	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			gotRequeue := tc.csd.Create(ctx, tc.i)
-
-			if gotRequeue != tc.wantRequeue {
-				t.Errorf("tc.csd.Create(...): want: %t got: %t", tc.wantRequeue, gotRequeue)
-			}
-
-			if diff := deep.Equal(tc.want, tc.i); diff != nil {
-				t.Errorf("i: want != got:\n%s", diff)
-			}
-		})
-	}
-}
-
-func TestSync(t *testing.T) {
-	cases := []struct {
-		name        string
-		csd         createsyncdeleter
-		i           *v1alpha1.CloudMemorystoreInstance
-		want        *v1alpha1.CloudMemorystoreInstance
-		wantRequeue bool
-	}{
-		{
-			name: "SuccessfulSyncWhileInstanceCreating",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return &redisv1pb.Instance{State: redisv1pb.Instance_CREATING}, nil
-				},
-			}},
-			i: instance(
-				withInstanceName(instanceName),
-				withConditions(
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonCreatingInstance,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			want: instance(
-				withState(v1alpha1.StateCreating),
-				withInstanceName(instanceName),
-				withConditions(
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionFalse,
-						Reason:  reasonCreatingInstance,
-						Message: errorBoom.Error(),
-					},
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue},
-				),
-			),
-			wantRequeue: true,
-		},
-		{
-			name: "SuccessfulSyncWhileInstanceDeleting",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return &redisv1pb.Instance{State: redisv1pb.Instance_DELETING}, nil
-				},
-			}},
-			i: instance(
-				withInstanceName(instanceName),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			want: instance(
-				withInstanceName(instanceName),
-				withState(v1alpha1.StateDeleting),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
-		},
-		{
-			name: "SuccessfulSyncWhileInstanceUpdating",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return &redisv1pb.Instance{State: redisv1pb.Instance_UPDATING}, nil
-				},
-			}},
-			i: instance(
-				withInstanceName(instanceName),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			want: instance(
-				withInstanceName(instanceName),
-				withState(v1alpha1.StateUpdating),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionFalse}),
-			),
-			wantRequeue: true,
-		},
-		{
-			name: "SuccessfulSyncWhileInstanceReadyAndDoesNotNeedUpdate",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return &redisv1pb.Instance{
-						Name:         qualifiedName,
-						State:        redisv1pb.Instance_READY,
-						MemorySizeGb: memorySizeGB,
-						RedisConfigs: redisConfigs,
-						Host:         host,
-						Port:         port,
-						// This field is not in sync between Kubernetes and GCP,
-						// but we cannot update it in place, so the instance
-						// does not count as needing an update.
-						AuthorizedNetwork: "imdifferent",
-					}, nil
-				},
-			}},
-			i: instance(
-				withInstanceName(instanceName),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue}),
-			),
-			want: instance(
-				withInstanceName(instanceName),
-				withState(v1alpha1.StateReady),
-				withProviderID(qualifiedName),
-				withEndpoint(host),
-				withPort(port),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
-		},
-		{
-			name: "SuccessfulSyncWhileInstanceReadyAndNeedsUpdate",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return &redisv1pb.Instance{
-						Name:         qualifiedName,
-						State:        redisv1pb.Instance_READY,
-						MemorySizeGb: memorySizeGB + 1,
-						RedisConfigs: redisConfigs,
-						Host:         host,
-						Port:         port,
-					}, nil
-				},
-				MockUpdateInstance: func(_ context.Context, u *redisv1pb.UpdateInstanceRequest, _ ...gax.CallOption) (*redisv1.UpdateInstanceOperation, error) {
-					// The GCP API is reporting more memory than we specified in
-					// the Kubernetes API. Ensure we're resetting it to our
-					// specified value.
-					if u.Instance.MemorySizeGb != memorySizeGB {
-						t.Errorf("u.Instance.MemorySizeGB: want %d, got %d", memorySizeGB, u.Instance.MemorySizeGb)
-					}
-					return nil, nil
-				},
-			}},
-			i: instance(
-				withInstanceName(instanceName),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue}),
-			),
-			want: instance(
-				withInstanceName(instanceName),
-				withState(v1alpha1.StateReady),
-				withProviderID(qualifiedName),
-				withEndpoint(host),
-				withPort(port),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
-		},
-		{
-			name: "FailedGet",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return nil, errorBoom
-				},
-			}},
-			i: instance(
-				withInstanceName(instanceName),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue}),
-			),
-			want: instance(
-				withInstanceName(instanceName),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Creating, Status: corev1.ConditionTrue},
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonSyncingInstance,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			wantRequeue: true,
-		},
-		{
-			name: "FailedUpdate",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
-					return &redisv1pb.Instance{
-						Name:         qualifiedName,
-						State:        redisv1pb.Instance_READY,
-						MemorySizeGb: memorySizeGB + 1,
-						RedisConfigs: redisConfigs,
-						Host:         host,
-						Port:         port,
-					}, nil
-				},
-				MockUpdateInstance: func(_ context.Context, u *redisv1pb.UpdateInstanceRequest, _ ...gax.CallOption) (*redisv1.UpdateInstanceOperation, error) {
-					return nil, errorBoom
-				},
-			}},
-			i: instance(withInstanceName(instanceName),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue}),
-			),
-			want: instance(
-				withInstanceName(instanceName),
-				withState(v1alpha1.StateReady),
-				withProviderID(qualifiedName),
-				withEndpoint(host),
-				withPort(port),
-				withConditions(
-					corev1alpha1.Condition{Type: corev1alpha1.Ready, Status: corev1.ConditionTrue},
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonSyncingInstance,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			wantRequeue: true,
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			gotRequeue := tc.csd.Sync(ctx, tc.i)
-
-			if gotRequeue != tc.wantRequeue {
-				t.Errorf("tc.csd.Sync(...): want: %t got: %t", tc.wantRequeue, gotRequeue)
-			}
-
-			if diff := deep.Equal(tc.want, tc.i); diff != nil {
-				t.Errorf("i: want != got:\n%s", diff)
-			}
-		})
+	for _, fn := range m {
+		fn(i)
 	}
+	return i
 }
 
-func TestDelete(t *testing.T) {
-	cases := []struct {
-		name        string
-		csd         createsyncdeleter
-		i           *v1alpha1.CloudMemorystoreInstance
-		want        *v1alpha1.CloudMemorystoreInstance
-		wantRequeue bool
-	}{
-		{
-			name: "ReclaimRetainSuccessfulDelete",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockDeleteInstance: func(_ context.Context, _ *redisv1pb.DeleteInstanceRequest, _ ...gax.CallOption) (*redisv1.DeleteInstanceOperation, error) {
-					return nil, nil
-				}},
-			},
-			i: instance(withFinalizers(finalizerName), withReclaimPolicy(corev1alpha1.ReclaimRetain)),
-			want: instance(
-				withReclaimPolicy(corev1alpha1.ReclaimRetain),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
-		},
-		{
-			name: "ReclaimDeleteSuccessfulDelete",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockDeleteInstance: func(_ context.Context, _ *redisv1pb.DeleteInstanceRequest, _ ...gax.CallOption) (*redisv1.DeleteInstanceOperation, error) {
-					return nil, nil
-				}},
-			},
-			i: instance(withFinalizers(finalizerName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
-			want: instance(
-				withReclaimPolicy(corev1alpha1.ReclaimDelete),
-				withConditions(corev1alpha1.Condition{Type: corev1alpha1.Deleting, Status: corev1.ConditionTrue}),
-			),
-			wantRequeue: false,
-		},
-		{
-			name: "ReclaimDeleteFailedDelete",
-			csd: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{
-				MockDeleteInstance: func(_ context.Context, _ *redisv1pb.DeleteInstanceRequest, _ ...gax.CallOption) (*redisv1.DeleteInstanceOperation, error) {
-					return nil, errorBoom
-				}},
-			},
-			i: instance(withFinalizers(finalizerName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
-			want: instance(
-				withFinalizers(finalizerName),
-				withReclaimPolicy(corev1alpha1.ReclaimDelete),
-				withConditions(
-					corev1alpha1.Condition{
-						Type:    corev1alpha1.Failed,
-						Status:  corev1.ConditionTrue,
-						Reason:  reasonDeletingInstance,
-						Message: errorBoom.Error(),
-					},
-				),
-			),
-			wantRequeue: true,
-		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			gotRequeue := tc.csd.Delete(ctx, tc.i)
-
-			if gotRequeue != tc.wantRequeue {
-				t.Errorf("tc.csd.Delete(...): want: %t got: %t", tc.wantRequeue, gotRequeue)
-			}
-
-			if diff := deep.Equal(tc.want, tc.i); diff != nil {
-				t.Errorf("i: want != got:\n%s", diff)
-			}
-		})
-	}
-}
+// Test that our connecter and external implementations satisfy their
+// interfaces.
+var _ core.ExternalConnecter = &connecter{}
+var _ core.ExternalClient = &external{}
 
 func TestConnect(t *testing.T) {
 	cases := []struct {
 		name    string
-		conn    connecter
+		conn    *connecter
 		i       *v1alpha1.CloudMemorystoreInstance
-		want    createsyncdeleter
+		want    core.ExternalClient
 		wantErr error
 	}{
 		{
 			name: "SuccessfulConnect",
-			conn: &providerConnecter{
+			conn: &connecter{
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
 					switch key {
 					case client.ObjectKey{Namespace: namespace, Name: providerName}:
@@ -544,11 +200,11 @@ func TestConnect(t *testing.T) {
 				},
 			},
 			i:    instance(),
-			want: &cloudMemorystore{client: &fakecloudmemorystore.MockClient{}, project: project},
+			want: &external{client: &fakecloudmemorystore.MockClient{}, project: project},
 		},
 		{
 			name: "FailedToGetProvider",
-			conn: &providerConnecter{
+			conn: &connecter{
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
 					return kerrors.NewNotFound(schema.GroupResource{}, providerName)
 				}},
@@ -560,8 +216,8 @@ func TestConnect(t *testing.T) {
 			wantErr: errors.WithStack(errors.Errorf("cannot get provider %s/%s:  \"%s\" not found", namespace, providerName, providerName)),
 		},
 		{
-			name: "FailedToAssetProviderIsValid",
-			conn: &providerConnecter{
+			name: "FailedToAssertProviderIsValid",
+			conn: &connecter{
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
 					// This provider does not have condition ready, and thus is
 					// deemed invalid.
@@ -586,7 +242,7 @@ func TestConnect(t *testing.T) {
 		},
 		{
 			name: "FailedToGetProviderSecret",
-			conn: &providerConnecter{
+			conn: &connecter{
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
 					switch key {
 					case client.ObjectKey{Namespace: namespace, Name: providerName}:
@@ -605,7 +261,7 @@ func TestConnect(t *testing.T) {
 		},
 		{
 			name: "FailedToCreateCloudMemorystoreClient",
-			conn: &providerConnecter{
+			conn: &connecter{
 				kube: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
 					switch key {
 					case client.ObjectKey{Namespace: namespace, Name: providerName}:
@@ -618,7 +274,6 @@ func TestConnect(t *testing.T) {
 				newClient: func(_ context.Context, _ []byte) (cloudmemorystore.Client, error) { return nil, errorBoom },
 			},
 			i:       instance(),
-			want:    &cloudMemorystore{project: project},
 			wantErr: errors.Wrap(errorBoom, "cannot create new CloudMemorystore client"),
 		},
 	}
@@ -638,329 +293,309 @@ func TestConnect(t *testing.T) {
 	}
 }
 
-type mockConnector struct {
-	MockConnect func(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error)
-}
-
-func (c *mockConnector) Connect(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
-	return c.MockConnect(ctx, i)
-}
-
-type mockCSD struct {
-	MockCreate func(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool
-	MockSync   func(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool
-	MockDelete func(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool
-}
-
-func (csd *mockCSD) Create(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool {
-	return csd.MockCreate(ctx, i)
-}
-
-func (csd *mockCSD) Sync(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool {
-	return csd.MockSync(ctx, i)
-}
-
-func (csd *mockCSD) Delete(ctx context.Context, i *v1alpha1.CloudMemorystoreInstance) bool {
-	return csd.MockDelete(ctx, i)
-}
-
-func TestReconcile(t *testing.T) {
+func TestObserve(t *testing.T) {
 	cases := []struct {
 		name    string
-		rec     *Reconciler
-		req     reconcile.Request
-		want    reconcile.Result
+		e       *external
+		i       *v1alpha1.CloudMemorystoreInstance
+		want    *v1alpha1.CloudMemorystoreInstance
+		wantObs core.ExternalObservation
 		wantErr error
 	}{
 		{
-			name: "SuccessfulDelete",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
-					return &mockCSD{MockDelete: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) bool { return false }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance(withInstanceName(instanceName), withDeletionTimestamp(time.Now())))
-						return nil
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
+			name:    "InstanceNotCreated",
+			e:       &external{client: &fakecloudmemorystore.MockClient{}, project: project},
+			i:       instance(),
+			want:    instance(),
+			wantObs: core.ExternalObservation{ResourceExists: false},
+		},
+		{
+			name: "InstanceNotFound",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					return nil, status.Error(codes.NotFound, "not found")
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: nil,
+			}, project: project},
+			i:       instance(withInstanceName(instanceName)),
+			want:    instance(withInstanceName(instanceName)),
+			wantObs: core.ExternalObservation{ResourceExists: false},
 		},
 		{
-			name: "SuccessfulCreate",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
-					return &mockCSD{MockCreate: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) bool { return true }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance())
-						return nil
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
+			name: "InstanceAdopted",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, req *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					if req.GetName() != qualifiedName {
+						return nil, status.Error(codes.NotFound, "not found")
+					}
+					return &redisv1pb.Instance{State: redisv1pb.Instance_CREATING}, nil
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}, project: project},
+			i:       instance(withExternalName(instanceName)),
+			want:    instance(withExternalName(instanceName), withInstanceName(instanceName), withState(v1alpha1.StateCreating)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 		},
 		{
-			name: "SuccessfulSync",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
-					return &mockCSD{MockSync: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) bool { return false }}, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case client.ObjectKey{Namespace: namespace, Name: instanceName}:
-							*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance(withInstanceName(instanceName), withEndpoint(host)))
-						case client.ObjectKey{Namespace: namespace, Name: connectionSecretName}:
-							return kerrors.NewNotFound(schema.GroupResource{}, connectionSecretName)
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
-					MockCreate: func(_ context.Context, _ runtime.Object) error { return nil },
+			name: "InstanceCreating",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					return &redisv1pb.Instance{State: redisv1pb.Instance_CREATING}, nil
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: nil,
+			}, project: project},
+			i:       instance(withInstanceName(instanceName)),
+			want:    instance(withInstanceName(instanceName), withState(v1alpha1.StateCreating)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
 		},
 		{
-			name: "FailedToGetNonexistentInstance",
-			rec: &Reconciler{
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						return kerrors.NewNotFound(schema.GroupResource{}, instanceName)
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
+			name: "InstanceUpdating",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					return &redisv1pb.Instance{State: redisv1pb.Instance_UPDATING}, nil
 				},
+			}, project: project},
+			i:       instance(withInstanceName(instanceName)),
+			want:    instance(withInstanceName(instanceName), withState(v1alpha1.StateUpdating)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		{
+			name: "InstanceDeleting",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					return &redisv1pb.Instance{State: redisv1pb.Instance_DELETING}, nil
+				},
+			}, project: project},
+			i:       instance(withInstanceName(instanceName)),
+			want:    instance(withInstanceName(instanceName), withState(v1alpha1.StateDeleting)),
+			wantObs: core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		{
+			name: "InstanceReadyAndDoesNotNeedUpdate",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					// AuthorizedNetwork is not in sync between Kubernetes and
+					// GCP, but we cannot update it in place, so the instance
+					// does not count as needing an update.
+					return gcpInstance(func(i *redisv1pb.Instance) { i.AuthorizedNetwork = "imdifferent" }), nil
+				},
+			}, project: project},
+			i: instance(withInstanceName(instanceName)),
+			want: instance(
+				withInstanceName(instanceName),
+				withState(v1alpha1.StateReady),
+				withProviderID(qualifiedName),
+				withEndpoint(host),
+				withPort(port),
+			),
+			wantObs: core.ExternalObservation{
+				ResourceExists:    true,
+				ResourceReady:     true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host)},
 			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: nil,
 		},
 		{
-			name: "FailedToGetExtantInstance",
-			rec: &Reconciler{
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						return errorBoom
-					},
-					MockUpdate: func(_ context.Context, _ runtime.Object) error { return nil },
+			name: "InstanceReadyAndNeedsUpdate",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
+					return gcpInstance(func(i *redisv1pb.Instance) { i.MemorySizeGb = memorySizeGB + 1 }), nil
 				},
+			}, project: project},
+			i: instance(withInstanceName(instanceName)),
+			want: instance(
+				withInstanceName(instanceName),
+				withState(v1alpha1.StateReady),
+				withProviderID(qualifiedName),
+				withEndpoint(host),
+				withPort(port),
+			),
+			wantObs: core.ExternalObservation{
+				ResourceExists:   true,
+				ResourceReady:    true,
+				ResourceUpToDate: false,
+				Diff: cloudmemorystore.NewUpdateInstanceRequest(
+					cloudmemorystore.NewInstanceID(project, instance(withInstanceName(instanceName))),
+					instance(withInstanceName(instanceName)),
+				).String(),
+				ConnectionDetails: core.ConnectionDetails{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host)},
 			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: false},
-			wantErr: errors.Wrapf(errorBoom, "cannot get instance %s/%s", namespace, instanceName),
 		},
 		{
-			name: "FailedToConnect",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
+			name: "FailedGetInstance",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockGetInstance: func(_ context.Context, _ *redisv1pb.GetInstanceRequest, _ ...gax.CallOption) (*redisv1pb.Instance, error) {
 					return nil, errorBoom
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance())
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						want := instance(withConditions(
-							corev1alpha1.Condition{
-								Type:    corev1alpha1.Failed,
-								Status:  corev1.ConditionTrue,
-								Reason:  reasonFetchingClient,
-								Message: errorBoom.Error(),
-							},
-						))
-						got := obj.(*v1alpha1.CloudMemorystoreInstance)
-						if diff := deep.Equal(want, got); diff != nil {
-							t.Errorf("kube.Update(...): want != got:\n%s", diff)
-						}
-						return nil
-					},
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}, project: project},
+			i:       instance(withInstanceName(instanceName)),
+			want:    instance(withInstanceName(instanceName)),
+			wantErr: errors.Wrapf(errorBoom, "cannot get CloudMemorystore instance %s", qualifiedName),
 		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotObs, gotErr := tc.e.Observe(ctx, tc.i)
+
+			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
+				t.Errorf("tc.e.Observe(...): want error != got error:\n%s", diff)
+			}
+
+			if diff := deep.Equal(tc.wantObs, gotObs); diff != nil {
+				t.Errorf("tc.e.Observe(...): want != got:\n%s", diff)
+			}
+
+			if diff := deep.Equal(tc.want, tc.i); diff != nil {
+				t.Errorf("i: want != got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name    string
+		e       *external
+		i       *v1alpha1.CloudMemorystoreInstance
+		want    *v1alpha1.CloudMemorystoreInstance
+		wantErr error
+	}{
 		{
-			name: "FailedToGetConnectionSecret",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
+			name: "SuccessfulCreate",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockCreateInstance: func(_ context.Context, _ *redisv1pb.CreateInstanceRequest, _ ...gax.CallOption) (*redisv1.CreateInstanceOperation, error) {
 					return nil, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case types.NamespacedName{Namespace: namespace, Name: connectionSecretName}:
-							return errorBoom
-						case types.NamespacedName{Namespace: namespace, Name: instanceName}:
-							*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance(withInstanceName(instanceName)))
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						want := instance(
-							withInstanceName(instanceName),
-							withConditions(
-								corev1alpha1.Condition{
-									Type:    corev1alpha1.Failed,
-									Status:  corev1.ConditionTrue,
-									Reason:  reasonSyncingSecret,
-									Message: errors.Wrapf(errorBoom, "cannot get secret %s/%s", namespace, connectionSecretName).Error(),
-								},
-							))
-						got := obj.(*v1alpha1.CloudMemorystoreInstance)
-						if diff := deep.Equal(want, got); diff != nil {
-							t.Errorf("kube.Update(...): want != got:\n%s", diff)
-						}
-						return nil
-					},
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}, project: project},
+			i:    instance(),
+			want: instance(withInstanceName(instanceName)),
 		},
 		{
-			name: "FailedToCreateConnectionSecret",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
-					return nil, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case types.NamespacedName{Namespace: namespace, Name: connectionSecretName}:
-							return kerrors.NewNotFound(schema.GroupResource{}, connectionSecretName)
-						case types.NamespacedName{Namespace: namespace, Name: instanceName}:
-							*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance(withInstanceName(instanceName)))
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						want := instance(
-							withInstanceName(instanceName),
-							withConditions(
-								corev1alpha1.Condition{
-									Type:    corev1alpha1.Failed,
-									Status:  corev1.ConditionTrue,
-									Reason:  reasonSyncingSecret,
-									Message: errors.Wrapf(errorBoom, "cannot create secret %s/%s", namespace, connectionSecretName).Error(),
-								},
-							))
-						got := obj.(*v1alpha1.CloudMemorystoreInstance)
-						if diff := deep.Equal(want, got); diff != nil {
-							t.Errorf("kube.Update(...): want != got:\n%s", diff)
-						}
-						return nil
-					},
-					MockCreate: func(_ context.Context, obj runtime.Object) error { return errorBoom },
+			name: "InstanceAlreadyExists",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockCreateInstance: func(_ context.Context, _ *redisv1pb.CreateInstanceRequest, _ ...gax.CallOption) (*redisv1.CreateInstanceOperation, error) {
+					return nil, status.Error(codes.AlreadyExists, "already exists")
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}, project: project},
+			i:    instance(),
+			want: instance(withInstanceName(instanceName)),
 		},
 		{
-			name: "FailedToUpdateConnectionSecret",
-			rec: &Reconciler{
-				connecter: &mockConnector{MockConnect: func(_ context.Context, _ *v1alpha1.CloudMemorystoreInstance) (createsyncdeleter, error) {
-					return nil, nil
-				}},
-				kube: &test.MockClient{
-					MockGet: func(_ context.Context, key client.ObjectKey, obj runtime.Object) error {
-						switch key {
-						case types.NamespacedName{Namespace: namespace, Name: connectionSecretName}:
-							return nil
-						case types.NamespacedName{Namespace: namespace, Name: instanceName}:
-							*obj.(*v1alpha1.CloudMemorystoreInstance) = *(instance(withInstanceName(instanceName)))
-						}
-						return nil
-					},
-					MockUpdate: func(_ context.Context, obj runtime.Object) error {
-						switch got := obj.(type) {
-						case *corev1.Secret:
-							return errorBoom
-						case *v1alpha1.CloudMemorystoreInstance:
-							want := instance(
-								withInstanceName(instanceName),
-								withConditions(
-									corev1alpha1.Condition{
-										Type:    corev1alpha1.Failed,
-										Status:  corev1.ConditionTrue,
-										Reason:  reasonSyncingSecret,
-										Message: errors.Wrapf(errorBoom, "cannot update secret %s/%s", namespace, connectionSecretName).Error(),
-									},
-								))
-							if diff := deep.Equal(want, got); diff != nil {
-								t.Errorf("kube.Update(...): want != got:\n%s", diff)
-							}
-						}
-						return nil
-					},
+			name: "FailedCreate",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockCreateInstance: func(_ context.Context, _ *redisv1pb.CreateInstanceRequest, _ ...gax.CallOption) (*redisv1.CreateInstanceOperation, error) {
+					return nil, errorBoom
 				},
-			},
-			req:     reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: instanceName}},
-			want:    reconcile.Result{Requeue: true},
-			wantErr: nil,
+			}, project: project},
+			i:       instance(),
+			want:    instance(),
+			wantErr: errors.Wrapf(errorBoom, "cannot create CloudMemorystore instance %s", qualifiedName),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			gotResult, gotErr := tc.rec.Reconcile(tc.req)
+			_, gotErr := tc.e.Create(ctx, tc.i)
 
 			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
-				t.Errorf("tc.rec.Reconcile(...): want error != got error:\n%s", diff)
+				t.Errorf("tc.e.Create(...): want error != got error:\n%s", diff)
 			}
 
-			if diff := deep.Equal(tc.want, gotResult); diff != nil {
-				t.Errorf("tc.rec.Reconcile(...): want != got:\n%s", diff)
+			if diff := deep.Equal(tc.want, tc.i); diff != nil {
+				t.Errorf("i: want != got:\n%s", diff)
 			}
 		})
 	}
 }
 
-func TestConnectionSecret(t *testing.T) {
+func TestUpdate(t *testing.T) {
 	cases := []struct {
-		name string
-		i    *v1alpha1.CloudMemorystoreInstance
-		want *corev1.Secret
+		name    string
+		e       *external
+		i       *v1alpha1.CloudMemorystoreInstance
+		wantErr error
 	}{
 		{
-			name: "Successful",
-			i:    instance(withEndpoint(host)),
-			want: &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      connectionSecretName,
-					Namespace: namespace,
-					OwnerReferences: []metav1.OwnerReference{{
-						APIVersion: v1alpha1.APIVersion,
-						Kind:       v1alpha1.CloudMemorystoreInstanceKind,
-						Name:       instanceName,
-						UID:        uid,
-					}},
+			name: "SuccessfulUpdate",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockUpdateInstance: func(_ context.Context, r *redisv1pb.UpdateInstanceRequest, _ ...gax.CallOption) (*redisv1.UpdateInstanceOperation, error) {
+					if r.GetInstance().GetName() != qualifiedName {
+						t.Errorf("r.Instance.Name: want %s, got %s", qualifiedName, r.GetInstance().GetName())
+					}
+					return nil, nil
 				},
-				Data: map[string][]byte{corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(host)},
-			},
+			}, project: project},
+			i: instance(withInstanceName(instanceName)),
+		},
+		{
+			name: "FailedUpdate",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockUpdateInstance: func(_ context.Context, _ *redisv1pb.UpdateInstanceRequest, _ ...gax.CallOption) (*redisv1.UpdateInstanceOperation, error) {
+					return nil, errorBoom
+				},
+			}, project: project},
+			i:       instance(withInstanceName(instanceName)),
+			wantErr: errors.Wrapf(errorBoom, "cannot update CloudMemorystore instance %s", qualifiedName),
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := connectionSecret(tc.i)
-			if diff := deep.Equal(tc.want, got); diff != nil {
-				t.Errorf("connectionSecret(...): want != got:\n%s", diff)
+			_, gotErr := tc.e.Update(ctx, tc.i)
+
+			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
+				t.Errorf("tc.e.Update(...): want error != got error:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []struct {
+		name    string
+		e       *external
+		i       *v1alpha1.CloudMemorystoreInstance
+		wantErr error
+	}{
+		{
+			name: "SuccessfulDelete",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockDeleteInstance: func(_ context.Context, _ *redisv1pb.DeleteInstanceRequest, _ ...gax.CallOption) (*redisv1.DeleteInstanceOperation, error) {
+					return nil, nil
+				},
+			}, project: project},
+			i: instance(withInstanceName(instanceName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+		},
+		{
+			name: "InstanceAlreadyDeleting",
+			e:    &external{client: &fakecloudmemorystore.MockClient{}, project: project},
+			i:    instance(withInstanceName(instanceName), withState(v1alpha1.StateDeleting)),
+		},
+		{
+			name: "InstanceNotFound",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockDeleteInstance: func(_ context.Context, _ *redisv1pb.DeleteInstanceRequest, _ ...gax.CallOption) (*redisv1.DeleteInstanceOperation, error) {
+					return nil, status.Error(codes.NotFound, "not found")
+				},
+			}, project: project},
+			i: instance(withInstanceName(instanceName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+		},
+		{
+			name: "FailedDelete",
+			e: &external{client: &fakecloudmemorystore.MockClient{
+				MockDeleteInstance: func(_ context.Context, _ *redisv1pb.DeleteInstanceRequest, _ ...gax.CallOption) (*redisv1.DeleteInstanceOperation, error) {
+					return nil, errorBoom
+				},
+			}, project: project},
+			i:       instance(withInstanceName(instanceName), withReclaimPolicy(corev1alpha1.ReclaimDelete)),
+			wantErr: errors.Wrapf(errorBoom, "cannot delete CloudMemorystore instance %s", qualifiedName),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotErr := tc.e.Delete(ctx, tc.i)
+
+			if diff := deep.Equal(tc.wantErr, gotErr); diff != nil {
+				t.Errorf("tc.e.Delete(...): want error != got error:\n%s", diff)
 			}
 		})
 	}
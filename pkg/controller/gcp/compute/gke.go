@@ -20,269 +20,195 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"log"
 
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/container/v1"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp/gke"
-	"github.com/crossplaneio/crossplane/pkg/util"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
 	controllerName    = "gke.compute.gcp.crossplane.io"
-	finalizer         = "finalizer." + controllerName
 	clusterNamePrefix = "gke-"
 
-	errorClusterClient = "Failed to create cluster client"
-	errorCreateCluster = "Failed to create new cluster"
-	errorSyncCluster   = "Failed to sync cluster state"
-	errorDeleteCluster = "Failed to delete cluster"
+	errNotGKECluster = "managed resource is not a GKE cluster"
 )
 
-var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
-)
-
-// Add creates a new Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
-// and Start it when the Manager is Started.
+// Add creates a new GKECluster controller and adds it to the Manager. The
+// Manager will set fields on the controller and start it when the Manager is
+// started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	c := &connecter{
+		kube:       mgr.GetClient(),
+		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
+		newClient:  func(creds *google.Credentials) (gke.Client, error) { return gke.NewClusterClient(creds) },
+	}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &gcpcomputev1alpha1.GKECluster{} }, c,
+		gcp.ClassifyError, corev1alpha1.ReclaimDelete)
 }
 
-// Reconciler reconciles a Provider object
-type Reconciler struct {
-	client.Client
-	scheme     *runtime.Scheme
+// connecter returns GKE clients authenticated using the credentials of the
+// GCP Provider referenced by a GKECluster.
+type connecter struct {
+	kube       client.Client
 	kubeclient kubernetes.Interface
-	recorder   record.EventRecorder
-
-	connect func(*gcpcomputev1alpha1.GKECluster) (gke.Client, error)
-	create  func(*gcpcomputev1alpha1.GKECluster, gke.Client) (reconcile.Result, error)
-	sync    func(*gcpcomputev1alpha1.GKECluster, gke.Client) (reconcile.Result, error)
-	delete  func(*gcpcomputev1alpha1.GKECluster, gke.Client) (reconcile.Result, error)
+	newClient  func(*google.Credentials) (gke.Client, error)
 }
 
-// newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	r := &Reconciler{
-		Client:     mgr.GetClient(),
-		scheme:     mgr.GetScheme(),
-		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
-		recorder:   mgr.GetRecorder(controllerName),
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(*gcpcomputev1alpha1.GKECluster)
+	if !ok {
+		return nil, errors.New(errNotGKECluster)
 	}
-	r.connect = r._connect
-	r.create = r._create
-	r.sync = r._sync
-	r.delete = r._delete
-	return r
-}
 
-// add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	p := &gcpv1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
+	}
+
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
+	}
+
+	creds, err := gcp.ProviderCredentials(c.kubeclient, p, gke.DefaultScope)
 	if err != nil {
-		return err
+		return nil, errors.Wrapf(err, "cannot get credentials of provider %s", n)
 	}
 
-	// Watch for changes to Provider
-	err = c.Watch(&source.Kind{Type: &gcpcomputev1alpha1.GKECluster{}}, &handler.EnqueueRequestForObject{})
+	gc, err := c.newClient(creds)
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "cannot create GKE client")
 	}
 
-	return nil
+	return &external{client: gc}, nil
 }
 
-// fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *gcpcomputev1alpha1.GKECluster, reason, msg string) (reconcile.Result, error) {
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetFailed(reason, msg)
-	return resultRequeue, r.Update(context.TODO(), instance)
+// external manages GKE clusters using the GKE API.
+type external struct {
+	client gke.Client
 }
 
-// connectionSecret return secret object for cluster instance
-func (r *Reconciler) connectionSecret(instance *gcpcomputev1alpha1.GKECluster, cluster *container.Cluster) (*corev1.Secret, error) {
-	secret := instance.ConnectionSecret()
-
-	data := make(map[string][]byte)
-	data[corev1alpha1.ResourceCredentialsSecretEndpointKey] = []byte(cluster.Endpoint)
-	data[corev1alpha1.ResourceCredentialsSecretUserKey] = []byte(cluster.MasterAuth.Username)
-	data[corev1alpha1.ResourceCredentialsSecretPasswordKey] = []byte(cluster.MasterAuth.Password)
-
-	val, err := base64.StdEncoding.DecodeString(cluster.MasterAuth.ClusterCaCertificate)
-	if err != nil {
-		return nil, err
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(*gcpcomputev1alpha1.GKECluster)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotGKECluster)
 	}
-	data[corev1alpha1.ResourceCredentialsSecretCAKey] = val
 
-	val, err = base64.StdEncoding.DecodeString(cluster.MasterAuth.ClientCertificate)
-	if err != nil {
-		return nil, err
+	// Adopt the existing cluster named by the managed resource, if any.
+	if i.Status.ClusterName == "" {
+		i.Status.ClusterName = corev1alpha1.ExternalName(i)
 	}
-	data[corev1alpha1.ResourceCredentialsSecretClientCertKey] = val
 
-	val, err = base64.StdEncoding.DecodeString(cluster.MasterAuth.ClientKey)
-	if err != nil {
-		return nil, err
+	// The cluster is unnamed. Assume it has not been created in GCP.
+	if i.Status.ClusterName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
-	data[corev1alpha1.ResourceCredentialsSecretClientKeyKey] = val
-
-	secret.Data = data
-
-	return secret, nil
-}
 
-func (r *Reconciler) _connect(instance *gcpcomputev1alpha1.GKECluster) (gke.Client, error) {
-	// Fetch Provider
-	p := &gcpv1alpha1.Provider{}
-	providerNamespacedName := types.NamespacedName{
-		Namespace: instance.Namespace,
-		Name:      instance.Spec.ProviderRef.Name,
+	cluster, err := e.client.GetCluster(i.Spec.Zone, i.Status.ClusterName)
+	if gcp.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
-	err := r.Get(ctx, providerNamespacedName, p)
 	if err != nil {
-		return nil, err
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get GKE cluster %s", i.Status.ClusterName)
 	}
 
-	// Check provider status
-	if !p.IsValid() {
-		return nil, fmt.Errorf("provider status is invalid")
+	i.Status.State = cluster.Status
+	i.Status.ProviderID = cluster.SelfLink
+	if cluster.Status == gcpcomputev1alpha1.ClusterStateError {
+		return core.ExternalObservation{}, requeue.Terminal(errors.Errorf("GKE cluster %s is in error state: %s", i.Status.ClusterName, cluster.StatusMessage))
 	}
 
-	creds, err := gcp.ProviderCredentials(r.kubeclient, p, gke.DefaultScope)
+	// TODO: figure out how we going to handle cluster statuses other than RUNNING
+	if cluster.Status != gcpcomputev1alpha1.ClusterStateRunning {
+		return core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	cd, err := connectionDetails(cluster)
 	if err != nil {
-		return nil, err
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get connection details of GKE cluster %s", i.Status.ClusterName)
 	}
 
-	return gke.NewClusterClient(creds)
+	i.Status.Endpoint = cluster.Endpoint
+	return core.ExternalObservation{
+		ResourceExists:    true,
+		ResourceReady:     true,
+		ResourceUpToDate:  true,
+		ConnectionDetails: cd,
+	}, nil
 }
 
-func (r *Reconciler) _create(instance *gcpcomputev1alpha1.GKECluster, client gke.Client) (reconcile.Result, error) {
-	clusterName := fmt.Sprintf("%s%s", clusterNamePrefix, instance.UID)
-
-	_, err := client.CreateCluster(clusterName, instance.Spec)
-	if err != nil && !gcp.IsErrorAlreadyExists(err) {
-		if gcp.IsErrorBadRequest(err) {
-			instance.Status.SetFailed(errorCreateCluster, err.Error())
-			// do not requeue on bad requests
-			return result, r.Update(ctx, instance)
-		}
-		return r.fail(instance, errorCreateCluster, err.Error())
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(*gcpcomputev1alpha1.GKECluster)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotGKECluster)
 	}
 
-	instance.Status.State = gcpcomputev1alpha1.ClusterStateProvisioning
-
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetCreating()
-	instance.Status.ClusterName = clusterName
+	name := fmt.Sprintf("%s%s", clusterNamePrefix, i.UID)
+	if _, err := e.client.CreateCluster(name, i.Spec); err != nil && !gcp.IsErrorAlreadyExists(err) {
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create GKE cluster %s", name)
+	}
 
-	return resultRequeue, r.Update(ctx, instance)
+	i.Status.State = gcpcomputev1alpha1.ClusterStateProvisioning
+	i.Status.ClusterName = name
+	return core.ExternalCreation{}, nil
 }
 
-func (r *Reconciler) _sync(instance *gcpcomputev1alpha1.GKECluster, client gke.Client) (reconcile.Result, error) {
-	cluster, err := client.GetCluster(instance.Spec.Zone, instance.Status.ClusterName)
-	if err != nil {
-		return r.fail(instance, errorSyncCluster, err.Error())
-	}
-
-	if cluster.Status != gcpcomputev1alpha1.ClusterStateRunning {
-		return resultRequeue, nil
-	}
+// Update does nothing, because GKE clusters are never out of date.
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	return core.ExternalUpdate{}, nil
+}
 
-	// create connection secret
-	secret, err := r.connectionSecret(instance, cluster)
-	if err != nil {
-		return r.fail(instance, errorSyncCluster, err.Error())
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(*gcpcomputev1alpha1.GKECluster)
+	if !ok {
+		return errors.New(errNotGKECluster)
 	}
 
-	// save secret
-	if _, err := util.ApplySecret(r.kubeclient, secret); err != nil {
-		return r.fail(instance, errorSyncCluster, err.Error())
+	if i.Status.State == gcpcomputev1alpha1.ClusterStateStopping {
+		return nil
 	}
 
-	// update resource status
-	instance.Status.Endpoint = cluster.Endpoint
-	instance.Status.State = gcpcomputev1alpha1.ClusterStateRunning
-	instance.Status.UnsetAllConditions()
-	instance.Status.SetReady()
-
-	// TODO: figure out how we going to handle cluster statuses other than RUNNING
-	return result, r.Update(ctx, instance)
-
+	return errors.Wrapf(e.client.DeleteCluster(i.Spec.Zone, i.Status.ClusterName), "cannot delete GKE cluster %s", i.Status.ClusterName)
 }
 
-// _delete check reclaim policy and if needed delete the gke cluster resource
-func (r *Reconciler) _delete(instance *gcpcomputev1alpha1.GKECluster, client gke.Client) (reconcile.Result, error) {
-	if instance.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		if err := client.DeleteCluster(instance.Spec.Zone, instance.Status.ClusterName); err != nil {
-			return r.fail(instance, errorDeleteCluster, err.Error())
-		}
+// connectionDetails returns the details required to connect to the supplied
+// GKE cluster.
+func connectionDetails(cluster *container.Cluster) (core.ConnectionDetails, error) {
+	cd := core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(cluster.Endpoint),
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(cluster.MasterAuth.Username),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(cluster.MasterAuth.Password),
 	}
-	util.RemoveFinalizer(&instance.ObjectMeta, finalizer)
-	instance.Status.UnsetAllConditions()
-	return result, r.Update(ctx, instance)
-}
 
-// Reconcile reads that state of the cluster for a Provider object and makes changes based on the state read
-// and what is in the Provider.Spec
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", gcpcomputev1alpha1.GKEClusterKindAPIVersion, request)
-	// Fetch the Provider instance
-	instance := &gcpcomputev1alpha1.GKECluster{}
-	err := r.Get(ctx, request.NamespacedName, instance)
+	val, err := base64.StdEncoding.DecodeString(cluster.MasterAuth.ClusterCaCertificate)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		return reconcile.Result{}, err
+		return nil, err
 	}
+	cd[corev1alpha1.ResourceCredentialsSecretCAKey] = val
 
-	// Create GKE Client
-	gkeClient, err := r.connect(instance)
+	val, err = base64.StdEncoding.DecodeString(cluster.MasterAuth.ClientCertificate)
 	if err != nil {
-		return r.fail(instance, errorClusterClient, err.Error())
-	}
-
-	// Check for deletion
-	if instance.DeletionTimestamp != nil {
-		return r.delete(instance, gkeClient)
-	}
-
-	// Add finalizer
-	if !util.HasFinalizer(&instance.ObjectMeta, finalizer) {
-		util.AddFinalizer(&instance.ObjectMeta, finalizer)
-		if err := r.Update(ctx, instance); err != nil {
-			return resultRequeue, err
-		}
+		return nil, err
 	}
+	cd[corev1alpha1.ResourceCredentialsSecretClientCertKey] = val
 
-	// Create cluster instance
-	if instance.Status.ClusterName == "" {
-		return r.create(instance, gkeClient)
+	val, err = base64.StdEncoding.DecodeString(cluster.MasterAuth.ClientKey)
+	if err != nil {
+		return nil, err
 	}
+	cd[corev1alpha1.ResourceCredentialsSecretClientKeyKey] = val
 
-	// Sync cluster instance status with cluster status
-	return r.sync(instance, gkeClient)
+	return cd, nil
 }
@@ -17,27 +17,30 @@ limitations under the License.
 package compute
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/container/v1"
+	"google.golang.org/api/googleapi"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	. "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
-	. "k8s.io/client-go/testing"
 	. "sigs.k8s.io/controller-runtime/pkg/client/fake"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp"
 	. "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp/fake"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp/gke"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -47,13 +50,7 @@ const (
 )
 
 var (
-	key = types.NamespacedName{
-		Namespace: namespace,
-		Name:      clusterName,
-	}
-	request = reconcile.Request{
-		NamespacedName: key,
-	}
+	ctx = context.Background()
 
 	masterAuth = &container.MasterAuth{
 		Username:             "test-user",
@@ -73,6 +70,7 @@ func testCluster() *GKECluster {
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      clusterName,
 			Namespace: namespace,
+			UID:       types.UID("test-uid"),
 		},
 		Spec: GKEClusterSpec{
 			ProviderRef: corev1.LocalObjectReference{
@@ -82,388 +80,173 @@ func testCluster() *GKECluster {
 	}
 }
 
-// assertResource a helper function to check on cluster and its status
-func assertResource(g *GomegaWithT, r *Reconciler, s corev1alpha1.ConditionedStatus) *GKECluster {
-	rc := &GKECluster{}
-	err := r.Get(ctx, key, rc)
-	g.Expect(err).To(BeNil())
-	g.Expect(rc.Status.ConditionedStatus).Should(corev1alpha1.MatchConditionedStatus(s))
-	return rc
-}
-
-func TestSyncClusterGetError(t *testing.T) {
+func TestConnect(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tc := testCluster()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
+	p := &gcpv1alpha1.Provider{ObjectMeta: metav1.ObjectMeta{Name: providerName, Namespace: namespace}}
+	c := &connecter{
+		kube:       NewFakeClient(p),
 		kubeclient: NewSimpleClientset(),
+		newClient:  func(*google.Credentials) (gke.Client, error) { return fake.NewGKEClient(), nil },
 	}
 
-	called := false
-	testError := "test-cluster-retriever-error"
+	// provider status is not ready
+	ext, err := c.Connect(ctx, testCluster())
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(MatchError(fmt.Sprintf("provider %s/%s is not ready", namespace, providerName)))
 
-	cl := fake.NewGKEClient()
-	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
-		called = true
-		return nil, fmt.Errorf(testError)
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncCluster, testError)
-
-	rs, err := r._sync(tc, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	// provider is not found
+	c.kube = NewFakeClient()
+	ext, err = c.Connect(ctx, testCluster())
+	g.Expect(ext).To(BeNil())
+	g.Expect(err).To(HaveOccurred())
 }
 
-func TestSyncClusterNotReady(t *testing.T) {
+func TestObserve(t *testing.T) {
 	g := NewGomegaWithT(t)
 
-	tc := testCluster()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-	}
-
-	called := false
-
+	// cluster has not been created
 	cl := fake.NewGKEClient()
-	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
-		called = true
-		return &container.Cluster{
-			Status: ClusterStateProvisioning,
-		}, nil
-	}
-
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-
-	rs, err := r._sync(tc, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	e := &external{client: cl}
+	o, err := e.Observe(ctx, testCluster())
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-}
-
-func TestSyncApplySecretError(t *testing.T) {
-	g := NewGomegaWithT(t)
+	g.Expect(o.ResourceExists).To(BeFalse())
 
+	// cluster does not exist
 	tc := testCluster()
-
-	testError := "test-error-create-secret"
-	kc := NewSimpleClientset()
-	kc.PrependReactor("create", "secrets", func(Action) (handled bool, ret runtime.Object, err error) {
-		return true, nil, fmt.Errorf(testError)
-	})
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: kc,
+	tc.Status.ClusterName = clusterName
+	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
+		return nil, &googleapi.Error{Code: 404}
 	}
+	o, err = e.Observe(ctx, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceExists).To(BeFalse())
 
-	called := false
-
-	auth := masterAuth
-	endpoint := "test-ep"
-
-	cl := fake.NewGKEClient()
+	// cluster cannot be retrieved
+	testError := "test-cluster-retriever-error"
 	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
-		called = true
-		return &container.Cluster{
-			Status:     ClusterStateRunning,
-			Endpoint:   endpoint,
-			MasterAuth: auth,
-		}, nil
+		return nil, fmt.Errorf(testError)
 	}
+	_, err = e.Observe(ctx, tc)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot get GKE cluster %s: %s", clusterName, testError)))
 
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorSyncCluster, testError)
+	// cluster is in error state
+	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
+		return &container.Cluster{Status: ClusterStateError}, nil
+	}
+	_, err = e.Observe(ctx, tc)
+	g.Expect(requeue.IsTerminal(err)).To(BeTrue())
 
-	rs, err := r._sync(tc, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
+	// cluster is being provisioned
+	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
+		return &container.Cluster{Status: ClusterStateProvisioning}, nil
+	}
+	o, err = e.Observe(ctx, tc)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-}
-
-func TestSync(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	tc := testCluster()
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(o.ResourceReady).To(BeFalse())
+	g.Expect(tc.State()).To(Equal(ClusterStateProvisioning))
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
+	// cluster is running
+	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
+		return &container.Cluster{Status: ClusterStateRunning, Endpoint: "test-ep", MasterAuth: masterAuth, SelfLink: "test-link"}, nil
 	}
-
-	called := false
-
-	auth := masterAuth
-	endpoint := "test-ep"
-
-	cl := fake.NewGKEClient()
+	o, err = e.Observe(ctx, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(o.ResourceReady).To(BeTrue())
+	g.Expect(o.ConnectionDetails).To(Equal(core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretEndpointKey:   []byte("test-ep"),
+		corev1alpha1.ResourceCredentialsSecretUserKey:       []byte("test-user"),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey:   []byte("test-pass"),
+		corev1alpha1.ResourceCredentialsSecretCAKey:         []byte("test-ca"),
+		corev1alpha1.ResourceCredentialsSecretClientCertKey: []byte("test-cert"),
+		corev1alpha1.ResourceCredentialsSecretClientKeyKey:  []byte("test-key"),
+	}))
+	g.Expect(tc.State()).To(Equal(ClusterStateRunning))
+	g.Expect(tc.Status.Endpoint).To(Equal("test-ep"))
+	g.Expect(tc.StatusSummary().ProviderID).To(Equal("test-link"))
+
+	// cluster connection details are invalid
 	cl.MockGetCluster = func(string, string) (*container.Cluster, error) {
-		called = true
-		return &container.Cluster{
-			Status:     ClusterStateRunning,
-			Endpoint:   endpoint,
-			MasterAuth: auth,
-		}, nil
+		return &container.Cluster{Status: ClusterStateRunning, MasterAuth: &container.MasterAuth{ClusterCaCertificate: "%"}}, nil
 	}
+	_, err = e.Observe(ctx, tc)
+	g.Expect(err).To(HaveOccurred())
 
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-
-	rs, err := r._sync(tc, cl)
-	g.Expect(rs).To(Equal(result))
+	// existing cluster is adopted
+	adopted := testCluster()
+	adopted.SetAnnotations(map[string]string{corev1alpha1.AnnotationExternalName: "existing-cluster"})
+	cl.MockGetCluster = func(_, name string) (*container.Cluster, error) {
+		if name != "existing-cluster" {
+			return nil, &googleapi.Error{Code: 404}
+		}
+		return &container.Cluster{Status: ClusterStateProvisioning}, nil
+	}
+	o, err = e.Observe(ctx, adopted)
 	g.Expect(err).NotTo(HaveOccurred())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	g.Expect(o.ResourceExists).To(BeTrue())
+	g.Expect(adopted.Status.ClusterName).To(Equal("existing-cluster"))
 }
 
-func TestDeleteReclaimDelete(t *testing.T) {
+func TestCreate(t *testing.T) {
 	g := NewGomegaWithT(t)
 
+	// cluster is created
+	cl := fake.NewGKEClient()
+	cl.MockCreateCluster = func(string, GKEClusterSpec) (*container.Cluster, error) { return nil, nil }
+	e := &external{client: cl}
 	tc := testCluster()
-	tc.Finalizers = []string{finalizer}
-	tc.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	tc.Status.SetReady()
+	_, err := e.Create(ctx, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.ClusterName).To(Equal(clusterNamePrefix + "test-uid"))
+	g.Expect(tc.State()).To(Equal(ClusterStateProvisioning))
 
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
+	// cluster was created by an earlier reconcile
+	cl.MockCreateCluster = func(string, GKEClusterSpec) (*container.Cluster, error) {
+		return nil, &googleapi.Error{Code: 409}
 	}
+	tc = testCluster()
+	_, err = e.Create(ctx, tc)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tc.Status.ClusterName).To(Equal(clusterNamePrefix + "test-uid"))
 
-	called := false
-	cl := fake.NewGKEClient()
-	cl.MockDeleteCluster = func(string, string) error {
-		called = true
-		return nil
+	// cluster cannot be created
+	testError := "test-create-error"
+	cl.MockCreateCluster = func(string, GKEClusterSpec) (*container.Cluster, error) {
+		return nil, fmt.Errorf(testError)
 	}
-
-	// expected to have a cond condition set to inactive
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.UnsetAllConditions()
-
-	rs, err := r._delete(tc, cl)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
-
-	// repeat the same for cluster in 'failing' condition
-	reason := "test-reason"
-	msg := "test-msg"
-	tc.Status.SetFailed(reason, msg)
-
-	// expected to have both ready and fail condition inactive
-	expectedStatus.SetFailed(reason, msg)
-	expectedStatus.UnsetAllConditions()
-
-	rs, err = r._delete(tc, cl)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, expectedStatus)
+	tc = testCluster()
+	_, err = e.Create(ctx, tc)
+	g.Expect(err).To(MatchError(fmt.Sprintf("cannot create GKE cluster %s%s: %s", clusterNamePrefix, "test-uid", testError)))
+	g.Expect(tc.Status.ClusterName).To(BeEmpty())
 }
 
-func TestDeleteReclaimRetain(t *testing.T) {
+func TestDelete(t *testing.T) {
 	g := NewGomegaWithT(t)
 
 	tc := testCluster()
-	tc.Spec.ReclaimPolicy = corev1alpha1.ReclaimRetain
-	tc.Finalizers = []string{finalizer}
-	tc.Status.SetReady()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-	}
+	tc.Status.ClusterName = clusterName
 
+	// cluster is deleted
 	called := false
 	cl := fake.NewGKEClient()
 	cl.MockDeleteCluster = func(string, string) error {
 		called = true
 		return nil
 	}
+	e := &external{client: cl}
+	g.Expect(e.Delete(ctx, tc)).To(Succeed())
+	g.Expect(called).To(BeTrue())
 
-	rs, err := r._delete(tc, cl)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
-	// there should be no delete calls on gke client since policy is set to Retain
+	// cluster is already being deleted
+	called = false
+	tc.Status.State = ClusterStateStopping
+	g.Expect(e.Delete(ctx, tc)).To(Succeed())
 	g.Expect(called).To(BeFalse())
 
-	// expected to have all conditions set to inactive
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.UnsetAllConditions()
-
-	assertResource(g, r, expectedStatus)
-}
-
-func TestDeleteFailed(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	tc := testCluster()
-	tc.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
-	tc.Finalizers = []string{finalizer}
-	tc.Status.SetReady()
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-	}
-
+	// cluster cannot be deleted
+	tc.Status.State = ClusterStateRunning
 	testError := "test-delete-error"
-
-	called := false
-	cl := fake.NewGKEClient()
-	cl.MockDeleteCluster = func(string, string) error {
-		called = true
-		return fmt.Errorf(testError)
-	}
-
-	rs, err := r._delete(tc, cl)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	// there should be no delete calls on gke client since policy is set to Retain
-	g.Expect(called).To(BeTrue())
-
-	// expected status
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetReady()
-	expectedStatus.UnsetAllConditions()
-	expectedStatus.SetFailed(errorDeleteCluster, testError)
-
-	assertResource(g, r, expectedStatus)
-}
-
-func TestReconcileObjectNotFound(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	r := &Reconciler{
-		Client: NewFakeClient(),
-	}
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
-}
-
-func TestReconcileClientError(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	testError := "test-client-error"
-
-	called := false
-
-	r := &Reconciler{
-		Client:     NewFakeClient(testCluster()),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*GKECluster) (gke.Client, error) {
-			called = true
-			return nil, fmt.Errorf(testError)
-		},
-	}
-
-	// expected to have a failed condition
-	expectedStatus := corev1alpha1.ConditionedStatus{}
-	expectedStatus.SetFailed(errorClusterClient, testError)
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-
-	assertResource(g, r, expectedStatus)
-}
-
-func TestReconcileDelete(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	// test objects
-	tc := testCluster()
-	dt := metav1.Now()
-	tc.DeletionTimestamp = &dt
-
-	called := false
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*GKECluster) (gke.Client, error) {
-			return nil, nil
-		},
-		delete: func(*GKECluster, gke.Client) (reconcile.Result, error) {
-			called = true
-			return result, nil
-		},
-	}
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(result))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-	assertResource(g, r, corev1alpha1.ConditionedStatus{})
-}
-
-func TestReconcileCreate(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	called := false
-
-	r := &Reconciler{
-		Client:     NewFakeClient(testCluster()),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*GKECluster) (gke.Client, error) {
-			return nil, nil
-		},
-		create: func(*GKECluster, gke.Client) (reconcile.Result, error) {
-			called = true
-			return resultRequeue, nil
-		},
-	}
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-
-	rc := assertResource(g, r, corev1alpha1.ConditionedStatus{})
-	g.Expect(rc.Finalizers).To(HaveLen(1))
-	g.Expect(rc.Finalizers).To(ContainElement(finalizer))
-}
-
-func TestReconcileSync(t *testing.T) {
-	g := NewGomegaWithT(t)
-
-	called := false
-
-	tc := testCluster()
-	tc.Status.ClusterName = "test-status- cluster-name"
-	tc.Finalizers = []string{finalizer}
-
-	r := &Reconciler{
-		Client:     NewFakeClient(tc),
-		kubeclient: NewSimpleClientset(),
-		connect: func(*GKECluster) (gke.Client, error) {
-			return nil, nil
-		},
-		sync: func(*GKECluster, gke.Client) (reconcile.Result, error) {
-			called = true
-			return resultRequeue, nil
-		},
-	}
-
-	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
-	g.Expect(err).To(BeNil())
-	g.Expect(called).To(BeTrue())
-
-	rc := assertResource(g, r, corev1alpha1.ConditionedStatus{})
-	g.Expect(rc.Finalizers).To(HaveLen(1))
-	g.Expect(rc.Finalizers).To(ContainElement(finalizer))
+	cl.MockDeleteCluster = func(string, string) error { return fmt.Errorf(testError) }
+	g.Expect(e.Delete(ctx, tc)).To(MatchError(fmt.Sprintf("cannot delete GKE cluster %s: %s", clusterName, testError)))
 }
@@ -18,49 +18,33 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
-	"time"
 
+	"github.com/pkg/errors"
 	sqladmin "google.golang.org/api/sqladmin/v1beta4"
-	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	apitypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	databasev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
 	gcpclients "github.com/crossplaneio/crossplane/pkg/clients/gcp"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/util"
 )
 
 const (
-	finalizer                 = "finalizer.cloudsqlinstances.database.gcp.crossplane.io"
+	controllerName            = "cloudsqlinstances.database.gcp.crossplane.io"
 	mysqlDefaultUserName      = "root"
 	postgresqlDefaultUserName = "postgres"
 	passwordDataLen           = 20
 
-	errorSettingInstanceName    = "failed to set instance name"
-	errorFetchingGCPProvider    = "failed to fetch GCP Provider"
-	errorFetchingCloudSQLClient = "failed to fetch CloudSQL client"
-	errorFetchingInstance       = "failed to fetch instance"
-	errorCreatingInstance       = "failed to create instance"
-	errorDeletingInstance       = "failed to delete instance"
-	errorInitDefaultUser        = "failed to initialize default user"
-	conditionStateChanged       = "instance state changed"
-)
-
-var (
-	_ reconcile.Reconciler = &Reconciler{}
+	errNotCloudsqlInstance = "managed resource is not a CloudSQL instance"
 )
 
 // Add creates a new CloudsqlInstance Controller and adds it to the Manager with default RBAC.
@@ -68,190 +52,170 @@ var (
 func Add(mgr manager.Manager) error {
 	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
 	if err != nil {
-		return fmt.Errorf("failed to create clientset: %+v", err)
+		return errors.Wrap(err, "cannot create clientset")
 	}
 
-	r := newCloudsqlInstanceReconciler(mgr, &gcpclients.CloudSQLClientFactory{}, clientset, NewReconcilerOptions())
-	return addCloudsqlInstanceReconciler(mgr, r)
+	return addCloudsqlInstance(mgr, &gcpclients.CloudSQLClientFactory{}, clientset)
 }
 
-// newCloudsqlInstanceReconciler returns a new reconcile.Reconciler
-func newCloudsqlInstanceReconciler(mgr manager.Manager, cloudSQLAPIFactory gcpclients.CloudSQLAPIFactory,
-	clientset kubernetes.Interface, options ReconcilerOptions) *Reconciler {
+// addCloudsqlInstance adds a CloudsqlInstance controller that uses the
+// supplied factory to create CloudSQL clients.
+func addCloudsqlInstance(mgr manager.Manager, cloudSQLAPIFactory gcpclients.CloudSQLAPIFactory, clientset kubernetes.Interface) error {
+	c := &connecter{kube: mgr.GetClient(), clientset: clientset, cloudSQLAPIFactory: cloudSQLAPIFactory}
+	return core.AddManaged(mgr, controllerName, func() core.Managed { return &databasev1alpha1.CloudsqlInstance{} }, c,
+		gcpclients.ClassifyError, corev1alpha1.ReclaimDelete, corev1alpha1.ReclaimSnapshot)
+}
 
-	return &Reconciler{
-		Client:             mgr.GetClient(),
-		cloudSQLAPIFactory: cloudSQLAPIFactory,
-		clientset:          clientset,
-		options:            options,
-	}
+// connecter returns CloudSQL clients authenticated using the credentials of
+// the GCP Provider referenced by a CloudsqlInstance.
+type connecter struct {
+	kube               client.Client
+	clientset          kubernetes.Interface
+	cloudSQLAPIFactory gcpclients.CloudSQLAPIFactory
 }
 
-// addCloudsqlInstanceReconciler adds a new Controller to mgr with r as the reconcile.Reconciler
-func addCloudsqlInstanceReconciler(mgr manager.Manager, r reconcile.Reconciler) error {
-	// Create a new controller
-	c, err := controller.New("CloudsqlInstance-controller", mgr, controller.Options{Reconciler: r})
-	if err != nil {
-		return err
+func (c *connecter) Connect(ctx context.Context, mg core.Managed) (core.ExternalClient, error) {
+	i, ok := mg.(*databasev1alpha1.CloudsqlInstance)
+	if !ok {
+		return nil, errors.New(errNotCloudsqlInstance)
 	}
 
-	// Watch for changes to CloudsqlInstance
-	err = c.Watch(&source.Kind{Type: &databasev1alpha1.CloudsqlInstance{}}, &handler.EnqueueRequestForObject{})
-	if err != nil {
-		return err
+	p := &gcpv1alpha1.Provider{}
+	n := types.NamespacedName{Namespace: i.GetNamespace(), Name: i.Spec.ProviderRef.Name}
+	if err := c.kube.Get(ctx, n, p); err != nil {
+		return nil, errors.Wrapf(err, "cannot get provider %s", n)
 	}
 
-	return nil
-}
+	if !p.IsValid() {
+		return nil, errors.Errorf("provider %s is not ready", n)
+	}
 
-// Reconciler reconciles a CloudsqlInstance object
-type Reconciler struct {
-	client.Client
-	clientset          kubernetes.Interface
-	cloudSQLAPIFactory gcpclients.CloudSQLAPIFactory
-	options            ReconcilerOptions
-}
+	// create a Cloud SQL client for each reconcile since each instance can have different creds
+	sc, err := c.cloudSQLAPIFactory.CreateAPIInstance(c.clientset, p.Namespace, p.Spec.Secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot create CloudSQL client")
+	}
 
-// ReconcilerOptions represent options to configure the CloudSQL reconciler
-type ReconcilerOptions struct {
-	WaitSleepTime time.Duration
+	return &external{kube: c.kube, client: sc, project: p.Spec.ProjectID}, nil
 }
 
-// NewReconcilerOptions creates a new ReconcilerOptions object with the default options
-func NewReconcilerOptions() ReconcilerOptions {
-	return ReconcilerOptions{
-		WaitSleepTime: 10 * time.Second,
-	}
+// external manages CloudSQL instances and their default users using the
+// CloudSQL API.
+type external struct {
+	kube    client.Client
+	client  gcpclients.CloudSQLAPI
+	project string
 }
 
-// Reconcile reads that state of the cluster for a CloudsqlInstance object and makes changes based on the state read
-// and what is in the CloudsqlInstance.Spec
-func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) { // nolint:gocyclo
-	// TODO(negz): This method's cyclomatic complexity is very high. Consider
-	// refactoring it if you touch it.
-
-	log.Printf("reconciling %s: %v", databasev1alpha1.CloudsqlInstanceKindAPIVersion, request)
-	instance := &databasev1alpha1.CloudsqlInstance{}
-	var cloudSQLInstance *sqladmin.DatabaseInstance
+func (e *external) Observe(ctx context.Context, mg core.Managed) (core.ExternalObservation, error) {
+	i, ok := mg.(*databasev1alpha1.CloudsqlInstance)
+	if !ok {
+		return core.ExternalObservation{}, errors.New(errNotCloudsqlInstance)
+	}
 
-	// Fetch the CloudsqlInstance instance
-	err := r.Get(context.TODO(), request.NamespacedName, instance)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			// Object not found, return.  Created objects are automatically garbage collected.
-			// For additional cleanup logic use finalizers.
-			return reconcile.Result{}, nil
-		}
-		// Error reading the object - requeue the request.
-		log.Printf("failed to get object at start of reconcile loop: %+v", err)
-		return reconcile.Result{}, err
+	// Adopt the existing instance named by the managed resource, if any.
+	if i.Status.InstanceName == "" {
+		i.Status.InstanceName = corev1alpha1.ExternalName(i)
 	}
 
-	if instance.Status.InstanceName == "" {
-		// we haven't generated a unique instance name yet, let's do that now
-		instance.Status.InstanceName = "cloudsql-" + string(instance.UID)
-		log.Printf("cloud sql instance %s does not yet have an instance name, setting it to %s", instance.Name, instance.Status.InstanceName)
-		if err := r.Update(context.TODO(), instance); err != nil {
-			return r.fail(instance, errorSettingInstanceName, err.Error())
-		}
+	// The instance is unnamed. Assume it has not been created in GCP.
+	if i.Status.InstanceName == "" {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
 
-	// look up the provider information for this instance
-	provider := &gcpv1alpha1.Provider{}
-	providerNamespacedName := apitypes.NamespacedName{
-		Namespace: instance.Namespace,
-		Name:      instance.Spec.ProviderRef.Name,
+	cloudSQLInstance, err := e.client.GetInstance(ctx, e.project, i.Status.InstanceName)
+	if gcpclients.IsErrorNotFound(err) {
+		return core.ExternalObservation{ResourceExists: false}, nil
 	}
-	if err = r.Get(context.TODO(), providerNamespacedName, provider); err != nil {
-		return r.fail(instance, errorFetchingGCPProvider, fmt.Sprintf("failed to get provider %+v: %+v", providerNamespacedName, err))
+	if err != nil {
+		return core.ExternalObservation{}, errors.Wrapf(err, "cannot get CloudSQL instance %s", i.Status.InstanceName)
 	}
 
-	// create a Cloud SQL client during each reconciliation loop since each instance can have different creds
-	cloudSQLClient, err := r.cloudSQLAPIFactory.CreateAPIInstance(r.clientset, provider.Namespace, provider.Spec.Secret)
-	if err != nil {
-		return r.fail(instance, errorFetchingCloudSQLClient, fmt.Sprintf("failed to get cloud sql client: %+v", err))
+	i.Status.Message = gcpclients.CloudSQLStatusMessage(i.Name, cloudSQLInstance)
+	i.Status.State = cloudSQLInstance.State
+	i.Status.ProviderID = cloudSQLInstance.SelfLink
+	if len(cloudSQLInstance.IpAddresses) > 0 {
+		i.Status.Endpoint = cloudSQLInstance.IpAddresses[0].IpAddress
 	}
 
-	// check for CRD deletion and handle it if needed
-	if instance.DeletionTimestamp != nil {
-		if instance.Status.Condition(corev1alpha1.Deleting) == nil {
-			// we haven't started the deletion of the CloudSQL resource yet, do it now
-			log.Printf("cloud sql instance %s has been deleted, running finalizer now", instance.Name)
-			return r.handleDeletion(cloudSQLClient, instance, provider)
-		}
-		// we already started the deletion of the CloudSQL resource, nothing more to do
-		return reconcile.Result{}, nil
+	// Retrying will not fix a failed instance, but it can still be deleted.
+	if i.Status.State == databasev1alpha1.StateFailed && i.GetDeletionTimestamp() == nil {
+		return core.ExternalObservation{}, requeue.Terminal(errors.New(i.Status.Message))
 	}
 
-	// Add finalizer to the CRD if it doesn't already exist
-	if !util.HasFinalizer(&instance.ObjectMeta, finalizer) {
-		util.AddFinalizer(&instance.ObjectMeta, finalizer)
-		if err := r.Update(context.TODO(), instance); err != nil {
-			log.Printf("failed to add finalizer to instance %s: %+v", instance.Name, err)
-			return reconcile.Result{}, err
-		}
+	if i.Status.State != databasev1alpha1.StateRunnable {
+		return core.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
 	}
 
-	// retrieve the CloudSQL instance from GCP to get the latest status
-	cloudSQLInstance, err = cloudSQLClient.GetInstance(provider.Spec.ProjectID, instance.Status.InstanceName)
+	user, err := getDefaultDBUserName(i.Spec.DatabaseVersion)
 	if err != nil {
-		if !gcpclients.IsErrorNotFound(err) {
-			return r.fail(instance, errorFetchingInstance, fmt.Sprintf("failed to get cloud sql instance %s: %+v", instance.Name, err))
-		}
-
-		// seems like we didn't find a cloud sql instance with this name, let's create one
-		return r.handleCreation(cloudSQLClient, instance, provider)
+		return core.ExternalObservation{}, err
 	}
 
-	stateChanged := instance.Status.State != cloudSQLInstance.State
-	conditionType := gcpclients.CloudSQLConditionType(cloudSQLInstance.State)
-
-	// cloud sql instance exists, update the CRD status now with its latest status
-	if err := r.updateStatus(instance, gcpclients.CloudSQLStatusMessage(instance.Name, cloudSQLInstance), cloudSQLInstance); err != nil {
-		// updating the CRD status failed, return the error and try the next reconcile loop
-		log.Printf("failed to update status of instance %s: %+v", instance.Name, err)
-		return reconcile.Result{}, err
+	o := core.ExternalObservation{
+		ResourceExists:   true,
+		ResourceReady:    true,
+		ResourceUpToDate: true,
+		ConnectionDetails: core.ConnectionDetails{
+			corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(i.Status.Endpoint),
+			corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(user),
+		},
 	}
 
-	if stateChanged {
-		// the state of the instance has changed, let's set a corresponding condition on the CRD and then
-		// requeue another reconiliation attempt
-		if conditionType == corev1alpha1.Ready {
-			// when we hit the running condition, clear out all old conditions first
-			instance.Status.UnsetAllConditions()
+	// The settings of the instance are not compared to its spec until an
+	// earlier patch is complete.
+	if i.Status.Operation != "" {
+		op, err := e.client.GetOperation(ctx, e.project, i.Status.Operation)
+		if err != nil {
+			return core.ExternalObservation{}, errors.Wrapf(err, "cannot get CloudSQL operation %s", i.Status.Operation)
 		}
-
-		conditionMessage := fmt.Sprintf("cloud sql instance %s is in the %s state", instance.Name, conditionType)
-		log.Print(conditionMessage)
-		instance.Status.SetCondition(corev1alpha1.NewCondition(conditionType, conditionStateChanged, conditionMessage))
-		return reconcile.Result{Requeue: true}, r.Update(context.TODO(), instance)
+		if !gcpclients.IsOperationComplete(op) {
+			o.ResourceUpdating = true
+			return o, nil
+		}
+		// Patching the instance again would fail the same way, so a failed
+		// operation is kept until the spec of the instance changes.
+		if !gcpclients.IsOperationSuccessful(op) && i.Status.OperationGeneration == i.GetGeneration() && i.GetDeletionTimestamp() == nil {
+			return core.ExternalObservation{}, requeue.Terminal(errors.Errorf("cannot patch CloudSQL instance %s: %s", i.Status.InstanceName, gcpclients.OperationErrorMessage(op)))
+		}
+		i.Status.Operation = ""
+		i.Status.OperationGeneration = 0
 	}
 
-	if conditionType != corev1alpha1.Ready {
-		// the instance isn't running still, requeue another reconciliation attempt
-		return reconcile.Result{Requeue: true}, nil
+	// The default user's password is reset and stored in the connection
+	// secret once the instance is running.
+	initialized, err := core.ConnectionDetailPublished(ctx, e.kube, i, corev1alpha1.ResourceCredentialsSecretPasswordKey)
+	if err != nil {
+		return core.ExternalObservation{}, err
 	}
 
-	// ensure the default user is initialized
-	if err = r.initDefaultUser(cloudSQLClient, instance, provider); err != nil {
-		return r.fail(instance, errorInitDefaultUser, fmt.Sprintf("failed to init default user for cloud sql instance %s: %+v", instance.Name, err))
-	}
+	o.ResourceUpToDate = initialized && !gcpclients.CloudSQLInstanceNeedsUpdate(&i.Spec, cloudSQLInstance)
 
-	return reconcile.Result{}, nil
+	switch {
+	case !initialized:
+		o.Diff = fmt.Sprintf("password of the default user of CloudSQL instance %s would be reset", i.Status.InstanceName)
+	case !o.ResourceUpToDate:
+		j, _ := json.Marshal(gcpclients.CloudSQLInstancePatch(&i.Spec, cloudSQLInstance))
+		o.Diff = string(j)
+	}
+	return o, nil
 }
 
-// handleCreation performs the operation to create the given CloudSQL instance in GCP
-func (r *Reconciler) handleCreation(cloudSQLClient gcpclients.CloudSQLAPI,
-	instance *databasev1alpha1.CloudsqlInstance, provider *gcpv1alpha1.Provider) (reconcile.Result, error) {
+func (e *external) Create(ctx context.Context, mg core.Managed) (core.ExternalCreation, error) {
+	i, ok := mg.(*databasev1alpha1.CloudsqlInstance)
+	if !ok {
+		return core.ExternalCreation{}, errors.New(errNotCloudsqlInstance)
+	}
 
+	name := "cloudsql-" + string(i.GetUID())
 	cloudSQLInstance := &sqladmin.DatabaseInstance{
-		Name:            instance.Status.InstanceName,
-		Region:          instance.Spec.Region,
-		DatabaseVersion: instance.Spec.DatabaseVersion,
+		Name:            name,
+		Region:          i.Spec.Region,
+		DatabaseVersion: i.Spec.DatabaseVersion,
 		Settings: &sqladmin.Settings{
-			Tier:           instance.Spec.Tier,
-			DataDiskType:   instance.Spec.StorageType,
-			DataDiskSizeGb: instance.Spec.StorageGB,
+			Tier:           i.Spec.Tier,
+			DataDiskType:   i.Spec.StorageType,
+			DataDiskSizeGb: i.Spec.StorageGB,
 			IpConfiguration: &sqladmin.IpConfiguration{
 				AuthorizedNetworks: []*sqladmin.AclEntry{
 					// TODO: we need to come up with better AuthorizedNetworks handing, for now a short cut - open to all
@@ -261,189 +225,135 @@ func (r *Reconciler) handleCreation(cloudSQLClient gcpclients.CloudSQLAPI,
 		},
 	}
 
-	log.Printf("cloud sql instance %s not found, will try to create it now: %+v", instance.Name, cloudSQLInstance)
-	createOp, err := cloudSQLClient.CreateInstance(provider.Spec.ProjectID, cloudSQLInstance)
-	if err != nil {
-		return r.fail(instance, errorCreatingInstance, fmt.Sprintf("failed to start create operation for cloud sql instance %s: %+v", instance.Name, err))
+	if _, err := e.client.CreateInstance(ctx, e.project, cloudSQLInstance); err != nil && !gcpclients.IsErrorAlreadyExists(err) {
+		return core.ExternalCreation{}, errors.Wrapf(err, "cannot create CloudSQL instance %s", name)
 	}
 
-	log.Printf("started create of cloud sql instance %s, operation %s %s", instance.Name, createOp.Name, createOp.Status)
-	return reconcile.Result{Requeue: true}, nil
+	i.Status.InstanceName = name
+	return core.ExternalCreation{}, nil
 }
 
-// handleDeletion performs the operation to delete the given CloudSQL instance in GCP
-func (r *Reconciler) handleDeletion(cloudSQLClient gcpclients.CloudSQLAPI,
-	instance *databasev1alpha1.CloudsqlInstance, provider *gcpv1alpha1.Provider) (reconcile.Result, error) {
+// Update initializes the default user of the supplied instance by resetting
+// its password. Once the default user is initialized, Update patches the
+// settings of the instance that differ from its spec.
+func (e *external) Update(ctx context.Context, mg core.Managed) (core.ExternalUpdate, error) {
+	i, ok := mg.(*databasev1alpha1.CloudsqlInstance)
+	if !ok {
+		return core.ExternalUpdate{}, errors.New(errNotCloudsqlInstance)
+	}
 
-	// first get the latest status of the CloudSQL resource that needs to be deleted
-	_, err := cloudSQLClient.GetInstance(provider.Spec.ProjectID, instance.Status.InstanceName)
+	initialized, err := core.ConnectionDetailPublished(ctx, e.kube, i, corev1alpha1.ResourceCredentialsSecretPasswordKey)
 	if err != nil {
-		if !gcpclients.IsErrorNotFound(err) {
-			return r.fail(instance, errorFetchingInstance, fmt.Sprintf("failed to get cloud sql instance %s for deletion: %+v", instance.Name, err))
-		}
-
-		// CloudSQL instance doesn't exist, it's already deleted
-		return r.markAsDeleting(instance)
+		return core.ExternalUpdate{}, err
 	}
-
-	if instance.Spec.ReclaimPolicy == corev1alpha1.ReclaimDelete {
-		// attempt to delete the CloudSQL instance now
-		deleteOp, err := cloudSQLClient.DeleteInstance(provider.Spec.ProjectID, instance.Status.InstanceName)
-		if err != nil {
-			return r.fail(instance, errorDeletingInstance, fmt.Sprintf("failed to start delete operation for cloud sql instance %s: %+v", instance.Name, err))
-		}
-
-		log.Printf("started deletion of cloud sql instance %s, operation %s %s", instance.Name, deleteOp.Name, deleteOp.Status)
+	if !initialized {
+		return e.initializeDefaultUser(ctx, i)
 	}
-	return r.markAsDeleting(instance)
-}
-
-func (r *Reconciler) markAsDeleting(instance *databasev1alpha1.CloudsqlInstance) (reconcile.Result, error) {
-	instance.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Deleting, "", ""))
-	util.RemoveFinalizer(&instance.ObjectMeta, finalizer)
-	return reconcile.Result{}, r.Update(context.TODO(), instance)
-}
 
-// TODO(negz): This method's cyclomatic complexity is very high. Consider
-// refactoring it if you touch it.
-// nolint:gocyclo
-func (r *Reconciler) initDefaultUser(cloudSQLClient gcpclients.CloudSQLAPI,
-	instance *databasev1alpha1.CloudsqlInstance, provider *gcpv1alpha1.Provider) error {
-
-	// get the default database user name depending on the database version in use
-	defaultUserName, err := getDefaultDBUserName(instance.Spec.DatabaseVersion)
+	cloudSQLInstance, err := e.client.GetInstance(ctx, e.project, i.Status.InstanceName)
 	if err != nil {
-		return err
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot get CloudSQL instance %s", i.Status.InstanceName)
 	}
 
-	// first ensure the connection secret name has been set
-	secretName, err := r.ensureConnectionSecretNameSet(instance)
+	// CloudSQL runs one operation on an instance at a time, so the operation
+	// is tracked until it is complete.
+	op, err := e.client.PatchInstance(ctx, e.project, i.Status.InstanceName, gcpclients.CloudSQLInstancePatch(&i.Spec, cloudSQLInstance))
 	if err != nil {
-		return err
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot patch CloudSQL instance %s", i.Status.InstanceName)
 	}
 
-	// check if the default user has already been initialized
-	if _, err := r.clientset.CoreV1().Secrets(instance.Namespace).Get(secretName, metav1.GetOptions{}); err == nil {
-		// we already have a password for the default user, we are done
-		return nil
+	i.Status.Operation = op.Name
+	i.Status.OperationGeneration = i.GetGeneration()
+	return core.ExternalUpdate{}, nil
+}
+
+// initializeDefaultUser resets the password of the default user of the
+// supplied instance.
+func (e *external) initializeDefaultUser(ctx context.Context, i *databasev1alpha1.CloudsqlInstance) (core.ExternalUpdate, error) {
+	name, err := getDefaultDBUserName(i.Spec.DatabaseVersion)
+	if err != nil {
+		return core.ExternalUpdate{}, err
 	}
-	log.Printf("user '%s' is not initialized yet: %+v", defaultUserName, err)
 
-	users, err := cloudSQLClient.ListUsers(provider.Spec.ProjectID, instance.Status.InstanceName)
+	users, err := e.client.ListUsers(ctx, e.project, i.Status.InstanceName)
 	if err != nil {
-		return fmt.Errorf("failed to list users: %+v", err)
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot list users of CloudSQL instance %s", i.Status.InstanceName)
 	}
 
-	var defaultUser *sqladmin.User
-	for i := range users.Items {
-		if users.Items[i].Name == defaultUserName {
-			defaultUser = users.Items[i]
+	var user *sqladmin.User
+	for _, u := range users.Items {
+		if u.Name == name {
+			user = u
 			break
 		}
 	}
-
-	if defaultUser == nil {
-		return fmt.Errorf("failed to find user '%s'", defaultUserName)
+	if user == nil {
+		return core.ExternalUpdate{}, errors.Errorf("cannot find user %s of CloudSQL instance %s", name, i.Status.InstanceName)
 	}
 
 	password, err := util.GeneratePassword(passwordDataLen)
 	if err != nil {
-		return fmt.Errorf("failed to generate password for user '%s': %+v", defaultUser.Name, err)
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot generate password for user %s", name)
 	}
-	defaultUser.Password = password
+	user.Password = password
 
-	// update the user via Cloud SQL API
-	log.Printf("updating user '%s'", defaultUser.Name)
-	updateUserOp, err := cloudSQLClient.UpdateUser(provider.Spec.ProjectID, instance.Status.InstanceName, defaultUser.Name, defaultUser)
-	if err != nil {
-		return fmt.Errorf("failed to start update user operation for user '%s': %+v", defaultUser.Name, err)
+	// The password is published as soon as the update is accepted. A failed
+	// update operation leaves the user with its previous password.
+	if _, err := e.client.UpdateUser(ctx, e.project, i.Status.InstanceName, name, user); err != nil {
+		return core.ExternalUpdate{}, errors.Wrapf(err, "cannot update user %s of CloudSQL instance %s", name, i.Status.InstanceName)
 	}
 
-	// wait for the update user operation to complete
-	log.Printf("waiting for update user operation %s to complete for user '%s'", updateUserOp.Name, defaultUser.Name)
-	updateUserOp, err = gcpclients.WaitUntilOperationCompletes(updateUserOp.Name, provider, cloudSQLClient, r.options.WaitSleepTime)
-	if err != nil {
-		return fmt.Errorf("failed to wait until update user operation %s completed for user '%s': %+v", updateUserOp.Name, defaultUser.Name, err)
-	}
+	return core.ExternalUpdate{ConnectionDetails: core.ConnectionDetails{
+		corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(name),
+		corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
+	}}, nil
+}
 
-	log.Printf("update user operation for user '%s' completed. status: %s, errors: %+v", defaultUser.Name, updateUserOp.Status, updateUserOp.Error)
-	if !gcpclients.IsOperationSuccessful(updateUserOp) {
-		// the operation completed, but it failed
-		m := fmt.Sprintf("update user operation for user '%s' failed: %+v", defaultUser.Name, updateUserOp)
-		if updateUserOp.Error != nil && len(updateUserOp.Error.Errors) > 0 {
-			m = fmt.Sprintf("%s. errors: %+v", m, updateUserOp.Error.Errors)
-		}
-		return fmt.Errorf(m)
+func (e *external) Delete(ctx context.Context, mg core.Managed) error {
+	i, ok := mg.(*databasev1alpha1.CloudsqlInstance)
+	if !ok {
+		return errors.New(errNotCloudsqlInstance)
 	}
 
-	// save the user and connection info to a secret
-	connectionSecret := &v1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            secretName,
-			Namespace:       instance.Namespace,
-			OwnerReferences: []metav1.OwnerReference{instance.OwnerReference()},
-		},
-		Data: map[string][]byte{
-			corev1alpha1.ResourceCredentialsSecretEndpointKey: []byte(instance.Status.Endpoint),
-			corev1alpha1.ResourceCredentialsSecretUserKey:     []byte(defaultUser.Name),
-			corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte(password),
-		},
-	}
-	log.Printf("creating connection secret %s for user '%s'", connectionSecret.Name, defaultUser.Name)
-	if _, err := r.clientset.CoreV1().Secrets(instance.Namespace).Create(connectionSecret); err != nil {
-		return fmt.Errorf("failed to update connection secret %s: %+v", connectionSecret.Name, err)
+	// we already started the deletion of the instance, nothing more to do
+	if i.Status.State == databasev1alpha1.StatePendingDelete {
+		return nil
 	}
 
-	log.Printf("user '%s' initialized", defaultUser.Name)
-	return nil
-}
-
-// fail - helper function to set fail condition with reason and message
-func (r *Reconciler) fail(instance *databasev1alpha1.CloudsqlInstance, reason, msg string) (reconcile.Result, error) {
-	log.Printf("instance %s failed: '%s' %s", instance.Name, reason, msg)
-	instance.Status.SetCondition(corev1alpha1.NewCondition(corev1alpha1.Failed, reason, msg))
-	return reconcile.Result{Requeue: true}, r.Update(context.TODO(), instance)
-}
-
-func (r *Reconciler) updateStatus(instance *databasev1alpha1.CloudsqlInstance, message string,
-	cloudSQLInstance *sqladmin.DatabaseInstance) error {
-
-	var state, providerID, endpoint string
-	if cloudSQLInstance != nil {
-		state = cloudSQLInstance.State
-		providerID = cloudSQLInstance.SelfLink
-		if len(cloudSQLInstance.IpAddresses) > 0 {
-			endpoint = cloudSQLInstance.IpAddresses[0].IpAddress
+	if i.Spec.ReclaimPolicy == corev1alpha1.ReclaimSnapshot && i.Status.FinalSnapshot == "" {
+		done, err := e.finalSnapshot(ctx, i)
+		if err != nil || !done {
+			return err
 		}
 	}
 
-	instance.Status.Message = message
-	instance.Status.State = state
-	instance.Status.ProviderID = providerID
-	instance.Status.Endpoint = endpoint
-
-	if err := r.Update(context.TODO(), instance); err != nil {
-		return fmt.Errorf("failed to update status of CRD instance %s: %+v", instance.Name, err)
+	if _, err := e.client.DeleteInstance(ctx, e.project, i.Status.InstanceName); err != nil && !gcpclients.IsErrorNotFound(err) {
+		return errors.Wrapf(err, "cannot delete CloudSQL instance %s", i.Status.InstanceName)
 	}
-
 	return nil
 }
 
-func (r *Reconciler) ensureConnectionSecretNameSet(instance *databasev1alpha1.CloudsqlInstance) (string, error) {
-	// if the secret name doesn't already exist, we'll need to update the instance with it
-	updateNeeded := instance.Spec.ConnectionSecretRef.Name == ""
-
-	// get or create the connection secret name
-	secretName := instance.ConnectionSecretName()
+// finalSnapshot clones the supplied instance, returning true once the clone is
+// runnable. CloudSQL backups do not outlive their instance, so the final
+// snapshot is a clone that must be runnable before the source instance is
+// deleted.
+func (e *external) finalSnapshot(ctx context.Context, i *databasev1alpha1.CloudsqlInstance) (bool, error) {
+	snapshot := corev1alpha1.FinalSnapshotName(i.Status.InstanceName)
+	clone, err := e.client.GetInstance(ctx, e.project, snapshot)
+	if gcpclients.IsErrorNotFound(err) {
+		_, err := e.client.CloneInstance(ctx, e.project, i.Status.InstanceName, snapshot)
+		return false, errors.Wrapf(err, "cannot clone CloudSQL instance %s", i.Status.InstanceName)
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "cannot get final snapshot %s of CloudSQL instance %s", snapshot, i.Status.InstanceName)
+	}
 
-	// if an update on the instance was needed, do it now
-	if updateNeeded {
-		if err := r.Update(context.TODO(), instance); err != nil {
-			return "", fmt.Errorf("failed to set connection secret ref: %+v", err)
-		}
+	if clone.State != databasev1alpha1.StateRunnable {
+		return false, nil
 	}
 
-	return secretName, nil
+	i.Status.FinalSnapshot = snapshot
+	return true, nil
 }
 
 func getDefaultDBUserName(dbVersion string) (string, error) {
@@ -453,5 +363,5 @@ func getDefaultDBUserName(dbVersion string) (string, error) {
 		return mysqlDefaultUserName, nil
 	}
 
-	return "", fmt.Errorf("database version does not match any known types: %s", dbVersion)
+	return "", errors.Errorf("database version does not match any known types: %s", dbVersion)
 }
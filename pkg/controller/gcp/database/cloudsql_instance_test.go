@@ -17,11 +17,12 @@ limitations under the License.
 package database
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"sync/atomic"
 	"testing"
-	"time"
 
 	"github.com/onsi/gomega"
 	"google.golang.org/api/googleapi"
@@ -29,14 +30,16 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	kubefake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
-	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	dbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -46,46 +49,43 @@ func TestReconcile(t *testing.T) {
 	clientset := fake.NewSimpleClientset()
 	cloudSQLClient := &mockCloudSQLClient{}
 	cloudSQLClientFactory := &mockCloudSQLClientFactory{mockClient: cloudSQLClient}
-	options := ReconcilerOptions{
-		WaitSleepTime: 1 * time.Millisecond,
-	}
 
 	// Mock the GetInstance function with functionality that simulates creating a CloudSQL instance and
-	// the creation operation taking a while to complete before the instance is runnable.
-	getInstanceCallCount := 0
-	getInstanceCallCountBeforeRunning := 5
-	cloudSQLClient.MockGetInstance = func(project string, instance string) (*sqladmin.DatabaseInstance, error) {
-		getInstanceCallCount++
-		if getInstanceCallCount <= 1 {
-			// first GET should return not found, which will cause the reconcile loop to try to create the instance
+	// the creation operation taking a while to complete before the instance is runnable. The instance
+	// is not found until it is created and once it is deleted.
+	var created, deleted, pending int32
+	getInstanceCallCountBeforeRunning := int32(3)
+	cloudSQLClient.MockGetInstance = func(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error) {
+		if atomic.LoadInt32(&created) == 0 || atomic.LoadInt32(&deleted) == 1 {
 			return nil, &googleapi.Error{Code: http.StatusNotFound}
-		} else if getInstanceCallCount >= 2 && getInstanceCallCount <= getInstanceCallCountBeforeRunning {
-			// for a few GET calls, return PENDING_CREATE, simulating that the instance is in the process of
-			// being created.  This will exercise the requeueing of the reconciliation loop.
+		}
+		if atomic.AddInt32(&pending, 1) <= getInstanceCallCountBeforeRunning {
 			return createMockDatabaseInstance(project, instance, dbv1alpha1.StatePendingCreate), nil
 		}
-		// Finally we simulate that the create operation has completed and the instance is RUNNABLE
 		return createMockDatabaseInstance(project, instance, dbv1alpha1.StateRunnable), nil
 	}
-	cloudSQLClient.MockCreateInstance = createInstanceDefault
-	cloudSQLClient.MockDeleteInstance = deleteInstanceDefault
+	cloudSQLClient.MockCreateInstance = func(ctx context.Context, project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
+		atomic.StoreInt32(&created, 1)
+		return &sqladmin.Operation{}, nil
+	}
+	cloudSQLClient.MockDeleteInstance = func(ctx context.Context, project string, instance string) (*sqladmin.Operation, error) {
+		atomic.StoreInt32(&deleted, 1)
+		return &sqladmin.Operation{}, nil
+	}
 	cloudSQLClient.MockListUsers = listUsersDefault
 	cloudSQLClient.MockUpdateUser = updateUserDefault
-	cloudSQLClient.MockGetOperation = getOperationDefault
 
-	// Setup the Manager and Controller.  Wrap the Controller Reconcile function so it writes each request to a
-	// channel when it is finished.
+	// Setup the Manager and Controller.
 	mgr, err := manager.New(cfg, manager.Options{})
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	c := mgr.GetClient()
 
-	r := newCloudsqlInstanceReconciler(mgr, cloudSQLClientFactory, clientset, options)
-	recFn, requests := SetupTestReconcile(r)
-	g.Expect(addCloudsqlInstanceReconciler(mgr, recFn)).NotTo(gomega.HaveOccurred())
+	g.Expect(addCloudsqlInstance(mgr, cloudSQLClientFactory, clientset)).NotTo(gomega.HaveOccurred())
 	defer close(StartTestManager(mgr, g))
 
 	// create the provider object and defer its cleanup
 	provider := testProvider(testSecret([]byte("testdata")))
+	provider.Status.SetReady()
 	err = c.Create(ctx, provider)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 	defer c.Delete(ctx, provider)
@@ -94,61 +94,233 @@ func TestReconcile(t *testing.T) {
 	instance := testInstance(provider)
 	err = c.Create(ctx, instance)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
-	defer cleanupCloudsqlInstance(g, c, requests, instance)
-
-	// wait on the number of reconciliation requests that are caused by the instance status being PENDING_CREATE for a few GET calls
-	expectedReconciliationCalls := getInstanceCallCountBeforeRunning + 1
-	for i := 1; i <= expectedReconciliationCalls; i++ {
-		g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-	}
+	defer cleanupCloudsqlInstance(g, c, instance)
 
 	// verify that the CRD status was updated with details about the external CloudSQL instance and that the
 	// CRD conditions show the transition from creating to running
-	expectedInstanceName := fmt.Sprintf("cloudsql-%s", instance.UID)
+	expectedInstanceName := fmt.Sprintf("cloudsql-%s", cloudsqlInstance(c).UID)
 	expectedStatus := dbv1alpha1.CloudsqlInstanceStatus{
 		Message:      "Cloud SQL instance test-db-instance is running",
 		State:        dbv1alpha1.StateRunnable,
-		ProviderID:   fmt.Sprintf("https://www.googleapis.com/sql/v1beta4/projects/%s/instances/cloudsql-%s", providerProject, instance.UID),
+		ProviderID:   fmt.Sprintf("https://www.googleapis.com/sql/v1beta4/projects/%s/instances/%s", providerProject, expectedInstanceName),
 		Endpoint:     "10.0.0.1",
 		InstanceName: expectedInstanceName,
 		ConditionedStatus: corev1alpha1.ConditionedStatus{
 			Conditions: []corev1alpha1.Condition{
 				{
-					Type:    corev1alpha1.Creating,
-					Status:  v1.ConditionFalse,
-					Reason:  conditionStateChanged,
-					Message: "cloud sql instance test-db-instance is in the Creating state",
+					Type:   corev1alpha1.Creating,
+					Status: v1.ConditionFalse,
 				},
 				{
-					Type:    corev1alpha1.Ready,
-					Status:  v1.ConditionTrue,
-					Reason:  conditionStateChanged,
-					Message: "cloud sql instance test-db-instance is in the Ready state",
+					Type:   corev1alpha1.Ready,
+					Status: v1.ConditionTrue,
 				},
 			},
 		},
 	}
+	g.Eventually(func() bool { return cloudsqlInstance(c).Status.IsReady() }, timeout).Should(gomega.BeTrue())
 	assertCloudsqlInstanceStatus(g, c, expectedStatus)
 
-	// wait for the connection information to be stored in a secret, then verify it
-	var connectionSecret *v1.Secret
-	for {
-		if connectionSecret, err = r.clientset.CoreV1().Secrets(namespace).Get(instanceName, metav1.GetOptions{}); err == nil {
-			break
-		}
-	}
+	// wait for the default user's password to be stored in the connection secret, then verify it
+	connectionSecret := &v1.Secret{}
+	g.Eventually(func() []byte {
+		c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: instanceName}, connectionSecret)
+		return connectionSecret.Data[corev1alpha1.ResourceCredentialsSecretPasswordKey]
+	}, timeout).ShouldNot(gomega.BeEmpty())
 	assertConnectionSecret(g, c, connectionSecret)
 
 	// verify that a finalizer was added to the CRD
-	c.Get(ctx, expectedRequest.NamespacedName, instance)
-	g.Expect(len(instance.Finalizers)).To(gomega.Equal(1))
-	g.Expect(instance.Finalizers[0]).To(gomega.Equal(finalizer))
+	g.Expect(cloudsqlInstance(c).Finalizers).To(gomega.Equal([]string{"finalizer." + controllerName}))
 
 	// test deletion of the instance
-	cleanupCloudsqlInstance(g, c, requests, instance)
+	cleanupCloudsqlInstance(g, c, instance)
+	g.Expect(atomic.LoadInt32(&deleted)).To(gomega.Equal(int32(1)))
+}
+
+func TestPatchSettings(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tier := "db-n1-standard-2"
+	settings := &sqladmin.Settings{Tier: "db-n1-standard-1", SettingsVersion: 3}
+	operation := &sqladmin.Operation{Name: "patch-op", Status: "RUNNING"}
+	var patch *sqladmin.DatabaseInstance
+	cloudSQLClient := &mockCloudSQLClient{
+		MockGetInstance: func(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error) {
+			i := createMockDatabaseInstance(project, instance, dbv1alpha1.StateRunnable)
+			i.Settings = settings
+			return i, nil
+		},
+		MockPatchInstance: func(ctx context.Context, project string, instance string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
+			patch = databaseinstance
+			return operation, nil
+		},
+		MockGetOperation: func(ctx context.Context, project string, operationID string) (*sqladmin.Operation, error) {
+			g.Expect(operationID).To(gomega.Equal(operation.Name))
+			return operation, nil
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: instanceName},
+		Data:       map[string][]byte{corev1alpha1.ResourceCredentialsSecretPasswordKey: []byte("test-password")},
+	}
+	e := &external{kube: kubefake.NewFakeClient(secret), client: cloudSQLClient, project: providerProject}
+
+	instance := testInstance(testProvider(testSecret([]byte("testdata"))))
+	instance.Spec.Tier = tier
+	instance.Status.InstanceName = "cloudsql-test"
+
+	// the tier of the instance differs from its spec
+	o, err := e.Observe(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(gomega.BeFalse())
+
+	// the tier of the instance is patched
+	_, err = e.Update(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(patch).To(gomega.Equal(&sqladmin.DatabaseInstance{Settings: &sqladmin.Settings{Tier: tier, SettingsVersion: 3}}))
+	g.Expect(instance.Status.Operation).To(gomega.Equal(operation.Name))
+
+	// the patch is in progress
+	o, err = e.Observe(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(o.ResourceReady).To(gomega.BeTrue())
+	g.Expect(o.ResourceUpToDate).To(gomega.BeTrue())
+	g.Expect(o.ResourceUpdating).To(gomega.BeTrue())
+
+	// the patch failed
+	operation = &sqladmin.Operation{Name: "patch-op", Status: "DONE", EndTime: "now",
+		Error: &sqladmin.OperationErrors{Errors: []*sqladmin.OperationError{{Message: "test-patch-error"}}}}
+	_, err = e.Observe(ctx, instance)
+	g.Expect(err).To(gomega.MatchError("cannot patch CloudSQL instance cloudsql-test: test-patch-error"))
+	g.Expect(requeue.IsTerminal(err)).To(gomega.BeTrue())
+	g.Expect(instance.Status.Operation).To(gomega.Equal(operation.Name))
+
+	// the failed patch is retried once the spec of the instance changes
+	instance.Generation++
+	o, err = e.Observe(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(gomega.BeFalse())
+	g.Expect(instance.Status.Operation).To(gomega.BeEmpty())
+
+	// the patch is complete
+	_, err = e.Update(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(instance.Status.OperationGeneration).To(gomega.Equal(instance.Generation))
+	operation = &sqladmin.Operation{Name: "patch-op", Status: "DONE", EndTime: "now"}
+	settings = &sqladmin.Settings{Tier: tier, SettingsVersion: 4}
+	o, err = e.Observe(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(o.ResourceUpToDate).To(gomega.BeTrue())
+	g.Expect(o.ResourceUpdating).To(gomega.BeFalse())
+	g.Expect(instance.Status.Operation).To(gomega.BeEmpty())
+
+	// the instance is not patched until its default user is initialized
+	patch = nil
+	e.kube = kubefake.NewFakeClient()
+	settings = &sqladmin.Settings{Tier: "db-n1-standard-1", SettingsVersion: 4}
+	cloudSQLClient.MockListUsers = listUsersDefault
+	cloudSQLClient.MockUpdateUser = updateUserDefault
+	u, err := e.Update(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(u.ConnectionDetails).To(gomega.HaveKey(corev1alpha1.ResourceCredentialsSecretPasswordKey))
+	g.Expect(patch).To(gomega.BeNil())
 }
 
-func cleanupCloudsqlInstance(g *gomega.GomegaWithT, c client.Client, requests chan reconcile.Request, instance *dbv1alpha1.CloudsqlInstance) {
+type testContextKey struct{}
+
+func TestDeleteFinalSnapshot(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// the final snapshot is taken with the context of the deletion
+	deleteCtx := context.WithValue(ctx, testContextKey{}, "delete")
+
+	snapshot := corev1alpha1.FinalSnapshotName("cloudsql-test")
+	var cloned, deleted bool
+	cloneState := dbv1alpha1.StatePendingCreate
+	cloudSQLClient := &mockCloudSQLClient{
+		MockGetInstance: func(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error) {
+			g.Expect(ctx.Value(testContextKey{})).To(gomega.Equal("delete"))
+			g.Expect(instance).To(gomega.Equal(snapshot))
+			if !cloned {
+				return nil, &googleapi.Error{Code: http.StatusNotFound}
+			}
+			return createMockDatabaseInstance(project, instance, cloneState), nil
+		},
+		MockCloneInstance: func(ctx context.Context, project string, instance string, destination string) (*sqladmin.Operation, error) {
+			g.Expect(ctx.Value(testContextKey{})).To(gomega.Equal("delete"))
+			g.Expect(instance).To(gomega.Equal("cloudsql-test"))
+			g.Expect(destination).To(gomega.Equal(snapshot))
+			cloned = true
+			return &sqladmin.Operation{}, nil
+		},
+		MockDeleteInstance: func(ctx context.Context, project string, instance string) (*sqladmin.Operation, error) {
+			deleted = true
+			return &sqladmin.Operation{}, nil
+		},
+	}
+	e := &external{client: cloudSQLClient, project: providerProject}
+
+	instance := testInstance(testProvider(testSecret([]byte("testdata"))))
+	instance.Spec.ReclaimPolicy = corev1alpha1.ReclaimSnapshot
+	instance.Status.InstanceName = "cloudsql-test"
+
+	// the final snapshot is started
+	g.Expect(e.Delete(deleteCtx, instance)).To(gomega.Succeed())
+	g.Expect(cloned).To(gomega.BeTrue())
+	g.Expect(deleted).To(gomega.BeFalse())
+
+	// the final snapshot is not yet runnable
+	g.Expect(e.Delete(deleteCtx, instance)).To(gomega.Succeed())
+	g.Expect(instance.Status.FinalSnapshot).To(gomega.BeEmpty())
+	g.Expect(deleted).To(gomega.BeFalse())
+
+	// the final snapshot is runnable, and the instance is deleted
+	cloneState = dbv1alpha1.StateRunnable
+	g.Expect(e.Delete(deleteCtx, instance)).To(gomega.Succeed())
+	g.Expect(instance.Status.FinalSnapshot).To(gomega.Equal(snapshot))
+	g.Expect(deleted).To(gomega.BeTrue())
+
+	// an instance that is already being deleted is left alone
+	deleted = false
+	instance.Status.State = dbv1alpha1.StatePendingDelete
+	g.Expect(e.Delete(deleteCtx, instance)).To(gomega.Succeed())
+	g.Expect(deleted).To(gomega.BeFalse())
+}
+
+func TestObserveAdopt(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cloudSQLClient := &mockCloudSQLClient{
+		MockGetInstance: func(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error) {
+			if instance != "existing-instance" {
+				return nil, &googleapi.Error{Code: http.StatusNotFound}
+			}
+			return createMockDatabaseInstance(project, instance, dbv1alpha1.StateRunnable), nil
+		},
+	}
+	e := &external{kube: kubefake.NewFakeClient(), client: cloudSQLClient, project: providerProject}
+
+	instance := testInstance(testProvider(testSecret([]byte("testdata"))))
+	instance.SetAnnotations(map[string]string{corev1alpha1.AnnotationExternalName: "existing-instance"})
+
+	// the existing instance is adopted, and its default user's password must
+	// be reset because it was never published
+	o, err := e.Observe(ctx, instance)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(o.ResourceExists).To(gomega.BeTrue())
+	g.Expect(o.ResourceReady).To(gomega.BeTrue())
+	g.Expect(o.ResourceUpToDate).To(gomega.BeFalse())
+	g.Expect(instance.Status.InstanceName).To(gomega.Equal("existing-instance"))
+	g.Expect(instance.Status.ProviderID).To(gomega.Equal(fmt.Sprintf("https://www.googleapis.com/sql/v1beta4/projects/%s/instances/existing-instance", providerProject)))
+}
+
+func cloudsqlInstance(c client.Client) *dbv1alpha1.CloudsqlInstance {
+	instance := &dbv1alpha1.CloudsqlInstance{}
+	c.Get(ctx, expectedRequest.NamespacedName, instance)
+	return instance
+}
+
+func cleanupCloudsqlInstance(g *gomega.GomegaWithT, c client.Client, instance *dbv1alpha1.CloudsqlInstance) {
 	deletedInstance := &dbv1alpha1.CloudsqlInstance{}
 	if err := c.Get(ctx, expectedRequest.NamespacedName, deletedInstance); errors.IsNotFound(err) {
 		// instance has already been deleted, bail out
@@ -159,20 +331,6 @@ func cleanupCloudsqlInstance(g *gomega.GomegaWithT, c client.Client, requests ch
 	err := c.Delete(ctx, instance)
 	g.Expect(err).NotTo(gomega.HaveOccurred())
 
-	// wait for the deletion timestamp to be set
-	err = wait.ExponentialBackoff(test.DefaultRetry, func() (done bool, err error) {
-		deletedInstance := &dbv1alpha1.CloudsqlInstance{}
-		c.Get(ctx, expectedRequest.NamespacedName, deletedInstance)
-		if deletedInstance.DeletionTimestamp != nil {
-			return true, nil
-		}
-		return false, nil
-	})
-	g.Expect(err).NotTo(gomega.HaveOccurred())
-
-	// wait for the reconcile to happen that handles the CRD deletion
-	g.Eventually(requests, timeout).Should(gomega.Receive(gomega.Equal(expectedRequest)))
-
 	// wait for the finalizer to run and the instance to be deleted for good
 	err = wait.ExponentialBackoff(test.DefaultRetry, func() (done bool, err error) {
 		deletedInstance := &dbv1alpha1.CloudsqlInstance{}
@@ -17,8 +17,8 @@ limitations under the License.
 package database
 
 import (
+	"context"
 	"fmt"
-	"time"
 
 	sqladmin "google.golang.org/api/sqladmin/v1beta4"
 	v1 "k8s.io/api/core/v1"
@@ -30,18 +30,20 @@ import (
 // mockCloudSQLClient provides a mock implementation of the CloudSQLAPI interface for unit testing purposes
 type mockCloudSQLClient struct {
 	gcpclients.CloudSQLAPI
-	MockGetInstance    func(project string, instance string) (*sqladmin.DatabaseInstance, error)
-	MockCreateInstance func(project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error)
-	MockDeleteInstance func(project string, instance string) (*sqladmin.Operation, error)
-	MockListUsers      func(project string, instance string) (*sqladmin.UsersListResponse, error)
-	MockUpdateUser     func(project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error)
-	MockGetOperation   func(project string, operationID string) (*sqladmin.Operation, error)
+	MockGetInstance    func(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error)
+	MockCreateInstance func(ctx context.Context, project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error)
+	MockPatchInstance  func(ctx context.Context, project string, instance string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error)
+	MockDeleteInstance func(ctx context.Context, project string, instance string) (*sqladmin.Operation, error)
+	MockCloneInstance  func(ctx context.Context, project string, instance string, destination string) (*sqladmin.Operation, error)
+	MockListUsers      func(ctx context.Context, project string, instance string) (*sqladmin.UsersListResponse, error)
+	MockUpdateUser     func(ctx context.Context, project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error)
+	MockGetOperation   func(ctx context.Context, project string, operationID string) (*sqladmin.Operation, error)
 }
 
 // GetInstance retrieves details for the requested CloudSQL instance
-func (m *mockCloudSQLClient) GetInstance(project string, instance string) (*sqladmin.DatabaseInstance, error) {
+func (m *mockCloudSQLClient) GetInstance(ctx context.Context, project string, instance string) (*sqladmin.DatabaseInstance, error) {
 	if m.MockGetInstance != nil {
-		return m.MockGetInstance(project, instance)
+		return m.MockGetInstance(ctx, project, instance)
 	}
 
 	// default implementation
@@ -49,61 +51,63 @@ func (m *mockCloudSQLClient) GetInstance(project string, instance string) (*sqla
 }
 
 // CreateInstance creates the given CloudSQL instance
-func (m *mockCloudSQLClient) CreateInstance(project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
+func (m *mockCloudSQLClient) CreateInstance(ctx context.Context, project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
 	if m.MockCreateInstance != nil {
-		return m.MockCreateInstance(project, databaseinstance)
+		return m.MockCreateInstance(ctx, project, databaseinstance)
 	}
 	return nil, nil
 }
 
-func (m *mockCloudSQLClient) DeleteInstance(project string, instance string) (*sqladmin.Operation, error) {
-	if m.MockDeleteInstance != nil {
-		return m.MockDeleteInstance(project, instance)
+func (m *mockCloudSQLClient) PatchInstance(ctx context.Context, project string, instance string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
+	if m.MockPatchInstance != nil {
+		return m.MockPatchInstance(ctx, project, instance, databaseinstance)
 	}
 	return nil, nil
 }
 
-func (m *mockCloudSQLClient) ListUsers(project string, instance string) (*sqladmin.UsersListResponse, error) {
-	if m.MockListUsers != nil {
-		return m.MockListUsers(project, instance)
+func (m *mockCloudSQLClient) DeleteInstance(ctx context.Context, project string, instance string) (*sqladmin.Operation, error) {
+	if m.MockDeleteInstance != nil {
+		return m.MockDeleteInstance(ctx, project, instance)
 	}
 	return nil, nil
 }
 
-func (m *mockCloudSQLClient) UpdateUser(project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error) {
-	if m.MockUpdateUser != nil {
-		return m.MockUpdateUser(project, instance, name, user)
+func (m *mockCloudSQLClient) CloneInstance(ctx context.Context, project string, instance string, destination string) (*sqladmin.Operation, error) {
+	if m.MockCloneInstance != nil {
+		return m.MockCloneInstance(ctx, project, instance, destination)
 	}
 	return nil, nil
 }
 
-func (m *mockCloudSQLClient) GetOperation(project string, operationID string) (*sqladmin.Operation, error) {
-	if m.MockGetOperation != nil {
-		return m.MockGetOperation(project, operationID)
+func (m *mockCloudSQLClient) ListUsers(ctx context.Context, project string, instance string) (*sqladmin.UsersListResponse, error) {
+	if m.MockListUsers != nil {
+		return m.MockListUsers(ctx, project, instance)
 	}
 	return nil, nil
 }
 
-func createInstanceDefault(project string, databaseinstance *sqladmin.DatabaseInstance) (*sqladmin.Operation, error) {
-	return &sqladmin.Operation{}, nil
+func (m *mockCloudSQLClient) UpdateUser(ctx context.Context, project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error) {
+	if m.MockUpdateUser != nil {
+		return m.MockUpdateUser(ctx, project, instance, name, user)
+	}
+	return nil, nil
 }
 
-func deleteInstanceDefault(project string, instance string) (*sqladmin.Operation, error) {
-	return &sqladmin.Operation{}, nil
+func (m *mockCloudSQLClient) GetOperation(ctx context.Context, project string, operationID string) (*sqladmin.Operation, error) {
+	if m.MockGetOperation != nil {
+		return m.MockGetOperation(ctx, project, operationID)
+	}
+	return nil, nil
 }
 
-func listUsersDefault(project string, instance string) (*sqladmin.UsersListResponse, error) {
+func listUsersDefault(ctx context.Context, project string, instance string) (*sqladmin.UsersListResponse, error) {
 	return &sqladmin.UsersListResponse{Items: []*sqladmin.User{{Name: "root"}}}, nil
 }
 
-func updateUserDefault(project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error) {
+func updateUserDefault(ctx context.Context, project string, instance string, name string, user *sqladmin.User) (*sqladmin.Operation, error) {
 	return &sqladmin.Operation{Name: "updateuser-op-123", Status: "RUNNING"}, nil
 }
 
-func getOperationDefault(project string, operationID string) (*sqladmin.Operation, error) {
-	return &sqladmin.Operation{Name: operationID, Status: "DONE", EndTime: time.Now().String()}, nil
-}
-
 // CreateMockDatabaseInstance creates a simple test instance of a CloudSQL database instance object
 func createMockDatabaseInstance(project, instance, state string) *sqladmin.DatabaseInstance {
 	return &sqladmin.DatabaseInstance{
@@ -30,9 +30,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp"
 	databasev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -55,24 +57,15 @@ var (
 func TestMain(m *testing.M) {
 	gcp.AddToScheme(scheme.Scheme)
 
+	// poll resources that are being created or deleted without delay
+	requeue.Defaults.PollInterval = 100 * time.Millisecond
+
 	t := test.NewEnv(namespace, test.CRDs())
 	cfg = t.Start()
 	t.StopAndExit(m.Run())
 }
 
-// SetupTestReconcile returns a reconcile.Reconcile implementation that delegates to inner and
-// writes the request to requests after Reconcile is finished.
-func SetupTestReconcile(inner reconcile.Reconciler) (reconcile.Reconciler, chan reconcile.Request) {
-	requests := make(chan reconcile.Request)
-	fn := reconcile.Func(func(req reconcile.Request) (reconcile.Result, error) {
-		result, err := inner.Reconcile(req)
-		requests <- req
-		return result, err
-	})
-	return fn, requests
-}
-
-// StartTestManager adds recFn
+// StartTestManager starts the supplied manager
 func StartTestManager(mgr manager.Manager, g *gomega.GomegaWithT) chan struct{} {
 	stop := make(chan struct{})
 	go func() {
@@ -115,6 +108,7 @@ func testInstance(p *gcpv1alpha1.Provider) *databasev1alpha1.CloudsqlInstance {
 		Spec: databasev1alpha1.CloudsqlInstanceSpec{
 			ProviderRef:     corev1.LocalObjectReference{Name: p.Name},
 			DatabaseVersion: "MYSQL_5_6",
+			ReclaimPolicy:   corev1alpha1.ReclaimDelete,
 		},
 	}
 }
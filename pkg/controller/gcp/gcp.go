@@ -23,27 +23,22 @@ import (
 	"github.com/crossplaneio/crossplane/pkg/controller/gcp/compute"
 	"github.com/crossplaneio/crossplane/pkg/controller/gcp/database"
 	"github.com/crossplaneio/crossplane/pkg/controller/gcp/provider"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
-	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
-	AddToManagerFuncs = append(AddToManagerFuncs,
-		provider.Add,
-		database.Add,
-		compute.Add,
-		cache.Add,
+	Controllers = append(Controllers,
+		selection.Controller{Provider: selection.ProviderGCP, Add: provider.Add},
+		selection.Controller{Provider: selection.ProviderGCP, Kind: selection.KindDatabase, Add: database.Add},
+		selection.Controller{Provider: selection.ProviderGCP, Kind: selection.KindCompute, Add: compute.Add},
+		selection.Controller{Provider: selection.ProviderGCP, Kind: selection.KindCache, Add: cache.Add},
 	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
-
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
-			return err
-		}
-	}
-	return nil
+// Controllers are the controllers of GCP resources
+var Controllers []selection.Controller
+
+// AddToManager adds the selected Controllers to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
+	return sel.AddToManager(m, Controllers)
 }
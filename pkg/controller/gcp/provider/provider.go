@@ -18,8 +18,8 @@ package provider
 
 import (
 	"context"
-	"log"
 
+	"github.com/go-logr/logr"
 	"golang.org/x/oauth2/google"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,6 +34,9 @@ import (
 
 	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/clients/gcp"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -43,9 +46,7 @@ const (
 )
 
 var (
-	ctx           = context.Background()
-	result        = reconcile.Result{}
-	resultRequeue = reconcile.Result{Requeue: true}
+	result = reconcile.Result{}
 )
 
 // Add creates a new Provider Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -60,6 +61,8 @@ type Reconciler struct {
 	scheme     *runtime.Scheme
 	kubeclient kubernetes.Interface
 	recorder   record.EventRecorder
+	requeue    *requeue.Policy
+	log        logr.Logger
 
 	validate func(*google.Credentials, []string) error
 }
@@ -71,6 +74,8 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 		scheme:     mgr.GetScheme(),
 		kubeclient: kubernetes.NewForConfigOrDie(mgr.GetConfig()),
 		recorder:   mgr.GetRecorder(controllerName),
+		requeue:    requeue.NewPolicy(controllerName, requeue.Defaults),
+		log:        logging.Log.WithName(controllerName),
 	}
 	r.validate = r._validate
 	return r
@@ -79,7 +84,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -97,7 +102,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 func (r *Reconciler) fail(instance *gcpv1alpha1.Provider, reason, msg string) (reconcile.Result, error) {
 	instance.Status.UnsetAllConditions()
 	instance.Status.SetFailed(reason, msg)
-	return resultRequeue, r.Update(context.TODO(), instance)
+	return r.requeue.Failed(instance, nil), r.Update(context.TODO(), instance)
 }
 
 func (r *Reconciler) _validate(creds *google.Credentials, permissions []string) error {
@@ -114,7 +119,7 @@ func (r *Reconciler) _validate(creds *google.Credentials, permissions []string)
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gcp.crossplane.io,resources=provider,verbs=get;list;watch;create;update;patch;delete
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", gcpv1alpha1.ProviderKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), r.log, request)
 	// Fetch the Provider instance
 	instance := &gcpv1alpha1.Provider{}
 	err := r.Get(ctx, request.NamespacedName, instance)
@@ -122,12 +127,20 @@ func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, err
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			r.requeue.Forget(request.NamespacedName)
 			return result, nil
 		}
 		// Error reading the object - requeue the request.
 		return result, err
 	}
 
+	logging.Reconciling(ctx, instance)
+
+	// Wait for a failed provider to back off before reconciling it again
+	if d := r.requeue.Wait(instance); d > 0 {
+		return reconcile.Result{RequeueAfter: d}, nil
+	}
+
 	creds, err := gcp.ProviderCredentials(r.kubeclient, instance)
 	if err != nil {
 		return r.fail(instance, errorRetrievingSecret, err.Error())
@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -34,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/crossplaneio/crossplane/pkg/apis/gcp"
+	"github.com/crossplaneio/crossplane/pkg/requeue"
 )
 
 const (
@@ -58,6 +60,7 @@ const (
 )
 
 var (
+	ctx = context.Background()
 	key = types.NamespacedName{
 		Namespace: namespace,
 		Name:      providerName,
@@ -65,6 +68,8 @@ var (
 	request = reconcile.Request{
 		NamespacedName: key,
 	}
+
+	resultFailed = reconcile.Result{RequeueAfter: requeue.Defaults.BackoffBase}
 )
 
 func init() {
@@ -122,7 +127,7 @@ func TestReconcileSecretNotFound(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	rp := &Provider{}
@@ -148,7 +153,7 @@ func TestReconcileSecretKeyNotFound(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	rp := &Provider{}
@@ -195,7 +200,7 @@ func TestReconcileValidationFailed(t *testing.T) {
 	}
 
 	rs, err := r.Reconcile(request)
-	g.Expect(rs).To(Equal(resultRequeue))
+	g.Expect(rs).To(Equal(resultFailed))
 	g.Expect(err).To(BeNil())
 
 	// assert provider status
@@ -17,6 +17,7 @@ limitations under the License.
 package bucket
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -29,6 +30,14 @@ import (
 	s3Bucketv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	bucketv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
+)
+
+// Bucket claim fields resource classes may constrain.
+const (
+	NameField            = "name"
+	PredefinedACLField   = "predefinedACL"
+	LocalPermissionField = "localPermission"
 )
 
 var (
@@ -44,12 +53,17 @@ var (
 type S3BucketHandler struct{}
 
 // Find an S3 bucket.
-func (h *S3BucketHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *S3BucketHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	s3Bucket := &s3Bucketv1alpha1.S3Bucket{}
 	err := c.Get(ctx, name, s3Bucket)
 	return s3Bucket, err
 }
 
+// Match an existing, unbound S3 bucket to the supplied claim.
+func (h *S3BucketHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &s3Bucketv1alpha1.S3BucketList{})
+}
+
 // newS3Bucket initialized bucket with resources and object references applied
 func (h *S3BucketHandler) newS3Bucket(class *corev1alpha1.ResourceClass, instance *bucketv1alpha1.Bucket, bucketSpec *s3Bucketv1alpha1.S3BucketSpec) *s3Bucketv1alpha1.S3Bucket {
 	// create and save S3Bucket
@@ -77,7 +91,7 @@ func (h *S3BucketHandler) newS3Bucket(class *corev1alpha1.ResourceClass, instanc
 }
 
 // Provision a new S3Bucket
-func (h *S3BucketHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (h *S3BucketHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	// construct S3Bucket Spec from class definition
 	bucketSpec := s3Bucketv1alpha1.NewS3BucketSpec(class.Parameters)
 
@@ -89,6 +103,10 @@ func (h *S3BucketHandler) Provision(class *corev1alpha1.ResourceClass, claim cor
 	// Making connection secret override configurable from parameters doesn't make sense, so we take the value from the instance.
 	bucketSpec.ConnectionSecretNameOverride = bucket.Spec.ConnectionSecretNameOverride
 
+	if err := applyConstraintPolicy(corecontroller.NewConstraintPolicy(class), bucketSpec, bucket); err != nil {
+		return nil, err
+	}
+
 	val, err := resolveClassInstanceValues(bucketSpec.Name, bucket.Spec.Name)
 	if err != nil {
 		return nil, err
@@ -123,7 +141,7 @@ func (h *S3BucketHandler) Provision(class *corev1alpha1.ResourceClass, claim cor
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (h S3BucketHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (h S3BucketHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	s3Bucket := &s3Bucketv1alpha1.S3Bucket{}
 	err := c.Get(ctx, name, s3Bucket)
 	if err != nil {
@@ -137,9 +155,34 @@ func (h S3BucketHandler) SetBindStatus(name types.NamespacedName, c client.Clien
 	return c.Update(ctx, s3Bucket)
 }
 
+// applyConstraintPolicy checks the bucket values of any fields constrained by
+// the supplied policy. Bucket values that satisfy their constraint override the
+// corresponding resource class values.
+func applyConstraintPolicy(policy *corecontroller.ConstraintPolicy, spec *s3Bucketv1alpha1.S3BucketSpec, bucket *bucketv1alpha1.Bucket) error {
+	if policy.Constrained(NameField) && bucket.Spec.Name != "" {
+		if err := policy.Check(NameField, bucket.Spec.Name); err != nil {
+			return err
+		}
+		spec.Name = ""
+	}
+	if policy.Constrained(PredefinedACLField) && bucket.Spec.PredefinedACL != nil {
+		if err := policy.Check(PredefinedACLField, string(*bucket.Spec.PredefinedACL)); err != nil {
+			return err
+		}
+		spec.CannedACL = nil
+	}
+	if policy.Constrained(LocalPermissionField) && bucket.Spec.LocalPermission != nil {
+		if err := policy.Check(LocalPermissionField, string(*bucket.Spec.LocalPermission)); err != nil {
+			return err
+		}
+		spec.LocalPermission = nil
+	}
+	return nil
+}
+
 // resolveClassInstanceACL validates instance value against resource class properties.
 // if both values are defined, then the instance value is validated against the resource class value and expected to match
-// TODO: the "matching" process will be further refined once we implement constraint policies at the resource class level
+// Values of fields constrained by the resource class are handled by applyConstraintPolicy
 func resolveClassInstanceACL(classValue *s3.BucketCannedACL, instanceValue *s3.BucketCannedACL) (*s3.BucketCannedACL, error) {
 	if classValue == nil {
 		return instanceValue, nil
@@ -155,7 +198,7 @@ func resolveClassInstanceACL(classValue *s3.BucketCannedACL, instanceValue *s3.B
 
 // resolveClassInstanceValues validates instance value against resource class properties.
 // if both values are defined, then the instance value is validated against the resource class value and expected to match
-// TODO: the "matching" process will be further refined once we implement constraint policies at the resource class level
+// Values of fields constrained by the resource class are handled by applyConstraintPolicy
 func resolveClassInstanceLocalPermissions(classValue, instanceValue *bucketv1alpha1.LocalPermissionType) (*bucketv1alpha1.LocalPermissionType, error) {
 	if classValue == nil {
 		return instanceValue, nil
@@ -183,7 +226,7 @@ func translateACL(acl *bucketv1alpha1.PredefinedACL) (*s3.BucketCannedACL, error
 
 // resolveClassInstanceValues validates instance value against resource class properties.
 // if both values are defined, then the instance value is validated against the resource class value and expected to match
-// TODO: the "matching" process will be further refined once we implement constraint policies at the resource class level
+// Values of fields constrained by the resource class are handled by applyConstraintPolicy
 func resolveClassInstanceValues(classValue, instanceValue string) (string, error) {
 	if classValue == "" {
 		return instanceValue, nil
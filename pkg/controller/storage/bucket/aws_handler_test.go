@@ -33,6 +33,7 @@ import (
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	"github.com/crossplaneio/crossplane/pkg/apis/storage"
 	. "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 	"github.com/crossplaneio/crossplane/pkg/test"
 )
 
@@ -41,6 +42,7 @@ const (
 )
 
 var (
+	ctx = context.Background()
 	cfg *rest.Config
 )
 
@@ -77,6 +79,41 @@ func TestResolveClassInstanceLocalPermissions(t *testing.T) {
 	g.Expect(err).To(And(HaveOccurred(), MatchError("bucket instance value [Write] does not match the one defined in the resource class [Read]")))
 }
 
+func TestApplyConstraintPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	read := ReadOnlyPermission
+	write := WriteOnlyPermission
+	publicRead := ACLPublicRead
+	privateS3 := s3.BucketCannedACLPrivate
+
+	policy := corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			PredefinedACLField:   {Values: []string{string(ACLPrivate), string(ACLPublicRead)}},
+			LocalPermissionField: {Values: []string{string(ReadOnlyPermission)}},
+		},
+	})
+
+	// allowed claim values override the class values of constrained fields
+	spec := &s3Bucketv1alpha1.S3BucketSpec{Name: "class-bucket", CannedACL: &privateS3, LocalPermission: &read}
+	bucket := &Bucket{Spec: BucketSpec{Name: "claim-bucket", PredefinedACL: &publicRead, LocalPermission: &read}}
+	g.Expect(applyConstraintPolicy(policy, spec, bucket)).To(Succeed())
+	g.Expect(spec.Name).To(Equal("class-bucket"))
+	g.Expect(spec.CannedACL).To(BeNil())
+	g.Expect(spec.LocalPermission).To(BeNil())
+
+	// disallowed claim values are rejected
+	spec = &s3Bucketv1alpha1.S3BucketSpec{LocalPermission: &read}
+	bucket = &Bucket{Spec: BucketSpec{LocalPermission: &write}}
+	g.Expect(applyConstraintPolicy(policy, spec, bucket)).To(MatchError(
+		"claim value [Write] for localPermission is not allowed by the resource class: value must be one of [Read]"))
+
+	// a nil policy constrains nothing
+	spec = &s3Bucketv1alpha1.S3BucketSpec{LocalPermission: &read}
+	g.Expect(applyConstraintPolicy(nil, spec, bucket)).To(Succeed())
+	g.Expect(spec.LocalPermission).To(Equal(&read))
+}
+
 func TestTranslateACL(t *testing.T) {
 	g := NewGomegaWithT(t)
 
@@ -132,7 +169,7 @@ func TestProvision(t *testing.T) {
 			return nil
 		}
 
-		_, err := handler.Provision(class, claim, mc)
+		_, err := handler.Provision(ctx, class, claim, mc)
 		g.Expect(err).To(BeNil())
 		g.Expect(expected).To(Equal(rtObj))
 	}
@@ -18,7 +18,6 @@ package bucket
 
 import (
 	"context"
-	"log"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -27,18 +26,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	awsbucketv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	bucketv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
 	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
 )
 
 const (
 	controllerName = "bucket.storage.crossplane.io"
 	finalizer      = "finalizer." + controllerName
+	poolName       = "pool." + controllerName
 )
 
 var (
-	ctx = context.Background()
-
 	// map of supported resource handlers
 	handlers = map[string]corecontroller.ResourceHandler{
 		awsbucketv1alpha1.S3BucketKindAPIVersion: &S3BucketHandler{},
@@ -53,7 +54,14 @@ type Reconciler struct {
 // Add creates a new Bucket Controller and adds it to the Manager with default RBAC.
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-	return add(mgr, newReconciler(mgr))
+	if err := add(mgr, newReconciler(mgr)); err != nil {
+		return err
+	}
+
+	// Keep the pools of resource classes that pool resources for Buckets filled
+	return corecontroller.AddPool(mgr, poolName, bucketv1alpha1.BucketKindAPIVersion,
+		func() corev1alpha1.ResourceClaim { return &bucketv1alpha1.Bucket{} }, handlers,
+		&awsbucketv1alpha1.S3Bucket{})
 }
 
 // newReconciler returns a new reconcile.Reconciler
@@ -67,7 +75,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -78,19 +86,21 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	return nil
+	// Watch for changes to the resources Buckets are bound to, and their secrets
+	return corecontroller.WatchResources(mgr, c, bucketv1alpha1.BucketKind, handlers, &awsbucketv1alpha1.S3Bucket{}, &corev1alpha1.ExternalResource{})
 }
 
 // Reconcile reads that state of the cluster for a Instance object and makes changes based on the state read
 // and what is in the Instance.Spec
 func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", bucketv1alpha1.BucketKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), r.Log, request)
 
 	// fetch the CRD instance
 	instance := &bucketv1alpha1.Bucket{}
 	if err := r.Get(ctx, request.NamespacedName, instance); err != nil {
-		return corecontroller.HandleGetClaimError(err)
+		return r.HandleGetClaimError(request.NamespacedName, err)
 	}
 
-	return r.DoReconcile(instance)
+	logging.Reconciling(ctx, instance)
+	return r.DoReconcile(ctx, instance)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package sql
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -29,25 +30,31 @@ import (
 	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
 	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 // RDSInstanceHandler handles RDS Instance functionality
 type RDSInstanceHandler struct{}
 
 // Find RDSInstance
-func (h *RDSInstanceHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *RDSInstanceHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	rdsInstance := &awsdbv1alpha1.RDSInstance{}
 	err := c.Get(ctx, name, rdsInstance)
 	return rdsInstance, err
 }
 
+// Match an existing, unbound RDSInstance to the supplied claim.
+func (h *RDSInstanceHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &awsdbv1alpha1.RDSInstanceList{})
+}
+
 // Provision create new RDSInstance
-func (h *RDSInstanceHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (h *RDSInstanceHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	// construct RDSInstance Spec from class definition
 	rdsInstanceSpec := awsdbv1alpha1.NewRDSInstanceSpec(class.Parameters)
 
 	// resolve the resource class params and the resource claim values
-	if err := resolveAWSClassInstanceValues(rdsInstanceSpec, claim); err != nil {
+	if err := resolveAWSClassInstanceValues(corecontroller.NewConstraintPolicy(class), rdsInstanceSpec, claim); err != nil {
 		return nil, err
 	}
 
@@ -81,7 +88,7 @@ func (h *RDSInstanceHandler) Provision(class *corev1alpha1.ResourceClass, claim
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (h RDSInstanceHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (h RDSInstanceHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	rdsInstance := &awsdbv1alpha1.RDSInstance{}
 	err := c.Get(ctx, name, rdsInstance)
 	if err != nil {
@@ -95,32 +102,47 @@ func (h RDSInstanceHandler) SetBindStatus(name types.NamespacedName, c client.Cl
 	return c.Update(ctx, rdsInstance)
 }
 
-func resolveAWSClassInstanceValues(rdsInstanceSpec *awsdbv1alpha1.RDSInstanceSpec, claim corev1alpha1.ResourceClaim) error {
+func resolveAWSClassInstanceValues(policy *corecontroller.ConstraintPolicy, rdsInstanceSpec *awsdbv1alpha1.RDSInstanceSpec, claim corev1alpha1.ResourceClaim) error {
 	var engineVersion string
+	var storageSize int64
 
 	switch claim := claim.(type) {
 	case *storagev1alpha1.MySQLInstance:
 		// translate mysql spec fields to RDSInstance spec
 		rdsInstanceSpec.Engine = awsdbv1alpha1.MysqlEngine
 		engineVersion = claim.Spec.EngineVersion
+		storageSize = claim.Spec.StorageSize
 	case *storagev1alpha1.PostgreSQLInstance:
 		// translate postgres spec fields to RDSInstance spec
 		rdsInstanceSpec.Engine = awsdbv1alpha1.PostgresqlEngine
 		engineVersion = claim.Spec.EngineVersion
+		storageSize = claim.Spec.StorageSize
 	default:
 		return fmt.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
 	}
 
-	resolvedEngineVersion, err := validateEngineVersion(rdsInstanceSpec.EngineVersion, engineVersion)
+	var resolvedEngineVersion string
+	var err error
+	if policy.Constrained(EngineVersionField) {
+		resolvedEngineVersion, err = policy.Resolve(EngineVersionField, rdsInstanceSpec.EngineVersion, engineVersion)
+	} else {
+		resolvedEngineVersion, err = ValidateEngineVersion(rdsInstanceSpec.EngineVersion, engineVersion)
+	}
+	if err != nil {
+		return err
+	}
+
+	size, err := policy.ResolveInt(StorageSizeField, rdsInstanceSpec.Size, storageSize)
 	if err != nil {
 		return err
 	}
 
 	rdsInstanceSpec.EngineVersion = resolvedEngineVersion
+	rdsInstanceSpec.Size = size
 	return nil
 }
 
-// validateEngineVersion compares class and claim engine values and returns an engine value or error
+// ValidateEngineVersion compares class and claim engine values and returns an engine value or error
 // if class values is empty - claim value returned (could be an empty string),
 // otherwise if claim value is not a prefix of the class value - return an error
 // else return class value
@@ -130,7 +152,7 @@ func resolveAWSClassInstanceValues(rdsInstanceSpec *awsdbv1alpha1.RDSInstanceSpe
 // class: "", claim: 5.7 - result: 5.7
 // class: 5.6.45, claim 5.6 - result: 5.6.45
 // class: 5.6, claim 5.7 - result error
-func validateEngineVersion(class, claim string) (string, error) {
+func ValidateEngineVersion(class, claim string) (string, error) {
 	if class == "" {
 		return claim, nil
 	}
@@ -22,7 +22,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 func TestResolveAWSClassInstanceValues(t *testing.T) {
@@ -32,7 +34,7 @@ func TestResolveAWSClassInstanceValues(t *testing.T) {
 	// that can be inferred from the abstract type)
 	rdsInstanceSpec := awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{})
 	mysqlInstance := &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: ""}}
-	err := resolveAWSClassInstanceValues(rdsInstanceSpec, mysqlInstance)
+	err := resolveAWSClassInstanceValues(nil, rdsInstanceSpec, mysqlInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(rdsInstanceSpec.EngineVersion).To(Equal(""))
 	g.Expect(rdsInstanceSpec.Engine).To(Equal(awsdbv1alpha1.MysqlEngine))
@@ -40,7 +42,7 @@ func TestResolveAWSClassInstanceValues(t *testing.T) {
 	// class parameter set, instance value not set.  class parameter should be honored
 	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"engineVersion": "9.6.9"})
 	postgresInstance := &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{EngineVersion: ""}}
-	err = resolveAWSClassInstanceValues(rdsInstanceSpec, postgresInstance)
+	err = resolveAWSClassInstanceValues(nil, rdsInstanceSpec, postgresInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(rdsInstanceSpec.EngineVersion).To(Equal("9.6.9"))
 	g.Expect(rdsInstanceSpec.Engine).To(Equal(awsdbv1alpha1.PostgresqlEngine))
@@ -48,7 +50,7 @@ func TestResolveAWSClassInstanceValues(t *testing.T) {
 	// class parameter not set, instance value set.  instance value should be honored
 	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{})
 	postgresInstance = &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{EngineVersion: "9.6.9"}}
-	err = resolveAWSClassInstanceValues(rdsInstanceSpec, postgresInstance)
+	err = resolveAWSClassInstanceValues(nil, rdsInstanceSpec, postgresInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(rdsInstanceSpec.EngineVersion).To(Equal("9.6.9"))
 	g.Expect(rdsInstanceSpec.Engine).To(Equal(awsdbv1alpha1.PostgresqlEngine))
@@ -56,7 +58,7 @@ func TestResolveAWSClassInstanceValues(t *testing.T) {
 	// class parameter and instance value both set and in agreement. should be honored.
 	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"engineVersion": "5.6.45"})
 	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveAWSClassInstanceValues(rdsInstanceSpec, mysqlInstance)
+	err = resolveAWSClassInstanceValues(nil, rdsInstanceSpec, mysqlInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(rdsInstanceSpec.EngineVersion).To(Equal("5.6.45"))
 	g.Expect(rdsInstanceSpec.Engine).To(Equal(awsdbv1alpha1.MysqlEngine))
@@ -64,7 +66,57 @@ func TestResolveAWSClassInstanceValues(t *testing.T) {
 	// class parameter and instance value both set to conflicting values, should be an error.
 	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"engineVersion": "5.7.23"})
 	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveAWSClassInstanceValues(rdsInstanceSpec, mysqlInstance)
+	err = resolveAWSClassInstanceValues(nil, rdsInstanceSpec, mysqlInstance)
 	g.Expect(err).To(HaveOccurred())
 
+	// engine version constrained by the class, instance value allowed. instance value should be honored.
+	policy := corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			EngineVersionField: {Versions: []string{"5.6.x", "5.7.x"}},
+		},
+	})
+	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"engineVersion": "5.6.40"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.7"}}
+	err = resolveAWSClassInstanceValues(policy, rdsInstanceSpec, mysqlInstance)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rdsInstanceSpec.EngineVersion).To(Equal("5.7"))
+
+	// engine version constrained by the class, instance value not set. class parameter should be honored.
+	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"engineVersion": "5.6.40"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: ""}}
+	err = resolveAWSClassInstanceValues(policy, rdsInstanceSpec, mysqlInstance)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rdsInstanceSpec.EngineVersion).To(Equal("5.6.40"))
+
+	// engine version constrained by the class, instance value not allowed. should be an error.
+	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"engineVersion": "5.6.40"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "8.0"}}
+	err = resolveAWSClassInstanceValues(policy, rdsInstanceSpec, mysqlInstance)
+	g.Expect(err).To(HaveOccurred())
+
+	// storage size constrained by the class: instance values at the bounds are
+	// honored, values just outside them are errors.
+	min, max := int64(20), int64(100)
+	policy = corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			StorageSizeField: {Minimum: &min, Maximum: &max},
+		},
+	})
+	for size, wantErr := range map[int64]bool{20: false, 100: false, 19: true, 101: true} {
+		rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"size": "50"})
+		postgresInstance = &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{StorageSize: size}}
+		err = resolveAWSClassInstanceValues(policy, rdsInstanceSpec, postgresInstance)
+		if wantErr {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(rdsInstanceSpec.Size).To(Equal(size))
+	}
+
+	// storage size not constrained by the class, instance value differs from the class. should be an error.
+	rdsInstanceSpec = awsdbv1alpha1.NewRDSInstanceSpec(map[string]string{"size": "50"})
+	postgresInstance = &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{StorageSize: 20}}
+	err = resolveAWSClassInstanceValues(nil, rdsInstanceSpec, postgresInstance)
+	g.Expect(err).To(HaveOccurred())
 }
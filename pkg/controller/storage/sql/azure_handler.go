@@ -17,6 +17,7 @@ limitations under the License.
 package sql
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -38,37 +39,47 @@ type AzureMySQLServerHandler struct{}
 type AzurePostgreSQLServerHandler struct{}
 
 // Find Azure MysqlServer resource
-func (h *AzureMySQLServerHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *AzureMySQLServerHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	azureMySQLServer := &azuredbv1alpha1.MysqlServer{}
 	err := c.Get(ctx, name, azureMySQLServer)
 	return azureMySQLServer, err
 }
 
+// Match an existing, unbound Azure MysqlServer resource to the supplied claim.
+func (h *AzureMySQLServerHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &azuredbv1alpha1.MysqlServerList{})
+}
+
 // Find a PostgreSQL server.
-func (h *AzurePostgreSQLServerHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *AzurePostgreSQLServerHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	azurePostgreSQLServer := &azuredbv1alpha1.PostgresqlServer{}
 	err := c.Get(ctx, name, azurePostgreSQLServer)
 	return azurePostgreSQLServer, err
 }
 
+// Match an existing, unbound PostgreSQL server to the supplied claim.
+func (h *AzurePostgreSQLServerHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &azuredbv1alpha1.PostgresqlServerList{})
+}
+
 // Provision (create) a new Azure SQL Server resource
-func (h *AzureMySQLServerHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
-	return provisionAzureSQL(class, claim, c)
+func (h *AzureMySQLServerHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return provisionAzureSQL(ctx, class, claim, c)
 }
 
 // Provision (create) a new Azure SQL Server resource
-func (h *AzurePostgreSQLServerHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
-	return provisionAzureSQL(class, claim, c)
+func (h *AzurePostgreSQLServerHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return provisionAzureSQL(ctx, class, claim, c)
 }
 
-func provisionAzureSQL(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim,
+func provisionAzureSQL(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim,
 	c client.Client) (corev1alpha1.Resource, error) {
 
 	// construct Azure MySQL Server spec from class definition/parameters
 	sqlServerSpec := azuredbv1alpha1.NewSQLServerSpec(class.Parameters)
 
 	// resolve the resource class params and the resource claim values
-	if err := resolveAzureClassInstanceValues(sqlServerSpec, claim); err != nil {
+	if err := resolveAzureClassInstanceValues(corecontroller.NewConstraintPolicy(class), sqlServerSpec, claim); err != nil {
 		return nil, err
 	}
 
@@ -121,21 +132,21 @@ func provisionAzureSQL(class *corev1alpha1.ResourceClass, claim corev1alpha1.Res
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (h *AzureMySQLServerHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (h *AzureMySQLServerHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	mysqlServer := &azuredbv1alpha1.MysqlServer{}
 	err := c.Get(ctx, name, mysqlServer)
-	return setBindStatus(mysqlServer, err, c, bound)
+	return setBindStatus(ctx, mysqlServer, err, c, bound)
 }
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (h *AzurePostgreSQLServerHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (h *AzurePostgreSQLServerHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	postgresqlServer := &azuredbv1alpha1.PostgresqlServer{}
 	err := c.Get(ctx, name, postgresqlServer)
-	return setBindStatus(postgresqlServer, err, c, bound)
+	return setBindStatus(ctx, postgresqlServer, err, c, bound)
 }
 
-func setBindStatus(resource corev1alpha1.Resource, getErr error, c client.StatusWriter, bound bool) error {
+func setBindStatus(ctx context.Context, resource corev1alpha1.Resource, getErr error, c client.StatusWriter, bound bool) error {
 	if getErr != nil {
 		// TODO: the CRD is not found and the binding state is supposed to be unbound. is this OK?
 		if errors.IsNotFound(getErr) && !bound {
@@ -149,24 +160,32 @@ func setBindStatus(resource corev1alpha1.Resource, getErr error, c client.Status
 	return c.Update(ctx, resource)
 }
 
-func resolveAzureClassInstanceValues(sqlServerSpec *azuredbv1alpha1.SQLServerSpec, claim corev1alpha1.ResourceClaim) error {
+func resolveAzureClassInstanceValues(policy *corecontroller.ConstraintPolicy, sqlServerSpec *azuredbv1alpha1.SQLServerSpec, claim corev1alpha1.ResourceClaim) error {
 	var engineVersion string
+	var storageSize int64
 
 	switch claim := claim.(type) {
 	case *storagev1alpha1.MySQLInstance:
 		engineVersion = claim.Spec.EngineVersion
+		storageSize = claim.Spec.StorageSize
 	case *storagev1alpha1.PostgreSQLInstance:
 		engineVersion = claim.Spec.EngineVersion
+		storageSize = claim.Spec.StorageSize
 	default:
 		return fmt.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
 	}
 
-	resolvedEngineVersion, err := corecontroller.ResolveClassClaimValues(
-		sqlServerSpec.Version, engineVersion)
+	resolvedEngineVersion, err := policy.Resolve(EngineVersionField, sqlServerSpec.Version, engineVersion)
+	if err != nil {
+		return err
+	}
+
+	size, err := policy.ResolveInt(StorageSizeField, int64(sqlServerSpec.StorageProfile.StorageGB), storageSize)
 	if err != nil {
 		return err
 	}
 
 	sqlServerSpec.Version = resolvedEngineVersion
+	sqlServerSpec.StorageProfile.StorageGB = int(size)
 	return nil
 }
@@ -22,7 +22,9 @@ import (
 	. "github.com/onsi/gomega"
 
 	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 func TestResolveAzureClassInstanceValues(t *testing.T) {
@@ -31,34 +33,72 @@ func TestResolveAzureClassInstanceValues(t *testing.T) {
 	// no class or instance values set: no error and no resolved value
 	mysqlServerSpec := azurev1alpha1.NewSQLServerSpec(map[string]string{})
 	mysqlInstance := &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: ""}}
-	err := resolveAzureClassInstanceValues(mysqlServerSpec, mysqlInstance)
+	err := resolveAzureClassInstanceValues(nil, mysqlServerSpec, mysqlInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(mysqlServerSpec.Version).To(Equal(""))
 
 	// class parameter set, instance value not set.  class parameter should be honored
 	mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{"version": "5.6"})
 	postgresInstance := &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: ""}}
-	err = resolveAzureClassInstanceValues(mysqlServerSpec, postgresInstance)
+	err = resolveAzureClassInstanceValues(nil, mysqlServerSpec, postgresInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(mysqlServerSpec.Version).To(Equal("5.6"))
 
 	// class parameter not set, instance value set.  instance value should be honored
 	mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{})
 	postgresInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveAzureClassInstanceValues(mysqlServerSpec, postgresInstance)
+	err = resolveAzureClassInstanceValues(nil, mysqlServerSpec, postgresInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(mysqlServerSpec.Version).To(Equal("5.6"))
 
 	// class parameter and instance value both set and in agreement. should be honored.
 	mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{"version": "5.6"})
 	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveAzureClassInstanceValues(mysqlServerSpec, mysqlInstance)
+	err = resolveAzureClassInstanceValues(nil, mysqlServerSpec, mysqlInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(mysqlServerSpec.Version).To(Equal("5.6"))
 
 	// class parameter and instance value both set to conflicting values, should be an error.
 	mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{"version": "5.7"})
 	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveAzureClassInstanceValues(mysqlServerSpec, mysqlInstance)
+	err = resolveAzureClassInstanceValues(nil, mysqlServerSpec, mysqlInstance)
 	g.Expect(err).To(HaveOccurred())
+
+	// engine version constrained by the class, instance value allowed. instance value should be honored.
+	policy := corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			EngineVersionField: {Versions: []string{"5.6.x", "5.7.x"}},
+		},
+	})
+	mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{"version": "5.6"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.7"}}
+	err = resolveAzureClassInstanceValues(policy, mysqlServerSpec, mysqlInstance)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(mysqlServerSpec.Version).To(Equal("5.7"))
+
+	// engine version constrained by the class, instance value not allowed. should be an error.
+	mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{"version": "5.6"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "8.0"}}
+	err = resolveAzureClassInstanceValues(policy, mysqlServerSpec, mysqlInstance)
+	g.Expect(err).To(HaveOccurred())
+
+	// storage size constrained by the class: instance values at the bounds are
+	// honored, values just outside them are errors.
+	min, max := int64(20), int64(100)
+	policy = corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			StorageSizeField: {Minimum: &min, Maximum: &max},
+		},
+	})
+	for size, wantErr := range map[int64]bool{20: false, 100: false, 19: true, 101: true} {
+		mysqlServerSpec = azurev1alpha1.NewSQLServerSpec(map[string]string{"storageGB": "50"})
+		mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{StorageSize: size}}
+		err = resolveAzureClassInstanceValues(policy, mysqlServerSpec, mysqlInstance)
+		if wantErr {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(mysqlServerSpec.StorageProfile.StorageGB).To(Equal(int(size)))
+	}
 }
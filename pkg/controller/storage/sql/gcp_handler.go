@@ -17,6 +17,7 @@ limitations under the License.
 package sql
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -36,19 +37,24 @@ import (
 type CloudSQLServerHandler struct{}
 
 // Find CloudSQL resource
-func (h *CloudSQLServerHandler) Find(name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
+func (h *CloudSQLServerHandler) Find(ctx context.Context, name types.NamespacedName, c client.Client) (corev1alpha1.Resource, error) {
 	cloudsqlInstance := &gcpdbv1alpha1.CloudsqlInstance{}
 	err := c.Get(ctx, name, cloudsqlInstance)
 	return cloudsqlInstance, err
 }
 
+// Match an existing, unbound CloudSQL resource to the supplied claim.
+func (h *CloudSQLServerHandler) Match(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+	return corecontroller.MatchUnboundResource(ctx, c, class.Namespace, claim, &gcpdbv1alpha1.CloudsqlInstanceList{})
+}
+
 // Provision (create) a new CloudSQL resource
-func (h *CloudSQLServerHandler) Provision(class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
+func (h *CloudSQLServerHandler) Provision(ctx context.Context, class *corev1alpha1.ResourceClass, claim corev1alpha1.ResourceClaim, c client.Client) (corev1alpha1.Resource, error) {
 	// construct CloudSQL resource spec from class definition/parameters
 	cloudsqlInstanceSpec := gcpdbv1alpha1.NewCloudSQLInstanceSpec(class.Parameters)
 
 	// resolve the resource class params and the resource claim values
-	if err := resolveGCPClassInstanceValues(cloudsqlInstanceSpec, claim); err != nil {
+	if err := resolveGCPClassInstanceValues(corecontroller.NewConstraintPolicy(class), cloudsqlInstanceSpec, claim); err != nil {
 		return nil, err
 	}
 
@@ -90,7 +96,7 @@ func (h *CloudSQLServerHandler) Provision(class *corev1alpha1.ResourceClass, cla
 
 // SetBindStatus updates resource state binding phase
 // TODO: this SetBindStatus function could be refactored to 1 common implementation for all providers
-func (h *CloudSQLServerHandler) SetBindStatus(name types.NamespacedName, c client.Client, bound bool) error {
+func (h *CloudSQLServerHandler) SetBindStatus(ctx context.Context, name types.NamespacedName, c client.Client, bound bool) error {
 	cloudsqlInstance := &gcpdbv1alpha1.CloudsqlInstance{}
 	err := c.Get(ctx, name, cloudsqlInstance)
 	if err != nil {
@@ -104,23 +110,43 @@ func (h *CloudSQLServerHandler) SetBindStatus(name types.NamespacedName, c clien
 	return c.Update(ctx, cloudsqlInstance)
 }
 
-func resolveGCPClassInstanceValues(cloudsqlInstanceSpec *gcpdbv1alpha1.CloudsqlInstanceSpec, claim corev1alpha1.ResourceClaim) error {
+func resolveGCPClassInstanceValues(policy *corecontroller.ConstraintPolicy, cloudsqlInstanceSpec *gcpdbv1alpha1.CloudsqlInstanceSpec, claim corev1alpha1.ResourceClaim) error {
 	var engineVersion string
 	var versionPrefix string
+	var storageSize int64
 
 	switch claim := claim.(type) {
 	case *storagev1alpha1.MySQLInstance:
 		engineVersion = claim.Spec.EngineVersion
 		versionPrefix = gcpdbv1alpha1.MysqlDBVersionPrefix
+		storageSize = claim.Spec.StorageSize
 	case *storagev1alpha1.PostgreSQLInstance:
 		engineVersion = claim.Spec.EngineVersion
 		versionPrefix = gcpdbv1alpha1.PostgresqlDBVersionPrefix
+		storageSize = claim.Spec.StorageSize
 	default:
 		return fmt.Errorf("unexpected claim type: %+v", reflect.TypeOf(claim))
 	}
 
+	size, err := policy.ResolveInt(StorageSizeField, cloudsqlInstanceSpec.StorageGB, storageSize)
+	if err != nil {
+		return err
+	}
+	cloudsqlInstanceSpec.StorageGB = size
+
 	// translate and validate engine version
 	translatedEngineVersion := translateVersion(engineVersion, versionPrefix)
+	if policy.Constrained(EngineVersionField) {
+		// constraints apply to the version requested by the claim, not the translated version
+		if err := policy.Check(EngineVersionField, engineVersion); err != nil {
+			return err
+		}
+		if translatedEngineVersion != "" {
+			cloudsqlInstanceSpec.DatabaseVersion = translatedEngineVersion
+		}
+		return nil
+	}
+
 	resolvedEngineVersion, err := corecontroller.ResolveClassClaimValues(
 		cloudsqlInstanceSpec.DatabaseVersion, translatedEngineVersion)
 	if err != nil {
@@ -21,8 +21,10 @@ import (
 
 	. "github.com/onsi/gomega"
 
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
 func TestResolveGCPClassInstanceValues(t *testing.T) {
@@ -31,34 +33,79 @@ func TestResolveGCPClassInstanceValues(t *testing.T) {
 	// no class or instance values set: no error and no resolved value
 	cloudsqlInstanceSpec := gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{})
 	mysqlInstance := &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: ""}}
-	err := resolveGCPClassInstanceValues(cloudsqlInstanceSpec, mysqlInstance)
+	err := resolveGCPClassInstanceValues(nil, cloudsqlInstanceSpec, mysqlInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(cloudsqlInstanceSpec.DatabaseVersion).To(Equal(""))
 
 	// class parameter set, instance value not set.  class parameter should be honored
 	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"databaseVersion": "POSTGRES_9_6"})
 	postgresInstance := &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{EngineVersion: ""}}
-	err = resolveGCPClassInstanceValues(cloudsqlInstanceSpec, postgresInstance)
+	err = resolveGCPClassInstanceValues(nil, cloudsqlInstanceSpec, postgresInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(cloudsqlInstanceSpec.DatabaseVersion).To(Equal("POSTGRES_9_6"))
 
 	// class parameter not set, instance value set.  translated instance value should be honored
 	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{})
 	postgresInstance = &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{EngineVersion: "9.6"}}
-	err = resolveGCPClassInstanceValues(cloudsqlInstanceSpec, postgresInstance)
+	err = resolveGCPClassInstanceValues(nil, cloudsqlInstanceSpec, postgresInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(cloudsqlInstanceSpec.DatabaseVersion).To(Equal("POSTGRES_9_6"))
 
 	// class parameter and instance value both set and in agreement. should be honored.
 	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"databaseVersion": "MYSQL_5_6"})
 	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveGCPClassInstanceValues(cloudsqlInstanceSpec, mysqlInstance)
+	err = resolveGCPClassInstanceValues(nil, cloudsqlInstanceSpec, mysqlInstance)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(cloudsqlInstanceSpec.DatabaseVersion).To(Equal("MYSQL_5_6"))
 
 	// class parameter and instance value both set to conflicting values, should be an error.
 	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"databaseVersion": "MYSQL_5_7"})
 	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"}}
-	err = resolveGCPClassInstanceValues(cloudsqlInstanceSpec, mysqlInstance)
+	err = resolveGCPClassInstanceValues(nil, cloudsqlInstanceSpec, mysqlInstance)
 	g.Expect(err).To(HaveOccurred())
+
+	// engine version constrained by the class, instance value allowed. translated instance value should be honored.
+	policy := corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			EngineVersionField: {Versions: []string{"5.6.x", "5.7.x"}},
+		},
+	})
+	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"databaseVersion": "MYSQL_5_6"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.7"}}
+	err = resolveGCPClassInstanceValues(policy, cloudsqlInstanceSpec, mysqlInstance)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cloudsqlInstanceSpec.DatabaseVersion).To(Equal("MYSQL_5_7"))
+
+	// engine version constrained by the class, instance value not set. class parameter should be honored.
+	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"databaseVersion": "MYSQL_5_6"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: ""}}
+	err = resolveGCPClassInstanceValues(policy, cloudsqlInstanceSpec, mysqlInstance)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cloudsqlInstanceSpec.DatabaseVersion).To(Equal("MYSQL_5_6"))
+
+	// engine version constrained by the class, instance value not allowed. should be an error.
+	cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"databaseVersion": "MYSQL_5_6"})
+	mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: "8.0"}}
+	err = resolveGCPClassInstanceValues(policy, cloudsqlInstanceSpec, mysqlInstance)
+	g.Expect(err).To(HaveOccurred())
+
+	// storage size constrained by the class: instance values at the bounds are
+	// honored, values just outside them are errors.
+	min, max := int64(20), int64(100)
+	policy = corecontroller.NewConstraintPolicy(&corev1alpha1.ResourceClass{
+		Constraints: map[string]corev1alpha1.ParameterConstraint{
+			StorageSizeField: {Minimum: &min, Maximum: &max},
+		},
+	})
+	for size, wantErr := range map[int64]bool{20: false, 100: false, 19: true, 101: true} {
+		cloudsqlInstanceSpec = gcpdbv1alpha1.NewCloudSQLInstanceSpec(map[string]string{"storageGB": "50"})
+		mysqlInstance = &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{StorageSize: size}}
+		err = resolveGCPClassInstanceValues(policy, cloudsqlInstanceSpec, mysqlInstance)
+		if wantErr {
+			g.Expect(err).To(HaveOccurred())
+			continue
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cloudsqlInstanceSpec.StorageGB).To(Equal(size))
+	}
 }
@@ -17,7 +17,7 @@ limitations under the License.
 package sql
 
 import (
-	"log"
+	"context"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -25,13 +25,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
 	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
 	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+	"github.com/crossplaneio/crossplane/pkg/metrics"
 )
 
 const (
 	mysqlControllerName = "mysql.storage.crossplane.io"
 	mysqlFinalizerName  = "finalizer." + mysqlControllerName
+	mysqlPoolName       = "pool." + mysqlControllerName
 )
 
 // MySQLReconciler is the reconciler for MySQLInstance objects
@@ -42,7 +49,16 @@ type MySQLReconciler struct {
 // AddMySQL creates a new MySQLInstance Controller and adds it to the Manager with default RBAC.
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func AddMySQL(mgr manager.Manager) error {
-	return addMySQL(mgr, newMySQLReconciler(mgr))
+	corecontroller.SecretDefinitions.SetDefault(mysqlSecretDefinition)
+
+	if err := addMySQL(mgr, newMySQLReconciler(mgr)); err != nil {
+		return err
+	}
+
+	// Keep the pools of resource classes that pool resources for MySQLInstances filled
+	return corecontroller.AddPool(mgr, mysqlPoolName, storagev1alpha1.MySQLInstanceKindAPIVersion,
+		func() corev1alpha1.ResourceClaim { return &storagev1alpha1.MySQLInstance{} }, handlers,
+		&awsdbv1alpha1.RDSInstance{}, &azuredbv1alpha1.MysqlServer{}, &gcpdbv1alpha1.CloudsqlInstance{})
 }
 
 // newMySQLReconciler returns a new MySQL reconcile.Reconciler
@@ -56,7 +72,7 @@ func newMySQLReconciler(mgr manager.Manager) reconcile.Reconciler {
 // addMySQL adds a new Controller to mgr with r as the reconcile.Reconciler
 func addMySQL(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(mysqlControllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(mysqlControllerName, mgr, controller.Options{Reconciler: metrics.InstrumentReconciler(mysqlControllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -67,19 +83,22 @@ func addMySQL(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	return nil
+	// Watch for changes to the resources MySQLInstances are bound to, and their secrets
+	return corecontroller.WatchResources(mgr, c, storagev1alpha1.MySQLInstanceKind, handlers,
+		&awsdbv1alpha1.RDSInstance{}, &azuredbv1alpha1.MysqlServer{}, &gcpdbv1alpha1.CloudsqlInstance{}, &corev1alpha1.ExternalResource{})
 }
 
 // Reconcile reads that state of the cluster for a MySQLInstance object and makes changes based on the state read
 // and what is in the Instance.Spec
 func (r *MySQLReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	log.Printf("reconciling %s: %v", storagev1alpha1.MySQLInstanceKindAPIVersion, request)
+	ctx := logging.NewContext(context.Background(), r.Log, request)
 
 	// fetch the CRD instance
 	instance := &storagev1alpha1.MySQLInstance{}
 	if err := r.Get(ctx, request.NamespacedName, instance); err != nil {
-		return corecontroller.HandleGetClaimError(err)
+		return r.HandleGetClaimError(request.NamespacedName, err)
 	}
 
-	return r.DoReconcile(instance)
+	logging.Reconciling(ctx, instance)
+	return r.DoReconcile(ctx, instance)
 }
@@ -17,17 +17,23 @@ limitations under the License.
 package sql
 
 import (
-	"context"
-
 	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
 	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
 	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
 	corecontroller "github.com/crossplaneio/crossplane/pkg/controller/core"
 )
 
-var (
-	ctx = context.Background()
+// EngineVersionField is the claim field resource classes may constrain to
+// allow claims to request a range of engine versions.
+const EngineVersionField = "engineVersion"
 
+// StorageSizeField is the claim field resource classes may constrain to allow
+// claims to request a range of storage sizes, in GB.
+const StorageSizeField = "storageSize"
+
+var (
 	// map of supported resource handlers
 	handlers = map[string]corecontroller.ResourceHandler{
 		awsdbv1alpha1.RDSInstanceKindAPIVersion:        &RDSInstanceHandler{},
@@ -35,4 +41,15 @@ var (
 		azuredbv1alpha1.PostgresqlServerKindAPIVersion: &AzurePostgreSQLServerHandler{},
 		gcpdbv1alpha1.CloudsqlInstanceKindAPIVersion:   &CloudSQLServerHandler{},
 	}
+
+	// keys every database connection secret must contain
+	secretKeys = []string{
+		corev1alpha1.ResourceCredentialsSecretEndpointKey,
+		corev1alpha1.ResourceCredentialsSecretUserKey,
+		corev1alpha1.ResourceCredentialsSecretPasswordKey,
+	}
+
+	// built-in definitions of connection secrets, used unless overridden by a CustomSecretDefinition
+	mysqlSecretDefinition    = corev1alpha1.NewCustomSecretDefinition(storagev1alpha1.Group, storagev1alpha1.Version, storagev1alpha1.MySQLInstanceKind, secretKeys...)
+	postgresSecretDefinition = corev1alpha1.NewCustomSecretDefinition(storagev1alpha1.Group, storagev1alpha1.Version, storagev1alpha1.PostgreSQLInstanceKind, secretKeys...)
 )
@@ -21,25 +21,21 @@ import (
 
 	"github.com/crossplaneio/crossplane/pkg/controller/storage/bucket"
 	"github.com/crossplaneio/crossplane/pkg/controller/storage/sql"
+	"github.com/crossplaneio/crossplane/pkg/selection"
 )
 
 func init() {
-	AddToManagerFuncs = append(AddToManagerFuncs,
-		bucket.Add,
-		sql.AddMySQL,
-		sql.AddPostgreSQL,
+	Controllers = append(Controllers,
+		selection.Controller{Kind: selection.KindStorage, Add: bucket.Add},
+		selection.Controller{Kind: selection.KindDatabase, Add: sql.AddMySQL},
+		selection.Controller{Kind: selection.KindDatabase, Add: sql.AddPostgreSQL},
 	)
 }
 
-// AddToManagerFuncs is a list of functions to add all Controllers to the Manager
-var AddToManagerFuncs []func(manager.Manager) error
-
-// AddToManager adds all Controllers to the Manager
-func AddToManager(m manager.Manager) error {
-	for _, f := range AddToManagerFuncs {
-		if err := f(m); err != nil {
-			return err
-		}
-	}
-	return nil
+// Controllers are the controllers of database and storage resource claims
+var Controllers []selection.Controller
+
+// AddToManager adds the selected Controllers to the Manager
+func AddToManager(m manager.Manager, sel selection.Selection) error {
+	return sel.AddToManager(m, Controllers)
 }
@@ -0,0 +1,114 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
+	azurev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
+)
+
+// Ping always succeeds. It is intended to be used as a liveness check that
+// passes as long as the probes are served.
+func Ping() error { return nil }
+
+// Standby returns a check that passes while the supplied function reports that
+// this replica is not the leader, and runs the supplied check otherwise. The
+// checks of components that only run on the leader are wrapped by it, so that
+// standby replicas are ready to take over.
+func Standby(leading func() bool, check Check) Check {
+	return func() error {
+		if !leading() {
+			return nil
+		}
+		return check()
+	}
+}
+
+// A CacheSync check passes once the manager it was added to has started. The
+// manager starts it after the informer caches of its controllers have synced.
+type CacheSync struct {
+	synced int32
+}
+
+// Start marks the caches as synced, then blocks until the supplied channel is
+// closed.
+func (c *CacheSync) Start(stop <-chan struct{}) error {
+	atomic.StoreInt32(&c.synced, 1)
+	<-stop
+	return nil
+}
+
+// Check returns an error until the caches have synced.
+func (c *CacheSync) Check() error {
+	if atomic.LoadInt32(&c.synced) == 0 {
+		return errors.New("informer caches have not synced")
+	}
+	return nil
+}
+
+// Providers returns a check that fails while an AWS, GCP or Azure provider
+// has failed to connect to its cloud, e.g. because its credentials are
+// invalid. Providers that have not yet been reconciled, and the providers of
+// clouds that are not selected, are ignored.
+func Providers(c client.Client) Check {
+	return func() error {
+		aws := &awsv1alpha1.ProviderList{}
+		gcp := &gcpv1alpha1.ProviderList{}
+		azure := &azurev1alpha1.ProviderList{}
+		for _, l := range []runtime.Object{aws, gcp, azure} {
+			err := c.List(context.Background(), &client.ListOptions{}, l)
+			if runtime.IsNotRegisteredError(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("cannot list providers: %s", err)
+			}
+		}
+
+		failed := []string{}
+		add := func(kind, namespace, name string, status corev1alpha1.ConditionedStatus) {
+			if status.IsFailed() {
+				failed = append(failed, fmt.Sprintf("%s %s/%s: %s", kind, namespace, name, status.FailureMessage()))
+			}
+		}
+		for _, p := range aws.Items {
+			add("aws", p.GetNamespace(), p.GetName(), p.Status.ConditionedStatus)
+		}
+		for _, p := range gcp.Items {
+			add("gcp", p.GetNamespace(), p.GetName(), p.Status.ConditionedStatus)
+		}
+		for _, p := range azure.Items {
+			add("azure", p.GetNamespace(), p.GetName(), p.Status.ConditionedStatus)
+		}
+
+		if len(failed) > 0 {
+			return errors.New(strings.Join(failed, "; "))
+		}
+		return nil
+	}
+}
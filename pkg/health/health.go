@@ -0,0 +1,152 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health serves the liveness and readiness probes of Crossplane.
+//
+// The probes are served at /healthz and /readyz, in the style of the
+// Kubernetes API server: each runs a set of named checks, and fails if any of
+// them fails. A single check may be run at /healthz/<name> or /readyz/<name>,
+// and checks may be left out with the exclude query parameter, e.g.
+// /readyz?exclude=providers. The result of every check is listed if the probe
+// fails, or if the verbose query parameter is set.
+package health
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Probe paths.
+const (
+	PathLiveness  = "/healthz"
+	PathReadiness = "/readyz"
+)
+
+// A Check returns an error if the component it checks is not healthy.
+type Check func() error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// A Checker serves the liveness and readiness probes.
+type Checker struct {
+	mu     sync.RWMutex
+	checks map[string][]namedCheck
+}
+
+// NewChecker returns a Checker without any checks. A probe without checks
+// always succeeds.
+func NewChecker() *Checker {
+	return &Checker{checks: map[string][]namedCheck{}}
+}
+
+// AddLivenessCheck adds the supplied check to the liveness probe.
+func (c *Checker) AddLivenessCheck(name string, check Check) {
+	c.add(PathLiveness, name, check)
+}
+
+// AddReadinessCheck adds the supplied check to the readiness probe.
+func (c *Checker) AddReadinessCheck(name string, check Check) {
+	c.add(PathReadiness, name, check)
+}
+
+func (c *Checker) add(path, name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[path] = append(c.checks[path], namedCheck{name: name, check: check})
+}
+
+// ServeHTTP runs the checks of the requested probe.
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, name := r.URL.Path, ""
+	for _, p := range []string{PathLiveness, PathReadiness} {
+		if strings.HasPrefix(path, p+"/") {
+			path, name = p, strings.TrimPrefix(path, p+"/")
+		}
+	}
+
+	c.mu.RLock()
+	checks, ok := c.checks[path]
+	c.mu.RUnlock()
+	if !ok && path != PathLiveness && path != PathReadiness {
+		http.NotFound(w, r)
+		return
+	}
+
+	exclude := map[string]bool{}
+	for _, e := range r.URL.Query()["exclude"] {
+		exclude[e] = true
+	}
+
+	out, failed, found := &bytes.Buffer{}, false, false
+	for _, nc := range checks {
+		if (name != "" && nc.name != name) || exclude[nc.name] {
+			continue
+		}
+		found = true
+		if err := nc.check(); err != nil {
+			failed = true
+			fmt.Fprintf(out, "[-]%s failed: %s\n", nc.name, err)
+			continue
+		}
+		fmt.Fprintf(out, "[+]%s ok\n", nc.name)
+	}
+	if name != "" && !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	_, verbose := r.URL.Query()["verbose"]
+	switch {
+	case failed:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "%s%s check failed\n", out, strings.TrimPrefix(path, "/"))
+	case verbose:
+		fmt.Fprintf(w, "%s%s check passed\n", out, strings.TrimPrefix(path, "/"))
+	default:
+		fmt.Fprint(w, "ok")
+	}
+}
+
+// Serve the probes on the supplied address until the supplied channel is
+// closed.
+func (c *Checker) Serve(addr string, stop <-chan struct{}) error {
+	mux := http.NewServeMux()
+	mux.Handle(PathLiveness, c)
+	mux.Handle(PathLiveness+"/", c)
+	mux.Handle(PathReadiness, c)
+	mux.Handle(PathReadiness+"/", c)
+	s := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-stop
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Shutdown(ctx) // nolint:errcheck
+	}()
+
+	if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	awsv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/v1alpha1"
+)
+
+var errBoom = errors.New("boom")
+
+func get(c *Checker, path string) (int, string) {
+	w := httptest.NewRecorder()
+	c.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w.Code, w.Body.String()
+}
+
+func TestChecker(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := NewChecker()
+
+	// test: probes without checks succeed
+	code, body := get(c, PathReadiness)
+	g.Expect(code).To(Equal(http.StatusOK))
+	g.Expect(body).To(Equal("ok"))
+
+	c.AddLivenessCheck("ping", Ping)
+	c.AddReadinessCheck("cache", Ping)
+	c.AddReadinessCheck("providers", func() error { return errBoom })
+
+	// test: a probe fails if any of its checks fails
+	code, _ = get(c, PathLiveness)
+	g.Expect(code).To(Equal(http.StatusOK))
+	code, body = get(c, PathReadiness)
+	g.Expect(code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(body).To(Equal("[+]cache ok\n[-]providers failed: boom\nreadyz check failed\n"))
+
+	// test: failing checks may be excluded
+	code, _ = get(c, PathReadiness+"?exclude=providers")
+	g.Expect(code).To(Equal(http.StatusOK))
+
+	// test: checks may be run individually
+	code, _ = get(c, PathReadiness+"/cache")
+	g.Expect(code).To(Equal(http.StatusOK))
+	code, _ = get(c, PathReadiness+"/providers")
+	g.Expect(code).To(Equal(http.StatusServiceUnavailable))
+	code, _ = get(c, PathReadiness+"/unknown")
+	g.Expect(code).To(Equal(http.StatusNotFound))
+
+	// test: passed checks are listed if verbose
+	code, body = get(c, PathLiveness+"?verbose")
+	g.Expect(code).To(Equal(http.StatusOK))
+	g.Expect(body).To(Equal("[+]ping ok\nhealthz check passed\n"))
+}
+
+func TestStandby(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	leading := false
+	check := Standby(func() bool { return leading }, func() error { return errBoom })
+	g.Expect(check()).To(Succeed())
+
+	leading = true
+	g.Expect(check()).To(Equal(errBoom))
+}
+
+func TestCacheSync(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	c := &CacheSync{}
+	g.Expect(c.Check()).NotTo(Succeed())
+
+	stop := make(chan struct{})
+	close(stop)
+	g.Expect(c.Start(stop)).To(Succeed())
+	g.Expect(c.Check()).To(Succeed())
+}
+
+type listClient struct {
+	client.Client
+	list func(obj runtime.Object) error
+}
+
+func (c *listClient) List(_ context.Context, _ *client.ListOptions, obj runtime.Object) error {
+	return c.list(obj)
+}
+
+func TestProviders(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	failed := corev1alpha1.ConditionedStatus{}
+	failed.SetFailed("invalid credentials", "access denied")
+	ready := corev1alpha1.ConditionedStatus{}
+	ready.SetReady()
+
+	c := &listClient{list: func(obj runtime.Object) error {
+		switch l := obj.(type) {
+		case *awsv1alpha1.ProviderList:
+			l.Items = []awsv1alpha1.Provider{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "ready"}, Status: awsv1alpha1.ProviderStatus{ConditionedStatus: ready}},
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "new"}},
+			}
+		case *gcpv1alpha1.ProviderList:
+			l.Items = []gcpv1alpha1.Provider{
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "failed"}, Status: gcpv1alpha1.ProviderStatus{ConditionedStatus: failed}},
+			}
+		}
+		return nil
+	}}
+
+	// test: failed providers fail the check
+	g.Expect(Providers(c)()).To(MatchError("gcp crossplane-system/failed: access denied"))
+
+	// test: providers that cannot be listed fail the check
+	c.list = func(runtime.Object) error { return errBoom }
+	g.Expect(Providers(c)()).To(HaveOccurred())
+
+	// test: ready and new providers pass the check
+	c.list = func(obj runtime.Object) error {
+		if l, ok := obj.(*awsv1alpha1.ProviderList); ok {
+			l.Items = []awsv1alpha1.Provider{{ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "ready"}, Status: awsv1alpha1.ProviderStatus{ConditionedStatus: ready}}}
+		}
+		return nil
+	}
+	g.Expect(Providers(c)()).To(Succeed())
+
+	// test: providers of clouds that are not selected are ignored
+	c.list = func(obj runtime.Object) error {
+		if _, ok := obj.(*awsv1alpha1.ProviderList); ok {
+			return nil
+		}
+		return runtime.NewNotRegisteredErrForKind("test", schema.GroupVersionKind{Kind: "ProviderList"})
+	}
+	g.Expect(Providers(c)()).To(Succeed())
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leader elects the replica of Crossplane that runs the controllers,
+// so that several replicas may be deployed without reconciling, and creating
+// cloud resources, twice.
+package leader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/crossplaneio/crossplane/pkg/logging"
+)
+
+// LabelLeader is the label of the pod of the leader. The admission webhook
+// service selects it, since the webhook server only runs on the leader. The
+// label is removed when a replica starts, and when it loses its leadership.
+// Every replica is labelled if leader election is disabled.
+const LabelLeader = "core.crossplane.io/leader"
+
+// Leader election timing, as used by the Kubernetes controller manager.
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+var log = logging.Log.WithName("leader")
+
+// Options configure leader election.
+type Options struct {
+	// Enabled elects a leader. Every replica runs the controllers if leader
+	// election is disabled.
+	Enabled bool
+
+	// Namespace of the config map that holds the leader election lock.
+	Namespace string
+
+	// ID is the name of the config map that holds the leader election lock.
+	ID string
+
+	// PodNamespace and PodName identify the pod of this replica, which is
+	// labelled with LabelLeader while it is the leader. The pod is not
+	// labelled if PodName is empty.
+	PodNamespace string
+	PodName      string
+}
+
+// An Elector runs a function while this replica is the leader.
+type Elector struct {
+	options  Options
+	client   kubernetes.Interface
+	recorder record.EventRecorder
+	identity string
+
+	elected int32
+	leading int32
+}
+
+// NewElector returns an Elector that holds its lock with the supplied client,
+// and records leader election events with the supplied recorder.
+func NewElector(c kubernetes.Interface, r record.EventRecorder, o Options) *Elector {
+	host, _ := os.Hostname()
+	return &Elector{options: o, client: c, recorder: r, identity: host + "_" + string(uuid.NewUUID())}
+}
+
+// Leading returns true while this replica is the leader, or if leader election
+// is disabled.
+func (e *Elector) Leading() bool {
+	return atomic.LoadInt32(&e.leading) == 1
+}
+
+// Run blocks until this replica is elected leader, then runs the supplied
+// function. The function must return once the channel passed to it is closed,
+// which happens when the supplied channel is closed or when this replica loses
+// its leadership. Run returns once the function has returned, and returns an
+// error if the function failed or if the leadership was lost. It returns
+// immediately if the supplied channel is closed before this replica is
+// elected.
+func (e *Elector) Run(stop <-chan struct{}, run func(stop <-chan struct{}) error) error {
+	if !e.options.Enabled {
+		atomic.StoreInt32(&e.leading, 1)
+		return e.lead(stop, run)
+	}
+
+	select {
+	case <-stop:
+		return nil
+	default:
+	}
+
+	// A container restarted in the pod of a former leader inherits its label,
+	// which must not select this replica until it is elected.
+	if err := e.label(false); err != nil {
+		return err
+	}
+
+	lock, err := resourcelock.New(resourcelock.ConfigMapsResourceLock, e.options.Namespace, e.options.ID, e.client.CoreV1(),
+		resourcelock.ResourceLockConfig{Identity: e.identity, EventRecorder: e.recorder})
+	if err != nil {
+		return errors.Wrap(err, "cannot create leader election lock")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	done := make(chan error, 1)
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				atomic.StoreInt32(&e.elected, 1)
+				atomic.StoreInt32(&e.leading, 1)
+				log.Info("elected leader", "identity", e.identity)
+				done <- e.lead(ctx.Done(), run)
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				atomic.StoreInt32(&e.leading, 0)
+				if err := e.label(false); err != nil {
+					log.Error(err, "cannot remove leader label", "identity", e.identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot create leader elector")
+	}
+
+	log.Info("waiting to be elected leader", "identity", e.identity, "lock", e.options.Namespace+"/"+e.options.ID)
+	le.Run(ctx)
+
+	if atomic.LoadInt32(&e.elected) == 0 {
+		return nil
+	}
+	if err := <-done; err != nil {
+		return err
+	}
+	select {
+	case <-stop:
+		return nil
+	default:
+		return errors.New("lost leadership")
+	}
+}
+
+// lead labels the pod of this replica as the leader, then runs the supplied
+// function.
+func (e *Elector) lead(stop <-chan struct{}, run func(stop <-chan struct{}) error) error {
+	if err := e.label(true); err != nil {
+		return err
+	}
+	return run(stop)
+}
+
+// label adds LabelLeader to, or removes it from, the pod of this replica.
+func (e *Elector) label(leader bool) error {
+	if e.options.PodName == "" {
+		return nil
+	}
+	value := "null"
+	if leader {
+		value = `"true"`
+	}
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:%s}}}`, LabelLeader, value))
+	_, err := e.client.CoreV1().Pods(e.options.PodNamespace).Patch(e.options.PodName, types.StrategicMergePatchType, patch)
+	return errors.Wrapf(err, "cannot set label %s of pod %s to %t", LabelLeader, e.options.PodName, leader)
+}
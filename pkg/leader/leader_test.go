@@ -0,0 +1,160 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leader
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	namespace = "crossplane-system"
+	podName   = "crossplane-0"
+)
+
+func options(enabled bool) Options {
+	return Options{Enabled: enabled, Namespace: namespace, ID: "test-lock", PodNamespace: namespace, PodName: podName}
+}
+
+// newClientset returns a fake clientset tracking the supplied objects. Patches
+// of pods are applied to a new pod, because the fake object tracker applies
+// them to the tracked pod, which keeps the labels the patch removes.
+func newClientset(objs ...runtime.Object) *fake.Clientset {
+	o := clienttesting.NewObjectTracker(scheme.Scheme, scheme.Codecs.UniversalDecoder())
+	for _, obj := range objs {
+		if err := o.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+
+	c := &fake.Clientset{}
+	c.AddReactor("*", "*", clienttesting.ObjectReaction(o))
+	c.PrependReactor("patch", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		a := action.(clienttesting.PatchAction)
+		gvr := corev1.SchemeGroupVersion.WithResource("pods")
+		obj, err := o.Get(gvr, a.GetNamespace(), a.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		old, err := json.Marshal(obj)
+		if err != nil {
+			return true, nil, err
+		}
+		patched, err := strategicpatch.StrategicMergePatch(old, a.GetPatch(), &corev1.Pod{})
+		if err != nil {
+			return true, nil, err
+		}
+		pod := &corev1.Pod{}
+		if err := json.Unmarshal(patched, pod); err != nil {
+			return true, nil, err
+		}
+		return true, pod, o.Update(gvr, pod, a.GetNamespace())
+	})
+	return c
+}
+
+func TestRun(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, enabled := range []bool{false, true} {
+		kube := newClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: podName}})
+		e := NewElector(kube, record.NewFakeRecorder(10), options(enabled))
+
+		// test: the function runs on the leader until stopped, while the pod
+		// of the leader is labelled
+		leading := false
+		var labels map[string]string
+		stop := make(chan struct{})
+		err := e.Run(stop, func(s <-chan struct{}) error {
+			leading = e.Leading()
+			pod, err := kube.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			labels = pod.GetLabels()
+			close(stop)
+			<-s
+			return nil
+		})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(leading).To(BeTrue())
+		g.Expect(labels).To(HaveKeyWithValue(LabelLeader, "true"))
+	}
+}
+
+func TestRunUnlabel(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: podName, Labels: map[string]string{LabelLeader: "true"}}}
+	kube := newClientset(pod)
+	e := NewElector(kube, record.NewFakeRecorder(10), options(true))
+
+	// test: the label inherited from a former leader is removed before this
+	// replica is elected, and removed again once it stops leading
+	stop := make(chan struct{})
+	err := e.Run(stop, func(s <-chan struct{}) error {
+		close(stop)
+		<-s
+		return nil
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	pod, err = kube.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pod.GetLabels()).NotTo(HaveKey(LabelLeader))
+}
+
+func TestRunError(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	errBoom := errors.New("boom")
+	kube := newClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: podName}})
+	e := NewElector(kube, record.NewFakeRecorder(10), options(true))
+
+	// test: errors of the function are returned
+	err := e.Run(make(chan struct{}), func(<-chan struct{}) error { return errBoom })
+	g.Expect(err).To(Equal(errBoom))
+}
+
+func TestRunStopped(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	kube := fake.NewSimpleClientset()
+	e := NewElector(kube, record.NewFakeRecorder(10), options(true))
+
+	// test: the function does not run if stopped before this replica is elected
+	stop := make(chan struct{})
+	close(stop)
+	ran := false
+	err := e.Run(stop, func(<-chan struct{}) error {
+		ran = true
+		return nil
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(ran).To(BeFalse())
+	g.Expect(e.Leading()).To(BeFalse())
+}
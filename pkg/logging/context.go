@@ -0,0 +1,108 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+// AnnotationCorrelationID is the annotation of the events recorded during a
+// reconcile that holds the correlation ID of the reconcile.
+const AnnotationCorrelationID = "core.crossplane.io/correlation-id"
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	correlationIDKey
+)
+
+// NewContext returns a context for a reconcile of the supplied request. The
+// context carries a new correlation ID, and a logger derived from the supplied
+// controller logger that labels every message with the request and the
+// correlation ID. A nil controller logger is replaced by Log.
+func NewContext(ctx context.Context, log logr.Logger, request reconcile.Request) context.Context {
+	if log == nil {
+		log = Log
+	}
+	id := string(uuid.NewUUID())
+	log = log.WithValues("request", request.NamespacedName.String(), "correlationID", id)
+	return context.WithValue(context.WithValue(ctx, correlationIDKey, id), loggerKey, log)
+}
+
+// FromContext returns the logger of the reconcile the supplied context was
+// created for, or Log if the context was not created by NewContext.
+func FromContext(ctx context.Context) logr.Logger {
+	if log, ok := ctx.Value(loggerKey).(logr.Logger); ok {
+		return log
+	}
+	return Log
+}
+
+// CorrelationID returns the correlation ID of the reconcile the supplied
+// context was created for, or an empty string if the context was not created
+// by NewContext.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// Reconciling logs, at debug level, that the reconcile the supplied context
+// was created for is reconciling the supplied object. The message is labelled
+// with the claim the object is, or is bound to, so that the reconciles of a
+// claim may be correlated with the reconciles of its managed resource.
+func Reconciling(ctx context.Context, obj runtime.Object) {
+	WithClaim(FromContext(ctx), obj).V(Debug).Info("reconciling")
+}
+
+// WithClaim returns a logger that labels every message with the UID of the
+// resource claim the supplied object is, or is bound to, if any. The log lines
+// of a claim and of the managed resource bound to it may be correlated by it.
+// A nil logger is replaced by Log.
+func WithClaim(log logr.Logger, obj runtime.Object) logr.Logger {
+	if log == nil {
+		log = Log
+	}
+	switch o := obj.(type) {
+	case corev1alpha1.ResourceClaim:
+		return log.WithValues("claim", o.GetNamespace()+"/"+o.GetName(), "claimUID", string(o.GetUID()))
+	case corev1alpha1.Resource:
+		if ref := o.ClaimRef(); ref != nil {
+			return log.WithValues("claim", ref.Namespace+"/"+ref.Name, "claimUID", string(ref.UID))
+		}
+	}
+	return log
+}
+
+// RecordEvent records an event about the supplied object, annotated with the
+// correlation ID of the reconcile the supplied context was created for.
+func RecordEvent(ctx context.Context, r record.EventRecorder, obj runtime.Object, eventtype, reason, message string) {
+	id := CorrelationID(ctx)
+	if id == "" {
+		r.Event(obj, eventtype, reason, message)
+		return
+	}
+	r.AnnotatedEventf(obj, map[string]string{AnnotationCorrelationID: id}, eventtype, reason, "%s", message)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging provides the structured, leveled logger used by Crossplane
+// controllers and cloud clients, and the correlation IDs that tie the log
+// lines, cloud API requests and events of a single reconcile together.
+package logging
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// Debug is the verbosity of debug messages, e.g. log.V(logging.Debug).Info().
+// They are only emitted at the debug log level.
+const Debug = 1
+
+// Log is the root logger of Crossplane. It is shared with controller-runtime,
+// and discards all messages until a logger is set with SetLogger.
+var Log logr.Logger = logf.Log
+
+// SetLogger sets the logger that Log, and all loggers derived from it,
+// delegate to.
+func SetLogger(l logr.Logger) {
+	logf.SetLogger(l)
+}
+
+// New returns a logger that writes JSON encoded messages at or above the
+// supplied level, i.e. one of debug, info, warn or error, to stderr.
+func New(level string) (logr.Logger, error) {
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, errors.Wrapf(err, "invalid log level %q", level)
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(l)
+	z, err := cfg.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot build logger")
+	}
+	return zapr.NewLogger(z), nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var request = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test-resource"}}
+
+func TestNew(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	for _, level := range []string{"debug", "info", "warn", "error"} {
+		l, err := New(level)
+		g.Expect(err).NotTo(HaveOccurred(), level)
+		g.Expect(l).NotTo(BeNil(), level)
+	}
+
+	_, err := New("verbose")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestContext(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// test: contexts not created for a reconcile fall back to the root logger
+	g.Expect(FromContext(context.Background())).To(Equal(Log))
+	g.Expect(CorrelationID(context.Background())).To(BeEmpty())
+
+	// test: each reconcile has its own correlation ID
+	a := NewContext(context.Background(), nil, request)
+	b := NewContext(context.Background(), Log.WithName("test"), request)
+	g.Expect(FromContext(a)).NotTo(BeNil())
+	g.Expect(CorrelationID(a)).NotTo(BeEmpty())
+	g.Expect(CorrelationID(b)).NotTo(BeEmpty())
+	g.Expect(CorrelationID(a)).NotTo(Equal(CorrelationID(b)))
+}
+
+func TestRecordEvent(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-resource"}}
+	r := record.NewFakeRecorder(2)
+
+	RecordEvent(context.Background(), r, obj, corev1.EventTypeWarning, "TestReason", "test message")
+	g.Expect(<-r.Events).To(Equal("Warning TestReason test message"))
+
+	RecordEvent(NewContext(context.Background(), nil, request), r, obj, corev1.EventTypeNormal, "TestReason", "test message")
+	g.Expect(<-r.Events).To(HavePrefix("Normal TestReason"))
+}
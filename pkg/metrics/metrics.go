@@ -0,0 +1,202 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics exposed by Crossplane. The
+// metrics are registered with the controller-runtime registry, and served by
+// the manager alongside the metrics of controller-runtime itself.
+package metrics
+
+import (
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+const namespace = "crossplane"
+
+// Reconcile results.
+const (
+	ResultSuccess = "success"
+	ResultRequeue = "requeue"
+	ResultError   = "error"
+)
+
+var (
+	// ReconcileTotal counts the reconciles of each controller by result.
+	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "reconcile_total",
+		Help:      "Total number of reconciles per controller and result.",
+	}, []string{"controller", "result"})
+
+	// ReconcileErrors counts the failed reconciles of each controller, whether
+	// they returned an error or recorded the failure in a condition.
+	ReconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "reconcile_errors_total",
+		Help:      "Total number of failed reconciles per controller.",
+	}, []string{"controller"})
+
+	// ReconcileDuration observes how long the reconciles of each controller
+	// take.
+	ReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of reconciles per controller.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"controller"})
+
+	// ReconcilesInFlight counts the reconciles of each controller that are in
+	// progress.
+	ReconcilesInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "controller",
+		Name:      "reconciles_in_flight",
+		Help:      "Number of reconciles in progress per controller.",
+	}, []string{"controller"})
+
+	// TimeToReady observes how long managed resources of each kind take to
+	// become ready after they are created.
+	TimeToReady = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "managed_resource",
+		Name:      "time_to_ready_seconds",
+		Help:      "Time from the creation of a managed resource until it first becomes ready, per kind.",
+		// 10 seconds to about 3 hours
+		Buckets: prometheus.ExponentialBuckets(10, 2, 11),
+	}, []string{"kind"})
+
+	// CloudAPIRequestDuration observes the latency of the cloud provider API
+	// requests made by each method of each client.
+	CloudAPIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "cloud_api",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of cloud provider API requests per client and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"client", "method"})
+
+	// CloudAPIRequestErrors counts the cloud provider API requests made by
+	// each method of each client that failed, by error class.
+	CloudAPIRequestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cloud_api",
+		Name:      "request_errors_total",
+		Help:      "Total number of failed cloud provider API requests per client, method and error class.",
+	}, []string{"client", "method", "class"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcileTotal,
+		ReconcileErrors,
+		ReconcileDuration,
+		ReconcilesInFlight,
+		TimeToReady,
+		CloudAPIRequestDuration,
+		CloudAPIRequestErrors,
+	)
+}
+
+// inFlight is the number of reconciles in progress across all controllers.
+var inFlight int64
+
+// instrumentedReconciler records metrics about the reconciles of a controller.
+type instrumentedReconciler struct {
+	controller string
+	reconciler reconcile.Reconciler
+}
+
+// InstrumentReconciler returns a reconciler that records the number, result
+// and duration of the reconciles of the supplied reconciler, labelled with the
+// supplied controller name.
+func InstrumentReconciler(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &instrumentedReconciler{controller: controller, reconciler: r}
+}
+
+// Reconcile the supplied request, recording metrics about the reconcile.
+func (i *instrumentedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	atomic.AddInt64(&inFlight, 1)
+	ReconcilesInFlight.WithLabelValues(i.controller).Inc()
+	defer func() {
+		ReconcilesInFlight.WithLabelValues(i.controller).Dec()
+		atomic.AddInt64(&inFlight, -1)
+	}()
+
+	start := time.Now()
+	result, err := i.reconciler.Reconcile(request)
+	ReconcileDuration.WithLabelValues(i.controller).Observe(time.Since(start).Seconds())
+
+	switch {
+	case err != nil:
+		ReconcileErrors.WithLabelValues(i.controller).Inc()
+		ReconcileTotal.WithLabelValues(i.controller, ResultError).Inc()
+	case result.Requeue || result.RequeueAfter > 0:
+		ReconcileTotal.WithLabelValues(i.controller, ResultRequeue).Inc()
+	default:
+		ReconcileTotal.WithLabelValues(i.controller, ResultSuccess).Inc()
+	}
+	return result, err
+}
+
+// WaitForReconciles blocks until no instrumented reconciles are in progress,
+// or until the supplied timeout expires. It returns false if reconciles were
+// still in progress when the timeout expired. It is intended to be called once
+// the controllers have been stopped, so that the reconciles they were running
+// may record the state of the cloud operations they started.
+func WaitForReconciles(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&inFlight) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
+
+// ObserveReady records the time the supplied managed resource took to become
+// ready, if it has never been ready before. It must be called before the Ready
+// condition of the supplied status is set. Resources are labelled with the
+// name of their Go type, e.g. RDSInstance.
+func ObserveReady(obj metav1.Object, status *corev1alpha1.ConditionedStatus) {
+	if status.Condition(corev1alpha1.Ready) != nil {
+		return
+	}
+	kind := reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	TimeToReady.WithLabelValues(kind).Observe(time.Since(obj.GetCreationTimestamp().Time).Seconds())
+}
+
+// ObserveCloudAPIRequest records a request made by the supplied method of the
+// supplied cloud provider client that started at the supplied time. Failed
+// requests are counted by the supplied error class, which is empty if the
+// request succeeded.
+func ObserveCloudAPIRequest(client, method string, start time.Time, class string) {
+	CloudAPIRequestDuration.WithLabelValues(client, method).Observe(time.Since(start).Seconds())
+	if class != "" {
+		CloudAPIRequestErrors.WithLabelValues(client, method, class).Inc()
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+// counterValue returns the current value of the supplied counter.
+func counterValue(c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	if err := c.Write(m); err != nil {
+		return -1
+	}
+	return m.GetCounter().GetValue()
+}
+
+// sampleCount returns the number of observations of the supplied histogram.
+func sampleCount(o prometheus.Observer) uint64 {
+	m := &dto.Metric{}
+	if err := o.(prometheus.Metric).Write(m); err != nil {
+		return 0
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+type mockReconciler struct {
+	result reconcile.Result
+	err    error
+}
+
+func (m *mockReconciler) Reconcile(_ reconcile.Request) (reconcile.Result, error) {
+	return m.result, m.err
+}
+
+func TestInstrumentReconciler(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		name       string
+		reconciler *mockReconciler
+		result     string
+		errors     float64
+	}{
+		{"success", &mockReconciler{}, ResultSuccess, 0},
+		{"requeue", &mockReconciler{result: reconcile.Result{RequeueAfter: time.Second}}, ResultRequeue, 0},
+		{"error", &mockReconciler{err: fmt.Errorf("test-error")}, ResultError, 1},
+	}
+
+	for _, tc := range cases {
+		controller := "test-" + tc.name
+		r := InstrumentReconciler(controller, tc.reconciler)
+
+		result, err := r.Reconcile(reconcile.Request{})
+		g.Expect(result).To(Equal(tc.reconciler.result), tc.name)
+		if tc.reconciler.err == nil {
+			g.Expect(err).NotTo(HaveOccurred(), tc.name)
+		} else {
+			g.Expect(err).To(Equal(tc.reconciler.err), tc.name)
+		}
+
+		g.Expect(counterValue(ReconcileTotal.WithLabelValues(controller, tc.result))).To(Equal(float64(1)), tc.name)
+		g.Expect(counterValue(ReconcileErrors.WithLabelValues(controller))).To(Equal(tc.errors), tc.name)
+		g.Expect(sampleCount(ReconcileDuration.WithLabelValues(controller))).To(Equal(uint64(1)), tc.name)
+	}
+}
+
+type blockingReconciler struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingReconciler) Reconcile(_ reconcile.Request) (reconcile.Result, error) {
+	close(b.started)
+	<-b.release
+	return reconcile.Result{}, nil
+}
+
+func TestWaitForReconciles(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// test: there is nothing to wait for without reconciles in progress
+	g.Expect(WaitForReconciles(0)).To(BeTrue())
+
+	b := &blockingReconciler{started: make(chan struct{}), release: make(chan struct{})}
+	r := InstrumentReconciler("test-in-flight", b)
+	go r.Reconcile(reconcile.Request{}) // nolint:errcheck
+	<-b.started
+
+	// test: reconciles in progress are waited for until the timeout expires
+	g.Expect(WaitForReconciles(10 * time.Millisecond)).To(BeFalse())
+
+	close(b.release)
+	g.Expect(WaitForReconciles(time.Minute)).To(BeTrue())
+}
+
+func TestObserveReady(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	res := &corev1alpha1.ExternalResource{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Minute))},
+	}
+	observed := TimeToReady.WithLabelValues("ExternalResource")
+	before := sampleCount(observed)
+
+	// test: the first time a resource becomes ready is observed
+	ObserveReady(res, &res.Status.ConditionedStatus)
+	g.Expect(sampleCount(observed)).To(Equal(before + 1))
+
+	// test: resources that have been ready before are not observed again
+	res.Status.SetReady()
+	res.Status.UnsetAllConditions()
+	ObserveReady(res, &res.Status.ConditionedStatus)
+	g.Expect(sampleCount(observed)).To(Equal(before + 1))
+}
+
+func TestObserveCloudAPIRequest(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	ObserveCloudAPIRequest("test/client", "Get", time.Now(), "")
+	ObserveCloudAPIRequest("test/client", "Get", time.Now(), "NotFound")
+
+	g.Expect(sampleCount(CloudAPIRequestDuration.WithLabelValues("test/client", "Get"))).To(Equal(uint64(2)))
+	g.Expect(counterValue(CloudAPIRequestErrors.WithLabelValues("test/client", "Get", "NotFound"))).To(Equal(float64(1)))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/logging"
+)
+
+var (
+	claimsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "claims"),
+		"Number of resource claims per kind and binding phase.",
+		[]string{"kind", "phase"}, nil)
+
+	resourcesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "managed_resources"),
+		"Number of managed resources per kind and binding phase.",
+		[]string{"kind", "phase"}, nil)
+)
+
+var phases = []corev1alpha1.BindingState{
+	corev1alpha1.BindingStateUnbound,
+	corev1alpha1.BindingStateBound,
+	corev1alpha1.BindingStateReleased,
+}
+
+// AddToManager registers a collector that reports the number of resource
+// claims and managed resources in each binding phase, as read from the cache
+// of the supplied manager.
+func AddToManager(mgr manager.Manager) error {
+	return metrics.Registry.Register(NewPhaseCollector(mgr.GetClient(), mgr.GetScheme()))
+}
+
+// A PhaseCollector reports the number of resource claims and managed resources
+// of each kind registered with its scheme that are in each binding phase. The
+// objects are listed each time the collector is scraped.
+type PhaseCollector struct {
+	client client.Client
+	scheme *runtime.Scheme
+}
+
+// NewPhaseCollector returns a PhaseCollector that lists objects with the
+// supplied client.
+func NewPhaseCollector(c client.Client, s *runtime.Scheme) *PhaseCollector {
+	return &PhaseCollector{client: c, scheme: s}
+}
+
+// Describe the metrics reported by the collector.
+func (c *PhaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- claimsDesc
+	ch <- resourcesDesc
+}
+
+// Collect the number of claims and resources in each binding phase.
+func (c *PhaseCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, gvk := range c.kinds() {
+		obj, err := c.scheme.New(gvk)
+		if err != nil {
+			continue
+		}
+
+		var desc *prometheus.Desc
+		switch obj.(type) {
+		case corev1alpha1.ResourceClaim:
+			desc = claimsDesc
+		case corev1alpha1.Resource:
+			desc = resourcesDesc
+		default:
+			continue
+		}
+
+		counts, err := c.count(gvk)
+		if err != nil {
+			logging.Log.WithName("metrics").Error(err, "cannot count objects in each binding phase", "kind", gvk.Kind)
+			continue
+		}
+		for _, p := range phases {
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(counts[p]), gvk.Kind, p.String())
+		}
+	}
+}
+
+// kinds returns the kinds registered with the scheme of the collector, sorted
+// so that metrics are reported in a stable order.
+func (c *PhaseCollector) kinds() []schema.GroupVersionKind {
+	kinds := make([]schema.GroupVersionKind, 0)
+	for gvk := range c.scheme.AllKnownTypes() {
+		kinds = append(kinds, gvk)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// count the objects of the supplied kind in each binding phase.
+func (c *PhaseCollector) count(gvk schema.GroupVersionKind) (map[corev1alpha1.BindingState]int, error) {
+	list, err := c.scheme.New(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err != nil {
+		return nil, err
+	}
+	if err := c.client.List(context.Background(), &client.ListOptions{}, list); err != nil {
+		return nil, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[corev1alpha1.BindingState]int{}
+	for _, item := range items {
+		counts[phase(item)]++
+	}
+	return counts, nil
+}
+
+// phase returns the binding phase of the supplied claim or resource.
+func phase(obj runtime.Object) corev1alpha1.BindingState {
+	switch o := obj.(type) {
+	case corev1alpha1.ResourceClaim:
+		return o.ClaimStatus().Phase
+	case corev1alpha1.Resource:
+		switch {
+		case o.IsReleased():
+			return corev1alpha1.BindingStateReleased
+		case o.IsBound():
+			return corev1alpha1.BindingStateBound
+		}
+	}
+	return corev1alpha1.BindingStateUnbound
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+)
+
+func TestPhase(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	claim := &storagev1alpha1.MySQLInstance{}
+	g.Expect(phase(claim)).To(Equal(corev1alpha1.BindingStateUnbound))
+	claim.Status.SetBound(true)
+	g.Expect(phase(claim)).To(Equal(corev1alpha1.BindingStateBound))
+
+	res := &corev1alpha1.ExternalResource{}
+	g.Expect(phase(res)).To(Equal(corev1alpha1.BindingStateUnbound))
+	res.Status.SetBound(true)
+	g.Expect(phase(res)).To(Equal(corev1alpha1.BindingStateBound))
+	res.Status.SetReleased()
+	g.Expect(phase(res)).To(Equal(corev1alpha1.BindingStateReleased))
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package requeue decides when controllers reconcile an object again. Failed
+// reconciles are retried with per-object exponential backoff, resources that
+// are still being created are polled at a fixed interval, and terminal errors
+// are not retried at all.
+package requeue
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"reflect"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplaneio/crossplane/pkg/metrics"
+)
+
+// Options configure a requeue policy.
+type Options struct {
+	// BackoffBase is the delay before the first retry of a failed reconcile.
+	// The delay doubles with each consecutive failure.
+	BackoffBase time.Duration
+
+	// BackoffMax is the longest delay between retries of a failed reconcile.
+	BackoffMax time.Duration
+
+	// PollInterval is the delay between reconciles of a resource that is
+	// waiting for a long-running operation, such as its creation, to finish.
+	PollInterval time.Duration
+}
+
+// Defaults are the options of policies created by controllers. They may be
+// changed before controllers are added to the manager.
+var Defaults = Options{
+	BackoffBase:  1 * time.Second,
+	BackoffMax:   5 * time.Minute,
+	PollInterval: 30 * time.Second,
+}
+
+// terminal wraps errors that should not be retried.
+type terminal struct {
+	error
+}
+
+func (t terminal) Terminal() bool {
+	return true
+}
+
+func (t terminal) Cause() error {
+	return t.error
+}
+
+// Terminal marks the supplied error as terminal: reconciling the object again
+// will not succeed until the object is changed, so it is not requeued.
+func Terminal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return terminal{err}
+}
+
+// IsTerminal returns true if the supplied error, or any error it wraps, is
+// terminal. Errors are terminal if they implement a Terminal method that
+// returns true. Wrapped errors are found through their Cause method, as
+// implemented by github.com/pkg/errors.
+func IsTerminal(err error) bool {
+	for err != nil {
+		if t, ok := err.(interface{ Terminal() bool }); ok && t.Terminal() {
+			return true
+		}
+		c, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = c.Cause()
+	}
+	return false
+}
+
+// Key returns the namespace and name of the supplied object, by which policies
+// track its failures.
+func Key(obj metav1.Object) types.NamespacedName {
+	return types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+}
+
+type failure struct {
+	count int
+	last  time.Time
+	delay time.Duration
+
+	// spec and deleting are the state of the object when it failed.
+	spec     uint64
+	deleting bool
+}
+
+// A Policy tracks the consecutive failures of the objects reconciled by a
+// controller. Failures are consecutive when each occurs within twice the
+// backoff delay of the previous failure, and the object was neither changed
+// nor deleted since. They are counted in the reconcile error metric of the
+// controller. A nil Policy does not track failures; it
+// retries every failure after the base delay of the Defaults.
+type Policy struct {
+	controller string
+	options    Options
+	now        func() time.Time
+	mu         sync.Mutex
+	failures   map[types.NamespacedName]*failure
+}
+
+// NewPolicy returns a new Policy for the named controller with the supplied
+// options.
+func NewPolicy(controller string, o Options) *Policy {
+	return &Policy{controller: controller, options: o, now: time.Now, failures: map[types.NamespacedName]*failure{}}
+}
+
+func (p *Policy) opts() Options {
+	if p == nil {
+		return Defaults
+	}
+	return p.options
+}
+
+// Failed records a failed reconcile of the supplied object and returns a
+// result that retries it after a backoff delay. Objects that failed with a
+// terminal error are not requeued. The error may be nil.
+func (p *Policy) Failed(obj metav1.Object, err error) reconcile.Result {
+	key := Key(obj)
+	if p != nil {
+		metrics.ReconcileErrors.WithLabelValues(p.controller).Inc()
+	}
+
+	if IsTerminal(err) {
+		p.Forget(key)
+		return reconcile.Result{}
+	}
+
+	o := p.opts()
+	if p == nil {
+		return reconcile.Result{RequeueAfter: o.BackoffBase}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	f, ok := p.failures[key]
+	if !ok || now.Sub(f.last) > 2*f.delay || changed(f, obj) {
+		f = &failure{spec: specHash(obj), deleting: obj.GetDeletionTimestamp() != nil}
+		p.failures[key] = f
+	}
+	f.delay = backoff(o, f.count)
+	f.count++
+	f.last = now
+	return reconcile.Result{RequeueAfter: f.delay}
+}
+
+// Pending returns a result that polls the object with the supplied key while
+// it waits for a long-running operation to finish. Any failures of the object
+// are forgotten.
+func (p *Policy) Pending(key types.NamespacedName) reconcile.Result {
+	p.Forget(key)
+	return reconcile.Result{RequeueAfter: p.opts().PollInterval}
+}
+
+// Forget the failures of the object with the supplied key.
+func (p *Policy) Forget(key types.NamespacedName) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.failures, key)
+}
+
+// Wait returns how long the supplied object must wait before its failed
+// reconcile is retried, or zero if it may be reconciled now. Controllers
+// return early when an object that is backing off is reconciled because its
+// status or metadata was updated. Objects whose spec changed, or that are
+// being deleted, since they failed are reconciled now, and their failures
+// are forgotten.
+func (p *Policy) Wait(obj metav1.Object) time.Duration {
+	if p == nil {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := Key(obj)
+	f, ok := p.failures[key]
+	if !ok {
+		return 0
+	}
+	if changed(f, obj) {
+		delete(p.failures, key)
+		return 0
+	}
+	if d := f.last.Add(f.delay).Sub(p.now()); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// changed returns true if the spec of the supplied object changed, or the
+// object began to be deleted, since the supplied failure.
+func changed(f *failure, obj metav1.Object) bool {
+	return f.spec != specHash(obj) || f.deleting != (obj.GetDeletionTimestamp() != nil)
+}
+
+// specHash returns a hash of the Spec field of the supplied object. The
+// generation of an object cannot tell whether its spec changed, because
+// resources without a status subresource get a new generation whenever their
+// status is updated, including when a failure is recorded. Objects without a
+// Spec field always have the same hash.
+func specHash(obj metav1.Object) uint64 {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return 0
+	}
+	spec := v.FieldByName("Spec")
+	if !spec.IsValid() {
+		return 0
+	}
+	b, _ := json.Marshal(spec.Interface())
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+	return h.Sum64()
+}
+
+// backoff returns the delay before retrying an object that has failed the
+// supplied number of times before.
+func backoff(o Options, failures int) time.Duration {
+	d := o.BackoffBase
+	for i := 0; i < failures && d < o.BackoffMax; i++ {
+		d *= 2
+	}
+	if d > o.BackoffMax {
+		return o.BackoffMax
+	}
+	return d
+}
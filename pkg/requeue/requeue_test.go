@@ -0,0 +1,146 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package requeue
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var key = types.NamespacedName{Namespace: "default", Name: "test-resource"}
+
+type resourceSpec struct {
+	Size int `json:"size"`
+}
+
+type resource struct {
+	metav1.ObjectMeta
+	Spec resourceSpec
+}
+
+func object(size int) *resource {
+	return &resource{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}, Spec: resourceSpec{Size: size}}
+}
+
+func TestIsTerminal(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(IsTerminal(nil)).To(BeFalse())
+	g.Expect(IsTerminal(fmt.Errorf("test-error"))).To(BeFalse())
+	g.Expect(Terminal(nil)).To(BeNil())
+	g.Expect(IsTerminal(Terminal(fmt.Errorf("test-error")))).To(BeTrue())
+	g.Expect(IsTerminal(errors.Wrap(Terminal(fmt.Errorf("test-error")), "wrapped"))).To(BeTrue())
+	g.Expect(Terminal(fmt.Errorf("test-error")).Error()).To(Equal("test-error"))
+}
+
+func TestPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := time.Now()
+	p := NewPolicy("test", Options{BackoffBase: time.Second, BackoffMax: 5 * time.Second, PollInterval: time.Minute})
+	p.now = func() time.Time { return now }
+	obj := object(1)
+
+	// test: consecutive failures double the delay up to the maximum
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: 2 * time.Second}))
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: 4 * time.Second}))
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+
+	// test: the object waits until its delay has passed
+	g.Expect(p.Wait(obj)).To(Equal(5 * time.Second))
+	now = now.Add(3 * time.Second)
+	g.Expect(p.Wait(obj)).To(Equal(2 * time.Second))
+	now = now.Add(2 * time.Second)
+	g.Expect(p.Wait(obj)).To(BeZero())
+
+	// test: other objects are tracked separately
+	other := &metav1.ObjectMeta{Namespace: "default", Name: "other-resource"}
+	g.Expect(p.Wait(other)).To(BeZero())
+	g.Expect(p.Failed(other, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+
+	// test: a failure long after the previous one starts a new streak
+	now = now.Add(time.Minute)
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+
+	// test: pending objects are polled and their failures forgotten
+	g.Expect(p.Pending(key)).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+	g.Expect(p.Wait(obj)).To(BeZero())
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+
+	// test: terminal errors are not requeued
+	g.Expect(p.Failed(obj, Terminal(fmt.Errorf("test-error")))).To(Equal(reconcile.Result{}))
+	g.Expect(p.Wait(obj)).To(BeZero())
+
+	// test: an object whose spec changed since it failed is reconciled now,
+	// and its failures are forgotten
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: 2 * time.Second}))
+	changed := object(2)
+	g.Expect(p.Wait(changed)).To(BeZero())
+	g.Expect(p.Wait(obj)).To(BeZero())
+	g.Expect(p.Failed(changed, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+
+	// test: a failure after the spec changed starts a new streak
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+
+	// test: an object that is being deleted since it failed is reconciled now
+	deleted := object(1)
+	deleted.SetDeletionTimestamp(&metav1.Time{Time: now})
+	g.Expect(p.Wait(deleted)).To(BeZero())
+	g.Expect(p.Failed(deleted, nil)).To(Equal(reconcile.Result{RequeueAfter: time.Second}))
+	g.Expect(p.Wait(deleted)).To(Equal(time.Second))
+}
+
+func TestPolicyStatusUpdates(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	now := time.Now()
+	p := NewPolicy("test", Options{BackoffBase: time.Second, BackoffMax: time.Minute, PollInterval: time.Minute})
+	p.now = func() time.Time { return now }
+	obj := object(1)
+
+	// Resources without a status subresource get a new generation each time
+	// their failure is recorded. The reconcile triggered by that update must
+	// still wait, and the backoff must keep growing.
+	for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second} {
+		g.Expect(p.Failed(obj, fmt.Errorf("test-error %d", i))).To(Equal(reconcile.Result{RequeueAfter: want}))
+		obj.Generation++
+		g.Expect(p.Wait(obj)).To(Equal(want))
+		now = now.Add(want)
+	}
+}
+
+func TestNilPolicy(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	var p *Policy
+	obj := object(1)
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: Defaults.BackoffBase}))
+	g.Expect(p.Failed(obj, nil)).To(Equal(reconcile.Result{RequeueAfter: Defaults.BackoffBase}))
+	g.Expect(p.Failed(obj, Terminal(fmt.Errorf("test-error")))).To(Equal(reconcile.Result{}))
+	g.Expect(p.Pending(key)).To(Equal(reconcile.Result{RequeueAfter: Defaults.PollInterval}))
+	g.Expect(p.Wait(obj)).To(BeZero())
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selection
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// groupSuffix is the suffix of the API groups defined by Crossplane.
+const groupSuffix = "crossplane.io"
+
+// VerifyCRDs returns an error that lists the kinds of Crossplane resources
+// registered with the supplied scheme whose CustomResourceDefinitions are not
+// installed, as reported by the supplied discovery client. Controllers of such
+// kinds would otherwise fail to start, or retry listing them forever.
+func VerifyCRDs(d discovery.DiscoveryInterface, s *runtime.Scheme) error {
+	kinds := map[schema.GroupVersion][]string{}
+	for gvk := range s.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Group, groupSuffix) || gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		obj, err := s.New(gvk)
+		if err != nil {
+			return err
+		}
+		if _, err := meta.Accessor(obj); err != nil {
+			// Options and other types that are not resources.
+			continue
+		}
+		kinds[gvk.GroupVersion()] = append(kinds[gvk.GroupVersion()], gvk.Kind)
+	}
+
+	missing := []string{}
+	for gv, ks := range kinds {
+		served := map[string]bool{}
+		l, err := d.ServerResourcesForGroupVersion(gv.String())
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("cannot discover the resources of %s: %s", gv, err)
+		}
+		if l != nil {
+			for _, r := range l.APIResources {
+				served[r.Kind] = true
+			}
+		}
+		for _, k := range ks {
+			if !served[k] {
+				missing = append(missing, k+"."+gv.Group)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("the CustomResourceDefinitions of these kinds are not installed: %s; install them or deselect their providers and kinds with --providers and --kinds",
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selection
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+)
+
+// notFoundDiscovery reports missing group versions with a NotFound status
+// error, like the API server does, rather than the plain error of the fake.
+type notFoundDiscovery struct {
+	*fakediscovery.FakeDiscovery
+}
+
+func (d *notFoundDiscovery) ServerResourcesForGroupVersion(gv string) (*metav1.APIResourceList, error) {
+	for _, l := range d.Resources {
+		if l.GroupVersion == gv {
+			return l, nil
+		}
+	}
+	return nil, errors.NewNotFound(schema.GroupResource{}, gv)
+}
+
+func TestVerifyCRDs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	s := runtime.NewScheme()
+	g.Expect(awsdbv1alpha1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+	g.Expect(storagev1alpha1.SchemeBuilder.AddToScheme(s)).To(Succeed())
+
+	storage := &metav1.APIResourceList{
+		GroupVersion: storagev1alpha1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Kind: "Bucket"}, {Kind: "MySQLInstance"}, {Kind: "PostgreSQLInstance"}},
+	}
+	database := &metav1.APIResourceList{
+		GroupVersion: awsdbv1alpha1.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{{Kind: "RDSInstance"}},
+	}
+
+	// test: the check passes if the CRDs of every registered kind are installed
+	d := &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{Resources: []*metav1.APIResourceList{storage, database}}}
+	g.Expect(VerifyCRDs(d, s)).To(Succeed())
+
+	// test: missing CRDs are listed
+	storage.APIResources = storage.APIResources[:1]
+	d = &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{Resources: []*metav1.APIResourceList{storage}}}
+	err := VerifyCRDs(&notFoundDiscovery{d}, s)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(HavePrefix("the CustomResourceDefinitions of these kinds are not installed: " +
+		"MySQLInstance.storage.crossplane.io, PostgreSQLInstance.storage.crossplane.io, RDSInstance.database.aws.crossplane.io;"))
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package selection selects the cloud providers and kinds of resources that
+// Crossplane manages. Only the API groups and controllers of the selected
+// providers and kinds are registered, so that the CustomResourceDefinitions of
+// other providers and kinds need not be installed.
+package selection
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// Cloud providers.
+const (
+	ProviderAWS   = "aws"
+	ProviderAzure = "azure"
+	ProviderGCP   = "gcp"
+)
+
+// Kinds of resources.
+const (
+	KindCache    = "cache"
+	KindCompute  = "compute"
+	KindDatabase = "database"
+	KindStorage  = "storage"
+)
+
+// Providers are all cloud providers.
+var Providers = []string{ProviderAWS, ProviderAzure, ProviderGCP}
+
+// Kinds are all kinds of resources.
+var Kinds = []string{KindCache, KindCompute, KindDatabase, KindStorage}
+
+// A Selection of cloud providers and kinds of resources.
+type Selection struct {
+	// Providers that are selected. All providers are selected if empty.
+	Providers []string `json:"providers,omitempty"`
+
+	// Kinds of resources that are selected. All kinds are selected if empty.
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// Load the selection in the supplied YAML file, e.g.
+//
+//	providers: [aws]
+//	kinds: [database, storage]
+func Load(path string) (Selection, error) {
+	s := Selection{}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return s, fmt.Errorf("cannot read selection: %s", err)
+	}
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("cannot parse selection %s: %s", path, err)
+	}
+	return s, s.Validate()
+}
+
+// Split the supplied comma separated list of providers or kinds, as accepted
+// on the command line.
+func Split(list string) []string {
+	if strings.TrimSpace(list) == "" {
+		return nil
+	}
+	items := strings.Split(list, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
+
+// Validate returns an error if the selection contains an unknown provider or
+// kind.
+func (s Selection) Validate() error {
+	if err := known("provider", s.Providers, Providers); err != nil {
+		return err
+	}
+	return known("kind", s.Kinds, Kinds)
+}
+
+func known(what string, items, all []string) error {
+	for _, i := range items {
+		if !contains(all, i) {
+			return fmt.Errorf("unknown %s %q, must be one of %s", what, i, strings.Join(all, ", "))
+		}
+	}
+	return nil
+}
+
+// Selected returns true if the supplied provider and kind are selected. An
+// empty provider or kind is always selected, e.g. the kind of the controller
+// of the credentials of a provider, or the provider of the controller of a
+// kind of resource claim.
+func (s Selection) Selected(provider, kind string) bool {
+	return (provider == "" || len(s.Providers) == 0 || contains(s.Providers, provider)) &&
+		(kind == "" || len(s.Kinds) == 0 || contains(s.Kinds, kind))
+}
+
+// String lists the selected providers and kinds.
+func (s Selection) String() string {
+	p, k := s.Providers, s.Kinds
+	if len(p) == 0 {
+		p = Providers
+	}
+	if len(k) == 0 {
+		k = Kinds
+	}
+	return fmt.Sprintf("providers: %s, kinds: %s", join(p), join(k))
+}
+
+func join(items []string) string {
+	sorted := append([]string{}, items...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+// A Group of API types of a provider and kind of resource.
+type Group struct {
+	Provider    string
+	Kind        string
+	AddToScheme func(*runtime.Scheme) error
+}
+
+// AddToScheme adds the supplied groups that are selected to the supplied
+// scheme.
+func (s Selection) AddToScheme(scheme *runtime.Scheme, groups []Group) error {
+	for _, g := range groups {
+		if !s.Selected(g.Provider, g.Kind) {
+			continue
+		}
+		if err := g.AddToScheme(scheme); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A Controller of a provider and kind of resource.
+type Controller struct {
+	Provider string
+	Kind     string
+	Add      func(manager.Manager) error
+}
+
+// AddToManager adds the supplied controllers that are selected to the
+// supplied manager.
+func (s Selection) AddToManager(m manager.Manager, controllers []Controller) error {
+	for _, c := range controllers {
+		if !s.Selected(c.Provider, c.Kind) {
+			continue
+		}
+		if err := c.Add(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selection
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+func TestSelected(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// test: everything is selected by an empty selection
+	all := Selection{}
+	g.Expect(all.Selected(ProviderAzure, KindCache)).To(BeTrue())
+
+	s := Selection{Providers: []string{ProviderAWS}, Kinds: []string{KindDatabase, KindStorage}}
+
+	// test: controllers of the selected providers and kinds are selected
+	g.Expect(s.Selected(ProviderAWS, KindDatabase)).To(BeTrue())
+	g.Expect(s.Selected(ProviderAWS, KindCache)).To(BeFalse())
+	g.Expect(s.Selected(ProviderGCP, KindDatabase)).To(BeFalse())
+
+	// test: controllers without a provider or kind are selected
+	g.Expect(s.Selected(ProviderAWS, "")).To(BeTrue())
+	g.Expect(s.Selected("", KindStorage)).To(BeTrue())
+	g.Expect(s.Selected("", KindCompute)).To(BeFalse())
+	g.Expect(s.Selected(ProviderGCP, "")).To(BeFalse())
+}
+
+func TestValidate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Selection{Providers: []string{ProviderGCP}, Kinds: []string{KindCompute}}.Validate()).To(Succeed())
+	g.Expect(Selection{Providers: []string{"openstack"}}.Validate()).To(MatchError(`unknown provider "openstack", must be one of aws, azure, gcp`))
+	g.Expect(Selection{Kinds: []string{"queue"}}.Validate()).To(MatchError(`unknown kind "queue", must be one of cache, compute, database, storage`))
+}
+
+func TestSplit(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Split("")).To(BeEmpty())
+	g.Expect(Split("aws, gcp")).To(Equal([]string{ProviderAWS, ProviderGCP}))
+}
+
+func TestString(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(Selection{}.String()).To(Equal("providers: aws,azure,gcp, kinds: cache,compute,database,storage"))
+	g.Expect(Selection{Providers: []string{ProviderGCP, ProviderAWS}}.String()).To(Equal("providers: aws,gcp, kinds: cache,compute,database,storage"))
+}
+
+func TestLoad(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "selection")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "selection.yaml")
+	g.Expect(ioutil.WriteFile(path, []byte("providers: [aws]\nkinds: [database, storage]\n"), 0600)).To(Succeed())
+
+	// test: the selection is read from the file
+	s, err := Load(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(s).To(Equal(Selection{Providers: []string{ProviderAWS}, Kinds: []string{KindDatabase, KindStorage}}))
+
+	// test: unknown providers and kinds are rejected
+	g.Expect(ioutil.WriteFile(path, []byte("providers: [openstack]\n"), 0600)).To(Succeed())
+	_, err = Load(path)
+	g.Expect(err).To(HaveOccurred())
+
+	// test: missing files are reported
+	_, err = Load(filepath.Join(dir, "missing.yaml"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestAddToScheme(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	added := []string{}
+	group := func(name string) func(*runtime.Scheme) error {
+		return func(*runtime.Scheme) error {
+			added = append(added, name)
+			return nil
+		}
+	}
+	groups := []Group{
+		{Provider: ProviderAWS, AddToScheme: group("aws")},
+		{Provider: ProviderAWS, Kind: KindDatabase, AddToScheme: group("aws-database")},
+		{Provider: ProviderGCP, AddToScheme: group("gcp")},
+		{AddToScheme: group("core")},
+	}
+
+	// test: only the selected groups are added to the scheme
+	s := Selection{Providers: []string{ProviderAWS}, Kinds: []string{KindCompute}}
+	g.Expect(s.AddToScheme(runtime.NewScheme(), groups)).To(Succeed())
+	g.Expect(added).To(Equal([]string{"aws", "core"}))
+}
+
+func TestAddToManager(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	errBoom := errors.New("boom")
+	added := []string{}
+	controller := func(name string, err error) func(manager.Manager) error {
+		return func(manager.Manager) error {
+			added = append(added, name)
+			return err
+		}
+	}
+	controllers := []Controller{
+		{Provider: ProviderAWS, Kind: KindDatabase, Add: controller("rds", nil)},
+		{Provider: ProviderAWS, Kind: KindStorage, Add: controller("s3", nil)},
+		{Kind: KindDatabase, Add: controller("mysql", nil)},
+		{Provider: ProviderAzure, Kind: KindDatabase, Add: controller("mysqlserver", errBoom)},
+	}
+
+	// test: only the selected controllers are added to the manager
+	s := Selection{Kinds: []string{KindDatabase}, Providers: []string{ProviderAWS}}
+	g.Expect(s.AddToManager(nil, controllers)).To(Succeed())
+	g.Expect(added).To(Equal([]string{"rds", "mysql"}))
+
+	// test: errors adding a controller are returned
+	g.Expect(Selection{}.AddToManager(nil, controllers)).To(Equal(errBoom))
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing records distributed traces of reconciles and of the cloud
+// provider API requests they make. Traces are recorded with OpenTelemetry and
+// exported to a Jaeger collector, or to an OpenTelemetry collector with a
+// Jaeger receiver.
+//
+// The trace of a claim reconcile continues in the reconciles of the managed
+// resource it provisions: the span context of the provisioning reconcile is
+// stored in an annotation of the resource, from which the resource controller
+// starts its spans.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationSpanContext is the annotation of a managed resource that holds the
+// span context of the claim reconcile that provisioned it, in the W3C
+// traceparent format.
+const AnnotationSpanContext = "core.crossplane.io/span-context"
+
+// instrumentationName is the name of the tracer spans are started with.
+const instrumentationName = "github.com/crossplaneio/crossplane"
+
+// traceparent is the key of the span context in W3C trace context carriers.
+const traceparent = "traceparent"
+
+// Exporters of traces.
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = ""
+
+	// ExporterJaeger exports traces to a Jaeger collector, or to an
+	// OpenTelemetry collector with a Jaeger receiver.
+	ExporterJaeger = "jaeger"
+)
+
+// Span attribute keys.
+const (
+	AttributeKind      = "crossplane.kind"
+	AttributeNamespace = "crossplane.namespace"
+	AttributeName      = "crossplane.name"
+	AttributeProvider  = "crossplane.provider"
+)
+
+// Options configure the exporting of traces.
+type Options struct {
+	// Exporter of traces, i.e. ExporterNone or ExporterJaeger.
+	Exporter string
+
+	// Endpoint is the HTTP endpoint of the collector traces are exported to,
+	// e.g. http://localhost:14268/api/traces.
+	Endpoint string
+
+	// SampleRate is the fraction of reconciles that are traced.
+	SampleRate float64
+}
+
+// Setup registers the tracer provider configured by the supplied options.
+// The returned function flushes and stops the exporting of traces.
+func Setup(o Options) (func(), error) {
+	switch o.Exporter {
+	case ExporterNone:
+		return func() {}, nil
+	case ExporterJaeger:
+		e, err := jaeger.New(jaeger.WithCollectorEndpoint(jaeger.WithEndpoint(o.Endpoint)))
+		if err != nil {
+			return nil, fmt.Errorf("cannot create trace exporter: %s", err)
+		}
+		tp := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(e),
+			sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.SampleRate))),
+			sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("crossplane"))),
+		)
+		otel.SetTracerProvider(tp)
+		return func() {
+			tp.Shutdown(context.Background()) // nolint:errcheck
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown trace exporter %q", o.Exporter)
+}
+
+// Tracer returns the tracer that spans of reconciles and cloud provider API
+// requests are started with.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span of the reconcile of the supplied object, labelled
+// with the kind, namespace, name and cloud provider of the object. The span is
+// a child of the span in the supplied context, if any, or else of the span
+// stored in the AnnotationSpanContext annotation of the object.
+func StartSpan(ctx context.Context, name string, obj runtime.Object) (context.Context, trace.Span) {
+	if sc, ok := spanContext(obj); ok && !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attributes(obj)...))
+}
+
+// End ends the supplied span, recording the supplied error, if any, as its
+// status.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Inject stores the span context of the supplied context in the
+// AnnotationSpanContext annotation of the supplied object, so that the span
+// may be continued by the controller of the object.
+func Inject(ctx context.Context, obj runtime.Object) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	c := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, c)
+	a := o.GetAnnotations()
+	if a == nil {
+		a = map[string]string{}
+	}
+	a[AnnotationSpanContext] = c.Get(traceparent)
+	o.SetAnnotations(a)
+}
+
+// Forget removes the AnnotationSpanContext annotation from the supplied
+// object, so that its later reconciles start new traces. It is intended to be
+// called once the external resource of the object has been created.
+func Forget(obj runtime.Object) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return
+	}
+	a := o.GetAnnotations()
+	if _, ok := a[AnnotationSpanContext]; !ok {
+		return
+	}
+	delete(a, AnnotationSpanContext)
+	o.SetAnnotations(a)
+}
+
+// NewClient returns a client that injects the span context of the supplied
+// context into every object it creates.
+func NewClient(ctx context.Context, c client.Client) client.Client {
+	return &injectingClient{Client: c, ctx: ctx}
+}
+
+type injectingClient struct {
+	client.Client
+	ctx context.Context
+}
+
+func (c *injectingClient) Create(ctx context.Context, obj runtime.Object) error {
+	Inject(c.ctx, obj)
+	return c.Client.Create(ctx, obj)
+}
+
+// spanContext returns the span context stored in the AnnotationSpanContext
+// annotation of the supplied object.
+func spanContext(obj runtime.Object) (trace.SpanContext, bool) {
+	o, err := meta.Accessor(obj)
+	if err != nil {
+		return trace.SpanContext{}, false
+	}
+	v, ok := o.GetAnnotations()[AnnotationSpanContext]
+	if !ok {
+		return trace.SpanContext{}, false
+	}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{traceparent: v})
+	sc := trace.SpanContextFromContext(ctx)
+	return sc, sc.IsValid()
+}
+
+type referencer interface {
+	ObjectReference() *corev1.ObjectReference
+}
+
+// attributes returns the span attributes that identify the supplied object.
+func attributes(obj runtime.Object) []attribute.KeyValue {
+	r, ok := obj.(referencer)
+	if !ok {
+		return nil
+	}
+	ref := r.ObjectReference()
+	attrs := []attribute.KeyValue{
+		attribute.String(AttributeKind, ref.Kind),
+		attribute.String(AttributeNamespace, ref.Namespace),
+		attribute.String(AttributeName, ref.Name),
+	}
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil {
+		if p := provider(gv.Group); p != "" {
+			attrs = append(attrs, attribute.String(AttributeProvider, p))
+		}
+	}
+	return attrs
+}
+
+// provider returns the cloud provider of the kinds of the supplied API group,
+// e.g. aws for database.aws.crossplane.io, or an empty string for provider
+// agnostic groups such as storage.crossplane.io.
+func provider(group string) string {
+	parts := strings.Split(strings.TrimSuffix(group, ".crossplane.io"), ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[len(parts)-1]
+}
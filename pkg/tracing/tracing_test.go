@@ -0,0 +1,93 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/test"
+)
+
+func testInstance() *awsdbv1alpha1.RDSInstance {
+	return &awsdbv1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-instance"}}
+}
+
+func TestSetup(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	stop, err := Setup(Options{Exporter: ExporterNone})
+	g.Expect(err).NotTo(HaveOccurred())
+	stop()
+
+	_, err = Setup(Options{Exporter: "zipkin"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPropagation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	// test: objects without the annotation start new traces
+	instance := testInstance()
+	_, root := StartSpan(context.Background(), "root", instance)
+	g.Expect(instance.GetAnnotations()).NotTo(HaveKey(AnnotationSpanContext))
+
+	// test: objects created by the client carry the span context of its context
+	var created runtime.Object
+	mc := test.NewMockClient()
+	mc.MockCreate = func(_ context.Context, obj runtime.Object) error {
+		created = obj
+		return nil
+	}
+	ctx := trace.ContextWithSpan(context.Background(), root)
+	g.Expect(NewClient(ctx, mc).Create(context.Background(), instance)).To(Succeed())
+	g.Expect(created).To(Equal(instance))
+	g.Expect(instance.GetAnnotations()).To(HaveKey(AnnotationSpanContext))
+
+	// test: spans of annotated objects continue the trace
+	_, child := StartSpan(context.Background(), "child", instance)
+	g.Expect(child.SpanContext().TraceID()).To(Equal(root.SpanContext().TraceID()))
+
+	// test: spans with a parent in their context ignore the annotation
+	_, other := Tracer().Start(context.Background(), "other")
+	_, child = StartSpan(trace.ContextWithSpan(context.Background(), other), "child", instance)
+	g.Expect(child.SpanContext().TraceID()).To(Equal(other.SpanContext().TraceID()))
+
+	// test: forgotten objects start new traces
+	Forget(instance)
+	g.Expect(instance.GetAnnotations()).NotTo(HaveKey(AnnotationSpanContext))
+	_, child = StartSpan(context.Background(), "child", instance)
+	g.Expect(child.SpanContext().TraceID()).NotTo(Equal(root.SpanContext().TraceID()))
+}
+
+func TestProvider(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(provider(awsdbv1alpha1.Group)).To(Equal("aws"))
+	g.Expect(provider("cache.gcp.crossplane.io")).To(Equal("gcp"))
+	g.Expect(provider("storage.crossplane.io")).To(BeEmpty())
+	g.Expect(provider("")).To(BeEmpty())
+}
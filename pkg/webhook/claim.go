@@ -0,0 +1,200 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	cachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/cache/v1alpha1"
+	computev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/compute/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+	"github.com/crossplaneio/crossplane/pkg/controller/cache/redis"
+	"github.com/crossplaneio/crossplane/pkg/controller/compute/kubernetes"
+	"github.com/crossplaneio/crossplane/pkg/controller/core"
+	"github.com/crossplaneio/crossplane/pkg/controller/storage/bucket"
+	"github.com/crossplaneio/crossplane/pkg/controller/storage/sql"
+	"github.com/crossplaneio/crossplane/pkg/util"
+)
+
+var claimRule = admissionregistrationv1beta1.Rule{
+	APIGroups:   []string{cachev1alpha1.Group, computev1alpha1.Group, storagev1alpha1.Group},
+	APIVersions: []string{"v1alpha1"},
+	Resources:   []string{"buckets", "kubernetesclusters", "mysqlinstances", "postgresqlinstances", "redisclusters"},
+}
+
+var (
+	predefinedACLs = map[storagev1alpha1.PredefinedACL]bool{
+		storagev1alpha1.ACLPrivate:           true,
+		storagev1alpha1.ACLPublicRead:        true,
+		storagev1alpha1.ACLPublicReadWrite:   true,
+		storagev1alpha1.ACLAuthenticatedRead: true,
+	}
+	localPermissions = map[storagev1alpha1.LocalPermissionType]bool{
+		storagev1alpha1.ReadOnlyPermission:  true,
+		storagev1alpha1.WriteOnlyPermission: true,
+		storagev1alpha1.ReadWritePermission: true,
+	}
+)
+
+// claimValidator rejects invalid resource claims, including claims whose
+// values are not allowed by the constraints of their resource class.
+type claimValidator struct {
+	client.Client
+	scheme *runtime.Scheme
+}
+
+func (h *claimValidator) Handle(ctx context.Context, req types.Request) types.Response {
+	obj, err := decode(h.scheme, req)
+	if err != nil {
+		return errorResponse(err)
+	}
+	claim, ok := obj.(corev1alpha1.ResourceClaim)
+	if !ok {
+		return validationResponse(nil)
+	}
+
+	// the values of an existing claim were allowed when they were last
+	// changed, and must not be rejected because its class has since been
+	// tightened, or the claim controller could not update the claim
+	if req.AdmissionRequest.Operation == admissionv1beta1.Update {
+		old, err := decodeOld(h.scheme, req)
+		if err != nil {
+			return errorResponse(err)
+		}
+		if o, ok := old.(corev1alpha1.ResourceClaim); ok && reflect.DeepEqual(claimValues(o), claimValues(claim)) {
+			return validationResponse(validateClaim(claim, nil))
+		}
+	}
+
+	// a class that does not exist yet is reported by the claim controller, and
+	// a claim that does not reference a class is checked against the default
+	// class of its kind, if any
+	var class *corev1alpha1.ResourceClass
+	if ref := claim.ClassRef(); ref != nil {
+		class = &corev1alpha1.ResourceClass{}
+		if err := h.Get(ctx, util.NamespaceNameFromObjectRef(ref), class); err != nil {
+			if !errors.IsNotFound(err) {
+				return errorResponse(err)
+			}
+			class = nil
+		}
+	} else if class, err = core.DefaultResourceClass(ctx, h.Client, claim); err != nil {
+		return errorResponse(err)
+	}
+
+	return validationResponse(validateClaim(claim, class))
+}
+
+// validateClaim returns an error if the supplied claim is invalid, or if it
+// requests values that are not allowed by the supplied resource class. The
+// class may be nil.
+func validateClaim(claim corev1alpha1.ResourceClaim, class *corev1alpha1.ResourceClass) error {
+	if _, err := metav1.LabelSelectorAsSelector(claim.Selector()); err != nil {
+		return fmt.Errorf("invalid selector: %s", err)
+	}
+	if err := core.ValidateSecretTemplate(claim.SecretTemplate()); err != nil {
+		return fmt.Errorf("invalid secret template: %s", err)
+	}
+
+	if b, ok := claim.(*storagev1alpha1.Bucket); ok {
+		if acl := b.Spec.PredefinedACL; acl != nil && !predefinedACLs[*acl] {
+			return fmt.Errorf("unknown predefined ACL %s", *acl)
+		}
+		if perm := b.Spec.LocalPermission; perm != nil && !localPermissions[*perm] {
+			return fmt.Errorf("unknown local permission %s", *perm)
+		}
+	}
+
+	if class == nil {
+		return nil
+	}
+	if class.Provisioner == "" {
+		return fmt.Errorf("resource class %s/%s has no provisioner", class.Namespace, class.Name)
+	}
+
+	values := claimValues(claim)
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	policy := core.NewConstraintPolicy(class)
+	for _, field := range fields {
+		if err := policy.Check(field, values[field]); err != nil {
+			return err
+		}
+	}
+
+	// unconstrained engine versions must prefix the engine version of RDS classes
+	if strings.EqualFold(class.Provisioner, awsdbv1alpha1.RDSInstanceKindAPIVersion) && !policy.Constrained(sql.EngineVersionField) {
+		if _, err := sql.ValidateEngineVersion(class.Parameters[sql.EngineVersionField], values[sql.EngineVersionField]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimValues returns the values of the supplied claim that may be constrained
+// by its resource class, keyed by claim field.
+func claimValues(claim corev1alpha1.ResourceClaim) map[string]string {
+	switch c := claim.(type) {
+	case *storagev1alpha1.MySQLInstance:
+		return sqlClaimValues(c.Spec.EngineVersion, c.Spec.StorageSize)
+	case *storagev1alpha1.PostgreSQLInstance:
+		return sqlClaimValues(c.Spec.EngineVersion, c.Spec.StorageSize)
+	case *cachev1alpha1.RedisCluster:
+		return map[string]string{redis.EngineVersionField: c.Spec.EngineVersion}
+	case *computev1alpha1.KubernetesCluster:
+		return map[string]string{kubernetes.ClusterVersionField: c.Spec.ClusterVersion}
+	case *storagev1alpha1.Bucket:
+		values := map[string]string{bucket.NameField: c.Spec.Name}
+		if c.Spec.PredefinedACL != nil {
+			values[bucket.PredefinedACLField] = string(*c.Spec.PredefinedACL)
+		}
+		if c.Spec.LocalPermission != nil {
+			values[bucket.LocalPermissionField] = string(*c.Spec.LocalPermission)
+		}
+		return values
+	}
+	return nil
+}
+
+// sqlClaimValues returns the values of a SQL database claim that may be
+// constrained by its resource class, keyed by claim field.
+func sqlClaimValues(engineVersion string, storageSize int64) map[string]string {
+	values := map[string]string{sql.EngineVersionField: engineVersion}
+	if storageSize != 0 {
+		values[sql.StorageSizeField] = strconv.FormatInt(storageSize, 10)
+	}
+	return values
+}
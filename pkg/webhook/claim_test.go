@@ -0,0 +1,195 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	storagev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/storage/v1alpha1"
+)
+
+func TestValidateClaim(t *testing.T) {
+	rds := &corev1alpha1.ResourceClass{
+		Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+		Parameters:  map[string]string{"engineVersion": "5.6.45"},
+	}
+	constrained := &corev1alpha1.ResourceClass{
+		Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+		Parameters:  map[string]string{"engineVersion": "5.6.45"},
+		Constraints: map[string]corev1alpha1.ParameterConstraint{"engineVersion": {Versions: []string{"5.7.x"}}},
+	}
+	mysql := func(version string) *storagev1alpha1.MySQLInstance {
+		return &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{EngineVersion: version}}
+	}
+	min, max := int64(20), int64(100)
+	sized := &corev1alpha1.ResourceClass{
+		Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+		Parameters:  map[string]string{"size": "50"},
+		Constraints: map[string]corev1alpha1.ParameterConstraint{"storageSize": {Minimum: &min, Maximum: &max}},
+	}
+	postgres := func(size int64) *storagev1alpha1.PostgreSQLInstance {
+		return &storagev1alpha1.PostgreSQLInstance{Spec: storagev1alpha1.PostgreSQLInstanceSpec{StorageSize: size}}
+	}
+	acl := storagev1alpha1.PredefinedACL("Secret")
+
+	cases := []struct {
+		name    string
+		claim   corev1alpha1.ResourceClaim
+		class   *corev1alpha1.ResourceClass
+		wantErr bool
+	}{
+		{name: "NoClass", claim: mysql("5.7")},
+		{name: "MatchingEngineVersion", claim: mysql("5.6"), class: rds},
+		{name: "MismatchedEngineVersion", claim: mysql("5.7"), class: rds, wantErr: true},
+		{name: "ConstrainedEngineVersion", claim: mysql("5.7.21"), class: constrained},
+		{name: "DisallowedEngineVersion", claim: mysql("5.6"), class: constrained, wantErr: true},
+		{name: "StorageSizeAtMinimum", claim: postgres(20), class: sized},
+		{name: "StorageSizeAtMaximum", claim: postgres(100), class: sized},
+		{name: "StorageSizeBelowMinimum", claim: postgres(19), class: sized, wantErr: true},
+		{name: "StorageSizeAboveMaximum", claim: postgres(101), class: sized, wantErr: true},
+		{name: "ClassWithoutProvisioner", claim: mysql(""), class: &corev1alpha1.ResourceClass{}, wantErr: true},
+		{
+			name: "InvalidSelector",
+			claim: &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{Selector: metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Near"}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "InvalidSecretTemplate",
+			claim: &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{
+				SecretTemplate: &corev1alpha1.SecretTemplate{Templates: map[string]string{"url": "{{ .endpoint"}},
+			}},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownPredefinedACL",
+			claim:   &storagev1alpha1.Bucket{Spec: storagev1alpha1.BucketSpec{PredefinedACL: &acl}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateClaim(tc.claim, tc.class)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+// classClient gets a resource class.
+type classClient struct {
+	client.Client
+	class *corev1alpha1.ResourceClass
+}
+
+func (c *classClient) Get(_ context.Context, _ client.ObjectKey, obj runtime.Object) error {
+	c.class.DeepCopyInto(obj.(*corev1alpha1.ResourceClass))
+	return nil
+}
+
+func (c *classClient) List(_ context.Context, _ *client.ListOptions, list runtime.Object) error {
+	list.(*corev1alpha1.ResourceClassList).Items = []corev1alpha1.ResourceClass{*c.class.DeepCopy()}
+	return nil
+}
+
+func TestClaimValidatorDefaultClass(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	class := &corev1alpha1.ResourceClass{
+		ObjectMeta:  metav1.ObjectMeta{Namespace: "default", Name: "default-mysql"},
+		Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+		Constraints: map[string]corev1alpha1.ParameterConstraint{"engineVersion": {Versions: []string{"5.7.x"}}},
+	}
+	h := &claimValidator{Client: &classClient{class: class}, scheme: testScheme(t)}
+	raw, _ := json.Marshal(&storagev1alpha1.MySQLInstance{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "unclassed"},
+		Spec:       storagev1alpha1.MySQLInstanceSpec{EngineVersion: "5.6"},
+	})
+	req := types.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+		Operation: admissionv1beta1.Create,
+		Kind:      metav1.GroupVersionKind{Group: storagev1alpha1.Group, Version: storagev1alpha1.Version, Kind: "MySQLInstance"},
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+
+	// test: there is no default class, and the claim is not checked
+	rsp := h.Handle(context.Background(), req)
+	g.Expect(rsp.Response.Allowed).To(BeTrue())
+
+	// test: a claim without a class is checked against the constraints of
+	// the default class of its kind
+	class.SetAnnotations(map[string]string{corev1alpha1.AnnotationDefaultClassFor: "mysqlinstance.storage.crossplane.io/v1alpha1"})
+	rsp = h.Handle(context.Background(), req)
+	g.Expect(rsp.Response.Allowed).To(BeFalse())
+	g.Expect(string(rsp.Response.Result.Reason)).To(ContainSubstring("5.7.x"))
+}
+
+func TestClaimValidatorUpdate(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tightened := &corev1alpha1.ResourceClass{
+		Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+		Constraints: map[string]corev1alpha1.ParameterConstraint{"engineVersion": {Versions: []string{"5.7.x"}}},
+	}
+	h := &claimValidator{Client: &classClient{class: tightened}, scheme: testScheme(t)}
+	mysql := func(version string) runtime.RawExtension {
+		claim := &storagev1alpha1.MySQLInstance{Spec: storagev1alpha1.MySQLInstanceSpec{
+			ClassRef:      &corev1.ObjectReference{Namespace: "default", Name: "tightened"},
+			EngineVersion: version,
+		}}
+		raw, _ := json.Marshal(claim)
+		return runtime.RawExtension{Raw: raw}
+	}
+	request := func(op admissionv1beta1.Operation, old, claim runtime.RawExtension) types.Request {
+		return types.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Kind:      metav1.GroupVersionKind{Group: storagev1alpha1.Group, Version: storagev1alpha1.Version, Kind: "MySQLInstance"},
+			Object:    claim,
+			OldObject: old,
+		}}
+	}
+
+	// test: a new claim is checked against the constraints of its class
+	rsp := h.Handle(context.Background(), request(admissionv1beta1.Create, runtime.RawExtension{}, mysql("5.6")))
+	g.Expect(rsp.Response.Allowed).To(BeFalse())
+
+	// test: an existing claim whose values do not change is not checked
+	// against the constraints of its class, which were since tightened
+	rsp = h.Handle(context.Background(), request(admissionv1beta1.Update, mysql("5.6"), mysql("5.6")))
+	g.Expect(rsp.Response.Allowed).To(BeTrue())
+
+	// test: an existing claim whose values change is checked
+	rsp = h.Handle(context.Background(), request(admissionv1beta1.Update, mysql("5.6"), mysql("5.6.40")))
+	g.Expect(rsp.Response.Allowed).To(BeFalse())
+}
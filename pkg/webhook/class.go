@@ -0,0 +1,326 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	awscachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	awsstoragev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
+	azurecachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
+	azurecomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
+	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpcachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
+	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+)
+
+// Types of class parameters that are parsed by provisioners.
+const (
+	integerParameter = "integer"
+	booleanParameter = "boolean"
+)
+
+var classRule = admissionregistrationv1beta1.Rule{
+	APIGroups:   []string{corev1alpha1.Group},
+	APIVersions: []string{corev1alpha1.Version},
+	Resources:   []string{"resourceclasses"},
+}
+
+// parameterParsers parse the class parameters into the spec of the resources
+// provisioned by the provisioners of this repository, keyed by provisioner.
+// They are the parsers the provisioners use, and ignore parameters they cannot
+// parse.
+var parameterParsers = map[string]func(map[string]string) interface{}{
+	awsdbv1alpha1.RDSInstanceKindAPIVersion: func(p map[string]string) interface{} {
+		return awsdbv1alpha1.NewRDSInstanceSpec(p)
+	},
+	azuredbv1alpha1.MysqlServerKindAPIVersion: func(p map[string]string) interface{} {
+		return azuredbv1alpha1.NewSQLServerSpec(p)
+	},
+	azuredbv1alpha1.PostgresqlServerKindAPIVersion: func(p map[string]string) interface{} {
+		return azuredbv1alpha1.NewSQLServerSpec(p)
+	},
+	gcpdbv1alpha1.CloudsqlInstanceKindAPIVersion: func(p map[string]string) interface{} {
+		return gcpdbv1alpha1.NewCloudSQLInstanceSpec(p)
+	},
+	awsstoragev1alpha1.S3BucketKindAPIVersion: func(p map[string]string) interface{} {
+		return awsstoragev1alpha1.NewS3BucketSpec(p)
+	},
+	awscomputev1alpha1.EKSClusterKindAPIVersion: func(p map[string]string) interface{} {
+		return awscomputev1alpha1.NewEKSClusterSpec(p)
+	},
+	azurecomputev1alpha1.AKSClusterKindAPIVersion: func(p map[string]string) interface{} {
+		return azurecomputev1alpha1.NewAKSClusterSpec(p)
+	},
+	gcpcomputev1alpha1.GKEClusterKindAPIVersion: func(p map[string]string) interface{} {
+		return gcpcomputev1alpha1.NewGKEClusterSpec(p)
+	},
+	awscachev1alpha1.ReplicationGroupKindAPIVersion: func(p map[string]string) interface{} {
+		return awscachev1alpha1.NewReplicationGroupSpec(p)
+	},
+	azurecachev1alpha1.RedisKindAPIVersion: func(p map[string]string) interface{} {
+		return azurecachev1alpha1.NewRedisSpec(p)
+	},
+	gcpcachev1alpha1.CloudMemorystoreInstanceKindAPIVersion: func(p map[string]string) interface{} {
+		return gcpcachev1alpha1.NewCloudMemorystoreInstanceSpec(p)
+	},
+}
+
+// snapshotProvisioners are the provisioners of this repository whose
+// resources take a final snapshot when they are deleted under the Snapshot
+// reclaim policy. The resources of other provisioners of this repository
+// would be orphaned instead.
+var snapshotProvisioners = map[string]bool{
+	awsdbv1alpha1.RDSInstanceKindAPIVersion:         true,
+	awscachev1alpha1.ReplicationGroupKindAPIVersion: true,
+	azuredbv1alpha1.MysqlServerKindAPIVersion:       true,
+	azuredbv1alpha1.PostgresqlServerKindAPIVersion:  true,
+	gcpdbv1alpha1.CloudsqlInstanceKindAPIVersion:    true,
+	corev1alpha1.ExternalResourceKindAPIVersion:     true,
+}
+
+// classDefaulter defaults the fields of resource classes.
+type classDefaulter struct{}
+
+func (h *classDefaulter) Handle(_ context.Context, req types.Request) types.Response {
+	class := &corev1alpha1.ResourceClass{}
+	if err := decodeInto(req, class); err != nil {
+		return errorResponse(err)
+	}
+	defaulted := class.DeepCopy()
+	defaultClass(defaulted)
+	return admission.PatchResponse(class, defaulted)
+}
+
+// defaultClass sets the reclaim policy of the supplied class to Retain, the
+// documented default, if it is not set.
+func defaultClass(class *corev1alpha1.ResourceClass) {
+	if class.ReclaimPolicy == "" {
+		class.ReclaimPolicy = corev1alpha1.ReclaimRetain
+	}
+}
+
+// classValidator rejects invalid resource classes.
+type classValidator struct {
+	scheme *runtime.Scheme
+}
+
+func (h *classValidator) Handle(_ context.Context, req types.Request) types.Response {
+	class := &corev1alpha1.ResourceClass{}
+	if err := decodeInto(req, class); err != nil {
+		return errorResponse(err)
+	}
+	return validationResponse(validateClass(h.scheme, class))
+}
+
+// validateClass returns an error if the supplied resource class is invalid.
+// Provisioners must be of the form kind.group/version. Provisioners of an API
+// group version served by this repository must be known kinds, while those of
+// other API groups are left to external provisioners.
+func validateClass(scheme *runtime.Scheme, class *corev1alpha1.ResourceClass) error {
+	if err := validateProvisioner(scheme, class.Provisioner); err != nil {
+		return err
+	}
+	if err := validateReclaimPolicy(scheme, class.Provisioner, class.ReclaimPolicy); err != nil {
+		return err
+	}
+	if err := validateParameters(class.Provisioner, class.Parameters); err != nil {
+		return err
+	}
+
+	fields := make([]string, 0, len(class.Constraints))
+	for field := range class.Constraints {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if err := validateConstraint(class.Constraints[field]); err != nil {
+			return fmt.Errorf("invalid constraint for %s: %s", field, err)
+		}
+	}
+
+	if class.Pool != nil && class.Pool.ClaimKind == "" {
+		return fmt.Errorf("pool must specify a claim kind")
+	}
+	if p := class.ExternalProvisioner; p != nil {
+		if err := validateExternalProvisioner(scheme, class.Provisioner, p); err != nil {
+			return fmt.Errorf("invalid external provisioner: %s", err)
+		}
+	}
+	return nil
+}
+
+// validateExternalProvisioner returns an error if the supplied provisioner
+// cannot be provisioned by the supplied external provisioner.
+func validateExternalProvisioner(scheme *runtime.Scheme, provisioner string, p *corev1alpha1.ExternalProvisioner) error {
+	if builtIn(scheme, provisioner) {
+		return fmt.Errorf("provisioner %s is built into Crossplane", provisioner)
+	}
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL %s is not an absolute http or https URL", p.URL)
+	}
+	return nil
+}
+
+func validateProvisioner(scheme *runtime.Scheme, provisioner string) error {
+	if provisioner == "" {
+		return fmt.Errorf("provisioner may not be empty")
+	}
+
+	parts := strings.SplitN(provisioner, ".", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("provisioner %s is not of the form kind.group/version", provisioner)
+	}
+	gv, err := schema.ParseGroupVersion(parts[1])
+	if err != nil || gv.Group == "" || gv.Version == "" {
+		return fmt.Errorf("provisioner %s is not of the form kind.group/version", provisioner)
+	}
+
+	if !scheme.IsVersionRegistered(gv) {
+		return nil
+	}
+	for gvk := range scheme.AllKnownTypes() {
+		if gvk.GroupVersion() == gv && strings.EqualFold(gvk.Kind, parts[0]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown provisioner %s", provisioner)
+}
+
+// validateReclaimPolicy returns an error if the supplied reclaim policy is
+// unknown, or if it is Snapshot and the supplied provisioner of this
+// repository does not snapshot its resources. External provisioners may
+// snapshot theirs.
+func validateReclaimPolicy(scheme *runtime.Scheme, provisioner string, p corev1alpha1.ReclaimPolicy) error {
+	switch p {
+	case "", corev1alpha1.ReclaimDelete, corev1alpha1.ReclaimRetain:
+		return nil
+	case corev1alpha1.ReclaimSnapshot:
+		if !builtIn(scheme, provisioner) {
+			return nil
+		}
+		for sp := range snapshotProvisioners {
+			if strings.EqualFold(sp, provisioner) {
+				return nil
+			}
+		}
+		return fmt.Errorf("provisioner %s does not support reclaim policy %s", provisioner, p)
+	}
+	return fmt.Errorf("unknown reclaim policy %s", p)
+}
+
+// builtIn returns true if the supplied provisioner is of an API group version
+// served by this repository.
+func builtIn(scheme *runtime.Scheme, provisioner string) bool {
+	parts := strings.SplitN(provisioner, ".", 2)
+	gv, err := schema.ParseGroupVersion(parts[len(parts)-1])
+	return err == nil && scheme.IsVersionRegistered(gv)
+}
+
+// validateParameters returns an error if the parser of the supplied
+// provisioner would ignore any of the supplied parameters because it cannot
+// parse them.
+func validateParameters(provisioner string, params map[string]string) error {
+	var parse func(map[string]string) interface{}
+	for p, fn := range parameterParsers {
+		if strings.EqualFold(p, provisioner) {
+			parse = fn
+		}
+	}
+	if parse == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// A parameter that changes the parsed spec was parsed. One that
+		// does not was either ignored or parsed into the default value.
+		if parses(parse, k, params[k]) {
+			continue
+		}
+		var err error
+		t := parameterType(parse, k)
+		switch t {
+		case integerParameter:
+			_, err = strconv.Atoi(params[k])
+		case booleanParameter:
+			_, err = strconv.ParseBool(params[k])
+		}
+		if err != nil {
+			return fmt.Errorf("parameter %s is not of type %s: %s", k, t, params[k])
+		}
+	}
+	return nil
+}
+
+// parameterType returns the type the supplied parser parses the supplied
+// parameter as, or an empty string if it takes any string or ignores it.
+func parameterType(parse func(map[string]string) interface{}, k string) string {
+	switch {
+	case parses(parse, k, "-"):
+		return ""
+	case parses(parse, k, "2"):
+		return integerParameter
+	case parses(parse, k, "true"):
+		return booleanParameter
+	}
+	return ""
+}
+
+// parses returns true if the supplied parameter value changes the spec
+// returned by the supplied parser.
+func parses(parse func(map[string]string) interface{}, k, v string) bool {
+	return !reflect.DeepEqual(parse(map[string]string{k: v}), parse(map[string]string{}))
+}
+
+func validateConstraint(c corev1alpha1.ParameterConstraint) error {
+	if c.Pattern != "" {
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern %s: %s", c.Pattern, err)
+		}
+	}
+	if c.Minimum != nil && c.Maximum != nil && *c.Minimum > *c.Maximum {
+		return fmt.Errorf("minimum %d is greater than maximum %d", *c.Minimum, *c.Maximum)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplaneio/crossplane/pkg/apis"
+	awscachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	awsstoragev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+func testScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestValidateClass(t *testing.T) {
+	scheme := testScheme(t)
+	min, max := int64(10), int64(5)
+
+	cases := []struct {
+		name    string
+		class   *corev1alpha1.ResourceClass
+		wantErr bool
+	}{
+		{
+			name: "Valid",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner:   awsdbv1alpha1.RDSInstanceKindAPIVersion,
+				Parameters:    map[string]string{"size": "20", "class": "db.t2.small"},
+				ReclaimPolicy: corev1alpha1.ReclaimDelete,
+			},
+		},
+		{
+			name:  "ExternalProvisioner",
+			class: &corev1alpha1.ResourceClass{Provisioner: "mysql.database.example.org/v1"},
+		},
+		{
+			name: "ExternalProvisionerURL",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner:         "mysql.database.example.org/v1",
+				ExternalProvisioner: &corev1alpha1.ExternalProvisioner{URL: "https://mysql-provisioner.infra.svc"},
+			},
+		},
+		{
+			name: "RelativeExternalProvisionerURL",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner:         "mysql.database.example.org/v1",
+				ExternalProvisioner: &corev1alpha1.ExternalProvisioner{URL: "mysql-provisioner.infra.svc"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "BuiltInExternalProvisioner",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner:         awsdbv1alpha1.RDSInstanceKindAPIVersion,
+				ExternalProvisioner: &corev1alpha1.ExternalProvisioner{URL: "https://rds-provisioner.infra.svc"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "EmptyProvisioner",
+			class:   &corev1alpha1.ResourceClass{},
+			wantErr: true,
+		},
+		{
+			name:    "MalformedProvisioner",
+			class:   &corev1alpha1.ResourceClass{Provisioner: "rdsinstance"},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownProvisioner",
+			class:   &corev1alpha1.ResourceClass{Provisioner: "rdsinstanse.database.aws.crossplane.io/v1alpha1"},
+			wantErr: true,
+		},
+		{
+			name:    "UnknownReclaimPolicy",
+			class:   &corev1alpha1.ResourceClass{Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion, ReclaimPolicy: "Keep"},
+			wantErr: true,
+		},
+		{
+			name: "UnparsableParameter",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+				Parameters:  map[string]string{"size": "20GB"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "UnparsableBooleanParameter",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awscachev1alpha1.ReplicationGroupKindAPIVersion,
+				Parameters:  map[string]string{"authEnabled": "yes"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "DefaultValuedParameter",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awscachev1alpha1.ReplicationGroupKindAPIVersion,
+				Parameters:  map[string]string{"authEnabled": "false"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "UnparsablePointerParameter",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awscomputev1alpha1.EKSClusterKindAPIVersion,
+				Parameters:  map[string]string{"workerNodeVolumeSize": "large"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "InvalidConstraint",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+				Constraints: map[string]corev1alpha1.ParameterConstraint{"engineVersion": {Pattern: "5.["}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "MinimumGreaterThanMaximum",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+				Constraints: map[string]corev1alpha1.ParameterConstraint{"storageSize": {Minimum: &min, Maximum: &max}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "UnsupportedSnapshot",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner:   awsstoragev1alpha1.S3BucketKindAPIVersion,
+				ReclaimPolicy: corev1alpha1.ReclaimSnapshot,
+			},
+			wantErr: true,
+		},
+		{
+			name: "ExternalProvisionerSnapshot",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner:   "mysql.database.example.org/v1",
+				ReclaimPolicy: corev1alpha1.ReclaimSnapshot,
+			},
+		},
+		{
+			name: "PoolWithoutClaimKind",
+			class: &corev1alpha1.ResourceClass{
+				Provisioner: awsdbv1alpha1.RDSInstanceKindAPIVersion,
+				Pool:        &corev1alpha1.ResourcePool{Size: 1},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			err := validateClass(scheme, tc.class)
+			if tc.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestDefaultClass(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	class := &corev1alpha1.ResourceClass{}
+	defaultClass(class)
+	g.Expect(class.ReclaimPolicy).To(Equal(corev1alpha1.ReclaimRetain))
+
+	class = &corev1alpha1.ResourceClass{ReclaimPolicy: corev1alpha1.ReclaimDelete}
+	defaultClass(class)
+	g.Expect(class.ReclaimPolicy).To(Equal(corev1alpha1.ReclaimDelete))
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	awscachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/cache/v1alpha1"
+	awscomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/compute/v1alpha1"
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	awsstoragev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
+	azurecachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/cache/v1alpha1"
+	azurecomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/compute/v1alpha1"
+	azuredbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/azure/database/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+	gcpcachev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/cache/v1alpha1"
+	gcpcomputev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/compute/v1alpha1"
+	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+)
+
+var resourceRule = admissionregistrationv1beta1.Rule{
+	APIGroups: []string{
+		awscachev1alpha1.Group,
+		awscomputev1alpha1.Group,
+		awsdbv1alpha1.Group,
+		awsstoragev1alpha1.Group,
+		azurecachev1alpha1.Group,
+		azurecomputev1alpha1.Group,
+		azuredbv1alpha1.Group,
+		gcpcachev1alpha1.Group,
+		gcpcomputev1alpha1.Group,
+		gcpdbv1alpha1.Group,
+	},
+	APIVersions: []string{"v1alpha1"},
+	Resources:   []string{"*"},
+}
+
+var cannedACLs = map[s3.BucketCannedACL]bool{
+	s3.BucketCannedACLPrivate:           true,
+	s3.BucketCannedACLPublicRead:        true,
+	s3.BucketCannedACLPublicReadWrite:   true,
+	s3.BucketCannedACLAuthenticatedRead: true,
+}
+
+// resourceDefaulter defaults the fields of managed resources.
+type resourceDefaulter struct {
+	scheme *runtime.Scheme
+}
+
+func (h *resourceDefaulter) Handle(_ context.Context, req types.Request) types.Response {
+	obj, err := decode(h.scheme, req)
+	if err != nil {
+		return errorResponse(err)
+	}
+	if _, ok := obj.(corev1alpha1.Resource); !ok {
+		return admission.ValidationResponse(true, "")
+	}
+
+	// the resource is patched as unstructured content so that fields unknown
+	// to this version of the API are preserved
+	original := &unstructured.Unstructured{}
+	if err := decodeInto(req, &original.Object); err != nil {
+		return errorResponse(err)
+	}
+	defaulted := original.DeepCopy()
+	defaultResource(defaulted)
+	return admission.PatchResponse(original, defaulted)
+}
+
+// defaultResource sets the reclaim policy of the supplied managed resource to
+// Retain, the documented default, if it is not set.
+func defaultResource(res *unstructured.Unstructured) {
+	policy, _, _ := unstructured.NestedString(res.Object, "spec", "reclaimPolicy")
+	if policy == "" {
+		_ = unstructured.SetNestedField(res.Object, string(corev1alpha1.ReclaimRetain), "spec", "reclaimPolicy")
+	}
+}
+
+// resourceValidator rejects invalid managed resources.
+type resourceValidator struct {
+	scheme *runtime.Scheme
+}
+
+func (h *resourceValidator) Handle(_ context.Context, req types.Request) types.Response {
+	obj, err := decode(h.scheme, req)
+	if err != nil {
+		return errorResponse(err)
+	}
+	res, ok := obj.(corev1alpha1.Resource)
+	if !ok {
+		return validationResponse(nil)
+	}
+
+	// a resource that is being deleted must not be kept from being finalized
+	if m, err := meta.Accessor(obj); err == nil && m.GetDeletionTimestamp() != nil {
+		return validationResponse(nil)
+	}
+
+	var old corev1alpha1.Resource
+	if req.AdmissionRequest.Operation == admissionv1beta1.Update {
+		o, err := decodeOld(h.scheme, req)
+		if err != nil {
+			return errorResponse(err)
+		}
+		old, _ = o.(corev1alpha1.Resource)
+	}
+	return validationResponse(validateResource(h.scheme, res, old))
+}
+
+// validateResource returns an error if the supplied managed resource is
+// invalid. The reclaim policy of an updated resource is only validated if it
+// differs from that of the supplied old resource, which may be nil, so that
+// resources created before a policy was rejected can still be updated.
+func validateResource(scheme *runtime.Scheme, res, old corev1alpha1.Resource) error {
+	gvks, _, err := scheme.ObjectKinds(res)
+	if err != nil {
+		return err
+	}
+	provisioner := gvks[0].Kind + "." + gvks[0].GroupVersion().String()
+	if old == nil || old.ReclaimPolicy() != res.ReclaimPolicy() {
+		if err := validateReclaimPolicy(scheme, provisioner, res.ReclaimPolicy()); err != nil {
+			return err
+		}
+	}
+
+	switch r := res.(type) {
+	case *awsstoragev1alpha1.S3Bucket:
+		if acl := r.Spec.CannedACL; acl != nil && !cannedACLs[*acl] {
+			return fmt.Errorf("unknown canned ACL %s", *acl)
+		}
+		if perm := r.Spec.LocalPermission; perm != nil && !localPermissions[*perm] {
+			return fmt.Errorf("unknown local permission %s", *perm)
+		}
+	case corev1alpha1.StorageResource:
+		if r.StorageGB() < 0 {
+			return fmt.Errorf("storage may not be negative")
+		}
+	}
+	return nil
+}
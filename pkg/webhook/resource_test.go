@@ -0,0 +1,113 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	. "github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	awsstoragev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/storage/v1alpha1"
+	corev1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/core/v1alpha1"
+)
+
+func TestValidateResource(t *testing.T) {
+	g := NewGomegaWithT(t)
+	scheme := testScheme(t)
+
+	rds := &awsdbv1alpha1.RDSInstance{Spec: awsdbv1alpha1.RDSInstanceSpec{Size: 20, ReclaimPolicy: corev1alpha1.ReclaimSnapshot}}
+	g.Expect(validateResource(scheme, rds, nil)).To(Succeed())
+
+	rds.Spec.ReclaimPolicy = "Keep"
+	g.Expect(validateResource(scheme, rds, nil)).NotTo(Succeed())
+
+	rds = &awsdbv1alpha1.RDSInstance{Spec: awsdbv1alpha1.RDSInstanceSpec{Size: -1}}
+	g.Expect(validateResource(scheme, rds, nil)).NotTo(Succeed())
+
+	acl := s3.BucketCannedACL("secret")
+	bucket := &awsstoragev1alpha1.S3Bucket{Spec: awsstoragev1alpha1.S3BucketSpec{CannedACL: &acl}}
+	g.Expect(validateResource(scheme, bucket, nil)).NotTo(Succeed())
+
+	acl = s3.BucketCannedACLPublicRead
+	g.Expect(validateResource(scheme, bucket, nil)).To(Succeed())
+
+	bucket.Spec.ReclaimPolicy = corev1alpha1.ReclaimSnapshot
+	g.Expect(validateResource(scheme, bucket, nil)).NotTo(Succeed())
+
+	// the unchanged reclaim policy of an updated resource is not validated
+	g.Expect(validateResource(scheme, bucket, bucket.DeepCopy())).To(Succeed())
+
+	old := bucket.DeepCopy()
+	old.Spec.ReclaimPolicy = corev1alpha1.ReclaimDelete
+	g.Expect(validateResource(scheme, bucket, old)).NotTo(Succeed())
+}
+
+func TestResourceValidator(t *testing.T) {
+	g := NewGomegaWithT(t)
+	h := &resourceValidator{scheme: testScheme(t)}
+
+	snapshot := &awsstoragev1alpha1.S3Bucket{Spec: awsstoragev1alpha1.S3BucketSpec{ReclaimPolicy: corev1alpha1.ReclaimSnapshot}}
+	request := func(op admissionv1beta1.Operation, old, res *awsstoragev1alpha1.S3Bucket) types.Request {
+		raw, _ := json.Marshal(res)
+		oldRaw, _ := json.Marshal(old)
+		return types.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+			Operation: op,
+			Kind:      metav1.GroupVersionKind{Group: awsstoragev1alpha1.Group, Version: awsstoragev1alpha1.Version, Kind: awsstoragev1alpha1.S3BucketKind},
+			Object:    runtime.RawExtension{Raw: raw},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+		}}
+	}
+
+	// test: a new resource with an unsupported reclaim policy is rejected
+	rsp := h.Handle(context.Background(), request(admissionv1beta1.Create, nil, snapshot))
+	g.Expect(rsp.Response.Allowed).To(BeFalse())
+
+	// test: an existing resource whose reclaim policy does not change is allowed
+	rsp = h.Handle(context.Background(), request(admissionv1beta1.Update, snapshot, snapshot))
+	g.Expect(rsp.Response.Allowed).To(BeTrue())
+
+	// test: a resource that is being deleted is allowed
+	deleting := snapshot.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+	rsp = h.Handle(context.Background(), request(admissionv1beta1.Create, nil, deleting))
+	g.Expect(rsp.Response.Allowed).To(BeTrue())
+}
+
+func TestDefaultResource(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	res := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"size": int64(20)}}}
+	defaultResource(res)
+	g.Expect(res.Object).To(Equal(map[string]interface{}{"spec": map[string]interface{}{
+		"size":          int64(20),
+		"reclaimPolicy": "Retain",
+	}}))
+
+	res = &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"reclaimPolicy": "Delete"}}}
+	defaultResource(res)
+	g.Expect(res.Object).To(Equal(map[string]interface{}{"spec": map[string]interface{}{"reclaimPolicy": "Delete"}}))
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook serves the admission webhooks that validate and default
+// resource classes, resource claims and managed resources before they are
+// persisted.
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apitypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/builder"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+const (
+	serverName  = "crossplane-admission-server"
+	serviceName = "crossplane-webhook"
+	secretName  = "crossplane-webhook-cert"
+
+	mutatingConfigName   = "crossplane-mutating-webhooks"
+	validatingConfigName = "crossplane-validating-webhooks"
+)
+
+// Options configure the admission webhook server.
+type Options struct {
+	// Port the webhook server listens on.
+	Port int32
+
+	// CertDir is the directory the serving certificate is written to.
+	CertDir string
+
+	// Namespace of the service and secret that expose the webhook server.
+	Namespace string
+
+	// Selector of the pods backing the webhook service.
+	Selector map[string]string
+}
+
+// AddToManager creates the admission webhook server, registers the resource
+// class, resource claim and managed resource webhooks with it, and adds it to
+// the manager. The server bootstraps its own serving certificate, service and
+// webhook configurations.
+func AddToManager(mgr manager.Manager, o Options) error {
+	svr, err := webhook.NewServer(serverName, mgr, webhook.ServerOptions{
+		Port:    o.Port,
+		CertDir: o.CertDir,
+		BootstrapOptions: &webhook.BootstrapOptions{
+			MutatingWebhookConfigName:   mutatingConfigName,
+			ValidatingWebhookConfigName: validatingConfigName,
+			Secret:                      &apitypes.NamespacedName{Namespace: o.Namespace, Name: secretName},
+			Service: &webhook.Service{
+				Namespace: o.Namespace,
+				Name:      serviceName,
+				Selectors: o.Selector,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	scheme := mgr.GetScheme()
+	hooks := []struct {
+		name     string
+		mutating bool
+		rule     admissionregistrationv1beta1.Rule
+		handler  admission.Handler
+	}{
+		{"default.resourceclass.core.crossplane.io", true, classRule, &classDefaulter{}},
+		{"validate.resourceclass.core.crossplane.io", false, classRule, &classValidator{scheme: scheme}},
+		{"validate.claim.crossplane.io", false, claimRule, &claimValidator{Client: mgr.GetClient(), scheme: scheme}},
+		{"default.resource.crossplane.io", true, resourceRule, &resourceDefaulter{scheme: scheme}},
+		{"validate.resource.crossplane.io", false, resourceRule, &resourceValidator{scheme: scheme}},
+	}
+
+	for _, h := range hooks {
+		// objects of API groups that are not selected cannot be decoded, and
+		// must not be sent to the webhooks
+		rule := registered(scheme, h.rule)
+		if len(rule.APIGroups) == 0 {
+			continue
+		}
+		b := builder.NewWebhookBuilder().
+			Name(h.name).
+			Rules(admissionregistrationv1beta1.RuleWithOperations{
+				Operations: []admissionregistrationv1beta1.OperationType{
+					admissionregistrationv1beta1.Create,
+					admissionregistrationv1beta1.Update,
+				},
+				Rule: rule,
+			}).
+			Handlers(h.handler).
+			WithManager(mgr)
+		if h.mutating {
+			b = b.Mutating()
+		} else {
+			b = b.Validating()
+		}
+
+		wh, err := b.Build()
+		if err != nil {
+			return err
+		}
+		if err := svr.Register(wh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registered returns a copy of the supplied rule that matches only the API
+// groups of the supplied rule that are registered with the supplied scheme.
+func registered(scheme *runtime.Scheme, rule admissionregistrationv1beta1.Rule) admissionregistrationv1beta1.Rule {
+	groups := make([]string, 0, len(rule.APIGroups))
+	for _, g := range rule.APIGroups {
+		if scheme.IsGroupRegistered(g) {
+			groups = append(groups, g)
+		}
+	}
+	rule.APIGroups = groups
+	return rule
+}
+
+// decode the object of the supplied admission request into a new object of
+// the requested kind.
+func decode(scheme *runtime.Scheme, req types.Request) (runtime.Object, error) {
+	k := req.AdmissionRequest.Kind
+	obj, err := scheme.New(schema.GroupVersionKind{Group: k.Group, Version: k.Version, Kind: k.Kind})
+	if err != nil {
+		return nil, err
+	}
+	return obj, decodeInto(req, obj)
+}
+
+// decodeInto decodes the object of the supplied admission request into the
+// supplied object.
+func decodeInto(req types.Request, obj interface{}) error {
+	if err := json.Unmarshal(req.AdmissionRequest.Object.Raw, obj); err != nil {
+		return fmt.Errorf("cannot decode %s: %s", req.AdmissionRequest.Kind.Kind, err)
+	}
+	return nil
+}
+
+// decodeOld decodes the existing object of the supplied update request into a
+// new object of the requested kind.
+func decodeOld(scheme *runtime.Scheme, req types.Request) (runtime.Object, error) {
+	k := req.AdmissionRequest.Kind
+	obj, err := scheme.New(schema.GroupVersionKind{Group: k.Group, Version: k.Version, Kind: k.Kind})
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(req.AdmissionRequest.OldObject.Raw, obj); err != nil {
+		return nil, fmt.Errorf("cannot decode existing %s: %s", k.Kind, err)
+	}
+	return obj, nil
+}
+
+// validationResponse allows the request if the supplied error is nil, and
+// denies it with the error as the reason otherwise.
+func validationResponse(err error) types.Response {
+	if err != nil {
+		return admission.ValidationResponse(false, err.Error())
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+// errorResponse denies a request that could not be decoded.
+func errorResponse(err error) types.Response {
+	return admission.ErrorResponse(http.StatusBadRequest, err)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2018 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	awsdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/aws/database/v1alpha1"
+	gcpdbv1alpha1 "github.com/crossplaneio/crossplane/pkg/apis/gcp/database/v1alpha1"
+)
+
+func TestRegistered(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(awsdbv1alpha1.SchemeBuilder.AddToScheme(scheme)).To(Succeed())
+
+	// test: only the API groups registered with the scheme are matched
+	rule := registered(scheme, resourceRule)
+	g.Expect(rule.APIGroups).To(Equal([]string{awsdbv1alpha1.Group}))
+	g.Expect(rule.Resources).To(Equal(resourceRule.Resources))
+	g.Expect(resourceRule.APIGroups).To(ContainElement(gcpdbv1alpha1.Group))
+
+	// test: no API group is matched if none is registered
+	rule = registered(runtime.NewScheme(), admissionregistrationv1beta1.Rule{APIGroups: []string{gcpdbv1alpha1.Group}})
+	g.Expect(rule.APIGroups).To(BeEmpty())
+}